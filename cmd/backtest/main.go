@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	engine_types "github.com/rxtech-lab/argo-trading/internal/backtest/engine"
 	engine "github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1"
@@ -83,7 +84,8 @@ func main() {
 			progressBar = progressbar.New(info.Total)
 			progressBar.Add(info.Current)
 		}
-		progressBar.Describe(fmt.Sprintf("%.0f bars/s | realized PnL %.2f", info.BarsPerSecond, info.RealizedPnL))
+		progressBar.Describe(fmt.Sprintf("%.0f bars/s | ETA %s | realized PnL %.2f",
+			info.BarsPerSecond, info.Remaining.Round(time.Second), info.RealizedPnL))
 		progressBar.Add(1)
 		return nil
 	})
@@ -9,6 +9,7 @@ import (
 
 	"github.com/polygon-io/client-go/rest/models"
 	"github.com/rxtech-lab/argo-trading/pkg/marketdata"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
 	"github.com/schollz/progressbar/v3"
 	"github.com/urfave/cli/v3"
 )
@@ -23,6 +24,11 @@ func downloadAction(ctx context.Context, cmd *cli.Command) error {
 	providerFlag := cmd.String("provider")
 	writerFlag := cmd.String("writer")
 	dataPath := cmd.String("data")
+	csvPath := cmd.String("csv")
+	chunkDays := cmd.Int("chunk-days")
+	retries := cmd.Int("retries")
+	retryBackoff := cmd.Duration("retry-backoff")
+	validate := cmd.Bool("validate")
 
 	// Create client configuration
 	clientConfig := marketdata.ClientConfig{
@@ -30,17 +36,26 @@ func downloadAction(ctx context.Context, cmd *cli.Command) error {
 		WriterType:    marketdata.WriterType(writerFlag),
 		DataPath:      dataPath,
 		PolygonApiKey: os.Getenv("POLYGON_API_KEY"),
+		CSV:           nil,
+		ChunkDays:     chunkDays,
+		MaxRetries:    retries,
+		RetryBackoff:  retryBackoff,
+	}
+
+	if clientConfig.ProviderType == marketdata.ProviderCSV {
+		//nolint:exhaustruct // defaults detect the delimiter, header and columns
+		clientConfig.CSV = &provider.CSVConfig{FilePath: csvPath}
 	}
 
 	progressBar := progressbar.New(100)
 
 	// Create market data client
 	client, err := marketdata.NewClient(clientConfig, func(current float64, total float64, message string) {
+		// Chunked downloads report the number of completed chunks
 		if total != 0 {
-			progressBar.Set(int(total))
+			progressBar.ChangeMax(int(total))
+			progressBar.Set(int(current))
 		}
-
-		progressBar.Add(1)
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create market data client: %w", err)
@@ -65,9 +80,58 @@ func downloadAction(ctx context.Context, cmd *cli.Command) error {
 	}
 
 	log.Println("Download completed successfully.")
+
+	if validate {
+		report, err := client.Validate(downloadParams)
+		if err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+
+		printValidationReport(report)
+	}
+
 	return nil
 }
 
+// maxReportedIssues is the number of issues of each kind printed by printValidationReport.
+const maxReportedIssues = 10
+
+// printValidationReport logs a summary of a validation report and its first issues of each kind.
+func printValidationReport(report marketdata.ValidationReport) {
+	log.Printf("Validated %d bars in %s: %d gaps, %d duplicate timestamps, %d invalid bars",
+		report.Bars, report.Path, len(report.Gaps), len(report.Duplicates), len(report.InvalidPrices))
+
+	for i, gap := range report.Gaps {
+		if i == maxReportedIssues {
+			log.Printf("  ... %d more gaps", len(report.Gaps)-i)
+
+			break
+		}
+
+		log.Printf("  gap: %s missing %d bars between %s and %s", gap.Symbol, gap.MissingBars, gap.From.Format(time.RFC3339), gap.To.Format(time.RFC3339))
+	}
+
+	for i, duplicate := range report.Duplicates {
+		if i == maxReportedIssues {
+			log.Printf("  ... %d more duplicate timestamps", len(report.Duplicates)-i)
+
+			break
+		}
+
+		log.Printf("  duplicate: %s at %s appears %d times", duplicate.Symbol, duplicate.Time.Format(time.RFC3339), duplicate.Count)
+	}
+
+	for i, bar := range report.InvalidPrices {
+		if i == maxReportedIssues {
+			log.Printf("  ... %d more invalid bars", len(report.InvalidPrices)-i)
+
+			break
+		}
+
+		log.Printf("  invalid: %s at %s has %s", bar.Symbol, bar.Time.Format(time.RFC3339), bar.Reason)
+	}
+}
+
 func main() {
 	// Define the CLI application
 	cmd := &cli.Command{
@@ -102,7 +166,7 @@ func main() {
 			&cli.StringFlag{
 				Name:     "provider",
 				Aliases:  []string{"p"},
-				Usage:    fmt.Sprintf("Data provider to use (e.g., %s, %s)", marketdata.ProviderPolygon, marketdata.ProviderBinance),
+				Usage:    fmt.Sprintf("Data provider to use (e.g., %s, %s, %s)", marketdata.ProviderPolygon, marketdata.ProviderBinance, marketdata.ProviderCSV),
 				Value:    string(marketdata.ProviderPolygon), // Default provider
 				Required: false,
 			},
@@ -113,6 +177,11 @@ func main() {
 				Value:    string(marketdata.WriterDuckDB), // Default writer
 				Required: false,
 			},
+			&cli.StringFlag{
+				Name:     "csv",
+				Usage:    "Path to the CSV file to import when using the csv provider",
+				Required: false,
+			},
 			&cli.StringFlag{
 				Name:     "data",
 				Aliases:  []string{"d"},
@@ -120,6 +189,29 @@ func main() {
 				Value:    "data", // Default data directory
 				Required: false,
 			},
+			&cli.IntFlag{
+				Name:     "chunk-days",
+				Usage:    "Download the range in chunks of this many days and resume from the missing chunks when run again. 0 downloads the range in one request",
+				Value:    1,
+				Required: false,
+			},
+			&cli.IntFlag{
+				Name:     "retries",
+				Usage:    "Number of times a failed chunk is retried",
+				Value:    3,
+				Required: false,
+			},
+			&cli.DurationFlag{
+				Name:     "retry-backoff",
+				Usage:    "Wait before the first retry of a failed chunk, doubled for each further retry",
+				Value:    2 * time.Second,
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "validate",
+				Usage:    "Check the downloaded data for gaps, duplicate timestamps and invalid prices",
+				Required: false,
+			},
 		},
 		Action: downloadAction, // Assign the action function
 	}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
@@ -11,8 +10,10 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/rxtech-lab/argo-trading/internal/logger"
 	engine "github.com/rxtech-lab/argo-trading/internal/trading/engine"
 	enginev1 "github.com/rxtech-lab/argo-trading/internal/trading/engine/engine_v1"
+	"github.com/rxtech-lab/argo-trading/internal/trading/monitor"
 	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
@@ -24,12 +25,18 @@ func main() {
 	strategyConfigFlag := flag.String("strategy-config", "", "Path to strategy configuration file")
 	marketDataProviderFlag := flag.String("market-data-provider", "", "Market data provider: binance, polygon (required)")
 	polygonApiKeyFlag := flag.String("polygon-api-key", "", "Polygon API key (required if provider=polygon)")
-	tradingProviderFlag := flag.String("trading-provider", "", "Trading provider: binance-paper, binance-live (required)")
+	tradingProviderFlag := flag.String("trading-provider", "", "Trading provider: binance-paper, binance-live, kraken-live, alpaca-paper, alpaca-live (required)")
 	tradingConfigFlag := flag.String("trading-config", "", "Path to trading provider config file (required)")
 	symbolsFlag := flag.String("symbols", "", "Comma-separated list of symbols (required)")
 	intervalFlag := flag.String("interval", "1m", "Candlestick interval")
 	cacheSizeFlag := flag.Int("cache-size", 1000, "Market data cache size")
 	logOutputFlag := flag.String("log-output", "", "Directory for log output files")
+	initialBalanceFlag := flag.Float64("initial-balance", 0, "Trade a simulated paper account holding this much cash instead of the exchange account")
+	baseCurrencyFlag := flag.String("base-currency", "USDT", "Currency of the paper account cash")
+	liquidateOnStopFlag := flag.Bool("liquidate-on-stop", false, "Cancel open orders and close the positions in the traded symbols when stopping")
+	logLevelFlag := flag.String("log-level", "info", "Minimum console log level: debug, info, warning, error")
+	logFormatFlag := flag.String("log-format", "json", "Console log format: json, console")
+	metricsAddrFlag := flag.String("metrics-addr", "", "Serve engine status at /status and Prometheus metrics at /metrics on this address (e.g. :9090)")
 
 	flag.Parse()
 
@@ -86,6 +93,11 @@ func main() {
 	config := engine.LiveTradingEngineConfig{
 		MarketDataCacheSize: *cacheSizeFlag,
 		EnableLogging:       *logOutputFlag != "",
+		InitialBalance:      *initialBalanceFlag,
+		BaseCurrency:        *baseCurrencyFlag,
+		LiquidateOnStop:     *liquidateOnStopFlag,
+		LogLevel:            types.LogLevel(*logLevelFlag),
+		LogFormat:           logger.Format(*logFormatFlag),
 	}
 	if err := eng.Initialize(config); err != nil {
 		log.Fatalf("Failed to initialize engine: %v", err)
@@ -116,12 +128,12 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to read trading config: %v", err)
 	}
-	var tradingConfig tradingprovider.BinanceProviderConfig
-	if err := json.Unmarshal(tradingConfigBytes, &tradingConfig); err != nil {
+	tradingConfig, err := tradingprovider.ParseProviderConfig(*tradingProviderFlag, string(tradingConfigBytes))
+	if err != nil {
 		log.Fatalf("Failed to parse trading config: %v", err)
 	}
 	tradingProvider, err := tradingprovider.NewTradingSystemProvider(
-		tradingprovider.ProviderType(*tradingProviderFlag), &tradingConfig)
+		tradingprovider.ProviderType(*tradingProviderFlag), tradingConfig)
 	if err != nil {
 		log.Fatalf("Failed to create trading provider: %v", err)
 	}
@@ -197,8 +209,41 @@ func main() {
 		<-sigChan
 		fmt.Println("\nReceived interrupt signal, stopping...")
 		cancel()
+
+		// A second interrupt skips the shutdown work, such as liquidation
+		<-sigChan
+		fmt.Println("\nReceived second interrupt signal, exiting immediately")
+		os.Exit(1)
 	}()
 
+	// Serve status and metrics if requested
+	if *metricsAddrFlag != "" {
+		// Read the account strategies trade, the paper account with
+		// --initial-balance, which the engine sets up when it starts
+		mon := monitor.New(nil)
+		callbacks = mon.Wrap(callbacks)
+
+		startCallback := callbacks.OnEngineStart
+		onMonitoredStart := engine.OnEngineStartCallback(func(symbols []string, interval string, previousDataPath string) error {
+			mon.SetSource(eng.Account())
+
+			if startCallback != nil {
+				return (*startCallback)(symbols, interval, previousDataPath)
+			}
+
+			return nil
+		})
+		callbacks.OnEngineStart = &onMonitoredStart
+
+		go mon.Run(ctx)
+		go func() {
+			if err := mon.ListenAndServe(ctx, *metricsAddrFlag); err != nil {
+				fmt.Printf("Metrics server error: %v\n", err)
+			}
+		}()
+		fmt.Printf("Serving status and metrics on %s\n", *metricsAddrFlag)
+	}
+
 	// Run engine
 	fmt.Printf("Starting live trading with %d symbols...\n", len(symbols))
 	err = eng.Run(ctx, callbacks)
@@ -0,0 +1,83 @@
+//go:build wasip1
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/knqyf263/go-plugin/types/known/emptypb"
+	"github.com/rxtech-lab/argo-trading/pkg/strategy"
+)
+
+// CurrentTimeStrategy logs the time and interval the host reports on every bar
+// next to the time of the bar itself, to test GetCurrentTime and GetInterval.
+type CurrentTimeStrategy struct{}
+
+func main() {}
+
+func init() {
+	strategy.RegisterTradingStrategy(NewCurrentTimeStrategy())
+}
+
+func NewCurrentTimeStrategy() strategy.TradingStrategy {
+	return &CurrentTimeStrategy{}
+}
+
+// Initialize implements strategy.TradingStrategy.
+func (s *CurrentTimeStrategy) Initialize(_ context.Context, _ *strategy.InitializeRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// Name implements strategy.TradingStrategy.
+func (s *CurrentTimeStrategy) Name(_ context.Context, _ *strategy.NameRequest) (*strategy.NameResponse, error) {
+	return &strategy.NameResponse{Name: "CurrentTimeStrategy"}, nil
+}
+
+// GetDescription implements strategy.TradingStrategy.
+func (s *CurrentTimeStrategy) GetDescription(_ context.Context, _ *strategy.GetDescriptionRequest) (*strategy.GetDescriptionResponse, error) {
+	return &strategy.GetDescriptionResponse{Description: "A strategy that logs the current time and interval for testing"}, nil
+}
+
+// ProcessData implements strategy.TradingStrategy.
+func (s *CurrentTimeStrategy) ProcessData(ctx context.Context, req *strategy.ProcessDataRequest) (*emptypb.Empty, error) {
+	api := strategy.NewStrategyApi()
+
+	currentTime, err := api.GetCurrentTime(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current time: %w", err)
+	}
+
+	interval, err := api.GetInterval(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interval: %w", err)
+	}
+
+	_, err = api.Log(ctx, &strategy.LogRequest{
+		Message: "Current time",
+		Level:   strategy.LogLevel_LOG_LEVEL_INFO,
+		Fields: map[string]string{
+			"bar_time":     req.Data.Time.AsTime().Format(time.RFC3339),
+			"current_time": currentTime.Time.AsTime().Format(time.RFC3339),
+			"interval":     interval.Interval,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to log: %w", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetConfigSchema implements strategy.TradingStrategy.
+func (s *CurrentTimeStrategy) GetConfigSchema(_ context.Context, _ *strategy.GetConfigSchemaRequest) (*strategy.GetConfigSchemaResponse, error) {
+	return &strategy.GetConfigSchemaResponse{Schema: "{}"}, nil
+}
+
+// GetIdentifier implements strategy.TradingStrategy.
+func (s *CurrentTimeStrategy) GetIdentifier(_ context.Context, _ *strategy.GetIdentifierRequest) (*strategy.GetIdentifierResponse, error) {
+	return &strategy.GetIdentifierResponse{
+		Identifier: "com.argo-trading.e2e.current-time",
+	}, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/e2e/backtest/wasm/testhelper"
+	"github.com/stretchr/testify/suite"
+)
+
+// CurrentTimeTestSuite extends the base test suite
+type CurrentTimeTestSuite struct {
+	testhelper.E2ETestSuite
+}
+
+func TestCurrentTimeTestSuite(t *testing.T) {
+	suite.Run(t, new(CurrentTimeTestSuite))
+}
+
+// SetupTest initializes the test with config
+func (s *CurrentTimeTestSuite) SetupTest() {
+}
+
+func (s *CurrentTimeTestSuite) TestCurrentTimeAndInterval() {
+	s.Run("TestCurrentTimeAndInterval", func() {
+		s.E2ETestSuite.SetupTest(`
+initial_capital: 10000
+`)
+		start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		dataPath := filepath.Join(s.T().TempDir(), testhelper.GenerateMockFilename("current_time_data"))
+		err := testhelper.GenerateAndWriteToParquet(testhelper.MockDataConfig{
+			Symbol:        "TESTSTOCK",
+			StartTime:     start,
+			Interval:      15 * time.Minute,
+			NumDataPoints: 10,
+			Pattern:       testhelper.PatternIncreasing,
+			InitialPrice:  100.0,
+			TrendStrength: 0.5,
+			Seed:          42,
+		}, dataPath)
+		s.Require().NoError(err)
+
+		tmpFolder := testhelper.RunWasmStrategyTest(&s.E2ETestSuite, "CurrentTimeStrategy", "./current_time_plugin.wasm", dataPath)
+
+		logs, err := testhelper.ReadLogs(&s.E2ETestSuite, tmpFolder)
+		s.Require().NoError(err)
+		s.Require().Len(logs, 10)
+
+		for i, logEntry := range logs {
+			barTime := start.Add(time.Duration(i) * 15 * time.Minute).Format(time.RFC3339)
+			s.Equal(barTime, logEntry.Fields["bar_time"])
+			s.Equal(barTime, logEntry.Fields["current_time"])
+
+			// The interval is known once a second bar has been seen
+			if i == 0 {
+				s.Empty(logEntry.Fields["interval"])
+			} else {
+				s.Equal("15m", logEntry.Fields["interval"])
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+//go:build wasip1
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/go-plugin/types/known/emptypb"
+	"github.com/rxtech-lab/argo-trading/pkg/strategy"
+)
+
+// recentCandles is the number of candles the strategy reads on every bar.
+const recentCandles = 3
+
+// RecentCandlesStrategy logs the times and close prices of the last candles the
+// host reports on every bar, to test GetRecentCandles.
+type RecentCandlesStrategy struct{}
+
+func main() {}
+
+func init() {
+	strategy.RegisterTradingStrategy(NewRecentCandlesStrategy())
+}
+
+func NewRecentCandlesStrategy() strategy.TradingStrategy {
+	return &RecentCandlesStrategy{}
+}
+
+// Initialize implements strategy.TradingStrategy.
+func (s *RecentCandlesStrategy) Initialize(_ context.Context, _ *strategy.InitializeRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// Name implements strategy.TradingStrategy.
+func (s *RecentCandlesStrategy) Name(_ context.Context, _ *strategy.NameRequest) (*strategy.NameResponse, error) {
+	return &strategy.NameResponse{Name: "RecentCandlesStrategy"}, nil
+}
+
+// GetDescription implements strategy.TradingStrategy.
+func (s *RecentCandlesStrategy) GetDescription(_ context.Context, _ *strategy.GetDescriptionRequest) (*strategy.GetDescriptionResponse, error) {
+	return &strategy.GetDescriptionResponse{Description: "A strategy that logs the most recent candles for testing"}, nil
+}
+
+// ProcessData implements strategy.TradingStrategy.
+func (s *RecentCandlesStrategy) ProcessData(ctx context.Context, req *strategy.ProcessDataRequest) (*emptypb.Empty, error) {
+	api := strategy.NewStrategyApi()
+
+	candles, err := api.GetRecentCandles(ctx, &strategy.GetRecentCandlesRequest{
+		Symbol: req.Data.Symbol,
+		N:      recentCandles,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent candles: %w", err)
+	}
+
+	times := make([]string, len(candles.Data))
+	closes := make([]string, len(candles.Data))
+
+	for i, candle := range candles.Data {
+		times[i] = candle.Time.AsTime().Format(time.RFC3339)
+		closes[i] = strconv.FormatFloat(candle.Close, 'f', -1, 64)
+	}
+
+	_, err = api.Log(ctx, &strategy.LogRequest{
+		Message: "Recent candles",
+		Level:   strategy.LogLevel_LOG_LEVEL_INFO,
+		Fields: map[string]string{
+			"bar_time": req.Data.Time.AsTime().Format(time.RFC3339),
+			"times":    strings.Join(times, ","),
+			"closes":   strings.Join(closes, ","),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to log: %w", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetConfigSchema implements strategy.TradingStrategy.
+func (s *RecentCandlesStrategy) GetConfigSchema(_ context.Context, _ *strategy.GetConfigSchemaRequest) (*strategy.GetConfigSchemaResponse, error) {
+	return &strategy.GetConfigSchemaResponse{Schema: "{}"}, nil
+}
+
+// GetIdentifier implements strategy.TradingStrategy.
+func (s *RecentCandlesStrategy) GetIdentifier(_ context.Context, _ *strategy.GetIdentifierRequest) (*strategy.GetIdentifierResponse, error) {
+	return &strategy.GetIdentifierResponse{
+		Identifier: "com.argo-trading.e2e.recent-candles",
+	}, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/e2e/backtest/wasm/testhelper"
+	"github.com/stretchr/testify/suite"
+)
+
+// RecentCandlesTestSuite extends the base test suite
+type RecentCandlesTestSuite struct {
+	testhelper.E2ETestSuite
+}
+
+func TestRecentCandlesTestSuite(t *testing.T) {
+	suite.Run(t, new(RecentCandlesTestSuite))
+}
+
+// SetupTest initializes the test with config
+func (s *RecentCandlesTestSuite) SetupTest() {
+}
+
+func (s *RecentCandlesTestSuite) TestRecentCandles() {
+	s.Run("TestRecentCandlesAfterWarmup", func() {
+		// The two warm-up bars fill the cache, so the strategy can read three
+		// candles from its first bar on
+		s.E2ETestSuite.SetupTest(`
+initial_capital: 10000
+market_data_cache_size: 5
+warmup_bars: 2
+`)
+		start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		dataPath := filepath.Join(s.T().TempDir(), testhelper.GenerateMockFilename("recent_candles_data"))
+		err := testhelper.GenerateAndWriteToParquet(testhelper.MockDataConfig{
+			Symbol:        "TESTSTOCK",
+			StartTime:     start,
+			Interval:      time.Minute,
+			NumDataPoints: 10,
+			Pattern:       testhelper.PatternIncreasing,
+			InitialPrice:  100.0,
+			TrendStrength: 0.5,
+			Seed:          42,
+		}, dataPath)
+		s.Require().NoError(err)
+
+		tmpFolder := testhelper.RunWasmStrategyTest(&s.E2ETestSuite, "RecentCandlesStrategy", "./recent_candles_plugin.wasm", dataPath)
+
+		logs, err := testhelper.ReadLogs(&s.E2ETestSuite, tmpFolder)
+		s.Require().NoError(err)
+		s.Require().Len(logs, 8)
+
+		var previousCloses []string
+
+		for i, logEntry := range logs {
+			barIndex := i + 2
+			s.Equal(start.Add(time.Duration(barIndex)*time.Minute).Format(time.RFC3339), logEntry.Fields["bar_time"])
+
+			expectedTimes := make([]string, 3)
+			for j := range expectedTimes {
+				expectedTimes[j] = start.Add(time.Duration(barIndex-2+j) * time.Minute).Format(time.RFC3339)
+			}
+
+			s.Equal(strings.Join(expectedTimes, ","), logEntry.Fields["times"])
+
+			// The window slides by one candle per bar
+			closes := strings.Split(logEntry.Fields["closes"], ",")
+			s.Require().Len(closes, 3)
+
+			if previousCloses != nil {
+				s.Equal(previousCloses[1:], closes[:2], fmt.Sprintf("bar %d", barIndex))
+			}
+
+			previousCloses = closes
+		}
+	})
+	s.Run("TestRecentCandlesWithoutWarmup", func() {
+		s.E2ETestSuite.SetupTest(`
+initial_capital: 10000
+market_data_cache_size: 5
+`)
+		start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		dataPath := filepath.Join(s.T().TempDir(), testhelper.GenerateMockFilename("recent_candles_data"))
+		err := testhelper.GenerateAndWriteToParquet(testhelper.MockDataConfig{
+			Symbol:        "TESTSTOCK",
+			StartTime:     start,
+			Interval:      time.Minute,
+			NumDataPoints: 10,
+			Pattern:       testhelper.PatternIncreasing,
+			InitialPrice:  100.0,
+			TrendStrength: 0.5,
+			Seed:          42,
+		}, dataPath)
+		s.Require().NoError(err)
+
+		tmpFolder := testhelper.RunWasmStrategyTest(&s.E2ETestSuite, "RecentCandlesStrategy", "./recent_candles_plugin.wasm", dataPath)
+
+		markers, err := testhelper.ReadMarker(&s.E2ETestSuite, tmpFolder)
+		s.Require().NoError(err)
+
+		// The first two bars report insufficient data until three candles are cached
+		s.Require().Len(markers, 2)
+		s.Equal("InsufficientData", string(markers[0].Category))
+		s.Equal(start, markers[0].Signal.Unwrap().Time.UTC())
+		s.Equal(start.Add(time.Minute), markers[1].Signal.Unwrap().Time.UTC())
+
+		logs, err := testhelper.ReadLogs(&s.E2ETestSuite, tmpFolder)
+		s.Require().NoError(err)
+		s.Require().Len(logs, 8)
+		s.Equal(start.Add(2*time.Minute).Format(time.RFC3339), logs[0].Fields["bar_time"])
+	})
+}
@@ -187,6 +187,8 @@ func (g *MockDataGenerator) Generate() ([]types.MarketData, error) {
 			Low:    low,
 			Close:  closePrice,
 			Volume: volume,
+			Bid:    0,
+			Ask:    0,
 		}
 
 		currentPrice = newPrice
@@ -210,6 +210,7 @@ class MockTradingEngineHelper: NSObject, SwiftargoTradingEngineHelperProtocol {
     var marketDataCalled = false
     var orderPlacedCalled = false
     var orderFilledCalled = false
+    var orderRejectedCalled = false
     var errorCalled = false
     var strategyErrorCalled = false
     var statusUpdateCalled = false
@@ -229,6 +230,7 @@ class MockTradingEngineHelper: NSObject, SwiftargoTradingEngineHelperProtocol {
     var marketDataCount: Int = 0
     var orderPlacedCount: Int = 0
     var orderFilledCount: Int = 0
+    var lastRejectionReason: String = ""
     var lastOrderJSON: String = ""
     var statusUpdates: [String] = []
     var prefetchProgressCount: Int = 0
@@ -315,6 +317,12 @@ class MockTradingEngineHelper: NSObject, SwiftargoTradingEngineHelperProtocol {
         }
     }
 
+    func onOrderRejected(_ orderJSON: String?, reason: String?) throws {
+        orderRejectedCalled = true
+        lastOrderJSON = orderJSON ?? ""
+        lastRejectionReason = reason ?? ""
+    }
+
     func onError(_ err: Error?) {
         errorCalled = true
         lastError = err
@@ -107,6 +107,18 @@ func (p *MockMarketDataProvider) Download(
 	return "", fmt.Errorf("download not supported in mock provider")
 }
 
+// GetHistoricalKlines implements provider.Provider.
+// This is not supported for mock provider since we only do streaming.
+func (p *MockMarketDataProvider) GetHistoricalKlines(
+	_ context.Context,
+	_ string,
+	_ string,
+	_ time.Time,
+	_ time.Time,
+) ([]types.MarketData, error) {
+	return nil, fmt.Errorf("historical klines not supported in mock provider")
+}
+
 // Stream implements provider.Provider.
 // Yields generated market data as fast as possible for quick test execution.
 // Data is generated using the MockDataGenerator from backtest testhelper.
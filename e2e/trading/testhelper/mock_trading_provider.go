@@ -3,9 +3,12 @@ package testhelper
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 )
@@ -163,6 +166,10 @@ func (m *MockTradingProvider) getOrCreatePosition(symbol string) *types.Position
 		TotalLongOutFee:               0,
 		TotalShortInFee:               0,
 		TotalShortOutFee:              0,
+		AverageLongEntryPrice:         0,
+		TotalLongCost:                 0,
+		AverageShortEntryPrice:        0,
+		TotalShortCost:                0,
 		OpenTimestamp:                 time.Time{},
 		StrategyName:                  "",
 	}
@@ -222,6 +229,37 @@ func (m *MockTradingProvider) CancelAllOrders() error {
 	return nil
 }
 
+// CancelOrdersForSymbol removes the open orders of a symbol.
+func (m *MockTradingProvider) CancelOrdersForSymbol(symbol string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.openOrders = slices.DeleteFunc(m.openOrders, func(order types.ExecuteOrder) bool {
+		return order.Symbol == symbol
+	})
+
+	return nil
+}
+
+// PlaceOCOOrder executes the entry instantly at the current price. The legs are
+// not simulated since the mock has no price feed to trigger them.
+func (m *MockTradingProvider) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	if err := tradingprovider.ValidateOCOOrder(entry, takeProfit, stopLoss); err != nil {
+		return "", err
+	}
+
+	if err := m.PlaceOrder(entry); err != nil {
+		return "", err
+	}
+
+	return uuid.NewString(), nil
+}
+
+// PlaceBracketOrder places the bracket through PlaceOCOOrder, so only the entry executes.
+func (m *MockTradingProvider) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	return tradingprovider.PlaceBracketOrderAsOCO(m, entry, stopLoss, takeProfit)
+}
+
 // GetOrderStatus returns the status of an order.
 func (m *MockTradingProvider) GetOrderStatus(_ string) (types.OrderStatus, error) {
 	// All orders in mock execute instantly
@@ -241,6 +279,8 @@ func (m *MockTradingProvider) GetAccountInfo() (types.AccountInfo, error) {
 		UnrealizedPnL: 0,
 		TotalFees:     0,
 		MarginUsed:    0,
+		FreeMargin:    m.balance,
+		Balances:      nil,
 	}, nil
 }
 
@@ -352,6 +392,54 @@ func (m *MockTradingProvider) GetTrades(filter types.TradeFilter) ([]types.Trade
 	return result, nil
 }
 
+// GetTradesPage returns one page of executed trades. The cursor is the index of
+// the last trade returned.
+func (m *MockTradingProvider) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	start := 0
+
+	if filter.Cursor != "" {
+		last, err := strconv.Atoi(filter.Cursor)
+		if err != nil {
+			return types.TradePage{}, fmt.Errorf("invalid trade cursor %q", filter.Cursor)
+		}
+
+		start = last + 1
+	}
+
+	page := types.TradePage{Trades: make([]types.Trade, 0)}
+	lastIndex := start - 1
+
+	for i := start; i < len(m.trades); i++ {
+		trade := m.trades[i]
+
+		if filter.Symbol != "" && trade.Order.Symbol != filter.Symbol {
+			continue
+		}
+
+		if !filter.StartTime.IsZero() && trade.ExecutedAt.Before(filter.StartTime) {
+			continue
+		}
+
+		if !filter.EndTime.IsZero() && trade.ExecutedAt.After(filter.EndTime) {
+			continue
+		}
+
+		if filter.Limit > 0 && len(page.Trades) == filter.Limit {
+			page.NextCursor = strconv.Itoa(lastIndex)
+
+			break
+		}
+
+		page.Trades = append(page.Trades, trade)
+		lastIndex = i
+	}
+
+	return page, nil
+}
+
 // GetMaxBuyQuantity returns the maximum quantity that can be bought at the given price.
 func (m *MockTradingProvider) GetMaxBuyQuantity(_ string, price float64) (float64, error) {
 	m.mu.RLock()
@@ -376,6 +464,16 @@ func (m *MockTradingProvider) GetMaxSellQuantity(symbol string) (float64, error)
 	return 0, nil
 }
 
+// CalculatePositionSize sizes a position by the risk taken to its stop.
+func (m *MockTradingProvider) CalculatePositionSize(_ string, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	accountInfo, err := m.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	return tradingprovider.RiskPositionSize(accountInfo.Equity, entryPrice, stopPrice, riskFraction)
+}
+
 // GetAllTrades returns all trades without filter (convenience for tests).
 func (m *MockTradingProvider) GetAllTrades() []types.Trade {
 	m.mu.RLock()
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"time"
 
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
 	"github.com/rxtech-lab/argo-trading/internal/runtime"
@@ -35,8 +36,14 @@ type ProgressInfo struct {
 	Current int
 	// Total is the total number of bars in this run.
 	Total int
-	// BarsPerSecond is the wall-clock throughput since the start of this run.
+	// BarsPerSecond is the wall-clock throughput, a moving average over the most
+	// recently processed bars. Zero until time has passed.
 	BarsPerSecond float64
+	// Elapsed is the wall-clock time since the start of this run.
+	Elapsed time.Duration
+	// Remaining is the estimated time to process the rest of the bars at
+	// BarsPerSecond. Zero when there is no throughput to estimate from yet.
+	Remaining time.Duration
 	// RealizedPnL is the cumulative realized PnL across all closed trades in this run.
 	RealizedPnL float64
 }
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/indicator"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/internal/utils"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// defaultATRSizingPeriod is the ATR period used when the ATR sizing config sets none.
+const defaultATRSizingPeriod = 14
+
+// ATRSizingConfig sizes entry orders by volatility: the quantity is chosen so
+// that an adverse move of ATRMultiple times the ATR costs RiskPercentage of
+// the equity.
+type ATRSizingConfig struct {
+	ATRMultiple    float64 `yaml:"atr_multiple" json:"atr_multiple" jsonschema:"title=ATR Multiple,description=Multiple of the ATR a position is sized to survive. Set to 0 to disable ATR sizing.,minimum=0,default=0"`
+	RiskPercentage float64 `yaml:"risk_percentage" json:"risk_percentage" jsonschema:"title=Risk Percentage,description=Fraction of the equity (e.g. 0.01 = 1%) lost when the price moves against the position by the ATR multiple.,minimum=0,maximum=1,default=0"`
+	Period         int     `yaml:"period" json:"period" jsonschema:"title=Period,description=Number of bars of the ATR. Defaults to 14.,minimum=0,default=14"`
+}
+
+// Enabled reports whether ATR sizing is configured.
+func (c ATRSizingConfig) Enabled() bool {
+	return c.ATRMultiple != 0
+}
+
+// atrSource returns the ATR of a symbol over period bars at the given bar time.
+type atrSource func(symbol string, at time.Time, period int) (float64, error)
+
+// SetATRSizing configures ATR sizing of entry orders with the ATR read from
+// atr. A config without an ATR multiple disables it.
+func (b *BacktestTrading) SetATRSizing(config ATRSizingConfig, atr atrSource) error {
+	if !config.Enabled() {
+		b.atrSizing = nil
+		b.atr = nil
+
+		return nil
+	}
+
+	if config.ATRMultiple < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "ATR multiple must not be negative: %f", config.ATRMultiple)
+	}
+
+	if config.RiskPercentage <= 0 || config.RiskPercentage > 1 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "ATR sizing risk percentage must be between 0 and 1: %f", config.RiskPercentage)
+	}
+
+	if config.Period < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "ATR sizing period must not be negative: %d", config.Period)
+	}
+
+	if config.Period == 0 {
+		config.Period = defaultATRSizingPeriod
+	}
+
+	if atr == nil {
+		return errors.New(errors.ErrCodeInvalidParameter, "ATR sizing needs an ATR source")
+	}
+
+	b.atrSizing = &config
+	b.atr = atr
+
+	return nil
+}
+
+// sizeByATR replaces the quantity of an entry order with the quantity at
+// which the configured ATR multiple costs the configured share of the equity.
+// The order keeps the quantity the strategy requested until the symbol has
+// enough bars for the ATR.
+func (b *BacktestTrading) sizeByATR(order *types.ExecuteOrder) error {
+	if b.atrSizing == nil || !isEntryOrder(*order) {
+		return nil
+	}
+
+	atr, err := b.atr(order.Symbol, b.marketData.Time, b.atrSizing.Period)
+	if errors.IsInsufficientDataError(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeIndicatorCalculation, "failed to read the ATR for order sizing", err)
+	}
+
+	accountInfo, err := b.GetAccountInfo()
+	if err != nil {
+		return err
+	}
+
+	quantity := utils.CalculateOrderQuantityByATR(accountInfo.Equity, b.atrSizing.RiskPercentage, atr, b.atrSizing.ATRMultiple)
+	if quantity > 0 {
+		order.Quantity = quantity
+	}
+
+	return nil
+}
+
+// atr reads the ATR of symbol at a bar time from the ATR indicator of the
+// registry, sharing the indicator cache of the strategy.
+func (b *BacktestEngineV1) atr(symbol string, at time.Time, period int) (float64, error) {
+	atr, err := b.indicatorRegistry.GetIndicator(types.IndicatorTypeATR)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := indicator.IndicatorContext{
+		DataSource:        b.datasource,
+		IndicatorRegistry: b.indicatorRegistry,
+		Cache:             b.cache,
+	}
+
+	return atr.RawValue(symbol, at, ctx, period)
+}
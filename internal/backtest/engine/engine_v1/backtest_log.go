@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/Masterminds/squirrel"
 	_ "github.com/marcboeker/go-duckdb"
@@ -166,6 +165,11 @@ func (l *BacktestLog) GetLogs() ([]log.LogEntry, error) {
 
 // Write saves the logs to a Parquet file in the specified directory.
 func (l *BacktestLog) Write(path string) error {
+	return l.WriteFormats(path, []ResultOutputFormat{ResultOutputFormatParquet})
+}
+
+// WriteFormats writes the logs to the path in every given format.
+func (l *BacktestLog) WriteFormats(path string, formats []ResultOutputFormat) error {
 	// Check for nil fields
 	if l == nil || l.db == nil || l.logger == nil {
 		return fmt.Errorf("backtest log, database, or logger is nil")
@@ -176,16 +180,13 @@ func (l *BacktestLog) Write(path string) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Export logs to Parquet
-	logsPath := filepath.Join(path, "logs.parquet")
-
-	_, err := l.db.Exec(fmt.Sprintf(`COPY logs TO '%s' (FORMAT PARQUET)`, logsPath))
+	logsPaths, err := copyTableToFormats(l.db, "logs", path, formats)
 	if err != nil {
-		return fmt.Errorf("failed to export logs to Parquet: %w", err)
+		return err
 	}
 
-	l.logger.Info("Successfully exported logs to Parquet file",
-		zap.String("logs", logsPath),
+	l.logger.Info("Successfully exported logs",
+		zap.Strings("logs", logsPaths),
 	)
 
 	return nil
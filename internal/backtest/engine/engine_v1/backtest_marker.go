@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/Masterminds/squirrel"
 	_ "github.com/marcboeker/go-duckdb"
@@ -184,6 +183,7 @@ func (m *BacktestMarker) GetMarks() ([]types.Mark, error) {
 			Title:        title,
 			Message:      message,
 			Category:     category,
+			StrategyName: "",
 			Signal:       optional.None[types.Signal](),
 		}
 
@@ -215,6 +215,11 @@ func (m *BacktestMarker) GetMarks() ([]types.Mark, error) {
 
 // Write saves the marks to a Parquet file in the specified directory.
 func (m *BacktestMarker) Write(path string) error {
+	return m.WriteFormats(path, []ResultOutputFormat{ResultOutputFormatParquet})
+}
+
+// WriteFormats writes the marks to the path in every given format.
+func (m *BacktestMarker) WriteFormats(path string, formats []ResultOutputFormat) error {
 	// Check for nil fields
 	if m == nil || m.db == nil || m.logger == nil {
 		return fmt.Errorf("backtest marker, database, or logger is nil")
@@ -225,16 +230,13 @@ func (m *BacktestMarker) Write(path string) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Export marks to Parquet
-	marksPath := filepath.Join(path, "marks.parquet")
-
-	_, err := m.db.Exec(fmt.Sprintf(`COPY marks TO '%s' (FORMAT PARQUET)`, marksPath))
+	marksPaths, err := copyTableToFormats(m.db, "marks", path, formats)
 	if err != nil {
-		return fmt.Errorf("failed to export marks to Parquet: %w", err)
+		return err
 	}
 
-	m.logger.Info("Successfully exported marks to Parquet file",
-		zap.String("marks", marksPath),
+	m.logger.Info("Successfully exported marks",
+		zap.Strings("marks", marksPaths),
 	)
 
 	return nil
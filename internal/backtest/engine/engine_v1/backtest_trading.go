@@ -3,6 +3,7 @@ package engine
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"slices"
 	"time"
 
@@ -23,28 +24,197 @@ type BacktestTrading struct {
 	pendingOrders    []types.ExecuteOrder
 	commission       commission_fee.CommissionFee
 	decimalPrecision int
+	// symbolDecimalPrecision overrides decimalPrecision for the listed symbols.
+	symbolDecimalPrecision map[string]int
+	// roundingMode selects how order quantities are snapped to the lot grid.
+	roundingMode RoundingModeType
+	// lotSizes overrides the lot grid of decimal precision for the listed symbols.
+	lotSizes map[string]float64
+	// confirmationBars is the number of consecutive bars an order must be
+	// requested before it is placed. Values <= 1 disable the confirmation delay.
+	confirmationBars int
+	// signalConfirmations holds the orders waiting for confirmation, keyed by signalKey.
+	signalConfirmations map[string]*signalConfirmation
+	// lastPrices holds the latest close of every symbol seen, so positions of
+	// symbols other than the current bar's can be valued.
+	lastPrices map[string]float64
+	// benchmarkStop configures the benchmark-relative stop.
+	benchmarkStop BenchmarkRelativeStopConfig
+	// benchmarkPrice is the latest close of the benchmark symbol.
+	benchmarkPrice float64
+	// benchmarkTime is the time of the bar benchmarkPrice was read from.
+	benchmarkTime time.Time
+	// benchmarkEntries holds the benchmark close at the entry bar of each open position.
+	benchmarkEntries map[string]benchmarkEntry
+	// entryThrottle limits the number of new entries per bar across symbols.
+	entryThrottle EntryThrottleConfig
+	// entryBarTime is the time of the bar the entry count belongs to.
+	entryBarTime time.Time
+	// entriesThisBar is the number of entries placed on the current bar.
+	entriesThisBar int
+	// deferredEntries holds entries above the limit waiting for a later bar.
+	deferredEntries []types.ExecuteOrder
+	// releasingEntry is true while a deferred entry is placed on a later bar.
+	releasingEntry bool
+	// sessionFlatten closes positions at the session end. Nil when disabled.
+	sessionFlatten *sessionFlattener
+	// ocoLegs maps the IDs of pending OCO legs to their group.
+	ocoLegs map[string]ocoLeg
+	// brackets holds the bracket orders whose entry has not finished filling, in placement order.
+	brackets []*bracketOrder
+	// maxVolumeParticipation caps the quantity an order fills per bar at this
+	// fraction of the bar volume. 0 fills orders in full.
+	maxVolumeParticipation float64
+	// slippage moves market order fills against the order. Nil when disabled.
+	slippage SlippageModel
+	// limitFill decides when a bar fills a limit order. Nil fills on touch.
+	limitFill LimitFillPolicy
+	// fillPrice selects the bar price market orders fill at. Empty fills at the mid price.
+	fillPrice FillPricePolicyType
+	// commissionBounds holds the minimum and cap of the commission per order.
+	commissionBounds commission_fee.Config
+	// orderFees tracks the commission of partially filled orders while
+	// commission bounds are set, keyed by order ID.
+	orderFees map[string]orderFee
+	// randomSeed seeds rng at the start of every run.
+	randomSeed int64
+	// rng is the random source of every stochastic fill model. Models never draw
+	// from the global source, so runs with the same seed produce the same trades.
+	rng *rand.Rand
+	// cooldown rejects orders placed soon after the last accepted order of a symbol. Nil when disabled.
+	cooldown *orderCooldown
+	// dailyLimits caps the orders and notional accepted per UTC day. Nil when disabled.
+	dailyLimits *dailyLimits
+	// maxOpenPositions caps the symbols with an open position. 0 disables the cap.
+	maxOpenPositions int
+	// positionLimit caps the position size of each symbol. Nil when disabled.
+	positionLimit *PositionLimitConfig
+	// atrSizing sizes entry orders by the ATR read from atr. Nil when disabled.
+	atrSizing *ATRSizingConfig
+	atr       atrSource
+	// minHoldingPeriod is the time a position must be held before orders may reduce or close it. 0 disables it.
+	minHoldingPeriod time.Duration
+	// positionOpenedAt holds the opening fill time of each open position side
+	// while minHoldingPeriod is set, keyed by positionSideKey.
+	positionOpenedAt map[string]time.Time
+	// lastFailedOrderID is the ID of the last order a failed order was created for.
+	lastFailedOrderID string
+	// equityCurveEnabled records the account state after every bar.
+	equityCurveEnabled bool
+	// equityCurveInterval downsamples the equity curve to one row per interval. 0 keeps every bar.
+	equityCurveInterval time.Duration
+	// fundingRates holds the funding schedule of each perpetual futures symbol in time order.
+	fundingRates map[string][]FundingRate
+	// fundingNext is the index of the next unsettled funding rate of each symbol.
+	fundingNext map[string]int
+	// margin configures leveraged trading. The zero value trades without margin.
+	margin MarginConfig
+	// signalOnly records orders as signals instead of executing them.
+	signalOnly bool
 }
 
-func (b *BacktestTrading) UpdateCurrentMarketData(marketData types.MarketData) {
+// signalConfirmation tracks how many consecutive bars a strategy has requested
+// the same order while it waits for the configured confirmation delay.
+type signalConfirmation struct {
+	order types.ExecuteOrder
+	// bars is the number of consecutive bars the order has been requested on.
+	bars int
+	// requestedThisBar is true when the order was requested on the current bar.
+	requestedThisBar bool
+}
+
+// UpdateCurrentMarketData moves the trading system to the bar: it fills the
+// pending orders on it, runs the exits it triggers and records the account
+// state after it.
+func (b *BacktestTrading) UpdateCurrentMarketData(marketData types.MarketData) error {
 	b.marketData = marketData
 
+	if price := closePrice(marketData); price > 0 {
+		if b.lastPrices == nil {
+			b.lastPrices = map[string]float64{}
+		}
+
+		b.lastPrices[marketData.Symbol] = price
+	}
+
+	// Read the benchmark's close before this bar's fills record their entries
+	b.updateBenchmarkPrice()
+
+	// Reset the entry count when a new bar starts
+	b.advanceEntryThrottle(marketData.Time)
+
+	// Drop signals that were not requested again on the previous bar of this symbol
+	b.advanceSignalConfirmations(marketData.Symbol)
+
+	// Settle funding of the positions held into this bar
+	b.applyFunding()
+
+	// Cancel open orders and close the position at the session end
+	b.flattenAtSessionEnd()
+
 	// Process pending orders with the updated market data
 	b.processPendingOrders()
+
+	// Place the deferred entries of this bar's symbol
+	if err := b.releaseDeferredEntries(); err != nil {
+		return err
+	}
+
+	// Close the position when equity falls below the maintenance margin
+	b.checkMarginCall()
+
+	// Exit positions that lag the benchmark by more than the configured amount
+	if err := b.checkBenchmarkRelativeStop(); err != nil {
+		return err
+	}
+
+	// Record the account state after this bar's fills
+	return b.recordEquityCurve()
 }
 
+// UpdateBalance sets the cash balance the account starts from.
 func (b *BacktestTrading) UpdateBalance(balance float64) {
 	b.balance = balance
+	b.state.SetInitialBalance(balance)
+}
+
+// SetSignalConfirmationBars sets the number of consecutive bars an order must be
+// requested before it is placed. Values <= 1 place orders immediately.
+func (b *BacktestTrading) SetSignalConfirmationBars(bars int) {
+	b.confirmationBars = bars
+	b.signalConfirmations = map[string]*signalConfirmation{}
+}
+
+// SetSymbolDecimalPrecision sets the decimal precision of the quantities of
+// individual symbols. Symbols without one use the default decimal precision.
+func (b *BacktestTrading) SetSymbolDecimalPrecision(precisions map[string]int) error {
+	for symbol, precision := range precisions {
+		if precision < 0 {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "decimal precision of %s must not be negative: %d", symbol, precision)
+		}
+	}
+
+	b.symbolDecimalPrecision = precisions
+
+	return nil
 }
 
 // CancelAllOrders implements tradingprovider.TradingSystemProvider.
 func (b *BacktestTrading) CancelAllOrders() error {
 	b.pendingOrders = []types.ExecuteOrder{}
+	b.deferredEntries = []types.ExecuteOrder{}
+	b.ocoLegs = map[string]ocoLeg{}
 
 	return nil
 }
 
 // CancelOrder implements tradingprovider.TradingSystemProvider.
+// Cancelling one leg of an OCO group cancels the whole group.
 func (b *BacktestTrading) CancelOrder(orderID string) error {
+	if b.cancelOCOGroup(orderID) {
+		return nil
+	}
+
 	for i, order := range b.pendingOrders {
 		if order.ID == orderID {
 			b.pendingOrders = slices.Delete(b.pendingOrders, i, i+1)
@@ -56,8 +226,41 @@ func (b *BacktestTrading) CancelOrder(orderID string) error {
 	return nil
 }
 
+// CancelOrdersForSymbol implements tradingprovider.TradingSystemProvider.
+// The pending and deferred orders of other symbols are kept.
+func (b *BacktestTrading) CancelOrdersForSymbol(symbol string) error {
+	isSymbol := func(order types.ExecuteOrder) bool {
+		if order.Symbol != symbol {
+			return false
+		}
+
+		// Both legs of an OCO group share the symbol, so the whole group goes
+		delete(b.ocoLegs, order.ID)
+
+		return true
+	}
+
+	b.pendingOrders = slices.DeleteFunc(b.pendingOrders, isSymbol)
+	b.deferredEntries = slices.DeleteFunc(b.deferredEntries, isSymbol)
+
+	return nil
+}
+
 // GetOrderStatus implements tradingprovider.TradingSystemProvider.
 func (b *BacktestTrading) GetOrderStatus(orderID string) (types.OrderStatus, error) {
+	// Open orders, including the rest of partially filled ones, are still pending
+	for _, pendingOrder := range b.pendingOrders {
+		if pendingOrder.ID != orderID {
+			continue
+		}
+
+		if pendingOrder.FilledQuantity > 0 {
+			return types.OrderStatusPartiallyFilled, nil
+		}
+
+		return types.OrderStatusPending, nil
+	}
+
 	order, err := b.state.GetOrderById(orderID)
 	if err != nil {
 		return types.OrderStatusFailed, err
@@ -72,15 +275,13 @@ func (b *BacktestTrading) GetOrderStatus(orderID string) (types.OrderStatus, err
 		return types.OrderStatusFailed, err
 	}
 
-	if value.IsCompleted {
-		return types.OrderStatusFilled, nil
+	// Expired and IOC orders are completed without filling
+	if value.Status == types.OrderStatusCancelled {
+		return types.OrderStatusCancelled, nil
 	}
 
-	// check if the order is in the pending orders
-	for _, pendingOrder := range b.pendingOrders {
-		if pendingOrder.ID == orderID {
-			return types.OrderStatusPending, nil
-		}
+	if value.IsCompleted {
+		return types.OrderStatusFilled, nil
 	}
 
 	return types.OrderStatusFailed, nil
@@ -132,6 +333,13 @@ func (b *BacktestTrading) PlaceMultipleOrders(orders []types.ExecuteOrder) error
 func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 	order.ID = uuid.New().String()
 
+	return b.placeOrder(order)
+}
+
+// placeOrder places order under the ID it already has.
+//
+//nolint:funcorder // helper method used by PlaceOrder and PlaceBracketOrder
+func (b *BacktestTrading) placeOrder(order types.ExecuteOrder) (err error) {
 	// Check for invalid quantity before struct validation
 	if order.Quantity <= 0 {
 		failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonInvalidQuantity,
@@ -148,17 +356,107 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 		return b.state.StoreFailedOrder(failedOrder)
 	}
 
+	// Check for a missing trigger price on stop orders
+	if order.IsStopOrder() && order.StopPrice <= 0 {
+		failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonInvalidPrice,
+			fmt.Sprintf("stop order stop price must be greater than zero: %.2f", order.StopPrice))
+
+		return b.state.StoreFailedOrder(failedOrder)
+	}
+
+	// Check for a missing or out of range trail offset on trailing stop orders
+	if order.OrderType == types.OrderTypeTrailingStop &&
+		(order.TrailOffset <= 0 || (order.TrailPercent && order.TrailOffset >= 100)) {
+		failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonInvalidPrice,
+			fmt.Sprintf("trailing stop order trail offset is invalid: %.2f", order.TrailOffset))
+
+		return b.state.StoreFailedOrder(failedOrder)
+	}
+
 	// validate the order using go-playground/validator/v10
 	if err := order.Validate(); err != nil {
 		return err
 	}
 
-	// Round the quantity to respect configured decimal precision
-	order.Quantity = utils.RoundToDecimalPrecision(order.Quantity, b.decimalPrecision)
+	// Record the order as it was requested, before any execution rule applies
+	if b.signalOnly {
+		return b.recordSignal(order)
+	}
+
+	// Size entries so that the configured ATR multiple risks the configured share of the equity
+	if err := b.sizeByATR(&order); err != nil {
+		return err
+	}
+
+	// Snap the quantity to the lot grid under the rounding mode
+	order.Quantity, err = b.roundOrderQuantity(order.Symbol, order.Quantity)
+	if err != nil {
+		return err
+	}
+
 	if order.Quantity <= 0 {
 		return errors.New(errors.ErrCodeInvalidParameter, "order quantity is too small or zero after rounding to configured precision")
 	}
 
+	// Do not open new positions once the session has ended
+	if rejected, err := b.rejectAfterSessionEnd(order); rejected {
+		return err
+	}
+
+	// Hold the order until the signal has persisted for the configured number of bars.
+	// Released deferred entries were confirmed before they were deferred.
+	if !b.releasingEntry && !b.confirmSignal(order) {
+		return nil
+	}
+
+	// Reject orders within the cooldown of the symbol's last accepted order
+	if rejected, err := b.rejectDuringCooldown(order); rejected {
+		return err
+	}
+
+	// Reject exits before the position has been held for the minimum holding period
+	if rejected, err := b.rejectBeforeMinHolding(order); rejected {
+		return err
+	}
+
+	// Reject or shrink entries that would take the symbol's position over its size limit
+	if rejected, err := b.limitPositionSize(&order); rejected {
+		return err
+	}
+
+	// Reject orders over the day's order count or notional
+	if rejected, err := b.rejectOverDailyLimit(order); rejected {
+		return err
+	}
+
+	// Reject entries in new symbols once the maximum number of positions is open
+	if rejected, err := b.rejectOverMaxPositions(order); rejected {
+		return err
+	}
+
+	// Limit the number of new entries per bar across all symbols
+	if allowed, err := b.throttleEntry(order); !allowed {
+		return err
+	}
+
+	// Start the symbol's cooldown once the order has been placed without being rejected
+	if b.cooldown != nil {
+		defer func() {
+			if err == nil {
+				b.recordAcceptedOrder(order)
+			}
+		}()
+	}
+
+	// Count the order towards the daily limits once it has been placed without being rejected
+	if b.dailyLimits != nil {
+		defer func() {
+			if err == nil {
+				b.recordDailyOrder(order)
+			}
+		}()
+	}
+
 	// Check if the symbol matches current market data symbol
 	// If not, add to pending orders and return (no errors)
 	if order.Symbol != b.marketData.Symbol {
@@ -167,6 +465,16 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 		return nil
 	}
 
+	// Hold trailing stops until the trailed stop is crossed
+	if order.OrderType == types.OrderTypeTrailingStop {
+		return b.placeTrailingStopOrder(order)
+	}
+
+	// Hold stop orders until their stop price is crossed
+	if order.IsStopOrder() {
+		return b.placeStopOrder(order)
+	}
+
 	// Handle limit orders
 	if order.OrderType == types.OrderTypeLimit {
 		// Check if the order's price is valid (greater than zero)
@@ -174,19 +482,32 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 			return errors.Newf(errors.ErrCodeInvalidParameter, "limit order price must be greater than zero: %f", order.Price)
 		}
 
+		// Short sells and covers are checked against margin and the borrowed quantity
+		if order.PositionType == types.PositionTypeShort {
+			if rejected, err := b.rejectShortOrder(order, order.Price); rejected {
+				return err
+			}
+
+			if b.limitFills(order) {
+				return b.executeMarketOrder(order)
+			}
+
+			return b.restOrder(order)
+		}
+
 		// For buy orders, check if quantity * price exceeds buying power
 		if order.Side == types.PurchaseTypeBuy {
 			// Check if we can afford this order
 			totalCost := order.Quantity * order.Price
-			if totalCost > b.balance {
+			if buyingPower := b.orderBuyingPower(order); totalCost > buyingPower {
 				failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonInsufficientBuyPower,
-					fmt.Sprintf("limit buy order cost (%.2f) exceeds available balance (%.2f)", totalCost, b.balance))
+					fmt.Sprintf("limit buy order cost (%.2f) exceeds buying power (%.2f)", totalCost, buyingPower))
 
 				return b.state.StoreFailedOrder(failedOrder)
 			}
 
-			// If current price is already below limit price, execute immediately with the current market price
-			if b.marketData.Low <= order.Price {
+			// If current price has already reached the limit price, execute immediately with the current market price
+			if b.limitFills(order) {
 				// Modify the order to use current market price if lower than limit price
 				marketOrder := order
 				// We'll let executeMarketOrder set the appropriate price
@@ -194,14 +515,12 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 			}
 
 			// Otherwise, add to pending orders
-			b.pendingOrders = append(b.pendingOrders, order)
-
-			return nil
+			return b.restOrder(order)
 		}
 
 		// For limit sell orders, check if the quantity exceeds current holdings
 		if order.Side == types.PurchaseTypeSell {
-			sellingPower := b.getSellingPower()
+			sellingPower := b.orderSellingPower(order)
 
 			// If trying to sell more than available, fail the order
 			if order.Quantity > sellingPower {
@@ -211,15 +530,13 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 				return b.state.StoreFailedOrder(failedOrder)
 			}
 
-			// If current price is already above limit price, execute immediately with the limit price
-			if b.marketData.High >= order.Price {
+			// If current price has already reached the limit price, execute immediately with the limit price
+			if b.limitFills(order) {
 				return b.executeMarketOrder(order)
 			}
 
 			// Otherwise, add to pending orders
-			b.pendingOrders = append(b.pendingOrders, order)
-
-			return nil
+			return b.restOrder(order)
 		}
 
 		return nil
@@ -227,28 +544,33 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 
 	// For market orders, execute immediately
 	if order.OrderType == types.OrderTypeMarket {
-		// Calculate average market price
-		avgPrice := (b.marketData.High + b.marketData.Low) / 2
+		// Calculate the market price under the fill price policy
+		avgPrice := b.marketFillPrice(order)
 
 		if avgPrice <= 0 {
-			return errors.New(errors.ErrCodeInvalidParameter, "invalid market data: average price is zero or negative")
+			return errors.New(errors.ErrCodeInvalidParameter, "invalid market data: fill price is zero or negative")
 		}
 
 		// Set the order price to the average price
 		order.Price = avgPrice
 
-		// For buy orders, check if we can afford this order
-		if order.Side == types.PurchaseTypeBuy {
+		// Short sells and covers are checked against margin and the borrowed quantity
+		if order.PositionType == types.PositionTypeShort {
+			if rejected, err := b.rejectShortOrder(order, avgPrice); rejected {
+				return err
+			}
+		} else if order.Side == types.PurchaseTypeBuy {
+			// For buy orders, check if we can afford this order
 			totalCost := order.Quantity * avgPrice
-			if totalCost > b.balance {
+			if buyingPower := b.orderBuyingPower(order); totalCost > buyingPower {
 				failedOrder := b.createFailedOrder(order, avgPrice, types.OrderReasonInsufficientBuyPower,
-					fmt.Sprintf("market buy order cost (%.2f) exceeds available balance (%.2f)", totalCost, b.balance))
+					fmt.Sprintf("market buy order cost (%.2f) exceeds buying power (%.2f)", totalCost, buyingPower))
 
 				return b.state.StoreFailedOrder(failedOrder)
 			}
 		} else {
 			// For sell orders, fail if quantity exceeds selling power
-			sellingPower := b.getSellingPower()
+			sellingPower := b.orderSellingPower(order)
 			if order.Quantity > sellingPower {
 				failedOrder := b.createFailedOrder(order, avgPrice, types.OrderReasonInsufficientSellPower,
 					fmt.Sprintf("order quantity (%.2f) exceeds selling power (%.2f)", order.Quantity, sellingPower))
@@ -267,17 +589,23 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 
 		// Create a limit order for take profit
 		tpOrder := types.ExecuteOrder{
-			ID:           uuid.New().String(),
-			Symbol:       order.Symbol,
-			Side:         takeProfitOrder.Side,
-			OrderType:    types.OrderTypeLimit,
-			Reason:       types.Reason{Reason: types.OrderReasonTakeProfit, Message: "Take profit order"},
-			Price:        order.Price, // This needs to be set by the caller based on the take profit level
-			StrategyName: order.StrategyName,
-			Quantity:     order.Quantity,
-			PositionType: order.PositionType,
-			TakeProfit:   optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
-			StopLoss:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+			ID:             uuid.New().String(),
+			Symbol:         order.Symbol,
+			Side:           takeProfitOrder.Side,
+			OrderType:      types.OrderTypeLimit,
+			Reason:         types.Reason{Reason: types.OrderReasonTakeProfit, Message: "Take profit order"},
+			Price:          order.Price, // This needs to be set by the caller based on the take profit level
+			StrategyName:   order.StrategyName,
+			Quantity:       order.Quantity,
+			PositionType:   order.PositionType,
+			StopPrice:      0,
+			TrailOffset:    0,
+			TrailPercent:   false,
+			FilledQuantity: 0,
+			TimeInForce:    types.TimeInForceGTC,
+			GoodTillDate:   time.Time{},
+			TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+			StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
 		}
 
 		// Add to pending orders
@@ -289,17 +617,23 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 
 		// Create a limit order for stop loss
 		slOrder := types.ExecuteOrder{
-			ID:           uuid.New().String(),
-			Symbol:       order.Symbol,
-			Side:         stopLossOrder.Side,
-			OrderType:    types.OrderTypeLimit,
-			Reason:       types.Reason{Reason: types.OrderReasonStopLoss, Message: "Stop loss order"},
-			Price:        order.Price, // This needs to be set by the caller based on the stop loss level
-			StrategyName: order.StrategyName,
-			Quantity:     order.Quantity,
-			PositionType: order.PositionType,
-			TakeProfit:   optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
-			StopLoss:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+			ID:             uuid.New().String(),
+			Symbol:         order.Symbol,
+			Side:           stopLossOrder.Side,
+			OrderType:      types.OrderTypeLimit,
+			Reason:         types.Reason{Reason: types.OrderReasonStopLoss, Message: "Stop loss order"},
+			Price:          order.Price, // This needs to be set by the caller based on the stop loss level
+			StrategyName:   order.StrategyName,
+			Quantity:       order.Quantity,
+			PositionType:   order.PositionType,
+			StopPrice:      0,
+			TrailOffset:    0,
+			TrailPercent:   false,
+			FilledQuantity: 0,
+			TimeInForce:    types.TimeInForceGTC,
+			GoodTillDate:   time.Time{},
+			TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+			StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
 		}
 
 		// Add to pending orders
@@ -311,7 +645,36 @@ func (b *BacktestTrading) PlaceOrder(order types.ExecuteOrder) error {
 
 func (b *BacktestTrading) Reset(initialBalance float64) {
 	b.pendingOrders = []types.ExecuteOrder{}
+	b.signalConfirmations = map[string]*signalConfirmation{}
+	b.benchmarkPrice = 0
+	b.benchmarkTime = time.Time{}
+	b.benchmarkEntries = map[string]benchmarkEntry{}
+	b.lastPrices = map[string]float64{}
+	b.entryBarTime = time.Time{}
+	b.entriesThisBar = 0
+	b.deferredEntries = []types.ExecuteOrder{}
+	b.ocoLegs = map[string]ocoLeg{}
+	b.brackets = nil
+	b.fundingNext = map[string]int{}
+	b.orderFees = map[string]orderFee{}
+	if b.sessionFlatten != nil {
+		b.sessionFlatten.reset()
+	}
+	if b.cooldown != nil {
+		b.cooldown.lastAccepted = map[string]time.Time{}
+	}
+	if b.dailyLimits != nil {
+		b.dailyLimits.reset()
+	}
+	b.positionOpenedAt = map[string]time.Time{}
+	if tracker, ok := b.commission.(commission_fee.VolumeTracker); ok {
+		tracker.Reset()
+	}
+	b.random().Seed(b.randomSeed)
 	b.balance = initialBalance
+	if b.state != nil {
+		b.state.SetInitialBalance(initialBalance)
+	}
 	b.marketData = types.MarketData{
 		Id:     "",
 		Symbol: "",
@@ -321,60 +684,66 @@ func (b *BacktestTrading) Reset(initialBalance float64) {
 		Low:    0,
 		Close:  0,
 		Volume: 0,
+		Bid:    0,
+		Ask:    0,
 	}
 }
 
+// SetRandomSeed seeds the random source of the stochastic fill models. The
+// source is reseeded on Reset, so every run draws the same sequence and a run
+// with the same config, data and seed produces the same trades, apart from the
+// randomly generated order IDs.
+func (b *BacktestTrading) SetRandomSeed(seed int64) {
+	b.randomSeed = seed
+	b.random().Seed(seed)
+}
+
 // GetAccountInfo implements tradingprovider.TradingSystemProvider.
 // Returns the current account state including balance, equity, and P&L information.
+// The balance is the cash the fills and funding payments left of the initial
+// balance, and the equity adds the market value of the open positions, the same
+// way the strategy sub-accounts are valued, so they sum to the global account.
 func (b *BacktestTrading) GetAccountInfo() (types.AccountInfo, error) {
 	positions, err := b.state.GetAllPositions()
 	if err != nil {
 		return types.AccountInfo{}, err
 	}
 
-	var realizedPnL, unrealizedPnL, totalFees float64
+	realizedPnL, unrealizedPnL, notional := valuePositions(positions, b.markPrice)
 
-	for _, pos := range positions {
-		// Add realized PnL from this position
-		realizedPnL += pos.GetTotalPnL()
-
-		// Calculate unrealized PnL for open long positions
-		if pos.TotalLongPositionQuantity > 0 {
-			currentPrice := b.marketData.Close
-			if currentPrice == 0 {
-				currentPrice = (b.marketData.High + b.marketData.Low) / 2
-			}
-
-			avgEntry := pos.GetAverageLongPositionEntryPrice()
-			unrealizedPnL += (currentPrice - avgEntry) * pos.TotalLongPositionQuantity
-		}
-
-		// Calculate unrealized PnL for open short positions
-		if pos.TotalShortPositionQuantity > 0 {
-			currentPrice := b.marketData.Close
-			if currentPrice == 0 {
-				currentPrice = (b.marketData.High + b.marketData.Low) / 2
-			}
+	// Fees are summed over all trades so that closed positions are included
+	totalFees, err := b.state.GetTotalFees()
+	if err != nil {
+		return types.AccountInfo{}, err
+	}
 
-			avgEntry := pos.GetAverageShortPositionEntryPrice()
-			unrealizedPnL += (avgEntry - currentPrice) * pos.TotalShortPositionQuantity
-		}
+	// Funding payments of perpetual futures are realized when they are settled
+	totalFunding, err := b.state.GetTotalFunding()
+	if err != nil {
+		return types.AccountInfo{}, err
+	}
 
-		// Accumulate total fees
-		totalFees += pos.TotalLongInFee + pos.TotalLongOutFee + pos.TotalShortInFee + pos.TotalShortOutFee
+	cash, err := b.state.GetCashBalance()
+	if err != nil {
+		return types.AccountInfo{}, err
 	}
 
-	equity := b.balance + unrealizedPnL
+	marginUsed := b.usedMargin(notional)
+	freeMargin := b.marginEquity(realizedPnL, unrealizedPnL) - marginUsed
+	realizedPnL += totalFunding
+
 	buyingPower := b.getBuyingPower()
 
 	return types.AccountInfo{
-		Balance:       b.balance,
-		Equity:        equity,
+		Balance:       cash,
+		Equity:        cash + positionsValue(positions, b.markPrice),
 		BuyingPower:   buyingPower,
 		RealizedPnL:   realizedPnL,
 		UnrealizedPnL: unrealizedPnL,
 		TotalFees:     totalFees,
-		MarginUsed:    0, // Not implemented for backtesting
+		MarginUsed:    marginUsed,
+		FreeMargin:    freeMargin,
+		Balances:      nil,
 	}, nil
 }
 
@@ -436,6 +805,15 @@ func (b *BacktestTrading) GetOpenOrders() ([]types.ExecuteOrder, error) {
 // GetTrades implements tradingprovider.TradingSystemProvider.
 // Returns executed trades with optional filtering by symbol, time range, and limit.
 func (b *BacktestTrading) GetTrades(filter types.TradeFilter) ([]types.Trade, error) {
+	if filter.Cursor != "" {
+		page, err := b.GetTradesPage(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return page.Trades, nil
+	}
+
 	allTrades, err := b.state.GetAllTrades()
 	if err != nil {
 		return nil, err
@@ -470,7 +848,15 @@ func (b *BacktestTrading) GetTrades(filter types.TradeFilter) ([]types.Trade, er
 	return filteredTrades, nil
 }
 
+// GetTradesPage implements tradingprovider.TradingSystemProvider.
+// Pages through the trades in execution order; the cursor is the id of the last trade returned.
+func (b *BacktestTrading) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	return b.state.GetTradesPage(filter)
+}
+
 func NewBacktestTrading(state *BacktestState, initialBalance float64, commission commission_fee.CommissionFee, decimalPrecision int) tradingprovider.TradingSystemProvider {
+	state.SetInitialBalance(initialBalance)
+
 	return &BacktestTrading{
 		state:   state,
 		balance: initialBalance,
@@ -483,10 +869,59 @@ func NewBacktestTrading(state *BacktestState, initialBalance float64, commission
 			Low:    0,
 			Close:  0,
 			Volume: 0,
+			Bid:    0,
+			Ask:    0,
+		},
+		pendingOrders:          []types.ExecuteOrder{},
+		commission:             commission,
+		decimalPrecision:       decimalPrecision,
+		symbolDecimalPrecision: nil,
+		roundingMode:           RoundingFloor,
+		lotSizes:               nil,
+		confirmationBars:       0,
+		signalConfirmations:    map[string]*signalConfirmation{},
+		benchmarkStop: BenchmarkRelativeStopConfig{
+			BenchmarkSymbol:     "",
+			MaxUnderperformance: 0,
 		},
-		pendingOrders:    []types.ExecuteOrder{},
-		commission:       commission,
-		decimalPrecision: decimalPrecision,
+		benchmarkPrice:   0,
+		benchmarkTime:    time.Time{},
+		benchmarkEntries: map[string]benchmarkEntry{},
+		lastPrices:       map[string]float64{},
+		entryThrottle: EntryThrottleConfig{
+			MaxEntriesPerBar: 0,
+			Policy:           EntryThrottlePolicyDrop,
+		},
+		entryBarTime:           time.Time{},
+		entriesThisBar:         0,
+		deferredEntries:        []types.ExecuteOrder{},
+		releasingEntry:         false,
+		sessionFlatten:         nil,
+		ocoLegs:                map[string]ocoLeg{},
+		brackets:               nil,
+		maxVolumeParticipation: 0,
+		slippage:               nil,
+		limitFill:              nil,
+		fillPrice:              FillPriceMid,
+		commissionBounds:       commission_fee.Config{Rate: 0, Tiers: nil, MakerRate: 0, TakerRate: 0, MinFee: 0, MaxFee: 0},
+		orderFees:              map[string]orderFee{},
+		randomSeed:             0,
+		rng:                    rand.New(rand.NewSource(0)),
+		cooldown:               nil,
+		dailyLimits:            nil,
+		maxOpenPositions:       0,
+		positionLimit:          nil,
+		atrSizing:              nil,
+		atr:                    nil,
+		minHoldingPeriod:       0,
+		positionOpenedAt:       map[string]time.Time{},
+		lastFailedOrderID:      "",
+		equityCurveEnabled:     true,
+		equityCurveInterval:    0,
+		fundingRates:           nil,
+		fundingNext:            map[string]int{},
+		margin:                 MarginConfig{Leverage: 0, MaintenanceMarginFraction: 0},
+		signalOnly:             false,
 	}
 }
 
@@ -497,13 +932,14 @@ func (b *BacktestTrading) GetMaxBuyQuantity(symbol string, price float64) (float
 		return 0, errors.New(errors.ErrCodeInvalidParameter, "price must be greater than zero")
 	}
 
-	if b.balance <= 0 {
+	buyingPower := b.getBuyingPower()
+	if buyingPower <= 0 {
 		return 0, nil
 	}
 
-	maxQty := utils.CalculateMaxQuantity(b.balance, price, b.commission)
+	maxQty := utils.CalculateMaxQuantity(buyingPower, price, b.commission)
 
-	return utils.RoundToDecimalPrecision(maxQty, b.decimalPrecision), nil
+	return b.floorQuantity(symbol, maxQty), nil
 }
 
 // GetMaxSellQuantity implements tradingprovider.TradingSystemProvider.
@@ -514,7 +950,23 @@ func (b *BacktestTrading) GetMaxSellQuantity(symbol string) (float64, error) {
 		return 0, nil
 	}
 
-	return utils.RoundToDecimalPrecision(position.TotalLongPositionQuantity, b.decimalPrecision), nil
+	return b.floorQuantity(symbol, position.TotalLongPositionQuantity), nil
+}
+
+// CalculatePositionSize implements tradingprovider.TradingSystemProvider.
+// The quantity is floored to the configured decimal precision.
+func (b *BacktestTrading) CalculatePositionSize(symbol string, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	accountInfo, err := b.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	quantity, err := tradingprovider.RiskPositionSize(accountInfo.Equity, entryPrice, stopPrice, riskFraction)
+	if err != nil {
+		return 0, err
+	}
+
+	return b.floorQuantity(symbol, quantity), nil
 }
 
 // CheckConnection implements tradingprovider.TradingSystemProvider.
@@ -529,9 +981,28 @@ func (b *BacktestTrading) SetOnStatusChange(_ tradingprovider.OnStatusChange) {
 	// No-op for backtest trading
 }
 
-// getBuyingPower returns the cash available for new purchases, in account currency.
-// Margin/leverage is not modeled in the backtest engine, so this equals the cash balance.
+// random returns the random source of the stochastic fill models.
+func (b *BacktestTrading) random() *rand.Rand {
+	if b.rng == nil {
+		b.rng = rand.New(rand.NewSource(b.randomSeed))
+	}
+
+	return b.rng
+}
+
+// precision returns the decimal precision of the quantities of symbol.
+func (b *BacktestTrading) precision(symbol string) int {
+	return utils.SymbolDecimalPrecision(b.symbolDecimalPrecision, symbol, b.decimalPrecision)
+}
+
+// getBuyingPower returns the notional available for new purchases, in account
+// currency. Without margin this is the cash balance; with margin it is the free
+// margin times the leverage.
 func (b *BacktestTrading) getBuyingPower() float64 {
+	if b.margin.enabled() {
+		return b.marginBuyingPower()
+	}
+
 	if b.balance <= 0 {
 		return 0
 	}
@@ -547,12 +1018,14 @@ func (b *BacktestTrading) getSellingPower() float64 {
 		return 0
 	}
 
-	return utils.RoundToDecimalPrecision(position.TotalLongPositionQuantity, b.decimalPrecision)
+	return b.floorQuantity(b.marketData.Symbol, position.TotalLongPositionQuantity)
 }
 
 // createFailedOrder creates a failed order with the given parameters.
 // This helper consolidates the repeated failed order creation logic.
 func (b *BacktestTrading) createFailedOrder(order types.ExecuteOrder, executePrice float64, reason string, message string) types.Order {
+	b.lastFailedOrderID = order.ID
+
 	return types.Order{
 		OrderID:      order.ID,
 		Symbol:       order.Symbol,
@@ -572,7 +1045,67 @@ func (b *BacktestTrading) createFailedOrder(order types.ExecuteOrder, executePri
 	}
 }
 
-// processPendingOrders processes all pending limit orders based on current market data.
+// signalKey identifies the signal behind an order so repeated requests on
+// consecutive bars can be matched regardless of the requested price or quantity.
+func signalKey(order types.ExecuteOrder) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s", order.StrategyName, order.Symbol, order.Side, order.PositionType, order.OrderType)
+}
+
+// confirmSignal records a request for the order and reports whether the signal has
+// persisted for the configured number of consecutive bars and may be placed.
+// The most recent request wins, so the order placed uses the latest price and quantity.
+func (b *BacktestTrading) confirmSignal(order types.ExecuteOrder) bool {
+	if b.confirmationBars <= 1 {
+		return true
+	}
+
+	key := signalKey(order)
+
+	confirmation, ok := b.signalConfirmations[key]
+	if !ok {
+		confirmation = &signalConfirmation{
+			order:            order,
+			bars:             0,
+			requestedThisBar: false,
+		}
+		b.signalConfirmations[key] = confirmation
+	}
+
+	confirmation.order = order
+
+	if !confirmation.requestedThisBar {
+		confirmation.bars++
+		confirmation.requestedThisBar = true
+	}
+
+	if confirmation.bars < b.confirmationBars {
+		return false
+	}
+
+	delete(b.signalConfirmations, key)
+
+	return true
+}
+
+// advanceSignalConfirmations moves the confirmations of the given symbol to a new bar.
+// Signals that were not requested on the previous bar did not persist and are dropped.
+func (b *BacktestTrading) advanceSignalConfirmations(symbol string) {
+	for key, confirmation := range b.signalConfirmations {
+		if confirmation.order.Symbol != symbol {
+			continue
+		}
+
+		if !confirmation.requestedThisBar {
+			delete(b.signalConfirmations, key)
+
+			continue
+		}
+
+		confirmation.requestedThisBar = false
+	}
+}
+
+// processPendingOrders processes all pending limit and stop orders based on current market data.
 func (b *BacktestTrading) processPendingOrders() {
 	if len(b.pendingOrders) == 0 {
 		return
@@ -580,11 +1113,23 @@ func (b *BacktestTrading) processPendingOrders() {
 
 	var remainingOrders []types.ExecuteOrder
 
-	var ordersToExecute []types.ExecuteOrder
+	var ordersToExecute []pendingFill
 
 	// Check each pending order to see if it can be executed with current market data
 	for _, order := range b.pendingOrders {
 		canExecute := false
+		// Limit orders that rested in pending orders add liquidity and fill as makers
+		liquidity := commission_fee.LiquidityTaker
+		if order.OrderType == types.OrderTypeLimit {
+			liquidity = commission_fee.LiquidityMaker
+		}
+
+		// Orders past their GoodTillDate are cancelled whichever symbol the bar is for
+		if b.goodTillDatePassed(order) {
+			_ = b.cancelExpiredOrder(order)
+
+			continue
+		}
 
 		// check if symbol matches current market data
 		if order.Symbol != b.marketData.Symbol {
@@ -594,49 +1139,87 @@ func (b *BacktestTrading) processPendingOrders() {
 			continue
 		}
 
-		// For limit buy orders, we execute if market price has fallen below or equal to the limit price
-		if order.Side == types.PurchaseTypeBuy && order.OrderType == types.OrderTypeLimit {
-			// Buy when price falls to or below limit price
-			if b.marketData.Low <= order.Price {
-				canExecute = true
-			}
+		// Trailing stops trigger on the stop trailed up to the previous bar, since a
+		// bar does not tell whether its high came before its low. Until then the
+		// stop follows this bar's best price.
+		if order.OrderType == types.OrderTypeTrailingStop && (order.StopPrice <= 0 || !b.stopTriggered(order)) {
+			order.StopPrice = b.trailStop(order)
+			remainingOrders = append(remainingOrders, order)
+
+			continue
 		}
 
-		// For limit sell orders, we execute if market price has risen above or equal to the limit price
-		if order.Side == types.PurchaseTypeSell && order.OrderType == types.OrderTypeLimit {
-			// Sell when price rises to or above limit price
-			if b.marketData.High >= order.Price {
-				canExecute = true
+		// Stop orders wait for their trigger; a triggered stop-limit then behaves
+		// like a limit order and stays pending (as a limit) until it can fill
+		if order.IsStopOrder() {
+			if !b.stopTriggered(order) {
+				remainingOrders = append(remainingOrders, order)
+
+				continue
+			}
+
+			if order.OrderType == types.OrderTypeStopLimit {
+				order.OrderType = types.OrderTypeLimit
 			}
 		}
 
-		// For market orders, always execute them when their symbol matches current market data
-		if order.OrderType == types.OrderTypeMarket {
+		// Limit orders execute once the bar reaches the limit price under the configured fill policy
+		if order.OrderType == types.OrderTypeLimit && b.limitFills(order) {
 			canExecute = true
 		}
 
-		if canExecute {
-			ordersToExecute = append(ordersToExecute, order)
-		} else {
+		// For market orders and triggered stop-market and trailing stop orders, always execute them when their symbol matches current market data
+		if order.OrderType == types.OrderTypeMarket || order.OrderType == types.OrderTypeStopMarket ||
+			order.OrderType == types.OrderTypeTrailingStop {
+			canExecute = true
+		}
+
+		switch {
+		case canExecute:
+			ordersToExecute = append(ordersToExecute, pendingFill{order: order, liquidity: liquidity})
+		case fillsImmediately(order):
+			// IOC and FOK orders that cannot fill on their first bar expire
+			_ = b.expireOrder(order)
+		default:
 			remainingOrders = append(remainingOrders, order)
 		}
 	}
 
+	// Fill at most one leg of each OCO group and cancel the other
+	ordersToExecute, remainingOrders = b.resolveOCOFills(ordersToExecute, remainingOrders)
+
 	// Update the list of pending orders
 	b.pendingOrders = remainingOrders
 
 	// Execute the orders that can be executed
-	for _, order := range ordersToExecute {
+	for _, fill := range ordersToExecute {
 		// Execute the order with its original properties
 		// Ignore errors - if one order fails, try to execute the rest
-		_ = b.executeMarketOrder(order)
+		_ = b.executeOrder(fill.order, fill.liquidity, false)
 	}
+
+	// Protect the entries of bracket orders that have finished filling
+	b.armBrackets()
+}
+
+// pendingFill is a pending order that can be executed on the current bar.
+type pendingFill struct {
+	order     types.ExecuteOrder
+	liquidity commission_fee.Liquidity
 }
 
-// executeMarketOrder executes a market order immediately.
+// executeMarketOrder executes an order immediately as a taker fill. Limit
+// orders are filled as limits that were marketable when placed.
 func (b *BacktestTrading) executeMarketOrder(order types.ExecuteOrder) error {
+	return b.executeOrder(order, commission_fee.LiquidityTaker, true)
+}
+
+// executeOrder executes an order immediately, charging the commission for the
+// given liquidity. A marketable limit order fills at the fill price policy's
+// bar price bounded by its limit instead of at its limit.
+func (b *BacktestTrading) executeOrder(order types.ExecuteOrder, liquidity commission_fee.Liquidity, marketable bool) error {
 	// Validate the order (quantity, buying power, etc.)
-	order.Quantity = utils.RoundToDecimalPrecision(order.Quantity, b.decimalPrecision)
+	order.Quantity = b.floorQuantity(order.Symbol, order.Quantity)
 	if order.Quantity <= 0 {
 		return errors.New(errors.ErrCodeInvalidParameter, "order quantity is too small or zero after rounding to configured precision")
 	}
@@ -649,11 +1232,27 @@ func (b *BacktestTrading) executeMarketOrder(order types.ExecuteOrder) error {
 		return nil
 	}
 
+	// Fill at most the configured share of the bar volume; the rest stays pending
+	unfilled := order
+	order.Quantity = b.fillableQuantity(order)
+
+	// A FOK order that cannot fill its whole quantity on this bar is rejected
+	if order.TimeInForce == types.TimeInForceFOK && order.Quantity < b.unfilledQuantity(unfilled) {
+		return b.expireOrder(unfilled)
+	}
+
+	if order.Quantity <= 0 {
+		return b.keepUnfilledRemainder(unfilled, 0)
+	}
+
 	if order.OrderType == types.OrderTypeMarket {
-		// For market orders, always use the average price
-		executePrice = (b.marketData.High + b.marketData.Low) / 2
+		// For market orders, use the fill price policy's bar price moved by the configured slippage
+		executePrice = b.applySlippage(order, b.marketFillPrice(order))
 	} else if order.OrderType == types.OrderTypeLimit {
-		if order.Side == types.PurchaseTypeBuy {
+		if marketable {
+			// Limit orders that were marketable when placed fill like market orders, bounded by their limit
+			executePrice = b.marketableLimitFillPrice(order)
+		} else if order.Side == types.PurchaseTypeBuy {
 			// For buy limit orders, use the lower of limit price and current market price
 			executePrice = order.Price
 			avgPrice := (b.marketData.High + b.marketData.Low) / 2
@@ -665,6 +1264,9 @@ func (b *BacktestTrading) executeMarketOrder(order types.ExecuteOrder) error {
 			// For sell limit orders, use the limit price
 			executePrice = order.Price
 		}
+	} else if order.OrderType == types.OrderTypeStopMarket || order.OrderType == types.OrderTypeTrailingStop {
+		// For triggered stop-market and trailing stop orders, use the stop price (or the open on a gap)
+		executePrice = b.stopFillPrice(order)
 	}
 
 	if executePrice <= 0 {
@@ -672,16 +1274,20 @@ func (b *BacktestTrading) executeMarketOrder(order types.ExecuteOrder) error {
 	}
 
 	// Check buying/selling power again with final execution price
-	if order.Side == types.PurchaseTypeBuy {
+	if order.PositionType == types.PositionTypeShort {
+		if rejected, err := b.rejectShortOrder(order, executePrice); rejected {
+			return err
+		}
+	} else if order.Side == types.PurchaseTypeBuy {
 		totalCost := order.Quantity * executePrice
-		if totalCost > b.balance {
+		if buyingPower := b.orderBuyingPower(order); totalCost > buyingPower {
 			failedOrder := b.createFailedOrder(order, executePrice, types.OrderReasonInsufficientBuyPower,
-				fmt.Sprintf("order cost (%.2f) exceeds available balance (%.2f)", totalCost, b.balance))
+				fmt.Sprintf("order cost (%.2f) exceeds buying power (%.2f)", totalCost, buyingPower))
 
 			return b.state.StoreFailedOrder(failedOrder)
 		}
 	} else {
-		sellingPower := b.getSellingPower()
+		sellingPower := b.orderSellingPower(order)
 		if order.Quantity > sellingPower {
 			failedOrder := b.createFailedOrder(order, executePrice, types.OrderReasonInsufficientSellPower,
 				fmt.Sprintf("order quantity (%.2f) exceeds selling power (%.2f)", order.Quantity, sellingPower))
@@ -690,8 +1296,8 @@ func (b *BacktestTrading) executeMarketOrder(order types.ExecuteOrder) error {
 		}
 	}
 
-	// Calculate commission fee
-	commission := b.commission.Calculate(order.Quantity, executePrice)
+	// Calculate commission fee, bounded per order
+	commission := b.chargeCommission(order, executePrice, liquidity, order.Quantity >= b.unfilledQuantity(unfilled))
 
 	// Create the executed order
 	executedOrder := types.Order{
@@ -711,6 +1317,24 @@ func (b *BacktestTrading) executeMarketOrder(order types.ExecuteOrder) error {
 
 	// Update the order in the state
 	_, err := b.state.Update([]types.Order{executedOrder})
+	if err != nil {
+		return err
+	}
+
+	b.recordBracketFill(executedOrder.OrderID, executedOrder.Quantity)
+
+	if err := b.recordPositionOpening(executedOrder); err != nil {
+		return err
+	}
+
+	// Count the fill towards the volume of volume-tiered commission models
+	if tracker, ok := b.commission.(commission_fee.VolumeTracker); ok {
+		tracker.RecordFill(executedOrder.Quantity, executedOrder.Price, executedOrder.Timestamp)
+	}
+
+	if executedOrder.Side == types.PurchaseTypeBuy && executedOrder.PositionType == types.PositionTypeLong {
+		b.recordBenchmarkEntry(executedOrder.Symbol)
+	}
 
-	return err
+	return b.keepUnfilledRemainder(unfilled, executedOrder.Quantity)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/cache"
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/commission_fee"
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
+	"github.com/rxtech-lab/argo-trading/internal/clock"
 	"github.com/rxtech-lab/argo-trading/internal/indicator"
 	"github.com/rxtech-lab/argo-trading/internal/logger"
 	"github.com/rxtech-lab/argo-trading/internal/marker"
@@ -22,6 +23,7 @@ import (
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/internal/version"
 	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
 )
@@ -43,6 +45,11 @@ type BacktestEngineV1 struct {
 	balance             float64
 	cache               cache.Cache
 	logStorage          *BacktestLog
+	// lastRun holds the results of the most recent run for ExportResults. Each
+	// data file × strategy config run replaces it.
+	lastRun *runResults
+	// clock tells the wall-clock time used to name result folders and measure throughput.
+	clock clock.Clock
 }
 
 func NewBacktestEngineV1() (engine.Engine, error) {
@@ -68,6 +75,8 @@ func NewBacktestEngineV1() (engine.Engine, error) {
 		balance:             0,
 		cache:               cache.NewCacheV1(),
 		logStorage:          nil,
+		lastRun:             nil,
+		clock:               clock.New(),
 	}, nil
 }
 
@@ -79,6 +88,18 @@ func (b *BacktestEngineV1) Initialize(config string) error {
 		return err
 	}
 
+	if _, err := ResolveResultOutputFormats(b.config.OutputFormats); err != nil {
+		return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid output formats", err)
+	}
+
+	if _, err := NewMarkToMarketScheduler(b.config.MarkToMarket); err != nil {
+		return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid mark-to-market config", err)
+	}
+
+	if b.config.WarmupBars < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "warmup bars must not be negative: %d", b.config.WarmupBars)
+	}
+
 	b.log.Debug("Backtest engine initialized",
 		zap.String("config", config),
 	)
@@ -94,6 +115,7 @@ func (b *BacktestEngineV1) Initialize(config string) error {
 	b.indicatorRegistry.RegisterIndicator(indicator.NewMA())
 	b.indicatorRegistry.RegisterIndicator(indicator.NewWR())
 	b.indicatorRegistry.RegisterIndicator(indicator.NewPSY())
+	b.indicatorRegistry.RegisterIndicator(indicator.NewVWAP())
 
 	// initialize the state
 	b.state, err = NewBacktestState(b.log)
@@ -120,11 +142,94 @@ func (b *BacktestEngineV1) Initialize(config string) error {
 		commissionFee = commission_fee.NewZeroCommissionFee()
 	case commission_fee.BrokerBinance:
 		commissionFee = commission_fee.NewBinanceCommissionFee()
+	case commission_fee.BrokerPercentage, commission_fee.BrokerTiered, commission_fee.BrokerMakerTaker:
+		commissionFee = commission_fee.NewCommissionFee(b.config.Broker, b.config.Commission)
 	default:
 		commissionFee = commission_fee.NewInteractiveBrokerCommissionFee()
 	}
 
-	b.tradingSystem = NewBacktestTrading(b.state, b.config.InitialCapital, commissionFee, b.config.DecimalPrecision)
+	backtestTrading := NewBacktestTrading(b.state, b.config.InitialCapital, commissionFee, b.config.DecimalPrecision)
+	if trading, ok := backtestTrading.(*BacktestTrading); ok {
+		trading.SetSignalConfirmationBars(b.config.SignalConfirmationBars)
+		trading.SetBenchmarkRelativeStop(b.config.BenchmarkRelativeStop.withDefaultSymbol(b.config.BenchmarkSymbol))
+		trading.SetEntryThrottle(b.config.EntryThrottle)
+		trading.SetRandomSeed(b.config.RandomSeed)
+		trading.SetSignalOnly(b.config.SignalOnly)
+
+		if err := trading.SetSymbolDecimalPrecision(b.config.SymbolDecimalPrecision); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid symbol decimal precision", err)
+		}
+
+		if err := trading.SetQuantityRounding(b.config.RoundingMode, b.config.LotSize); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid quantity rounding", err)
+		}
+
+		if err := trading.SetCooldown(b.config.Cooldown); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid cooldown config", err)
+		}
+
+		if err := trading.SetDailyLimits(b.config.MaxOrdersPerDay, b.config.MaxNotionalPerDay); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid daily limits", err)
+		}
+
+		if err := trading.SetMaxOpenPositions(b.config.MaxOpenPositions); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid max open positions", err)
+		}
+
+		if err := trading.SetPositionLimit(b.config.PositionLimit); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid position limit", err)
+		}
+
+		if err := trading.SetATRSizing(b.config.ATRSizing, b.atr); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid ATR sizing config", err)
+		}
+
+		if err := trading.SetMinHoldingPeriod(b.config.MinHoldingPeriod); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid min holding period", err)
+		}
+
+		if err := trading.SetStrategyAllocations(b.config.StrategyAllocations); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid strategy allocations", err)
+		}
+
+		if err := trading.SetEquityCurve(b.config.EquityCurve); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid equity curve config", err)
+		}
+
+		if err := trading.SetAutoFlatten(b.config.AutoFlatten); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid auto-flatten config", err)
+		}
+
+		if err := trading.SetMaxVolumeParticipation(b.config.MaxVolumeParticipation); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid max volume participation", err)
+		}
+
+		if err := trading.SetSlippage(b.config.Slippage); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid slippage config", err)
+		}
+
+		if err := trading.SetLimitFillPolicy(b.config.LimitFillPolicy); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid limit fill policy", err)
+		}
+
+		if err := trading.SetFillPricePolicy(b.config.FillPricePolicy); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid fill price policy", err)
+		}
+
+		if err := trading.SetCommissionBounds(b.config.Commission); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid commission bounds", err)
+		}
+
+		if err := trading.SetFundingRates(b.config.FundingRates); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid funding rates", err)
+		}
+
+		if err := trading.SetMargin(b.config.Margin); err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid margin config", err)
+		}
+	}
+
+	b.tradingSystem = backtestTrading
 
 	return nil
 }
@@ -309,6 +414,9 @@ type runIterationParams struct {
 	dataPath         string
 	callbacks        engine.LifecycleCallbacks
 	resultFolderPath string
+	// keepState skips the cleanup at the end of the run so the next run
+	// starts with the positions and cash this run ended with.
+	keepState bool
 }
 
 // Run implements engine.Engine.
@@ -318,43 +426,14 @@ func (b *BacktestEngineV1) Run(ctx context.Context, callbacks engine.LifecycleCa
 	}
 
 	// Create timestamped subfolder for this backtest session
-	timestamp := time.Now().Format("20060102_150405")
+	timestamp := b.clock.Now().Format("20060102_150405")
 	sessionFolder := filepath.Join(b.resultsFolder, timestamp)
 	os.MkdirAll(sessionFolder, 0755)
 	b.resultsFolder = sessionFolder
 
-	// Build config list from either file paths or direct content
-	type configItem struct {
-		name    string
-		content string
-	}
-
-	var configs []configItem
-
-	if len(b.strategyConfigs) > 0 {
-		for i, content := range b.strategyConfigs {
-			configs = append(configs, configItem{
-				name:    fmt.Sprintf("config_%d", i),
-				content: content,
-			})
-		}
-	} else {
-		for _, configPath := range b.strategyConfigPaths {
-			content, err := os.ReadFile(configPath)
-			if err != nil {
-				b.log.Error("Failed to read config",
-					zap.String("config", configPath),
-					zap.Error(err),
-				)
-
-				return err
-			}
-
-			configs = append(configs, configItem{
-				name:    configPath,
-				content: string(content),
-			})
-		}
+	configs, err := b.strategyConfigItems()
+	if err != nil {
+		return err
 	}
 
 	// Track any error for OnBacktestEnd callback
@@ -406,6 +485,7 @@ func (b *BacktestEngineV1) Run(ctx context.Context, callbacks engine.LifecycleCa
 					dataPath:         dataPath,
 					callbacks:        callbacks,
 					resultFolderPath: resultFolderPath,
+					keepState:        false,
 				}
 
 				if err := b.runSingleIteration(params); err != nil {
@@ -473,6 +553,7 @@ func (b *BacktestEngineV1) runSingleIteration(params runIterationParams) error {
 
 	strategyContext := runtime.RuntimeContext{
 		DataSource:        slidingWindowDS,
+		MarketDataCache:   slidingWindowDS.GetCache(),
 		IndicatorRegistry: b.indicatorRegistry,
 		Marker:            b.marker,
 		TradingSystem:     b.tradingSystem,
@@ -480,6 +561,7 @@ func (b *BacktestEngineV1) runSingleIteration(params runIterationParams) error {
 		Logger:            b.log,
 		LogStorage:        b.logStorage,
 		CurrentMarketData: nil,
+		Interval:          "",
 	}
 
 	// need to initialize the strategy api first since there is no wasm plugin available before this line
@@ -551,6 +633,10 @@ func (b *BacktestEngineV1) runSingleIteration(params runIterationParams) error {
 		(*params.callbacks.OnRunEnd)(params.configIdx, params.configName, params.dataIdx, params.dataPath, params.resultFolderPath)
 	}
 
+	if params.keepState {
+		return nil
+	}
+
 	// Cleanup state
 	if err := b.cleanUpRun(); err != nil {
 		return errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to cleanup run", err)
@@ -561,102 +647,304 @@ func (b *BacktestEngineV1) runSingleIteration(params runIterationParams) error {
 
 // processDataPoints processes all data points for a single run iteration.
 func (b *BacktestEngineV1) processDataPoints(params runIterationParams, strategyContext *runtime.RuntimeContext, slidingWindowDS *datasource.SlidingWindowDataSource, count int) error {
-	currentCount := 0
-	runStart := time.Now()
+	if b.config.SymbolWorkers > 1 {
+		if cloner, ok := params.strategy.(runtime.Cloner); ok {
+			return b.processDataPointsBySymbol(params, strategyContext, slidingWindowDS, count, cloner)
+		}
 
-	// Track insufficient data error state for marker boundaries
-	var (
-		inInsufficientDataError bool
-		lastInsufficientData    types.MarketData
-	)
+		b.log.Warn("Strategy runtime cannot be cloned per symbol, processing bars serially",
+			zap.String("strategy", params.strategy.Name()),
+			zap.Int("symbol_workers", b.config.SymbolWorkers),
+		)
+	}
+
+	bars, err := b.newBarBookkeeping(params, slidingWindowDS, count)
+	if err != nil {
+		return err
+	}
 
 	for data, err := range b.datasource.ReadAll(b.config.StartTime, b.config.EndTime) {
 		// Check for context cancellation
-		select {
-		case <-params.ctx.Done():
-			if cleanupErr := b.cleanUpRun(); cleanupErr != nil {
-				b.log.Error("Failed to cleanup run after cancellation",
-					zap.Error(cleanupErr),
-				)
-			}
-
-			return params.ctx.Err()
-		default:
+		if err := b.checkCancelled(params.ctx); err != nil {
+			return err
 		}
 
 		if err != nil {
 			return errors.Wrap(errors.ErrCodeDataNotFound, "failed to read data", err)
 		}
 
-		// Add market data to the sliding window cache for future lookups
-		slidingWindowDS.AddToCache(data)
+		if err := bars.before(data); err != nil {
+			return err
+		}
 
 		// run the strategy
 		if backtestTrading, ok := b.tradingSystem.(*BacktestTrading); ok {
-			backtestTrading.UpdateCurrentMarketData(data)
+			if err := backtestTrading.UpdateCurrentMarketData(data); err != nil {
+				return err
+			}
+		}
+
+		// Bars of the warm-up period only fill the cache indicators read from
+		if bars.warmingUp(data) {
+			if err := bars.reportProgress(); err != nil {
+				return err
+			}
+
+			continue
 		}
 
 		// Set current market data in strategy context for implicit log context
 		strategyContext.CurrentMarketData = &data
+		strategyContext.Interval = bars.interval()
 
 		// Process data and track insufficient data errors for markers
 		processErr := params.strategy.ProcessData(data)
 
-		if errors.IsInsufficientDataError(processErr) {
-			if !inInsufficientDataError {
-				// Transition: OK → Insufficient - mark beginning
-				b.markInsufficientDataStart(data)
+		if err := bars.after(data, processErr); err != nil {
+			return err
+		}
+	}
 
-				inInsufficientDataError = true
-			}
-			// Track the last data point with insufficient error for end marker
-			lastInsufficientData = data
-		} else {
-			if inInsufficientDataError {
-				// Transition: Insufficient → OK - mark end at last insufficient data point
-				b.markInsufficientDataEnd(lastInsufficientData)
-
-				inInsufficientDataError = false
-			}
+	return bars.finish()
+}
 
-			// Add error marker for non-insufficient errors (continue processing)
-			if processErr != nil {
-				b.markStrategyError(data, processErr)
-			}
+// checkCancelled cleans up the run and returns the context's error once ctx is done.
+func (b *BacktestEngineV1) checkCancelled(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		if cleanupErr := b.cleanUpRun(); cleanupErr != nil {
+			b.log.Error("Failed to cleanup run after cancellation",
+				zap.Error(cleanupErr),
+			)
 		}
 
-		// Update progress bar
-		currentCount++
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
 
-		// Invoke OnProcessData callback
-		if params.callbacks.OnProcessData != nil {
-			elapsed := time.Since(runStart).Seconds()
+// progressWindow is the number of recent bars the reported throughput averages over.
+const progressWindow = 100
+
+// barBookkeeping is the per-bar work of a run that surrounds the strategy call:
+// caching the bar, equity snapshots, error markers and progress callbacks.
+type barBookkeeping struct {
+	engine          *BacktestEngineV1
+	params          runIterationParams
+	slidingWindowDS *datasource.SlidingWindowDataSource
+	count           int
+	currentCount    int
+	runStart        time.Time
+
+	// lastReport is the time the previous bar was reported, or runStart
+	lastReport time.Time
+	// barDurations holds the durations of the most recent bars for the throughput
+	barDurations []time.Duration
+	// barDurationsSum is the sum of barDurations
+	barDurationsSum time.Duration
 
-			var barsPerSecond float64
-			if elapsed > 0 {
-				barsPerSecond = float64(currentCount) / elapsed
-			}
+	// Track insufficient data error state for marker boundaries
+	inInsufficientDataError bool
+	lastInsufficientData    types.MarketData
+
+	// Snapshot equity at the configured times of day, independent of bar frequency
+	markToMarket *MarkToMarketScheduler
+
+	// symbolBars counts the bars of each symbol for the warm-up period
+	symbolBars map[string]int
+	// lastBarTimes is the time of the latest bar of each symbol
+	lastBarTimes map[string]time.Time
+	// barLength is the shortest gap between consecutive bars of a symbol seen so far
+	barLength time.Duration
+}
 
-			var realizedPnL float64
-			if b.state != nil {
-				realizedPnL = b.state.GetRealizedPnL()
-			}
+// newBarBookkeeping returns the bookkeeping of a run over count bars.
+func (b *BacktestEngineV1) newBarBookkeeping(params runIterationParams, slidingWindowDS *datasource.SlidingWindowDataSource, count int) (*barBookkeeping, error) {
+	var markToMarket *MarkToMarketScheduler
 
-			info := engine.ProgressInfo{
-				Current:       currentCount,
-				Total:         count,
-				BarsPerSecond: barsPerSecond,
-				RealizedPnL:   realizedPnL,
-			}
-			if err := (*params.callbacks.OnProcessData)(info); err != nil {
-				return err
-			}
+	if b.config.MarkToMarket.Enabled() {
+		scheduler, err := NewMarkToMarketScheduler(b.config.MarkToMarket)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrCodeInvalidParameter, "invalid mark-to-market config", err)
+		}
+
+		markToMarket = scheduler
+	}
+
+	// lastInsufficientData is set on the first insufficient data error
+	var lastInsufficientData types.MarketData
+
+	runStart := b.clock.Now()
+
+	return &barBookkeeping{
+		engine:                  b,
+		params:                  params,
+		slidingWindowDS:         slidingWindowDS,
+		count:                   count,
+		currentCount:            0,
+		runStart:                runStart,
+		lastReport:              runStart,
+		barDurations:            make([]time.Duration, 0, progressWindow),
+		barDurationsSum:         0,
+		inInsufficientDataError: false,
+		lastInsufficientData:    lastInsufficientData,
+		markToMarket:            markToMarket,
+		symbolBars:              map[string]int{},
+		lastBarTimes:            map[string]time.Time{},
+		barLength:               0,
+	}, nil
+}
+
+// before runs ahead of the strategy call for data.
+func (k *barBookkeeping) before(data types.MarketData) error {
+	// Add market data to the sliding window cache for future lookups
+	k.slidingWindowDS.AddToCache(data)
+
+	if last, ok := k.lastBarTimes[data.Symbol]; ok {
+		if gap := data.Time.Sub(last); gap > 0 && (k.barLength == 0 || gap < k.barLength) {
+			k.barLength = gap
+		}
+	}
+
+	k.lastBarTimes[data.Symbol] = data.Time
+
+	// Record snapshots whose time has passed before this bar moves prices
+	if k.markToMarket != nil {
+		if err := k.engine.recordEquitySnapshots(k.markToMarket, k.markToMarket.Due(data.Time)); err != nil {
+			return err
 		}
+
+		k.markToMarket.Observe(data)
+	}
+
+	return nil
+}
+
+// after runs once the strategy has processed data and returned processErr.
+func (k *barBookkeeping) after(data types.MarketData, processErr error) error {
+	b := k.engine
+
+	if errors.IsInsufficientDataError(processErr) {
+		if !k.inInsufficientDataError {
+			// Transition: OK → Insufficient - mark beginning
+			b.markInsufficientDataStart(data)
+
+			k.inInsufficientDataError = true
+		}
+		// Track the last data point with insufficient error for end marker
+		k.lastInsufficientData = data
+	} else {
+		if k.inInsufficientDataError {
+			// Transition: Insufficient → OK - mark end at last insufficient data point
+			b.markInsufficientDataEnd(k.lastInsufficientData)
+
+			k.inInsufficientDataError = false
+		}
+
+		// Add error marker for non-insufficient errors (continue processing)
+		if processErr != nil {
+			b.markStrategyError(data, processErr)
+		}
+	}
+
+	return k.reportProgress()
+}
+
+// warmingUp counts data and reports whether it falls in the warm-up period of
+// its symbol, during which the strategy is not called.
+func (k *barBookkeeping) warmingUp(data types.MarketData) bool {
+	k.symbolBars[data.Symbol]++
+
+	return k.symbolBars[data.Symbol] <= k.engine.config.WarmupBars
+}
+
+// interval is the candle interval of the data, the shortest gap between two
+// bars of a symbol so far. It is empty until a symbol has had a second bar.
+func (k *barBookkeeping) interval() string {
+	return provider.FormatInterval(k.barLength)
+}
+
+// reportProgress counts a processed bar and invokes the OnProcessData callback.
+func (k *barBookkeeping) reportProgress() error {
+	b := k.engine
+
+	// Update progress bar
+	k.currentCount++
+
+	now := b.clock.Now()
+	barsPerSecond := k.recordBarDuration(now.Sub(k.lastReport))
+	k.lastReport = now
+
+	// Invoke OnProcessData callback
+	if k.params.callbacks.OnProcessData != nil {
+		var remaining time.Duration
+		if barsPerSecond > 0 {
+			remaining = time.Duration(float64(k.count-k.currentCount) / barsPerSecond * float64(time.Second))
+		}
+
+		var realizedPnL float64
+		if b.state != nil {
+			realizedPnL = b.state.GetRealizedPnL()
+		}
+
+		info := engine.ProgressInfo{
+			Current:       k.currentCount,
+			Total:         k.count,
+			BarsPerSecond: barsPerSecond,
+			Elapsed:       now.Sub(k.runStart),
+			Remaining:     max(remaining, 0),
+			RealizedPnL:   realizedPnL,
+		}
+		if err := (*k.params.callbacks.OnProcessData)(info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordBarDuration adds the time a bar took to the moving window of recent
+// bars and returns the throughput over the window.
+func (k *barBookkeeping) recordBarDuration(duration time.Duration) float64 {
+	if len(k.barDurations) == progressWindow {
+		k.barDurationsSum -= k.barDurations[0]
+		k.barDurations = k.barDurations[1:]
 	}
 
+	k.barDurations = append(k.barDurations, duration)
+	k.barDurationsSum += duration
+
+	if k.barDurationsSum <= 0 {
+		return 0
+	}
+
+	return float64(len(k.barDurations)) / k.barDurationsSum.Seconds()
+}
+
+// finish runs after the last bar.
+func (k *barBookkeeping) finish() error {
 	// If we ended in an insufficient data error state, mark the end
-	if inInsufficientDataError {
-		b.markInsufficientDataEnd(lastInsufficientData)
+	if k.inInsufficientDataError {
+		k.engine.markInsufficientDataEnd(k.lastInsufficientData)
+	}
+
+	if k.markToMarket != nil {
+		if err := k.engine.recordEquitySnapshots(k.markToMarket, k.markToMarket.Flush()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordEquitySnapshots stores an equity snapshot for each time, valued at the
+// closes the scheduler has observed so far.
+func (b *BacktestEngineV1) recordEquitySnapshots(scheduler *MarkToMarketScheduler, times []time.Time) error {
+	for _, t := range times {
+		if _, err := b.state.RecordEquitySnapshot(t, scheduler.Closes()); err != nil {
+			return errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to record equity snapshot", err)
+		}
 	}
 
 	return nil
@@ -676,6 +964,7 @@ func (b *BacktestEngineV1) markInsufficientDataStart(data types.MarketData) {
 		Title:        "Insufficient Data",
 		Message:      "Insufficient data error started",
 		Category:     "InsufficientData",
+		StrategyName: "",
 		Signal: optional.Some(types.Signal{
 			Time:      data.Time,
 			Symbol:    data.Symbol,
@@ -708,6 +997,7 @@ func (b *BacktestEngineV1) markInsufficientDataEnd(data types.MarketData) {
 		Title:        "Insufficient Data",
 		Message:      "Insufficient data error ended",
 		Category:     "InsufficientData",
+		StrategyName: "",
 		Signal: optional.Some(types.Signal{
 			Time:      data.Time,
 			Symbol:    data.Symbol,
@@ -740,6 +1030,7 @@ func (b *BacktestEngineV1) markStrategyError(data types.MarketData, strategyErr
 		Title:        "Strategy Error",
 		Message:      strategyErr.Error(),
 		Category:     "StrategyError",
+		StrategyName: "",
 		Signal: optional.Some(types.Signal{
 			Time:      data.Time,
 			Symbol:    data.Symbol,
@@ -792,6 +1083,10 @@ func (b *BacktestEngineV1) writeResults(strategyContext runtime.RuntimeContext,
 		stats[i].StrategyConfig = strategyConfigNode
 	}
 
+	if err := b.captureRunResults(stats, resultFolderPath); err != nil {
+		return err
+	}
+
 	// Write stats to file
 	if err := types.WriteTradeStats(filepath.Join(resultFolderPath, "stats.yaml"), stats); err != nil {
 		return errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to write stats", err)
@@ -802,24 +1097,37 @@ func (b *BacktestEngineV1) writeResults(strategyContext runtime.RuntimeContext,
 		return errors.New(errors.ErrCodeBacktestStateNil, "backtest state is nil")
 	}
 
-	if err := b.state.Write(stateDBPath); err != nil {
+	outputFormats, err := ResolveResultOutputFormats(b.config.OutputFormats)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid output formats", err)
+	}
+
+	if err := b.state.WriteFormats(stateDBPath, outputFormats); err != nil {
 		return errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to write state", err)
 	}
 
 	// write the marker to disk
 	if marker, ok := b.marker.(*BacktestMarker); ok {
-		if err := marker.Write(resultFolderPath); err != nil {
+		if err := marker.WriteFormats(resultFolderPath, outputFormats); err != nil {
 			return errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to write marker", err)
 		}
 	}
 
 	// write the logs to disk
 	if b.logStorage != nil {
-		if err := b.logStorage.Write(resultFolderPath); err != nil {
+		if err := b.logStorage.WriteFormats(resultFolderPath, outputFormats); err != nil {
 			return errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to write logs", err)
 		}
 	}
 
+	// Optionally copy the run's results into a user-supplied database for
+	// external analysis.
+	if b.config.ResultsExportPath != "" {
+		if err := ExportResultsToDatabase(b.config.ResultsExportPath, runID, b.lastRun); err != nil {
+			return errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to export results", err)
+		}
+	}
+
 	return nil
 }
 
@@ -890,3 +1198,45 @@ func (b *BacktestEngineV1) preRunCheck() error {
 
 	return nil
 }
+
+// configItem is a strategy config with the name its results are filed under.
+type configItem struct {
+	name    string
+	content string
+}
+
+// strategyConfigItems builds the config list from either the direct content
+// or the config file paths.
+func (b *BacktestEngineV1) strategyConfigItems() ([]configItem, error) {
+	var configs []configItem
+
+	if len(b.strategyConfigs) > 0 {
+		for i, content := range b.strategyConfigs {
+			configs = append(configs, configItem{
+				name:    fmt.Sprintf("config_%d", i),
+				content: content,
+			})
+		}
+
+		return configs, nil
+	}
+
+	for _, configPath := range b.strategyConfigPaths {
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			b.log.Error("Failed to read config",
+				zap.String("config", configPath),
+				zap.Error(err),
+			)
+
+			return nil, err
+		}
+
+		configs = append(configs, configItem{
+			name:    configPath,
+			content: string(content),
+		})
+	}
+
+	return configs, nil
+}
@@ -12,6 +12,7 @@ import (
 
 	engine_types "github.com/rxtech-lab/argo-trading/internal/backtest/engine"
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/commission_fee"
+	"github.com/rxtech-lab/argo-trading/internal/clock"
 	"github.com/rxtech-lab/argo-trading/internal/logger"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/internal/version"
@@ -32,7 +33,7 @@ func findTimestampFolder(t *testing.T, resultsDir string) string {
 }
 
 // setTestVersion sets the version for testing and returns via t.Cleanup.
-func setTestVersion(t *testing.T, v string) {
+func setTestVersion(t testing.TB, v string) {
 	originalVersion := version.Version
 	version.Version = v
 	t.Cleanup(func() {
@@ -2464,3 +2465,173 @@ func TestStrategyErrorMarkers(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestBacktestEngineV1_WarmupBars(t *testing.T) {
+	t.Run("Strategy is called from the bar after the warm-up period", func(t *testing.T) {
+		setTestVersion(t, "1.0.0")
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStrategy := mocks.NewMockStrategyRuntime(ctrl)
+		mockDatasource := mocks.NewMockDataSource(ctrl)
+
+		tempDir := t.TempDir()
+
+		start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+
+		var bars []types.MarketData
+		for i := range 5 {
+			bars = append(bars, types.MarketData{
+				Id:     fmt.Sprintf("bar-%d", i),
+				Symbol: "TEST",
+				Time:   start.Add(time.Duration(i) * time.Minute),
+				Open:   100,
+				High:   101,
+				Low:    99,
+				Close:  100 + float64(i),
+				Volume: 1000,
+			})
+		}
+
+		mockStrategy.EXPECT().Name().Return("TestStrategy").AnyTimes()
+		mockStrategy.EXPECT().InitializeApi(gomock.Any()).Return(nil).AnyTimes()
+		mockStrategy.EXPECT().Initialize(gomock.Any()).Return(nil).AnyTimes()
+		mockStrategy.EXPECT().GetRuntimeEngineVersion().Return("1.0.0", nil).AnyTimes()
+		mockStrategy.EXPECT().GetIdentifier().Return("com.test.mock", nil).AnyTimes()
+		// The first three bars only fill the cache
+		gomock.InOrder(
+			mockStrategy.EXPECT().ProcessData(bars[3]).Return(nil),
+			mockStrategy.EXPECT().ProcessData(bars[4]).Return(nil),
+		)
+
+		mockDatasource.EXPECT().Initialize(gomock.Any()).Return(nil).AnyTimes()
+		mockDatasource.EXPECT().Count(gomock.Any(), gomock.Any()).Return(len(bars), nil).AnyTimes()
+		mockDatasource.EXPECT().GetAllSymbols().Return([]string{"TEST"}, nil).AnyTimes()
+		mockDatasource.EXPECT().ReadLastData(gomock.Any()).Return(bars[4], nil).AnyTimes()
+		mockDatasource.EXPECT().ReadAll(gomock.Any(), gomock.Any()).Return(func(yield func(types.MarketData, error) bool) {
+			for _, bar := range bars {
+				if !yield(bar, nil) {
+					return
+				}
+			}
+		}).AnyTimes()
+
+		engine, err := NewBacktestEngineV1()
+		require.NoError(t, err)
+		backtestEngine := engine.(*BacktestEngineV1)
+
+		err = backtestEngine.Initialize("initial_capital: 10000\nwarmup_bars: 3\n")
+		require.NoError(t, err)
+
+		backtestEngine.LoadStrategy(mockStrategy)
+		backtestEngine.SetDataSource(mockDatasource)
+		require.NoError(t, backtestEngine.SetConfigContent([]string{""}))
+
+		backtestEngine.dataPaths = []string{filepath.Join(tempDir, "data_path")}
+		backtestEngine.SetResultsFolder(tempDir)
+
+		var progress []int
+		onProcessData := engine_types.OnProcessDataCallback(func(info engine_types.ProgressInfo) error {
+			progress = append(progress, info.Current)
+			return nil
+		})
+
+		err = backtestEngine.Run(context.Background(), engine_types.LifecycleCallbacks{OnProcessData: &onProcessData})
+		require.NoError(t, err)
+
+		// Warm-up bars still count towards the progress
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, progress)
+	})
+
+	t.Run("Negative warm-up bars are rejected", func(t *testing.T) {
+		engine, err := NewBacktestEngineV1()
+		require.NoError(t, err)
+
+		err = engine.Initialize("warmup_bars: -1")
+		assert.Error(t, err)
+	})
+}
+
+func TestBarBookkeeping_ReportProgressUsesClock(t *testing.T) {
+	eng, err := NewBacktestEngineV1()
+	require.NoError(t, err)
+
+	b := eng.(*BacktestEngineV1)
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b.clock = fake
+
+	var reported []engine_types.ProgressInfo
+
+	onProcessData := engine_types.OnProcessDataCallback(func(info engine_types.ProgressInfo) error {
+		reported = append(reported, info)
+
+		return nil
+	})
+
+	bars, err := b.newBarBookkeeping(runIterationParams{callbacks: engine_types.LifecycleCallbacks{OnProcessData: &onProcessData}}, nil, 10)
+	require.NoError(t, err)
+
+	// No time has passed yet, so there is no throughput to report
+	require.NoError(t, bars.reportProgress())
+
+	fake.Advance(2 * time.Second)
+	require.NoError(t, bars.reportProgress())
+
+	fake.Advance(2 * time.Second)
+	require.NoError(t, bars.reportProgress())
+	require.NoError(t, bars.reportProgress())
+
+	require.Len(t, reported, 4)
+	assert.Equal(t, 0.0, reported[0].BarsPerSecond)
+	assert.Equal(t, 1.0, reported[1].BarsPerSecond)
+	assert.Equal(t, 1.0, reported[3].BarsPerSecond)
+	assert.Equal(t, 4, reported[3].Current)
+	assert.Equal(t, 4*time.Second, reported[3].Elapsed)
+	assert.Equal(t, 6*time.Second, reported[3].Remaining)
+}
+
+func TestBarBookkeeping_ReportProgressEstimatesRemainingTime(t *testing.T) {
+	eng, err := NewBacktestEngineV1()
+	require.NoError(t, err)
+
+	b := eng.(*BacktestEngineV1)
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b.clock = fake
+
+	var reported []engine_types.ProgressInfo
+
+	onProcessData := engine_types.OnProcessDataCallback(func(info engine_types.ProgressInfo) error {
+		reported = append(reported, info)
+
+		return nil
+	})
+
+	total := 3 * progressWindow
+	bars, err := b.newBarBookkeeping(runIterationParams{callbacks: engine_types.LifecycleCallbacks{OnProcessData: &onProcessData}}, nil, total)
+	require.NoError(t, err)
+
+	// A slow first window of 1s bars, then bars of 100ms
+	for i := range total {
+		if i < progressWindow {
+			fake.Advance(time.Second)
+		} else {
+			fake.Advance(100 * time.Millisecond)
+		}
+
+		require.NoError(t, bars.reportProgress())
+	}
+
+	require.Len(t, reported, total)
+
+	for i := 1; i < total; i++ {
+		assert.Positive(t, reported[i].BarsPerSecond)
+		assert.Less(t, reported[i].Remaining, reported[i-1].Remaining, "bar %d", i+1)
+		assert.Greater(t, reported[i].Elapsed, reported[i-1].Elapsed)
+	}
+
+	// Once the slow bars have left the window the throughput is that of the fast bars
+	last := reported[total-1]
+	assert.InDelta(t, 10.0, reported[2*progressWindow-1].BarsPerSecond, 1e-9)
+	assert.Equal(t, time.Duration(0), last.Remaining)
+	assert.Equal(t, 120*time.Second, last.Elapsed)
+}
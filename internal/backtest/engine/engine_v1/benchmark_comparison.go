@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// BenchmarkComparison compares the return of a run against buying the
+// benchmark symbol with the full initial balance at its first bar and holding
+// it to its last bar. Returns are fractions of the initial balance.
+type BenchmarkComparison struct {
+	Symbol         string
+	InitialBalance float64
+	StartTime      time.Time
+	StartPrice     float64
+	EndTime        time.Time
+	EndPrice       float64
+	// BenchmarkFinalValue is the value of the buy-and-hold position at the last bar.
+	BenchmarkFinalValue float64
+	StrategyReturn      float64
+	BenchmarkReturn     float64
+	// Alpha is StrategyReturn minus BenchmarkReturn.
+	Alpha float64
+}
+
+// newBenchmarkComparison compares a run that made strategyPnL on initialBalance
+// against holding the benchmark bought at the close of first and valued at the
+// close of last. Fees are not charged on the benchmark.
+func newBenchmarkComparison(initialBalance, strategyPnL float64, first, last types.MarketData) BenchmarkComparison {
+	comparison := BenchmarkComparison{
+		Symbol:              first.Symbol,
+		InitialBalance:      initialBalance,
+		StartTime:           first.Time,
+		StartPrice:          first.Close,
+		EndTime:             last.Time,
+		EndPrice:            last.Close,
+		BenchmarkFinalValue: 0,
+		StrategyReturn:      0,
+		BenchmarkReturn:     0,
+		Alpha:               0,
+	}
+
+	if initialBalance != 0 {
+		comparison.StrategyReturn = strategyPnL / initialBalance
+	}
+
+	if first.Close > 0 {
+		comparison.BenchmarkFinalValue = initialBalance / first.Close * last.Close
+		comparison.BenchmarkReturn = last.Close/first.Close - 1
+	}
+
+	comparison.Alpha = comparison.StrategyReturn - comparison.BenchmarkReturn
+
+	return comparison
+}
+
+// compareWithBenchmark computes the benchmark comparison of the run that
+// produced stats. It returns nil when no benchmark symbol is configured, or
+// when the dataset has no bars of it in the backtest period.
+func (b *BacktestEngineV1) compareWithBenchmark(stats []types.TradeStats) (*BenchmarkComparison, error) {
+	symbol := b.config.BenchmarkSymbol
+	if symbol == "" {
+		return nil, nil
+	}
+
+	var first, last types.MarketData
+
+	found := false
+
+	for data, err := range b.datasource.ReadAll(b.config.StartTime, b.config.EndTime) {
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrCodeDataNotFound, "failed to read benchmark data", err)
+		}
+
+		if data.Symbol != symbol {
+			continue
+		}
+
+		if !found {
+			first = data
+			found = true
+		}
+
+		last = data
+	}
+
+	if !found {
+		b.log.Warn("Benchmark symbol has no data in the backtest period, skipping the benchmark comparison",
+			zap.String("benchmark_symbol", symbol),
+		)
+
+		return nil, nil
+	}
+
+	// Every symbol's stats share the run's initial balance, while each holds
+	// the PnL of its own symbol
+	strategyPnL := 0.0
+	for _, symbolStats := range stats {
+		strategyPnL += symbolStats.TradePnl.TotalPnL
+	}
+
+	comparison := newBenchmarkComparison(b.config.InitialCapital, strategyPnL, first, last)
+
+	return &comparison, nil
+}
@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// BenchmarkRelativeStopConfig configures a stop that exits a long position when
+// its return since entry lags the benchmark's return over the same period by
+// more than MaxUnderperformance. The benchmark series is read from the market
+// data of BenchmarkSymbol, so the dataset must contain that symbol.
+type BenchmarkRelativeStopConfig struct {
+	BenchmarkSymbol     string  `yaml:"benchmark_symbol" json:"benchmark_symbol" jsonschema:"title=Benchmark Symbol,description=Symbol in the dataset whose close prices form the benchmark series. Leave empty to use the top-level benchmark_symbol."`
+	MaxUnderperformance float64 `yaml:"max_underperformance" json:"max_underperformance" jsonschema:"title=Max Underperformance,description=Maximum allowed lag of the position return behind the benchmark return since entry as a decimal fraction (e.g. 0.05 = 5 percentage points). The position is closed once the lag reaches this value. Set to 0 to disable the stop.,minimum=0"`
+}
+
+// Enabled reports whether the benchmark-relative stop is configured.
+func (c BenchmarkRelativeStopConfig) Enabled() bool {
+	return c.BenchmarkSymbol != "" && c.MaxUnderperformance > 0
+}
+
+// withDefaultSymbol returns the config with the benchmark symbol defaulting to
+// symbol, the benchmark the run is compared against.
+func (c BenchmarkRelativeStopConfig) withDefaultSymbol(symbol string) BenchmarkRelativeStopConfig {
+	if c.BenchmarkSymbol == "" {
+		c.BenchmarkSymbol = symbol
+	}
+
+	return c
+}
+
+// benchmarkEntry is the benchmark close at the bar a position was entered on.
+type benchmarkEntry struct {
+	// at is the time of the entry bar.
+	at time.Time
+	// price is the benchmark close at at, 0 until the benchmark's bar at or
+	// after at is seen.
+	price float64
+}
+
+// SetBenchmarkRelativeStop configures the benchmark-relative stop.
+func (b *BacktestTrading) SetBenchmarkRelativeStop(config BenchmarkRelativeStopConfig) {
+	b.benchmarkStop = config
+	b.benchmarkPrice = 0
+	b.benchmarkTime = time.Time{}
+	b.benchmarkEntries = map[string]benchmarkEntry{}
+}
+
+// updateBenchmarkPrice records the close of a benchmark bar before the bar's
+// fills and stops run, and prices the entries made on or before the bar while
+// the benchmark's bar for their time was not seen yet.
+func (b *BacktestTrading) updateBenchmarkPrice() {
+	if !b.benchmarkStop.Enabled() || b.marketData.Symbol != b.benchmarkStop.BenchmarkSymbol || b.marketData.Close <= 0 {
+		return
+	}
+
+	b.benchmarkPrice = b.marketData.Close
+	b.benchmarkTime = b.marketData.Time
+
+	for symbol, entry := range b.benchmarkEntries {
+		if entry.price <= 0 && !entry.at.After(b.benchmarkTime) {
+			entry.price = b.benchmarkPrice
+			b.benchmarkEntries[symbol] = entry
+		}
+	}
+}
+
+// recordBenchmarkEntry remembers the benchmark close at the bar a position in
+// the symbol was opened on. When the benchmark's bar at that time has not been
+// seen yet, the entry is priced by it once it is. Later fills into an open
+// position keep the original entry.
+func (b *BacktestTrading) recordBenchmarkEntry(symbol string) {
+	if !b.benchmarkStop.Enabled() {
+		return
+	}
+
+	if _, ok := b.benchmarkEntries[symbol]; ok {
+		return
+	}
+
+	entry := benchmarkEntry{at: b.marketData.Time, price: 0}
+	if b.benchmarkTime.Equal(entry.at) {
+		entry.price = b.benchmarkPrice
+	}
+
+	b.benchmarkEntries[symbol] = entry
+}
+
+// checkBenchmarkRelativeStop closes the current symbol's long position when it
+// underperforms the benchmark by at least the configured amount. The
+// benchmark's return is measured up to its latest close.
+func (b *BacktestTrading) checkBenchmarkRelativeStop() error {
+	if !b.benchmarkStop.Enabled() {
+		return nil
+	}
+
+	symbol := b.marketData.Symbol
+	if symbol == b.benchmarkStop.BenchmarkSymbol || b.benchmarkPrice <= 0 || b.marketData.Close <= 0 {
+		return nil
+	}
+
+	position, err := b.state.GetPosition(symbol)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodePositionNotFound, "failed to get position for the benchmark-relative stop", err)
+	}
+
+	if position.TotalLongPositionQuantity <= 0 {
+		delete(b.benchmarkEntries, symbol)
+
+		return nil
+	}
+
+	entry, ok := b.benchmarkEntries[symbol]
+	if !ok {
+		// The position was opened before the stop tracked it; start tracking now
+		b.recordBenchmarkEntry(symbol)
+
+		return nil
+	}
+
+	entryPrice := position.GetAverageLongPositionEntryPrice()
+	if entry.price <= 0 || entryPrice <= 0 {
+		return nil
+	}
+
+	positionReturn := b.marketData.Close/entryPrice - 1
+	benchmarkReturn := b.benchmarkPrice/entry.price - 1
+	underperformance := benchmarkReturn - positionReturn
+
+	if underperformance < b.benchmarkStop.MaxUnderperformance {
+		return nil
+	}
+
+	stopOrder := types.ExecuteOrder{
+		ID:        uuid.New().String(),
+		Symbol:    symbol,
+		Side:      types.PurchaseTypeSell,
+		OrderType: types.OrderTypeMarket,
+		Reason: types.Reason{
+			Reason: types.OrderReasonBenchmarkRelativeStop,
+			Message: fmt.Sprintf("position return (%.4f) lags benchmark %s return (%.4f) by %.4f",
+				positionReturn, b.benchmarkStop.BenchmarkSymbol, benchmarkReturn, underperformance),
+		},
+		Price:          b.marketData.Close,
+		StrategyName:   position.StrategyName,
+		Quantity:       position.TotalLongPositionQuantity,
+		PositionType:   types.PositionTypeLong,
+		StopPrice:      0,
+		TrailOffset:    0,
+		TrailPercent:   false,
+		FilledQuantity: 0,
+		TimeInForce:    types.TimeInForceGTC,
+		GoodTillDate:   time.Time{},
+		TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+	}
+
+	if err := b.executeMarketOrder(stopOrder); err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to execute the benchmark-relative stop", err)
+	}
+
+	delete(b.benchmarkEntries, symbol)
+
+	return nil
+}
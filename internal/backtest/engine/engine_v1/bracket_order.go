@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// bracketOrder is a bracket entry waiting to finish filling before its stop
+// loss and take profit are placed.
+type bracketOrder struct {
+	entry      types.ExecuteOrder
+	stopLoss   float64
+	takeProfit float64
+	// filled is the quantity of the entry filled so far.
+	filled float64
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+// The entry is placed like any other order. Once it has finished filling, its
+// filled quantity is protected by an OCO pair of a take profit limit order and a
+// stop-market stop loss, which fill as described on PlaceOCOOrder from the next
+// bar on. An entry that ends without filling places no legs.
+func (b *BacktestTrading) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	if err := tradingprovider.ValidateBracketOrder(entry, stopLoss, takeProfit); err != nil {
+		return err
+	}
+
+	entry.ID = uuid.New().String()
+	b.brackets = append(b.brackets, &bracketOrder{entry: entry, stopLoss: stopLoss, takeProfit: takeProfit, filled: 0})
+
+	if err := b.placeOrder(entry); err != nil {
+		b.brackets = slices.DeleteFunc(b.brackets, func(bracket *bracketOrder) bool {
+			return bracket.entry.ID == entry.ID
+		})
+
+		return err
+	}
+
+	b.armBrackets()
+
+	return nil
+}
+
+// recordBracketFill counts quantity towards the bracket orderID is the entry of, if any.
+func (b *BacktestTrading) recordBracketFill(orderID string, quantity float64) {
+	for _, bracket := range b.brackets {
+		if bracket.entry.ID == orderID {
+			bracket.filled += quantity
+
+			return
+		}
+	}
+}
+
+// armBrackets places the OCO legs of every bracket whose entry is no longer
+// pending, sized to the quantity the entry filled.
+func (b *BacktestTrading) armBrackets() {
+	waiting := b.brackets[:0]
+
+	for _, bracket := range b.brackets {
+		if slices.ContainsFunc(b.pendingOrders, func(order types.ExecuteOrder) bool { return order.ID == bracket.entry.ID }) {
+			waiting = append(waiting, bracket)
+
+			continue
+		}
+
+		quantity := b.floorQuantity(bracket.entry.Symbol, bracket.filled)
+		if quantity <= 0 {
+			continue
+		}
+
+		takeProfit, stopLoss := tradingprovider.BracketLegs(bracket.entry, bracket.stopLoss, bracket.takeProfit, quantity)
+		takeProfit.ID = uuid.New().String()
+		stopLoss.ID = uuid.New().String()
+		b.restOCOLegs(takeProfit, stopLoss)
+	}
+
+	b.brackets = waiting
+}
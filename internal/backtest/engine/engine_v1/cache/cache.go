@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"time"
+
 	"github.com/moznion/go-optional"
 )
 
@@ -28,9 +30,35 @@ type WaddahAttarState struct {
 	Symbol      string  `json:"symbol"` // Symbol this state applies to
 }
 
+// ATRStateKey identifies the ATR state of a symbol at a period, since
+// strategies and other indicators may use different ATR periods.
+type ATRStateKey struct {
+	Symbol string
+	Period int
+}
+
+// ATRState holds the running ATR of a symbol up to the bar at LastTime.
+type ATRState struct {
+	LastTime  time.Time `json:"last_time"`
+	PrevClose float64   `json:"prev_close"`
+	ATR       float64   `json:"atr"`
+}
+
+// VWAPState holds the running totals of a symbol's session VWAP up to the bar at LastTime.
+type VWAPState struct {
+	SessionStart time.Time `json:"session_start"`
+	LastTime     time.Time `json:"last_time"`
+	PriceVolume  float64   `json:"price_volume"`
+	Volume       float64   `json:"volume"`
+	// LastPrice is the typical price of the last bar, used while no volume has traded
+	LastPrice float64 `json:"last_price"`
+}
+
 type CacheV1 struct {
 	RangeFilterState optional.Option[RangeFilterState]
 	WaddahAttarState optional.Option[WaddahAttarState]
+	ATRStates        map[ATRStateKey]ATRState
+	VWAPStates       map[string]VWAPState
 	otherData        map[string]any
 }
 
@@ -38,6 +66,8 @@ func NewCacheV1() Cache {
 	return &CacheV1{
 		RangeFilterState: optional.None[RangeFilterState](),
 		WaddahAttarState: optional.None[WaddahAttarState](),
+		ATRStates:        make(map[ATRStateKey]ATRState),
+		VWAPStates:       make(map[string]VWAPState),
 		otherData:        make(map[string]any),
 	}
 }
@@ -46,6 +76,8 @@ func NewCacheV1() Cache {
 func (c *CacheV1) Reset() {
 	c.RangeFilterState = optional.None[RangeFilterState]()
 	c.WaddahAttarState = optional.None[WaddahAttarState]()
+	c.ATRStates = make(map[ATRStateKey]ATRState)
+	c.VWAPStates = make(map[string]VWAPState)
 	c.otherData = make(map[string]any)
 }
 
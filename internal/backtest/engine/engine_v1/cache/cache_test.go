@@ -42,6 +42,8 @@ func (suite *CacheTestSuite) TestReset() {
 		Symbol:      "BTCUSDT",
 	}
 	suite.cache.RangeFilterState = optional.Some(initialState)
+	suite.cache.ATRStates[ATRStateKey{Symbol: "BTCUSDT", Period: 14}] = ATRState{ATR: 2}
+	suite.cache.VWAPStates["BTCUSDT"] = VWAPState{Volume: 10}
 	suite.cache.otherData = map[string]any{
 		"test": "value",
 	}
@@ -51,6 +53,8 @@ func (suite *CacheTestSuite) TestReset() {
 
 	// Verify the cache is reset
 	suite.True(suite.cache.RangeFilterState.IsNone())
+	suite.Empty(suite.cache.ATRStates)
+	suite.Empty(suite.cache.VWAPStates)
 	suite.Empty(suite.cache.otherData)
 }
 
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/commission_fee"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// orderFee is the commission of an order that has filled partially so far.
+type orderFee struct {
+	// base is the fee of the commission model over the order's fills.
+	base float64
+	// charged is the fee charged over the order's fills after the bounds.
+	charged float64
+}
+
+// SetCommissionBounds configures the smallest and largest commission charged
+// per order, applied after the commission model's fee. Only MinFee and MaxFee
+// of config are used; zero disables the bound.
+func (b *BacktestTrading) SetCommissionBounds(config commission_fee.Config) error {
+	if config.MinFee < 0 || config.MaxFee < 0 {
+		return errors.New(errors.ErrCodeInvalidParameter, "commission min_fee and max_fee must not be negative")
+	}
+
+	if config.MaxFee > 0 && config.MaxFee < config.MinFee {
+		return errors.Newf(errors.ErrCodeInvalidParameter,
+			"commission max_fee (%.2f) must not be below min_fee (%.2f)", config.MaxFee, config.MinFee)
+	}
+
+	b.commissionBounds = config
+	if b.orderFees == nil {
+		b.orderFees = map[string]orderFee{}
+	}
+
+	return nil
+}
+
+// chargeCommission returns the commission of a fill of quantity at price.
+// The minimum and cap apply to the whole order, so the later fills of a
+// partially filled order only pay what its bounded fee grows by. completed
+// reports whether the fill completes the order.
+func (b *BacktestTrading) chargeCommission(order types.ExecuteOrder, price float64, liquidity commission_fee.Liquidity, completed bool) float64 {
+	var fee float64
+	// Volume-tiered models select the rate by the volume as of the bar
+	if tracker, ok := b.commission.(commission_fee.VolumeTracker); ok {
+		fee = tracker.CalculateAt(order.Quantity, price, b.marketData.Time)
+	} else {
+		fee = b.commission.CalculateWithLiquidity(order.Quantity, price, liquidity)
+	}
+
+	if b.commissionBounds.MinFee <= 0 && b.commissionBounds.MaxFee <= 0 {
+		return fee
+	}
+
+	if order.ID == "" {
+		return b.commissionBounds.BoundFee(fee)
+	}
+
+	fees := b.orderFees[order.ID]
+	fees.base += fee
+	charge := max(b.commissionBounds.BoundFee(fees.base)-fees.charged, 0)
+	fees.charged += charge
+
+	if completed {
+		delete(b.orderFees, order.ID)
+	} else {
+		b.orderFees[order.ID] = fees
+	}
+
+	return charge
+}
@@ -47,3 +47,8 @@ func (c *BinanceCommissionFee) Calculate(quantity float64, price float64) float6
 
 	return quantity * price * c.FeeRate
 }
+
+// CalculateWithLiquidity charges FeeRate on both maker and taker fills, as for Regular users.
+func (c *BinanceCommissionFee) CalculateWithLiquidity(quantity float64, price float64, _ Liquidity) float64 {
+	return c.Calculate(quantity, price)
+}
@@ -1,5 +1,7 @@
 package commission_fee
 
+import "time"
+
 type CommissionFee interface {
 	// Calculate the commission fee for a given quantity and price (per unit)
 	// and returns the fee in USD. Implementations that depend only on
@@ -7,6 +9,33 @@ type CommissionFee interface {
 	// implementations that depend on notional value (e.g. crypto exchanges
 	// such as Binance) use both arguments.
 	Calculate(quantity float64, price float64) float64
+	// CalculateWithLiquidity returns the commission fee for a fill that added
+	// (maker) or removed (taker) liquidity. Models without a maker/taker
+	// distinction return the same fee as Calculate.
+	CalculateWithLiquidity(quantity float64, price float64, liquidity Liquidity) float64
+}
+
+// Liquidity classifies a fill as adding liquidity to the order book (maker)
+// or removing it (taker).
+type Liquidity string
+
+const (
+	// LiquidityMaker is a fill of an order that rested in the book before filling.
+	LiquidityMaker Liquidity = "maker"
+	// LiquidityTaker is a fill of a market order or an immediately marketable limit order.
+	LiquidityTaker Liquidity = "taker"
+)
+
+// VolumeTracker is implemented by commission models whose rate depends on the
+// traded volume. The backtest engine reports every executed fill to it.
+type VolumeTracker interface {
+	// CalculateAt returns the commission fee of a fill of quantity at price
+	// executed at the given time, with the volume as of that time.
+	CalculateAt(quantity float64, price float64, at time.Time) float64
+	// RecordFill counts a fill of quantity at price, executed at the given time.
+	RecordFill(quantity float64, price float64, at time.Time)
+	// Reset forgets all recorded fills, e.g. before the next backtest run.
+	Reset()
 }
 
 type Broker string
@@ -15,12 +44,47 @@ const (
 	BrokerInteractiveBroker Broker = "interactive_broker"
 	BrokerZero              Broker = "zero_commission"
 	BrokerBinance           Broker = "binance"
+	// BrokerPercentage charges Config.Rate of the notional value of each fill.
+	BrokerPercentage Broker = "percentage"
+	// BrokerTiered charges a rate selected from Config.Tiers by the rolling 30-day volume.
+	BrokerTiered Broker = "tiered"
+	// BrokerMakerTaker charges Config.MakerRate on maker fills and Config.TakerRate on taker fills.
+	BrokerMakerTaker Broker = "maker_taker"
 )
 
 var AllBrokers = []any{
 	BrokerInteractiveBroker,
 	BrokerZero,
 	BrokerBinance,
+	BrokerPercentage,
+	BrokerTiered,
+	BrokerMakerTaker,
+}
+
+// Config holds the parameters of the configurable commission models.
+// Rate is used by BrokerPercentage, Tiers by BrokerTiered and MakerRate and
+// TakerRate by BrokerMakerTaker. MinFee and MaxFee bound the commission of
+// each order under every broker.
+type Config struct {
+	Rate      float64 `yaml:"rate" json:"rate" jsonschema:"title=Rate,description=Fee rate as a decimal fraction of the notional value used by the percentage broker (e.g. 0.001 = 0.1%),minimum=0"`
+	Tiers     []Tier  `yaml:"tiers" json:"tiers" jsonschema:"title=Tiers,description=Volume tiers used by the tiered broker. The rate of the highest tier whose volume threshold is reached by the rolling 30-day notional volume applies."`
+	MakerRate float64 `yaml:"maker_rate" json:"maker_rate" jsonschema:"title=Maker Rate,description=Fee rate for limit orders that rested before filling used by the maker_taker broker,minimum=0"`
+	TakerRate float64 `yaml:"taker_rate" json:"taker_rate" jsonschema:"title=Taker Rate,description=Fee rate for market orders and immediately marketable limit orders used by the maker_taker broker,minimum=0"`
+	MinFee    float64 `yaml:"min_fee" json:"min_fee" jsonschema:"title=Minimum Fee,description=Smallest commission charged per order in USD applied after the broker's fee. Set to 0 for no minimum.,minimum=0,default=0"`
+	MaxFee    float64 `yaml:"max_fee" json:"max_fee" jsonschema:"title=Maximum Fee,description=Largest commission charged per order in USD applied after the broker's fee and the minimum. Set to 0 for no cap.,minimum=0,default=0"`
+}
+
+// BoundFee applies MinFee and MaxFee to the commission fee of an order.
+func (c Config) BoundFee(fee float64) float64 {
+	if c.MinFee > 0 {
+		fee = max(fee, c.MinFee)
+	}
+
+	if c.MaxFee > 0 {
+		fee = min(fee, c.MaxFee)
+	}
+
+	return fee
 }
 
 func GetCommissionFeeHandler(broker Broker) CommissionFee {
@@ -35,3 +99,18 @@ func GetCommissionFeeHandler(broker Broker) CommissionFee {
 		return NewZeroCommissionFee()
 	}
 }
+
+// NewCommissionFee returns the commission model for the broker, using config
+// for the configurable percentage, tiered and maker/taker models.
+func NewCommissionFee(broker Broker, config Config) CommissionFee {
+	switch broker {
+	case BrokerPercentage:
+		return NewPercentageCommissionFee(config.Rate)
+	case BrokerTiered:
+		return NewTieredCommissionFee(config.Tiers)
+	case BrokerMakerTaker:
+		return NewMakerTakerCommissionFee(config.MakerRate, config.TakerRate)
+	default:
+		return GetCommissionFeeHandler(broker)
+	}
+}
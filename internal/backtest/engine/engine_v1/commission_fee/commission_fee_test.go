@@ -2,6 +2,7 @@ package commission_fee
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -154,11 +155,195 @@ func (suite *CommissionFeeTestSuite) TestGetCommissionFeeHandler() {
 	}
 }
 
+func (suite *CommissionFeeTestSuite) TestPercentageCommissionFee() {
+	fee := NewPercentageCommissionFee(0.001)
+	suite.NotNil(fee)
+
+	tests := []struct {
+		name     string
+		quantity float64
+		price    float64
+		expected float64
+	}{
+		{"10 shares at 95", 10, 95, 0.95}, // 0.001 * 10 * 95
+		{"zero quantity", 0, 95, 0},
+		{"fractional quantity", 0.5, 40000, 20.0},
+		{"negative quantity uses abs", -10, 95, 0.95},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			suite.InDelta(tc.expected, fee.Calculate(tc.quantity, tc.price), 1e-9)
+		})
+	}
+
+	// Negative rate should clamp to zero.
+	suite.Equal(0.0, NewPercentageCommissionFee(-0.01).Calculate(10, 95))
+}
+
+func (suite *CommissionFeeTestSuite) TestTieredCommissionFee() {
+	fee := NewTieredCommissionFee([]Tier{
+		{VolumeThreshold: 10000, Rate: 0.0005},
+		{VolumeThreshold: 0, Rate: 0.001},
+		{VolumeThreshold: 50000, Rate: 0.0002},
+	})
+	tiered, ok := fee.(*TieredCommissionFee)
+	suite.Require().True(ok)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// No volume yet: the lowest tier applies
+	suite.InDelta(0.95, fee.Calculate(10, 95), 1e-9)
+
+	// Calculate does not count towards the volume
+	suite.Equal(0.0, tiered.Volume())
+
+	tiered.RecordFill(100, 100, start) // 10000
+	suite.Equal(10000.0, tiered.Volume())
+	suite.InDelta(0.475, fee.Calculate(10, 95), 1e-9) // 0.0005 * 950
+
+	tiered.RecordFill(400, 100, start.Add(24*time.Hour)) // 50000
+	suite.InDelta(0.19, fee.Calculate(10, 95), 1e-9)     // 0.0002 * 950
+
+	// Fills older than 30 days drop out of the rolling volume
+	tiered.RecordFill(1, 100, start.Add(30*24*time.Hour)) // first fill expires
+	suite.Equal(40100.0, tiered.Volume())
+	suite.InDelta(0.475, fee.Calculate(10, 95), 1e-9)
+
+	tiered.RecordFill(1, 100, start.Add(90*24*time.Hour))
+	suite.Equal(100.0, tiered.Volume())
+	suite.InDelta(0.95, fee.Calculate(10, 95), 1e-9)
+}
+
+func (suite *CommissionFeeTestSuite) TestTieredCommissionFeeAfterLongGap() {
+	fee := NewTieredCommissionFee([]Tier{
+		{VolumeThreshold: 0, Rate: 0.001},
+		{VolumeThreshold: 50000, Rate: 0.0002},
+	})
+	tiered, ok := fee.(*TieredCommissionFee)
+	suite.Require().True(ok)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tiered.RecordFill(500, 100, start) // 50000
+
+	// Within the window the high-volume tier applies
+	suite.InDelta(0.0002, tiered.CurrentRate(start.Add(29*24*time.Hour)), 1e-12)
+	suite.InDelta(0.19, tiered.CalculateAt(10, 95, start.Add(29*24*time.Hour)), 1e-9)
+
+	// 45 days without fills: the volume is pruned before the rate is selected
+	suite.InDelta(0.95, tiered.CalculateAt(10, 95, start.Add(45*24*time.Hour)), 1e-9)
+	suite.Equal(0.0, tiered.Volume())
+}
+
+func (suite *CommissionFeeTestSuite) TestTieredCommissionFeeReset() {
+	fee := NewTieredCommissionFee([]Tier{
+		{VolumeThreshold: 0, Rate: 0.001},
+		{VolumeThreshold: 10000, Rate: 0.0005},
+	})
+	tiered, ok := fee.(*TieredCommissionFee)
+	suite.Require().True(ok)
+
+	tiered.RecordFill(100, 100, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	suite.InDelta(0.475, fee.Calculate(10, 95), 1e-9)
+
+	tiered.Reset()
+	suite.Equal(0.0, tiered.Volume())
+	suite.InDelta(0.95, fee.Calculate(10, 95), 1e-9)
+}
+
+func (suite *CommissionFeeTestSuite) TestTieredCommissionFeeWithoutTiers() {
+	fee := NewTieredCommissionFee(nil)
+	suite.Equal(0.0, fee.Calculate(10, 95))
+}
+
+func (suite *CommissionFeeTestSuite) TestMakerTakerCommissionFee() {
+	fee := NewMakerTakerCommissionFee(0.0002, 0.001)
+	suite.NotNil(fee)
+
+	tests := []struct {
+		name      string
+		liquidity Liquidity
+		quantity  float64
+		price     float64
+		expected  float64
+	}{
+		{"maker fill", LiquidityMaker, 10, 95, 0.19}, // 0.0002 * 950
+		{"taker fill", LiquidityTaker, 10, 95, 0.95}, // 0.001 * 950
+		{"negative quantity uses abs", LiquidityMaker, -10, 95, 0.19},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			suite.InDelta(tc.expected, fee.CalculateWithLiquidity(tc.quantity, tc.price, tc.liquidity), 1e-9)
+		})
+	}
+
+	// Without liquidity information the taker rate applies
+	suite.InDelta(0.95, fee.Calculate(10, 95), 1e-9)
+
+	// Negative rates should clamp to zero.
+	clamped := NewMakerTakerCommissionFee(-1, -1)
+	suite.Equal(0.0, clamped.CalculateWithLiquidity(10, 95, LiquidityMaker))
+	suite.Equal(0.0, clamped.CalculateWithLiquidity(10, 95, LiquidityTaker))
+}
+
+func (suite *CommissionFeeTestSuite) TestCalculateWithLiquidityWithoutDistinction() {
+	for _, broker := range []Broker{BrokerInteractiveBroker, BrokerZero, BrokerBinance} {
+		fee := GetCommissionFeeHandler(broker)
+		suite.Equal(fee.Calculate(10, 95), fee.CalculateWithLiquidity(10, 95, LiquidityMaker), string(broker))
+		suite.Equal(fee.Calculate(10, 95), fee.CalculateWithLiquidity(10, 95, LiquidityTaker), string(broker))
+	}
+}
+
+func (suite *CommissionFeeTestSuite) TestNewCommissionFee() {
+	config := Config{
+		Rate:  0.001,
+		Tiers: []Tier{{VolumeThreshold: 0, Rate: 0.002}},
+	}
+
+	suite.IsType(&PercentageCommissionFee{}, NewCommissionFee(BrokerPercentage, config))
+	suite.InDelta(0.95, NewCommissionFee(BrokerPercentage, config).Calculate(10, 95), 1e-9)
+
+	suite.IsType(&TieredCommissionFee{}, NewCommissionFee(BrokerTiered, config))
+	suite.InDelta(1.9, NewCommissionFee(BrokerTiered, config).Calculate(10, 95), 1e-9)
+
+	suite.IsType(&BinanceCommissionFee{}, NewCommissionFee(BrokerBinance, config))
+
+	makerTaker := NewCommissionFee(BrokerMakerTaker, Config{Rate: 0, Tiers: nil, MakerRate: 0.0002, TakerRate: 0.001})
+	suite.IsType(&MakerTakerCommissionFee{}, makerTaker)
+	suite.InDelta(0.19, makerTaker.CalculateWithLiquidity(10, 95, LiquidityMaker), 1e-9)
+}
+
+func (suite *CommissionFeeTestSuite) TestConfigBoundFee() {
+	tests := []struct {
+		name     string
+		config   Config
+		fee      float64
+		expected float64
+	}{
+		{"no bounds", Config{}, 0.001, 0.001},
+		{"minimum raises a small fee", Config{MinFee: 1}, 0.001, 1},
+		{"minimum keeps a larger fee", Config{MinFee: 1}, 5, 5},
+		{"cap lowers a large fee", Config{MaxFee: 50}, 1000, 50},
+		{"cap keeps a smaller fee", Config{MaxFee: 50}, 20, 20},
+		{"minimum and cap", Config{MinFee: 1, MaxFee: 50}, 0, 1},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			suite.InDelta(tc.expected, tc.config.BoundFee(tc.fee), 1e-12)
+		})
+	}
+}
+
 func (suite *CommissionFeeTestSuite) TestAllBrokers() {
-	suite.Len(AllBrokers, 3)
+	suite.Len(AllBrokers, 6)
 	suite.Contains(AllBrokers, BrokerInteractiveBroker)
 	suite.Contains(AllBrokers, BrokerZero)
 	suite.Contains(AllBrokers, BrokerBinance)
+	suite.Contains(AllBrokers, BrokerPercentage)
+	suite.Contains(AllBrokers, BrokerTiered)
+	suite.Contains(AllBrokers, BrokerMakerTaker)
 }
 
 func (suite *CommissionFeeTestSuite) TestBrokerConstants() {
@@ -16,3 +16,8 @@ func (c *InteractiveBrokerCommissionFee) Calculate(quantity float64, price float
 
 	return fee
 }
+
+// CalculateWithLiquidity applies the per-share schedule to maker and taker fills alike.
+func (c *InteractiveBrokerCommissionFee) CalculateWithLiquidity(quantity float64, price float64, _ Liquidity) float64 {
+	return c.Calculate(quantity, price)
+}
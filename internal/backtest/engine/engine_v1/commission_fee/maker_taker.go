@@ -0,0 +1,41 @@
+package commission_fee
+
+// MakerTakerCommissionFee implements CommissionFee with separate rates for
+// maker fills (limit orders that rested before filling) and taker fills
+// (market orders and immediately marketable limit orders), both applied to the
+// fill's notional value.
+type MakerTakerCommissionFee struct {
+	// MakerRate is the fee rate for maker fills as a decimal fraction.
+	MakerRate float64
+	// TakerRate is the fee rate for taker fills as a decimal fraction.
+	TakerRate float64
+}
+
+// NewMakerTakerCommissionFee creates a MakerTakerCommissionFee. Negative rates
+// are clamped to zero.
+func NewMakerTakerCommissionFee(makerRate float64, takerRate float64) CommissionFee {
+	if makerRate < 0 {
+		makerRate = 0
+	}
+
+	if takerRate < 0 {
+		takerRate = 0
+	}
+
+	return &MakerTakerCommissionFee{MakerRate: makerRate, TakerRate: takerRate}
+}
+
+// Calculate returns the taker fee, the conservative estimate when the
+// liquidity of the fill is not known in advance.
+func (c *MakerTakerCommissionFee) Calculate(quantity float64, price float64) float64 {
+	return c.CalculateWithLiquidity(quantity, price, LiquidityTaker)
+}
+
+// CalculateWithLiquidity returns the notional value multiplied by the maker or taker rate.
+func (c *MakerTakerCommissionFee) CalculateWithLiquidity(quantity float64, price float64, liquidity Liquidity) float64 {
+	if liquidity == LiquidityMaker {
+		return notional(quantity, price) * c.MakerRate
+	}
+
+	return notional(quantity, price) * c.TakerRate
+}
@@ -0,0 +1,42 @@
+package commission_fee
+
+// PercentageCommissionFee implements CommissionFee as a fixed fraction of the
+// fill's notional value (price * quantity).
+type PercentageCommissionFee struct {
+	// Rate is the fee rate applied to the notional value, expressed as a
+	// decimal fraction (e.g. 0.001 for 0.1%).
+	Rate float64
+}
+
+// NewPercentageCommissionFee creates a PercentageCommissionFee charging rate
+// of the notional value of each fill. Negative rates are clamped to zero.
+func NewPercentageCommissionFee(rate float64) CommissionFee {
+	if rate < 0 {
+		rate = 0
+	}
+
+	return &PercentageCommissionFee{Rate: rate}
+}
+
+// Calculate returns |quantity| * |price| * Rate.
+func (c *PercentageCommissionFee) Calculate(quantity float64, price float64) float64 {
+	return notional(quantity, price) * c.Rate
+}
+
+// notional returns the absolute notional value of a fill.
+func notional(quantity float64, price float64) float64 {
+	if quantity < 0 {
+		quantity = -quantity
+	}
+
+	if price < 0 {
+		price = -price
+	}
+
+	return quantity * price
+}
+
+// CalculateWithLiquidity charges Rate regardless of liquidity.
+func (c *PercentageCommissionFee) CalculateWithLiquidity(quantity float64, price float64, _ Liquidity) float64 {
+	return c.Calculate(quantity, price)
+}
@@ -0,0 +1,163 @@
+package commission_fee
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// TieredVolumeWindow is the rolling window over which traded volume is
+// accumulated to select the fee tier.
+const TieredVolumeWindow = 30 * 24 * time.Hour
+
+// Tier is a fee rate that applies once the rolling traded volume reaches
+// VolumeThreshold.
+type Tier struct {
+	// VolumeThreshold is the minimum rolling 30-day notional volume for this tier.
+	VolumeThreshold float64 `yaml:"volume_threshold" json:"volume_threshold" jsonschema:"title=Volume Threshold,description=Minimum rolling 30-day notional volume at which this tier applies,minimum=0"`
+	// Rate is the fee rate as a decimal fraction of the notional value.
+	Rate float64 `yaml:"rate" json:"rate" jsonschema:"title=Rate,description=Fee rate as a decimal fraction of the notional value (e.g. 0.001 = 0.1%),minimum=0"`
+}
+
+// volumeFill is a single fill counted towards the rolling volume.
+type volumeFill struct {
+	at       time.Time
+	notional float64
+}
+
+// TieredCommissionFee implements CommissionFee with a rate that decreases as
+// the rolling 30-day traded volume grows, as on most exchanges' VIP schedules.
+// The rate for a fill is the rate of the highest tier whose threshold is at or
+// below the volume recorded before that fill; below the lowest threshold the
+// lowest tier's rate applies.
+type TieredCommissionFee struct {
+	mu     sync.Mutex
+	tiers  []Tier
+	fills  []volumeFill
+	volume float64
+}
+
+// NewTieredCommissionFee creates a TieredCommissionFee from the given tiers.
+// Tiers may be given in any order; negative rates are clamped to zero. With no
+// tiers, no commission is charged.
+func NewTieredCommissionFee(tiers []Tier) CommissionFee {
+	sorted := make([]Tier, 0, len(tiers))
+	for _, tier := range tiers {
+		if tier.Rate < 0 {
+			tier.Rate = 0
+		}
+
+		sorted = append(sorted, tier)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].VolumeThreshold < sorted[j].VolumeThreshold
+	})
+
+	return &TieredCommissionFee{
+		mu:     sync.Mutex{},
+		tiers:  sorted,
+		fills:  nil,
+		volume: 0,
+	}
+}
+
+// Calculate returns the notional value multiplied by the rate of the rolling
+// volume as of the last recorded fill. It does not count the fill towards the
+// volume; see RecordFill. Use CalculateAt to price a fill at a given time.
+func (c *TieredCommissionFee) Calculate(quantity float64, price float64) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return notional(quantity, price) * c.rate()
+}
+
+// CalculateAt implements VolumeTracker. It returns the notional value
+// multiplied by the rate of the rolling volume as of at.
+func (c *TieredCommissionFee) CalculateAt(quantity float64, price float64, at time.Time) float64 {
+	return notional(quantity, price) * c.CurrentRate(at)
+}
+
+// CurrentRate returns the rate of the tier selected by the rolling volume as
+// of at. Fills that left the window by then no longer count, so the rate
+// falls back to a lower tier after a long gap between fills.
+func (c *TieredCommissionFee) CurrentRate(at time.Time) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expireFills(at)
+
+	return c.rate()
+}
+
+// Volume returns the rolling 30-day notional volume as of the last recorded fill
+// or rate lookup.
+func (c *TieredCommissionFee) Volume() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.volume
+}
+
+// RecordFill implements VolumeTracker. It adds the fill's notional value to the
+// rolling volume and drops fills older than TieredVolumeWindow relative to at.
+func (c *TieredCommissionFee) RecordFill(quantity float64, price float64, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fills = append(c.fills, volumeFill{at: at, notional: notional(quantity, price)})
+	c.volume += c.fills[len(c.fills)-1].notional
+
+	c.expireFills(at)
+}
+
+// expireFills drops the fills older than TieredVolumeWindow relative to at.
+// The caller must hold c.mu.
+func (c *TieredCommissionFee) expireFills(at time.Time) {
+	cutoff := at.Add(-TieredVolumeWindow)
+	expired := 0
+
+	for expired < len(c.fills) && !c.fills[expired].at.After(cutoff) {
+		c.volume -= c.fills[expired].notional
+		expired++
+	}
+
+	c.fills = c.fills[expired:]
+	if len(c.fills) == 0 {
+		c.volume = 0
+	}
+}
+
+// rate returns the rate of the tier selected by the rolling volume. The
+// caller must hold c.mu.
+func (c *TieredCommissionFee) rate() float64 {
+	if len(c.tiers) == 0 {
+		return 0
+	}
+
+	rate := c.tiers[0].Rate
+
+	for _, tier := range c.tiers {
+		if c.volume < tier.VolumeThreshold {
+			break
+		}
+
+		rate = tier.Rate
+	}
+
+	return rate
+}
+
+// Reset implements VolumeTracker.
+func (c *TieredCommissionFee) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fills = nil
+	c.volume = 0
+}
+
+// CalculateWithLiquidity charges the rate of Calculate regardless of liquidity.
+func (c *TieredCommissionFee) CalculateWithLiquidity(quantity float64, price float64, _ Liquidity) float64 {
+	return c.Calculate(quantity, price)
+}
@@ -15,3 +15,8 @@ func (c *ZeroCommissionFee) Calculate(quantity float64, price float64) float64 {
 
 	return 0.0
 }
+
+// CalculateWithLiquidity returns 0 for maker and taker fills alike.
+func (c *ZeroCommissionFee) CalculateWithLiquidity(quantity float64, price float64, _ Liquidity) float64 {
+	return c.Calculate(quantity, price)
+}
@@ -39,10 +39,41 @@ type BacktestEngineV1Config struct {
 	StartTime                 optional.Option[time.Time]   `yaml:"start_time" json:"start_time" jsonschema:"title=Start Time,description=Optional start time for the backtest period"`
 	EndTime                   optional.Option[time.Time]   `yaml:"end_time" json:"end_time" jsonschema:"title=End Time,description=Optional end time for the backtest period"`
 	DecimalPrecision          int                          `yaml:"decimal_precision" json:"decimal_precision" jsonschema:"title=Decimal Precision,description=The number of decimal places allowed for quantity (0 means integers only, higher values allow more decimal places),minimum=0,default=1"`
+	SymbolDecimalPrecision    map[string]int               `yaml:"symbol_decimal_precision" json:"symbol_decimal_precision" jsonschema:"title=Symbol Decimal Precision,description=Optional number of decimal places allowed for the quantity of individual symbols keyed by symbol (e.g. 8 for BTC/USD and 0 for AAPL). Symbols that are not listed use decimal_precision."`
+	RoundingMode              RoundingModeType             `yaml:"rounding_mode" json:"rounding_mode" jsonschema:"title=Rounding Mode,description=How order quantities are snapped to the lot grid. 'floor' rounds down; 'nearest' rounds to the nearest lot with halves rounded up; 'reject' rejects orders that are not a whole number of lots. Maximum buy and sell quantities are always rounded down. Defaults to 'floor'.,default=floor"`
+	LotSize                   map[string]float64           `yaml:"lot_size" json:"lot_size" jsonschema:"title=Lot Size,description=Optional lot size of individual symbols keyed by symbol (e.g. 0.1 or 100). Quantities of a listed symbol are snapped to multiples of its lot size instead of to its decimal precision."`
 	MarketDataCacheSize       int                          `yaml:"market_data_cache_size" json:"market_data_cache_size" jsonschema:"title=Market Data Cache Size,description=The number of market data points to cache per symbol using sliding window algorithm. When data requests exceed cache size the system falls back to DuckDB. Set to 0 to disable caching.,minimum=0,default=1000"`
 	PortfolioCalculation      PortfolioCalculationStrategy `yaml:"portfolio_calculation" json:"portfolio_calculation" jsonschema:"title=Portfolio Calculation Strategy,description=How individual-trade and cumulative PnL are computed. 'fifo' matches exits against earliest entries; 'average_cost' uses the running weighted-average cost of the currently-open position. Defaults to 'average_cost' when unset.,default=average_cost"`
 	RiskFreeRate              float64                      `yaml:"risk_free_rate" json:"risk_free_rate" jsonschema:"title=Risk-Free Rate,description=Annualized risk-free rate (as a decimal fraction; e.g. 0.04 = 4%) used when computing the Sharpe ratio from daily equity returns. Defaults to 0.,default=0"`
 	SharpeAnnualizationFactor int                          `yaml:"sharpe_annualization_factor" json:"sharpe_annualization_factor" jsonschema:"title=Sharpe Annualization Factor,description=Number of return periods per year used to annualize the Sharpe ratio (e.g. 252 for daily trading-day returns 365 for calendar-day returns). Set to 0 to disable annualization. Defaults to 252.,minimum=0,default=252"`
+	ResultsExportPath         string                       `yaml:"results_export_path" json:"results_export_path" jsonschema:"title=Results Export Path,description=Optional path to a DuckDB (.duckdb/.db) or SQLite (.sqlite/.sqlite3) file. When set the orders trades marks and equity curve of every run are appended to this file at the end of the run. SQLite export needs the DuckDB sqlite extension which is downloaded on first use. Leave empty to disable."`
+	SignalConfirmationBars    int                          `yaml:"signal_confirmation_bars" json:"signal_confirmation_bars" jsonschema:"title=Signal Confirmation Bars,description=Number of consecutive bars a strategy must keep requesting the same order (symbol side position type and order type) before it is placed. Orders that are not requested again on the next bar are dropped. Set to 0 or 1 to place orders immediately.,minimum=0,default=0"`
+	OutputFormats             []ResultOutputFormat         `yaml:"output_formats" json:"output_formats" jsonschema:"title=Output Formats,description=File formats the trades orders marks and logs of each run are written in. Parquet is always written; add csv and/or json to also write those formats from the same records. Defaults to parquet only."`
+	BenchmarkRelativeStop     BenchmarkRelativeStopConfig  `yaml:"benchmark_relative_stop" json:"benchmark_relative_stop" jsonschema:"title=Benchmark Relative Stop,description=Optional stop that closes a long position when its return since entry lags the benchmark symbol's return by the configured amount. The benchmark symbol defaults to benchmark_symbol."`
+	EntryThrottle             EntryThrottleConfig          `yaml:"entry_throttle" json:"entry_throttle" jsonschema:"title=Entry Throttle,description=Optional limit on the number of new entries placed per bar across all symbols and the policy for entries above the limit."`
+	Cooldown                  CooldownConfig               `yaml:"cooldown" json:"cooldown" jsonschema:"title=Cooldown,description=Optional time after an accepted order during which further orders on the same symbol are rejected. Can be set per symbol and tracked per side."`
+	MaxOrdersPerDay           int                          `yaml:"max_orders_per_day" json:"max_orders_per_day" jsonschema:"title=Max Orders Per Day,description=Largest number of orders accepted per UTC day of the bar time. Further orders that day are rejected with reason max_orders_per_day. Set to 0 to disable.,minimum=0,default=0"`
+	MaxNotionalPerDay         float64                      `yaml:"max_notional_per_day" json:"max_notional_per_day" jsonschema:"title=Max Notional Per Day,description=Largest total notional (quantity times order price) of the orders accepted per UTC day of the bar time. Orders that would exceed it are rejected with reason max_notional_per_day. Set to 0 to disable.,minimum=0,default=0"`
+	StrategyAllocations       map[string]float64           `yaml:"strategy_allocations" json:"strategy_allocations" jsonschema:"title=Strategy Allocations,description=Optional share of the initial capital allocated to each strategy name. The allocations must sum to the initial capital. Each strategy then trades a sub-account whose buying power selling power and PnL are tracked separately from the other strategies' and a strategy without an allocation cannot open positions."`
+	MaxOpenPositions          int                          `yaml:"max_open_positions" json:"max_open_positions" jsonschema:"title=Max Open Positions,description=Largest number of symbols with an open long or short position. Orders that would open a position in another symbol are rejected with reason max_positions while orders that add to reduce or close a position are allowed. Set to 0 to disable.,minimum=0,default=0"`
+	PositionLimit             PositionLimitConfig          `yaml:"position_limit" json:"position_limit" jsonschema:"title=Position Limit,description=Optional largest quantity and/or notional of the position of each symbol. Entry orders that would take a position beyond it are rejected with reason position_limit or reduced to the quantity left. Can be set per symbol."`
+	ATRSizing                 ATRSizingConfig              `yaml:"atr_sizing" json:"atr_sizing" jsonschema:"title=ATR Sizing,description=Optional volatility sizing of entry orders. The quantity the strategy requests is replaced by the quantity at which a move of atr_multiple times the ATR against the position loses risk_percentage of the equity. Entries keep their requested quantity until the symbol has enough bars for the ATR."`
+	MinHoldingPeriod          string                       `yaml:"min_holding_period" json:"min_holding_period" jsonschema:"title=Min Holding Period,description=Optional minimum time a position is held after its opening fill (e.g. 30m or 24h). Orders that would reduce or close the position earlier are rejected with reason min_holding. Measured on the bar time. Leave empty to disable."`
+	BenchmarkSymbol           string                       `yaml:"benchmark_symbol" json:"benchmark_symbol" jsonschema:"title=Benchmark Symbol,description=Optional symbol in the dataset whose buy-and-hold return the run is compared against in the exported results: the initial capital is invested at its first close and held to its last. Also the default benchmark of benchmark_relative_stop. Leave empty to skip the comparison."`
+	EquityCurve               EquityCurveConfig            `yaml:"equity_curve" json:"equity_curve" jsonschema:"title=Equity Curve,description=Per-bar record of the balance and equity written to state.db/equity_curve. Recorded by default with optional downsampling for long runs."`
+	MarkToMarket              MarkToMarketConfig           `yaml:"mark_to_market" json:"mark_to_market" jsonschema:"title=Mark To Market,description=Optional times of day at which equity is snapshotted (e.g. each session close) producing an equity series independent of the bar frequency. Snapshots are written to state.db/equity_snapshots."`
+	AutoFlatten               AutoFlattenConfig            `yaml:"auto_flatten" json:"auto_flatten" jsonschema:"title=Auto Flatten,description=Optional session end at which open orders are cancelled and all positions are closed at market with reason session_close. New entries after the session end are rejected."`
+	MaxVolumeParticipation    float64                      `yaml:"max_volume_participation" json:"max_volume_participation" jsonschema:"title=Max Volume Participation,description=Largest fraction of a bar's volume (e.g. 0.1 = 10%) a single order may fill on that bar. The rest of the order stays open with status PARTIALLY_FILLED and fills on the following bars. Set to 0 to fill orders in full.,minimum=0,maximum=1,default=0"`
+	Slippage                  SlippageConfig               `yaml:"slippage" json:"slippage" jsonschema:"title=Slippage,description=Optional slippage model that moves market order fills against the order: buys fill higher and sells lower than the bar price."`
+	LimitFillPolicy           LimitFillPolicyType          `yaml:"limit_fill_policy" json:"limit_fill_policy" jsonschema:"title=Limit Fill Policy,description=When a bar fills a limit order. 'touch' fills once the low reaches a buy limit or the high reaches a sell limit; 'penetration' requires the bar to trade strictly through the limit; 'touch_volume' fills on penetration and on a touch only when the bar volume exceeds the unfilled quantity. Defaults to 'touch'.,default=touch"`
+	FillPricePolicy           FillPricePolicyType          `yaml:"fill_price_policy" json:"fill_price_policy" jsonschema:"title=Fill Price Policy,description=Bar price market orders and limit orders that are marketable when placed fill at. 'mid' buys at the ask and sells at the bid when the data has bid and ask columns and fills at the middle of the high-low range otherwise; 'open' at the open; 'close' at the close; 'worst' buys at the high and sells at the low. Marketable limit orders never fill beyond their limit. Defaults to 'mid'.,default=mid"`
+	FundingRates              []FundingRate                `yaml:"funding_rates" json:"funding_rates" jsonschema:"title=Funding Rates,description=Optional funding schedule of perpetual futures symbols. At each funding time open positions pay or receive the rate times their notional which is added to the balance and the realized PnL."`
+	Margin                    MarginConfig                 `yaml:"margin" json:"margin" jsonschema:"title=Margin,description=Optional leverage and maintenance margin. With leverage buying power is the free margin times the leverage and positions are closed with reason margin_call when equity falls below the maintenance margin."`
+	Commission                commission_fee.Config        `yaml:"commission" json:"commission" jsonschema:"title=Commission,description=Parameters of the percentage and tiered commission models selected with broker. Use rate for the percentage broker and tiers for the tiered broker. min_fee and max_fee bound the commission of each order under every broker."`
+	SymbolWorkers             int                          `yaml:"symbol_workers" json:"symbol_workers" jsonschema:"title=Symbol Workers,description=Number of goroutines that evaluate the bars of different symbols concurrently. Each symbol gets its own strategy instance and orders still reach the trading system in data order so results match a serial run. Requires a strategy that evaluates each symbol on its own. Set to 0 or 1 to process bars serially.,minimum=0,default=0"`
+	WarmupBars                int                          `yaml:"warmup_bars" json:"warmup_bars" jsonschema:"title=Warmup Bars,description=Number of bars of each symbol that are added to the market data cache before the strategy processes any bar of that symbol so indicators have history when the first signal fires. Set to 0 to call the strategy from the first bar.,minimum=0,default=0"`
+	SignalOnly                bool                         `yaml:"signal_only" json:"signal_only" jsonschema:"title=Signal Only,description=Record the orders the strategy places as signals in state.db/signals instead of executing them. No order is filled or rejected for buying power and the balance and positions never change. Use it to research the signals of a strategy apart from fills and fees.,default=false"`
+	RandomSeed                int64                        `yaml:"random_seed" json:"random_seed" jsonschema:"title=Random Seed,description=Seed of the random source used by stochastic fill models such as the random_bps slippage. The source is reseeded at the start of every run so the same config data and seed always produce identical trades apart from their generated order IDs.,default=0"`
 }
 
 // UnmarshalYAML implements custom unmarshaling for BacktestEngineV1Config.
@@ -53,10 +84,41 @@ func (c *BacktestEngineV1Config) UnmarshalYAML(unmarshal func(interface{}) error
 		StartTime                 *time.Time                   `yaml:"start_time"`
 		EndTime                   *time.Time                   `yaml:"end_time"`
 		DecimalPrecision          int                          `yaml:"decimal_precision"`
+		SymbolDecimalPrecision    map[string]int               `yaml:"symbol_decimal_precision"`
+		RoundingMode              RoundingModeType             `yaml:"rounding_mode"`
+		LotSize                   map[string]float64           `yaml:"lot_size"`
 		MarketDataCacheSize       int                          `yaml:"market_data_cache_size"`
 		PortfolioCalculation      PortfolioCalculationStrategy `yaml:"portfolio_calculation"`
 		RiskFreeRate              float64                      `yaml:"risk_free_rate"`
 		SharpeAnnualizationFactor int                          `yaml:"sharpe_annualization_factor"`
+		ResultsExportPath         string                       `yaml:"results_export_path"`
+		SignalConfirmationBars    int                          `yaml:"signal_confirmation_bars"`
+		OutputFormats             []ResultOutputFormat         `yaml:"output_formats"`
+		BenchmarkRelativeStop     BenchmarkRelativeStopConfig  `yaml:"benchmark_relative_stop"`
+		EntryThrottle             EntryThrottleConfig          `yaml:"entry_throttle"`
+		Cooldown                  CooldownConfig               `yaml:"cooldown"`
+		MaxOrdersPerDay           int                          `yaml:"max_orders_per_day"`
+		MaxNotionalPerDay         float64                      `yaml:"max_notional_per_day"`
+		StrategyAllocations       map[string]float64           `yaml:"strategy_allocations"`
+		MaxOpenPositions          int                          `yaml:"max_open_positions"`
+		PositionLimit             PositionLimitConfig          `yaml:"position_limit"`
+		ATRSizing                 ATRSizingConfig              `yaml:"atr_sizing"`
+		MinHoldingPeriod          string                       `yaml:"min_holding_period"`
+		BenchmarkSymbol           string                       `yaml:"benchmark_symbol"`
+		EquityCurve               EquityCurveConfig            `yaml:"equity_curve"`
+		MarkToMarket              MarkToMarketConfig           `yaml:"mark_to_market"`
+		AutoFlatten               AutoFlattenConfig            `yaml:"auto_flatten"`
+		MaxVolumeParticipation    float64                      `yaml:"max_volume_participation"`
+		Slippage                  SlippageConfig               `yaml:"slippage"`
+		LimitFillPolicy           LimitFillPolicyType          `yaml:"limit_fill_policy"`
+		FillPricePolicy           FillPricePolicyType          `yaml:"fill_price_policy"`
+		FundingRates              []FundingRate                `yaml:"funding_rates"`
+		Margin                    MarginConfig                 `yaml:"margin"`
+		Commission                commission_fee.Config        `yaml:"commission"`
+		SymbolWorkers             int                          `yaml:"symbol_workers"`
+		WarmupBars                int                          `yaml:"warmup_bars"`
+		SignalOnly                bool                         `yaml:"signal_only"`
+		RandomSeed                int64                        `yaml:"random_seed"`
 	}
 
 	var config Config
@@ -67,10 +129,41 @@ func (c *BacktestEngineV1Config) UnmarshalYAML(unmarshal func(interface{}) error
 	c.InitialCapital = config.InitialCapital
 	c.Broker = config.Broker
 	c.DecimalPrecision = config.DecimalPrecision
+	c.SymbolDecimalPrecision = config.SymbolDecimalPrecision
+	c.RoundingMode = config.RoundingMode
+	c.LotSize = config.LotSize
 	c.MarketDataCacheSize = config.MarketDataCacheSize
 	c.PortfolioCalculation = config.PortfolioCalculation
 	c.RiskFreeRate = config.RiskFreeRate
 	c.SharpeAnnualizationFactor = config.SharpeAnnualizationFactor
+	c.ResultsExportPath = config.ResultsExportPath
+	c.SignalConfirmationBars = config.SignalConfirmationBars
+	c.OutputFormats = config.OutputFormats
+	c.BenchmarkRelativeStop = config.BenchmarkRelativeStop
+	c.EntryThrottle = config.EntryThrottle
+	c.Cooldown = config.Cooldown
+	c.MaxOrdersPerDay = config.MaxOrdersPerDay
+	c.MaxNotionalPerDay = config.MaxNotionalPerDay
+	c.StrategyAllocations = config.StrategyAllocations
+	c.MaxOpenPositions = config.MaxOpenPositions
+	c.PositionLimit = config.PositionLimit
+	c.ATRSizing = config.ATRSizing
+	c.MinHoldingPeriod = config.MinHoldingPeriod
+	c.BenchmarkSymbol = config.BenchmarkSymbol
+	c.EquityCurve = config.EquityCurve
+	c.MarkToMarket = config.MarkToMarket
+	c.AutoFlatten = config.AutoFlatten
+	c.MaxVolumeParticipation = config.MaxVolumeParticipation
+	c.Slippage = config.Slippage
+	c.LimitFillPolicy = config.LimitFillPolicy
+	c.FillPricePolicy = config.FillPricePolicy
+	c.FundingRates = config.FundingRates
+	c.Margin = config.Margin
+	c.Commission = config.Commission
+	c.SymbolWorkers = config.SymbolWorkers
+	c.WarmupBars = config.WarmupBars
+	c.SignalOnly = config.SignalOnly
+	c.RandomSeed = config.RandomSeed
 
 	if config.StartTime != nil {
 		c.StartTime = optional.Some(*config.StartTime)
@@ -94,10 +187,41 @@ func (c BacktestEngineV1Config) MarshalYAML() (interface{}, error) {
 		StartTime                 *time.Time                   `yaml:"start_time,omitempty"`
 		EndTime                   *time.Time                   `yaml:"end_time,omitempty"`
 		DecimalPrecision          int                          `yaml:"decimal_precision"`
+		SymbolDecimalPrecision    map[string]int               `yaml:"symbol_decimal_precision,omitempty"`
+		RoundingMode              RoundingModeType             `yaml:"rounding_mode,omitempty"`
+		LotSize                   map[string]float64           `yaml:"lot_size,omitempty"`
 		MarketDataCacheSize       int                          `yaml:"market_data_cache_size"`
 		PortfolioCalculation      PortfolioCalculationStrategy `yaml:"portfolio_calculation"`
 		RiskFreeRate              float64                      `yaml:"risk_free_rate"`
 		SharpeAnnualizationFactor int                          `yaml:"sharpe_annualization_factor"`
+		ResultsExportPath         string                       `yaml:"results_export_path,omitempty"`
+		SignalConfirmationBars    int                          `yaml:"signal_confirmation_bars"`
+		OutputFormats             []ResultOutputFormat         `yaml:"output_formats,omitempty"`
+		BenchmarkRelativeStop     BenchmarkRelativeStopConfig  `yaml:"benchmark_relative_stop,omitempty"`
+		EntryThrottle             EntryThrottleConfig          `yaml:"entry_throttle,omitempty"`
+		Cooldown                  CooldownConfig               `yaml:"cooldown,omitempty"`
+		MaxOrdersPerDay           int                          `yaml:"max_orders_per_day,omitempty"`
+		MaxNotionalPerDay         float64                      `yaml:"max_notional_per_day,omitempty"`
+		StrategyAllocations       map[string]float64           `yaml:"strategy_allocations,omitempty"`
+		MaxOpenPositions          int                          `yaml:"max_open_positions,omitempty"`
+		PositionLimit             PositionLimitConfig          `yaml:"position_limit,omitempty"`
+		ATRSizing                 ATRSizingConfig              `yaml:"atr_sizing,omitempty"`
+		MinHoldingPeriod          string                       `yaml:"min_holding_period,omitempty"`
+		BenchmarkSymbol           string                       `yaml:"benchmark_symbol,omitempty"`
+		EquityCurve               EquityCurveConfig            `yaml:"equity_curve,omitempty"`
+		MarkToMarket              MarkToMarketConfig           `yaml:"mark_to_market,omitempty"`
+		AutoFlatten               AutoFlattenConfig            `yaml:"auto_flatten,omitempty"`
+		MaxVolumeParticipation    float64                      `yaml:"max_volume_participation,omitempty"`
+		Slippage                  SlippageConfig               `yaml:"slippage,omitempty"`
+		LimitFillPolicy           LimitFillPolicyType          `yaml:"limit_fill_policy,omitempty"`
+		FillPricePolicy           FillPricePolicyType          `yaml:"fill_price_policy,omitempty"`
+		FundingRates              []FundingRate                `yaml:"funding_rates,omitempty"`
+		Margin                    MarginConfig                 `yaml:"margin,omitempty"`
+		Commission                commission_fee.Config        `yaml:"commission,omitempty"`
+		SymbolWorkers             int                          `yaml:"symbol_workers,omitempty"`
+		WarmupBars                int                          `yaml:"warmup_bars,omitempty"`
+		SignalOnly                bool                         `yaml:"signal_only,omitempty"`
+		RandomSeed                int64                        `yaml:"random_seed,omitempty"`
 	}
 
 	out := Config{
@@ -106,10 +230,41 @@ func (c BacktestEngineV1Config) MarshalYAML() (interface{}, error) {
 		StartTime:                 nil,
 		EndTime:                   nil,
 		DecimalPrecision:          c.DecimalPrecision,
+		SymbolDecimalPrecision:    c.SymbolDecimalPrecision,
+		RoundingMode:              c.RoundingMode,
+		LotSize:                   c.LotSize,
 		MarketDataCacheSize:       c.MarketDataCacheSize,
 		PortfolioCalculation:      c.PortfolioCalculation,
 		RiskFreeRate:              c.RiskFreeRate,
 		SharpeAnnualizationFactor: c.SharpeAnnualizationFactor,
+		ResultsExportPath:         c.ResultsExportPath,
+		SignalConfirmationBars:    c.SignalConfirmationBars,
+		OutputFormats:             c.OutputFormats,
+		BenchmarkRelativeStop:     c.BenchmarkRelativeStop,
+		EntryThrottle:             c.EntryThrottle,
+		Cooldown:                  c.Cooldown,
+		MaxOrdersPerDay:           c.MaxOrdersPerDay,
+		MaxNotionalPerDay:         c.MaxNotionalPerDay,
+		StrategyAllocations:       c.StrategyAllocations,
+		MaxOpenPositions:          c.MaxOpenPositions,
+		PositionLimit:             c.PositionLimit,
+		ATRSizing:                 c.ATRSizing,
+		MinHoldingPeriod:          c.MinHoldingPeriod,
+		BenchmarkSymbol:           c.BenchmarkSymbol,
+		EquityCurve:               c.EquityCurve,
+		MarkToMarket:              c.MarkToMarket,
+		AutoFlatten:               c.AutoFlatten,
+		MaxVolumeParticipation:    c.MaxVolumeParticipation,
+		Slippage:                  c.Slippage,
+		LimitFillPolicy:           c.LimitFillPolicy,
+		FillPricePolicy:           c.FillPricePolicy,
+		FundingRates:              c.FundingRates,
+		Margin:                    c.Margin,
+		Commission:                c.Commission,
+		SymbolWorkers:             c.SymbolWorkers,
+		WarmupBars:                c.WarmupBars,
+		SignalOnly:                c.SignalOnly,
+		RandomSeed:                c.RandomSeed,
 	}
 
 	if v, err := c.StartTime.Take(); err == nil {
@@ -148,6 +303,55 @@ func (c *BacktestEngineV1Config) GenerateSchema() (*jsonschema.Schema, error) {
 					Enum: commission_fee.AllBrokers,
 				}
 			}
+			if t.String() == "engine.ResultOutputFormat" {
+				//nolint:exhaustruct // third-party struct with many optional fields
+				return &jsonschema.Schema{
+					Type: "string",
+					Enum: AllResultOutputFormats,
+				}
+			}
+			if t.String() == "engine.SlippageModelType" {
+				//nolint:exhaustruct // third-party struct with many optional fields
+				return &jsonschema.Schema{
+					Type: "string",
+					Enum: AllSlippageModels,
+				}
+			}
+			if t.String() == "engine.LimitFillPolicyType" {
+				//nolint:exhaustruct // third-party struct with many optional fields
+				return &jsonschema.Schema{
+					Type: "string",
+					Enum: AllLimitFillPolicies,
+				}
+			}
+			if t.String() == "engine.RoundingModeType" {
+				//nolint:exhaustruct // third-party struct with many optional fields
+				return &jsonschema.Schema{
+					Type: "string",
+					Enum: AllRoundingModes,
+				}
+			}
+			if t.String() == "engine.FillPricePolicyType" {
+				//nolint:exhaustruct // third-party struct with many optional fields
+				return &jsonschema.Schema{
+					Type: "string",
+					Enum: AllFillPricePolicies,
+				}
+			}
+			if t.String() == "engine.PositionLimitMode" {
+				//nolint:exhaustruct // third-party struct with many optional fields
+				return &jsonschema.Schema{
+					Type: "string",
+					Enum: AllPositionLimitModes,
+				}
+			}
+			if t.String() == "engine.EntryThrottlePolicy" {
+				//nolint:exhaustruct // third-party struct with many optional fields
+				return &jsonschema.Schema{
+					Type: "string",
+					Enum: AllEntryThrottlePolicies,
+				}
+			}
 			if strings.Contains(t.String(), "PortfolioCalculationStrategy") {
 				//nolint:exhaustruct // third-party struct with many optional fields
 				return &jsonschema.Schema{
@@ -193,10 +397,41 @@ func TestConfig(startTime time.Time, endTime time.Time, broker commission_fee.Br
 		StartTime:                 optional.Some(startTime),
 		EndTime:                   optional.Some(endTime),
 		DecimalPrecision:          1,
+		SymbolDecimalPrecision:    nil,
+		RoundingMode:              RoundingFloor,
+		LotSize:                   nil,
 		MarketDataCacheSize:       1000,
 		PortfolioCalculation:      PortfolioCalculationAverageCost,
 		RiskFreeRate:              0,
 		SharpeAnnualizationFactor: 252,
+		ResultsExportPath:         "",
+		SignalConfirmationBars:    0,
+		OutputFormats:             []ResultOutputFormat{ResultOutputFormatParquet},
+		BenchmarkRelativeStop:     BenchmarkRelativeStopConfig{BenchmarkSymbol: "", MaxUnderperformance: 0},
+		EntryThrottle:             EntryThrottleConfig{MaxEntriesPerBar: 0, Policy: EntryThrottlePolicyDrop},
+		Cooldown:                  CooldownConfig{Duration: "", PerSide: false, Symbols: nil},
+		MaxOrdersPerDay:           0,
+		MaxNotionalPerDay:         0,
+		StrategyAllocations:       nil,
+		MaxOpenPositions:          0,
+		PositionLimit:             PositionLimitConfig{MaxPositionQuantity: 0, MaxPositionNotional: 0, Mode: PositionLimitModeReject, Symbols: nil},
+		ATRSizing:                 ATRSizingConfig{ATRMultiple: 0, RiskPercentage: 0, Period: 0},
+		MinHoldingPeriod:          "",
+		BenchmarkSymbol:           "",
+		EquityCurve:               EquityCurveConfig{Disabled: false, Interval: ""},
+		MarkToMarket:              MarkToMarketConfig{Times: nil, Timezone: ""},
+		AutoFlatten:               AutoFlattenConfig{Time: "", Timezone: ""},
+		MaxVolumeParticipation:    0,
+		Slippage:                  SlippageConfig{Model: SlippageModelNone, Bps: 0, MaxBps: 0, SpreadFraction: 0},
+		LimitFillPolicy:           LimitFillTouch,
+		FillPricePolicy:           FillPriceMid,
+		FundingRates:              nil,
+		Margin:                    MarginConfig{Leverage: 0, MaintenanceMarginFraction: 0},
+		Commission:                commission_fee.Config{Rate: 0, Tiers: nil, MakerRate: 0, TakerRate: 0, MinFee: 0, MaxFee: 0},
+		SymbolWorkers:             0,
+		WarmupBars:                0,
+		SignalOnly:                false,
+		RandomSeed:                0,
 	}
 }
 
@@ -208,10 +443,41 @@ func EmptyConfig() BacktestEngineV1Config {
 		StartTime:                 optional.None[time.Time](),
 		EndTime:                   optional.None[time.Time](),
 		DecimalPrecision:          1,
+		SymbolDecimalPrecision:    nil,
+		RoundingMode:              RoundingFloor,
+		LotSize:                   nil,
 		MarketDataCacheSize:       1000,
 		PortfolioCalculation:      PortfolioCalculationAverageCost,
 		RiskFreeRate:              0,
 		SharpeAnnualizationFactor: 252,
+		ResultsExportPath:         "",
+		SignalConfirmationBars:    0,
+		OutputFormats:             []ResultOutputFormat{ResultOutputFormatParquet},
+		BenchmarkRelativeStop:     BenchmarkRelativeStopConfig{BenchmarkSymbol: "", MaxUnderperformance: 0},
+		EntryThrottle:             EntryThrottleConfig{MaxEntriesPerBar: 0, Policy: EntryThrottlePolicyDrop},
+		Cooldown:                  CooldownConfig{Duration: "", PerSide: false, Symbols: nil},
+		MaxOrdersPerDay:           0,
+		MaxNotionalPerDay:         0,
+		StrategyAllocations:       nil,
+		MaxOpenPositions:          0,
+		PositionLimit:             PositionLimitConfig{MaxPositionQuantity: 0, MaxPositionNotional: 0, Mode: PositionLimitModeReject, Symbols: nil},
+		ATRSizing:                 ATRSizingConfig{ATRMultiple: 0, RiskPercentage: 0, Period: 0},
+		MinHoldingPeriod:          "",
+		BenchmarkSymbol:           "",
+		EquityCurve:               EquityCurveConfig{Disabled: false, Interval: ""},
+		MarkToMarket:              MarkToMarketConfig{Times: nil, Timezone: ""},
+		AutoFlatten:               AutoFlattenConfig{Time: "", Timezone: ""},
+		MaxVolumeParticipation:    0,
+		Slippage:                  SlippageConfig{Model: SlippageModelNone, Bps: 0, MaxBps: 0, SpreadFraction: 0},
+		LimitFillPolicy:           LimitFillTouch,
+		FillPricePolicy:           FillPriceMid,
+		FundingRates:              nil,
+		Margin:                    MarginConfig{Leverage: 0, MaintenanceMarginFraction: 0},
+		Commission:                commission_fee.Config{Rate: 0, Tiers: nil, MakerRate: 0, TakerRate: 0, MinFee: 0, MaxFee: 0},
+		SymbolWorkers:             0,
+		WarmupBars:                0,
+		SignalOnly:                false,
+		RandomSeed:                0,
 	}
 }
 
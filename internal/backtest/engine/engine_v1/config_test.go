@@ -218,6 +218,78 @@ portfolio_calculation: average_cost
 	}
 }
 
+func (suite *ConfigTestSuite) TestUnmarshalYAMLCommission() {
+	yamlData := `
+initial_capital: 10000
+broker: tiered
+commission:
+  rate: 0.001
+  tiers:
+    - volume_threshold: 0
+      rate: 0.001
+    - volume_threshold: 1000000
+      rate: 0.0009
+`
+
+	var config BacktestEngineV1Config
+	err := yaml.Unmarshal([]byte(yamlData), &config)
+
+	suite.Require().NoError(err)
+	suite.Equal(commission_fee.BrokerTiered, config.Broker)
+	suite.Equal(0.001, config.Commission.Rate)
+	suite.Equal([]commission_fee.Tier{
+		{VolumeThreshold: 0, Rate: 0.001},
+		{VolumeThreshold: 1000000, Rate: 0.0009},
+	}, config.Commission.Tiers)
+}
+
+func (suite *ConfigTestSuite) TestUnmarshalYAMLSymbolDecimalPrecision() {
+	yamlData := `
+initial_capital: 10000
+decimal_precision: 2
+symbol_decimal_precision:
+  BTC/USD: 8
+  AAPL: 0
+`
+
+	var config BacktestEngineV1Config
+	err := yaml.Unmarshal([]byte(yamlData), &config)
+
+	suite.Require().NoError(err)
+	suite.Equal(2, config.DecimalPrecision)
+	suite.Equal(map[string]int{"BTC/USD": 8, "AAPL": 0}, config.SymbolDecimalPrecision)
+
+	out, err := yaml.Marshal(config)
+	suite.Require().NoError(err)
+	suite.Contains(string(out), "symbol_decimal_precision:")
+}
+
+func (suite *ConfigTestSuite) TestUnmarshalYAMLFundingRates() {
+	yamlData := `
+initial_capital: 10000
+funding_rates:
+  - symbol: BTCUSDT
+    time: 2024-01-01T08:00:00Z
+    rate: 0.0001
+  - symbol: BTCUSDT
+    time: 2024-01-01T16:00:00Z
+    rate: -0.00005
+`
+
+	var config BacktestEngineV1Config
+	err := yaml.Unmarshal([]byte(yamlData), &config)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(config.FundingRates, 2)
+	suite.Equal("BTCUSDT", config.FundingRates[0].Symbol)
+	suite.Equal(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), config.FundingRates[0].Time)
+	suite.Equal(-0.00005, config.FundingRates[1].Rate)
+
+	out, err := yaml.Marshal(config)
+	suite.Require().NoError(err)
+	suite.Contains(string(out), "funding_rates:")
+}
+
 func (suite *ConfigTestSuite) TestResolvePortfolioCalculation() {
 	suite.Equal(PortfolioCalculationFIFO, ResolvePortfolioCalculation(PortfolioCalculationFIFO))
 	suite.Equal(PortfolioCalculationAverageCost, ResolvePortfolioCalculation(PortfolioCalculationAverageCost))
@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// CooldownConfig rejects orders on a symbol for a while after the symbol's last
+// accepted order, to stop a strategy from trading the same symbol on every bar.
+type CooldownConfig struct {
+	Duration string            `yaml:"duration" json:"duration" jsonschema:"title=Duration,description=Time after an accepted order during which further orders on the same symbol are rejected with reason cooldown (e.g. 5m or 1h). Measured on the bar time. Leave empty to disable."`
+	PerSide  bool              `yaml:"per_side" json:"per_side" jsonschema:"title=Per Side,description=Track buys and sells separately so a buy only starts the cooldown of later buys and a sell of later sells. When false any accepted order starts the cooldown of the symbol.,default=false"`
+	Symbols  map[string]string `yaml:"symbols" json:"symbols" jsonschema:"title=Symbols,description=Cooldown durations for individual symbols that override the default duration. Use 0s to exempt a symbol."`
+}
+
+// Enabled reports whether a cooldown is configured for any symbol.
+func (c CooldownConfig) Enabled() bool {
+	return c.Duration != "" || len(c.Symbols) > 0
+}
+
+// orderCooldown holds the parsed cooldown durations and the time of the last
+// accepted order of each symbol, or of each symbol and side when per side.
+type orderCooldown struct {
+	duration     time.Duration
+	perSide      bool
+	symbols      map[string]time.Duration
+	lastAccepted map[string]time.Time
+}
+
+// newOrderCooldown parses the config into an orderCooldown.
+func newOrderCooldown(config CooldownConfig) (*orderCooldown, error) {
+	cooldown := &orderCooldown{
+		duration:     0,
+		perSide:      config.PerSide,
+		symbols:      make(map[string]time.Duration, len(config.Symbols)),
+		lastAccepted: map[string]time.Time{},
+	}
+
+	if config.Duration != "" {
+		duration, err := parseCooldownDuration(config.Duration)
+		if err != nil {
+			return nil, err
+		}
+
+		cooldown.duration = duration
+	}
+
+	for symbol, value := range config.Symbols {
+		duration, err := parseCooldownDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("symbol %s: %w", symbol, err)
+		}
+
+		cooldown.symbols[symbol] = duration
+	}
+
+	return cooldown, nil
+}
+
+// parseCooldownDuration parses a non-negative Go duration such as 90s or 5m.
+func parseCooldownDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cooldown duration %q: %w", value, err)
+	}
+
+	if duration < 0 {
+		return 0, fmt.Errorf("cooldown duration must not be negative: %s", value)
+	}
+
+	return duration, nil
+}
+
+// durationFor returns the cooldown that applies to the symbol.
+func (c *orderCooldown) durationFor(symbol string) time.Duration {
+	if duration, ok := c.symbols[symbol]; ok {
+		return duration
+	}
+
+	return c.duration
+}
+
+// key returns the key the last accepted order time of the order is kept under.
+func (c *orderCooldown) key(order types.ExecuteOrder) string {
+	if c.perSide {
+		return order.Symbol + "|" + string(order.Side)
+	}
+
+	return order.Symbol
+}
+
+// remaining returns how much of the cooldown is left at now, or 0 when the order may be placed.
+func (c *orderCooldown) remaining(order types.ExecuteOrder, now time.Time) time.Duration {
+	duration := c.durationFor(order.Symbol)
+	if duration <= 0 {
+		return 0
+	}
+
+	last, ok := c.lastAccepted[c.key(order)]
+	if !ok {
+		return 0
+	}
+
+	return max(last.Add(duration).Sub(now), 0)
+}
+
+// SetCooldown configures the per-symbol order cooldown. An empty config disables it.
+func (b *BacktestTrading) SetCooldown(config CooldownConfig) error {
+	if !config.Enabled() {
+		b.cooldown = nil
+
+		return nil
+	}
+
+	cooldown, err := newOrderCooldown(config)
+	if err != nil {
+		return err
+	}
+
+	b.cooldown = cooldown
+
+	return nil
+}
+
+// rejectDuringCooldown stores a failed order and reports true when the order is
+// placed before the cooldown of the symbol's last accepted order has elapsed.
+func (b *BacktestTrading) rejectDuringCooldown(order types.ExecuteOrder) (bool, error) {
+	if b.cooldown == nil {
+		return false, nil
+	}
+
+	remaining := b.cooldown.remaining(order, b.marketData.Time)
+	if remaining <= 0 {
+		return false, nil
+	}
+
+	failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonCooldown,
+		fmt.Sprintf("%s is in cooldown for another %s", order.Symbol, remaining))
+
+	return true, b.state.StoreFailedOrder(failedOrder)
+}
+
+// recordAcceptedOrder starts the cooldown of the order's symbol at the current bar
+// time, unless the order was rejected further down PlaceOrder.
+func (b *BacktestTrading) recordAcceptedOrder(order types.ExecuteOrder) {
+	if b.lastFailedOrderID == order.ID {
+		return
+	}
+
+	b.cooldown.lastAccepted[b.cooldown.key(order)] = b.marketData.Time
+}
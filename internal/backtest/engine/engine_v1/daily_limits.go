@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// dailyLimits counts the orders accepted and the notional they trade on the
+// current UTC day, measured on the bar time.
+type dailyLimits struct {
+	// maxOrders is the number of orders accepted per day. 0 disables the limit.
+	maxOrders int
+	// maxNotional is the notional traded per day. 0 disables the limit.
+	maxNotional float64
+	// day is the start of the UTC day the counts belong to.
+	day      time.Time
+	orders   int
+	notional float64
+}
+
+// advance resets the counts when now falls on a later UTC day than the counts.
+func (l *dailyLimits) advance(now time.Time) {
+	now = now.UTC()
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Equal(l.day) {
+		return
+	}
+
+	l.reset()
+	l.day = day
+}
+
+// reset clears the counts.
+func (l *dailyLimits) reset() {
+	l.day = time.Time{}
+	l.orders = 0
+	l.notional = 0
+}
+
+// SetDailyLimits caps the number of orders and the notional, quantity times
+// order price, accepted per UTC day. Orders over either limit are rejected with
+// reason max_orders_per_day or max_notional_per_day. Zero disables a limit.
+func (b *BacktestTrading) SetDailyLimits(maxOrders int, maxNotional float64) error {
+	if maxOrders < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "max orders per day must not be negative: %d", maxOrders)
+	}
+
+	if maxNotional < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "max notional per day must not be negative: %.2f", maxNotional)
+	}
+
+	if maxOrders == 0 && maxNotional == 0 {
+		b.dailyLimits = nil
+
+		return nil
+	}
+
+	b.dailyLimits = &dailyLimits{
+		maxOrders:   maxOrders,
+		maxNotional: maxNotional,
+		day:         time.Time{},
+		orders:      0,
+		notional:    0,
+	}
+
+	return nil
+}
+
+// rejectOverDailyLimit stores a failed order and reports true when accepting
+// the order would exceed the day's order count or notional.
+func (b *BacktestTrading) rejectOverDailyLimit(order types.ExecuteOrder) (bool, error) {
+	if b.dailyLimits == nil {
+		return false, nil
+	}
+
+	limits := b.dailyLimits
+	limits.advance(b.marketData.Time)
+
+	var failedOrder types.Order
+
+	switch notional := order.Quantity * order.Price; {
+	case limits.maxOrders > 0 && limits.orders >= limits.maxOrders:
+		failedOrder = b.createFailedOrder(order, order.Price, types.OrderReasonMaxOrdersPerDay,
+			fmt.Sprintf("%d orders were already placed today, the daily limit", limits.orders))
+	case limits.maxNotional > 0 && limits.notional+notional > limits.maxNotional:
+		failedOrder = b.createFailedOrder(order, order.Price, types.OrderReasonMaxNotionalPerDay,
+			fmt.Sprintf("order notional %.2f would bring today's notional of %.2f above the daily limit of %.2f",
+				notional, limits.notional, limits.maxNotional))
+	default:
+		return false, nil
+	}
+
+	return true, b.state.StoreFailedOrder(failedOrder)
+}
+
+// recordDailyOrder adds the order to the day's counts, unless it was rejected
+// further down PlaceOrder.
+func (b *BacktestTrading) recordDailyOrder(order types.ExecuteOrder) {
+	if b.lastFailedOrderID == order.ID {
+		return
+	}
+
+	b.dailyLimits.orders++
+	b.dailyLimits.notional += order.Quantity * order.Price
+}
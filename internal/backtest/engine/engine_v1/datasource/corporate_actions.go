@@ -0,0 +1,119 @@
+package datasource
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// adjustedMarketData back-adjusts the rows of the market_data view for the
+// loaded corporate actions. Each row is joined to the first action after it,
+// whose factors already include every later action of the symbol, so prices
+// are multiplied by the price factor and volumes by the cumulative split ratio.
+// Quotes are adjusted like prices when the data has them, filling the %s verb.
+// It is aliased to market_data so it can replace the view in any query.
+const adjustedMarketData = `(
+	SELECT
+		m.time,
+		m.symbol,
+		m.open * COALESCE(f.price_factor, 1) AS open,
+		m.high * COALESCE(f.price_factor, 1) AS high,
+		m.low * COALESCE(f.price_factor, 1) AS low,
+		m.close * COALESCE(f.price_factor, 1) AS close,
+		m.volume * COALESCE(f.volume_factor, 1) AS volume%s
+	FROM market_data m
+	ASOF LEFT JOIN corporate_action_factors f ON m.symbol = f.symbol AND m.time < f.date
+) AS market_data`
+
+// LoadCorporateActions loads the splits and cash dividends used for adjusted reads
+// from a CSV or parquet file with the columns date, symbol, split_ratio and dividend.
+// The split ratio is the number of new shares per old share, so a 2:1 split has a
+// ratio of 2. An empty or zero ratio means no split and an empty dividend means none.
+// An action applies to the bars before its date. Loading replaces any actions loaded
+// before, and the market_data view itself is left unchanged.
+func (d *DuckDBDataSource) LoadCorporateActions(path string) error {
+	d.logger.Debug("Loading corporate actions", zap.String("path", path))
+
+	reader := "read_parquet"
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		reader = "read_csv_auto"
+	}
+
+	query := fmt.Sprintf(`
+		CREATE OR REPLACE TABLE corporate_actions AS
+		SELECT
+			CAST(date AS TIMESTAMP) AS date,
+			CAST(symbol AS TEXT) AS symbol,
+			CASE WHEN CAST(split_ratio AS DOUBLE) > 0 THEN CAST(split_ratio AS DOUBLE) ELSE 1 END AS split_ratio,
+			COALESCE(CAST(dividend AS DOUBLE), 0) AS dividend
+		FROM %s('%s');
+	`, reader, path)
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to load corporate actions: %w", err)
+	}
+
+	// The dividend factor is 1 - dividend / close of the last bar before the
+	// ex-date. It is computed when read, so it follows the current market_data view.
+	_, err := d.db.Exec(`
+		CREATE OR REPLACE VIEW corporate_action_factors AS
+		WITH actions AS (
+			SELECT date, symbol, PRODUCT(split_ratio) AS split_ratio, SUM(dividend) AS dividend
+			FROM corporate_actions
+			GROUP BY date, symbol
+		), factors AS (
+			SELECT
+				a.date,
+				a.symbol,
+				a.split_ratio,
+				CASE WHEN a.dividend > 0 THEN 1 - a.dividend / (
+					SELECT m.close FROM market_data m
+					WHERE m.symbol = a.symbol AND m.time < a.date
+					ORDER BY m.time DESC
+					LIMIT 1
+				) ELSE 1 END / a.split_ratio AS price_factor
+			FROM actions a
+		)
+		SELECT
+			date,
+			symbol,
+			PRODUCT(price_factor) OVER later AS price_factor,
+			PRODUCT(split_ratio) OVER later AS volume_factor
+		FROM factors
+		WINDOW later AS (PARTITION BY symbol ORDER BY date DESC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corporate action factors: %w", err)
+	}
+
+	d.corporateActionsLoaded = true
+	d.queryCache.clear()
+
+	return nil
+}
+
+// SetAdjusted sets whether ReadAll, GetRange and the other reads of OHLCV rows
+// return prices and volumes back-adjusted for the loaded corporate actions.
+// It has no effect until LoadCorporateActions has been called. Count,
+// GetAllSymbols and ExecuteSQL always read the raw market_data view.
+func (d *DuckDBDataSource) SetAdjusted(adjusted bool) {
+	d.adjusted = adjusted
+}
+
+// marketDataSource returns the relation the OHLCV reads select from.
+func (d *DuckDBDataSource) marketDataSource() string {
+	if d.adjusted && d.corporateActionsLoaded {
+		quotes := ""
+		if d.hasQuotes {
+			quotes = `,
+		m.bid * COALESCE(f.price_factor, 1) AS bid,
+		m.ask * COALESCE(f.price_factor, 1) AS ask`
+		}
+
+		return fmt.Sprintf(adjustedMarketData, quotes)
+	}
+
+	return "market_data"
+}
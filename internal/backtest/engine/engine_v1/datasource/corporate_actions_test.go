@@ -0,0 +1,140 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// splitSeries returns four daily AAPL bars around a 2:1 split on the third day,
+// and one MSFT bar that no action applies to.
+func splitSeries() []types.MarketData {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	return []types.MarketData{
+		{Symbol: "AAPL", Time: day(1), Open: 100, High: 104, Low: 98, Close: 102, Volume: 1000},
+		{Symbol: "AAPL", Time: day(2), Open: 102, High: 106, Low: 100, Close: 104, Volume: 1200},
+		{Symbol: "AAPL", Time: day(3), Open: 52, High: 54, Low: 51, Close: 53, Volume: 2600},
+		{Symbol: "AAPL", Time: day(4), Open: 53, High: 55, Low: 52, Close: 50, Volume: 2400},
+		{Symbol: "MSFT", Time: day(1), Open: 300, High: 310, Low: 295, Close: 305, Volume: 500},
+	}
+}
+
+func (suite *DuckDBTestSuite) initializeCorporateActions(data []types.MarketData, actions string) {
+	tmpDir := suite.T().TempDir()
+
+	parquetPath := filepath.Join(tmpDir, "data.parquet")
+	suite.Require().NoError(writeTestDataToParquet(data, parquetPath))
+	suite.Require().NoError(suite.ds.Initialize(parquetPath))
+
+	actionsPath := filepath.Join(tmpDir, "actions.csv")
+	suite.Require().NoError(os.WriteFile(actionsPath, []byte(actions), 0o600))
+	suite.Require().NoError(suite.ds.LoadCorporateActions(actionsPath))
+}
+
+func (suite *DuckDBTestSuite) TestAdjustedSplit() {
+	suite.initializeCorporateActions(splitSeries(), "date,symbol,split_ratio,dividend\n2024-01-03,AAPL,2,\n")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	// Raw reads are unchanged until adjustment is switched on
+	raw, err := suite.ds.GetRangeForSymbol("AAPL", start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Require().Len(raw, 4)
+	suite.Equal(102.0, raw[0].Close)
+	suite.Equal(1000.0, raw[0].Volume)
+
+	suite.ds.SetAdjusted(true)
+
+	var adjusted []types.MarketData
+
+	for data, err := range suite.ds.ReadAll(optional.None[time.Time](), optional.None[time.Time]()) {
+		suite.Require().NoError(err)
+
+		if data.Symbol == "AAPL" {
+			adjusted = append(adjusted, data)
+		}
+	}
+
+	suite.Require().Len(adjusted, 4)
+
+	// Bars before the split have halved prices and doubled volumes
+	for i := range 2 {
+		suite.InDelta(raw[i].Open/2, adjusted[i].Open, 1e-9)
+		suite.InDelta(raw[i].High/2, adjusted[i].High, 1e-9)
+		suite.InDelta(raw[i].Low/2, adjusted[i].Low, 1e-9)
+		suite.InDelta(raw[i].Close/2, adjusted[i].Close, 1e-9)
+		suite.InDelta(raw[i].Volume*2, adjusted[i].Volume, 1e-9)
+	}
+
+	// Bars from the split date on are left as they are
+	suite.Equal(raw[2:], adjusted[2:])
+
+	rangeData, err := suite.ds.GetRange(start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Require().Len(rangeData, 5)
+
+	for _, data := range rangeData {
+		if data.Symbol == "MSFT" {
+			suite.Equal(305.0, data.Close)
+			suite.Equal(500.0, data.Volume)
+		}
+	}
+
+	previous, err := suite.ds.GetPreviousNumberOfDataPoints(end, "AAPL", 4)
+	suite.Require().NoError(err)
+	suite.Equal(adjusted, previous)
+
+	// The market_data view itself stays raw
+	results, err := suite.ds.ExecuteSQL("SELECT close FROM market_data WHERE symbol = 'AAPL' ORDER BY time LIMIT 1")
+	suite.Require().NoError(err)
+	suite.Equal(102.0, results[0].Values["close"])
+
+	suite.ds.SetAdjusted(false)
+
+	last, err := suite.ds.GetPreviousNumberOfDataPoints(end, "AAPL", 4)
+	suite.Require().NoError(err)
+	suite.Equal(raw, last)
+}
+
+func (suite *DuckDBTestSuite) TestAdjustedDividendAndSplit() {
+	// A dividend of 1 on the fourth day, when the previous close was 53
+	suite.initializeCorporateActions(splitSeries(), "date,symbol,split_ratio,dividend\n2024-01-03,AAPL,2,0\n2024-01-04,AAPL,,1\n")
+	suite.ds.SetAdjusted(true)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	data, err := suite.ds.GetRangeForSymbol("AAPL", start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Require().Len(data, 4)
+
+	dividendFactor := 1 - 1.0/53
+
+	suite.InDelta(102*dividendFactor/2, data[0].Close, 1e-9)
+	suite.InDelta(2000.0, data[0].Volume, 1e-9)
+	suite.InDelta(53*dividendFactor, data[2].Close, 1e-9)
+	suite.InDelta(2600.0, data[2].Volume, 1e-9)
+	suite.Equal(50.0, data[3].Close)
+}
+
+func (suite *DuckDBTestSuite) TestAdjustedWithoutCorporateActions() {
+	tmpDir := suite.T().TempDir()
+
+	parquetPath := filepath.Join(tmpDir, "data.parquet")
+	suite.Require().NoError(writeTestDataToParquet(splitSeries(), parquetPath))
+	suite.Require().NoError(suite.ds.Initialize(parquetPath))
+
+	// Nothing to adjust for, so the raw rows are read
+	suite.ds.SetAdjusted(true)
+
+	last, err := suite.ds.ReadLastData("AAPL")
+	suite.Require().NoError(err)
+	suite.Equal(50.0, last.Close)
+
+	suite.Error(suite.ds.LoadCorporateActions(filepath.Join(tmpDir, "missing.csv")))
+}
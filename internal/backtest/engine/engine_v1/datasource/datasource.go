@@ -36,6 +36,9 @@ type DataSource interface {
 	ReadAll(start optional.Option[time.Time], end optional.Option[time.Time]) func(yield func(types.MarketData, error) bool)
 	// GetRange reads a range of data from the data source and yields it to the caller
 	GetRange(start time.Time, end time.Time, interval optional.Option[Interval]) ([]types.MarketData, error)
+	// GetRangeForSymbol reads a range of data for a single symbol. Use it instead of GetRange
+	// when the data source holds several symbols so aggregation does not mix instruments.
+	GetRangeForSymbol(symbol string, start time.Time, end time.Time, interval optional.Option[Interval]) ([]types.MarketData, error)
 	// GetPreviousNumberOfDataPoints returns the specified number of historical data points for a given symbol,
 	// ending at the specified time. The data points are returned in chronological order from oldest to newest.
 	GetPreviousNumberOfDataPoints(end time.Time, symbol string, count int) ([]types.MarketData, error)
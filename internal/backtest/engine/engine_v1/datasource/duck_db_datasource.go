@@ -3,7 +3,10 @@ package datasource
 import (
 	"database/sql"
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Masterminds/squirrel"
@@ -19,6 +22,21 @@ type DuckDBDataSource struct {
 	db     *sql.DB
 	logger *logger.Logger
 	sq     squirrel.StatementBuilderType
+	// symbolWarningChecked is set once the dataset has been checked for multiple symbols.
+	// It is atomic since strategies may read ranges from several goroutines.
+	symbolWarningChecked atomic.Bool
+	// adjusted makes the OHLCV reads return split and dividend adjusted prices
+	// once corporate actions have been loaded. See SetAdjusted.
+	adjusted               bool
+	corporateActionsLoaded bool
+	// deduplicate keeps a single row per symbol and time when the market_data
+	// view is created. See SetDeduplicate.
+	deduplicate bool
+	// hasQuotes is set by Initialize when the data has bid and ask columns.
+	hasQuotes bool
+	// queryCache holds recent GetRange and ReadRecordsFrom* results. It is
+	// cleared whenever the data is reloaded. See SetQueryCacheSize.
+	queryCache *queryCache
 }
 
 // NewDataSource creates a new DuckDB data source instance with the specified database path.
@@ -42,35 +60,67 @@ func NewDataSource(path string, logger *logger.Logger) (DataSource, error) {
 	}
 
 	return &DuckDBDataSource{
-		db:     db,
-		logger: logger,
-		sq:     squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		db:                     db,
+		logger:                 logger,
+		sq:                     squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		symbolWarningChecked:   atomic.Bool{},
+		adjusted:               false,
+		corporateActionsLoaded: false,
+		deduplicate:            false,
+		hasQuotes:              false,
+		queryCache:             newQueryCache(defaultQueryCacheSize),
 	}, nil
 }
 
 // Initialize implements DataSource.
+// The path is a parquet file or a glob matching several. Several files are
+// read as one dataset sorted by time, with their columns matched by name.
 func (d *DuckDBDataSource) Initialize(path string) error {
 	d.logger.Debug("Initializing DuckDB data source", zap.String("path", path))
 
+	d.symbolWarningChecked.Store(false)
+	d.queryCache.clear()
+
 	// First drop the view if it exists
 	_, err := d.db.Exec(`DROP VIEW IF EXISTS market_data;`)
 	if err != nil {
 		return fmt.Errorf("failed to drop existing view: %w", err)
 	}
 
-	// Create a view from the parquet file - using raw SQL as Squirrel doesn't support CREATE VIEW
-	// Use SELECT * to include all columns from the parquet file (including indicator columns for testing)
-	query := fmt.Sprintf(`
-		CREATE VIEW market_data AS
-		SELECT * FROM read_parquet('%s');
-	`, path)
+	files, err := parquetFiles(path)
+	if err != nil {
+		return err
+	}
 
-	_, err = d.db.Exec(query)
+	// Create a view from the parquet files - using raw SQL as Squirrel doesn't support CREATE VIEW
+	// Use SELECT * to include all columns from the parquet files (including indicator columns for testing)
+	_, err = d.db.Exec(d.marketDataViewQuery(files))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return d.detectQuotes()
+}
+
+// SetQueryCacheSize sets how many GetRange, GetRangeForSymbol and
+// ReadRecordsFrom* results are kept, so repeated reads with the same
+// parameters skip the database. The least recently used result is evicted
+// first. A size of 0 disables the cache. Cached results are cleared.
+func (d *DuckDBDataSource) SetQueryCacheSize(size int) {
+	if d.queryCache == nil {
+		d.queryCache = newQueryCache(size)
+
+		return
+	}
+
+	d.queryCache.resize(size)
+}
+
+// SetDeduplicate sets whether Initialize keeps a single row per symbol and time,
+// for datasets whose files overlap. The kept row is the last one, taking the
+// files in the sorted order of their paths. It applies to the next Initialize.
+func (d *DuckDBDataSource) SetDeduplicate(deduplicate bool) {
+	d.deduplicate = deduplicate
 }
 
 // Count implements DataSource.
@@ -134,9 +184,8 @@ func (d *DuckDBDataSource) ReadAll(start optional.Option[time.Time], end optiona
 
 		// Build the base query using raw SQL for better compatibility
 		query := `
-			SELECT time, symbol, open, high, low, close, volume 
-			FROM market_data
-		`
+			SELECT time, symbol, open, high, low, close, volume, ` + strings.Join(d.quoteColumns(), ", ") + `
+			FROM ` + d.marketDataSource()
 
 		// Add time range conditions if provided
 		var conditions []string
@@ -166,7 +215,7 @@ func (d *DuckDBDataSource) ReadAll(start optional.Option[time.Time], end optiona
 		// Use a prepared statement for better performance
 		stmt, err := d.db.Prepare(query)
 		if err != nil {
-			yield(types.MarketData{Id: "", Symbol: "", Time: time.Time{}, Open: 0, High: 0, Low: 0, Close: 0, Volume: 0}, err)
+			yield(types.MarketData{Id: "", Symbol: "", Time: time.Time{}, Open: 0, High: 0, Low: 0, Close: 0, Volume: 0, Bid: 0, Ask: 0}, err)
 
 			return
 		}
@@ -180,7 +229,7 @@ func (d *DuckDBDataSource) ReadAll(start optional.Option[time.Time], end optiona
 		}
 
 		if err != nil {
-			yield(types.MarketData{Id: "", Symbol: "", Time: time.Time{}, Open: 0, High: 0, Low: 0, Close: 0, Volume: 0}, err)
+			yield(types.MarketData{Id: "", Symbol: "", Time: time.Time{}, Open: 0, High: 0, Low: 0, Close: 0, Volume: 0, Bid: 0, Ask: 0}, err)
 
 			return
 		}
@@ -192,14 +241,14 @@ func (d *DuckDBDataSource) ReadAll(start optional.Option[time.Time], end optiona
 
 		for rows.Next() {
 			var (
-				timestamp                      time.Time
-				open, high, low, close, volume float64
-				symbol                         string
+				timestamp                                time.Time
+				open, high, low, close, volume, bid, ask float64
+				symbol                                   string
 			)
 
-			err := rows.Scan(&timestamp, &symbol, &open, &high, &low, &close, &volume)
+			err := rows.Scan(&timestamp, &symbol, &open, &high, &low, &close, &volume, &bid, &ask)
 			if err != nil {
-				yield(types.MarketData{Id: "", Symbol: "", Time: time.Time{}, Open: 0, High: 0, Low: 0, Close: 0, Volume: 0}, err)
+				yield(types.MarketData{Id: "", Symbol: "", Time: time.Time{}, Open: 0, High: 0, Low: 0, Close: 0, Volume: 0, Bid: 0, Ask: 0}, err)
 
 				return
 			}
@@ -213,6 +262,8 @@ func (d *DuckDBDataSource) ReadAll(start optional.Option[time.Time], end optiona
 				Low:    low,
 				Close:  close,
 				Volume: volume,
+				Bid:    bid,
+				Ask:    ask,
 			}
 
 			batch = append(batch, marketData)
@@ -239,7 +290,22 @@ func (d *DuckDBDataSource) ReadAll(start optional.Option[time.Time], end optiona
 }
 
 // GetRange implements DataSource with optimized query.
+// Rows of all symbols are returned; with an interval, each symbol is bucketed separately.
+// A warning is logged once per dataset when it holds more than one symbol.
 func (d *DuckDBDataSource) GetRange(start time.Time, end time.Time, interval optional.Option[Interval]) ([]types.MarketData, error) {
+	d.warnIfMultipleSymbols()
+
+	return d.getRange(optional.None[string](), start, end, interval)
+}
+
+// GetRangeForSymbol implements DataSource. Only rows of the given symbol are read
+// and aggregated.
+func (d *DuckDBDataSource) GetRangeForSymbol(symbol string, start time.Time, end time.Time, interval optional.Option[Interval]) ([]types.MarketData, error) {
+	return d.getRange(optional.Some(symbol), start, end, interval)
+}
+
+// getRange reads a range of data, optionally filtered to a single symbol.
+func (d *DuckDBDataSource) getRange(symbol optional.Option[string], start time.Time, end time.Time, interval optional.Option[Interval]) ([]types.MarketData, error) {
 	// Process interval parameter
 	var intervalMinutes optional.Option[int] = optional.None[int]()
 
@@ -253,11 +319,16 @@ func (d *DuckDBDataSource) GetRange(start time.Time, end time.Time, interval opt
 	}
 
 	// Build the query
-	query, args, err := d.buildGetRangeQuery(start, end, intervalMinutes)
+	query, args, err := d.buildGetRangeQuery(symbol, start, end, intervalMinutes)
 	if err != nil {
 		return nil, err
 	}
 
+	cacheKey := queryCacheKey(query, args...)
+	if cached, ok := d.queryCache.get(cacheKey); ok {
+		return cached, nil
+	}
+
 	// Use prepared statement for better performance
 	stmt, err := d.db.Prepare(query)
 	if err != nil {
@@ -276,12 +347,12 @@ func (d *DuckDBDataSource) GetRange(start time.Time, end time.Time, interval opt
 
 	for rows.Next() {
 		var (
-			timestamp                      time.Time
-			open, high, low, close, volume float64
-			symbol                         string
+			timestamp                                time.Time
+			open, high, low, close, volume, bid, ask float64
+			symbol                                   string
 		)
 
-		err := rows.Scan(&timestamp, &symbol, &open, &high, &low, &close, &volume)
+		err := rows.Scan(&timestamp, &symbol, &open, &high, &low, &close, &volume, &bid, &ask)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
@@ -295,6 +366,8 @@ func (d *DuckDBDataSource) GetRange(start time.Time, end time.Time, interval opt
 			Low:    low,
 			Close:  close,
 			Volume: volume,
+			Bid:    bid,
+			Ask:    ask,
 		}
 
 		result = append(result, marketData)
@@ -304,6 +377,8 @@ func (d *DuckDBDataSource) GetRange(start time.Time, end time.Time, interval opt
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	d.queryCache.put(cacheKey, result)
+
 	return result, nil
 }
 
@@ -327,7 +402,7 @@ func (d *DuckDBDataSource) ReadRecordsFromStart(start time.Time, number int, int
 				MIN(low) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol) as low,
 				LAST_VALUE(close) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol ORDER BY time ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) as close,
 				SUM(volume) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol) as volume
-			FROM market_data 
+			FROM %s 
 			WHERE time >= $1
 		)
 		SELECT DISTINCT
@@ -341,7 +416,12 @@ func (d *DuckDBDataSource) ReadRecordsFromStart(start time.Time, number int, int
 		FROM time_buckets
 		ORDER BY bucket_time ASC
 		LIMIT $2
-	`, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes)
+	`, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, d.marketDataSource())
+
+	cacheKey := queryCacheKey(query, start, number)
+	if cached, ok := d.queryCache.get(cacheKey); ok {
+		return cached, nil
+	}
 
 	// Use prepared statement for better performance
 	stmt, err := d.db.Prepare(query)
@@ -380,6 +460,8 @@ func (d *DuckDBDataSource) ReadRecordsFromStart(start time.Time, number int, int
 			Low:    low,
 			Close:  close,
 			Volume: volume,
+			Bid:    0,
+			Ask:    0,
 		}
 
 		result = append(result, marketData)
@@ -389,6 +471,8 @@ func (d *DuckDBDataSource) ReadRecordsFromStart(start time.Time, number int, int
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	d.queryCache.put(cacheKey, result)
+
 	return result, nil
 }
 
@@ -412,7 +496,7 @@ func (d *DuckDBDataSource) ReadRecordsFromEnd(end time.Time, number int, interva
 				MIN(low) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol) as low,
 				LAST_VALUE(close) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol ORDER BY time ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) as close,
 				SUM(volume) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol) as volume
-			FROM market_data 
+			FROM %s 
 			WHERE time <= $1
 		)
 		SELECT DISTINCT
@@ -426,7 +510,12 @@ func (d *DuckDBDataSource) ReadRecordsFromEnd(end time.Time, number int, interva
 		FROM time_buckets
 		ORDER BY bucket_time DESC
 		LIMIT $2
-	`, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes)
+	`, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, intervalMinutes, d.marketDataSource())
+
+	cacheKey := queryCacheKey(query, end, number)
+	if cached, ok := d.queryCache.get(cacheKey); ok {
+		return cached, nil
+	}
 
 	// Use prepared statement for better performance
 	stmt, err := d.db.Prepare(query)
@@ -465,6 +554,8 @@ func (d *DuckDBDataSource) ReadRecordsFromEnd(end time.Time, number int, interva
 			Low:    low,
 			Close:  close,
 			Volume: volume,
+			Bid:    0,
+			Ask:    0,
 		}
 
 		result = append(result, marketData)
@@ -479,6 +570,8 @@ func (d *DuckDBDataSource) ReadRecordsFromEnd(end time.Time, number int, interva
 		result[i], result[j] = result[j], result[i]
 	}
 
+	d.queryCache.put(cacheKey, result)
+
 	return result, nil
 }
 
@@ -546,8 +639,8 @@ func (d *DuckDBDataSource) ReadLastData(symbol string) (types.MarketData, error)
 
 	// Using raw SQL for simplicity and reliability
 	query := `
-		SELECT time, symbol, open, high, low, close, volume 
-		FROM market_data 
+		SELECT time, symbol, open, high, low, close, volume, ` + strings.Join(d.quoteColumns(), ", ") + `
+		FROM ` + d.marketDataSource() + `
 		WHERE symbol = $1
 		ORDER BY time DESC
 		LIMIT 1
@@ -560,12 +653,12 @@ func (d *DuckDBDataSource) ReadLastData(symbol string) (types.MarketData, error)
 	defer stmt.Close()
 
 	var (
-		timestamp                      time.Time
-		open, high, low, close, volume float64
-		symbolResult                   string
+		timestamp                                time.Time
+		open, high, low, close, volume, bid, ask float64
+		symbolResult                             string
 	)
 
-	err = stmt.QueryRow(symbol).Scan(&timestamp, &symbolResult, &open, &high, &low, &close, &volume)
+	err = stmt.QueryRow(symbol).Scan(&timestamp, &symbolResult, &open, &high, &low, &close, &volume, &bid, &ask)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return types.MarketData{}, fmt.Errorf("no data found for symbol: %s", symbol)
@@ -583,6 +676,8 @@ func (d *DuckDBDataSource) ReadLastData(symbol string) (types.MarketData, error)
 		Low:    low,
 		Close:  close,
 		Volume: volume,
+		Bid:    bid,
+		Ask:    ask,
 	}, nil
 }
 
@@ -599,7 +694,8 @@ func (d *DuckDBDataSource) GetMarketData(symbol string, timestamp time.Time) (ty
 	// Build query using squirrel
 	query, args, err := d.sq.
 		Select("time", "symbol", "open", "high", "low", "close", "volume").
-		From("market_data").
+		Columns(d.quoteColumns()...).
+		From(d.marketDataSource()).
 		Where(squirrel.And{
 			squirrel.Eq{"symbol": symbol},
 			squirrel.Eq{"time": timestamp},
@@ -612,13 +708,13 @@ func (d *DuckDBDataSource) GetMarketData(symbol string, timestamp time.Time) (ty
 
 	// Execute the query
 	var (
-		timeResult                     time.Time
-		symbolResult                   string
-		open, high, low, close, volume float64
+		timeResult                               time.Time
+		symbolResult                             string
+		open, high, low, close, volume, bid, ask float64
 	)
 
 	err = d.db.QueryRow(query, args...).Scan(
-		&timeResult, &symbolResult, &open, &high, &low, &close, &volume)
+		&timeResult, &symbolResult, &open, &high, &low, &close, &volume, &bid, &ask)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -637,6 +733,8 @@ func (d *DuckDBDataSource) GetMarketData(symbol string, timestamp time.Time) (ty
 		Low:    low,
 		Close:  close,
 		Volume: volume,
+		Bid:    bid,
+		Ask:    ask,
 	}, nil
 }
 
@@ -650,7 +748,8 @@ func (d *DuckDBDataSource) GetPreviousNumberOfDataPoints(end time.Time, symbol s
 	// Build query using squirrel
 	query, args, err := d.sq.
 		Select("time", "symbol", "open", "high", "low", "close", "volume").
-		From("market_data").
+		Columns(d.quoteColumns()...).
+		From(d.marketDataSource()).
 		Where(squirrel.And{
 			squirrel.Eq{"symbol": symbol},
 			squirrel.LtOrEq{"time": end},
@@ -681,12 +780,12 @@ func (d *DuckDBDataSource) GetPreviousNumberOfDataPoints(end time.Time, symbol s
 
 	for rows.Next() {
 		var (
-			timestamp                      time.Time
-			symbolResult                   string
-			open, high, low, close, volume float64
+			timestamp                                time.Time
+			symbolResult                             string
+			open, high, low, close, volume, bid, ask float64
 		)
 
-		err := rows.Scan(&timestamp, &symbolResult, &open, &high, &low, &close, &volume)
+		err := rows.Scan(&timestamp, &symbolResult, &open, &high, &low, &close, &volume, &bid, &ask)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
@@ -700,6 +799,8 @@ func (d *DuckDBDataSource) GetPreviousNumberOfDataPoints(end time.Time, symbol s
 			Low:    low,
 			Close:  close,
 			Volume: volume,
+			Bid:    bid,
+			Ask:    ask,
 		}
 
 		result = append(result, marketData)
@@ -749,16 +850,23 @@ func (d *DuckDBDataSource) GetAllSymbols() ([]string, error) {
 }
 
 // buildGetRangeQuery constructs the SQL query for GetRange method.
-func (d *DuckDBDataSource) buildGetRangeQuery(start time.Time, end time.Time, intervalMinutes optional.Option[int]) (string, []interface{}, error) {
+func (d *DuckDBDataSource) buildGetRangeQuery(symbol optional.Option[string], start time.Time, end time.Time, intervalMinutes optional.Option[int]) (string, []interface{}, error) {
 	// If no interval is specified, use a simple query with squirrel
 	if !intervalMinutes.IsSome() {
+		conditions := squirrel.And{
+			squirrel.GtOrEq{"time": start},
+			squirrel.LtOrEq{"time": end},
+		}
+
+		if symbol.IsSome() {
+			conditions = append(conditions, squirrel.Eq{"symbol": symbol.Unwrap()})
+		}
+
 		query, args, err := d.sq.
 			Select("time", "symbol", "open", "high", "low", "close", "volume").
-			From("market_data").
-			Where(squirrel.And{
-				squirrel.GtOrEq{"time": start},
-				squirrel.LtOrEq{"time": end},
-			}).
+			Columns(d.quoteColumns()...).
+			From(d.marketDataSource()).
+			Where(conditions).
 			OrderBy("time ASC").
 			ToSql()
 		if err != nil {
@@ -768,6 +876,16 @@ func (d *DuckDBDataSource) buildGetRangeQuery(start time.Time, end time.Time, in
 		return query, args, nil
 	}
 
+	args := []interface{}{start, end}
+
+	// The symbol filter is applied before bucketing so other symbols never enter the aggregation
+	symbolFilter := ""
+	if symbol.IsSome() {
+		symbolFilter = " AND symbol = $3"
+
+		args = append(args, symbol.Unwrap())
+	}
+
 	// For interval case, use raw SQL with window functions
 	minutes := intervalMinutes.Unwrap()
 	query := fmt.Sprintf(`
@@ -780,8 +898,8 @@ func (d *DuckDBDataSource) buildGetRangeQuery(start time.Time, end time.Time, in
 				MIN(low) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol) as low,
 				LAST_VALUE(close) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol ORDER BY time ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) as close,
 				SUM(volume) OVER (PARTITION BY time_bucket(INTERVAL '%d minutes', time), symbol) as volume
-			FROM market_data 
-			WHERE time >= $1 AND time <= $2
+			FROM %s 
+			WHERE time >= $1 AND time <= $2%s
 		)
 		SELECT DISTINCT
 			bucket_time as time,
@@ -790,10 +908,88 @@ func (d *DuckDBDataSource) buildGetRangeQuery(start time.Time, end time.Time, in
 			high,
 			low,
 			close,
-			volume
+			volume,
+			0::DOUBLE AS bid,
+			0::DOUBLE AS ask
 		FROM time_buckets
-		ORDER BY bucket_time ASC
-	`, minutes, minutes, minutes, minutes, minutes, minutes)
+		ORDER BY bucket_time ASC, symbol ASC
+	`, minutes, minutes, minutes, minutes, minutes, minutes, d.marketDataSource(), symbolFilter)
+
+	return query, args, nil
+}
+
+// warnIfMultipleSymbols logs a warning the first time an unfiltered range is
+// read from a dataset that holds more than one symbol.
+func (d *DuckDBDataSource) warnIfMultipleSymbols() {
+	if !d.symbolWarningChecked.CompareAndSwap(false, true) {
+		return
+	}
+
+	symbols, err := d.GetAllSymbols()
+	if err != nil || len(symbols) <= 1 {
+		return
+	}
+
+	d.logger.Warn("GetRange called without a symbol filter on a dataset with multiple symbols; use GetRangeForSymbol to read a single instrument",
+		zap.Strings("symbols", symbols))
+}
+
+// marketDataViewQuery returns the query creating the market_data view over files.
+// A single file without deduplication is read as is.
+func (d *DuckDBDataSource) marketDataViewQuery(files []string) string {
+	quoted := make([]string, len(files))
+	for i, file := range files {
+		quoted[i] = "'" + strings.ReplaceAll(file, "'", "''") + "'"
+	}
+
+	list := "[" + strings.Join(quoted, ", ") + "]"
+
+	if !d.deduplicate {
+		if len(files) == 1 {
+			return fmt.Sprintf(`
+		CREATE VIEW market_data AS
+		SELECT * FROM read_parquet(%s);
+	`, quoted[0])
+		}
+
+		return fmt.Sprintf(`
+		CREATE VIEW market_data AS
+		SELECT * FROM read_parquet(%s, union_by_name = true)
+		ORDER BY time, symbol;
+	`, list)
+	}
+
+	// Rows of later files win, and within a file the later row wins.
+	return fmt.Sprintf(`
+		CREATE VIEW market_data AS
+		SELECT * EXCLUDE (filename, file_row_number)
+		FROM read_parquet(%[1]s, union_by_name = true, filename = true, file_row_number = true)
+		QUALIFY row_number() OVER (
+			PARTITION BY symbol, time
+			ORDER BY list_position(%[1]s, filename) DESC, file_row_number DESC
+		) = 1
+		ORDER BY time, symbol;
+	`, list)
+}
+
+// parquetFiles expands a glob path into the sorted list of files it matches.
+// A path without glob characters, or matching no local files, is returned as
+// is for DuckDB to resolve.
+func parquetFiles(path string) ([]string, error) {
+	if !strings.ContainsAny(path, "*?[") {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data path pattern %q: %w", path, err)
+	}
+
+	if len(files) == 0 {
+		return []string{path}, nil
+	}
+
+	sort.Strings(files)
 
-	return query, []interface{}{start, end}, nil
+	return files, nil
 }
@@ -161,6 +161,79 @@ func (suite *DuckDBTestSuite) TestInitialize() {
 	}
 }
 
+func (suite *DuckDBTestSuite) TestInitializeMultipleFiles() {
+	tmpDir := suite.T().TempDir()
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	bar := func(minute int, closePrice float64) types.MarketData {
+		return types.MarketData{
+			Time:   base.Add(time.Duration(minute) * time.Minute),
+			Open:   closePrice,
+			High:   closePrice,
+			Low:    closePrice,
+			Close:  closePrice,
+			Volume: 1000.0,
+			Symbol: "AAPL",
+		}
+	}
+
+	// The files are written out of order and overlap at minutes 2 and 3.
+	first := []types.MarketData{bar(3, 103), bar(0, 100), bar(2, 102), bar(1, 101)}
+	second := []types.MarketData{bar(4, 204), bar(2, 202), bar(3, 203)}
+	suite.Require().NoError(writeTestDataToParquet(first, filepath.Join(tmpDir, "part-1.parquet")))
+	suite.Require().NoError(writeTestDataToParquet(second, filepath.Join(tmpDir, "part-2.parquet")))
+
+	tests := []struct {
+		name           string
+		deduplicate    bool
+		expectedCloses []float64
+	}{
+		{
+			name:           "union keeps every row sorted by time",
+			deduplicate:    false,
+			expectedCloses: []float64{100, 101, 102, 202, 103, 203, 204},
+		},
+		{
+			name:           "deduplicate keeps the row of the last file",
+			deduplicate:    true,
+			expectedCloses: []float64{100, 101, 202, 203, 204},
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			suite.cleanupMarketData()
+			suite.ds.SetDeduplicate(tc.deduplicate)
+			defer suite.ds.SetDeduplicate(false)
+
+			suite.Require().NoError(suite.ds.Initialize(filepath.Join(tmpDir, "*.parquet")))
+
+			count, err := suite.ds.Count(optional.None[time.Time](), optional.None[time.Time]())
+			suite.Require().NoError(err)
+			suite.Equal(len(tc.expectedCloses), count)
+
+			var closes []float64
+
+			var previous time.Time
+
+			for data, err := range suite.ds.ReadAll(optional.None[time.Time](), optional.None[time.Time]()) {
+				suite.Require().NoError(err)
+				suite.False(data.Time.Before(previous), "rows must be sorted by time")
+				previous = data.Time
+
+				closes = append(closes, data.Close)
+			}
+
+			// Rows sharing a time come in no particular order without dedup.
+			if tc.deduplicate {
+				suite.Equal(tc.expectedCloses, closes)
+			} else {
+				suite.ElementsMatch(tc.expectedCloses, closes)
+			}
+		})
+	}
+}
+
 func (suite *DuckDBTestSuite) TestReadAll() {
 	// Define test cases for ReadAll
 	tests := []struct {
@@ -482,6 +555,166 @@ func (suite *DuckDBTestSuite) TestReadRange() {
 			},
 			expectError: false,
 		},
+		{
+			name: "Read 15-minute aggregated data from 60 one-minute bars",
+			setupData: `CREATE TABLE market_data_source (
+				time TIMESTAMP,
+				symbol TEXT,
+				open DOUBLE,
+				high DOUBLE,
+				low DOUBLE,
+				close DOUBLE,
+				volume DOUBLE
+			);
+			INSERT INTO market_data_source
+			SELECT TIMESTAMP '2024-01-01 10:00:00' + to_minutes(i), 'AAPL', 100.0 + i, 101.0 + i, 99.0 + i, 100.5 + i, 10.0
+			FROM range(60) t(i);
+			CREATE VIEW market_data AS SELECT * FROM market_data_source`,
+			start:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 1, 1, 10, 59, 0, 0, time.UTC),
+			interval: optional.Some(Interval15m),
+			expectedData: []types.MarketData{
+				{
+					Time:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+					Open:   100.0,
+					High:   115.0,
+					Low:    99.0,
+					Close:  114.5,
+					Volume: 150.0,
+					Symbol: "AAPL",
+				},
+				{
+					Time:   time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC),
+					Open:   115.0,
+					High:   130.0,
+					Low:    114.0,
+					Close:  129.5,
+					Volume: 150.0,
+					Symbol: "AAPL",
+				},
+				{
+					Time:   time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+					Open:   130.0,
+					High:   145.0,
+					Low:    129.0,
+					Close:  144.5,
+					Volume: 150.0,
+					Symbol: "AAPL",
+				},
+				{
+					Time:   time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC),
+					Open:   145.0,
+					High:   160.0,
+					Low:    144.0,
+					Close:  159.5,
+					Volume: 150.0,
+					Symbol: "AAPL",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "Read 1-hour aggregated data from 60 one-minute bars",
+			setupData: `CREATE TABLE market_data_source (
+				time TIMESTAMP,
+				symbol TEXT,
+				open DOUBLE,
+				high DOUBLE,
+				low DOUBLE,
+				close DOUBLE,
+				volume DOUBLE
+			);
+			INSERT INTO market_data_source
+			SELECT TIMESTAMP '2024-01-01 10:00:00' + to_minutes(i), 'AAPL', 100.0 + i, 101.0 + i, 99.0 + i, 100.5 + i, 10.0
+			FROM range(60) t(i);
+			CREATE VIEW market_data AS SELECT * FROM market_data_source`,
+			start:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 1, 1, 10, 59, 0, 0, time.UTC),
+			interval: optional.Some(Interval1h),
+			expectedData: []types.MarketData{
+				{
+					Time:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+					Open:   100.0,
+					High:   160.0,
+					Low:    99.0,
+					Close:  159.5,
+					Volume: 600.0,
+					Symbol: "AAPL",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "Read 1-hour aggregated data with range not aligned to the bucket edge",
+			setupData: `CREATE TABLE market_data_source (
+				time TIMESTAMP,
+				symbol TEXT,
+				open DOUBLE,
+				high DOUBLE,
+				low DOUBLE,
+				close DOUBLE,
+				volume DOUBLE
+			);
+			INSERT INTO market_data_source
+			SELECT TIMESTAMP '2024-01-01 10:00:00' + to_minutes(i), 'AAPL', 100.0 + i, 101.0 + i, 99.0 + i, 100.5 + i, 10.0
+			FROM range(120) t(i);
+			CREATE VIEW market_data AS SELECT * FROM market_data_source`,
+			start:    time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+			end:      time.Date(2024, 1, 1, 11, 29, 0, 0, time.UTC),
+			interval: optional.Some(Interval1h),
+			expectedData: []types.MarketData{
+				{
+					Time:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+					Open:   130.0,
+					High:   160.0,
+					Low:    129.0,
+					Close:  159.5,
+					Volume: 300.0,
+					Symbol: "AAPL",
+				},
+				{
+					Time:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+					Open:   160.0,
+					High:   190.0,
+					Low:    159.0,
+					Close:  189.5,
+					Volume: 300.0,
+					Symbol: "AAPL",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "Read 1-day aggregated data",
+			setupData: `CREATE TABLE market_data_source (
+				time TIMESTAMP,
+				symbol TEXT,
+				open DOUBLE,
+				high DOUBLE,
+				low DOUBLE,
+				close DOUBLE,
+				volume DOUBLE
+			);
+			INSERT INTO market_data_source
+			SELECT TIMESTAMP '2024-01-01 10:00:00' + to_minutes(i), 'AAPL', 100.0 + i, 101.0 + i, 99.0 + i, 100.5 + i, 10.0
+			FROM range(60) t(i);
+			CREATE VIEW market_data AS SELECT * FROM market_data_source`,
+			start:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 1, 1, 23, 59, 0, 0, time.UTC),
+			interval: optional.Some(Interval1d),
+			expectedData: []types.MarketData{
+				{
+					Time:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					Open:   100.0,
+					High:   160.0,
+					Low:    99.0,
+					Close:  159.5,
+					Volume: 600.0,
+					Symbol: "AAPL",
+				},
+			},
+			expectError: false,
+		},
 		{
 			name: "Read empty data range",
 			setupData: `CREATE TABLE market_data_source (
@@ -624,6 +857,102 @@ func (suite *DuckDBTestSuite) TestReadRange() {
 	}
 }
 
+func (suite *DuckDBTestSuite) TestGetRangeForSymbol() {
+	setupData := `CREATE TABLE market_data_source (
+		time TIMESTAMP,
+		symbol TEXT,
+		open DOUBLE,
+		high DOUBLE,
+		low DOUBLE,
+		close DOUBLE,
+		volume DOUBLE
+	);
+	INSERT INTO market_data_source VALUES
+	('2024-01-01 10:00:00'::TIMESTAMP, 'AAPL', 100.0, 101.0, 99.0, 100.5, 1000.0),
+	('2024-01-01 10:00:00'::TIMESTAMP, 'GOOGL', 140.0, 141.0, 139.0, 140.5, 500.0),
+	('2024-01-01 10:01:00'::TIMESTAMP, 'AAPL', 100.5, 102.0, 100.0, 101.5, 1500.0),
+	('2024-01-01 10:01:00'::TIMESTAMP, 'GOOGL', 140.5, 143.0, 140.0, 142.5, 600.0),
+	('2024-01-01 10:02:00'::TIMESTAMP, 'AAPL', 101.5, 103.0, 101.0, 102.5, 2000.0),
+	('2024-01-01 10:02:00'::TIMESTAMP, 'GOOGL', 142.5, 144.0, 138.0, 139.5, 700.0);
+	CREATE VIEW market_data AS SELECT * FROM market_data_source`
+
+	_, err := suite.ds.db.Exec(setupData)
+	suite.Require().NoError(err)
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 10, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		symbol       string
+		interval     optional.Option[Interval]
+		expectedData []types.MarketData
+	}{
+		{
+			name:     "AAPL aggregates only AAPL bars",
+			symbol:   "AAPL",
+			interval: optional.Some(Interval5m),
+			expectedData: []types.MarketData{
+				{Time: start, Symbol: "AAPL", Open: 100.0, High: 103.0, Low: 99.0, Close: 102.5, Volume: 4500.0},
+			},
+		},
+		{
+			name:     "GOOGL aggregates only GOOGL bars",
+			symbol:   "GOOGL",
+			interval: optional.Some(Interval5m),
+			expectedData: []types.MarketData{
+				{Time: start, Symbol: "GOOGL", Open: 140.0, High: 144.0, Low: 138.0, Close: 139.5, Volume: 1800.0},
+			},
+		},
+		{
+			name:     "raw bars without interval",
+			symbol:   "GOOGL",
+			interval: optional.None[Interval](),
+			expectedData: []types.MarketData{
+				{Time: start, Symbol: "GOOGL", Open: 140.0, High: 141.0, Low: 139.0, Close: 140.5, Volume: 500.0},
+				{Time: start.Add(time.Minute), Symbol: "GOOGL", Open: 140.5, High: 143.0, Low: 140.0, Close: 142.5, Volume: 600.0},
+				{Time: start.Add(2 * time.Minute), Symbol: "GOOGL", Open: 142.5, High: 144.0, Low: 138.0, Close: 139.5, Volume: 700.0},
+			},
+		},
+		{
+			name:         "unknown symbol returns no data",
+			symbol:       "MSFT",
+			interval:     optional.Some(Interval5m),
+			expectedData: []types.MarketData{},
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			results, err := suite.ds.GetRangeForSymbol(tc.symbol, start, end, tc.interval)
+			suite.Require().NoError(err)
+			suite.Require().Len(results, len(tc.expectedData))
+
+			for i, expected := range tc.expectedData {
+				suite.Equal(expected.Time.UTC(), results[i].Time.UTC(), "Time mismatch")
+				suite.Equal(expected.Symbol, results[i].Symbol, "Symbol mismatch")
+				suite.Equal(expected.Open, results[i].Open, "Open price mismatch")
+				suite.Equal(expected.High, results[i].High, "High price mismatch")
+				suite.Equal(expected.Low, results[i].Low, "Low price mismatch")
+				suite.Equal(expected.Close, results[i].Close, "Close price mismatch")
+				suite.Equal(expected.Volume, results[i].Volume, "Volume mismatch")
+			}
+		})
+	}
+
+	// Without a symbol filter each symbol still gets its own bucket
+	results, err := suite.ds.GetRange(start, end, optional.Some(Interval5m))
+	suite.Require().NoError(err)
+	suite.Require().Len(results, 2)
+	suite.Equal("AAPL", results[0].Symbol)
+	suite.Equal(4500.0, results[0].Volume)
+	suite.Equal("GOOGL", results[1].Symbol)
+	suite.Equal(1800.0, results[1].Volume)
+
+	_, err = suite.ds.GetRangeForSymbol("AAPL", start, end, optional.Some(Interval("invalid")))
+	suite.Error(err)
+}
+
 func (suite *DuckDBTestSuite) TestNewDataSource() {
 	tests := []struct {
 		name        string
@@ -1064,6 +1393,46 @@ func (suite *DuckDBTestSuite) TestReadRecordsFromStart() {
 			},
 			expectError: false,
 		},
+		{
+			name: "Read records from start with 1-hour interval",
+			setupData: `CREATE TABLE market_data_source (
+				time TIMESTAMP,
+				symbol TEXT,
+				open DOUBLE,
+				high DOUBLE,
+				low DOUBLE,
+				close DOUBLE,
+				volume DOUBLE
+			);
+			INSERT INTO market_data_source
+			SELECT TIMESTAMP '2024-01-01 10:00:00' + to_minutes(i), 'AAPL', 100.0 + i, 101.0 + i, 99.0 + i, 100.5 + i, 10.0
+			FROM range(120) t(i);
+			CREATE VIEW market_data AS SELECT * FROM market_data_source`,
+			start:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			number:   2,
+			interval: Interval1h,
+			expectedData: []types.MarketData{
+				{
+					Time:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+					Open:   100.0,
+					High:   160.0,
+					Low:    99.0,
+					Close:  159.5,
+					Volume: 600.0,
+					Symbol: "AAPL",
+				},
+				{
+					Time:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+					Open:   160.0,
+					High:   220.0,
+					Low:    159.0,
+					Close:  219.5,
+					Volume: 600.0,
+					Symbol: "AAPL",
+				},
+			},
+			expectError: false,
+		},
 		{
 			name: "Read records with invalid interval",
 			setupData: `CREATE TABLE market_data_source (
@@ -0,0 +1,140 @@
+package datasource
+
+import (
+	"container/list"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// defaultQueryCacheSize is the number of query results a DuckDBDataSource keeps by default.
+const defaultQueryCacheSize = 64
+
+// queryCache is a least recently used cache of market data query results keyed
+// by the SQL and its parameters. It is safe for concurrent use. Results are
+// copied in and out so callers cannot modify the cached rows.
+type queryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// order holds the cache entries, most recently used first.
+	order *list.List
+}
+
+// queryCacheEntry is a cached result and the key it is stored under.
+type queryCacheEntry struct {
+	key  string
+	rows []types.MarketData
+}
+
+// newQueryCache returns a cache holding up to size results. A size of 0 disables it.
+func newQueryCache(size int) *queryCache {
+	return &queryCache{
+		mu:      sync.Mutex{},
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// get returns a copy of the result cached for key. A nil cache holds nothing.
+func (c *queryCache) get(key string) ([]types.MarketData, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	entry, _ := element.Value.(*queryCacheEntry)
+
+	return slices.Clone(entry.rows), true
+}
+
+// put caches a copy of rows for key, evicting the least recently used result
+// when the cache is full.
+func (c *queryCache) put(key string, rows []types.MarketData) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry, _ := element.Value.(*queryCacheEntry)
+		entry.rows = slices.Clone(rows)
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&queryCacheEntry{key: key, rows: slices.Clone(rows)})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		entry, _ := oldest.Value.(*queryCacheEntry)
+
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+	}
+}
+
+// resize sets the number of results kept and clears the cache.
+func (c *queryCache) resize(size int) {
+	c.mu.Lock()
+	c.size = size
+	c.mu.Unlock()
+
+	c.clear()
+}
+
+// clear removes every cached result.
+func (c *queryCache) clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+// queryCacheKey builds the cache key of a query and its parameters. Times are
+// written in UTC so equal instants share a key.
+func queryCacheKey(query string, args ...any) string {
+	var key strings.Builder
+
+	key.WriteString(query)
+
+	for _, arg := range args {
+		key.WriteByte(0)
+
+		if t, ok := arg.(time.Time); ok {
+			key.WriteString(t.UTC().Format(time.RFC3339Nano))
+
+			continue
+		}
+
+		fmt.Fprintf(&key, "%T:%v", arg, arg)
+	}
+
+	return key.String()
+}
@@ -0,0 +1,197 @@
+package datasource
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// minuteSeries returns n one-minute bars of symbol starting at 2024-01-01 10:00 UTC.
+func minuteSeries(symbol string, n int, basePrice float64) []types.MarketData {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	data := make([]types.MarketData, n)
+
+	for i := range data {
+		price := basePrice + float64(i)
+		data[i] = types.MarketData{
+			Symbol: symbol,
+			Time:   start.Add(time.Duration(i) * time.Minute),
+			Open:   price,
+			High:   price + 1,
+			Low:    price - 1,
+			Close:  price + 0.5,
+			Volume: 100,
+		}
+	}
+
+	return data
+}
+
+// initializeSeries writes data to a parquet file and loads it into the suite's data source.
+func (suite *DuckDBTestSuite) initializeSeries(data []types.MarketData, name string) {
+	path := filepath.Join(suite.T().TempDir(), name)
+	suite.Require().NoError(writeTestDataToParquet(data, path))
+	suite.Require().NoError(suite.ds.Initialize(path))
+}
+
+func (suite *DuckDBTestSuite) TestQueryCacheMatchesUncachedResults() {
+	suite.initializeSeries(minuteSeries("AAPL", 120, 100), "data.parquet")
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	interval := optional.Some(Interval5m)
+
+	suite.ds.SetQueryCacheSize(0)
+	uncachedRange, err := suite.ds.GetRange(start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	uncachedSymbolRange, err := suite.ds.GetRangeForSymbol("AAPL", start, end, interval)
+	suite.Require().NoError(err)
+	uncachedFromEnd, err := suite.ds.ReadRecordsFromEnd(end, 10, Interval5m)
+	suite.Require().NoError(err)
+
+	suite.ds.SetQueryCacheSize(8)
+
+	// The first reads fill the cache, the second ones are served from it
+	for range 2 {
+		cachedRange, err := suite.ds.GetRange(start, end, optional.None[Interval]())
+		suite.Require().NoError(err)
+		suite.Assert().Equal(uncachedRange, cachedRange)
+
+		cachedSymbolRange, err := suite.ds.GetRangeForSymbol("AAPL", start, end, interval)
+		suite.Require().NoError(err)
+		suite.Assert().Equal(uncachedSymbolRange, cachedSymbolRange)
+
+		cachedFromEnd, err := suite.ds.ReadRecordsFromEnd(end, 10, Interval5m)
+		suite.Require().NoError(err)
+		suite.Assert().Equal(uncachedFromEnd, cachedFromEnd)
+	}
+
+	// Cached reads do not reach the database
+	_, err = suite.ds.db.Exec("DROP VIEW market_data")
+	suite.Require().NoError(err)
+
+	cachedRange, err := suite.ds.GetRange(start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Assert().Equal(uncachedRange, cachedRange)
+
+	// Reads with other parameters still query the database
+	_, err = suite.ds.GetRange(start, end.Add(time.Minute), optional.None[Interval]())
+	suite.Assert().Error(err)
+}
+
+func (suite *DuckDBTestSuite) TestQueryCacheReturnsCopies() {
+	suite.initializeSeries(minuteSeries("AAPL", 10, 100), "data.parquet")
+	suite.ds.SetQueryCacheSize(8)
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(5 * time.Minute)
+
+	first, err := suite.ds.GetRange(start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(first)
+
+	first[0].Close = -1
+
+	second, err := suite.ds.GetRange(start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Assert().Equal(100.5, second[0].Close)
+}
+
+func (suite *DuckDBTestSuite) TestQueryCacheInvalidatedOnReload() {
+	suite.initializeSeries(minuteSeries("AAPL", 10, 100), "first.parquet")
+	suite.ds.SetQueryCacheSize(8)
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(5 * time.Minute)
+
+	before, err := suite.ds.GetRange(start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(before)
+	suite.Assert().Equal(100.0, before[0].Open)
+
+	suite.initializeSeries(minuteSeries("AAPL", 10, 200), "second.parquet")
+
+	after, err := suite.ds.GetRange(start, end, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Require().Len(after, len(before))
+	suite.Assert().Equal(200.0, after[0].Open, "the reloaded data is read instead of the cached result")
+}
+
+func (suite *DuckDBTestSuite) TestQueryCacheEvictsLeastRecentlyUsed() {
+	suite.initializeSeries(minuteSeries("AAPL", 10, 100), "data.parquet")
+	suite.ds.SetQueryCacheSize(2)
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	read := func(minutes int) error {
+		_, err := suite.ds.GetRange(start, start.Add(time.Duration(minutes)*time.Minute), optional.None[Interval]())
+
+		return err
+	}
+
+	suite.Require().NoError(read(1))
+	suite.Require().NoError(read(2))
+	// Using the first result makes the second the least recently used
+	suite.Require().NoError(read(1))
+	suite.Require().NoError(read(3))
+
+	_, err := suite.ds.db.Exec("DROP VIEW market_data")
+	suite.Require().NoError(err)
+
+	suite.Assert().NoError(read(1))
+	suite.Assert().NoError(read(3))
+	suite.Assert().Error(read(2), "the least recently used result was evicted")
+}
+
+// benchmarkGetRange repeatedly reads the same range of a 10,000 bar dataset
+// with a query cache of the given size.
+func benchmarkGetRange(b *testing.B, cacheSize int) {
+	b.Helper()
+
+	log, err := logger.NewLogger()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	path := filepath.Join(b.TempDir(), "data.parquet")
+	if err := writeTestDataToParquet(minuteSeries("SPY", 10000, 100), path); err != nil {
+		b.Fatal(err)
+	}
+
+	ds, err := NewDataSource(":memory:", log)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ds.Close()
+
+	duckDB, _ := ds.(*DuckDBDataSource)
+	duckDB.SetQueryCacheSize(cacheSize)
+
+	if err := ds.Initialize(path); err != nil {
+		b.Fatal(err)
+	}
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC).Add(9000 * time.Minute)
+	end := start.Add(200 * time.Minute)
+
+	b.ResetTimer()
+
+	for range b.N {
+		if _, err := ds.GetRangeForSymbol("SPY", start, end, optional.Some(Interval5m)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetRangeWithoutQueryCache queries DuckDB on every read.
+func BenchmarkGetRangeWithoutQueryCache(b *testing.B) {
+	benchmarkGetRange(b, 0)
+}
+
+// BenchmarkGetRangeWithQueryCache serves every read after the first from the cache.
+func BenchmarkGetRangeWithQueryCache(b *testing.B) {
+	benchmarkGetRange(b, defaultQueryCacheSize)
+}
@@ -0,0 +1,30 @@
+package datasource
+
+import "fmt"
+
+// detectQuotes records whether the market_data view has bid and ask columns.
+func (d *DuckDBDataSource) detectQuotes() error {
+	var count int
+
+	err := d.db.QueryRow(`
+		SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_name = 'market_data' AND column_name IN ('bid', 'ask')
+	`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to inspect market data columns: %w", err)
+	}
+
+	d.hasQuotes = count == 2
+
+	return nil
+}
+
+// quoteColumns returns the bid and ask columns the OHLCV reads select after
+// the volume. Datasets without quotes, and rows with missing quotes, read 0.
+func (d *DuckDBDataSource) quoteColumns() []string {
+	if d.hasQuotes {
+		return []string{"COALESCE(bid, 0) AS bid", "COALESCE(ask, 0) AS ask"}
+	}
+
+	return []string{"0::DOUBLE AS bid", "0::DOUBLE AS ask"}
+}
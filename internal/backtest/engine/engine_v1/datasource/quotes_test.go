@@ -0,0 +1,169 @@
+package datasource
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// writeQuotedDataToParquet writes data to a parquet file with bid and ask columns.
+// Zero quotes are written as NULL.
+func writeQuotedDataToParquet(data []types.MarketData, path string) error {
+	db, err := sql.Open("duckdb", ":memory:")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE market_data (
+			time TIMESTAMP,
+			symbol TEXT,
+			open DOUBLE,
+			high DOUBLE,
+			low DOUBLE,
+			close DOUBLE,
+			volume DOUBLE,
+			bid DOUBLE,
+			ask DOUBLE
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	nullable := func(v float64) any {
+		if v == 0 {
+			return nil
+		}
+
+		return v
+	}
+
+	for _, d := range data {
+		_, err = db.Exec(`INSERT INTO market_data VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			d.Time, d.Symbol, d.Open, d.High, d.Low, d.Close, d.Volume, nullable(d.Bid), nullable(d.Ask))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`COPY market_data TO '%s' (FORMAT PARQUET)`, path))
+
+	return err
+}
+
+// quotedSeries returns three minute bars of AAPL, the last one without quotes.
+func quotedSeries() []types.MarketData {
+	minute := func(m int) time.Time { return time.Date(2024, 1, 2, 10, m, 0, 0, time.UTC) }
+
+	return []types.MarketData{
+		{Symbol: "AAPL", Time: minute(0), Open: 100, High: 101, Low: 99, Close: 100.5, Volume: 1000, Bid: 100.4, Ask: 100.6},
+		{Symbol: "AAPL", Time: minute(1), Open: 100.5, High: 102, Low: 100, Close: 101.5, Volume: 1500, Bid: 101.4, Ask: 101.6},
+		{Symbol: "AAPL", Time: minute(2), Open: 101.5, High: 103, Low: 101, Close: 102.5, Volume: 2000},
+	}
+}
+
+func (suite *DuckDBTestSuite) TestReadQuotes() {
+	data := quotedSeries()
+	path := filepath.Join(suite.T().TempDir(), "quotes.parquet")
+	suite.Require().NoError(writeQuotedDataToParquet(data, path))
+	suite.Require().NoError(suite.ds.Initialize(path))
+
+	var all []types.MarketData
+
+	for md, err := range suite.ds.ReadAll(optional.None[time.Time](), optional.None[time.Time]()) {
+		suite.Require().NoError(err)
+
+		all = append(all, md)
+	}
+
+	suite.Require().Len(all, 3)
+
+	for i, md := range all {
+		suite.Equal(data[i].Bid, md.Bid)
+		suite.Equal(data[i].Ask, md.Ask)
+	}
+
+	suite.True(all[0].HasQuote())
+	suite.False(all[2].HasQuote(), "missing quotes read as 0")
+
+	bars, err := suite.ds.GetRangeForSymbol("AAPL", data[0].Time, data[2].Time, optional.None[Interval]())
+	suite.Require().NoError(err)
+	suite.Require().Len(bars, 3)
+	suite.Equal(101.4, bars[1].Bid)
+	suite.Equal(101.6, bars[1].Ask)
+
+	md, err := suite.ds.GetMarketData("AAPL", data[0].Time)
+	suite.Require().NoError(err)
+	suite.Equal(100.4, md.Bid)
+	suite.Equal(100.6, md.Ask)
+
+	previous, err := suite.ds.GetPreviousNumberOfDataPoints(data[1].Time, "AAPL", 2)
+	suite.Require().NoError(err)
+	suite.Require().Len(previous, 2)
+	suite.Equal(100.6, previous[0].Ask)
+	suite.Equal(101.6, previous[1].Ask)
+
+	last, err := suite.ds.ReadLastData("AAPL")
+	suite.Require().NoError(err)
+	suite.False(last.HasQuote())
+
+	// Resampled bars span several quotes, so they carry none
+	resampled, err := suite.ds.GetRangeForSymbol("AAPL", data[0].Time, data[2].Time, optional.Some(Interval5m))
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(resampled)
+
+	for _, bar := range resampled {
+		suite.False(bar.HasQuote())
+	}
+}
+
+func (suite *DuckDBTestSuite) TestReadWithoutQuotes() {
+	data := quotedSeries()
+	path := filepath.Join(suite.T().TempDir(), "data.parquet")
+	suite.Require().NoError(writeTestDataToParquet(data, path))
+	suite.Require().NoError(suite.ds.Initialize(path))
+
+	count := 0
+
+	for md, err := range suite.ds.ReadAll(optional.None[time.Time](), optional.None[time.Time]()) {
+		suite.Require().NoError(err)
+		suite.Zero(md.Bid)
+		suite.Zero(md.Ask)
+
+		count++
+	}
+
+	suite.Equal(3, count)
+
+	md, err := suite.ds.GetMarketData("AAPL", data[0].Time)
+	suite.Require().NoError(err)
+	suite.False(md.HasQuote())
+}
+
+func (suite *DuckDBTestSuite) TestAdjustedQuotes() {
+	data := quotedSeries()
+	tmpDir := suite.T().TempDir()
+
+	path := filepath.Join(tmpDir, "quotes.parquet")
+	suite.Require().NoError(writeQuotedDataToParquet(data, path))
+	suite.Require().NoError(suite.ds.Initialize(path))
+
+	// A 2:1 split after the series halves every quote
+	actionsPath := filepath.Join(tmpDir, "actions.csv")
+	suite.Require().NoError(os.WriteFile(actionsPath, []byte("date,symbol,split_ratio,dividend\n2024-01-03,AAPL,2,\n"), 0o600))
+	suite.Require().NoError(suite.ds.LoadCorporateActions(actionsPath))
+	suite.ds.SetAdjusted(true)
+
+	md, err := suite.ds.GetMarketData("AAPL", data[0].Time)
+	suite.Require().NoError(err)
+	suite.InDelta(50.2, md.Bid, 1e-9)
+	suite.InDelta(50.3, md.Ask, 1e-9)
+	suite.InDelta(50.25, md.Close, 1e-9)
+}
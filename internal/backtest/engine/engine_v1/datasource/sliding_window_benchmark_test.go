@@ -49,6 +49,16 @@ func (m *MockSlowDataSource) GetRange(start time.Time, end time.Time, interval o
 	return result, nil
 }
 
+func (m *MockSlowDataSource) GetRangeForSymbol(symbol string, start time.Time, end time.Time, interval optional.Option[Interval]) ([]types.MarketData, error) {
+	var result []types.MarketData
+	for _, d := range m.data[symbol] {
+		if !d.Time.Before(start) && !d.Time.After(end) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
 func (m *MockSlowDataSource) GetPreviousNumberOfDataPoints(end time.Time, symbol string, count int) ([]types.MarketData, error) {
 	// Simulate slow query by searching through data
 	symbolData, ok := m.data[symbol]
@@ -151,6 +151,40 @@ func (c *SlidingWindowCache) GetRange(start time.Time, end time.Time) ([]types.M
 	return result, true
 }
 
+// GetRangeForSymbol returns market data of a single symbol within the specified time range.
+// Returns false if the symbol is not cached or the cache does not reach back to start.
+func (c *SlidingWindowCache) GetRangeForSymbol(symbol string, start time.Time, end time.Time) ([]types.MarketData, bool) {
+	if c.maxSize <= 0 {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	symbolData, ok := c.data[symbol]
+	if !ok || len(symbolData) == 0 || start.Before(symbolData[0].Time) {
+		return nil, false
+	}
+
+	startIdx := sort.Search(len(symbolData), func(i int) bool {
+		return !symbolData[i].Time.Before(start)
+	})
+
+	endIdx := sort.Search(len(symbolData), func(i int) bool {
+		return symbolData[i].Time.After(end)
+	})
+
+	// An empty range (end before start) yields no data
+	if endIdx < startIdx {
+		endIdx = startIdx
+	}
+
+	result := make([]types.MarketData, endIdx-startIdx)
+	copy(result, symbolData[startIdx:endIdx])
+
+	return result, true
+}
+
 // GetPreviousDataPoints returns the specified number of historical data points
 // for a given symbol, ending at the specified time.
 // Returns data in chronological order (oldest to newest).
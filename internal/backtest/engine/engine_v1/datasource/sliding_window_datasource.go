@@ -71,6 +71,20 @@ func (s *SlidingWindowDataSource) GetRange(start time.Time, end time.Time, inter
 	return s.underlying.GetRange(start, end, interval)
 }
 
+// GetRangeForSymbol implements DataSource with cache support.
+// Aggregated requests bypass the cache like GetRange.
+func (s *SlidingWindowDataSource) GetRangeForSymbol(symbol string, start time.Time, end time.Time, interval optional.Option[Interval]) ([]types.MarketData, error) {
+	if interval.IsSome() {
+		return s.underlying.GetRangeForSymbol(symbol, start, end, interval)
+	}
+
+	if data, ok := s.cache.GetRangeForSymbol(symbol, start, end); ok {
+		return data, nil
+	}
+
+	return s.underlying.GetRangeForSymbol(symbol, start, end, interval)
+}
+
 // GetPreviousNumberOfDataPoints implements DataSource with cache support.
 // It first checks if the sliding window cache can satisfy the request.
 // If not, it falls back to the underlying datasource.
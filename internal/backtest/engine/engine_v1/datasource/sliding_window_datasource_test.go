@@ -34,6 +34,14 @@ func (m *MockDataSource) GetRange(start time.Time, end time.Time, interval optio
 	return args.Get(0).([]types.MarketData), args.Error(1)
 }
 
+func (m *MockDataSource) GetRangeForSymbol(symbol string, start time.Time, end time.Time, interval optional.Option[Interval]) ([]types.MarketData, error) {
+	args := m.Called(symbol, start, end, interval)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.MarketData), args.Error(1)
+}
+
 func (m *MockDataSource) GetPreviousNumberOfDataPoints(end time.Time, symbol string, count int) ([]types.MarketData, error) {
 	args := m.Called(end, symbol, count)
 	if args.Get(0) == nil {
@@ -183,6 +191,42 @@ func (s *SlidingWindowDataSourceTestSuite) TestGetRangeWithIntervalBypassesCache
 	s.mockDS.AssertExpectations(s.T())
 }
 
+func (s *SlidingWindowDataSourceTestSuite) TestGetRangeForSymbolFromCache() {
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Populate cache with interleaved symbols
+	for i := 0; i < 5; i++ {
+		s.ds.AddToCache(s.createMarketData("SPY", i, float64(100+i)))
+		s.ds.AddToCache(s.createMarketData("QQQ", i, float64(200+i)))
+	}
+
+	result, err := s.ds.GetRangeForSymbol("QQQ", baseTime, baseTime.Add(2*time.Minute), optional.None[Interval]())
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, len(result))
+	for _, data := range result {
+		assert.Equal(s.T(), "QQQ", data.Symbol)
+	}
+	assert.Equal(s.T(), 202.0, result[2].Close)
+
+	s.mockDS.AssertNotCalled(s.T(), "GetRangeForSymbol", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *SlidingWindowDataSourceTestSuite) TestGetRangeForSymbolUncachedSymbolFallsBack() {
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.ds.AddToCache(s.createMarketData("SPY", 0, 100))
+
+	expectedResult := []types.MarketData{s.createMarketData("QQQ", 0, 200)}
+	s.mockDS.On("GetRangeForSymbol", "QQQ", baseTime, baseTime.Add(time.Minute), optional.None[Interval]()).Return(expectedResult, nil)
+
+	result, err := s.ds.GetRangeForSymbol("QQQ", baseTime, baseTime.Add(time.Minute), optional.None[Interval]())
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), expectedResult, result)
+	s.mockDS.AssertExpectations(s.T())
+}
+
 func (s *SlidingWindowDataSourceTestSuite) TestGetPreviousNumberOfDataPointsFromCache() {
 	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
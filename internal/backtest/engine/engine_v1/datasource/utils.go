@@ -1,6 +1,8 @@
 package datasource
 
-import "fmt"
+import (
+	"fmt"
+)
 
 func getIntervalMinutes(interval Interval) (int, error) {
 	var intervalMinutes int
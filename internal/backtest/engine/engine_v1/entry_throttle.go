@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// EntryThrottlePolicy selects what happens to entries above the per-bar limit.
+type EntryThrottlePolicy string
+
+const (
+	// EntryThrottlePolicyDrop rejects excess entries and records them as failed orders.
+	EntryThrottlePolicyDrop EntryThrottlePolicy = "drop"
+	// EntryThrottlePolicyDefer queues excess entries and places them on the following
+	// bars, in the order they were requested, as the limit allows.
+	EntryThrottlePolicyDefer EntryThrottlePolicy = "defer"
+)
+
+// AllEntryThrottlePolicies is the list of supported entry throttle policies
+// (used by schema generation).
+var AllEntryThrottlePolicies = []any{
+	string(EntryThrottlePolicyDrop),
+	string(EntryThrottlePolicyDefer),
+}
+
+// EntryThrottleConfig limits how many new entries are placed per bar across all
+// symbols. A bar is identified by its timestamp, so entries on different symbols
+// with the same bar time share the limit.
+type EntryThrottleConfig struct {
+	MaxEntriesPerBar int                 `yaml:"max_entries_per_bar" json:"max_entries_per_bar" jsonschema:"title=Max Entries Per Bar,description=Maximum number of new entries (long buys) placed per bar across all symbols. Set to 0 to disable the throttle.,minimum=0,default=0"`
+	Policy           EntryThrottlePolicy `yaml:"policy" json:"policy" jsonschema:"title=Policy,description=What to do with entries above the limit. 'drop' rejects them; 'defer' places them on the following bars and expires IOC and FOK entries. Defaults to 'drop'.,default=drop"`
+}
+
+// Enabled reports whether the entry throttle is configured.
+func (c EntryThrottleConfig) Enabled() bool {
+	return c.MaxEntriesPerBar > 0
+}
+
+// SetEntryThrottle configures the per-bar entry throttle.
+func (b *BacktestTrading) SetEntryThrottle(config EntryThrottleConfig) {
+	b.entryThrottle = config
+	b.entryBarTime = time.Time{}
+	b.entriesThisBar = 0
+	b.deferredEntries = []types.ExecuteOrder{}
+}
+
+// isEntryOrder reports whether the order opens or adds to a position.
+func isEntryOrder(order types.ExecuteOrder) bool {
+	return (order.Side == types.PurchaseTypeBuy && order.PositionType == types.PositionTypeLong) ||
+		(order.Side == types.PurchaseTypeSell && order.PositionType == types.PositionTypeShort)
+}
+
+// advanceEntryThrottle resets the entry count when a new bar starts.
+func (b *BacktestTrading) advanceEntryThrottle(barTime time.Time) {
+	if !b.entryThrottle.Enabled() || barTime.Equal(b.entryBarTime) {
+		return
+	}
+
+	b.entryBarTime = barTime
+	b.entriesThisBar = 0
+}
+
+// releaseDeferredEntries places the deferred entries of the current bar's
+// symbol, in the order they were requested, as the limit of the bar allows.
+// Released entries go through placeOrder again, so every check applies on the
+// bar they are placed on; only the throttle and the signal confirmation they
+// already passed are skipped. Entries rejected on release do not count
+// against the limit.
+func (b *BacktestTrading) releaseDeferredEntries() error {
+	if len(b.deferredEntries) == 0 {
+		return nil
+	}
+
+	remaining := make([]types.ExecuteOrder, 0, len(b.deferredEntries))
+
+	for i, order := range b.deferredEntries {
+		if order.Symbol != b.marketData.Symbol || b.entriesThisBar >= b.entryThrottle.MaxEntriesPerBar {
+			remaining = append(remaining, order)
+
+			continue
+		}
+
+		b.releasingEntry = true
+		err := b.placeOrder(order)
+		b.releasingEntry = false
+
+		if err != nil {
+			b.deferredEntries = append(remaining, b.deferredEntries[i+1:]...)
+
+			return err
+		}
+
+		if b.lastFailedOrderID != order.ID {
+			b.entriesThisBar++
+		}
+	}
+
+	b.deferredEntries = remaining
+
+	return nil
+}
+
+// throttleEntry counts the order against the current bar's entry limit. It reports
+// whether the order may be placed now; excess entries are dropped or deferred
+// according to the configured policy. Released deferred entries are not
+// throttled again.
+func (b *BacktestTrading) throttleEntry(order types.ExecuteOrder) (bool, error) {
+	if !b.entryThrottle.Enabled() || !isEntryOrder(order) || b.releasingEntry {
+		return true, nil
+	}
+
+	b.advanceEntryThrottle(b.marketData.Time)
+
+	if b.entriesThisBar < b.entryThrottle.MaxEntriesPerBar {
+		b.entriesThisBar++
+
+		return true, nil
+	}
+
+	if b.entryThrottle.Policy == EntryThrottlePolicyDefer {
+		// IOC and FOK entries must fill on the bar they are placed on, so they expire instead
+		if fillsImmediately(order) {
+			return false, b.expireOrder(order)
+		}
+
+		b.deferredEntries = append(b.deferredEntries, order)
+
+		return false, nil
+	}
+
+	failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonEntryThrottled,
+		fmt.Sprintf("entry limit of %d per bar reached", b.entryThrottle.MaxEntriesPerBar))
+
+	return false, b.state.StoreFailedOrder(failedOrder)
+}
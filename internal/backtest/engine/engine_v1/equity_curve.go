@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// EquityCurveConfig configures recording the account equity on every bar.
+type EquityCurveConfig struct {
+	Disabled bool   `yaml:"disabled" json:"disabled" jsonschema:"title=Disabled,description=Stop recording the balance equity and realized and unrealized PnL after every bar. The curve is written to state.db/equity_curve.,default=false"`
+	Interval string `yaml:"interval" json:"interval" jsonschema:"title=Interval,description=Optional downsampling interval (e.g. 1h or 24h) for long runs. The curve then keeps only the last snapshot of each interval. Leave empty to keep every bar."`
+}
+
+// SetEquityCurve configures the equity curve recorder. A disabled config stops recording.
+func (b *BacktestTrading) SetEquityCurve(config EquityCurveConfig) error {
+	b.equityCurveEnabled = !config.Disabled
+	b.equityCurveInterval = 0
+
+	if config.Disabled || config.Interval == "" {
+		return nil
+	}
+
+	interval, err := time.ParseDuration(config.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid equity curve interval %q: %w", config.Interval, err)
+	}
+
+	if interval < 0 {
+		return fmt.Errorf("equity curve interval must not be negative: %s", config.Interval)
+	}
+
+	b.equityCurveInterval = interval
+
+	return nil
+}
+
+// recordEquityCurve stores the account state after the current bar in the
+// equity curve, valued at the prices GetAccountInfo uses.
+func (b *BacktestTrading) recordEquityCurve() error {
+	if !b.equityCurveEnabled {
+		return nil
+	}
+
+	if _, err := b.state.RecordEquityCurve(b.marketData.Time, b.markPrice, b.equityCurveInterval); err != nil {
+		return errors.Wrap(errors.ErrCodeQueryFailed, "failed to record equity curve", err)
+	}
+
+	return nil
+}
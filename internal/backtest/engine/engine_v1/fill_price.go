@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// FillPricePolicyType selects the bar price market orders fill at.
+type FillPricePolicyType string
+
+const (
+	// FillPriceMid fills buys at the bar's ask and sells at its bid when the bar
+	// is quoted, and at the middle of its high-low range otherwise.
+	FillPriceMid FillPricePolicyType = "mid"
+	// FillPriceOpen fills at the bar's open.
+	FillPriceOpen FillPricePolicyType = "open"
+	// FillPriceClose fills at the bar's close.
+	FillPriceClose FillPricePolicyType = "close"
+	// FillPriceWorst fills buys at the bar's high and sells at its low.
+	FillPriceWorst FillPricePolicyType = "worst"
+)
+
+// AllFillPricePolicies is the list of supported fill price policies (used by schema generation).
+var AllFillPricePolicies = []any{
+	string(FillPriceMid),
+	string(FillPriceOpen),
+	string(FillPriceClose),
+	string(FillPriceWorst),
+}
+
+// FillPrice returns the price the bar fills a market order on side at under
+// the policy. An empty policy selects FillPriceMid.
+func (p FillPricePolicyType) FillPrice(side types.PurchaseType, md types.MarketData) float64 {
+	switch p {
+	case FillPriceOpen:
+		return md.Open
+	case FillPriceClose:
+		return md.Close
+	case FillPriceWorst:
+		if side == types.PurchaseTypeBuy {
+			return md.High
+		}
+
+		return md.Low
+	default:
+		if !md.HasQuote() {
+			return barPrice(md)
+		}
+
+		if side == types.PurchaseTypeBuy {
+			return md.Ask
+		}
+
+		return md.Bid
+	}
+}
+
+// SetFillPricePolicy configures the bar price market orders and immediately
+// marketable limit orders fill at.
+func (b *BacktestTrading) SetFillPricePolicy(policy FillPricePolicyType) error {
+	switch policy {
+	case "", FillPriceMid, FillPriceOpen, FillPriceClose, FillPriceWorst:
+		b.fillPrice = policy
+
+		return nil
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unknown fill price policy %q", policy)
+	}
+}
+
+// marketFillPrice is the price the current bar fills a market order at before slippage.
+func (b *BacktestTrading) marketFillPrice(order types.ExecuteOrder) float64 {
+	return b.fillPrice.FillPrice(order.Side, b.marketData)
+}
+
+// marketableLimitFillPrice is the price a limit order that was marketable when
+// it was placed fills at: the market fill price, or the limit price when the
+// market price is worse than the limit.
+func (b *BacktestTrading) marketableLimitFillPrice(order types.ExecuteOrder) float64 {
+	price := b.marketFillPrice(order)
+	if order.Side == types.PurchaseTypeBuy {
+		return min(price, order.Price)
+	}
+
+	return max(price, order.Price)
+}
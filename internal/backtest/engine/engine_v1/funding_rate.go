@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// FundingRate is the funding rate of a perpetual futures symbol at one funding time.
+type FundingRate struct {
+	Symbol string    `yaml:"symbol" json:"symbol" jsonschema:"title=Symbol,description=Symbol the funding rate applies to."`
+	Time   time.Time `yaml:"time" json:"time" jsonschema:"title=Time,description=Funding time (RFC 3339). Positions open at this time pay or receive funding at the open of the first bar at or after it."`
+	Rate   float64   `yaml:"rate" json:"rate" jsonschema:"title=Rate,description=Funding rate as a fraction of the position notional (e.g. 0.0001 for 0.01%). With a positive rate longs pay shorts and with a negative rate shorts pay longs."`
+}
+
+// SetFundingRates sets the funding schedule of perpetual futures symbols. At
+// each funding time the holders of open positions pay or receive the rate times
+// the position notional, which is added to the balance and the realized PnL.
+func (b *BacktestTrading) SetFundingRates(rates []FundingRate) error {
+	schedule := make(map[string][]FundingRate)
+
+	for _, rate := range rates {
+		if rate.Symbol == "" {
+			return errors.New(errors.ErrCodeInvalidParameter, "funding rate symbol is required")
+		}
+
+		if rate.Time.IsZero() {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "funding rate time of %s is required", rate.Symbol)
+		}
+
+		schedule[rate.Symbol] = append(schedule[rate.Symbol], rate)
+	}
+
+	for _, symbolRates := range schedule {
+		slices.SortStableFunc(symbolRates, func(a, c FundingRate) int {
+			return a.Time.Compare(c.Time)
+		})
+	}
+
+	b.fundingRates = schedule
+	b.fundingNext = map[string]int{}
+
+	return nil
+}
+
+// applyFunding settles the funding times of the current symbol up to the bar
+// time against the position held before the bar's fills, valued at the bar open.
+func (b *BacktestTrading) applyFunding() {
+	symbol := b.marketData.Symbol
+
+	rates := b.fundingRates[symbol]
+	next := b.fundingNext[symbol]
+
+	for next < len(rates) && !rates[next].Time.After(b.marketData.Time) {
+		rate := rates[next]
+		next++
+
+		position, err := b.state.GetPosition(symbol)
+		if err != nil {
+			continue
+		}
+
+		quantity := position.TotalLongPositionQuantity - position.TotalShortPositionQuantity
+		if quantity == 0 {
+			continue
+		}
+
+		price := b.marketData.Open
+		amount := -rate.Rate * quantity * price
+		b.balance += amount
+
+		_ = b.state.RecordFundingPayment(FundingPayment{
+			Symbol:   symbol,
+			Time:     rate.Time,
+			Rate:     rate.Rate,
+			Quantity: quantity,
+			Price:    price,
+			Amount:   amount,
+		})
+	}
+
+	if len(rates) > 0 {
+		b.fundingNext[symbol] = next
+	}
+}
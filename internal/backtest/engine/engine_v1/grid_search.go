@@ -0,0 +1,286 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine"
+	"github.com/rxtech-lab/argo-trading/internal/runtime"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// GridObjective selects the statistic grid search results are ranked by.
+type GridObjective string
+
+const (
+	// GridObjectiveSharpe ranks by the mean Sharpe ratio of the symbols, highest first.
+	GridObjectiveSharpe GridObjective = "sharpe_ratio"
+	// GridObjectiveTotalPnL ranks by the total PnL of all symbols, highest first.
+	GridObjectiveTotalPnL GridObjective = "total_pnl"
+	// GridObjectiveWinRate ranks by the mean win rate of the symbols, highest first.
+	GridObjectiveWinRate GridObjective = "win_rate"
+	// GridObjectiveMaxDrawdown ranks by the largest max drawdown of the symbols, lowest first.
+	GridObjectiveMaxDrawdown GridObjective = "max_drawdown"
+)
+
+// GridSearchConfig describes the backtest every combination of a grid search runs.
+type GridSearchConfig struct {
+	// BacktestConfig is the backtest engine config (YAML) of every run.
+	BacktestConfig string
+	// StrategyConfig holds the strategy config values the grid parameters are
+	// merged into. Grid parameters replace values of the same key.
+	StrategyConfig map[string]any
+	// NewStrategy creates the strategy of a run. Runs never share a strategy
+	// instance, so runs in parallel do not share strategy state.
+	NewStrategy func() (runtime.StrategyRuntime, error)
+	// DataPath is the data file pattern of every run (see Engine.SetDataPath).
+	DataPath string
+	// ResultsFolder is the folder holding one results folder per combination.
+	ResultsFolder string
+	// Workers is the number of runs in parallel. Values below 2 run serially.
+	Workers int
+	// Objective is the statistic results are ranked by. Defaults to GridObjectiveSharpe.
+	Objective GridObjective
+}
+
+// GridSearchResult is the result of one parameter combination.
+type GridSearchResult struct {
+	// Rank is the 1-based position of the combination by the objective.
+	Rank int
+	// Params are the grid parameter values of the combination.
+	Params map[string]any
+	// StrategyConfig is the strategy config (YAML) the combination ran with.
+	StrategyConfig string
+	// ResultsFolder is the folder holding the results of the combination.
+	ResultsFolder string
+	// Score is the objective value of the combination.
+	Score float64
+	// Stats are the statistics of every run of the combination, one per symbol.
+	Stats []types.TradeStats
+}
+
+// GridSearch runs the backtest of base once per combination of the paramGrid
+// values and returns the results ranked by the objective. Every run gets its
+// own engine, so its state and market data live in their own in-memory DuckDB
+// databases. The callbacks are passed to every run, concurrently when more
+// than one worker is configured.
+func GridSearch(ctx context.Context, base GridSearchConfig, paramGrid map[string][]any, callbacks engine.LifecycleCallbacks) ([]GridSearchResult, error) {
+	if base.NewStrategy == nil {
+		return nil, errors.New(errors.ErrCodeInvalidParameter, "grid search needs a strategy constructor")
+	}
+
+	objective := base.Objective
+	if objective == "" {
+		objective = GridObjectiveSharpe
+	}
+
+	if !slices.Contains([]GridObjective{GridObjectiveSharpe, GridObjectiveTotalPnL, GridObjectiveWinRate, GridObjectiveMaxDrawdown}, objective) {
+		return nil, errors.Newf(errors.ErrCodeInvalidParameter, "unknown grid search objective %q", objective)
+	}
+
+	combinations, err := gridCombinations(paramGrid)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]GridSearchResult, len(combinations))
+	errs := make([]error, len(combinations))
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range max(base.Workers, 1) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				results[i], errs[i] = runGridCombination(ctx, base, combinations[i], i, callbacks)
+			}
+		}()
+	}
+
+	for i := range combinations {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.Wrapf(errors.ErrCodeBacktestInitFailed, err, "grid search combination %d failed", i)
+		}
+	}
+
+	for i := range results {
+		results[i].Score = gridScore(objective, results[i].Stats)
+	}
+
+	// Stable, so combinations with the same score keep the grid order
+	sort.SliceStable(results, func(i, j int) bool {
+		if objective == GridObjectiveMaxDrawdown {
+			return results[i].Score < results[j].Score
+		}
+
+		return results[i].Score > results[j].Score
+	})
+
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+
+	return results, nil
+}
+
+// runGridCombination runs the backtest of one parameter combination on a new engine.
+func runGridCombination(ctx context.Context, base GridSearchConfig, params map[string]any, index int, callbacks engine.LifecycleCallbacks) (GridSearchResult, error) {
+	strategyConfig := make(map[string]any, len(base.StrategyConfig)+len(params))
+	for key, value := range base.StrategyConfig {
+		strategyConfig[key] = value
+	}
+
+	for key, value := range params {
+		strategyConfig[key] = value
+	}
+
+	content, err := yaml.Marshal(strategyConfig)
+	if err != nil {
+		return GridSearchResult{}, errors.Wrap(errors.ErrCodeInvalidParameter, "failed to encode strategy config", err)
+	}
+
+	result := GridSearchResult{
+		Rank:           0,
+		Params:         params,
+		StrategyConfig: string(content),
+		ResultsFolder:  filepath.Join(base.ResultsFolder, fmt.Sprintf("combination_%d", index)),
+		Score:          0,
+		Stats:          nil,
+	}
+
+	e, err := NewBacktestEngineV1()
+	if err != nil {
+		return result, err
+	}
+
+	backtestEngine, ok := e.(*BacktestEngineV1)
+	if !ok {
+		return result, errors.New(errors.ErrCodeBacktestInitFailed, "unexpected backtest engine type")
+	}
+
+	strategy, err := base.NewStrategy()
+	if err != nil {
+		return result, errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to create strategy", err)
+	}
+
+	if err := backtestEngine.Initialize(base.BacktestConfig); err != nil {
+		return result, err
+	}
+
+	if err := backtestEngine.LoadStrategy(strategy); err != nil {
+		return result, err
+	}
+
+	if err := backtestEngine.SetConfigContent([]string{result.StrategyConfig}); err != nil {
+		return result, err
+	}
+
+	if err := backtestEngine.SetDataPath(base.DataPath); err != nil {
+		return result, err
+	}
+
+	if err := backtestEngine.SetResultsFolder(result.ResultsFolder); err != nil {
+		return result, err
+	}
+
+	// The stats of each run are captured before the next run replaces them
+	onRunEnd := engine.OnRunEndCallback(func(configIndex int, configName string, dataFileIndex int, dataFilePath string, resultFolderPath string) {
+		if backtestEngine.lastRun != nil {
+			result.Stats = append(result.Stats, backtestEngine.lastRun.stats...)
+		}
+
+		if callbacks.OnRunEnd != nil {
+			(*callbacks.OnRunEnd)(configIndex, configName, dataFileIndex, dataFilePath, resultFolderPath)
+		}
+	})
+
+	runCallbacks := callbacks
+	runCallbacks.OnRunEnd = &onRunEnd
+
+	if err := backtestEngine.Run(ctx, runCallbacks); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// gridCombinations expands the grid into every combination of its values,
+// ordered by the sorted parameter names with the last name varying fastest.
+// An empty grid has the single empty combination.
+func gridCombinations(paramGrid map[string][]any) ([]map[string]any, error) {
+	keys := make([]string, 0, len(paramGrid))
+
+	for key, values := range paramGrid {
+		if len(values) == 0 {
+			return nil, errors.Newf(errors.ErrCodeInvalidParameter, "grid parameter %q has no values", key)
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	combinations := []map[string]any{{}}
+
+	for _, key := range keys {
+		next := make([]map[string]any, 0, len(combinations)*len(paramGrid[key]))
+
+		for _, combination := range combinations {
+			for _, value := range paramGrid[key] {
+				extended := make(map[string]any, len(combination)+1)
+				for k, v := range combination {
+					extended[k] = v
+				}
+
+				extended[key] = value
+				next = append(next, extended)
+			}
+		}
+
+		combinations = next
+	}
+
+	return combinations, nil
+}
+
+// gridScore computes the objective value of the stats of a combination.
+func gridScore(objective GridObjective, stats []types.TradeStats) float64 {
+	if len(stats) == 0 {
+		return 0
+	}
+
+	var score float64
+
+	for _, s := range stats {
+		switch objective {
+		case GridObjectiveTotalPnL:
+			score += s.TradePnl.TotalPnL
+		case GridObjectiveWinRate:
+			score += s.TradeResult.WinRate / float64(len(stats))
+		case GridObjectiveMaxDrawdown:
+			score = max(score, s.TradeResult.MaxDrawdown)
+		case GridObjectiveSharpe:
+			score += s.TradeResult.SharpeRatio / float64(len(stats))
+		}
+	}
+
+	return score
+}
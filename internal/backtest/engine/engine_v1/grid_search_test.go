@@ -0,0 +1,241 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	engine_types "github.com/rxtech-lab/argo-trading/internal/backtest/engine"
+	"github.com/rxtech-lab/argo-trading/internal/runtime"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// gridStrategy buys quantity units after the close rose by more than min_move
+// and sells the position after it fell by more than min_move.
+type gridStrategy struct {
+	api       strategy.StrategyApi
+	quantity  float64
+	minMove   float64
+	lastClose float64
+}
+
+func (s *gridStrategy) Initialize(config string) error {
+	var params struct {
+		Quantity float64 `yaml:"quantity"`
+		MinMove  float64 `yaml:"min_move"`
+	}
+
+	if err := yaml.Unmarshal([]byte(config), &params); err != nil {
+		return err
+	}
+
+	s.quantity = params.Quantity
+	s.minMove = params.MinMove
+
+	return nil
+}
+
+func (s *gridStrategy) InitializeApi(api strategy.StrategyApi) error {
+	s.api = api
+
+	return nil
+}
+
+func (s *gridStrategy) ProcessData(data types.MarketData) error {
+	previous := s.lastClose
+	s.lastClose = data.Close
+
+	if previous == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	position, err := s.api.GetPosition(ctx, &strategy.GetPositionRequest{Symbol: data.Symbol})
+	if err != nil {
+		return err
+	}
+
+	order := &strategy.ExecuteOrder{
+		Id:           uuid.NewString(),
+		Symbol:       data.Symbol,
+		Side:         strategy.PurchaseType_PURCHASE_TYPE_BUY,
+		OrderType:    strategy.OrderType_ORDER_TYPE_MARKET,
+		Reason:       &strategy.Reason{Reason: "strategy", Message: "grid"},
+		Price:        data.Close,
+		StrategyName: s.Name(),
+		Quantity:     s.quantity,
+		PositionType: strategy.PositionType_POSITION_TYPE_LONG,
+	}
+
+	switch {
+	case data.Close-previous > s.minMove:
+	case previous-data.Close > s.minMove && position.Quantity > 0:
+		order.Side = strategy.PurchaseType_PURCHASE_TYPE_SELL
+		order.Quantity = position.Quantity
+	default:
+		return nil
+	}
+
+	_, err = s.api.PlaceOrder(ctx, order)
+
+	return err
+}
+
+func (s *gridStrategy) GetConfigSchema() (string, error) { return "", nil }
+
+func (s *gridStrategy) Name() string { return "GridStrategy" }
+
+func (s *gridStrategy) GetDescription() (string, error) { return "", nil }
+
+func (s *gridStrategy) GetRuntimeEngineVersion() (string, error) { return "1.0.0", nil }
+
+func (s *gridStrategy) GetIdentifier() (string, error) { return "com.test.grid", nil }
+
+// writeBarsParquet writes bars to a parquet file in the layout the DuckDB data source reads.
+func writeBarsParquet(t *testing.T, bars []types.MarketData) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bars.parquet")
+
+	db, err := sql.Open("duckdb", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE market_data (id TEXT, time TIMESTAMP, symbol TEXT, open DOUBLE, high DOUBLE, low DOUBLE, close DOUBLE, volume DOUBLE)`)
+	require.NoError(t, err)
+
+	for _, bar := range bars {
+		_, err = db.Exec(`INSERT INTO market_data VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			bar.Id, bar.Time, bar.Symbol, bar.Open, bar.High, bar.Low, bar.Close, bar.Volume)
+		require.NoError(t, err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`COPY market_data TO '%s' (FORMAT PARQUET)`, path))
+	require.NoError(t, err)
+
+	return path
+}
+
+func TestGridSearch(t *testing.T) {
+	setTestVersion(t, "1.0.0")
+
+	dataPath := writeBarsParquet(t, walkForwardBars(60))
+
+	newConfig := func(workers int, objective GridObjective) GridSearchConfig {
+		return GridSearchConfig{
+			BacktestConfig: "initial_capital: 100000",
+			StrategyConfig: map[string]any{"min_move": 0.0},
+			NewStrategy: func() (runtime.StrategyRuntime, error) {
+				return &gridStrategy{}, nil
+			},
+			DataPath:      dataPath,
+			ResultsFolder: t.TempDir(),
+			Workers:       workers,
+			Objective:     objective,
+		}
+	}
+
+	grid := map[string][]any{
+		"quantity": {1, 2},
+		"min_move": {0.5, 5},
+	}
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			var runs atomic.Int32
+
+			onRunEnd := engine_types.OnRunEndCallback(func(int, string, int, string, string) {
+				runs.Add(1)
+			})
+
+			results, err := GridSearch(context.Background(), newConfig(workers, GridObjectiveTotalPnL), grid,
+				engine_types.LifecycleCallbacks{OnRunEnd: &onRunEnd})
+			require.NoError(t, err)
+			require.Len(t, results, 4)
+			assert.Equal(t, int32(4), runs.Load())
+
+			configs := map[string]bool{}
+			folders := map[string]bool{}
+
+			for i, result := range results {
+				assert.Equal(t, i+1, result.Rank)
+				require.Len(t, result.Stats, 1)
+				assert.Equal(t, "AAA", result.Stats[0].Symbol)
+				assert.Equal(t, result.Stats[0].TradePnl.TotalPnL, result.Score)
+				assert.Contains(t, result.StrategyConfig, fmt.Sprintf("quantity: %v", result.Params["quantity"]))
+				assert.Contains(t, result.StrategyConfig, fmt.Sprintf("min_move: %v", result.Params["min_move"]))
+
+				if i > 0 {
+					assert.GreaterOrEqual(t, results[i-1].Score, result.Score, "results are ranked by total PnL")
+				}
+
+				configs[result.StrategyConfig] = true
+				folders[result.ResultsFolder] = true
+			}
+
+			assert.Len(t, configs, 4, "every combination runs a distinct config")
+			assert.Len(t, folders, 4)
+		})
+	}
+
+	t.Run("parallel runs match serial runs", func(t *testing.T) {
+		serial, err := GridSearch(context.Background(), newConfig(1, GridObjectiveTotalPnL), grid, engine_types.LifecycleCallbacks{})
+		require.NoError(t, err)
+
+		parallel, err := GridSearch(context.Background(), newConfig(4, GridObjectiveTotalPnL), grid, engine_types.LifecycleCallbacks{})
+		require.NoError(t, err)
+
+		for i := range serial {
+			assert.Equal(t, serial[i].StrategyConfig, parallel[i].StrategyConfig)
+			assert.Equal(t, serial[i].Stats[0].TradeResult.NumberOfTrades, parallel[i].Stats[0].TradeResult.NumberOfTrades)
+			assert.InDelta(t, serial[i].Score, parallel[i].Score, 1e-9)
+		}
+
+		// A larger min_move filters out moves, so the runs must not share orders
+		trades := map[any]int{}
+		for _, result := range serial {
+			if result.Params["quantity"] == 1 {
+				trades[result.Params["min_move"]] = result.Stats[0].TradeResult.NumberOfTrades
+			}
+		}
+
+		assert.Greater(t, trades[0.5], trades[5])
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := GridSearch(context.Background(), newConfig(1, "profit_factor"), grid, engine_types.LifecycleCallbacks{})
+		assert.Error(t, err)
+
+		_, err = GridSearch(context.Background(), newConfig(1, ""), map[string][]any{"quantity": {}}, engine_types.LifecycleCallbacks{})
+		assert.Error(t, err)
+
+		config := newConfig(1, "")
+		config.NewStrategy = nil
+		_, err = GridSearch(context.Background(), config, grid, engine_types.LifecycleCallbacks{})
+		assert.Error(t, err)
+	})
+}
+
+func TestGridCombinations(t *testing.T) {
+	combinations, err := gridCombinations(map[string][]any{"b": {1, 2}, "a": {"x", "y"}})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"a": "x", "b": 1},
+		{"a": "x", "b": 2},
+		{"a": "y", "b": 1},
+		{"a": "y", "b": 2},
+	}, combinations)
+
+	combinations, err = gridCombinations(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{}}, combinations)
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// LimitFillPolicyType selects when a bar fills a limit order.
+type LimitFillPolicyType string
+
+const (
+	// LimitFillTouch fills a limit order as soon as the bar reaches its price.
+	LimitFillTouch LimitFillPolicyType = "touch"
+	// LimitFillPenetration fills a limit order only when the bar trades through
+	// its price: a buy needs a low below the limit and a sell a high above it.
+	LimitFillPenetration LimitFillPolicyType = "penetration"
+	// LimitFillTouchVolume fills a limit order the bar trades through, and one
+	// the bar only touches when the bar volume exceeds the unfilled quantity.
+	LimitFillTouchVolume LimitFillPolicyType = "touch_volume"
+)
+
+// AllLimitFillPolicies is the list of supported limit fill policies (used by schema generation).
+var AllLimitFillPolicies = []any{
+	string(LimitFillTouch),
+	string(LimitFillPenetration),
+	string(LimitFillTouchVolume),
+}
+
+// LimitFillPolicy decides whether the bar fills a limit order.
+type LimitFillPolicy interface {
+	Fills(order types.ExecuteOrder, md types.MarketData) bool
+}
+
+// TouchLimitFill fills buys when the low is at or below the limit and sells
+// when the high is at or above it. It is the most optimistic policy since an
+// order at the extreme of a bar may not have been reached in the queue.
+type TouchLimitFill struct{}
+
+// Fills implements LimitFillPolicy.
+func (TouchLimitFill) Fills(order types.ExecuteOrder, md types.MarketData) bool {
+	return limitPenetrated(order, md) || limitTouched(order, md)
+}
+
+// PenetrationLimitFill fills only when the bar trades strictly through the limit.
+type PenetrationLimitFill struct{}
+
+// Fills implements LimitFillPolicy.
+func (PenetrationLimitFill) Fills(order types.ExecuteOrder, md types.MarketData) bool {
+	return limitPenetrated(order, md)
+}
+
+// TouchVolumeLimitFill fills when the bar trades through the limit, or touches
+// it with more volume than the order still has to fill.
+type TouchVolumeLimitFill struct{}
+
+// Fills implements LimitFillPolicy.
+func (TouchVolumeLimitFill) Fills(order types.ExecuteOrder, md types.MarketData) bool {
+	if limitPenetrated(order, md) {
+		return true
+	}
+
+	return limitTouched(order, md) && md.Volume > order.Quantity-order.FilledQuantity
+}
+
+// NewLimitFillPolicy creates the limit fill policy of the given type. An empty
+// type selects LimitFillTouch.
+func NewLimitFillPolicy(policy LimitFillPolicyType) (LimitFillPolicy, error) {
+	switch policy {
+	case "", LimitFillTouch:
+		return TouchLimitFill{}, nil
+	case LimitFillPenetration:
+		return PenetrationLimitFill{}, nil
+	case LimitFillTouchVolume:
+		return TouchVolumeLimitFill{}, nil
+	default:
+		return nil, errors.Newf(errors.ErrCodeInvalidParameter, "unknown limit fill policy %q", policy)
+	}
+}
+
+// SetLimitFillPolicy configures when bars fill limit orders.
+func (b *BacktestTrading) SetLimitFillPolicy(policy LimitFillPolicyType) error {
+	fillPolicy, err := NewLimitFillPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	b.limitFill = fillPolicy
+
+	return nil
+}
+
+// limitFills reports whether the current bar fills the limit order.
+func (b *BacktestTrading) limitFills(order types.ExecuteOrder) bool {
+	if b.limitFill == nil {
+		return TouchLimitFill{}.Fills(order, b.marketData)
+	}
+
+	return b.limitFill.Fills(order, b.marketData)
+}
+
+// limitPenetrated reports whether the bar traded strictly through the limit price.
+func limitPenetrated(order types.ExecuteOrder, md types.MarketData) bool {
+	if order.Side == types.PurchaseTypeBuy {
+		return md.Low < order.Price
+	}
+
+	return md.High > order.Price
+}
+
+// limitTouched reports whether the bar's extreme is exactly the limit price.
+func limitTouched(order types.ExecuteOrder, md types.MarketData) bool {
+	if order.Side == types.PurchaseTypeBuy {
+		return md.Low == order.Price
+	}
+
+	return md.High == order.Price
+}
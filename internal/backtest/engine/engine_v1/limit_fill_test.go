@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitFillPolicies(t *testing.T) {
+	buy := types.ExecuteOrder{Symbol: "AAPL", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeLimit, Price: 100, Quantity: 10}
+	sell := types.ExecuteOrder{Symbol: "AAPL", Side: types.PurchaseTypeSell, OrderType: types.OrderTypeLimit, Price: 100, Quantity: 10}
+
+	// The low (for buys) or high (for sells) of each bar relative to the limit of 100
+	touch := types.MarketData{Symbol: "AAPL", High: 105, Low: 100, Volume: 50}
+	thinTouch := types.MarketData{Symbol: "AAPL", High: 105, Low: 100, Volume: 5}
+	through := types.MarketData{Symbol: "AAPL", High: 105, Low: 99.99, Volume: 5}
+	above := types.MarketData{Symbol: "AAPL", High: 105, Low: 100.01, Volume: 50}
+	sellTouch := types.MarketData{Symbol: "AAPL", High: 100, Low: 95, Volume: 50}
+
+	tests := []struct {
+		name   string
+		policy LimitFillPolicyType
+		order  types.ExecuteOrder
+		md     types.MarketData
+		fills  bool
+	}{
+		{name: "touch fills when the low equals the limit", policy: LimitFillTouch, order: buy, md: touch, fills: true},
+		{name: "penetration does not fill when the low equals the limit", policy: LimitFillPenetration, order: buy, md: touch, fills: false},
+		{name: "penetration fills when the low is below the limit", policy: LimitFillPenetration, order: buy, md: through, fills: true},
+		{name: "no policy fills when the low stays above the limit", policy: LimitFillTouch, order: buy, md: above, fills: false},
+		{name: "touch volume fills a touch with more volume than the order", policy: LimitFillTouchVolume, order: buy, md: touch, fills: true},
+		{name: "touch volume does not fill a touch with less volume than the order", policy: LimitFillTouchVolume, order: buy, md: thinTouch, fills: false},
+		{name: "touch volume fills a penetration regardless of volume", policy: LimitFillTouchVolume, order: buy, md: through, fills: true},
+		{name: "touch fills a sell when the high equals the limit", policy: LimitFillTouch, order: sell, md: sellTouch, fills: true},
+		{name: "penetration does not fill a sell when the high equals the limit", policy: LimitFillPenetration, order: sell, md: sellTouch, fills: false},
+		{name: "empty policy fills on touch", policy: "", order: buy, md: touch, fills: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewLimitFillPolicy(tc.policy)
+			require.NoError(t, err)
+			assert.Equal(t, tc.fills, policy.Fills(tc.order, tc.md))
+		})
+	}
+
+	t.Run("touch volume counts only the unfilled quantity", func(t *testing.T) {
+		partial := buy
+		partial.Quantity = 20
+		partial.FilledQuantity = 16
+
+		assert.True(t, TouchVolumeLimitFill{}.Fills(partial, thinTouch))
+	})
+
+	t.Run("unknown policy is rejected", func(t *testing.T) {
+		_, err := NewLimitFillPolicy("midpoint")
+		assert.Error(t, err)
+	})
+}
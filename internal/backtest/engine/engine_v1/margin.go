@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// MarginConfig enables leveraged trading and margin calls.
+type MarginConfig struct {
+	Leverage                  float64 `yaml:"leverage" json:"leverage" jsonschema:"title=Leverage,description=Largest ratio of open position notional to margin (e.g. 3 for 3x). New positions may use the equity that is not already used as margin times the leverage. Set to 0 to trade without margin so buying power is the cash balance.,minimum=0,default=0"`
+	MaintenanceMarginFraction float64 `yaml:"maintenance_margin_fraction" json:"maintenance_margin_fraction" jsonschema:"title=Maintenance Margin Fraction,description=Fraction of the open position notional (e.g. 0.05 = 5%) the equity must stay above. When it falls below all open orders are cancelled and the position of the bar's symbol is closed at market with reason margin_call. Set to 0 to disable margin calls.,minimum=0,maximum=1,default=0"`
+}
+
+// enabled reports whether margin accounting is configured.
+func (c MarginConfig) enabled() bool {
+	return c.Leverage > 0 || c.MaintenanceMarginFraction > 0
+}
+
+// leverage returns the configured leverage, or 1 without leverage.
+func (c MarginConfig) leverage() float64 {
+	if c.Leverage < 1 {
+		return 1
+	}
+
+	return c.Leverage
+}
+
+// SetMargin configures leveraged trading. Buying power becomes the free margin
+// times the leverage, and with a maintenance margin the position of a symbol is
+// closed on the first bar its equity falls below the requirement.
+func (b *BacktestTrading) SetMargin(config MarginConfig) error {
+	if config.Leverage != 0 && config.Leverage < 1 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "leverage must be 0 or at least 1: %f", config.Leverage)
+	}
+
+	if config.MaintenanceMarginFraction < 0 || config.MaintenanceMarginFraction >= 1 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "maintenance margin fraction must be between 0 and 1: %f", config.MaintenanceMarginFraction)
+	}
+
+	if config.MaintenanceMarginFraction > 0 && config.MaintenanceMarginFraction >= 1/config.leverage() {
+		return errors.Newf(errors.ErrCodeInvalidParameter,
+			"maintenance margin fraction %f must be below the initial margin fraction %f", config.MaintenanceMarginFraction, 1/config.leverage())
+	}
+
+	b.margin = config
+
+	return nil
+}
+
+// valuePositions returns the realized and unrealized PnL and the notional of
+// the positions, each valued at the price priceOf returns for its own symbol,
+// or at its average entry price when that is 0.
+func valuePositions(positions []types.Position, priceOf func(symbol string) float64) (realizedPnL, unrealizedPnL, notional float64) {
+	for _, pos := range positions {
+		// Add realized PnL from this position
+		realizedPnL += pos.GetTotalPnL()
+
+		currentPrice := priceOf(pos.Symbol)
+
+		// Calculate unrealized PnL for open long positions
+		if pos.TotalLongPositionQuantity > 0 {
+			avgEntry := pos.GetAverageLongPositionEntryPrice()
+			price := currentPrice
+			if price == 0 {
+				price = avgEntry
+			}
+
+			unrealizedPnL += (price - avgEntry) * pos.TotalLongPositionQuantity
+			notional += price * pos.TotalLongPositionQuantity
+		}
+
+		// Calculate unrealized PnL for open short positions
+		if pos.TotalShortPositionQuantity > 0 {
+			avgEntry := pos.GetAverageShortPositionEntryPrice()
+			price := currentPrice
+			if price == 0 {
+				price = avgEntry
+			}
+
+			unrealizedPnL += (avgEntry - price) * pos.TotalShortPositionQuantity
+			notional += price * pos.TotalShortPositionQuantity
+		}
+	}
+
+	return realizedPnL, unrealizedPnL, notional
+}
+
+// positionsValue returns the market value of the positions: the value of the
+// long quantities less that of the short quantities, each at the price priceOf
+// returns for its symbol, or at its average entry price when that is 0.
+func positionsValue(positions []types.Position, priceOf func(symbol string) float64) float64 {
+	value := 0.0
+
+	for _, pos := range positions {
+		price := priceOf(pos.Symbol)
+
+		if pos.TotalLongPositionQuantity > 0 {
+			longPrice := price
+			if longPrice == 0 {
+				longPrice = pos.GetAverageLongPositionEntryPrice()
+			}
+
+			value += longPrice * pos.TotalLongPositionQuantity
+		}
+
+		if pos.TotalShortPositionQuantity > 0 {
+			shortPrice := price
+			if shortPrice == 0 {
+				shortPrice = pos.GetAverageShortPositionEntryPrice()
+			}
+
+			value -= shortPrice * pos.TotalShortPositionQuantity
+		}
+	}
+
+	return value
+}
+
+// markPrice returns the price positions of symbol are valued at: the current
+// bar's price for the bar's symbol, the last close seen for other symbols, or 0
+// when no bar of the symbol has been seen yet.
+func (b *BacktestTrading) markPrice(symbol string) float64 {
+	if symbol == b.marketData.Symbol {
+		if price := closePrice(b.marketData); price > 0 {
+			return price
+		}
+	}
+
+	return b.lastPrices[symbol]
+}
+
+// closePrice returns the close of a bar, or its mid price when the bar has no close.
+func closePrice(bar types.MarketData) float64 {
+	if bar.Close != 0 {
+		return bar.Close
+	}
+
+	return barPrice(bar)
+}
+
+// usedMargin returns the margin held by open positions of the given notional.
+// It is zero without margin accounting.
+func (b *BacktestTrading) usedMargin(notional float64) float64 {
+	if !b.margin.enabled() {
+		return 0
+	}
+
+	return notional / b.margin.leverage()
+}
+
+// marginEquity returns the equity backing the margin: the balance plus the
+// realized and unrealized PnL of the positions.
+func (b *BacktestTrading) marginEquity(realizedPnL, unrealizedPnL float64) float64 {
+	return b.balance + realizedPnL + unrealizedPnL
+}
+
+// marginBuyingPower returns the notional the free margin can open at the configured leverage.
+func (b *BacktestTrading) marginBuyingPower() float64 {
+	positions, err := b.state.GetAllPositions()
+	if err != nil {
+		return 0
+	}
+
+	realizedPnL, unrealizedPnL, notional := valuePositions(positions, b.markPrice)
+	freeMargin := b.marginEquity(realizedPnL, unrealizedPnL) - b.usedMargin(notional)
+
+	return max(freeMargin*b.margin.leverage(), 0)
+}
+
+// checkMarginCall cancels all open orders and closes the current symbol's
+// position when the equity has fallen below the maintenance margin. Positions
+// of other symbols are closed on their own bars if the equity is still short,
+// so each is filled at a price of its own symbol.
+func (b *BacktestTrading) checkMarginCall() {
+	if b.margin.MaintenanceMarginFraction <= 0 {
+		return
+	}
+
+	positions, err := b.state.GetAllPositions()
+	if err != nil {
+		return
+	}
+
+	realizedPnL, unrealizedPnL, notional := valuePositions(positions, b.markPrice)
+	if notional == 0 || b.marginEquity(realizedPnL, unrealizedPnL) >= notional*b.margin.MaintenanceMarginFraction {
+		return
+	}
+
+	_ = b.CancelAllOrders()
+
+	position, err := b.state.GetPosition(b.marketData.Symbol)
+	if err != nil {
+		return
+	}
+
+	if position.TotalLongPositionQuantity > 0 {
+		_ = b.executeMarketOrder(b.marginCallOrder(position, types.PurchaseTypeSell, types.PositionTypeLong, position.TotalLongPositionQuantity))
+	}
+
+	if position.TotalShortPositionQuantity > 0 {
+		_ = b.executeMarketOrder(b.marginCallOrder(position, types.PurchaseTypeBuy, types.PositionTypeShort, position.TotalShortPositionQuantity))
+	}
+}
+
+// marginCallOrder builds the market order that closes a position on a margin call.
+func (b *BacktestTrading) marginCallOrder(position types.Position, side types.PurchaseType, positionType types.PositionType, quantity float64) types.ExecuteOrder {
+	return types.ExecuteOrder{
+		ID:        uuid.New().String(),
+		Symbol:    position.Symbol,
+		Side:      side,
+		OrderType: types.OrderTypeMarket,
+		Reason: types.Reason{
+			Reason:  types.OrderReasonMarginCall,
+			Message: fmt.Sprintf("close %s position below maintenance margin", positionType),
+		},
+		Price:          b.marketData.Close,
+		StrategyName:   position.StrategyName,
+		Quantity:       quantity,
+		PositionType:   positionType,
+		StopPrice:      0,
+		TrailOffset:    0,
+		TrailPercent:   false,
+		FilledQuantity: 0,
+		TimeInForce:    types.TimeInForceGTC,
+		GoodTillDate:   time.Time{},
+		TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+	}
+}
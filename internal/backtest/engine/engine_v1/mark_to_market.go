@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// MarkToMarketConfig configures equity snapshots taken at fixed times of day
+// (e.g. each session close), independent of the bar frequency.
+type MarkToMarketConfig struct {
+	Times    []string `yaml:"times" json:"times" jsonschema:"title=Times,description=Times of day (HH:MM) at which to snapshot equity (e.g. 16:00 for the US session close). Leave empty to disable snapshots."`
+	Timezone string   `yaml:"timezone" json:"timezone" jsonschema:"title=Timezone,description=IANA timezone the times are expressed in (e.g. America/New_York). Defaults to UTC."`
+}
+
+// Enabled reports whether any snapshot time is configured.
+func (c MarkToMarketConfig) Enabled() bool {
+	return len(c.Times) > 0
+}
+
+// clockTime is a time of day in minutes since midnight.
+type clockTime int
+
+// parseClockTime parses an HH:MM time of day.
+func parseClockTime(value string) (clockTime, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM: %w", value, err)
+	}
+
+	return clockTime(parsed.Hour()*60 + parsed.Minute()), nil
+}
+
+// loadTimezone resolves an IANA timezone name, defaulting to UTC when empty.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+
+	return location, nil
+}
+
+// clockTimeOf returns the time of day of t in the location.
+func clockTimeOf(t time.Time, location *time.Location) clockTime {
+	local := t.In(location)
+
+	return clockTime(local.Hour()*60 + local.Minute())
+}
+
+// MarkToMarketScheduler decides when equity snapshots are due while bars are
+// streamed in time order. A snapshot at time T values positions at the last
+// close observed at or before T, so it becomes due once a bar later than T
+// arrives (or the run ends). Scheduled times without any bar since the
+// previous snapshot (weekends, holidays) are skipped.
+type MarkToMarketScheduler struct {
+	times       []clockTime
+	location    *time.Location
+	closes      map[string]float64
+	next        time.Time
+	hasBarSince bool
+	lastBarTime time.Time
+}
+
+// NewMarkToMarketScheduler parses the config into a scheduler.
+func NewMarkToMarketScheduler(config MarkToMarketConfig) (*MarkToMarketScheduler, error) {
+	location, err := loadTimezone(config.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]clockTime, 0, len(config.Times))
+
+	for _, value := range config.Times {
+		clock, err := parseClockTime(value)
+		if err != nil {
+			return nil, err
+		}
+
+		times = append(times, clock)
+	}
+
+	slices.Sort(times)
+
+	return &MarkToMarketScheduler{
+		times:       slices.Compact(times),
+		location:    location,
+		closes:      map[string]float64{},
+		next:        time.Time{},
+		hasBarSince: false,
+		lastBarTime: time.Time{},
+	}, nil
+}
+
+// nextScheduled returns the first scheduled time at or after t.
+func (s *MarkToMarketScheduler) nextScheduled(t time.Time) time.Time {
+	local := t.In(s.location)
+	year, month, day := local.Date()
+
+	// A scheduled time always exists within the current or the following day
+	for offset := 0; offset <= 1; offset++ {
+		for _, clock := range s.times {
+			candidate := time.Date(year, month, day+offset, int(clock)/60, int(clock)%60, 0, 0, s.location)
+			if !candidate.Before(t) {
+				return candidate
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// Due returns the snapshot times that are complete before the bar at barTime is
+// applied. Call it before processing the bar, then call Observe with the bar.
+func (s *MarkToMarketScheduler) Due(barTime time.Time) []time.Time {
+	if len(s.times) == 0 {
+		return nil
+	}
+
+	if s.next.IsZero() {
+		s.next = s.nextScheduled(barTime)
+
+		return nil
+	}
+
+	var due []time.Time
+
+	for barTime.After(s.next) {
+		if s.hasBarSince {
+			due = append(due, s.next)
+			s.hasBarSince = false
+		}
+
+		s.next = s.nextScheduled(s.next.Add(time.Minute))
+	}
+
+	return due
+}
+
+// Observe records the bar's close for valuation.
+func (s *MarkToMarketScheduler) Observe(data types.MarketData) {
+	s.closes[data.Symbol] = data.Close
+	s.hasBarSince = true
+	s.lastBarTime = data.Time
+}
+
+// Flush returns the pending snapshot time at the end of the run when it falls on
+// the same day as the last bar, so the final session is not lost when the data
+// stops before the scheduled time.
+func (s *MarkToMarketScheduler) Flush() []time.Time {
+	if !s.hasBarSince || s.next.IsZero() {
+		return nil
+	}
+
+	lastYear, lastMonth, lastDay := s.lastBarTime.In(s.location).Date()
+	nextYear, nextMonth, nextDay := s.next.In(s.location).Date()
+
+	if lastYear != nextYear || lastMonth != nextMonth || lastDay != nextDay {
+		return nil
+	}
+
+	s.hasBarSince = false
+
+	return []time.Time{s.next}
+}
+
+// Closes returns the last observed close per symbol.
+func (s *MarkToMarketScheduler) Closes() map[string]float64 {
+	return s.closes
+}
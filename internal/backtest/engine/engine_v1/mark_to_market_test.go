@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// MarkToMarketTestSuite is a test suite for mark-to-market equity snapshots
+type MarkToMarketTestSuite struct {
+	suite.Suite
+	state *BacktestState
+}
+
+func TestMarkToMarketSuite(t *testing.T) {
+	suite.Run(t, new(MarkToMarketTestSuite))
+}
+
+func (suite *MarkToMarketTestSuite) SetupTest() {
+	logger, err := logger.NewLogger()
+	suite.Require().NoError(err)
+
+	suite.state, err = NewBacktestState(logger)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.state.Initialize())
+	suite.state.SetInitialBalance(10000)
+}
+
+func (suite *MarkToMarketTestSuite) TearDownTest() {
+	suite.state.db.Close()
+}
+
+// minuteBars returns one bar per minute of the regular US session (09:30-15:59 New York)
+// for each day, with the close rising by 0.01 every minute from the given start price.
+func minuteBars(location *time.Location, days []time.Time, startPrice float64) []types.MarketData {
+	var bars []types.MarketData
+
+	price := startPrice
+
+	for _, day := range days {
+		open := time.Date(day.Year(), day.Month(), day.Day(), 9, 30, 0, 0, location)
+		for minute := 0; minute < 390; minute++ {
+			bars = append(bars, types.MarketData{
+				Symbol: "AAPL",
+				Time:   open.Add(time.Duration(minute) * time.Minute).UTC(),
+				Open:   price,
+				High:   price,
+				Low:    price,
+				Close:  price,
+			})
+			price += 0.01
+		}
+	}
+
+	return bars
+}
+
+func (suite *MarkToMarketTestSuite) TestSessionCloseSnapshotsFromMinuteData() {
+	location, err := time.LoadLocation("America/New_York")
+	suite.Require().NoError(err)
+
+	scheduler, err := NewMarkToMarketScheduler(MarkToMarketConfig{
+		Times:    []string{"16:00"},
+		Timezone: "America/New_York",
+	})
+	suite.Require().NoError(err)
+
+	// Friday and the following Monday: the weekend session closes have no bars and are skipped
+	days := []time.Time{
+		time.Date(2024, 1, 5, 0, 0, 0, 0, location),
+		time.Date(2024, 1, 8, 0, 0, 0, 0, location),
+	}
+	bars := minuteBars(location, days, 100)
+
+	var snapshots []types.EquitySnapshot
+
+	for i, bar := range bars {
+		for _, t := range scheduler.Due(bar.Time) {
+			snapshot, err := suite.state.RecordEquitySnapshot(t, scheduler.Closes())
+			suite.Require().NoError(err)
+			snapshots = append(snapshots, snapshot)
+		}
+
+		scheduler.Observe(bar)
+
+		// Buy 10 shares on the first bar
+		if i == 0 {
+			_, err := suite.state.Update([]types.Order{{
+				Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 10, Price: bar.Close, Timestamp: bar.Time,
+				IsCompleted: true, Status: types.OrderStatusFilled, Reason: types.Reason{Reason: "strategy", Message: "buy"},
+				StrategyName: "test", PositionType: types.PositionTypeLong,
+			}})
+			suite.Require().NoError(err)
+		}
+	}
+
+	for _, t := range scheduler.Flush() {
+		snapshot, err := suite.state.RecordEquitySnapshot(t, scheduler.Closes())
+		suite.Require().NoError(err)
+		snapshots = append(snapshots, snapshot)
+	}
+
+	suite.Require().Len(snapshots, 2, "one snapshot per session close")
+	suite.Equal(time.Date(2024, 1, 5, 16, 0, 0, 0, location).Unix(), snapshots[0].Time.Unix())
+	suite.Equal(time.Date(2024, 1, 8, 16, 0, 0, 0, location).Unix(), snapshots[1].Time.Unix())
+
+	// Each snapshot is valued at the 15:59 close of its session
+	for i, snapshot := range snapshots {
+		sessionClose := bars[(i+1)*390-1].Close
+		suite.InDelta(9000.0, snapshot.Balance, 0.0001)
+		suite.InDelta(10*sessionClose, snapshot.PositionValue, 0.0001)
+		suite.InDelta(9000.0+10*sessionClose, snapshot.Equity, 0.0001)
+	}
+
+	stored, err := suite.state.GetEquitySnapshots()
+	suite.Require().NoError(err)
+	suite.Require().Len(stored, 2)
+	suite.InDelta(snapshots[1].Equity, stored[1].Equity, 0.0001)
+}
+
+func (suite *MarkToMarketTestSuite) TestMultipleTimesPerDay() {
+	scheduler, err := NewMarkToMarketScheduler(MarkToMarketConfig{Times: []string{"16:00", "12:00"}})
+	suite.Require().NoError(err)
+
+	start := time.Date(2024, 1, 2, 11, 0, 0, 0, time.UTC)
+
+	var due []time.Time
+
+	// Hourly bars from 11:00 to 17:00
+	for hour := 0; hour <= 6; hour++ {
+		bar := types.MarketData{Symbol: "AAPL", Time: start.Add(time.Duration(hour) * time.Hour), Close: 100}
+		due = append(due, scheduler.Due(bar.Time)...)
+		scheduler.Observe(bar)
+	}
+
+	suite.Equal([]time.Time{
+		time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 16, 0, 0, 0, time.UTC),
+	}, due)
+	suite.Empty(scheduler.Flush(), "the next snapshot is on the following day")
+}
+
+func (suite *MarkToMarketTestSuite) TestInvalidConfig() {
+	_, err := NewMarkToMarketScheduler(MarkToMarketConfig{Times: []string{"4pm"}})
+	suite.Error(err)
+
+	_, err = NewMarkToMarketScheduler(MarkToMarketConfig{Times: []string{"16:00"}, Timezone: "Mars/Olympus"})
+	suite.Error(err)
+}
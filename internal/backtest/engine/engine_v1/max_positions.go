@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// SetMaxOpenPositions caps the number of symbols with an open long or short
+// position. Orders that would open a position in another symbol once the cap is
+// reached are rejected with reason max_positions. Zero disables the cap.
+func (b *BacktestTrading) SetMaxOpenPositions(maxOpenPositions int) error {
+	if maxOpenPositions < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "max open positions must not be negative: %d", maxOpenPositions)
+	}
+
+	b.maxOpenPositions = maxOpenPositions
+
+	return nil
+}
+
+// rejectOverMaxPositions stores a failed order and reports true when the order
+// would open a position in a symbol without exposure while the number of open
+// positions is already at the cap. Orders on symbols with a position, including
+// those that reduce or close it, are always allowed.
+func (b *BacktestTrading) rejectOverMaxPositions(order types.ExecuteOrder) (bool, error) {
+	if b.maxOpenPositions == 0 || !isEntryOrder(order) {
+		return false, nil
+	}
+
+	positions, err := b.state.GetAllPositions()
+	if err != nil {
+		return true, err
+	}
+
+	open := 0
+
+	for _, position := range positions {
+		if position.TotalLongPositionQuantity <= 0 && position.TotalShortPositionQuantity <= 0 {
+			continue
+		}
+
+		if position.Symbol == order.Symbol {
+			return false, nil
+		}
+
+		open++
+	}
+
+	if open < b.maxOpenPositions {
+		return false, nil
+	}
+
+	failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonMaxPositions,
+		fmt.Sprintf("%d positions are already open, the maximum", open))
+
+	return true, b.state.StoreFailedOrder(failedOrder)
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// SetMinHoldingPeriod sets the minimum time, measured on the bar time, a
+// position is held after its opening fill. Orders that would reduce or close
+// the position earlier are rejected with reason min_holding. An empty or zero
+// period disables the constraint.
+func (b *BacktestTrading) SetMinHoldingPeriod(period string) error {
+	if period == "" {
+		b.minHoldingPeriod = 0
+
+		return nil
+	}
+
+	duration, err := time.ParseDuration(period)
+	if err != nil {
+		return errors.Wrapf(errors.ErrCodeInvalidParameter, err, "invalid min holding period %q", period)
+	}
+
+	if duration < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "min holding period must not be negative: %s", period)
+	}
+
+	b.minHoldingPeriod = duration
+
+	return nil
+}
+
+// rejectBeforeMinHolding stores a failed order and reports true when the order
+// would reduce or close a position before the minimum holding period since the
+// position's opening fill has elapsed.
+func (b *BacktestTrading) rejectBeforeMinHolding(order types.ExecuteOrder) (bool, error) {
+	if b.minHoldingPeriod <= 0 || isEntryOrder(order) {
+		return false, nil
+	}
+
+	openedAt, ok := b.positionOpenedAt[positionSideKey(order.Symbol, order.PositionType)]
+	if !ok {
+		return false, nil
+	}
+
+	heldUntil := openedAt.Add(b.minHoldingPeriod)
+	if !b.marketData.Time.Before(heldUntil) {
+		return false, nil
+	}
+
+	failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonMinHolding,
+		fmt.Sprintf("the %s %s position opened at %s must be held until %s",
+			order.Symbol, order.PositionType, openedAt.Format(time.RFC3339), heldUntil.Format(time.RFC3339)))
+
+	return true, b.state.StoreFailedOrder(failedOrder)
+}
+
+// recordPositionOpening tracks the opening fill time of the position the
+// executed order traded. The time is kept from the fill that opens the side
+// until the fill that closes it.
+func (b *BacktestTrading) recordPositionOpening(executedOrder types.Order) error {
+	if b.minHoldingPeriod <= 0 {
+		return nil
+	}
+
+	position, err := b.state.GetPosition(executedOrder.Symbol)
+	if err != nil {
+		return err
+	}
+
+	quantity := position.TotalLongPositionQuantity
+	if executedOrder.PositionType == types.PositionTypeShort {
+		quantity = position.TotalShortPositionQuantity
+	}
+
+	key := positionSideKey(executedOrder.Symbol, executedOrder.PositionType)
+
+	if quantity <= lotTolerance {
+		delete(b.positionOpenedAt, key)
+
+		return nil
+	}
+
+	if _, ok := b.positionOpenedAt[key]; !ok {
+		b.positionOpenedAt[key] = executedOrder.Timestamp
+	}
+
+	return nil
+}
+
+// positionSideKey returns the key of one side of a symbol's position. Orders
+// without a position type trade the long side.
+func positionSideKey(symbol string, positionType types.PositionType) string {
+	if positionType != types.PositionTypeShort {
+		positionType = types.PositionTypeLong
+	}
+
+	return symbol + "|" + string(positionType)
+}
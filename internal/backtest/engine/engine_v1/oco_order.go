@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"github.com/google/uuid"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// ocoLeg links a pending order to the OCO group it belongs to.
+type ocoLeg struct {
+	groupID string
+	// stopLoss is true for the stop loss leg and false for the take profit leg.
+	stopLoss bool
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+// The entry is placed like any other order. Both legs rest in pending orders under
+// a shared group ID; when processPendingOrders fills one of them the other is
+// removed. If both legs trigger on the same bar the stop loss fills, since the
+// bar does not tell which price was reached first.
+func (b *BacktestTrading) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	if err := tradingprovider.ValidateOCOOrder(entry, takeProfit, stopLoss); err != nil {
+		return "", err
+	}
+
+	legs := []types.ExecuteOrder{takeProfit, stopLoss}
+	for i := range legs {
+		legs[i].ID = uuid.New().String()
+
+		quantity, err := b.roundOrderQuantity(legs[i].Symbol, legs[i].Quantity)
+		if err != nil {
+			return "", err
+		}
+
+		legs[i].Quantity = quantity
+		if legs[i].Quantity <= 0 {
+			return "", errors.New(errors.ErrCodeInvalidParameter, "OCO leg quantity is too small or zero after rounding to configured precision")
+		}
+
+		if err := legs[i].Validate(); err != nil {
+			return "", err
+		}
+	}
+
+	if err := b.PlaceOrder(entry); err != nil {
+		return "", err
+	}
+
+	return b.restOCOLegs(legs[0], legs[1]), nil
+}
+
+// restOCOLegs adds takeProfit and stopLoss to the pending orders as the legs of
+// a new OCO group and returns the group ID.
+func (b *BacktestTrading) restOCOLegs(takeProfit, stopLoss types.ExecuteOrder) string {
+	if b.ocoLegs == nil {
+		b.ocoLegs = map[string]ocoLeg{}
+	}
+
+	groupID := uuid.New().String()
+	for i, leg := range []types.ExecuteOrder{takeProfit, stopLoss} {
+		b.pendingOrders = append(b.pendingOrders, leg)
+		b.ocoLegs[leg.ID] = ocoLeg{groupID: groupID, stopLoss: i == 1}
+	}
+
+	return groupID
+}
+
+// resolveOCOFills keeps one fill per OCO group and removes the other leg of each
+// filled group from the remaining pending orders.
+func (b *BacktestTrading) resolveOCOFills(fills []pendingFill, remaining []types.ExecuteOrder) ([]pendingFill, []types.ExecuteOrder) {
+	if len(b.ocoLegs) == 0 {
+		return fills, remaining
+	}
+
+	// filledGroups maps the ID of each filled group to its fill's index in resolved
+	filledGroups := map[string]int{}
+	resolved := make([]pendingFill, 0, len(fills))
+
+	for _, fill := range fills {
+		leg, ok := b.ocoLegs[fill.order.ID]
+		if !ok {
+			resolved = append(resolved, fill)
+
+			continue
+		}
+
+		if index, filled := filledGroups[leg.groupID]; filled {
+			if leg.stopLoss {
+				resolved[index] = fill
+			}
+
+			continue
+		}
+
+		filledGroups[leg.groupID] = len(resolved)
+		resolved = append(resolved, fill)
+	}
+
+	if len(filledGroups) == 0 {
+		return resolved, remaining
+	}
+
+	stillPending := make([]types.ExecuteOrder, 0, len(remaining))
+
+	for _, order := range remaining {
+		if leg, ok := b.ocoLegs[order.ID]; ok {
+			if _, filled := filledGroups[leg.groupID]; filled {
+				continue
+			}
+		}
+
+		stillPending = append(stillPending, order)
+	}
+
+	for orderID, leg := range b.ocoLegs {
+		if _, filled := filledGroups[leg.groupID]; filled {
+			delete(b.ocoLegs, orderID)
+		}
+	}
+
+	return resolved, stillPending
+}
+
+// cancelOCOGroup removes every pending leg of the OCO group orderID belongs to.
+// It reports false when orderID is not an OCO leg.
+func (b *BacktestTrading) cancelOCOGroup(orderID string) bool {
+	cancelled, ok := b.ocoLegs[orderID]
+	if !ok {
+		return false
+	}
+
+	stillPending := make([]types.ExecuteOrder, 0, len(b.pendingOrders))
+
+	for _, order := range b.pendingOrders {
+		if leg, isLeg := b.ocoLegs[order.ID]; isLeg && leg.groupID == cancelled.groupID {
+			delete(b.ocoLegs, order.ID)
+
+			continue
+		}
+
+		stillPending = append(stillPending, order)
+	}
+
+	b.pendingOrders = stillPending
+
+	return true
+}
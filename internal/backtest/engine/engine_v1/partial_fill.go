@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// SetMaxVolumeParticipation caps the quantity an order fills per bar at fraction
+// of the bar volume. The rest of the order stays pending and fills on the
+// following bars. A fraction of 0 fills orders in full.
+func (b *BacktestTrading) SetMaxVolumeParticipation(fraction float64) error {
+	if fraction < 0 || fraction > 1 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "max volume participation must be between 0 and 1: %f", fraction)
+	}
+
+	b.maxVolumeParticipation = fraction
+
+	return nil
+}
+
+// unfilledQuantity returns the part of the order that has not filled yet.
+func (b *BacktestTrading) unfilledQuantity(order types.ExecuteOrder) float64 {
+	return b.floorQuantity(order.Symbol, order.Quantity-order.FilledQuantity)
+}
+
+// fillableQuantity returns how much of the order can fill on the current bar:
+// its unfilled quantity, capped at the configured share of the bar volume.
+func (b *BacktestTrading) fillableQuantity(order types.ExecuteOrder) float64 {
+	quantity := b.unfilledQuantity(order)
+	if b.maxVolumeParticipation <= 0 {
+		return quantity
+	}
+
+	return min(quantity, b.floorQuantity(order.Symbol, b.marketData.Volume*b.maxVolumeParticipation))
+}
+
+// keepUnfilledRemainder records filled against the order and keeps the rest of
+// it pending. A triggered stop keeps filling as a market order. The rest of an
+// IOC order is cancelled instead.
+func (b *BacktestTrading) keepUnfilledRemainder(order types.ExecuteOrder, filled float64) error {
+	order.FilledQuantity += filled
+	if b.unfilledQuantity(order) <= 0 {
+		return nil
+	}
+
+	if fillsImmediately(order) {
+		return b.expireOrder(order)
+	}
+
+	if order.OrderType == types.OrderTypeStopMarket || order.OrderType == types.OrderTypeTrailingStop {
+		order.OrderType = types.OrderTypeMarket
+	}
+
+	b.pendingOrders = append(b.pendingOrders, order)
+
+	return nil
+}
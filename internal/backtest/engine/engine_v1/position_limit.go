@@ -0,0 +1,187 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// PositionLimitMode selects what happens to orders that would exceed a position limit.
+type PositionLimitMode string
+
+const (
+	// PositionLimitModeReject rejects the whole order and records it as a failed order.
+	PositionLimitModeReject PositionLimitMode = "reject"
+	// PositionLimitModeTruncate reduces the order to the quantity left under the
+	// limit and rejects it only when no quantity is left.
+	PositionLimitModeTruncate PositionLimitMode = "truncate"
+)
+
+// AllPositionLimitModes is the list of supported position limit modes (used by schema generation).
+var AllPositionLimitModes = []any{
+	string(PositionLimitModeReject),
+	string(PositionLimitModeTruncate),
+}
+
+// PositionLimit caps the size of one symbol's position.
+type PositionLimit struct {
+	MaxPositionQuantity float64 `yaml:"max_position_quantity" json:"max_position_quantity" jsonschema:"title=Max Position Quantity,description=Largest long or short quantity of the symbol including the unfilled quantity of open entry orders. Set to 0 to disable.,minimum=0,default=0"`
+	MaxPositionNotional float64 `yaml:"max_position_notional" json:"max_position_notional" jsonschema:"title=Max Position Notional,description=Largest long or short notional of the symbol including the unfilled quantity of open entry orders valued at the price of the new order. Set to 0 to disable.,minimum=0,default=0"`
+}
+
+// PositionLimitConfig caps the size of the position of each symbol, so a
+// strategy cannot concentrate its capital in a single symbol.
+type PositionLimitConfig struct {
+	MaxPositionQuantity float64                  `yaml:"max_position_quantity" json:"max_position_quantity" jsonschema:"title=Max Position Quantity,description=Largest long or short quantity of each symbol including the unfilled quantity of open entry orders. Set to 0 to disable.,minimum=0,default=0"`
+	MaxPositionNotional float64                  `yaml:"max_position_notional" json:"max_position_notional" jsonschema:"title=Max Position Notional,description=Largest long or short notional of each symbol including the unfilled quantity of open entry orders valued at the price of the new order. Set to 0 to disable.,minimum=0,default=0"`
+	Mode                PositionLimitMode        `yaml:"mode" json:"mode" jsonschema:"title=Mode,description=What to do with entry orders that would exceed a limit. 'reject' rejects them with reason position_limit; 'truncate' reduces them to the quantity left under the limit. Defaults to 'reject'.,default=reject"`
+	Symbols             map[string]PositionLimit `yaml:"symbols" json:"symbols" jsonschema:"title=Symbols,description=Limits of individual symbols keyed by symbol that replace the default limits."`
+}
+
+// Enabled reports whether a position limit is configured for any symbol.
+func (c PositionLimitConfig) Enabled() bool {
+	return c.MaxPositionQuantity != 0 || c.MaxPositionNotional != 0 || len(c.Symbols) > 0
+}
+
+// limitFor returns the limit that applies to the symbol.
+func (c PositionLimitConfig) limitFor(symbol string) PositionLimit {
+	if limit, ok := c.Symbols[symbol]; ok {
+		return limit
+	}
+
+	return PositionLimit{
+		MaxPositionQuantity: c.MaxPositionQuantity,
+		MaxPositionNotional: c.MaxPositionNotional,
+	}
+}
+
+// SetPositionLimit configures the per-symbol position limits. A config without
+// limits disables them.
+func (b *BacktestTrading) SetPositionLimit(config PositionLimitConfig) error {
+	if !config.Enabled() {
+		b.positionLimit = nil
+
+		return nil
+	}
+
+	if config.MaxPositionQuantity < 0 || config.MaxPositionNotional < 0 {
+		return errors.New(errors.ErrCodeInvalidParameter, "position limits must not be negative")
+	}
+
+	for symbol, limit := range config.Symbols {
+		if limit.MaxPositionQuantity < 0 || limit.MaxPositionNotional < 0 {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "position limits of %s must not be negative", symbol)
+		}
+	}
+
+	switch config.Mode {
+	case "":
+		config.Mode = PositionLimitModeReject
+	case PositionLimitModeReject, PositionLimitModeTruncate:
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unknown position limit mode: %s", config.Mode)
+	}
+
+	b.positionLimit = &config
+
+	return nil
+}
+
+// limitPositionSize applies the symbol's position limit to an entry order. It
+// stores a failed order and reports true when the order would take the
+// position over the limit, or under truncate mode reduces the order to the
+// quantity left under the limit and reports false. The position counts the
+// unfilled quantity of the symbol's open entry orders on the same side.
+func (b *BacktestTrading) limitPositionSize(order *types.ExecuteOrder) (bool, error) {
+	if b.positionLimit == nil || !isEntryOrder(*order) {
+		return false, nil
+	}
+
+	limit := b.positionLimit.limitFor(order.Symbol)
+
+	exposure, err := b.positionExposure(*order)
+	if err != nil {
+		return true, err
+	}
+
+	available := math.Inf(1)
+	if limit.MaxPositionQuantity > 0 {
+		available = limit.MaxPositionQuantity - exposure
+	}
+
+	price := b.positionLimitPrice(*order)
+	if limit.MaxPositionNotional > 0 && price > 0 {
+		available = math.Min(available, limit.MaxPositionNotional/price-exposure)
+	}
+
+	if order.Quantity <= available+lotTolerance {
+		return false, nil
+	}
+
+	if b.positionLimit.Mode == PositionLimitModeTruncate {
+		if truncated := b.floorQuantity(order.Symbol, available); truncated > 0 {
+			order.Quantity = truncated
+
+			return false, nil
+		}
+	}
+
+	failedOrder := b.createFailedOrder(*order, order.Price, types.OrderReasonPositionLimit,
+		fmt.Sprintf("order quantity %.2f would take the %s position of %.2f beyond the position limit of %s",
+			order.Quantity, order.PositionType, exposure, describePositionLimit(limit)))
+
+	return true, b.state.StoreFailedOrder(failedOrder)
+}
+
+// positionExposure returns the symbol's position on the order's side plus the
+// unfilled quantity of its open and deferred entry orders on that side.
+func (b *BacktestTrading) positionExposure(order types.ExecuteOrder) (float64, error) {
+	position, err := b.state.GetPosition(order.Symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	exposure := position.TotalLongPositionQuantity
+	if order.PositionType == types.PositionTypeShort {
+		exposure = position.TotalShortPositionQuantity
+	}
+
+	for _, orders := range [][]types.ExecuteOrder{b.pendingOrders, b.deferredEntries} {
+		for _, pending := range orders {
+			if pending.Symbol == order.Symbol && pending.PositionType == order.PositionType && isEntryOrder(pending) {
+				exposure += b.unfilledQuantity(pending)
+			}
+		}
+	}
+
+	return exposure, nil
+}
+
+// positionLimitPrice returns the price the notional limit values the order at:
+// its limit price, its stop price, or the current close of its symbol.
+func (b *BacktestTrading) positionLimitPrice(order types.ExecuteOrder) float64 {
+	switch {
+	case order.Price > 0:
+		return order.Price
+	case order.StopPrice > 0:
+		return order.StopPrice
+	case order.Symbol == b.marketData.Symbol:
+		return b.marketData.Close
+	default:
+		return 0
+	}
+}
+
+// describePositionLimit formats the limits that are set for a failed order message.
+func describePositionLimit(limit PositionLimit) string {
+	switch {
+	case limit.MaxPositionQuantity > 0 && limit.MaxPositionNotional > 0:
+		return fmt.Sprintf("%.2f quantity and %.2f notional", limit.MaxPositionQuantity, limit.MaxPositionNotional)
+	case limit.MaxPositionQuantity > 0:
+		return fmt.Sprintf("%.2f quantity", limit.MaxPositionQuantity)
+	default:
+		return fmt.Sprintf("%.2f notional", limit.MaxPositionNotional)
+	}
+}
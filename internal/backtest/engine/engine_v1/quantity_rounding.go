@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rxtech-lab/argo-trading/internal/utils"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// RoundingModeType selects how order quantities are snapped to the lot grid.
+type RoundingModeType string
+
+const (
+	// RoundingFloor rounds quantities down to the lot grid.
+	RoundingFloor RoundingModeType = "floor"
+	// RoundingNearest rounds quantities to the nearest lot, halves up.
+	RoundingNearest RoundingModeType = "nearest"
+	// RoundingReject rejects orders whose quantity is not a whole number of lots.
+	RoundingReject RoundingModeType = "reject"
+)
+
+// AllRoundingModes is the list of supported rounding modes (used by schema generation).
+var AllRoundingModes = []any{
+	string(RoundingFloor),
+	string(RoundingNearest),
+	string(RoundingReject),
+}
+
+// lotTolerance is the fraction of a lot by which a quantity may miss the lot
+// grid through floating point error and still be treated as on it.
+const lotTolerance = 1e-9
+
+// SetQuantityRounding configures how order quantities are snapped to the lot
+// grid and the lot sizes of individual symbols. Symbols without a lot size
+// trade in lots of one unit of their last decimal place. An empty mode selects
+// RoundingFloor.
+func (b *BacktestTrading) SetQuantityRounding(mode RoundingModeType, lotSizes map[string]float64) error {
+	switch mode {
+	case "", RoundingFloor, RoundingNearest, RoundingReject:
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unknown rounding mode %q", mode)
+	}
+
+	for symbol, lotSize := range lotSizes {
+		if lotSize <= 0 {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "lot size of %s must be greater than zero: %v", symbol, lotSize)
+		}
+	}
+
+	b.roundingMode = mode
+	b.lotSizes = lotSizes
+
+	return nil
+}
+
+// roundOrderQuantity snaps the quantity of a new order on symbol to the lot
+// grid under the rounding mode, or fails when the mode is reject and the
+// quantity is not a whole number of lots.
+func (b *BacktestTrading) roundOrderQuantity(symbol string, quantity float64) (float64, error) {
+	switch b.roundingMode {
+	case RoundingNearest:
+		lotSize := b.lotSize(symbol)
+
+		return snapToLot(math.Floor(quantity/lotSize+0.5+lotTolerance), lotSize), nil
+	case RoundingReject:
+		lotSize := b.lotSize(symbol)
+
+		lots := math.Round(quantity / lotSize)
+		if math.Abs(quantity/lotSize-lots) > lotTolerance {
+			return 0, errors.Newf(errors.ErrCodeInvalidParameter,
+				"order quantity %v of %s is not a multiple of the lot size %v", quantity, symbol, lotSize)
+		}
+
+		return snapToLot(lots, lotSize), nil
+	default:
+		return b.floorQuantity(symbol, quantity), nil
+	}
+}
+
+// floorQuantity rounds quantity down to the lot grid of symbol whatever the
+// rounding mode. It is used for maximum quantities, which rounding up would
+// exceed, and for quantities the engine derives from orders already placed.
+func (b *BacktestTrading) floorQuantity(symbol string, quantity float64) float64 {
+	lotSize, ok := b.lotSizes[symbol]
+	if !ok {
+		return utils.RoundToDecimalPrecision(quantity, b.precision(symbol))
+	}
+
+	return snapToLot(math.Floor(quantity/lotSize+lotTolerance), lotSize)
+}
+
+// lotSize returns the configured lot size of symbol, or one unit of the last
+// decimal place of its precision.
+func (b *BacktestTrading) lotSize(symbol string) float64 {
+	if lotSize, ok := b.lotSizes[symbol]; ok {
+		return lotSize
+	}
+
+	return math.Pow10(-b.precision(symbol))
+}
+
+// snapToLot returns lots times lotSize rounded to the decimal places of
+// lotSize, so that 19 lots of 0.1 are 1.9 rather than 1.9000000000000001.
+func snapToLot(lots, lotSize float64) float64 {
+	decimals := 0
+
+	formatted := strconv.FormatFloat(lotSize, 'f', -1, 64)
+	if point := strings.IndexByte(formatted, '.'); point >= 0 {
+		decimals = len(formatted) - point - 1
+	}
+
+	multiplier := math.Pow10(decimals)
+
+	return math.Round(lots*lotSize*multiplier) / multiplier
+}
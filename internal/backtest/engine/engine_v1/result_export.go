@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	_ "github.com/marcboeker/go-duckdb"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// Table names written to a results export database.
+const (
+	ExportTableOrders      = "orders"
+	ExportTableTrades      = "trades"
+	ExportTableMarks       = "marks"
+	ExportTableEquityCurve = "equity_curve"
+)
+
+// exportTables lists the result tables appended to the export database.
+var exportTables = []string{ExportTableOrders, ExportTableTrades, ExportTableMarks, ExportTableEquityCurve}
+
+// errSQLiteExtensionUnavailable is returned when exporting to SQLite without
+// DuckDB's sqlite extension. The extension is downloaded on first use, so an
+// offline machine can only export to SQLite once it has been installed.
+var errSQLiteExtensionUnavailable = errors.New(errors.ErrCodeDataSourceUnavailable, "DuckDB sqlite extension is not installed and could not be downloaded, export to a .duckdb file instead")
+
+// isSQLiteExportPath reports whether the export path should be attached as a
+// SQLite database rather than a DuckDB database, based on its extension.
+func isSQLiteExportPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sqlite", ".sqlite3":
+		return true
+	default:
+		return false
+	}
+}
+
+// quoteSQLString escapes a value for use inside a single-quoted SQL literal.
+func quoteSQLString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// ExportResultsToDatabase appends the orders, trades, marks and equity curve of
+// a single run to the DuckDB or SQLite database at exportPath. The tables are
+// the ones BacktestEngineV1.ExportResults writes, loaded from the run's results
+// rather than the engine's working database (which is often :memory:). Every
+// row is tagged with runID so multiple runs can share one export file.
+func ExportResultsToDatabase(exportPath string, runID string, results *runResults) error {
+	if exportPath == "" {
+		return errors.New(errors.ErrCodeInvalidParameter, "export path is empty")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(exportPath), 0755); err != nil {
+		return errors.Wrap(errors.ErrCodeBacktestNoResultsDir, "failed to create export directory", err)
+	}
+
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeQueryFailed, "failed to open export connection", err)
+	}
+	defer db.Close()
+
+	attachQuery := fmt.Sprintf(`ATTACH %s AS export_db`, quoteSQLString(exportPath))
+	if isSQLiteExportPath(exportPath) {
+		if err := loadSQLiteExtension(db); err != nil {
+			return err
+		}
+
+		attachQuery = fmt.Sprintf(`ATTACH %s AS export_db (TYPE SQLITE)`, quoteSQLString(exportPath))
+	}
+
+	if _, err := db.Exec(attachQuery); err != nil {
+		return errors.Wrap(errors.ErrCodeQueryFailed, "failed to attach export database", err)
+	}
+
+	defer func() {
+		_, _ = db.Exec(`DETACH export_db`)
+	}()
+
+	// Load the run's tables into the in-memory database the export is attached to
+	tables, err := results.load(db)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeQueryFailed, "failed to load backtest results", err)
+	}
+
+	runIDLiteral := quoteSQLString(runID)
+
+	for _, table := range tables {
+		if !slices.Contains(exportTables, table) {
+			continue
+		}
+
+		selectQuery := fmt.Sprintf(`SELECT %s AS run_id, * FROM %s`, runIDLiteral, table)
+		if err := appendExportTable(db, table, selectQuery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendExportTable creates the export table from the query's schema if it
+// does not exist yet and then appends the query's rows to it.
+func appendExportTable(db *sql.DB, table string, selectQuery string) error {
+	createQuery := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS export_db.%s AS SELECT * FROM (%s) LIMIT 0`, table, selectQuery)
+	if _, err := db.Exec(createQuery); err != nil {
+		return errors.Wrapf(errors.ErrCodeQueryFailed, err, "failed to create export table %s", table)
+	}
+
+	insertQuery := fmt.Sprintf(`INSERT INTO export_db.%s %s`, table, selectQuery)
+	if _, err := db.Exec(insertQuery); err != nil {
+		return errors.Wrapf(errors.ErrCodeQueryFailed, err, "failed to export %s", table)
+	}
+
+	return nil
+}
+
+// loadSQLiteExtension loads DuckDB's sqlite extension, installing it when it
+// is not installed yet. Installing needs network access.
+func loadSQLiteExtension(db *sql.DB) error {
+	if _, err := db.Exec(`LOAD sqlite`); err == nil {
+		return nil
+	}
+
+	if _, err := db.Exec(`INSTALL sqlite; LOAD sqlite;`); err != nil {
+		return errors.Wrapf(errors.ErrCodeDataSourceUnavailable, errSQLiteExtensionUnavailable, "failed to install the DuckDB sqlite extension: %v", err)
+	}
+
+	return nil
+}
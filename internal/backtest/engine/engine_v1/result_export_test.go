@@ -0,0 +1,216 @@
+package engine
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// ResultExportTestSuite is a test suite for ExportResultsToDatabase
+type ResultExportTestSuite struct {
+	suite.Suite
+	state  *BacktestState
+	marker *BacktestMarker
+	logger *logger.Logger
+}
+
+func TestResultExportSuite(t *testing.T) {
+	suite.Run(t, new(ResultExportTestSuite))
+}
+
+func (suite *ResultExportTestSuite) SetupTest() {
+	logger, err := logger.NewLogger()
+	suite.Require().NoError(err)
+	suite.logger = logger
+
+	suite.state, err = NewBacktestState(suite.logger)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.state.Initialize())
+	suite.state.SetInitialBalance(10000)
+
+	suite.marker, err = NewBacktestMarker(suite.logger)
+	suite.Require().NoError(err)
+}
+
+func (suite *ResultExportTestSuite) TearDownTest() {
+	suite.state.db.Close()
+	suite.marker.Close()
+}
+
+// writeRun populates the state and marker with a buy, a sell, a failed order,
+// a mark and three bars of the equity curve, and writes the artifacts to a
+// result folder like writeResults does.
+func (suite *ResultExportTestSuite) writeRun() string {
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	orders := []types.Order{
+		{
+			Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 10, Price: 100, Timestamp: baseTime,
+			IsCompleted: true, Status: types.OrderStatusFilled, Reason: types.Reason{Reason: "strategy", Message: "buy"},
+			StrategyName: "test", PositionType: types.PositionTypeLong,
+		},
+		{
+			Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 4, Price: 110, Timestamp: baseTime.Add(time.Hour),
+			IsCompleted: true, Status: types.OrderStatusFilled, Reason: types.Reason{Reason: "strategy", Message: "sell"},
+			StrategyName: "test", PositionType: types.PositionTypeLong,
+		},
+	}
+	_, err := suite.state.Update(orders)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.state.StoreFailedOrder(types.Order{
+		Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 1000, Price: 100, Timestamp: baseTime,
+		IsCompleted: true, Status: types.OrderStatusFailed,
+		Reason:       types.Reason{Reason: types.OrderReasonInsufficientBuyPower, Message: "too large"},
+		StrategyName: "test", PositionType: types.PositionTypeLong,
+	}))
+
+	suite.Require().NoError(suite.marker.Mark(types.MarketData{Symbol: "AAPL", Time: baseTime}, types.Mark{
+		MarketDataId: "md1",
+		Color:        types.MarkColorGreen,
+		Shape:        types.MarkShapeCircle,
+		Level:        types.MarkLevelInfo,
+		Title:        "Buy",
+		Message:      "buy",
+		Category:     "trade",
+		Signal:       optional.None[types.Signal](),
+	}))
+
+	// After the partial sell: 9440 cash plus 6 shares, up to 110 each on the last row
+	for i, price := range []float64{100, 105, 110} {
+		_, err := suite.state.RecordEquityCurve(baseTime.Add(time.Duration(i)*time.Hour), func(string) float64 {
+			return price
+		}, 0)
+		suite.Require().NoError(err)
+	}
+
+	resultFolder := suite.T().TempDir()
+	suite.Require().NoError(suite.state.Write(filepath.Join(resultFolder, "state.db")))
+	suite.Require().NoError(suite.marker.Write(resultFolder))
+
+	return resultFolder
+}
+
+// runResults captures the results of the run written to resultFolder.
+func (suite *ResultExportTestSuite) runResults(resultFolder string) *runResults {
+	results, err := newRunResults(suite.state, nil, resultFolder)
+	suite.Require().NoError(err)
+
+	return results
+}
+
+func (suite *ResultExportTestSuite) countRows(db *sql.DB, table string, runID string) int {
+	var count int
+
+	err := db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE run_id = ?`, table), runID).Scan(&count)
+	suite.Require().NoError(err)
+
+	return count
+}
+
+func (suite *ResultExportTestSuite) TestExportToDuckDB() {
+	resultFolder := suite.writeRun()
+	exportPath := filepath.Join(suite.T().TempDir(), "results.duckdb")
+
+	err := ExportResultsToDatabase(exportPath, "run-1", suite.runResults(resultFolder))
+	suite.Require().NoError(err)
+
+	db, err := sql.Open("duckdb", exportPath)
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	suite.Equal(3, suite.countRows(db, ExportTableOrders, "run-1"))
+	suite.Equal(2, suite.countRows(db, ExportTableTrades, "run-1"))
+	suite.Equal(1, suite.countRows(db, ExportTableMarks, "run-1"))
+	suite.Equal(3, suite.countRows(db, ExportTableEquityCurve, "run-1"))
+
+	var equity float64
+	err = db.QueryRow(`SELECT equity FROM equity_curve ORDER BY time DESC LIMIT 1`).Scan(&equity)
+	suite.Require().NoError(err)
+	suite.InDelta(10100.0, equity, 0.0001)
+}
+
+func (suite *ResultExportTestSuite) TestExportAppendsMultipleRuns() {
+	resultFolder := suite.writeRun()
+	exportPath := filepath.Join(suite.T().TempDir(), "results.duckdb")
+
+	suite.Require().NoError(ExportResultsToDatabase(exportPath, "run-1", suite.runResults(resultFolder)))
+	suite.Require().NoError(ExportResultsToDatabase(exportPath, "run-2", suite.runResults(resultFolder)))
+
+	db, err := sql.Open("duckdb", exportPath)
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	for _, runID := range []string{"run-1", "run-2"} {
+		suite.Equal(3, suite.countRows(db, ExportTableOrders, runID))
+		suite.Equal(2, suite.countRows(db, ExportTableTrades, runID))
+		suite.Equal(1, suite.countRows(db, ExportTableMarks, runID))
+		suite.Equal(3, suite.countRows(db, ExportTableEquityCurve, runID))
+	}
+}
+
+func (suite *ResultExportTestSuite) TestExportSkipsMissingMarks() {
+	resultFolder := suite.T().TempDir()
+	suite.Require().NoError(suite.state.Write(filepath.Join(resultFolder, "state.db")))
+
+	exportPath := filepath.Join(suite.T().TempDir(), "results.duckdb")
+	suite.Require().NoError(ExportResultsToDatabase(exportPath, "run-1", suite.runResults(resultFolder)))
+
+	db, err := sql.Open("duckdb", exportPath)
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	suite.Equal(0, suite.countRows(db, ExportTableTrades, "run-1"))
+	// The equity curve table is exported even when the run recorded no bars
+	suite.Equal(0, suite.countRows(db, ExportTableEquityCurve, "run-1"))
+
+	var tableCount int
+	err = db.QueryRow(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'marks'`).Scan(&tableCount)
+	suite.Require().NoError(err)
+	suite.Equal(0, tableCount)
+}
+
+func (suite *ResultExportTestSuite) TestExportToSQLite() {
+	resultFolder := suite.writeRun()
+	exportPath := filepath.Join(suite.T().TempDir(), "results.sqlite")
+
+	err := ExportResultsToDatabase(exportPath, "run-1", suite.runResults(resultFolder))
+	if errors.Is(err, errSQLiteExtensionUnavailable) {
+		// The extension is downloaded on first use, which needs network access
+		suite.T().Skip("DuckDB sqlite extension is not available offline")
+	}
+
+	suite.Require().NoError(err)
+
+	db, err := sql.Open("duckdb", "")
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	suite.Require().NoError(loadSQLiteExtension(db))
+	_, err = db.Exec(fmt.Sprintf(`ATTACH %s AS export_db (TYPE SQLITE)`, quoteSQLString(exportPath)))
+	suite.Require().NoError(err)
+
+	suite.Equal(3, suite.countRows(db, "export_db."+ExportTableOrders, "run-1"))
+	suite.Equal(2, suite.countRows(db, "export_db."+ExportTableTrades, "run-1"))
+	suite.Equal(3, suite.countRows(db, "export_db."+ExportTableEquityCurve, "run-1"))
+}
+
+func (suite *ResultExportTestSuite) TestExportEmptyPath() {
+	err := ExportResultsToDatabase("", "run-1", suite.runResults(suite.T().TempDir()))
+	suite.Error(err)
+}
+
+func (suite *ResultExportTestSuite) TestIsSQLiteExportPath() {
+	suite.True(isSQLiteExportPath("results.sqlite"))
+	suite.True(isSQLiteExportPath("results.SQLITE3"))
+	suite.False(isSQLiteExportPath("results.duckdb"))
+	suite.False(isSQLiteExportPath("results.db"))
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+// ResultOutputFormat is a file format the backtest results (trades, orders,
+// marks and logs) are written in.
+type ResultOutputFormat string
+
+const (
+	// ResultOutputFormatParquet writes results as Parquet files. Parquet is
+	// always written because the stats file and the results export read it.
+	ResultOutputFormatParquet ResultOutputFormat = "parquet"
+	// ResultOutputFormatCSV writes results as CSV files with a header row.
+	ResultOutputFormatCSV ResultOutputFormat = "csv"
+	// ResultOutputFormatJSON writes results as JSON files containing an array of records.
+	ResultOutputFormatJSON ResultOutputFormat = "json"
+)
+
+// AllResultOutputFormats is the list of supported result output formats
+// (used by schema generation).
+var AllResultOutputFormats = []any{
+	string(ResultOutputFormatParquet),
+	string(ResultOutputFormatCSV),
+	string(ResultOutputFormatJSON),
+}
+
+// copyOptions returns the DuckDB COPY options for the format.
+func (f ResultOutputFormat) copyOptions() string {
+	switch f {
+	case ResultOutputFormatCSV:
+		return "FORMAT CSV, HEADER true"
+	case ResultOutputFormatJSON:
+		return "FORMAT JSON, ARRAY true"
+	default:
+		return "FORMAT PARQUET"
+	}
+}
+
+// ResolveResultOutputFormats validates the configured output formats and
+// returns them deduplicated with Parquet first. Parquet is always included.
+func ResolveResultOutputFormats(formats []ResultOutputFormat) ([]ResultOutputFormat, error) {
+	resolved := []ResultOutputFormat{ResultOutputFormatParquet}
+
+	for _, format := range formats {
+		switch format {
+		case ResultOutputFormatParquet, ResultOutputFormatCSV, ResultOutputFormatJSON:
+			if !slices.Contains(resolved, format) {
+				resolved = append(resolved, format)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported result output format: %q", format)
+		}
+	}
+
+	return resolved, nil
+}
+
+// copyTableToFormats writes the same table to <dir>/<table>.<format> for every
+// format, so all output files are produced from one record set. It returns the
+// written file paths in the order of formats.
+func copyTableToFormats(db *sql.DB, table string, dir string, formats []ResultOutputFormat) ([]string, error) {
+	paths := make([]string, 0, len(formats))
+
+	for _, format := range formats {
+		path := filepath.Join(dir, fmt.Sprintf("%s.%s", table, format))
+
+		_, err := db.Exec(fmt.Sprintf(`COPY %s TO '%s' (%s)`, table, path, format.copyOptions()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s to %s: %w", table, format, err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// ResultFormatTestSuite is a test suite for writing results in multiple formats
+type ResultFormatTestSuite struct {
+	suite.Suite
+	state  *BacktestState
+	marker *BacktestMarker
+	logger *logger.Logger
+}
+
+func TestResultFormatSuite(t *testing.T) {
+	suite.Run(t, new(ResultFormatTestSuite))
+}
+
+func (suite *ResultFormatTestSuite) SetupTest() {
+	logger, err := logger.NewLogger()
+	suite.Require().NoError(err)
+	suite.logger = logger
+
+	suite.state, err = NewBacktestState(suite.logger)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.state.Initialize())
+
+	suite.marker, err = NewBacktestMarker(suite.logger)
+	suite.Require().NoError(err)
+}
+
+func (suite *ResultFormatTestSuite) TearDownTest() {
+	suite.state.db.Close()
+	suite.marker.Close()
+}
+
+func (suite *ResultFormatTestSuite) TestResolveResultOutputFormats() {
+	tests := []struct {
+		name        string
+		formats     []ResultOutputFormat
+		expected    []ResultOutputFormat
+		expectError bool
+	}{
+		{
+			name:     "Empty defaults to parquet",
+			formats:  nil,
+			expected: []ResultOutputFormat{ResultOutputFormatParquet},
+		},
+		{
+			name:     "Parquet is always first",
+			formats:  []ResultOutputFormat{ResultOutputFormatJSON, ResultOutputFormatCSV},
+			expected: []ResultOutputFormat{ResultOutputFormatParquet, ResultOutputFormatJSON, ResultOutputFormatCSV},
+		},
+		{
+			name:     "Duplicates are removed",
+			formats:  []ResultOutputFormat{ResultOutputFormatCSV, ResultOutputFormatParquet, ResultOutputFormatCSV},
+			expected: []ResultOutputFormat{ResultOutputFormatParquet, ResultOutputFormatCSV},
+		},
+		{
+			name:        "Unknown format",
+			formats:     []ResultOutputFormat{"xml"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			formats, err := ResolveResultOutputFormats(tc.formats)
+			if tc.expectError {
+				suite.Error(err)
+
+				return
+			}
+
+			suite.Require().NoError(err)
+			suite.Equal(tc.expected, formats)
+		})
+	}
+}
+
+func (suite *ResultFormatTestSuite) TestWriteAllFormatsConsistent() {
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	_, err := suite.state.Update([]types.Order{
+		{
+			Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 10, Price: 100, Timestamp: baseTime,
+			IsCompleted: true, Status: types.OrderStatusFilled, Reason: types.Reason{Reason: "strategy", Message: "buy"},
+			StrategyName: "test", PositionType: types.PositionTypeLong,
+		},
+		{
+			Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 4, Price: 110, Timestamp: baseTime.Add(time.Hour),
+			IsCompleted: true, Status: types.OrderStatusFilled, Reason: types.Reason{Reason: "strategy", Message: "sell"},
+			StrategyName: "test", PositionType: types.PositionTypeLong,
+		},
+	})
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.marker.Mark(types.MarketData{Symbol: "AAPL", Time: baseTime}, types.Mark{
+		MarketDataId: "md1",
+		Color:        types.MarkColorGreen,
+		Shape:        types.MarkShapeCircle,
+		Level:        types.MarkLevelInfo,
+		Title:        "Buy",
+		Message:      "buy",
+		Category:     "trade",
+		Signal:       optional.None[types.Signal](),
+	}))
+
+	formats := []ResultOutputFormat{ResultOutputFormatParquet, ResultOutputFormatCSV, ResultOutputFormatJSON}
+	resultFolder := suite.T().TempDir()
+	stateDBPath := filepath.Join(resultFolder, "state.db")
+	suite.Require().NoError(suite.state.WriteFormats(stateDBPath, formats))
+	suite.Require().NoError(suite.marker.WriteFormats(resultFolder, formats))
+
+	db, err := sql.Open("duckdb", "")
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	readers := map[ResultOutputFormat]string{
+		ResultOutputFormatParquet: "read_parquet",
+		ResultOutputFormatCSV:     "read_csv_auto",
+		ResultOutputFormatJSON:    "read_json_auto",
+	}
+
+	summarize := func(path string, format ResultOutputFormat, column string) (int, float64) {
+		var count int
+
+		var total float64
+
+		query := fmt.Sprintf(`SELECT COUNT(*), COALESCE(SUM(%s), 0) FROM %s('%s')`, column, readers[format], path)
+		suite.Require().NoError(db.QueryRow(query).Scan(&count, &total))
+
+		return count, total
+	}
+
+	for _, format := range formats {
+		suite.Run(string(format), func() {
+			tradesPath := filepath.Join(stateDBPath, fmt.Sprintf("trades.%s", format))
+			suite.FileExists(tradesPath)
+			count, quantity := summarize(tradesPath, format, "quantity")
+			suite.Equal(2, count)
+			suite.InDelta(14.0, quantity, 0.0001)
+
+			ordersPath := filepath.Join(stateDBPath, fmt.Sprintf("orders.%s", format))
+			suite.FileExists(ordersPath)
+			count, price := summarize(ordersPath, format, "price")
+			suite.Equal(2, count)
+			suite.InDelta(210.0, price, 0.0001)
+
+			marksPath := filepath.Join(resultFolder, fmt.Sprintf("marks.%s", format))
+			suite.FileExists(marksPath)
+			count, _ = summarize(marksPath, format, "1")
+			suite.Equal(1, count)
+		})
+	}
+}
+
+func (suite *ResultFormatTestSuite) TestWriteDefaultsToParquetOnly() {
+	resultFolder := suite.T().TempDir()
+	suite.Require().NoError(suite.state.Write(resultFolder))
+
+	suite.FileExists(filepath.Join(resultFolder, "trades.parquet"))
+	suite.FileExists(filepath.Join(resultFolder, "orders.parquet"))
+	suite.NoFileExists(filepath.Join(resultFolder, "trades.csv"))
+	suite.NoFileExists(filepath.Join(resultFolder, "trades.json"))
+	// Runs outside signal-only mode record no signals
+	suite.NoFileExists(filepath.Join(resultFolder, "signals.parquet"))
+}
@@ -0,0 +1,335 @@
+package engine
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// Tables written by BacktestEngineV1.ExportResults besides the ExportTable* tables.
+const (
+	resultsTableSummary   = "summary"
+	resultsTableBenchmark = "benchmark"
+)
+
+// runResults holds what a run produced. The working state is cleaned up after
+// every run, so the trades and orders are copied here from the state; the
+// equity curve and marks are read back from the run's result folder.
+type runResults struct {
+	resultFolderPath string
+	trades           []types.Trade
+	orders           []types.Order
+	stats            []types.TradeStats
+	// benchmark is nil when no benchmark symbol is configured.
+	benchmark *BenchmarkComparison
+}
+
+// newRunResults copies the trades and orders of the run out of the state.
+func newRunResults(state *BacktestState, stats []types.TradeStats, resultFolderPath string) (*runResults, error) {
+	trades, err := state.GetAllTrades()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeQueryFailed, "failed to get trades", err)
+	}
+
+	orders, err := state.GetAllOrders()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeQueryFailed, "failed to get orders", err)
+	}
+
+	return &runResults{
+		resultFolderPath: resultFolderPath,
+		trades:           trades,
+		orders:           orders,
+		stats:            stats,
+		benchmark:        nil,
+	}, nil
+}
+
+// ExportResults writes the results of the most recent run in the given format
+// (json, csv or parquet). path is a directory that receives one file per table:
+// summary, trades, orders, marks and equity_curve, plus benchmark when a
+// benchmark symbol is configured.
+//
+// Only the last run is kept: when the engine runs several data files or
+// strategy configs, this exports the last data file × strategy config run.
+// Every run's results are still written to its own result folder, and
+// results_export_path collects all of them into one database.
+func (b *BacktestEngineV1) ExportResults(format string, path string) error {
+	if b.lastRun == nil {
+		return errors.New(errors.ErrCodeDataNotFound, "no backtest results to export, run the backtest first")
+	}
+
+	outputFormat := ResultOutputFormat(format)
+	if _, err := ResolveResultOutputFormats([]ResultOutputFormat{outputFormat}); err != nil {
+		return errors.Wrapf(errors.ErrCodeInvalidParameter, err, "unsupported export format %q, expected json, csv or parquet", format)
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return errors.Wrap(errors.ErrCodeBacktestNoResultsDir, "failed to create export directory", err)
+	}
+
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeQueryFailed, "failed to open export connection", err)
+	}
+	defer db.Close()
+
+	tables, err := b.lastRun.load(db)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeQueryFailed, "failed to load backtest results", err)
+	}
+
+	for _, table := range tables {
+		if _, err := copyTableToFormats(db, table, path, []ResultOutputFormat{outputFormat}); err != nil {
+			return errors.Wrap(errors.ErrCodeBacktestNoResultsDir, "failed to write results", err)
+		}
+	}
+
+	return nil
+}
+
+// captureRunResults keeps the results of the run for ExportResults and
+// compares it against the benchmark.
+func (b *BacktestEngineV1) captureRunResults(stats []types.TradeStats, resultFolderPath string) error {
+	results, err := newRunResults(b.state, stats, resultFolderPath)
+	if err != nil {
+		return err
+	}
+
+	results.benchmark, err = b.compareWithBenchmark(stats)
+	if err != nil {
+		return err
+	}
+
+	b.lastRun = results
+
+	return nil
+}
+
+// load creates the result tables in db and returns their names. Both
+// ExportResults and ExportResultsToDatabase write the tables created here.
+// The equity curve and marks are read from the parquet files of the run, so
+// load must be called after the run's results are written. The equity curve
+// is always loaded; marks are skipped when the run wrote none.
+func (r *runResults) load(db *sql.DB) ([]string, error) {
+	if err := r.loadSummary(db); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadTrades(db); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadOrders(db); err != nil {
+		return nil, err
+	}
+
+	tables := []string{resultsTableSummary, ExportTableTrades, ExportTableOrders}
+
+	parquetTables := []struct {
+		table    string
+		path     string
+		optional bool
+	}{
+		// Marks are only written when a marker is configured
+		{table: ExportTableMarks, path: filepath.Join(r.resultFolderPath, "marks.parquet"), optional: true},
+		// The equity curve is recorded on every run
+		{table: ExportTableEquityCurve, path: filepath.Join(r.resultFolderPath, "state.db", "equity_curve.parquet"), optional: false},
+	}
+
+	for _, source := range parquetTables {
+		if _, err := os.Stat(source.path); err != nil && source.optional {
+			continue
+		}
+
+		query := fmt.Sprintf(`CREATE TABLE %s AS SELECT * FROM read_parquet(%s)`, source.table, quoteSQLString(source.path))
+		if _, err := db.Exec(query); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", source.table, err)
+		}
+
+		tables = append(tables, source.table)
+	}
+
+	if r.benchmark != nil {
+		if err := r.loadBenchmark(db); err != nil {
+			return nil, err
+		}
+
+		tables = append(tables, resultsTableBenchmark)
+	}
+
+	return tables, nil
+}
+
+// insertRows inserts rows into table in one transaction.
+func insertRows(db *sql.DB, table string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin %s insert: %w", table, err)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(rows[0])), ", ")
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`INSERT INTO %s VALUES (%s)`, table, placeholders))
+	if err != nil {
+		tx.Rollback()
+
+		return fmt.Errorf("failed to prepare %s insert: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.Exec(row...); err != nil {
+			tx.Rollback()
+
+			return fmt.Errorf("failed to insert into %s: %w", table, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// loadTrades creates the trades table from the trades of the run.
+func (r *runResults) loadTrades(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE trades (
+			order_id TEXT,
+			symbol TEXT,
+			side TEXT,
+			position_type TEXT,
+			executed_at TIMESTAMP,
+			quantity DOUBLE,
+			price DOUBLE,
+			fee DOUBLE,
+			pnl DOUBLE,
+			open_position_qty DOUBLE,
+			balance DOUBLE,
+			reason TEXT
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create trades table: %w", err)
+	}
+
+	rows := make([][]any, 0, len(r.trades))
+	for _, trade := range r.trades {
+		rows = append(rows, []any{
+			trade.Order.OrderID, trade.Order.Symbol, string(trade.Order.Side), string(trade.Order.PositionType),
+			trade.ExecutedAt, trade.ExecutedQty, trade.ExecutedPrice, trade.Fee, trade.PnL,
+			trade.OpenPositionQty, trade.Balance, trade.Order.Reason.Reason,
+		})
+	}
+
+	return insertRows(db, ExportTableTrades, rows)
+}
+
+// loadOrders creates the orders table from the orders of the run, including
+// the failed ones.
+func (r *runResults) loadOrders(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE orders (
+			order_id TEXT,
+			symbol TEXT,
+			side TEXT,
+			position_type TEXT,
+			timestamp TIMESTAMP,
+			quantity DOUBLE,
+			price DOUBLE,
+			status TEXT,
+			reason TEXT,
+			message TEXT,
+			strategy_name TEXT
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create orders table: %w", err)
+	}
+
+	rows := make([][]any, 0, len(r.orders))
+	for _, order := range r.orders {
+		rows = append(rows, []any{
+			order.OrderID, order.Symbol, string(order.Side), string(order.PositionType),
+			order.Timestamp, order.Quantity, order.Price, string(order.Status),
+			order.Reason.Reason, order.Reason.Message, order.StrategyName,
+		})
+	}
+
+	return insertRows(db, ExportTableOrders, rows)
+}
+
+// loadSummary creates the summary table holding the headline stats of each symbol.
+func (r *runResults) loadSummary(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE summary (
+			symbol TEXT,
+			initial_balance DOUBLE,
+			final_balance DOUBLE,
+			total_pnl DOUBLE,
+			total_return DOUBLE,
+			number_of_trades INTEGER,
+			win_rate DOUBLE,
+			sharpe_ratio DOUBLE,
+			max_drawdown DOUBLE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create summary table: %w", err)
+	}
+
+	for _, stats := range r.stats {
+		// The total return is the PnL as a fraction of the initial balance, 0 without one
+		totalReturn := 0.0
+		if stats.InitialBalance != 0 {
+			totalReturn = stats.TradePnl.TotalPnL / stats.InitialBalance
+		}
+
+		_, err := db.Exec(`INSERT INTO summary VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			stats.Symbol, stats.InitialBalance, stats.FinalBalance, stats.TradePnl.TotalPnL, totalReturn,
+			stats.TradeResult.NumberOfTrades, stats.TradeResult.WinRate, stats.TradeResult.SharpeRatio, stats.TradeResult.MaxDrawdown)
+		if err != nil {
+			return fmt.Errorf("failed to insert summary: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// loadBenchmark creates the benchmark table holding the run's benchmark comparison.
+func (r *runResults) loadBenchmark(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE benchmark (
+			symbol TEXT,
+			start_time TIMESTAMP,
+			start_price DOUBLE,
+			end_time TIMESTAMP,
+			end_price DOUBLE,
+			benchmark_final_value DOUBLE,
+			strategy_return DOUBLE,
+			benchmark_return DOUBLE,
+			alpha DOUBLE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create benchmark table: %w", err)
+	}
+
+	benchmark := r.benchmark
+
+	_, err = db.Exec(`INSERT INTO benchmark VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		benchmark.Symbol, benchmark.StartTime, benchmark.StartPrice, benchmark.EndTime, benchmark.EndPrice,
+		benchmark.BenchmarkFinalValue, benchmark.StrategyReturn, benchmark.BenchmarkReturn, benchmark.Alpha)
+	if err != nil {
+		return fmt.Errorf("failed to insert benchmark: %w", err)
+	}
+
+	return nil
+}
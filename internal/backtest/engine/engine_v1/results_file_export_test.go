@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	engine_types "github.com/rxtech-lab/argo-trading/internal/backtest/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// runExportBacktest runs a three bar backtest in which the strategy buys 10 shares
+// on the first bar, places an order it cannot afford on the second and sells 4
+// shares on the third. Market orders fill at the middle of the bar's range.
+func runExportBacktest(t *testing.T) *BacktestEngineV1 {
+	t.Helper()
+
+	return runExportBacktestWithConfig(t, exportBacktestConfig)
+}
+
+// exportBacktestConfig is the engine config of runExportBacktest.
+const exportBacktestConfig = "initial_capital: 10000\nbroker: zero_commission\n"
+
+// runExportBacktestWithConfig runs the backtest of runExportBacktest with the given engine config.
+func runExportBacktestWithConfig(t *testing.T, config string) *BacktestEngineV1 {
+	t.Helper()
+	setTestVersion(t, "1.0.0")
+
+	ctrl := gomock.NewController(t)
+	mockStrategy := mocks.NewMockStrategyRuntime(ctrl)
+	mockDatasource := mocks.NewMockDataSource(ctrl)
+
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	bars := []types.MarketData{
+		{Id: "bar-0", Symbol: "TEST", Time: start, Open: 100, High: 101, Low: 99, Close: 100, Volume: 1000},
+		{Id: "bar-1", Symbol: "TEST", Time: start.Add(time.Minute), Open: 105, High: 106, Low: 104, Close: 105, Volume: 1000},
+		{Id: "bar-2", Symbol: "TEST", Time: start.Add(2 * time.Minute), Open: 110, High: 111, Low: 109, Close: 110, Volume: 1000},
+	}
+
+	engine, err := NewBacktestEngineV1()
+	require.NoError(t, err)
+	backtestEngine := engine.(*BacktestEngineV1)
+
+	order := func(side types.PurchaseType, quantity float64) types.ExecuteOrder {
+		return types.ExecuteOrder{
+			Symbol:       "TEST",
+			Side:         side,
+			OrderType:    types.OrderTypeMarket,
+			Reason:       types.Reason{Reason: types.OrderReasonStrategy, Message: "signal"},
+			Price:        100,
+			StrategyName: "TestStrategy",
+			Quantity:     quantity,
+			PositionType: types.PositionTypeLong,
+		}
+	}
+
+	mockStrategy.EXPECT().Name().Return("TestStrategy").AnyTimes()
+	mockStrategy.EXPECT().InitializeApi(gomock.Any()).Return(nil).AnyTimes()
+	mockStrategy.EXPECT().Initialize(gomock.Any()).Return(nil).AnyTimes()
+	mockStrategy.EXPECT().GetRuntimeEngineVersion().Return("1.0.0", nil).AnyTimes()
+	mockStrategy.EXPECT().GetIdentifier().Return("com.test.mock", nil).AnyTimes()
+	mockStrategy.EXPECT().ProcessData(gomock.Any()).DoAndReturn(func(data types.MarketData) error {
+		switch data.Id {
+		case "bar-0":
+			return backtestEngine.tradingSystem.PlaceOrder(order(types.PurchaseTypeBuy, 10))
+		case "bar-1":
+			return backtestEngine.tradingSystem.PlaceOrder(order(types.PurchaseTypeBuy, 1000))
+		default:
+			return backtestEngine.tradingSystem.PlaceOrder(order(types.PurchaseTypeSell, 4))
+		}
+	}).Times(len(bars))
+
+	mockDatasource.EXPECT().Initialize(gomock.Any()).Return(nil).AnyTimes()
+	mockDatasource.EXPECT().Count(gomock.Any(), gomock.Any()).Return(len(bars), nil).AnyTimes()
+	mockDatasource.EXPECT().GetAllSymbols().Return([]string{"TEST"}, nil).AnyTimes()
+	mockDatasource.EXPECT().ReadLastData(gomock.Any()).Return(bars[2], nil).AnyTimes()
+	mockDatasource.EXPECT().ReadAll(gomock.Any(), gomock.Any()).Return(func(yield func(types.MarketData, error) bool) {
+		for _, bar := range bars {
+			if !yield(bar, nil) {
+				return
+			}
+		}
+	}).AnyTimes()
+
+	require.NoError(t, backtestEngine.Initialize(config))
+	require.NoError(t, backtestEngine.LoadStrategy(mockStrategy))
+	require.NoError(t, backtestEngine.SetDataSource(mockDatasource))
+	require.NoError(t, backtestEngine.SetConfigContent([]string{""}))
+
+	tempDir := t.TempDir()
+	backtestEngine.dataPaths = []string{filepath.Join(tempDir, "data_path")}
+	require.NoError(t, backtestEngine.SetResultsFolder(tempDir))
+
+	require.NoError(t, backtestEngine.Run(context.Background(), engine_types.LifecycleCallbacks{}))
+
+	return backtestEngine
+}
+
+// readCSV reads a CSV file into its header and records keyed by column name.
+func readCSV(t *testing.T, path string) []map[string]string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, rows)
+
+	records := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		record := map[string]string{}
+		for i, column := range rows[0] {
+			record[column] = row[i]
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// readJSON reads a JSON export file holding an array of records.
+func readJSON(t *testing.T, path string) []map[string]any {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var records []map[string]any
+	require.NoError(t, json.Unmarshal(data, &records))
+
+	return records
+}
+
+func TestBacktestEngineV1_ExportResults(t *testing.T) {
+	t.Run("JSON export holds all results of the last run", func(t *testing.T) {
+		backtestEngine := runExportBacktest(t)
+
+		dir := filepath.Join(t.TempDir(), "results")
+		require.NoError(t, backtestEngine.ExportResults("json", dir))
+
+		trades := readJSON(t, filepath.Join(dir, "trades.json"))
+		require.Len(t, trades, 2)
+		assert.Equal(t, "BUY", trades[0]["side"])
+		assert.Equal(t, 10.0, trades[0]["quantity"])
+		assert.Equal(t, 100.0, trades[0]["price"])
+		assert.Equal(t, "SELL", trades[1]["side"])
+		assert.Equal(t, 4.0, trades[1]["quantity"])
+		assert.Equal(t, 110.0, trades[1]["price"])
+		assert.InDelta(t, 40.0, trades[1]["pnl"], 1e-9)
+
+		orders := readJSON(t, filepath.Join(dir, "orders.json"))
+		require.Len(t, orders, 3)
+
+		var failed []map[string]any
+		for _, order := range orders {
+			if order["status"] == string(types.OrderStatusFailed) {
+				failed = append(failed, order)
+			}
+		}
+
+		require.Len(t, failed, 1)
+		assert.Equal(t, types.OrderReasonInsufficientBuyPower, failed[0]["reason"])
+
+		// One point per bar; after the sell: 40 realized plus 6 shares up 10 each
+		equity := readJSON(t, filepath.Join(dir, "equity_curve.json"))
+		require.Len(t, equity, 3)
+		assert.InDelta(t, 10000.0, equity[0]["equity"], 1e-9)
+		assert.InDelta(t, 10050.0, equity[1]["equity"], 1e-9)
+		assert.InDelta(t, 10100.0, equity[2]["equity"], 1e-9)
+
+		summary := readJSON(t, filepath.Join(dir, "summary.json"))
+		require.Len(t, summary, 1)
+		assert.Equal(t, "TEST", summary[0]["symbol"])
+		assert.Equal(t, 2.0, summary[0]["number_of_trades"])
+		assert.Equal(t, 1.0, summary[0]["win_rate"])
+		assert.InDelta(t, 100.0, summary[0]["total_pnl"], 1e-9)
+		assert.InDelta(t, 0.01, summary[0]["total_return"], 1e-9)
+
+		assert.NoFileExists(t, filepath.Join(dir, "benchmark.json"))
+	})
+
+	t.Run("CSV export writes one file per table", func(t *testing.T) {
+		backtestEngine := runExportBacktest(t)
+
+		dir := filepath.Join(t.TempDir(), "csv")
+		require.NoError(t, backtestEngine.ExportResults("csv", dir))
+
+		trades := readCSV(t, filepath.Join(dir, "trades.csv"))
+		require.Len(t, trades, 2)
+		assert.Equal(t, "BUY", trades[0]["side"])
+		assert.Equal(t, "10.0", trades[0]["quantity"])
+		assert.Equal(t, "SELL", trades[1]["side"])
+		assert.Equal(t, "110.0", trades[1]["price"])
+		assert.Equal(t, "2024-01-02 09:32:00", trades[1]["executed_at"])
+
+		assert.Len(t, readCSV(t, filepath.Join(dir, "orders.csv")), 3)
+
+		equity := readCSV(t, filepath.Join(dir, "equity_curve.csv"))
+		require.Len(t, equity, 3)
+		assert.Equal(t, "10100.0", equity[2]["equity"])
+
+		summary := readCSV(t, filepath.Join(dir, "summary.csv"))
+		require.Len(t, summary, 1)
+		assert.Equal(t, "TEST", summary[0]["symbol"])
+		assert.Equal(t, "1.0", summary[0]["win_rate"])
+		assert.Equal(t, "0.01", summary[0]["total_return"])
+		assert.Contains(t, summary[0], "sharpe_ratio")
+		assert.Contains(t, summary[0], "max_drawdown")
+	})
+
+	t.Run("Benchmark comparison is exported when a benchmark symbol is configured", func(t *testing.T) {
+		backtestEngine := runExportBacktestWithConfig(t, exportBacktestConfig+"benchmark_symbol: TEST\n")
+
+		dir := filepath.Join(t.TempDir(), "results")
+		require.NoError(t, backtestEngine.ExportResults("json", dir))
+
+		// 10000 invested at the first close of 100 is worth 11000 at the last close of 110
+		benchmark := readJSON(t, filepath.Join(dir, "benchmark.json"))
+		require.Len(t, benchmark, 1)
+		assert.Equal(t, "TEST", benchmark[0]["symbol"])
+		assert.Equal(t, 100.0, benchmark[0]["start_price"])
+		assert.Equal(t, 110.0, benchmark[0]["end_price"])
+		assert.InDelta(t, 11000.0, benchmark[0]["benchmark_final_value"], 1e-9)
+		assert.InDelta(t, 0.1, benchmark[0]["benchmark_return"], 1e-9)
+		assert.InDelta(t, 0.01, benchmark[0]["strategy_return"], 1e-9)
+		assert.InDelta(t, 0.01-0.1, benchmark[0]["alpha"], 1e-9)
+
+		csvDir := filepath.Join(t.TempDir(), "csv")
+		require.NoError(t, backtestEngine.ExportResults("csv", csvDir))
+
+		rows := readCSV(t, filepath.Join(csvDir, "benchmark.csv"))
+		require.Len(t, rows, 1)
+		assert.Equal(t, "TEST", rows[0]["symbol"])
+		assert.Equal(t, "11000.0", rows[0]["benchmark_final_value"])
+	})
+
+	t.Run("Unsupported format is rejected", func(t *testing.T) {
+		backtestEngine := runExportBacktest(t)
+
+		assert.Error(t, backtestEngine.ExportResults("xml", filepath.Join(t.TempDir(), "results")))
+	})
+
+	t.Run("Export before a run fails", func(t *testing.T) {
+		engine, err := NewBacktestEngineV1()
+		require.NoError(t, err)
+
+		err = engine.(*BacktestEngineV1).ExportResults("json", filepath.Join(t.TempDir(), "results"))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewBenchmarkComparison(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := types.MarketData{Symbol: "SPY", Time: start, Close: 80}
+	last := types.MarketData{Symbol: "SPY", Time: start.AddDate(0, 0, 30), Close: 100}
+
+	comparison := newBenchmarkComparison(20000, 3000, first, last)
+
+	// 250 shares bought at 80 are worth 25000 at 100
+	assert.Equal(t, "SPY", comparison.Symbol)
+	assert.InDelta(t, 25000.0, comparison.BenchmarkFinalValue, 1e-9)
+	assert.InDelta(t, 0.25, comparison.BenchmarkReturn, 1e-9)
+	assert.InDelta(t, 0.15, comparison.StrategyReturn, 1e-9)
+	assert.InDelta(t, comparison.StrategyReturn-comparison.BenchmarkReturn, comparison.Alpha, 1e-12)
+	assert.InDelta(t, -0.1, comparison.Alpha, 1e-9)
+}
@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// AutoFlattenConfig configures flattening all positions at the end of each
+// trading session, for intraday strategies that must not hold overnight.
+type AutoFlattenConfig struct {
+	Time     string `yaml:"time" json:"time" jsonschema:"title=Time,description=Time of day (HH:MM) at which the session ends. On the first bar of each symbol at or after this time open orders are cancelled and the position is closed at market. When the data has no bar at or after this time on a day (e.g. an early close) this happens on the symbol's next bar. Use the last bar before the close (e.g. 15:59 for minute data) to exit inside the session. Leave empty to disable."`
+	Timezone string `yaml:"timezone" json:"timezone" jsonschema:"title=Timezone,description=IANA timezone the time is expressed in (e.g. America/New_York). Defaults to UTC."`
+}
+
+// Enabled reports whether auto-flatten is configured.
+func (c AutoFlattenConfig) Enabled() bool {
+	return c.Time != ""
+}
+
+// sessionFlattener tracks the session end, the last bar of each symbol and
+// the session whose open orders were last cancelled, so orders are cancelled
+// once per session.
+type sessionFlattener struct {
+	sessionEnd clockTime
+	location   *time.Location
+	// lastBars is the time of the previous bar of each symbol.
+	lastBars map[string]time.Time
+	// cancelledSession is the session end at which open orders were last cancelled.
+	cancelledSession time.Time
+}
+
+// newSessionFlattener parses the config into a sessionFlattener.
+func newSessionFlattener(config AutoFlattenConfig) (*sessionFlattener, error) {
+	sessionEnd, err := parseClockTime(config.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := loadTimezone(config.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionFlattener{
+		sessionEnd:       sessionEnd,
+		location:         location,
+		lastBars:         map[string]time.Time{},
+		cancelledSession: time.Time{},
+	}, nil
+}
+
+// isAfterSessionEnd reports whether t is at or after the session end of its day.
+func (f *sessionFlattener) isAfterSessionEnd(t time.Time) bool {
+	return clockTimeOf(t, f.location) >= f.sessionEnd
+}
+
+// lastSessionEnd returns the latest session end at or before t.
+func (f *sessionFlattener) lastSessionEnd(t time.Time) time.Time {
+	local := t.In(f.location)
+
+	end := time.Date(local.Year(), local.Month(), local.Day(), int(f.sessionEnd)/60, int(f.sessionEnd)%60, 0, 0, f.location)
+	if local.Before(end) {
+		end = end.AddDate(0, 0, -1)
+	}
+
+	return end
+}
+
+// observe records a bar of symbol at t and reports whether the position must
+// be flattened: the bar is at or after the session end of its day, or a
+// session end passed since the symbol's previous bar without a bar at or
+// after it.
+func (f *sessionFlattener) observe(symbol string, t time.Time) bool {
+	previous, seen := f.lastBars[symbol]
+	f.lastBars[symbol] = t
+
+	if f.isAfterSessionEnd(t) {
+		return true
+	}
+
+	return seen && previous.Before(f.lastSessionEnd(t))
+}
+
+// reset forgets the observed bars and cancellations of a previous run.
+func (f *sessionFlattener) reset() {
+	f.lastBars = map[string]time.Time{}
+	f.cancelledSession = time.Time{}
+}
+
+// SetAutoFlatten configures auto-flatten at session end. An empty config disables it.
+func (b *BacktestTrading) SetAutoFlatten(config AutoFlattenConfig) error {
+	if !config.Enabled() {
+		b.sessionFlatten = nil
+
+		return nil
+	}
+
+	flattener, err := newSessionFlattener(config)
+	if err != nil {
+		return err
+	}
+
+	b.sessionFlatten = flattener
+
+	return nil
+}
+
+// rejectAfterSessionEnd stores a failed order and reports true when the order
+// would open a position after the session end.
+func (b *BacktestTrading) rejectAfterSessionEnd(order types.ExecuteOrder) (bool, error) {
+	if b.sessionFlatten == nil || !isEntryOrder(order) || !b.sessionFlatten.isAfterSessionEnd(b.marketData.Time) {
+		return false, nil
+	}
+
+	failedOrder := b.createFailedOrder(order, order.Price, types.OrderReasonSessionClose,
+		"new entries are not allowed after the session end")
+
+	return true, b.state.StoreFailedOrder(failedOrder)
+}
+
+// flattenAtSessionEnd cancels all open orders once per session and closes the
+// current symbol's position when the current bar is at or after the session
+// end, or is the symbol's first bar after a session end it had no bar for.
+func (b *BacktestTrading) flattenAtSessionEnd() {
+	if b.sessionFlatten == nil || !b.sessionFlatten.observe(b.marketData.Symbol, b.marketData.Time) {
+		return
+	}
+
+	session := b.sessionFlatten.lastSessionEnd(b.marketData.Time)
+	if !session.Equal(b.sessionFlatten.cancelledSession) {
+		_ = b.CancelAllOrders()
+		b.signalConfirmations = map[string]*signalConfirmation{}
+		b.sessionFlatten.cancelledSession = session
+	}
+
+	position, err := b.state.GetPosition(b.marketData.Symbol)
+	if err != nil {
+		return
+	}
+
+	if position.TotalLongPositionQuantity > 0 {
+		_ = b.executeMarketOrder(b.sessionCloseOrder(position, types.PurchaseTypeSell, types.PositionTypeLong, position.TotalLongPositionQuantity))
+	}
+
+	if position.TotalShortPositionQuantity > 0 {
+		_ = b.executeMarketOrder(b.sessionCloseOrder(position, types.PurchaseTypeBuy, types.PositionTypeShort, position.TotalShortPositionQuantity))
+	}
+}
+
+// sessionCloseOrder builds the market order that closes a position at session end.
+func (b *BacktestTrading) sessionCloseOrder(position types.Position, side types.PurchaseType, positionType types.PositionType, quantity float64) types.ExecuteOrder {
+	return types.ExecuteOrder{
+		ID:        uuid.New().String(),
+		Symbol:    position.Symbol,
+		Side:      side,
+		OrderType: types.OrderTypeMarket,
+		Reason: types.Reason{
+			Reason:  types.OrderReasonSessionClose,
+			Message: fmt.Sprintf("flatten %s position at session end", positionType),
+		},
+		Price:          b.marketData.Close,
+		StrategyName:   position.StrategyName,
+		Quantity:       quantity,
+		PositionType:   positionType,
+		StopPrice:      0,
+		TrailOffset:    0,
+		TrailPercent:   false,
+		FilledQuantity: 0,
+		TimeInForce:    types.TimeInForceGTC,
+		GoodTillDate:   time.Time{},
+		TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+	}
+}
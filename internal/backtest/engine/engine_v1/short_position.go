@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// rejectShortOrder checks a short order against the account at the given price.
+// A sell with PositionTypeShort opens or adds to a short: the notional of the
+// borrowed quantity, including the existing short, divided by the leverage must
+// be covered by the cash balance as margin. A buy with PositionTypeShort covers the short and may not exceed the
+// borrowed quantity. It reports whether the order was rejected; rejected orders
+// are stored as failed orders.
+func (b *BacktestTrading) rejectShortOrder(order types.ExecuteOrder, price float64) (bool, error) {
+	if order.Side == types.PurchaseTypeSell {
+		borrowed := b.getShortQuantity()
+
+		requiredMargin := (borrowed + order.Quantity) * price / b.margin.leverage()
+		if requiredMargin > b.balance {
+			failedOrder := b.createFailedOrder(order, price, types.OrderReasonInsufficientBuyPower,
+				fmt.Sprintf("short margin requirement (%.2f) exceeds available balance (%.2f)", requiredMargin, b.balance))
+
+			return true, b.state.StoreFailedOrder(failedOrder)
+		}
+
+		return false, nil
+	}
+
+	borrowed := b.getShortQuantity()
+	if order.Quantity > borrowed {
+		failedOrder := b.createFailedOrder(order, price, types.OrderReasonInsufficientSellPower,
+			fmt.Sprintf("cover quantity (%.2f) exceeds short position (%.2f)", order.Quantity, borrowed))
+
+		return true, b.state.StoreFailedOrder(failedOrder)
+	}
+
+	return false, nil
+}
+
+// getShortQuantity returns the borrowed quantity of the short position for the current market data.
+func (b *BacktestTrading) getShortQuantity() float64 {
+	position, err := b.GetPosition(b.marketData.Symbol)
+	if err != nil {
+		return 0
+	}
+
+	return b.floorQuantity(b.marketData.Symbol, position.TotalShortPositionQuantity)
+}
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// SetSignalOnly switches signal-only mode on or off. In signal-only mode every
+// valid order is recorded as a signal at the current bar instead of being
+// executed: nothing fills, no order fails for lack of buying power and the
+// balance and positions never change.
+func (b *BacktestTrading) SetSignalOnly(enabled bool) {
+	b.signalOnly = enabled
+}
+
+// recordSignal stores order as a signal of the current bar.
+func (b *BacktestTrading) recordSignal(order types.ExecuteOrder) error {
+	signal := OrderSignal{
+		ID:           order.ID,
+		Symbol:       order.Symbol,
+		Side:         order.Side,
+		OrderType:    order.OrderType,
+		PositionType: order.PositionType,
+		Quantity:     order.Quantity,
+		Price:        order.Price,
+		MarketPrice:  b.marketData.Close,
+		Timestamp:    b.marketData.Time,
+		StrategyName: order.StrategyName,
+		Reason:       order.Reason.Reason,
+		Message:      order.Reason.Message,
+	}
+
+	if err := b.state.RecordSignal(signal); err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to record signal", err)
+	}
+
+	return nil
+}
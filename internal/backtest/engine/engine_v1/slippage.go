@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// SlippageModelType selects how market order fills are moved away from the bar price.
+type SlippageModelType string
+
+const (
+	// SlippageModelNone fills market orders at the bar price.
+	SlippageModelNone SlippageModelType = "none"
+	// SlippageModelFixedBps moves every fill by a fixed number of basis points.
+	SlippageModelFixedBps SlippageModelType = "fixed_bps"
+	// SlippageModelVolume moves fills in proportion to the order's share of the bar volume.
+	SlippageModelVolume SlippageModelType = "volume"
+	// SlippageModelSpread moves fills by half of a spread estimated from the bar range.
+	SlippageModelSpread SlippageModelType = "spread"
+	// SlippageModelRandomBps moves every fill by a random number of basis points
+	// drawn from the run's seeded random source.
+	SlippageModelRandomBps SlippageModelType = "random_bps"
+)
+
+// AllSlippageModels is the list of supported slippage models (used by schema generation).
+var AllSlippageModels = []any{
+	string(SlippageModelNone),
+	string(SlippageModelFixedBps),
+	string(SlippageModelVolume),
+	string(SlippageModelSpread),
+	string(SlippageModelRandomBps),
+}
+
+// basisPoint is one hundredth of a percent.
+const basisPoint = 0.0001
+
+// SlippageConfig selects the slippage model applied to market order fills and its parameters.
+type SlippageConfig struct {
+	Model          SlippageModelType `yaml:"model" json:"model" jsonschema:"title=Model,description=Slippage model applied to market order fills. 'fixed_bps' moves every fill by bps; 'volume' scales bps by the order quantity over the bar volume; 'spread' crosses half of a spread estimated from the bar range; 'random_bps' moves every fill by a random number of basis points up to bps drawn from the random_seed source. Defaults to 'none'.,default=none"`
+	Bps            float64           `yaml:"bps" json:"bps" jsonschema:"title=Basis Points,description=For 'fixed_bps' the slippage in basis points. For 'random_bps' the largest slippage in basis points. For 'volume' the slippage in basis points of an order equal to the whole bar volume.,minimum=0,default=0"`
+	MaxBps         float64           `yaml:"max_bps" json:"max_bps" jsonschema:"title=Max Basis Points,description=Upper bound of the 'volume' model slippage in basis points. Set to 0 for no bound.,minimum=0,default=0"`
+	SpreadFraction float64           `yaml:"spread_fraction" json:"spread_fraction" jsonschema:"title=Spread Fraction,description=For 'spread' the estimated bid-ask spread as a fraction of the bar's high-low range. Fills cross half of it.,minimum=0,default=0"`
+}
+
+// SlippageModel computes how far a market order fill moves against the order.
+// Apply returns the slippage per unit in price terms; it is added to the price
+// of buys and subtracted from the price of sells.
+type SlippageModel interface {
+	Apply(order types.ExecuteOrder, md types.MarketData) float64
+}
+
+// FixedBpsSlippage moves fills by a fixed number of basis points of the bar price.
+type FixedBpsSlippage struct {
+	Bps float64
+}
+
+// Apply implements SlippageModel.
+func (s FixedBpsSlippage) Apply(_ types.ExecuteOrder, md types.MarketData) float64 {
+	return barPrice(md) * s.Bps * basisPoint
+}
+
+// VolumeSlippage moves fills in proportion to the order quantity over the bar
+// volume, so larger orders in thinner bars pay more. Bars without volume are
+// charged MaxBps, or Bps when there is no bound.
+type VolumeSlippage struct {
+	Bps    float64
+	MaxBps float64
+}
+
+// Apply implements SlippageModel.
+func (s VolumeSlippage) Apply(order types.ExecuteOrder, md types.MarketData) float64 {
+	bps := s.Bps
+	if md.Volume > 0 {
+		bps = s.Bps * order.Quantity / md.Volume
+	} else if s.MaxBps > 0 {
+		bps = s.MaxBps
+	}
+
+	if s.MaxBps > 0 {
+		bps = math.Min(bps, s.MaxBps)
+	}
+
+	return barPrice(md) * bps * basisPoint
+}
+
+// SpreadSlippage crosses half of a spread estimated as a fraction of the bar's
+// high-low range, since bars carry no bid and ask prices.
+type SpreadSlippage struct {
+	SpreadFraction float64
+}
+
+// Apply implements SlippageModel.
+func (s SpreadSlippage) Apply(_ types.ExecuteOrder, md types.MarketData) float64 {
+	return (md.High - md.Low) * s.SpreadFraction / 2
+}
+
+// RandomBpsSlippage moves fills by a number of basis points of the bar price
+// drawn uniformly between 0 and Bps. Draws come from Rand, so a run seeded the
+// same way slips the same way.
+type RandomBpsSlippage struct {
+	Bps  float64
+	Rand *rand.Rand
+}
+
+// Apply implements SlippageModel.
+func (s RandomBpsSlippage) Apply(_ types.ExecuteOrder, md types.MarketData) float64 {
+	return barPrice(md) * s.Rand.Float64() * s.Bps * basisPoint
+}
+
+// NewSlippageModel creates the slippage model selected by config. Stochastic
+// models draw from rng. It returns nil when slippage is disabled.
+func NewSlippageModel(config SlippageConfig, rng *rand.Rand) (SlippageModel, error) {
+	if config.Bps < 0 || config.MaxBps < 0 || config.SpreadFraction < 0 {
+		return nil, errors.New(errors.ErrCodeInvalidParameter, "slippage parameters must not be negative")
+	}
+
+	switch config.Model {
+	case "", SlippageModelNone:
+		return nil, nil
+	case SlippageModelFixedBps:
+		return FixedBpsSlippage{Bps: config.Bps}, nil
+	case SlippageModelVolume:
+		return VolumeSlippage{Bps: config.Bps, MaxBps: config.MaxBps}, nil
+	case SlippageModelSpread:
+		return SpreadSlippage{SpreadFraction: config.SpreadFraction}, nil
+	case SlippageModelRandomBps:
+		return RandomBpsSlippage{Bps: config.Bps, Rand: rng}, nil
+	default:
+		return nil, errors.Newf(errors.ErrCodeInvalidParameter, "unknown slippage model %q", config.Model)
+	}
+}
+
+// SetSlippage configures the slippage applied to market order fills.
+func (b *BacktestTrading) SetSlippage(config SlippageConfig) error {
+	model, err := NewSlippageModel(config, b.random())
+	if err != nil {
+		return err
+	}
+
+	b.slippage = model
+
+	return nil
+}
+
+// applySlippage moves price against the order by the configured slippage.
+func (b *BacktestTrading) applySlippage(order types.ExecuteOrder, price float64) float64 {
+	if b.slippage == nil {
+		return price
+	}
+
+	slippage := b.slippage.Apply(order, b.marketData)
+	if order.Side == types.PurchaseTypeBuy {
+		return price + slippage
+	}
+
+	return price - slippage
+}
+
+// barPrice is the mid price of the bar. Market orders fill at it under
+// FillPriceMid when the bar has no quotes, and slippage in basis points is
+// measured against it.
+func barPrice(md types.MarketData) float64 {
+	return (md.High + md.Low) / 2
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlippageModels(t *testing.T) {
+	bar := types.MarketData{Symbol: "AAPL", High: 101, Low: 99, Close: 100, Volume: 1000}
+	order := types.ExecuteOrder{Symbol: "AAPL", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 100}
+
+	tests := []struct {
+		name           string
+		config         SlippageConfig
+		md             types.MarketData
+		expectSlippage float64
+	}{
+		{
+			name:           "fixed bps",
+			config:         SlippageConfig{Model: SlippageModelFixedBps, Bps: 10},
+			md:             bar,
+			expectSlippage: 0.10,
+		},
+		{
+			name:           "volume scales with the order's share of the bar",
+			config:         SlippageConfig{Model: SlippageModelVolume, Bps: 100},
+			md:             bar,
+			expectSlippage: 0.10,
+		},
+		{
+			name:           "volume is bounded by max bps",
+			config:         SlippageConfig{Model: SlippageModelVolume, Bps: 1000, MaxBps: 5},
+			md:             bar,
+			expectSlippage: 0.05,
+		},
+		{
+			name:           "volume charges max bps on bars without volume",
+			config:         SlippageConfig{Model: SlippageModelVolume, Bps: 100, MaxBps: 5},
+			md:             types.MarketData{Symbol: "AAPL", High: 101, Low: 99, Close: 100},
+			expectSlippage: 0.05,
+		},
+		{
+			name:           "spread crosses half of the estimated spread",
+			config:         SlippageConfig{Model: SlippageModelSpread, SpreadFraction: 0.1},
+			md:             bar,
+			expectSlippage: 0.10,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			model, err := NewSlippageModel(tc.config, nil)
+			require.NoError(t, err)
+			require.NotNil(t, model)
+			assert.InDelta(t, tc.expectSlippage, model.Apply(order, tc.md), 1e-9)
+		})
+	}
+}
+
+func TestNewSlippageModel(t *testing.T) {
+	model, err := NewSlippageModel(SlippageConfig{Model: SlippageModelNone}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, model)
+
+	model, err = NewSlippageModel(SlippageConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, model)
+
+	_, err = NewSlippageModel(SlippageConfig{Model: "random"}, nil)
+	assert.Error(t, err)
+
+	_, err = NewSlippageModel(SlippageConfig{Model: SlippageModelFixedBps, Bps: -1}, nil)
+	assert.Error(t, err)
+}
+
+func TestRandomBpsSlippage(t *testing.T) {
+	bar := types.MarketData{Symbol: "AAPL", High: 101, Low: 99, Close: 100, Volume: 1000}
+	order := types.ExecuteOrder{Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 10}
+
+	model, err := NewSlippageModel(SlippageConfig{Model: SlippageModelRandomBps, Bps: 20}, rand.New(rand.NewSource(7)))
+	require.NoError(t, err)
+
+	varied := false
+	first := model.Apply(order, bar)
+
+	for range 100 {
+		slippage := model.Apply(order, bar)
+		assert.GreaterOrEqual(t, slippage, 0.0)
+		assert.Less(t, slippage, 0.2, "slippage stays below 20 bps of the bar price")
+
+		if slippage != first {
+			varied = true
+		}
+	}
+
+	assert.True(t, varied, "slippage should vary from fill to fill")
+}
+
+// randomSlippageTrades runs the momentum strategy with random slippage seeded
+// with seed and returns its trades as JSON.
+func randomSlippageTrades(t *testing.T, seed int64) []byte {
+	config := fmt.Sprintf("initial_capital: 100000\nrandom_seed: %d\nslippage:\n  model: random_bps\n  bps: 50\n", seed)
+	trades := runMultiSymbolBacktest(t, config, newMomentumStrategy(0), multiSymbolBars(40))
+	require.NotEmpty(t, trades)
+
+	output, err := json.Marshal(trades)
+	require.NoError(t, err)
+
+	return output
+}
+
+func TestBacktestEngineV1_RandomSeed(t *testing.T) {
+	setTestVersion(t, "1.0.0")
+
+	t.Run("Same seed reproduces the trades", func(t *testing.T) {
+		assert.Equal(t, randomSlippageTrades(t, 42), randomSlippageTrades(t, 42))
+	})
+
+	t.Run("Different seeds diverge", func(t *testing.T) {
+		assert.NotEqual(t, randomSlippageTrades(t, 42), randomSlippageTrades(t, 43))
+	})
+
+	t.Run("Reset replays the seeded sequence", func(t *testing.T) {
+		trading := &BacktestTrading{}
+		trading.SetRandomSeed(5)
+		first := []float64{trading.rng.Float64(), trading.rng.Float64()}
+
+		trading.Reset(1000)
+		assert.Equal(t, first, []float64{trading.rng.Float64(), trading.rng.Float64()})
+	})
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"math"
 	"os"
-	"path/filepath"
 	"sort"
 	"sync"
 	"time"
@@ -42,6 +41,26 @@ type BacktestState struct {
 	// realizedPnL is the running sum of FIFO PnL across all committed trades
 	// for the current run. Reset by Initialize so each run starts at zero.
 	realizedPnL float64
+
+	// lastEquityCurveTime is the time of the last equity curve row, used to
+	// downsample the curve. Zero when nothing has been recorded in this run.
+	lastEquityCurveTime time.Time
+	// equityCurveBuffer holds the equity curve rows not yet inserted. The curve
+	// is recorded on every bar, so rows are inserted in batches.
+	equityCurveBuffer []types.EquitySnapshot
+
+	// lastTradeBalance is the cash balance after the most recent trade, valid
+	// once hasTraded is set, and totalFunding the sum of the funding payments.
+	// Both mirror the database so the account can be valued on every bar
+	// without querying it. Reset by Initialize.
+	lastTradeBalance float64
+	hasTraded        bool
+	totalFunding     float64
+
+	// subAccounts is the sub-account of each strategy name the initial balance
+	// is allocated to. Nil when the strategies share the balance.
+	subAccountsMu sync.Mutex
+	subAccounts   map[string]*subAccount
 }
 
 // CalculatePNL calculates the profit/loss for a trade
@@ -73,6 +92,13 @@ func NewBacktestState(logger *logger.Logger) (*BacktestState, error) {
 		positionCacheMu:           sync.Mutex{},
 		positionCache:             make(map[string]*types.Position),
 		realizedPnL:               0,
+		lastEquityCurveTime:       time.Time{},
+		equityCurveBuffer:         nil,
+		lastTradeBalance:          0,
+		hasTraded:                 false,
+		totalFunding:              0,
+		subAccountsMu:             sync.Mutex{},
+		subAccounts:               nil,
 	}, nil
 }
 
@@ -125,6 +151,12 @@ func (b *BacktestState) Initialize() error {
 
 	// Reset per-run accumulators so each run starts at zero.
 	b.realizedPnL = 0
+	b.lastEquityCurveTime = time.Time{}
+	b.equityCurveBuffer = nil
+	b.lastTradeBalance = 0
+	b.hasTraded = false
+	b.totalFunding = 0
+	b.resetSubAccounts()
 
 	// Create sequence for order IDs
 	_, err := b.db.Exec(`CREATE SEQUENCE IF NOT EXISTS order_id_seq`)
@@ -177,14 +209,28 @@ func (b *BacktestState) Initialize() error {
 			open_position_qty DOUBLE,
 			balance DOUBLE,
 			hold_time BIGINT,
-			average_cost DOUBLE
+			average_cost DOUBLE,
+			realized_pnl DOUBLE,
+			cost_basis DOUBLE
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create trades table: %w", err)
 	}
 
-	return nil
+	if err := b.createEquityTable(equitySnapshotsTable); err != nil {
+		return err
+	}
+
+	if err := b.createFundingPaymentsTable(); err != nil {
+		return err
+	}
+
+	if err := b.createSignalsTable(); err != nil {
+		return err
+	}
+
+	return b.createEquityTable(equityCurveTable)
 }
 
 // UpdateResult contains the results of processing an order.
@@ -245,12 +291,24 @@ func (b *BacktestState) Update(orders []types.Order) ([]UpdateResult, error) {
 			return nil, fmt.Errorf("failed to get position: %w", err)
 		}
 
-		// Calculate FIFO PnL for closing trades; buys have zero individual PnL.
-		fifoPnl, err := b.computeClosingPnL(order, currentPosition)
+		// Match closing trades against the oldest open entry lots; opening
+		// trades realize nothing and have no cost basis.
+		realizedPnl, costBasis, err := b.computeClosingFIFO(order, currentPosition)
 		if err != nil {
 			tx.Rollback()
 
-			return nil, fmt.Errorf("failed to calculate FIFO PnL: %w", err)
+			return nil, fmt.Errorf("failed to match FIFO lots: %w", err)
+		}
+
+		// Per-trade PnL under the configured portfolio calculation strategy.
+		tradePnl := realizedPnl
+		if b.portfolioStrategy == PortfolioCalculationAverageCost {
+			tradePnl, err = b.computeClosingAverageCostPnL(order, currentPosition)
+			if err != nil {
+				tx.Rollback()
+
+				return nil, fmt.Errorf("failed to calculate average cost PnL: %w", err)
+			}
 		}
 
 		// Cumulative PnL is the running sum of per-trade PnL for this symbol.
@@ -261,7 +319,7 @@ func (b *BacktestState) Update(orders []types.Order) ([]UpdateResult, error) {
 			return nil, fmt.Errorf("failed to query prior pnl sum: %w", err)
 		}
 
-		cumulativePnl := priorPnlSum + fifoPnl
+		cumulativePnl := priorPnlSum + tradePnl
 
 		// LIFO PnL: closing trades are matched against the most recent (last)
 		// unmatched entry trades. Opening trades return 0.
@@ -321,9 +379,11 @@ func (b *BacktestState) Update(orders []types.Order) ([]UpdateResult, error) {
 			ExecutedQty:     order.Quantity,
 			ExecutedPrice:   order.Price,
 			Fee:             order.Fee,
-			PnL:             fifoPnl,
+			PnL:             tradePnl,
 			CumulativePnL:   cumulativePnl,
 			LIFOPnL:         lifoPnl,
+			RealizedPnL:     realizedPnl,
+			CostBasis:       costBasis,
 			OpenPositionQty: openPositionQty,
 			Balance:         balance,
 			HoldTime:        holdTime,
@@ -337,14 +397,14 @@ func (b *BacktestState) Update(orders []types.Order) ([]UpdateResult, error) {
 				"order_id", "symbol", "order_type", "quantity", "price", "timestamp",
 				"is_completed", "reason", "message", "strategy_name",
 				"executed_at", "executed_qty", "executed_price", "commission", "pnl", "cumulative_pnl", "lifo_pnl", "position_type",
-				"open_position_qty", "balance", "hold_time", "average_cost",
+				"open_position_qty", "balance", "hold_time", "average_cost", "realized_pnl", "cost_basis",
 			).
 			Values(
 				orderID, trade.Order.Symbol, trade.Order.Side, trade.Order.Quantity, trade.Order.Price,
 				trade.Order.Timestamp, trade.Order.IsCompleted, trade.Order.Reason.Reason, trade.Order.Reason.Message,
 				order.StrategyName, trade.ExecutedAt, trade.ExecutedQty, trade.ExecutedPrice,
 				trade.Fee, trade.PnL, trade.CumulativePnL, trade.LIFOPnL, trade.Order.PositionType,
-				trade.OpenPositionQty, trade.Balance, trade.HoldTime, trade.AverageCost,
+				trade.OpenPositionQty, trade.Balance, trade.HoldTime, trade.AverageCost, trade.RealizedPnL, trade.CostBasis,
 			).
 			RunWith(tx)
 
@@ -362,12 +422,15 @@ func (b *BacktestState) Update(orders []types.Order) ([]UpdateResult, error) {
 		}
 
 		// Update running realized PnL for fast lookups in progress callbacks.
-		b.realizedPnL += fifoPnl
+		b.realizedPnL += tradePnl
+		b.lastTradeBalance = balance
+		b.hasTraded = true
 
 		// Mirror the trade in the in-memory position cache. Done after commit so
 		// a rolled-back transaction never leaves the cache ahead of the DB.
 		order.OrderID = orderID
 		b.applyTradeToCache(order)
+		b.applyTradeToSubAccount(order)
 
 		// Add result
 		results = append(results, UpdateResult{
@@ -445,12 +508,7 @@ func (b *BacktestState) GetPosition(symbol string) (types.Position, error) {
 // GetAllTrades returns all trades from the database.
 func (b *BacktestState) GetAllTrades() ([]types.Trade, error) {
 	selectQuery := b.sq.
-		Select(
-			"order_id", "symbol", "order_type", "quantity", "price", "timestamp",
-			"is_completed", "reason", "message", "strategy_name",
-			"executed_at", "executed_qty", "executed_price", "commission", "pnl", "cumulative_pnl", "lifo_pnl", "position_type",
-			"hold_time",
-		).
+		Select(tradeColumns...).
 		From("trades").
 		OrderBy("executed_at ASC").
 		RunWith(b.db)
@@ -466,28 +524,7 @@ func (b *BacktestState) GetAllTrades() ([]types.Trade, error) {
 	for rows.Next() {
 		var trade types.Trade
 
-		err := rows.Scan(
-			&trade.Order.OrderID,
-			&trade.Order.Symbol,
-			&trade.Order.Side,
-			&trade.Order.Quantity,
-			&trade.Order.Price,
-			&trade.Order.Timestamp,
-			&trade.Order.IsCompleted,
-			&trade.Order.Reason.Reason,
-			&trade.Order.Reason.Message,
-			&trade.Order.StrategyName,
-			&trade.ExecutedAt,
-			&trade.ExecutedQty,
-			&trade.ExecutedPrice,
-			&trade.Fee,
-			&trade.PnL,
-			&trade.CumulativePnL,
-			&trade.LIFOPnL,
-			&trade.Order.PositionType,
-			&trade.HoldTime,
-		)
-		if err != nil {
+		if err := rows.Scan(tradeScanTargets(&trade)...); err != nil {
 			return nil, fmt.Errorf("failed to scan trade: %w", err)
 		}
 
@@ -501,6 +538,44 @@ func (b *BacktestState) GetAllTrades() ([]types.Trade, error) {
 	return trades, nil
 }
 
+// tradeColumns are the trades table columns read into a types.Trade, in the order of tradeScanTargets.
+var tradeColumns = []string{
+	"order_id", "symbol", "order_type", "quantity", "price", "timestamp",
+	"is_completed", "reason", "message", "strategy_name",
+	"executed_at", "executed_qty", "executed_price", "commission", "pnl", "cumulative_pnl", "lifo_pnl", "position_type",
+	"hold_time", "open_position_qty", "balance", "average_cost", "realized_pnl", "cost_basis",
+}
+
+// tradeScanTargets returns the fields of trade to scan the tradeColumns into.
+func tradeScanTargets(trade *types.Trade) []any {
+	return []any{
+		&trade.Order.OrderID,
+		&trade.Order.Symbol,
+		&trade.Order.Side,
+		&trade.Order.Quantity,
+		&trade.Order.Price,
+		&trade.Order.Timestamp,
+		&trade.Order.IsCompleted,
+		&trade.Order.Reason.Reason,
+		&trade.Order.Reason.Message,
+		&trade.Order.StrategyName,
+		&trade.ExecutedAt,
+		&trade.ExecutedQty,
+		&trade.ExecutedPrice,
+		&trade.Fee,
+		&trade.PnL,
+		&trade.CumulativePnL,
+		&trade.LIFOPnL,
+		&trade.Order.PositionType,
+		&trade.HoldTime,
+		&trade.OpenPositionQty,
+		&trade.Balance,
+		&trade.AverageCost,
+		&trade.RealizedPnL,
+		&trade.CostBasis,
+	}
+}
+
 // Cleanup resets the database state.
 func (b *BacktestState) Cleanup() error {
 	// Check for nil db
@@ -512,6 +587,10 @@ func (b *BacktestState) Cleanup() error {
 	_, err := b.db.Exec(`
 		DROP TABLE IF EXISTS trades;
 		DROP TABLE IF EXISTS orders;
+		DROP TABLE IF EXISTS equity_snapshots;
+		DROP TABLE IF EXISTS equity_curve;
+		DROP TABLE IF EXISTS funding_payments;
+		DROP TABLE IF EXISTS signals;
 		DROP SEQUENCE IF EXISTS order_id_seq;
 	`)
 	if err != nil {
@@ -526,6 +605,12 @@ func (b *BacktestState) Cleanup() error {
 
 // Write saves the backtest results to Parquet files in the specified directory.
 func (b *BacktestState) Write(path string) error {
+	return b.WriteFormats(path, []ResultOutputFormat{ResultOutputFormatParquet})
+}
+
+// WriteFormats writes the trades, orders, equity snapshots, equity curve and any signals to the path in every
+// given format (e.g. trades.parquet, trades.csv and trades.json).
+func (b *BacktestState) WriteFormats(path string, formats []ResultOutputFormat) error {
 	// Check for nil fields
 	if b == nil || b.db == nil || b.logger == nil {
 		return fmt.Errorf("backtest state, database, or logger is nil")
@@ -536,25 +621,51 @@ func (b *BacktestState) Write(path string) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Export trades to Parquet - using raw SQL as Squirrel doesn't support COPY
-	tradesPath := filepath.Join(path, "trades.parquet")
+	tradesPaths, err := copyTableToFormats(b.db, "trades", path, formats)
+	if err != nil {
+		return err
+	}
+
+	ordersPaths, err := copyTableToFormats(b.db, "orders", path, formats)
+	if err != nil {
+		return err
+	}
 
-	_, err := b.db.Exec(fmt.Sprintf(`COPY trades TO '%s' (FORMAT PARQUET)`, tradesPath))
+	equitySnapshotsPaths, err := copyTableToFormats(b.db, "equity_snapshots", path, formats)
 	if err != nil {
-		return fmt.Errorf("failed to export trades to Parquet: %w", err)
+		return err
 	}
 
-	// Export orders to Parquet
-	ordersPath := filepath.Join(path, "orders.parquet")
+	if err := b.flushEquityCurve(); err != nil {
+		return err
+	}
+
+	equityCurvePaths, err := copyTableToFormats(b.db, "equity_curve", path, formats)
+	if err != nil {
+		return err
+	}
 
-	_, err = b.db.Exec(fmt.Sprintf(`COPY orders TO '%s' (FORMAT PARQUET)`, ordersPath))
+	// Signals are only recorded in signal-only mode, so other runs write no signals file
+	signalCount, err := b.countSignals()
 	if err != nil {
-		return fmt.Errorf("failed to export orders to Parquet: %w", err)
+		return err
 	}
 
-	b.logger.Info("Successfully exported backtest results to Parquet files",
-		zap.String("trades", tradesPath),
-		zap.String("orders", ordersPath),
+	var signalsPaths []string
+
+	if signalCount > 0 {
+		signalsPaths, err = copyTableToFormats(b.db, "signals", path, formats)
+		if err != nil {
+			return err
+		}
+	}
+
+	b.logger.Info("Successfully exported backtest results",
+		zap.Strings("trades", tradesPaths),
+		zap.Strings("orders", ordersPaths),
+		zap.Strings("equity_snapshots", equitySnapshotsPaths),
+		zap.Strings("equity_curve", equityCurvePaths),
+		zap.Strings("signals", signalsPaths),
 	)
 
 	return nil
@@ -655,15 +766,11 @@ func calculateUnrealizedPnL(position types.Position, lastPrice float64) types.Tr
 		entryDec := decimal.NewFromFloat(position.TotalLongPositionQuantity).Mul(decimal.NewFromFloat(position.GetAverageLongPositionEntryPrice()))
 		exitDec := decimal.NewFromFloat(position.TotalLongPositionQuantity).Mul(decimal.NewFromFloat(lastPrice))
 		unrealizedPnL, _ = exitDec.Sub(entryDec).Float64()
-	} else if position.TotalShortPositionQuantity < 0 {
-		shortQuantity := -position.TotalShortPositionQuantity
-
-		var entryPrice float64
-		if position.TotalShortOutPositionQuantity > 0 {
-			entryPrice = position.TotalShortOutPositionAmount / position.TotalShortOutPositionQuantity
-		}
-
-		unrealizedPnL = (entryPrice - lastPrice) * shortQuantity
+	} else if position.TotalShortPositionQuantity > 0 {
+		// A short profits when the price falls below the average short entry price
+		entryDec := decimal.NewFromFloat(position.TotalShortPositionQuantity).Mul(decimal.NewFromFloat(position.GetAverageShortPositionEntryPrice()))
+		exitDec := decimal.NewFromFloat(position.TotalShortPositionQuantity).Mul(decimal.NewFromFloat(lastPrice))
+		unrealizedPnL, _ = entryDec.Sub(exitDec).Float64()
 	}
 
 	return types.TradePnl{
@@ -825,12 +932,15 @@ func (b *BacktestState) GetAllPositions() ([]types.Position, error) {
 			COALESCE(ss.total_in_short_qty, 0) as total_in_short_position_quantity,
 			COALESCE(sc.total_out_short_qty, 0) as total_out_short_position_quantity,
 			COALESCE(ss.total_in_short_amount, 0) as total_in_short_position_amount,
-			COALESCE(sc.total_out_short_amount, 0) as total_out_short_position_amount
+			COALESCE(sc.total_out_short_amount, 0) as total_out_short_position_amount,
+			COALESCE(ss.total_in_short_fee, 0) as total_short_in_fee,
+			COALESCE(sc.total_out_short_fee, 0) as total_short_out_fee
 		FROM long_buy_trades b
 		FULL OUTER JOIN long_sell_trades s ON b.symbol = s.symbol
 		FULL OUTER JOIN short_sell_trades ss ON COALESCE(b.symbol, s.symbol) = ss.symbol
 		FULL OUTER JOIN short_cover_trades sc ON COALESCE(b.symbol, s.symbol, ss.symbol) = sc.symbol
 		WHERE (COALESCE(b.total_in_qty, 0) - COALESCE(s.total_out_qty, 0)) != 0
+			OR (COALESCE(ss.total_in_short_qty, 0) - COALESCE(sc.total_out_short_qty, 0)) != 0
 		ORDER BY symbol
 	`
 
@@ -860,11 +970,15 @@ func (b *BacktestState) GetAllPositions() ([]types.Position, error) {
 			&position.TotalShortOutPositionQuantity,
 			&position.TotalShortInPositionAmount,
 			&position.TotalShortOutPositionAmount,
+			&position.TotalShortInFee,
+			&position.TotalShortOutFee,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan position: %w", err)
 		}
 
+		position.TotalShortPositionQuantity = position.TotalShortInPositionQuantity - position.TotalShortOutPositionQuantity
+
 		positions = append(positions, position)
 	}
 
@@ -872,6 +986,15 @@ func (b *BacktestState) GetAllPositions() ([]types.Position, error) {
 		return nil, fmt.Errorf("error iterating positions: %w", err)
 	}
 
+	positionRefs := make([]*types.Position, len(positions))
+	for i := range positions {
+		positionRefs[i] = &positions[i]
+	}
+
+	if err := b.loadEntryCosts(positionRefs); err != nil {
+		return nil, err
+	}
+
 	return positions, nil
 }
 
@@ -921,35 +1044,38 @@ func (b *BacktestState) GetAllOrders() ([]types.Order, error) {
 	return orders, nil
 }
 
-// computeClosingPnL calculates the closing PnL for a trade based on position
-// state, using the configured portfolio calculation strategy (FIFO or
-// average-cost). It returns 0 for opening trades.
-func (b *BacktestState) computeClosingPnL(order types.Order, position types.Position) (float64, error) {
-	isLongClose := order.Side == types.PurchaseTypeSell && order.PositionType == types.PositionTypeLong && position.TotalLongPositionQuantity > 0
-	isShortClose := order.Side == types.PurchaseTypeSell && order.PositionType == types.PositionTypeShort && position.TotalShortPositionQuantity > 0
-
-	if !isLongClose && !isShortClose {
-		return 0, nil
+// computeClosingFIFO matches a closing trade against the oldest open entry
+// lots and returns its realized PnL and the cost basis of the matched lots.
+// It returns zeros for opening trades. The PnL is the trade's PnL under the
+// FIFO portfolio calculation strategy.
+func (b *BacktestState) computeClosingFIFO(order types.Order, position types.Position) (float64, float64, error) {
+	if !isClosingTrade(order, position) {
+		return 0, 0, nil
 	}
 
-	if b.portfolioStrategy == PortfolioCalculationAverageCost {
-		return b.calculateAverageCostPnL(order.Symbol, order.PositionType, order.Quantity, order.Price, order.Fee)
+	return b.calculateFIFOPnL(order.Symbol, order.PositionType, order.Quantity, order.Price, order.Fee)
+}
+
+// computeClosingAverageCostPnL calculates the closing PnL for a trade against
+// the running weighted-average cost of the open position, as used by the
+// average-cost portfolio calculation strategy. It returns 0 for opening trades.
+func (b *BacktestState) computeClosingAverageCostPnL(order types.Order, position types.Position) (float64, error) {
+	if !isClosingTrade(order, position) {
+		return 0, nil
 	}
 
-	return b.calculateFIFOPnL(order.Symbol, order.PositionType, order.Quantity, order.Price, order.Fee)
+	return b.calculateAverageCostPnL(order.Symbol, order.PositionType, order.Quantity, order.Price, order.Fee)
 }
 
 // computeClosingHoldTime returns the quantity-weighted-average holding time (in
-// seconds) for a closing trade. Closing trades are SELL orders that match prior
-// BUY entries (mirroring the convention used by computeClosingPnL). Under FIFO
+// seconds) for a closing trade. Closing trades are exits (sells for longs,
+// covering buys for shorts) that match prior entries (mirroring the convention
+// used by computeClosingFIFO). Under FIFO
 // the match consumes the oldest unmatched entries first; under average-cost the
 // match consumes the most recently acquired entries first (LIFO). Opening
 // trades return 0.
 func (b *BacktestState) computeClosingHoldTime(order types.Order, position types.Position) (int, error) {
-	isLongClose := order.Side == types.PurchaseTypeSell && order.PositionType == types.PositionTypeLong && position.TotalLongPositionQuantity > 0
-	isShortClose := order.Side == types.PurchaseTypeSell && order.PositionType == types.PositionTypeShort && position.TotalShortPositionQuantity > 0
-
-	if !isLongClose && !isShortClose {
+	if !isClosingTrade(order, position) {
 		return 0, nil
 	}
 
@@ -969,7 +1095,7 @@ func (b *BacktestState) calculateFIFOHoldTime(symbol string, positionType types.
 		From("trades").
 		Where(squirrel.Eq{
 			"symbol":        symbol,
-			"order_type":    types.PurchaseTypeBuy,
+			"order_type":    entrySide(positionType),
 			"position_type": positionType,
 		}).
 		OrderBy("executed_at ASC").
@@ -1004,7 +1130,7 @@ func (b *BacktestState) calculateFIFOHoldTime(symbol string, positionType types.
 		From("trades").
 		Where(squirrel.Eq{
 			"symbol":        symbol,
-			"order_type":    types.PurchaseTypeSell,
+			"order_type":    exitSide(positionType),
 			"position_type": positionType,
 		}).
 		RunWith(b.db)
@@ -1092,7 +1218,7 @@ func (b *BacktestState) calculateLIFOHoldTime(symbol string, positionType types.
 			return 0, fmt.Errorf("failed to scan trade for LIFO hold time: %w", err)
 		}
 
-		if types.PurchaseType(orderType) == types.PurchaseTypeBuy {
+		if types.PurchaseType(orderType) == entrySide(positionType) {
 			stack = append(stack, lot{qty: qty, executedAt: executedAt})
 
 			continue
@@ -1150,15 +1276,12 @@ func (b *BacktestState) calculateLIFOHoldTime(symbol string, positionType types.
 
 // computeClosingLIFOPnL calculates the closing PnL for a trade using
 // last-in-first-out matching against prior entry trades. It returns 0 for
-// opening trades. Unlike computeClosingPnL this is independent of the
+// opening trades. Like computeClosingFIFO this is independent of the
 // portfolio calculation strategy: it is always reported as a separate column
 // alongside the strategy-driven PnL so that callers can compare each closing
 // trade's result against the most recent buy lots.
 func (b *BacktestState) computeClosingLIFOPnL(order types.Order, position types.Position) (float64, error) {
-	isLongClose := order.Side == types.PurchaseTypeSell && order.PositionType == types.PositionTypeLong && position.TotalLongPositionQuantity > 0
-	isShortClose := order.Side == types.PurchaseTypeSell && order.PositionType == types.PositionTypeShort && position.TotalShortPositionQuantity > 0
-
-	if !isLongClose && !isShortClose {
+	if !isClosingTrade(order, position) {
 		return 0, nil
 	}
 
@@ -1216,7 +1339,7 @@ func (b *BacktestState) calculateLIFOPnL(symbol string, positionType types.Posit
 			return 0, fmt.Errorf("failed to scan trade for LIFO PnL: %w", err)
 		}
 
-		if types.PurchaseType(orderType) == types.PurchaseTypeBuy {
+		if types.PurchaseType(orderType) == entrySide(positionType) {
 			perUnitFee := 0.0
 			if qty > 0 {
 				perUnitFee = fee / qty
@@ -1315,30 +1438,64 @@ func computeCashBalance(prevBalance float64, order types.Order) float64 {
 	return prevBalance + tradeCost - order.Fee
 }
 
+// entrySide returns the order side that opens or adds to a position of the given type.
+// Long positions are opened by buys, short positions by sells.
+func entrySide(positionType types.PositionType) types.PurchaseType {
+	if positionType == types.PositionTypeShort {
+		return types.PurchaseTypeSell
+	}
+
+	return types.PurchaseTypeBuy
+}
+
+// exitSide returns the order side that reduces or closes a position of the given type.
+// Long positions are closed by sells, short positions are covered by buys.
+func exitSide(positionType types.PositionType) types.PurchaseType {
+	if positionType == types.PositionTypeShort {
+		return types.PurchaseTypeBuy
+	}
+
+	return types.PurchaseTypeSell
+}
+
+// isClosingTrade checks if the order reduces an open position.
+func isClosingTrade(order types.Order, position types.Position) bool {
+	if order.Side != exitSide(order.PositionType) {
+		return false
+	}
+
+	if order.PositionType == types.PositionTypeShort {
+		return position.TotalShortPositionQuantity > 0
+	}
+
+	return position.TotalLongPositionQuantity > 0
+}
+
 // isNewPositionOpened checks if the order opens a new position.
 func isNewPositionOpened(order types.Order, position types.Position) bool {
-	if order.Side != types.PurchaseTypeBuy {
+	if order.Side != entrySide(order.PositionType) {
 		return false
 	}
 
-	if order.PositionType == types.PositionTypeLong {
-		return position.TotalLongPositionQuantity == 0
+	if order.PositionType == types.PositionTypeShort {
+		return position.TotalShortPositionQuantity == 0
 	}
 
-	return order.PositionType == types.PositionTypeShort && position.TotalShortPositionQuantity == 0
+	return position.TotalLongPositionQuantity == 0
 }
 
 // calculateFIFOPnL calculates the individual PnL for a sell order using FIFO matching.
 // It matches the sell quantity against the earliest unmatched buy orders to determine
-// the actual entry cost for this specific trade.
-func (b *BacktestState) calculateFIFOPnL(symbol string, positionType types.PositionType, sellQty float64, sellPrice float64, sellFee float64) (float64, error) {
+// the actual entry cost for this specific trade, and returns the PnL together with
+// that matched entry cost.
+func (b *BacktestState) calculateFIFOPnL(symbol string, positionType types.PositionType, sellQty float64, sellPrice float64, sellFee float64) (float64, float64, error) {
 	// Get all entry (buy) trades for this symbol+positionType in FIFO order
 	entryQuery := b.sq.
 		Select("executed_qty", "executed_price", "commission").
 		From("trades").
 		Where(squirrel.Eq{
 			"symbol":        symbol,
-			"order_type":    types.PurchaseTypeBuy,
+			"order_type":    entrySide(positionType),
 			"position_type": positionType,
 		}).
 		OrderBy("executed_at ASC").
@@ -1346,7 +1503,7 @@ func (b *BacktestState) calculateFIFOPnL(symbol string, positionType types.Posit
 
 	entryRows, err := entryQuery.Query()
 	if err != nil {
-		return 0, fmt.Errorf("failed to query entry trades for FIFO: %w", err)
+		return 0, 0, fmt.Errorf("failed to query entry trades for FIFO: %w", err)
 	}
 	defer entryRows.Close()
 
@@ -1360,12 +1517,12 @@ func (b *BacktestState) calculateFIFOPnL(symbol string, positionType types.Posit
 	for entryRows.Next() {
 		var e entryTrade
 		if err := entryRows.Scan(&e.qty, &e.price, &e.fee); err != nil {
-			return 0, fmt.Errorf("failed to scan entry trade: %w", err)
+			return 0, 0, fmt.Errorf("failed to scan entry trade: %w", err)
 		}
 		entries = append(entries, e)
 	}
 	if err := entryRows.Err(); err != nil {
-		return 0, fmt.Errorf("error iterating entry trades: %w", err)
+		return 0, 0, fmt.Errorf("error iterating entry trades: %w", err)
 	}
 
 	// Get total quantity previously sold (exited) for this symbol+positionType
@@ -1374,14 +1531,14 @@ func (b *BacktestState) calculateFIFOPnL(symbol string, positionType types.Posit
 		From("trades").
 		Where(squirrel.Eq{
 			"symbol":        symbol,
-			"order_type":    types.PurchaseTypeSell,
+			"order_type":    exitSide(positionType),
 			"position_type": positionType,
 		}).
 		RunWith(b.db)
 
 	var prevSoldQty float64
 	if err := prevSoldQuery.QueryRow().Scan(&prevSoldQty); err != nil {
-		return 0, fmt.Errorf("failed to query previous sold qty: %w", err)
+		return 0, 0, fmt.Errorf("failed to query previous sold qty: %w", err)
 	}
 
 	// FIFO matching: walk through entries, skip consumed portions, match remaining
@@ -1435,8 +1592,9 @@ func (b *BacktestState) calculateFIFOPnL(symbol string, positionType types.Posit
 	}
 
 	pnl, _ := result.Float64()
+	costBasis, _ := fifoCost.Float64()
 
-	return pnl, nil
+	return pnl, costBasis, nil
 }
 
 // calculateAverageCostPnL calculates the individual PnL for a closing trade
@@ -1484,7 +1642,7 @@ func (b *BacktestState) calculateAverageCostPnL(symbol string, positionType type
 		priceDec := decimal.NewFromFloat(price)
 		feeDec := decimal.NewFromFloat(fee)
 
-		if types.PurchaseType(orderType) == types.PurchaseTypeBuy {
+		if types.PurchaseType(orderType) == entrySide(positionType) {
 			// Entry trade — add to open quantity and cost basis. Fees are
 			// capitalised into the basis following the same sign convention as
 			// calculateFIFOPnL (added for long, subtracted for short).
@@ -1527,7 +1685,7 @@ func (b *BacktestState) calculateAverageCostPnL(symbol string, positionType type
 
 	if openQty.Sign() <= 0 {
 		// Nothing open to close — defensive fallback consistent with
-		// computeClosingPnL (which is only called for true closes).
+		// computeClosingAverageCostPnL (which is only called for true closes).
 		return 0, nil
 	}
 
@@ -1595,7 +1753,7 @@ func (b *BacktestState) computeTradeAverageCost(order types.Order) (float64, err
 		priceDec := decimal.NewFromFloat(price)
 		feeDec := decimal.NewFromFloat(fee)
 
-		if orderType == types.PurchaseTypeBuy {
+		if orderType == entrySide(order.PositionType) {
 			var entryValue decimal.Decimal
 			if order.PositionType == types.PositionTypeLong {
 				entryValue = priceDec.Mul(qtyDec).Add(feeDec)
@@ -1647,7 +1805,7 @@ func (b *BacktestState) computeTradeAverageCost(order types.Order) (float64, err
 	// running average BEFORE applying the sell. Partial closes leave the
 	// average unchanged, so this matches the post-close value in that case; on
 	// a full close, it stays non-zero rather than resetting to 0.
-	if order.Side == types.PurchaseTypeSell {
+	if order.Side == exitSide(order.PositionType) {
 		if openQty.Sign() <= 0 {
 			return 0, nil
 		}
@@ -1677,7 +1835,7 @@ func (b *BacktestState) calculateTradeResult(symbol string) (types.TradeResult,
 		WITH trade_stats AS (
 			SELECT
 				COUNT(*) as total_trades,
-				SUM(CASE WHEN order_type = ? THEN 1 ELSE 0 END) as trading_pairs,
+				SUM(CASE WHEN (order_type = ? AND position_type = ?) OR (order_type = ? AND position_type = ?) THEN 1 ELSE 0 END) as trading_pairs,
 				SUM(CASE WHEN pnl > 0 THEN 1 ELSE 0 END) as winning_trades,
 				SUM(CASE WHEN pnl < 0 THEN 1 ELSE 0 END) as losing_trades,
 				MIN(pnl) as min_pnl,
@@ -1697,7 +1855,10 @@ func (b *BacktestState) calculateTradeResult(symbol string) (types.TradeResult,
 
 	var result types.TradeResult
 
-	err := b.db.QueryRow(query, types.PurchaseTypeSell, symbol).Scan(
+	err := b.db.QueryRow(query,
+		types.PurchaseTypeSell, types.PositionTypeLong, types.PurchaseTypeBuy, types.PositionTypeShort,
+		symbol,
+	).Scan(
 		&result.NumberOfTrades,
 		&result.NumberOfTradingPairs,
 		&result.NumberOfWinningTrades,
@@ -1827,12 +1988,12 @@ func (b *BacktestState) calculateTradeHoldingTime(symbol string, endTime time.Ti
 		WITH buy_trades AS (
 			SELECT executed_at, ROW_NUMBER() OVER (ORDER BY executed_at) as rn
 			FROM trades
-			WHERE symbol = ? AND order_type = ?
+			WHERE symbol = ? AND ((order_type = ? AND position_type = ?) OR (order_type = ? AND position_type = ?))
 		),
 		sell_trades AS (
 			SELECT executed_at, ROW_NUMBER() OVER (ORDER BY executed_at) as rn
 			FROM trades
-			WHERE symbol = ? AND order_type = ?
+			WHERE symbol = ? AND ((order_type = ? AND position_type = ?) OR (order_type = ? AND position_type = ?))
 		),
 		-- Closed positions: matched buy-sell pairs using FIFO
 		closed_durations AS (
@@ -1873,7 +2034,11 @@ func (b *BacktestState) calculateTradeHoldingTime(symbol string, endTime time.Ti
 	// Format endTime as ISO 8601 string for DuckDB compatibility
 	endTimeStr := endTime.Format("2006-01-02 15:04:05")
 
-	err := b.db.QueryRow(query, symbol, types.PurchaseTypeBuy, symbol, types.PurchaseTypeSell, endTimeStr).Scan(
+	err := b.db.QueryRow(query,
+		symbol, types.PurchaseTypeBuy, types.PositionTypeLong, types.PurchaseTypeSell, types.PositionTypeShort, // entries
+		symbol, types.PurchaseTypeSell, types.PositionTypeLong, types.PurchaseTypeBuy, types.PositionTypeShort, // exits
+		endTimeStr,
+	).Scan(
 		&minDuration,
 		&maxDuration,
 		&avgDuration,
@@ -1949,7 +2114,7 @@ func (b *BacktestState) calculateTradeHoldingTimeLIFO(symbol string, endTime tim
 
 		pt := types.PositionType(positionType)
 
-		if types.PurchaseType(orderType) == types.PurchaseTypeBuy {
+		if types.PurchaseType(orderType) == entrySide(pt) {
 			stacks[pt] = append(stacks[pt], lot{qty: qty, executedAt: executedAt})
 
 			continue
@@ -2098,20 +2263,20 @@ func (b *BacktestState) calculateTradePnlStats(symbol string) (median float64, p
 }
 
 // calculateTotalInvestment returns the gross capital deployed across all entry
-// trades for a symbol. Both long and short positions are recorded with
-// order_type = BUY for their entries (per engine_v1 semantics), so we sum the
-// notional (executed_qty * executed_price) of every BUY fill. This is used as
-// the denominator for PnL percentage and represents the actual capital put to
-// work — distinct from the run-wide initial cash balance.
+// trades for a symbol. Long positions are entered with BUY fills and short
+// positions with SELL fills, so we sum the notional (executed_qty *
+// executed_price) of every entry fill. This is used as the denominator for PnL
+// percentage and represents the actual capital put to work — distinct from the
+// run-wide initial cash balance.
 func (b *BacktestState) calculateTotalInvestment(symbol string) (float64, error) {
 	query := `
 		SELECT COALESCE(SUM(executed_qty * executed_price), 0)
 		FROM trades
-		WHERE symbol = ? AND order_type = ?
+		WHERE symbol = ? AND ((order_type = ? AND position_type = ?) OR (order_type = ? AND position_type = ?))
 	`
 
 	var totalInvestment float64
-	if err := b.db.QueryRow(query, symbol, types.PurchaseTypeBuy).Scan(&totalInvestment); err != nil {
+	if err := b.db.QueryRow(query, symbol, types.PurchaseTypeBuy, types.PositionTypeLong, types.PurchaseTypeSell, types.PositionTypeShort).Scan(&totalInvestment); err != nil {
 		return 0, fmt.Errorf("failed to calculate total investment: %w", err)
 	}
 
@@ -2121,14 +2286,15 @@ func (b *BacktestState) calculateTotalInvestment(symbol string) (float64, error)
 // calculateMonthlyTradeStats returns per-month trade activity for a symbol.
 // Months are formatted as YYYY-MM and ordered chronologically. NumberOfTrades
 // counts every fill executed in the month (entries and exits). NumberOfTradingPairs
-// counts closing trades (sell trades for long positions) and is also the
+// counts closing trades (sells for long positions, covering buys for short
+// positions) and is also the
 // denominator for win/lose counts which use the per-trade pnl sign.
 func (b *BacktestState) calculateMonthlyTradeStats(symbol string) ([]types.MonthlyTradeStats, error) {
 	query := `
 		SELECT
 			strftime(date_trunc('month', executed_at), '%Y-%m') as month,
 			COUNT(*) as total_trades,
-			SUM(CASE WHEN order_type = ? THEN 1 ELSE 0 END) as trading_pairs,
+			SUM(CASE WHEN (order_type = ? AND position_type = ?) OR (order_type = ? AND position_type = ?) THEN 1 ELSE 0 END) as trading_pairs,
 			SUM(CASE WHEN pnl > 0 THEN 1 ELSE 0 END) as winning_trades,
 			SUM(CASE WHEN pnl < 0 THEN 1 ELSE 0 END) as losing_trades
 		FROM trades
@@ -2137,7 +2303,10 @@ func (b *BacktestState) calculateMonthlyTradeStats(symbol string) ([]types.Month
 		ORDER BY month
 	`
 
-	rows, err := b.db.Query(query, types.PurchaseTypeSell, symbol)
+	rows, err := b.db.Query(query,
+		types.PurchaseTypeSell, types.PositionTypeLong, types.PurchaseTypeBuy, types.PositionTypeShort,
+		symbol,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query monthly trade stats: %w", err)
 	}
@@ -2297,6 +2466,23 @@ func (b *BacktestState) calculateTotalFees(symbol string) (float64, error) {
 	return totalFees, nil
 }
 
+// GetTotalFees returns the commission charged on all trades across all symbols.
+func (b *BacktestState) GetTotalFees() (float64, error) {
+	query := b.sq.
+		Select("COALESCE(SUM(commission), 0)").
+		From("trades").
+		RunWith(b.db)
+
+	var totalFees float64
+
+	err := query.QueryRow().Scan(&totalFees)
+	if err != nil {
+		return 0, fmt.Errorf("failed to calculate total fees: %w", err)
+	}
+
+	return totalFees, nil
+}
+
 // calculateBuyAndHoldPnL calculates the buy-and-hold PnL for a symbol.
 func (b *BacktestState) calculateBuyAndHoldPnL(symbol string, ds datasource.DataSource) (float64, error) {
 	// Find the first trade for this symbol
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// equityCurveBatchSize is the number of equity curve rows buffered before they
+// are inserted.
+const equityCurveBatchSize = 1000
+
+// RecordEquityCurve values the account at time t the way ValueEquity does and
+// appends the snapshot to the equity curve. With a positive interval the curve
+// keeps one row per interval: a snapshot in the same interval as the last row
+// replaces it, so each row holds the last state of its interval. Rows are
+// buffered and inserted in batches; GetEquityCurve and WriteFormats see them all.
+func (b *BacktestState) RecordEquityCurve(t time.Time, priceOf func(symbol string) float64, interval time.Duration) (types.EquitySnapshot, error) {
+	snapshot, err := b.ValueEquity(t, priceOf)
+	if err != nil {
+		return types.EquitySnapshot{}, err
+	}
+
+	sameInterval := interval > 0 && !b.lastEquityCurveTime.IsZero() &&
+		snapshot.Time.Truncate(interval).Equal(b.lastEquityCurveTime.Truncate(interval))
+
+	switch {
+	case sameInterval && len(b.equityCurveBuffer) > 0:
+		b.equityCurveBuffer[len(b.equityCurveBuffer)-1] = snapshot
+	case sameInterval:
+		// The last row of the interval was already inserted
+		_, err := b.sq.Update(equityCurveTable).
+			Set("time", snapshot.Time).
+			Set("balance", snapshot.Balance).
+			Set("position_value", snapshot.PositionValue).
+			Set("equity", snapshot.Equity).
+			Set("unrealized_pnl", snapshot.UnrealizedPnL).
+			Set("realized_pnl", snapshot.RealizedPnL).
+			Where(squirrel.Eq{"time": b.lastEquityCurveTime}).
+			RunWith(b.db).
+			Exec()
+		if err != nil {
+			return types.EquitySnapshot{}, fmt.Errorf("failed to update equity curve: %w", err)
+		}
+	default:
+		b.equityCurveBuffer = append(b.equityCurveBuffer, snapshot)
+		if len(b.equityCurveBuffer) >= equityCurveBatchSize {
+			if err := b.flushEquityCurve(); err != nil {
+				return types.EquitySnapshot{}, err
+			}
+		}
+	}
+
+	b.lastEquityCurveTime = snapshot.Time
+
+	return snapshot, nil
+}
+
+// flushEquityCurve inserts the buffered equity curve rows.
+func (b *BacktestState) flushEquityCurve() error {
+	if err := b.insertEquitySnapshots(equityCurveTable, b.equityCurveBuffer); err != nil {
+		return err
+	}
+
+	b.equityCurveBuffer = b.equityCurveBuffer[:0]
+
+	return nil
+}
+
+// GetEquityCurve returns the equity curve rows within the time range in time order.
+func (b *BacktestState) GetEquityCurve(filter types.TimeRange) ([]types.EquitySnapshot, error) {
+	if err := b.flushEquityCurve(); err != nil {
+		return nil, err
+	}
+
+	return b.queryEquitySnapshots(equityCurveTable, filter)
+}
@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+const (
+	// equitySnapshotsTable holds the equity snapshots taken at the mark-to-market times.
+	equitySnapshotsTable = "equity_snapshots"
+	// equityCurveTable holds the equity snapshot taken after every bar.
+	equityCurveTable = "equity_curve"
+)
+
+// equityColumns are the columns of every equity table, in the order of the
+// types.EquitySnapshot fields.
+var equityColumns = []string{"time", "balance", "position_value", "equity", "unrealized_pnl", "realized_pnl"}
+
+// createEquityTable creates a table holding equity snapshots.
+func (b *BacktestState) createEquityTable(table string) error {
+	_, err := b.db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			time TIMESTAMP,
+			balance DOUBLE,
+			position_value DOUBLE,
+			equity DOUBLE,
+			unrealized_pnl DOUBLE,
+			realized_pnl DOUBLE
+		)
+	`, table))
+	if err != nil {
+		return fmt.Errorf("failed to create %s table: %w", table, err)
+	}
+
+	return nil
+}
+
+// GetCashBalance returns the cash balance after the most recent trade, or the
+// initial balance when nothing has traded yet, plus the funding payments so far.
+func (b *BacktestState) GetCashBalance() (float64, error) {
+	funding, err := b.GetTotalFunding()
+	if err != nil {
+		return 0, err
+	}
+
+	return b.tradeBalance() + funding, nil
+}
+
+// tradeBalance returns the cash balance after the most recent trade, or the
+// initial balance when nothing has traded yet.
+func (b *BacktestState) tradeBalance() float64 {
+	if !b.hasTraded {
+		return b.initialBalance
+	}
+
+	return b.lastTradeBalance
+}
+
+// ValueEquity values the account at time t: the cash balance plus the market
+// value of the open positions, each at the price priceOf returns for its
+// symbol, or at its average entry price when that is 0. This is the equity
+// GetAccountInfo reports, so every equity series agrees with it. It reads the
+// in-memory mirrors of the trades because the curve values the account on
+// every bar.
+func (b *BacktestState) ValueEquity(t time.Time, priceOf func(symbol string) float64) (types.EquitySnapshot, error) {
+	cash, err := b.GetCashBalance()
+	if err != nil {
+		return types.EquitySnapshot{}, err
+	}
+
+	funding, err := b.GetTotalFunding()
+	if err != nil {
+		return types.EquitySnapshot{}, err
+	}
+
+	positions := b.openCachedPositions()
+	realizedPnL, unrealizedPnL, _ := valuePositions(positions, priceOf)
+	positionValue := positionsValue(positions, priceOf)
+
+	return types.EquitySnapshot{
+		Time:          t,
+		Balance:       cash,
+		PositionValue: positionValue,
+		Equity:        cash + positionValue,
+		UnrealizedPnL: unrealizedPnL,
+		RealizedPnL:   realizedPnL + funding,
+	}, nil
+}
+
+// RecordEquitySnapshot values the account at the given prices, stores the
+// resulting snapshot for time t and returns it.
+func (b *BacktestState) RecordEquitySnapshot(t time.Time, prices map[string]float64) (types.EquitySnapshot, error) {
+	snapshot, err := b.ValueEquity(t, func(symbol string) float64 {
+		return prices[symbol]
+	})
+	if err != nil {
+		return types.EquitySnapshot{}, err
+	}
+
+	if err := b.insertEquitySnapshot(equitySnapshotsTable, snapshot); err != nil {
+		return types.EquitySnapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// GetEquitySnapshots returns all recorded mark-to-market snapshots in time order.
+func (b *BacktestState) GetEquitySnapshots() ([]types.EquitySnapshot, error) {
+	return b.queryEquitySnapshots(equitySnapshotsTable, types.TimeRange{})
+}
+
+// insertEquitySnapshot appends the snapshot to the equity table.
+func (b *BacktestState) insertEquitySnapshot(table string, snapshot types.EquitySnapshot) error {
+	return b.insertEquitySnapshots(table, []types.EquitySnapshot{snapshot})
+}
+
+// insertEquitySnapshots appends the snapshots to the equity table in one transaction.
+func (b *BacktestState) insertEquitySnapshots(table string, snapshots []types.EquitySnapshot) error {
+	rows := make([][]any, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		rows = append(rows, []any{snapshot.Time, snapshot.Balance, snapshot.PositionValue, snapshot.Equity, snapshot.UnrealizedPnL, snapshot.RealizedPnL})
+	}
+
+	return insertRows(b.db, table, rows)
+}
+
+// queryEquitySnapshots returns the snapshots of the equity table within the
+// time range in time order.
+func (b *BacktestState) queryEquitySnapshots(table string, filter types.TimeRange) ([]types.EquitySnapshot, error) {
+	query := b.sq.Select(equityColumns...).
+		From(table).
+		OrderBy("time ASC")
+
+	if !filter.Start.IsZero() {
+		query = query.Where(squirrel.GtOrEq{"time": filter.Start})
+	}
+
+	if !filter.End.IsZero() {
+		query = query.Where(squirrel.LtOrEq{"time": filter.End})
+	}
+
+	rows, err := query.RunWith(b.db).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var snapshots []types.EquitySnapshot
+
+	for rows.Next() {
+		var snapshot types.EquitySnapshot
+		if err := rows.Scan(&snapshot.Time, &snapshot.Balance, &snapshot.PositionValue, &snapshot.Equity, &snapshot.UnrealizedPnL, &snapshot.RealizedPnL); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %s: %w", table, err)
+	}
+
+	return snapshots, nil
+}
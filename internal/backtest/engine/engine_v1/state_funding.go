@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// FundingPayment is a funding payment of a perpetual futures position.
+// Amount is positive when the position received funding and negative when it paid.
+type FundingPayment struct {
+	Symbol string
+	Time   time.Time
+	Rate   float64
+	// Quantity is the net position quantity at the funding time, negative for a short position.
+	Quantity float64
+	Price    float64
+	Amount   float64
+}
+
+// createFundingPaymentsTable creates the table holding funding payments.
+func (b *BacktestState) createFundingPaymentsTable() error {
+	_, err := b.db.Exec(`
+		CREATE TABLE IF NOT EXISTS funding_payments (
+			symbol TEXT,
+			time TIMESTAMP,
+			rate DOUBLE,
+			quantity DOUBLE,
+			price DOUBLE,
+			amount DOUBLE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create funding_payments table: %w", err)
+	}
+
+	return nil
+}
+
+// RecordFundingPayment stores the payment and adds it to the realized PnL.
+func (b *BacktestState) RecordFundingPayment(payment FundingPayment) error {
+	_, err := b.sq.Insert("funding_payments").
+		Columns("symbol", "time", "rate", "quantity", "price", "amount").
+		Values(payment.Symbol, payment.Time, payment.Rate, payment.Quantity, payment.Price, payment.Amount).
+		RunWith(b.db).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to insert funding payment: %w", err)
+	}
+
+	b.realizedPnL += payment.Amount
+	b.totalFunding += payment.Amount
+
+	return nil
+}
+
+// GetTotalFunding returns the sum of all funding payments of the run.
+func (b *BacktestState) GetTotalFunding() (float64, error) {
+	return b.totalFunding, nil
+}
+
+// GetFundingPayments returns all funding payments in time order.
+func (b *BacktestState) GetFundingPayments() ([]FundingPayment, error) {
+	rows, err := b.sq.Select("symbol", "time", "rate", "quantity", "price", "amount").
+		From("funding_payments").
+		OrderBy("time ASC").
+		RunWith(b.db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query funding payments: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []FundingPayment
+
+	for rows.Next() {
+		var payment FundingPayment
+		if err := rows.Scan(&payment.Symbol, &payment.Time, &payment.Rate, &payment.Quantity, &payment.Price, &payment.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan funding payment: %w", err)
+		}
+
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating funding payments: %w", err)
+	}
+
+	return payments, nil
+}
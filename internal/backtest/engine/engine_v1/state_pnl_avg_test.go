@@ -207,13 +207,13 @@ func (suite *BacktestStateTestSuite) TestAverageCostPnL_ShortPosition() {
 			name: "Single short entry and exit",
 			orders: []types.Order{
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 100.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 100.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 90.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 90.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close"},
@@ -228,25 +228,25 @@ func (suite *BacktestStateTestSuite) TestAverageCostPnL_ShortPosition() {
 			name: "Two short entries at different prices - average basis",
 			orders: []types.Order{
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 200.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 200.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open1"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 100.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 100.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open2"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 120.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 120.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close1"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 120.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 120.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close2"},
@@ -370,3 +370,91 @@ func (suite *BacktestStateTestSuite) TestPortfolioCalculationStrategy_DefaultAnd
 	state.SetPortfolioCalculationStrategy(PortfolioCalculationFIFO)
 	suite.Assert().Equal(PortfolioCalculationFIFO, state.PortfolioCalculationStrategy())
 }
+
+// TestPositionAverageEntryPrice verifies that positions carry the weighted
+// average entry price and cost of their open lots on both sides, that exits
+// leave the average unchanged, and that positions recomputed from the trades
+// table match the cached ones.
+func (suite *BacktestStateTestSuite) TestPositionAverageEntryPrice() {
+	type step struct {
+		side         types.PurchaseType
+		qty          float64
+		price        float64
+		expectedAvg  float64
+		expectedCost float64
+		expectedQty  float64
+	}
+
+	tests := []struct {
+		name         string
+		positionType types.PositionType
+		steps        []step
+	}{
+		{
+			name:         "Long position",
+			positionType: types.PositionTypeLong,
+			steps: []step{
+				{side: types.PurchaseTypeBuy, qty: 100, price: 90, expectedAvg: 90, expectedCost: 9000, expectedQty: 100},
+				{side: types.PurchaseTypeBuy, qty: 100, price: 110, expectedAvg: 100, expectedCost: 20000, expectedQty: 200},
+				{side: types.PurchaseTypeSell, qty: 50, price: 120, expectedAvg: 100, expectedCost: 15000, expectedQty: 150},
+				{side: types.PurchaseTypeSell, qty: 150, price: 80, expectedAvg: 0, expectedCost: 0, expectedQty: 0},
+				// A new position does not inherit the closed lots
+				{side: types.PurchaseTypeBuy, qty: 10, price: 130, expectedAvg: 130, expectedCost: 1300, expectedQty: 10},
+			},
+		},
+		{
+			name:         "Short position",
+			positionType: types.PositionTypeShort,
+			steps: []step{
+				{side: types.PurchaseTypeSell, qty: 100, price: 90, expectedAvg: 90, expectedCost: 9000, expectedQty: 100},
+				{side: types.PurchaseTypeSell, qty: 100, price: 110, expectedAvg: 100, expectedCost: 20000, expectedQty: 200},
+				{side: types.PurchaseTypeBuy, qty: 50, price: 80, expectedAvg: 100, expectedCost: 15000, expectedQty: 150},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			suite.Require().NoError(suite.state.Cleanup())
+
+			for i, s := range tc.steps {
+				_, err := suite.state.Update([]types.Order{{
+					Symbol: "AAPL", Side: s.side, Quantity: s.qty, Price: s.price, Fee: 1,
+					Timestamp: time.Date(2024, 1, 1, 10+i, 0, 0, 0, time.UTC), IsCompleted: true,
+					PositionType: tc.positionType, StrategyName: "test",
+					Reason: types.Reason{Reason: "test", Message: "test"},
+				}})
+				suite.Require().NoError(err)
+
+				cached, err := suite.state.GetPosition("AAPL")
+				suite.Require().NoError(err)
+
+				avg, cost, quantity := cached.AverageLongEntryPrice, cached.TotalLongCost, cached.TotalLongPositionQuantity
+				if tc.positionType == types.PositionTypeShort {
+					avg, cost, quantity = cached.AverageShortEntryPrice, cached.TotalShortCost, cached.TotalShortPositionQuantity
+				}
+
+				suite.Assert().InDelta(s.expectedQty, quantity, 1e-9, "quantity mismatch at step %d", i)
+				suite.Assert().InDelta(s.expectedAvg, avg, 1e-9, "average entry price mismatch at step %d", i)
+				suite.Assert().InDelta(s.expectedCost, cost, 1e-9, "total cost mismatch at step %d", i)
+
+				suite.state.resetPositionCache()
+
+				recomputed, err := suite.state.GetPosition("AAPL")
+				suite.Require().NoError(err)
+				suite.Assert().Equal(cached, recomputed, "recomputed position mismatch at step %d", i)
+			}
+
+			positions, err := suite.state.GetAllPositions()
+			suite.Require().NoError(err)
+			suite.Require().Len(positions, 1)
+
+			position, err := suite.state.GetPosition("AAPL")
+			suite.Require().NoError(err)
+			suite.Assert().Equal(position.AverageLongEntryPrice, positions[0].AverageLongEntryPrice)
+			suite.Assert().Equal(position.TotalLongCost, positions[0].TotalLongCost)
+			suite.Assert().Equal(position.AverageShortEntryPrice, positions[0].AverageShortEntryPrice)
+			suite.Assert().Equal(position.TotalShortCost, positions[0].TotalShortCost)
+		})
+	}
+}
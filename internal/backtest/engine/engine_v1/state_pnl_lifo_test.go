@@ -163,13 +163,13 @@ func (suite *BacktestStateTestSuite) TestLIFOPnL_ShortPosition() {
 			name: "Single short entry and exit",
 			orders: []types.Order{
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 100.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 100.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 90.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 90.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close"},
@@ -184,20 +184,20 @@ func (suite *BacktestStateTestSuite) TestLIFOPnL_ShortPosition() {
 			name: "Two short entries then exit - LIFO matches last buy first",
 			orders: []types.Order{
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 100.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 100.0,
 					Fee: 0.0, Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open1"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 110.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 110.0,
 					Fee: 0.0, Timestamp: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open2"},
 				},
 				{
 					// Sell 100 @ 95 -> LIFO matches latest open (100@110)
-					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 95.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 95.0,
 					Fee: 0.0, Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close1"},
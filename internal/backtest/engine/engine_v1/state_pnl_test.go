@@ -204,13 +204,13 @@ func (suite *BacktestStateTestSuite) TestFIFOPnL_ShortPosition() {
 			name: "Single short entry and exit",
 			orders: []types.Order{
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 100.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 100.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 90.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 90.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close"},
@@ -225,25 +225,25 @@ func (suite *BacktestStateTestSuite) TestFIFOPnL_ShortPosition() {
 			name: "Multiple short entries at different prices, FIFO matches first",
 			orders: []types.Order{
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 200.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 200.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open1"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 100.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 100.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open2"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 120.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 120.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close1"},
 				},
 				{
-					Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 120.0,
+					Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 120.0,
 					Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
 					IsCompleted: true, PositionType: types.PositionTypeShort,
 					StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close2"},
@@ -337,25 +337,25 @@ func (suite *BacktestStateTestSuite) TestFIFOPnL_TotalConsistency() {
 
 		orders := []types.Order{
 			{
-				Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 200.0,
+				Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 200.0,
 				Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 				IsCompleted: true, PositionType: types.PositionTypeShort,
 				StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open1"},
 			},
 			{
-				Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 100.0,
+				Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 100.0,
 				Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
 				IsCompleted: true, PositionType: types.PositionTypeShort,
 				StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "open2"},
 			},
 			{
-				Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 120.0,
+				Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 120.0,
 				Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
 				IsCompleted: true, PositionType: types.PositionTypeShort,
 				StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close1"},
 			},
 			{
-				Symbol: "AAPL", Side: types.PurchaseTypeSell, Quantity: 100, Price: 120.0,
+				Symbol: "AAPL", Side: types.PurchaseTypeBuy, Quantity: 100, Price: 120.0,
 				Fee: 1.0, Timestamp: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
 				IsCompleted: true, PositionType: types.PositionTypeShort,
 				StrategyName: "test", Reason: types.Reason{Reason: "test", Message: "close2"},
@@ -423,3 +423,72 @@ func (suite *BacktestStateTestSuite) TestCumulativePnL_RunningSum() {
 		}
 	})
 }
+
+// TestRealizedPnL_FIFOLots verifies that each closing trade records the PnL and
+// cost basis of the oldest entry lots it was matched against, under both
+// portfolio calculation strategies.
+func (suite *BacktestStateTestSuite) TestRealizedPnL_FIFOLots() {
+	prev := suite.state.PortfolioCalculationStrategy()
+	defer suite.state.SetPortfolioCalculationStrategy(prev)
+
+	mkOrder := func(side types.PurchaseType, qty, price float64, hour int, msg string) types.Order {
+		return types.Order{
+			Symbol: "AAPL", Side: side, Quantity: qty, Price: price, Fee: 0,
+			Timestamp: time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC), IsCompleted: true,
+			PositionType: types.PositionTypeLong,
+			StrategyName: "test", Reason: types.Reason{Reason: "test", Message: msg},
+		}
+	}
+
+	orders := []types.Order{
+		mkOrder(types.PurchaseTypeBuy, 100, 90, 10, "buy1"),
+		mkOrder(types.PurchaseTypeBuy, 100, 100, 11, "buy2"),
+		// Matches all 100 @ 90 and 50 @ 100: 16500 - (9000 + 5000) = 2500
+		mkOrder(types.PurchaseTypeSell, 150, 110, 12, "sell1"),
+		// Matches the remaining 50 @ 100: 6000 - 5000 = 1000
+		mkOrder(types.PurchaseTypeSell, 50, 120, 13, "sell2"),
+	}
+
+	tests := []struct {
+		name        string
+		strategy    PortfolioCalculationStrategy
+		expectedPnL []float64
+	}{
+		{
+			name:        "FIFO strategy",
+			strategy:    PortfolioCalculationFIFO,
+			expectedPnL: []float64{0, 0, 2500, 1000},
+		},
+		{
+			name:        "Average cost strategy keeps FIFO realized PnL",
+			strategy:    PortfolioCalculationAverageCost,
+			expectedPnL: []float64{0, 0, 2250, 1250},
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			err := suite.state.Cleanup()
+			suite.Require().NoError(err)
+			suite.state.SetPortfolioCalculationStrategy(tc.strategy)
+
+			for _, order := range orders {
+				_, err := suite.state.Update([]types.Order{order})
+				suite.Require().NoError(err)
+			}
+
+			page, err := suite.state.GetTradesPage(types.TradeFilter{})
+			suite.Require().NoError(err)
+			suite.Require().Len(page.Trades, 4)
+
+			expectedRealized := []float64{0, 0, 2500, 1000}
+			expectedCostBasis := []float64{0, 0, 14000, 5000}
+
+			for i, trade := range page.Trades {
+				suite.Assert().InDelta(expectedRealized[i], trade.RealizedPnL, 0.0001, "RealizedPnL mismatch at trade %d", i)
+				suite.Assert().InDelta(expectedCostBasis[i], trade.CostBasis, 0.0001, "CostBasis mismatch at trade %d", i)
+				suite.Assert().InDelta(tc.expectedPnL[i], trade.PnL, 0.0001, "PnL mismatch at trade %d", i)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 )
 
@@ -29,6 +31,11 @@ func (b *BacktestState) applyTradeToCache(order types.Order) {
 		pos.OpenTimestamp = order.Timestamp
 	}
 
+	applyTradeToPosition(pos, order)
+}
+
+// applyTradeToPosition adds a trade to the aggregates of pos.
+func applyTradeToPosition(pos *types.Position, order types.Order) {
 	qty := order.Quantity
 	amount := qty * order.Price
 	fee := order.Fee
@@ -43,13 +50,13 @@ func (b *BacktestState) applyTradeToCache(order types.Order) {
 		pos.TotalLongOutPositionAmount += amount
 		pos.TotalLongOutFee += fee
 	case order.Side == types.PurchaseTypeSell && order.PositionType == types.PositionTypeShort:
-		pos.TotalShortOutPositionQuantity += qty
-		pos.TotalShortOutPositionAmount += amount
-		pos.TotalShortOutFee += fee
-	case order.Side == types.PurchaseTypeBuy && order.PositionType == types.PositionTypeShort:
 		pos.TotalShortInPositionQuantity += qty
 		pos.TotalShortInPositionAmount += amount
 		pos.TotalShortInFee += fee
+	case order.Side == types.PurchaseTypeBuy && order.PositionType == types.PositionTypeShort:
+		pos.TotalShortOutPositionQuantity += qty
+		pos.TotalShortOutPositionAmount += amount
+		pos.TotalShortOutFee += fee
 	}
 
 	// Match SQL MAX(strategy_name) (alphabetical max across all trades).
@@ -59,6 +66,57 @@ func (b *BacktestState) applyTradeToCache(order types.Order) {
 
 	pos.TotalLongPositionQuantity = pos.TotalLongInPositionQuantity - pos.TotalLongOutPositionQuantity
 	pos.TotalShortPositionQuantity = pos.TotalShortInPositionQuantity - pos.TotalShortOutPositionQuantity
+
+	if order.PositionType == types.PositionTypeShort {
+		applyEntryCost(&pos.TotalShortCost, &pos.AverageShortEntryPrice,
+			order.Side == types.PurchaseTypeSell, amount, pos.TotalShortPositionQuantity)
+	} else {
+		applyEntryCost(&pos.TotalLongCost, &pos.AverageLongEntryPrice,
+			order.Side == types.PurchaseTypeBuy, amount, pos.TotalLongPositionQuantity)
+	}
+}
+
+// applyEntryCost updates the cost and weighted average entry price of one
+// side of a position after a trade left openQty open on that side. Entries add
+// their amount to the cost, while exits release the closed lots at the average
+// entry price so the average of the remaining lots is unchanged. A side that
+// goes flat starts over.
+func applyEntryCost(totalCost, averagePrice *float64, entry bool, amount, openQty float64) {
+	switch {
+	case openQty <= lotTolerance:
+		*totalCost = 0
+		*averagePrice = 0
+
+		return
+	case entry:
+		*totalCost += amount
+	default:
+		*totalCost = *averagePrice * openQty
+	}
+
+	*averagePrice = *totalCost / openQty
+}
+
+// openCachedPositions returns the cached positions that are open on either
+// side, ordered by symbol like GetAllPositions. Every committed trade is
+// mirrored in the cache, so this answers without aggregating the trades table,
+// which matters to the callers that value the account on every bar.
+func (b *BacktestState) openCachedPositions() []types.Position {
+	b.positionCacheMu.Lock()
+	defer b.positionCacheMu.Unlock()
+
+	positions := make([]types.Position, 0, len(b.positionCache))
+	for _, pos := range b.positionCache {
+		if pos.TotalLongPositionQuantity != 0 || pos.TotalShortPositionQuantity != 0 {
+			positions = append(positions, *pos)
+		}
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Symbol < positions[j].Symbol
+	})
+
+	return positions
 }
 
 // resetPositionCache discards all cached positions. Called when the underlying
@@ -89,6 +147,10 @@ func newEmptyPosition(symbol string) *types.Position {
 		TotalLongOutFee:               0,
 		TotalShortInFee:               0,
 		TotalShortOutFee:              0,
+		AverageLongEntryPrice:         0,
+		TotalLongCost:                 0,
+		AverageShortEntryPrice:        0,
+		TotalShortCost:                0,
 		OpenTimestamp:                 time.Time{},
 		StrategyName:                  "",
 	}
@@ -96,7 +158,7 @@ func newEmptyPosition(symbol string) *types.Position {
 
 // getPositionFromDB recomputes a Position from the trades table via SQL.
 func (b *BacktestState) getPositionFromDB(symbol string) (types.Position, error) {
-	// Extended CTEs to calculate both long (buy/sell) and short (sell/buy) position fields
+	// Extended CTEs to calculate both long (buy in, sell out) and short (sell in, buy out) position fields
 	query := `
     WITH long_buy_trades AS (
        SELECT
@@ -116,17 +178,17 @@ func (b *BacktestState) getPositionFromDB(symbol string) (types.Position, error)
     ),
     short_sell_trades AS (
        SELECT
-          SUM(executed_qty) as total_out_short_qty,
-          SUM(commission) as total_short_out_fee,
-          SUM(executed_qty * executed_price) as total_short_out_amount
+          SUM(executed_qty) as total_short_in_qty,
+          SUM(commission) as total_short_in_fee,
+          SUM(executed_qty * executed_price) as total_short_in_amount
        FROM trades
        WHERE symbol = ? AND order_type = ? AND position_type = ?
     ),
     short_buy_trades AS (
        SELECT
-          SUM(executed_qty) as total_short_in_qty,
-          SUM(commission) as total_short_in_fee,
-          SUM(executed_qty * executed_price) as total_short_in_amount
+          SUM(executed_qty) as total_out_short_qty,
+          SUM(commission) as total_short_out_fee,
+          SUM(executed_qty * executed_price) as total_short_out_amount
        FROM trades
        WHERE symbol = ? AND order_type = ? AND position_type = ?
     ),
@@ -145,15 +207,15 @@ func (b *BacktestState) getPositionFromDB(symbol string) (types.Position, error)
        COALESCE(s.total_long_out_amount, 0) as total_out_long_position_amount,
        COALESCE(b.total_long_in_fee, 0) as total_long_in_fee,
        COALESCE(s.total_long_out_fee, 0)  as total_long_out_fee,
-       COALESCE(sb.total_short_in_fee, 0) as total_short_in_fee,
-       COALESCE(ss.total_short_out_fee, 0)  as total_short_out_fee,
+       COALESCE(ss.total_short_in_fee, 0) as total_short_in_fee,
+       COALESCE(sb.total_short_out_fee, 0)  as total_short_out_fee,
        ft.first_trade_time as open_timestamp,
        MAX(t.strategy_name) as strategy_name,
-       COALESCE(sb.total_short_in_qty, 0) as total_in_short_position_quantity,
-       COALESCE(ss.total_out_short_qty, 0) as total_out_short_position_quantity,
-       COALESCE(sb.total_short_in_amount, 0) as total_in_short_position_amount,
-       COALESCE(ss.total_short_out_amount, 0) as total_out_short_position_amount,
-       COALESCE(sb.total_short_in_qty, 0) - COALESCE(ss.total_out_short_qty, 0) as short_quantity
+       COALESCE(ss.total_short_in_qty, 0) as total_in_short_position_quantity,
+       COALESCE(sb.total_out_short_qty, 0) as total_out_short_position_quantity,
+       COALESCE(ss.total_short_in_amount, 0) as total_in_short_position_amount,
+       COALESCE(sb.total_short_out_amount, 0) as total_out_short_position_amount,
+       COALESCE(ss.total_short_in_qty, 0) - COALESCE(sb.total_out_short_qty, 0) as short_quantity
     FROM trades t
     LEFT JOIN long_buy_trades b ON 1=1
     LEFT JOIN long_sell_trades s ON 1=1
@@ -161,7 +223,7 @@ func (b *BacktestState) getPositionFromDB(symbol string) (types.Position, error)
     LEFT JOIN short_buy_trades sb ON 1=1
     CROSS JOIN first_trade ft
     WHERE t.symbol = ?
-    GROUP BY b.total_long_in_qty, s.total_long_out_qty, b.total_long_in_amount, s.total_long_out_amount, b.total_long_in_fee, s.total_long_out_fee, sb.total_short_in_fee, ss.total_short_out_fee, ss.total_out_short_qty, sb.total_short_in_qty, ss.total_short_out_amount, sb.total_short_in_amount, ss.total_short_out_fee, sb.total_short_in_fee, ft.first_trade_time
+    GROUP BY b.total_long_in_qty, s.total_long_out_qty, b.total_long_in_amount, s.total_long_out_amount, b.total_long_in_fee, s.total_long_out_fee, ss.total_short_in_fee, sb.total_short_out_fee, sb.total_out_short_qty, ss.total_short_in_qty, sb.total_short_out_amount, ss.total_short_in_amount, sb.total_short_out_fee, ss.total_short_in_fee, ft.first_trade_time
     `
 
 	args := []interface{}{
@@ -203,5 +265,63 @@ func (b *BacktestState) getPositionFromDB(symbol string) (types.Position, error)
 		return types.Position{}, fmt.Errorf("failed to query position: %w", err)
 	}
 
+	if err := b.loadEntryCosts([]*types.Position{&position}); err != nil {
+		return types.Position{}, err
+	}
+
 	return position, nil
 }
+
+// loadEntryCosts fills the entry cost fields of positions recomputed via SQL
+// by replaying their symbols' trades in execution order, so they match the
+// fields applyTradeToPosition maintains on cached positions.
+func (b *BacktestState) loadEntryCosts(positions []*types.Position) error {
+	if len(positions) == 0 {
+		return nil
+	}
+
+	replayed := make(map[string]*types.Position, len(positions))
+	symbols := make([]string, 0, len(positions))
+
+	for _, position := range positions {
+		replayed[position.Symbol] = newEmptyPosition(position.Symbol)
+		symbols = append(symbols, position.Symbol)
+	}
+
+	rows, err := b.sq.
+		Select("symbol", "order_type", "position_type", "executed_qty", "executed_price").
+		From("trades").
+		Where(squirrel.Eq{"symbol": symbols}).
+		OrderBy("executed_at ASC", "rowid ASC").
+		RunWith(b.db).
+		Query()
+	if err != nil {
+		return fmt.Errorf("failed to query trades for position costs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		//nolint:exhaustruct // only the fields positions aggregate are read
+		order := types.Order{}
+
+		if err := rows.Scan(&order.Symbol, &order.Side, &order.PositionType, &order.Quantity, &order.Price); err != nil {
+			return fmt.Errorf("failed to scan trade for position costs: %w", err)
+		}
+
+		applyTradeToPosition(replayed[order.Symbol], order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating trades for position costs: %w", err)
+	}
+
+	for _, position := range positions {
+		costs := replayed[position.Symbol]
+		position.AverageLongEntryPrice = costs.AverageLongEntryPrice
+		position.TotalLongCost = costs.TotalLongCost
+		position.AverageShortEntryPrice = costs.AverageShortEntryPrice
+		position.TotalShortCost = costs.TotalShortCost
+	}
+
+	return nil
+}
@@ -26,7 +26,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:      "order1",
 					Symbol:       "AAPL",
-					Side:         types.PurchaseTypeBuy,
+					Side:         types.PurchaseTypeSell,
 					Quantity:     100,
 					Price:        100.0,
 					Fee:          1.0,
@@ -45,7 +45,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 					Order: types.Order{
 						OrderID:      "order1",
 						Symbol:       "AAPL",
-						Side:         types.PurchaseTypeBuy,
+						Side:         types.PurchaseTypeSell,
 						Quantity:     100,
 						Price:        100.0,
 						Timestamp:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
@@ -80,7 +80,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:      "order1",
 					Symbol:       "AAPL",
-					Side:         types.PurchaseTypeBuy,
+					Side:         types.PurchaseTypeSell,
 					Quantity:     100,
 					Price:        100.0,
 					Fee:          1.0,
@@ -96,7 +96,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:      "order2",
 					Symbol:       "AAPL",
-					Side:         types.PurchaseTypeSell,
+					Side:         types.PurchaseTypeBuy,
 					Quantity:     100,
 					Price:        110.0,
 					Fee:          1.0,
@@ -115,7 +115,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 					Order: types.Order{
 						OrderID:      "order1",
 						Symbol:       "AAPL",
-						Side:         types.PurchaseTypeBuy,
+						Side:         types.PurchaseTypeSell,
 						Quantity:     100,
 						Price:        100.0,
 						Timestamp:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
@@ -138,7 +138,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 					Order: types.Order{
 						OrderID:      "order2",
 						Symbol:       "AAPL",
-						Side:         types.PurchaseTypeSell,
+						Side:         types.PurchaseTypeBuy,
 						Quantity:     100,
 						Price:        110.0,
 						Timestamp:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
@@ -173,7 +173,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:      "order1",
 					Symbol:       "AAPL",
-					Side:         types.PurchaseTypeBuy,
+					Side:         types.PurchaseTypeSell,
 					Quantity:     100,
 					Price:        100.0,
 					Fee:          1.0,
@@ -189,7 +189,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:      "order2",
 					Symbol:       "AAPL",
-					Side:         types.PurchaseTypeSell,
+					Side:         types.PurchaseTypeBuy,
 					Quantity:     50,
 					Price:        110.0,
 					Fee:          1.0,
@@ -207,7 +207,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 					Order: types.Order{
 						OrderID:      "order1",
 						Symbol:       "AAPL",
-						Side:         types.PurchaseTypeBuy,
+						Side:         types.PurchaseTypeSell,
 						Quantity:     100,
 						Price:        100.0,
 						Timestamp:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
@@ -230,7 +230,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 					Order: types.Order{
 						OrderID:      "order2",
 						Symbol:       "AAPL",
-						Side:         types.PurchaseTypeSell,
+						Side:         types.PurchaseTypeBuy,
 						Quantity:     50,
 						Price:        110.0,
 						Timestamp:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
@@ -265,7 +265,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:      "order1",
 					Symbol:       "AAPL",
-					Side:         types.PurchaseTypeBuy,
+					Side:         types.PurchaseTypeSell,
 					Quantity:     100,
 					Price:        100.0,
 					Timestamp:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
@@ -281,7 +281,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:     "order2",
 					Symbol:      "AAPL",
-					Side:        types.PurchaseTypeBuy,
+					Side:        types.PurchaseTypeSell,
 					Quantity:    100,
 					Price:       90.0,
 					Timestamp:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
@@ -297,7 +297,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:     "order3",
 					Symbol:      "AAPL",
-					Side:        types.PurchaseTypeBuy,
+					Side:        types.PurchaseTypeSell,
 					Quantity:    100,
 					Price:       80.0,
 					Timestamp:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
@@ -313,7 +313,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:     "order4",
 					Symbol:      "AAPL",
-					Side:        types.PurchaseTypeSell,
+					Side:        types.PurchaseTypeBuy,
 					Quantity:    100,
 					Price:       110.0,
 					Timestamp:   time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
@@ -329,7 +329,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:     "order5",
 					Symbol:      "AAPL",
-					Side:        types.PurchaseTypeSell,
+					Side:        types.PurchaseTypeBuy,
 					Quantity:    100,
 					Price:       120.0,
 					Timestamp:   time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC),
@@ -345,7 +345,7 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				{
 					OrderID:     "order6",
 					Symbol:      "AAPL",
-					Side:        types.PurchaseTypeSell,
+					Side:        types.PurchaseTypeBuy,
 					Quantity:    100,
 					Price:       130.0,
 					Timestamp:   time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
@@ -568,8 +568,8 @@ func (suite *BacktestStateTestSuite) TestUpdate_ShortPosition() {
 				suite.Assert().Equal(tc.orders[i].Timestamp.UTC(), result.Trade.ExecutedAt.UTC(), "Result trade timestamp mismatch")
 
 				// Verify IsNewPosition
-				if i == 0 && tc.orders[i].Side == types.PurchaseTypeBuy {
-					suite.Assert().True(result.IsNewPosition, "Expected IsNewPosition to be true for first buy order")
+				if i == 0 && tc.orders[i].Side == types.PurchaseTypeSell {
+					suite.Assert().True(result.IsNewPosition, "Expected IsNewPosition to be true for first short sell order")
 				} else {
 					suite.Assert().False(result.IsNewPosition, "Expected IsNewPosition to be false for subsequent orders")
 				}
@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// OrderSignal is an order a strategy placed in signal-only mode. It records
+// what the strategy intended to trade without being executed.
+type OrderSignal struct {
+	ID           string
+	Symbol       string
+	Side         types.PurchaseType
+	OrderType    types.OrderType
+	PositionType types.PositionType
+	Quantity     float64
+	// Price is the price of the order. MarketPrice is the close of the bar it was placed on.
+	Price        float64
+	MarketPrice  float64
+	Timestamp    time.Time
+	StrategyName string
+	Reason       string
+	Message      string
+}
+
+// createSignalsTable creates the table holding the signals of signal-only runs.
+func (b *BacktestState) createSignalsTable() error {
+	_, err := b.db.Exec(`
+		CREATE TABLE IF NOT EXISTS signals (
+			signal_id TEXT,
+			symbol TEXT,
+			side TEXT,
+			order_type TEXT,
+			position_type TEXT,
+			quantity DOUBLE,
+			price DOUBLE,
+			market_price DOUBLE,
+			timestamp TIMESTAMP,
+			strategy_name TEXT,
+			reason TEXT,
+			message TEXT
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create signals table: %w", err)
+	}
+
+	return nil
+}
+
+// RecordSignal stores the signal. Positions, trades and the balance are left untouched.
+func (b *BacktestState) RecordSignal(signal OrderSignal) error {
+	_, err := b.sq.Insert("signals").
+		Columns("signal_id", "symbol", "side", "order_type", "position_type", "quantity", "price",
+			"market_price", "timestamp", "strategy_name", "reason", "message").
+		Values(signal.ID, signal.Symbol, signal.Side, signal.OrderType, signal.PositionType, signal.Quantity,
+			signal.Price, signal.MarketPrice, signal.Timestamp, signal.StrategyName, signal.Reason, signal.Message).
+		RunWith(b.db).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to insert signal: %w", err)
+	}
+
+	return nil
+}
+
+// GetSignals returns all signals of the run in the order they were recorded.
+func (b *BacktestState) GetSignals() ([]OrderSignal, error) {
+	rows, err := b.sq.Select("signal_id", "symbol", "side", "order_type", "position_type", "quantity", "price",
+		"market_price", "timestamp", "strategy_name", "reason", "message").
+		From("signals").
+		OrderBy("rowid ASC").
+		RunWith(b.db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signals: %w", err)
+	}
+	defer rows.Close()
+
+	var signals []OrderSignal
+
+	for rows.Next() {
+		var signal OrderSignal
+		if err := rows.Scan(&signal.ID, &signal.Symbol, &signal.Side, &signal.OrderType, &signal.PositionType,
+			&signal.Quantity, &signal.Price, &signal.MarketPrice, &signal.Timestamp, &signal.StrategyName,
+			&signal.Reason, &signal.Message); err != nil {
+			return nil, fmt.Errorf("failed to scan signal: %w", err)
+		}
+
+		signals = append(signals, signal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating signals: %w", err)
+	}
+
+	return signals, nil
+}
+
+// countSignals returns the number of signals recorded in the run.
+func (b *BacktestState) countSignals() (int, error) {
+	var count int
+
+	if err := b.db.QueryRow(`SELECT COUNT(*) FROM signals`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count signals: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// subAccount is the share of the initial balance allocated to one strategy,
+// with the cash and positions that strategy's trades left it.
+type subAccount struct {
+	allocation float64
+	cash       float64
+	positions  map[string]*types.Position
+}
+
+// SetStrategyAllocations splits the initial balance into a sub-account per
+// strategy name. Every trade is then also tracked in the sub-account of the
+// strategy that placed it. A nil or empty map shares the balance between all
+// strategies.
+func (b *BacktestState) SetStrategyAllocations(allocations map[string]float64) error {
+	for strategyName, allocation := range allocations {
+		if allocation <= 0 {
+			return fmt.Errorf("allocation of strategy %q must be greater than zero: %f", strategyName, allocation)
+		}
+	}
+
+	b.subAccountsMu.Lock()
+	defer b.subAccountsMu.Unlock()
+
+	if len(allocations) == 0 {
+		b.subAccounts = nil
+
+		return nil
+	}
+
+	b.subAccounts = make(map[string]*subAccount, len(allocations))
+	for strategyName, allocation := range allocations {
+		b.subAccounts[strategyName] = &subAccount{
+			allocation: allocation,
+			cash:       allocation,
+			positions:  make(map[string]*types.Position),
+		}
+	}
+
+	return nil
+}
+
+// HasSubAccounts reports whether the initial balance is allocated per strategy.
+func (b *BacktestState) HasSubAccounts() bool {
+	b.subAccountsMu.Lock()
+	defer b.subAccountsMu.Unlock()
+
+	return len(b.subAccounts) > 0
+}
+
+// GetStrategyAllocation returns the share of the initial balance allocated to
+// the strategy. It fails when the strategy has no sub-account.
+func (b *BacktestState) GetStrategyAllocation(strategyName string) (float64, error) {
+	b.subAccountsMu.Lock()
+	defer b.subAccountsMu.Unlock()
+
+	account, ok := b.subAccounts[strategyName]
+	if !ok {
+		return 0, fmt.Errorf("strategy %q has no sub-account", strategyName)
+	}
+
+	return account.allocation, nil
+}
+
+// GetStrategyCash returns the cash of the strategy's sub-account: its
+// allocation less the cost of its buys and fees, plus the proceeds of its
+// sells. It fails when the strategy has no sub-account.
+func (b *BacktestState) GetStrategyCash(strategyName string) (float64, error) {
+	b.subAccountsMu.Lock()
+	defer b.subAccountsMu.Unlock()
+
+	account, ok := b.subAccounts[strategyName]
+	if !ok {
+		return 0, fmt.Errorf("strategy %q has no sub-account", strategyName)
+	}
+
+	return account.cash, nil
+}
+
+// GetStrategyPosition returns the position the strategy's own trades hold in
+// the symbol. It fails when the strategy has no sub-account.
+func (b *BacktestState) GetStrategyPosition(strategyName, symbol string) (types.Position, error) {
+	b.subAccountsMu.Lock()
+	defer b.subAccountsMu.Unlock()
+
+	account, ok := b.subAccounts[strategyName]
+	if !ok {
+		return types.Position{}, fmt.Errorf("strategy %q has no sub-account", strategyName)
+	}
+
+	if pos, ok := account.positions[symbol]; ok {
+		return *pos, nil
+	}
+
+	return *newEmptyPosition(symbol), nil
+}
+
+// GetStrategyPositions returns the positions of every symbol the strategy has
+// traded, ordered by symbol. It fails when the strategy has no sub-account.
+func (b *BacktestState) GetStrategyPositions(strategyName string) ([]types.Position, error) {
+	b.subAccountsMu.Lock()
+	defer b.subAccountsMu.Unlock()
+
+	account, ok := b.subAccounts[strategyName]
+	if !ok {
+		return nil, fmt.Errorf("strategy %q has no sub-account", strategyName)
+	}
+
+	positions := make([]types.Position, 0, len(account.positions))
+	for _, pos := range account.positions {
+		positions = append(positions, *pos)
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Symbol < positions[j].Symbol
+	})
+
+	return positions, nil
+}
+
+// applyTradeToSubAccount adds a committed trade to the cash and positions of
+// the sub-account of the strategy that placed it, if it has one.
+func (b *BacktestState) applyTradeToSubAccount(order types.Order) {
+	b.subAccountsMu.Lock()
+	defer b.subAccountsMu.Unlock()
+
+	account, ok := b.subAccounts[order.StrategyName]
+	if !ok {
+		return
+	}
+
+	pos, ok := account.positions[order.Symbol]
+	if !ok {
+		pos = newEmptyPosition(order.Symbol)
+		pos.OpenTimestamp = order.Timestamp
+		account.positions[order.Symbol] = pos
+	}
+
+	applyTradeToPosition(pos, order)
+
+	account.cash = computeCashBalance(account.cash, order)
+}
+
+// resetSubAccounts clears the cash and positions of every sub-account for a
+// new run, keeping the allocations.
+func (b *BacktestState) resetSubAccounts() {
+	b.subAccountsMu.Lock()
+	defer b.subAccountsMu.Unlock()
+
+	for _, account := range b.subAccounts {
+		account.cash = account.allocation
+		account.positions = make(map[string]*types.Position)
+	}
+}
@@ -453,14 +453,14 @@ func (suite *BacktestStateTestSuite) TestGetStats() {
 					Symbol: "TSLA",
 					TradePnl: types.TradePnl{
 						RealizedPnL:   0,
-						TotalPnL:      2000, // (1000 - 800) * 10 = 2000 profit for a short position
-						UnrealizedPnL: 2000,
+						TotalPnL:      1995, // (999.5 - 800) * 10, entry price net of the 5.0 fee
+						UnrealizedPnL: 1995,
 						MaximumLoss:   0,
 						MaximumProfit: 0,
 					},
 					TradeResult: types.TradeResult{
 						NumberOfTrades:        1,
-						NumberOfTradingPairs:  1,
+						NumberOfTradingPairs:  0,
 						NumberOfWinningTrades: 0,
 						NumberOfLosingTrades:  0,
 						WinRate:               0,
@@ -468,9 +468,10 @@ func (suite *BacktestStateTestSuite) TestGetStats() {
 					},
 					TotalFees: 5.0,
 					TradeHoldingTime: types.TradeHoldingTime{
-						Min: 0,
-						Max: 0,
-						Avg: 0,
+						// Short still open: Jan 1, 10:00 until the last bar at 15:00 = 18000 seconds
+						Min: 18000,
+						Max: 18000,
+						Avg: 18000,
 					},
 					BuyAndHoldPnl: 2000.0, // (1000 - 800) * 10 = positive 2000 for a short position
 				},
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// GetTradesPage returns the trades matching the filter in insertion order, one page
+// at a time. The cursor is the rowid of the last trade of the previous page, so pages
+// neither overlap nor skip trades while new trades are being appended.
+func (b *BacktestState) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	query := b.sq.Select(append([]string{"rowid"}, tradeColumns...)...).
+		From("trades").
+		OrderBy("rowid ASC")
+
+	if filter.Cursor != "" {
+		afterID, err := strconv.ParseInt(filter.Cursor, 10, 64)
+		if err != nil || afterID < 0 {
+			return types.TradePage{}, fmt.Errorf("invalid trade cursor %q", filter.Cursor)
+		}
+
+		query = query.Where(squirrel.Gt{"rowid": afterID})
+	}
+
+	if filter.Symbol != "" {
+		query = query.Where(squirrel.Eq{"symbol": filter.Symbol})
+	}
+
+	if !filter.StartTime.IsZero() {
+		query = query.Where(squirrel.GtOrEq{"executed_at": filter.StartTime})
+	}
+
+	if !filter.EndTime.IsZero() {
+		query = query.Where(squirrel.LtOrEq{"executed_at": filter.EndTime})
+	}
+
+	// Read one extra row to learn whether another page follows
+	if filter.Limit > 0 {
+		query = query.Limit(uint64(filter.Limit) + 1)
+	}
+
+	rows, err := query.RunWith(b.db).Query()
+	if err != nil {
+		return types.TradePage{}, fmt.Errorf("failed to query trades: %w", err)
+	}
+	defer rows.Close()
+
+	trades := []types.Trade{}
+	ids := []int64{}
+
+	for rows.Next() {
+		var (
+			id    int64
+			trade types.Trade
+		)
+
+		if err := rows.Scan(append([]any{&id}, tradeScanTargets(&trade)...)...); err != nil {
+			return types.TradePage{}, fmt.Errorf("failed to scan trade: %w", err)
+		}
+
+		trades = append(trades, trade)
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return types.TradePage{}, fmt.Errorf("error iterating trades: %w", err)
+	}
+
+	page := types.TradePage{Trades: trades, NextCursor: ""}
+
+	if filter.Limit > 0 && len(trades) > filter.Limit {
+		page.Trades = trades[:filter.Limit]
+		page.NextCursor = strconv.FormatInt(ids[filter.Limit-1], 10)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// placeStopOrder checks that a stop order could be filled with the current
+// account and holds it in pending orders until its stop price is crossed.
+// The trigger is evaluated from the next bar on by processPendingOrders.
+func (b *BacktestTrading) placeStopOrder(order types.ExecuteOrder) error {
+	if order.PositionType == types.PositionTypeShort {
+		price := order.StopPrice
+		if order.OrderType == types.OrderTypeStopLimit {
+			price = order.Price
+		}
+
+		if rejected, err := b.rejectShortOrder(order, price); rejected {
+			return err
+		}
+	} else if order.Side == types.PurchaseTypeBuy {
+		// A stop-limit buy fills at most at its limit price, a stop-market buy around its stop price
+		price := order.StopPrice
+		if order.OrderType == types.OrderTypeStopLimit {
+			price = order.Price
+		}
+
+		totalCost := order.Quantity * price
+		if buyingPower := b.orderBuyingPower(order); totalCost > buyingPower {
+			failedOrder := b.createFailedOrder(order, price, types.OrderReasonInsufficientBuyPower,
+				fmt.Sprintf("stop buy order cost (%.2f) exceeds buying power (%.2f)", totalCost, buyingPower))
+
+			return b.state.StoreFailedOrder(failedOrder)
+		}
+	} else {
+		sellingPower := b.orderSellingPower(order)
+		if order.Quantity > sellingPower {
+			failedOrder := b.createFailedOrder(order, order.StopPrice, types.OrderReasonInsufficientSellPower,
+				fmt.Sprintf("order quantity (%.2f) exceeds selling power (%.2f)", order.Quantity, sellingPower))
+
+			return b.state.StoreFailedOrder(failedOrder)
+		}
+	}
+
+	b.pendingOrders = append(b.pendingOrders, order)
+
+	return nil
+}
+
+// stopTriggered reports whether the current bar crosses the order's stop price.
+// Sell stops trigger when the low falls to or below the stop price, buy stops
+// when the high rises to or above it.
+func (b *BacktestTrading) stopTriggered(order types.ExecuteOrder) bool {
+	if order.Side == types.PurchaseTypeSell {
+		return b.marketData.Low <= order.StopPrice
+	}
+
+	return b.marketData.High >= order.StopPrice
+}
+
+// stopFillPrice returns the fill price of a triggered stop-market order.
+// It fills at the stop price unless the bar opened beyond it (a gap), in which
+// case it fills at the open.
+func (b *BacktestTrading) stopFillPrice(order types.ExecuteOrder) float64 {
+	open := b.marketData.Open
+	if open <= 0 {
+		return order.StopPrice
+	}
+
+	if order.Side == types.PurchaseTypeSell && open < order.StopPrice {
+		return open
+	}
+
+	if order.Side == types.PurchaseTypeBuy && open > order.StopPrice {
+		return open
+	}
+
+	return order.StopPrice
+}
+
+// placeTrailingStopOrder starts the trailing stop from the close of the current
+// bar and holds it like a stop-market order.
+func (b *BacktestTrading) placeTrailingStopOrder(order types.ExecuteOrder) error {
+	order.StopPrice = trailingStopPrice(order, b.marketData.Close)
+
+	return b.placeStopOrder(order)
+}
+
+// trailStop returns the stop of a trailing stop order after the current bar.
+// A sell trails the bar's high and a buy trails the bar's low; the stop only
+// moves in the order's favour.
+func (b *BacktestTrading) trailStop(order types.ExecuteOrder) float64 {
+	if order.Side == types.PurchaseTypeSell {
+		stop := trailingStopPrice(order, b.marketData.High)
+		if order.StopPrice > 0 {
+			return math.Max(order.StopPrice, stop)
+		}
+
+		return stop
+	}
+
+	stop := trailingStopPrice(order, b.marketData.Low)
+	if order.StopPrice > 0 {
+		return math.Min(order.StopPrice, stop)
+	}
+
+	return stop
+}
+
+// trailingStopPrice returns the stop of a trailing stop order whose best price is
+// mark: below it for a sell, above it for a buy.
+func trailingStopPrice(order types.ExecuteOrder, mark float64) float64 {
+	offset := order.TrailOffset
+	if order.TrailPercent {
+		offset = mark * order.TrailOffset / 100
+	}
+
+	if order.Side == types.PurchaseTypeSell {
+		return mark - offset
+	}
+
+	return mark + offset
+}
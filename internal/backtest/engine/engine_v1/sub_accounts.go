@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// allocationTolerance is how far the strategy allocations may sum from the
+// initial balance, absorbing floating point error.
+const allocationTolerance = 1e-6
+
+// SetStrategyAllocations allocates the initial balance to sub-accounts keyed by
+// strategy name. The allocations must sum to the initial balance, so every
+// dollar of it belongs to one sub-account. Each strategy's fills then move the
+// cash of its own sub-account, its orders are checked against that
+// sub-account's buying and selling power, and a strategy without an allocation
+// cannot open positions. A nil or empty map shares the balance between all
+// strategies.
+func (b *BacktestTrading) SetStrategyAllocations(allocations map[string]float64) error {
+	if len(allocations) > 0 {
+		total := 0.0
+		for _, allocation := range allocations {
+			total += allocation
+		}
+
+		if math.Abs(total-b.balance) > allocationTolerance {
+			return errors.Newf(errors.ErrCodeInvalidParameter,
+				"strategy allocations sum to %.2f instead of the initial balance %.2f", total, b.balance)
+		}
+	}
+
+	if err := b.state.SetStrategyAllocations(allocations); err != nil {
+		return errors.Wrap(errors.ErrCodeInvalidParameter, "failed to set strategy allocations", err)
+	}
+
+	return nil
+}
+
+// GetStrategyAccountInfo returns the account state of the strategy's
+// sub-account: the cash its own fills left of its allocation as the balance,
+// and the PnL of the positions its own trades opened. Funding payments are only
+// included in the global account.
+func (b *BacktestTrading) GetStrategyAccountInfo(strategyName string) (types.AccountInfo, error) {
+	allocation, err := b.state.GetStrategyAllocation(strategyName)
+	if err != nil {
+		return types.AccountInfo{}, errors.Wrap(errors.ErrCodeDataNotFound, "failed to get strategy account", err)
+	}
+
+	cash, err := b.state.GetStrategyCash(strategyName)
+	if err != nil {
+		return types.AccountInfo{}, errors.Wrap(errors.ErrCodeDataNotFound, "failed to get strategy cash", err)
+	}
+
+	positions, err := b.state.GetStrategyPositions(strategyName)
+	if err != nil {
+		return types.AccountInfo{}, errors.Wrap(errors.ErrCodeDataNotFound, "failed to get strategy positions", err)
+	}
+
+	realizedPnL, unrealizedPnL, notional := valuePositions(positions, b.markPrice)
+
+	totalFees := 0.0
+	for _, pos := range positions {
+		totalFees += pos.TotalLongInFee + pos.TotalLongOutFee + pos.TotalShortInFee + pos.TotalShortOutFee
+	}
+
+	marginUsed := b.usedMargin(notional)
+	freeMargin := allocation + realizedPnL + unrealizedPnL - marginUsed
+
+	return types.AccountInfo{
+		Balance:       cash,
+		Equity:        cash + positionsValue(positions, b.markPrice),
+		BuyingPower:   b.subAccountBuyingPower(cash, freeMargin),
+		RealizedPnL:   realizedPnL,
+		UnrealizedPnL: unrealizedPnL,
+		TotalFees:     totalFees,
+		MarginUsed:    marginUsed,
+		FreeMargin:    freeMargin,
+		Balances:      nil,
+	}, nil
+}
+
+// GetStrategyPosition returns the position the strategy's own trades hold in
+// the symbol.
+func (b *BacktestTrading) GetStrategyPosition(strategyName, symbol string) (types.Position, error) {
+	position, err := b.state.GetStrategyPosition(strategyName, symbol)
+	if err != nil {
+		return types.Position{}, errors.Wrap(errors.ErrCodeDataNotFound, "failed to get strategy position", err)
+	}
+
+	return position, nil
+}
+
+// subAccountBuyingPower returns the buying power of a sub-account, mirroring
+// getBuyingPower for the whole account: its cash, or with margin its free
+// margin times the leverage.
+func (b *BacktestTrading) subAccountBuyingPower(cash, freeMargin float64) float64 {
+	if b.margin.enabled() {
+		return max(freeMargin*b.margin.leverage(), 0)
+	}
+
+	return max(cash, 0)
+}
+
+// orderBuyingPower returns the buying power available to the order: that of
+// the placing strategy's sub-account when the balance is allocated per
+// strategy, and that of the whole account otherwise.
+func (b *BacktestTrading) orderBuyingPower(order types.ExecuteOrder) float64 {
+	if !b.state.HasSubAccounts() {
+		return b.getBuyingPower()
+	}
+
+	account, err := b.GetStrategyAccountInfo(order.StrategyName)
+	if err != nil {
+		return 0
+	}
+
+	return account.BuyingPower
+}
+
+// orderSellingPower returns the long quantity the order may sell: the placing
+// strategy's own position when the balance is allocated per strategy, and the
+// whole account's position otherwise.
+func (b *BacktestTrading) orderSellingPower(order types.ExecuteOrder) float64 {
+	if !b.state.HasSubAccounts() {
+		return b.getSellingPower()
+	}
+
+	position, err := b.state.GetStrategyPosition(order.StrategyName, order.Symbol)
+	if err != nil {
+		return 0
+	}
+
+	return b.floorQuantity(order.Symbol, position.TotalLongPositionQuantity)
+}
@@ -0,0 +1,413 @@
+package engine
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/cache"
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
+	"github.com/rxtech-lab/argo-trading/internal/runtime"
+	"github.com/rxtech-lab/argo-trading/internal/runtime/wasm"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// barTurn hands the shared trading system to one bar of a batch. Turns are
+// taken in data order, so orders reach the trading system in the same sequence
+// as in a serial run no matter which strategy instance finishes first.
+type barTurn struct {
+	data types.MarketData
+	// start is closed when the previous bar of the batch has finished.
+	start <-chan struct{}
+	// done is closed when this bar has finished.
+	done  chan struct{}
+	taken bool
+	// onStart moves the trading system to the bar once the turn is taken.
+	onStart func(data types.MarketData) error
+	// err is the error onStart returned.
+	err error
+}
+
+// wait blocks until it is the bar's turn. The strategy keeps the turn until it
+// returns from ProcessData. Outside of a batch, such as while the strategy is
+// initialized, there is no turn to wait for.
+func (t *barTurn) wait() {
+	if t == nil || t.taken {
+		return
+	}
+
+	<-t.start
+
+	t.taken = true
+	t.err = t.onStart(t.data)
+}
+
+// finish ends the bar's turn, taking it first if the strategy never did. A bar
+// that did not touch the trading system still moves it, so pending orders fill
+// on the bar as they would in a serial run.
+func (t *barTurn) finish() {
+	t.wait()
+	close(t.done)
+}
+
+// turnTakingTradingSystem is the trading system of one symbol's strategy
+// instance. Every call waits for the turn of the bar being processed.
+type turnTakingTradingSystem struct {
+	tradingprovider.TradingSystemProvider
+
+	turn *barTurn
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) PlaceOrder(order types.ExecuteOrder) error {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.PlaceOrder(order)
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.PlaceMultipleOrders(orders)
+}
+
+// GetPositions implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetPositions() ([]types.Position, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetPositions()
+}
+
+// GetPosition implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetPosition(symbol string) (types.Position, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetPosition(symbol)
+}
+
+// CancelOrder implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) CancelOrder(orderID string) error {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.CancelOrder(orderID)
+}
+
+// CancelAllOrders implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) CancelAllOrders() error {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.CancelAllOrders()
+}
+
+// CancelOrdersForSymbol implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) CancelOrdersForSymbol(symbol string) error {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.CancelOrdersForSymbol(symbol)
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.PlaceBracketOrder(entry, stopLoss, takeProfit)
+}
+
+// GetOrderStatus implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetOrderStatus(orderID string) (types.OrderStatus, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetOrderStatus(orderID)
+}
+
+// GetAccountInfo implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetAccountInfo() (types.AccountInfo, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetAccountInfo()
+}
+
+// GetAssets implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetAssets() ([]types.Asset, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetAssets()
+}
+
+// GetPrices implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetPrices(symbols []string) (map[string]float64, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetPrices(symbols)
+}
+
+// GetOpenOrders implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetOpenOrders() ([]types.ExecuteOrder, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetOpenOrders()
+}
+
+// GetTrades implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetTrades(filter types.TradeFilter) ([]types.Trade, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetTrades(filter)
+}
+
+// GetTradesPage implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetTradesPage(filter)
+}
+
+// GetMaxBuyQuantity implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetMaxBuyQuantity(symbol string, price float64) (float64, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetMaxBuyQuantity(symbol, price)
+}
+
+// GetMaxSellQuantity implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) GetMaxSellQuantity(symbol string) (float64, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.GetMaxSellQuantity(symbol)
+}
+
+// CalculatePositionSize implements tradingprovider.TradingSystemProvider.
+func (t *turnTakingTradingSystem) CalculatePositionSize(symbol string, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	t.turn.wait()
+
+	return t.TradingSystemProvider.CalculatePositionSize(symbol, entryPrice, stopPrice, riskFraction)
+}
+
+// symbolStrategy is the strategy instance that processes the bars of one symbol.
+type symbolStrategy struct {
+	strategy runtime.StrategyRuntime
+	context  *runtime.RuntimeContext
+	trading  *turnTakingTradingSystem
+}
+
+// process runs the strategy on data and then ends the bar's turn.
+func (s *symbolStrategy) process(data types.MarketData) error {
+	s.context.CurrentMarketData = &data
+
+	processErr := s.strategy.ProcessData(data)
+
+	s.trading.turn.finish()
+
+	return processErr
+}
+
+// processDataPointsBySymbol is processDataPoints for a config with more than one
+// symbol worker. Consecutive bars with the same time are processed as a batch:
+// each symbol's bar goes to the symbol's own clone of the strategy and the clones
+// run on the worker pool, while the trading system is handed to them one bar at
+// a time in data order. Bookkeeping runs serially around each batch.
+func (b *BacktestEngineV1) processDataPointsBySymbol(params runIterationParams, strategyContext *runtime.RuntimeContext, slidingWindowDS *datasource.SlidingWindowDataSource, count int, cloner runtime.Cloner) error {
+	bars, err := b.newBarBookkeeping(params, slidingWindowDS, count)
+	if err != nil {
+		return err
+	}
+
+	jobs := make(chan func())
+	defer close(jobs)
+
+	for range b.config.SymbolWorkers {
+		go func() {
+			for job := range jobs {
+				job()
+			}
+		}()
+	}
+
+	strategies := map[string]*symbolStrategy{}
+
+	var batch []types.MarketData
+
+	for data, err := range b.datasource.ReadAll(b.config.StartTime, b.config.EndTime) {
+		// Check for context cancellation
+		if err := b.checkCancelled(params.ctx); err != nil {
+			return err
+		}
+
+		if err != nil {
+			return errors.Wrap(errors.ErrCodeDataNotFound, "failed to read data", err)
+		}
+
+		// A bar of a new time, or a second bar of a symbol, starts the next batch
+		if len(batch) > 0 && (!data.Time.Equal(batch[0].Time) || slices.ContainsFunc(batch, func(bar types.MarketData) bool {
+			return bar.Symbol == data.Symbol
+		})) {
+			if err := b.processBatch(params, strategyContext, cloner, strategies, jobs, bars, batch); err != nil {
+				return err
+			}
+
+			batch = batch[:0]
+		}
+
+		batch = append(batch, data)
+	}
+
+	if len(batch) > 0 {
+		if err := b.processBatch(params, strategyContext, cloner, strategies, jobs, bars, batch); err != nil {
+			return err
+		}
+	}
+
+	return bars.finish()
+}
+
+// processBatch processes bars of different symbols with the same time on the worker pool.
+func (b *BacktestEngineV1) processBatch(params runIterationParams, strategyContext *runtime.RuntimeContext, cloner runtime.Cloner, strategies map[string]*symbolStrategy, jobs chan<- func(), bars *barBookkeeping, batch []types.MarketData) error {
+	warmingUp := make([]bool, len(batch))
+
+	for i, data := range batch {
+		if err := bars.before(data); err != nil {
+			return err
+		}
+
+		// Bars of the warm-up period only fill the cache and need no strategy
+		warmingUp[i] = bars.warmingUp(data)
+		if _, ok := strategies[data.Symbol]; ok || warmingUp[i] {
+			continue
+		}
+
+		instance, err := b.newSymbolStrategy(params, strategyContext, cloner)
+		if err != nil {
+			return err
+		}
+
+		strategies[data.Symbol] = instance
+	}
+
+	onStart := func(data types.MarketData) error {
+		if backtestTrading, ok := b.tradingSystem.(*BacktestTrading); ok {
+			return backtestTrading.UpdateCurrentMarketData(data)
+		}
+
+		return nil
+	}
+
+	processErrs := make([]error, len(batch))
+	turns := make([]*barTurn, len(batch))
+	start := make(chan struct{})
+	close(start)
+
+	var wg sync.WaitGroup
+
+	// Jobs are queued in data order, so a job only ever waits for the turn of a
+	// job a worker has already picked up
+	for i, data := range batch {
+		turn := &barTurn{
+			data:    data,
+			start:   start,
+			done:    make(chan struct{}),
+			taken:   false,
+			onStart: onStart,
+			err:     nil,
+		}
+		turns[i] = turn
+		start = turn.done
+
+		wg.Add(1)
+
+		if warmingUp[i] {
+			jobs <- func() {
+				defer wg.Done()
+
+				turn.finish()
+			}
+
+			continue
+		}
+
+		instance := strategies[data.Symbol]
+		instance.trading.turn = turn
+		instance.context.Interval = bars.interval()
+
+		jobs <- func() {
+			defer wg.Done()
+
+			processErrs[i] = instance.process(data)
+		}
+	}
+
+	wg.Wait()
+
+	for _, turn := range turns {
+		if turn.err != nil {
+			return turn.err
+		}
+	}
+
+	for i, data := range batch {
+		if warmingUp[i] {
+			if err := bars.reportProgress(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := bars.after(data, processErrs[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newSymbolStrategy clones the strategy of the run and initializes the clone
+// with a runtime context of its own. The clone shares the data source, markers
+// and logs of the run but has its own cache, since the cache is not safe for
+// concurrent use.
+func (b *BacktestEngineV1) newSymbolStrategy(params runIterationParams, strategyContext *runtime.RuntimeContext, cloner runtime.Cloner) (*symbolStrategy, error) {
+	instance, err := cloner.Clone()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to clone strategy", err)
+	}
+
+	trading := &turnTakingTradingSystem{
+		TradingSystemProvider: strategyContext.TradingSystem,
+		turn:                  nil,
+	}
+
+	runtimeContext := &runtime.RuntimeContext{
+		DataSource:        strategyContext.DataSource,
+		MarketDataCache:   strategyContext.MarketDataCache,
+		IndicatorRegistry: strategyContext.IndicatorRegistry,
+		Cache:             cache.NewCacheV1(),
+		TradingSystem:     trading,
+		Marker:            strategyContext.Marker,
+		Logger:            strategyContext.Logger,
+		LogStorage:        strategyContext.LogStorage,
+		CurrentMarketData: nil,
+		Interval:          "",
+	}
+
+	if err := instance.InitializeApi(wasm.NewWasmStrategyApi(runtimeContext)); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to initialize strategy api", err)
+	}
+
+	if err := instance.Initialize(params.configContent); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to initialize strategy", err)
+	}
+
+	return &symbolStrategy{
+		strategy: instance,
+		context:  runtimeContext,
+		trading:  trading,
+	}, nil
+}
@@ -0,0 +1,319 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	engine_types "github.com/rxtech-lab/argo-trading/internal/backtest/engine"
+	"github.com/rxtech-lab/argo-trading/internal/runtime"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/mocks"
+	"github.com/rxtech-lab/argo-trading/pkg/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// momentumStrategy buys one unit of a symbol after a rising close and sells the
+// position after a falling close. It keeps the previous close of every symbol it
+// sees, so serial and per-symbol runs trade the same way.
+type momentumStrategy struct {
+	api       strategy.StrategyApi
+	lastClose map[string]float64
+	// work is the number of rounds of busy work per bar, standing in for indicator calculations.
+	work     int
+	checksum float64
+	// processed counts the bars given to this instance.
+	processed int
+}
+
+func newMomentumStrategy(work int) *momentumStrategy {
+	return &momentumStrategy{lastClose: map[string]float64{}, work: work}
+}
+
+func (s *momentumStrategy) Clone() (runtime.StrategyRuntime, error) {
+	return newMomentumStrategy(s.work), nil
+}
+
+func (s *momentumStrategy) Initialize(config string) error { return nil }
+
+func (s *momentumStrategy) InitializeApi(api strategy.StrategyApi) error {
+	s.api = api
+
+	return nil
+}
+
+func (s *momentumStrategy) ProcessData(data types.MarketData) error {
+	s.processed++
+
+	x := data.Close
+	for range s.work {
+		x = math.Sqrt(x*x + 1)
+	}
+	s.checksum += x
+
+	previous, seen := s.lastClose[data.Symbol]
+	s.lastClose[data.Symbol] = data.Close
+
+	if !seen || data.Close == previous {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	position, err := s.api.GetPosition(ctx, &strategy.GetPositionRequest{Symbol: data.Symbol})
+	if err != nil {
+		return err
+	}
+
+	order := &strategy.ExecuteOrder{
+		Id:           uuid.NewString(),
+		Symbol:       data.Symbol,
+		Side:         strategy.PurchaseType_PURCHASE_TYPE_BUY,
+		OrderType:    strategy.OrderType_ORDER_TYPE_MARKET,
+		Reason:       &strategy.Reason{Reason: "strategy", Message: "rising close"},
+		Price:        data.Close,
+		StrategyName: s.Name(),
+		Quantity:     1,
+		PositionType: strategy.PositionType_POSITION_TYPE_LONG,
+	}
+
+	if data.Close < previous {
+		if position.Quantity <= 0 {
+			return nil
+		}
+
+		order.Side = strategy.PurchaseType_PURCHASE_TYPE_SELL
+		order.Reason.Message = "falling close"
+		order.Quantity = position.Quantity
+	}
+
+	_, err = s.api.PlaceOrder(ctx, order)
+
+	return err
+}
+
+func (s *momentumStrategy) GetConfigSchema() (string, error) { return "", nil }
+
+func (s *momentumStrategy) Name() string { return "MomentumStrategy" }
+
+func (s *momentumStrategy) GetDescription() (string, error) { return "", nil }
+
+func (s *momentumStrategy) GetRuntimeEngineVersion() (string, error) { return "1.0.0", nil }
+
+func (s *momentumStrategy) GetIdentifier() (string, error) { return "com.test.momentum", nil }
+
+// multiSymbolBars returns bars of three symbols with the same times, ordered by
+// time and then symbol like the DuckDB data source reads them.
+func multiSymbolBars(count int) []types.MarketData {
+	symbols := []string{"AAA", "BBB", "CCC"}
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+
+	var bars []types.MarketData
+
+	for i := range count {
+		for j, symbol := range symbols {
+			price := 100 + 10*math.Sin(float64(i)*0.7+float64(j)) + float64(j)*20
+			bars = append(bars, types.MarketData{
+				Id:     fmt.Sprintf("%s-%d", symbol, i),
+				Symbol: symbol,
+				Time:   start.Add(time.Duration(i) * time.Minute),
+				Open:   price,
+				High:   price + 1,
+				Low:    price - 1,
+				Close:  price,
+				Volume: 1_000_000,
+			})
+		}
+	}
+
+	return bars
+}
+
+// runMultiSymbolBacktest runs strategy over bars and returns the trades of the
+// run in the order they were recorded. Order IDs are left out since they are random.
+func runMultiSymbolBacktest(t testing.TB, config string, strategy runtime.StrategyRuntime, bars []types.MarketData) [][]any {
+	ctrl := gomock.NewController(t)
+
+	lastBars := map[string]types.MarketData{}
+	for _, bar := range bars {
+		lastBars[bar.Symbol] = bar
+	}
+
+	mockDatasource := mocks.NewMockDataSource(ctrl)
+	mockDatasource.EXPECT().Initialize(gomock.Any()).Return(nil).AnyTimes()
+	mockDatasource.EXPECT().ReadAll(gomock.Any(), gomock.Any()).Return(func(yield func(types.MarketData, error) bool) {
+		for _, bar := range bars {
+			if !yield(bar, nil) {
+				return
+			}
+		}
+	}).AnyTimes()
+	mockDatasource.EXPECT().Count(gomock.Any(), gomock.Any()).Return(len(bars), nil).AnyTimes()
+	mockDatasource.EXPECT().GetAllSymbols().Return([]string{"AAA", "BBB", "CCC"}, nil).AnyTimes()
+	mockDatasource.EXPECT().ReadLastData(gomock.Any()).DoAndReturn(func(symbol string) (types.MarketData, error) {
+		return lastBars[symbol], nil
+	}).AnyTimes()
+
+	e, err := NewBacktestEngineV1()
+	require.NoError(t, err)
+
+	backtestEngine := e.(*BacktestEngineV1)
+	require.NoError(t, backtestEngine.Initialize(config))
+	require.NoError(t, backtestEngine.SetDataSource(mockDatasource))
+	require.NoError(t, backtestEngine.LoadStrategy(strategy))
+	require.NoError(t, backtestEngine.SetConfigContent([]string{""}))
+
+	resultsDir := t.TempDir()
+	backtestEngine.dataPaths = []string{"bars.parquet"}
+	require.NoError(t, backtestEngine.SetResultsFolder(resultsDir))
+
+	require.NoError(t, backtestEngine.Run(context.Background(), engine_types.LifecycleCallbacks{}))
+
+	var tradesPath string
+
+	err = filepath.WalkDir(resultsDir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Name() == "trades.parquet" {
+			tradesPath = path
+		}
+
+		return err
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, tradesPath)
+
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT symbol, order_type, executed_at, executed_qty, executed_price, commission, pnl, balance, open_position_qty
+		FROM read_parquet('%s')
+	`, tradesPath))
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var trades [][]any
+
+	for rows.Next() {
+		var (
+			symbol, side                                               string
+			executedAt                                                 time.Time
+			quantity, price, commission, pnl, balance, openPositionQty float64
+		)
+
+		require.NoError(t, rows.Scan(&symbol, &side, &executedAt, &quantity, &price, &commission, &pnl, &balance, &openPositionQty))
+		trades = append(trades, []any{symbol, side, executedAt, quantity, price, commission, pnl, balance, openPositionQty})
+	}
+
+	require.NoError(t, rows.Err())
+
+	return trades
+}
+
+func symbolWorkersConfig(workers int, warmupBars int) string {
+	return fmt.Sprintf("initial_capital: 100000\nsymbol_workers: %d\nwarmup_bars: %d\n", workers, warmupBars)
+}
+
+func TestBacktestEngineV1_SymbolWorkers(t *testing.T) {
+	setTestVersion(t, "1.0.0")
+
+	bars := multiSymbolBars(40)
+
+	loaded := newMomentumStrategy(0)
+	serial := runMultiSymbolBacktest(t, "initial_capital: 100000", loaded, bars)
+	require.NotEmpty(t, serial)
+	assert.Equal(t, len(bars), loaded.processed)
+
+	symbols := map[any]bool{}
+	for _, trade := range serial {
+		symbols[trade[0]] = true
+	}
+
+	assert.Len(t, symbols, 3, "every symbol should trade")
+
+	for _, workers := range []int{2, 3, 8} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			loaded := newMomentumStrategy(0)
+			assert.Equal(t, serial, runMultiSymbolBacktest(t, symbolWorkersConfig(workers, 0), loaded, bars))
+			assert.Zero(t, loaded.processed, "bars should be processed by the per-symbol clones")
+		})
+	}
+}
+
+func TestBacktestEngineV1_SymbolWorkersWarmupBars(t *testing.T) {
+	setTestVersion(t, "1.0.0")
+
+	bars := multiSymbolBars(40)
+
+	loaded := newMomentumStrategy(0)
+	serial := runMultiSymbolBacktest(t, symbolWorkersConfig(0, 5), loaded, bars)
+	require.NotEmpty(t, serial)
+	assert.Equal(t, len(bars)-3*5, loaded.processed)
+
+	assert.Equal(t, serial, runMultiSymbolBacktest(t, symbolWorkersConfig(3, 5), newMomentumStrategy(0), bars))
+}
+
+func TestBacktestEngineV1_SymbolWorkersWithoutCloner(t *testing.T) {
+	setTestVersion(t, "1.0.0")
+
+	ctrl := gomock.NewController(t)
+
+	bars := multiSymbolBars(2)
+
+	// A strategy that cannot be cloned processes every bar itself
+	mockStrategy := mocks.NewMockStrategyRuntime(ctrl)
+	mockStrategy.EXPECT().Name().Return("TestStrategy").AnyTimes()
+	mockStrategy.EXPECT().Initialize(gomock.Any()).Return(nil).Times(1)
+	mockStrategy.EXPECT().InitializeApi(gomock.Any()).Return(nil).Times(1)
+	mockStrategy.EXPECT().ProcessData(gomock.Any()).Return(nil).Times(len(bars))
+	mockStrategy.EXPECT().GetRuntimeEngineVersion().Return("1.0.0", nil).AnyTimes()
+	mockStrategy.EXPECT().GetIdentifier().Return("com.test.mock", nil).AnyTimes()
+
+	mockDatasource := mocks.NewMockDataSource(ctrl)
+	mockDatasource.EXPECT().Initialize(gomock.Any()).Return(nil).AnyTimes()
+	mockDatasource.EXPECT().ReadAll(gomock.Any(), gomock.Any()).Return(func(yield func(types.MarketData, error) bool) {
+		for _, bar := range bars {
+			if !yield(bar, nil) {
+				return
+			}
+		}
+	}).AnyTimes()
+	mockDatasource.EXPECT().Count(gomock.Any(), gomock.Any()).Return(len(bars), nil).AnyTimes()
+	mockDatasource.EXPECT().GetAllSymbols().Return([]string{"AAA", "BBB", "CCC"}, nil).AnyTimes()
+	mockDatasource.EXPECT().ReadLastData(gomock.Any()).Return(bars[len(bars)-1], nil).AnyTimes()
+
+	e, err := NewBacktestEngineV1()
+	require.NoError(t, err)
+
+	backtestEngine := e.(*BacktestEngineV1)
+	require.NoError(t, backtestEngine.Initialize("initial_capital: 100000\nsymbol_workers: 4\n"))
+	require.NoError(t, backtestEngine.SetDataSource(mockDatasource))
+	require.NoError(t, backtestEngine.LoadStrategy(mockStrategy))
+	require.NoError(t, backtestEngine.SetConfigContent([]string{""}))
+	backtestEngine.dataPaths = []string{"bars.parquet"}
+	require.NoError(t, backtestEngine.SetResultsFolder(t.TempDir()))
+
+	require.NoError(t, backtestEngine.Run(context.Background(), engine_types.LifecycleCallbacks{}))
+}
+
+func BenchmarkBacktestEngineV1_SymbolWorkers(b *testing.B) {
+	setTestVersion(b, "1.0.0")
+
+	bars := multiSymbolBars(200)
+
+	for _, workers := range []int{0, 3} {
+		b.Run(fmt.Sprintf("%d workers", workers), func(b *testing.B) {
+			for b.Loop() {
+				runMultiSymbolBacktest(b, symbolWorkersConfig(workers, 0), newMomentumStrategy(200_000), bars)
+			}
+		})
+	}
+}
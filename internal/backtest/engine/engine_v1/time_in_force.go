@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// fillsImmediately reports whether the order must fill on the bar it is
+// executed on instead of resting in the pending orders: IOC and FOK orders.
+func fillsImmediately(order types.ExecuteOrder) bool {
+	return order.TimeInForce == types.TimeInForceIOC || order.TimeInForce == types.TimeInForceFOK
+}
+
+// restOrder keeps an order that cannot fill on the current bar pending, unless
+// its time in force requires an immediate fill, in which case it expires.
+func (b *BacktestTrading) restOrder(order types.ExecuteOrder) error {
+	if fillsImmediately(order) {
+		return b.expireOrder(order)
+	}
+
+	b.pendingOrders = append(b.pendingOrders, order)
+
+	return nil
+}
+
+// expireOrder records that the unfilled quantity of an IOC or FOK order did not
+// fill. An IOC order is cancelled for the rest of its quantity; a FOK order is
+// rejected as a whole.
+func (b *BacktestTrading) expireOrder(order types.ExecuteOrder) error {
+	order.Quantity = b.unfilledQuantity(order)
+
+	reason := types.OrderReasonImmediateOrCancel
+	status := types.OrderStatusCancelled
+	message := fmt.Sprintf("immediate-or-cancel order cancelled with %.2f unfilled", order.Quantity)
+
+	if order.TimeInForce == types.TimeInForceFOK {
+		reason = types.OrderReasonFillOrKill
+		status = types.OrderStatusRejected
+		message = fmt.Sprintf("fill-or-kill order of %.2f cannot fill in full on the current bar", order.Quantity)
+	}
+
+	expiredOrder := b.createFailedOrder(order, order.Price, reason, message)
+	expiredOrder.Status = status
+
+	// The part of an IOC order that filled still counts as an accepted order
+	if order.FilledQuantity > 0 {
+		b.lastFailedOrderID = ""
+	}
+
+	return b.state.StoreFailedOrder(expiredOrder)
+}
+
+// goodTillDatePassed reports whether the order has a GoodTillDate that is
+// before the current bar.
+func (b *BacktestTrading) goodTillDatePassed(order types.ExecuteOrder) bool {
+	return !order.GoodTillDate.IsZero() && order.GoodTillDate.Before(b.marketData.Time)
+}
+
+// cancelExpiredOrder records that a pending order was cancelled because its
+// GoodTillDate passed before the rest of its quantity filled.
+func (b *BacktestTrading) cancelExpiredOrder(order types.ExecuteOrder) error {
+	order.Quantity = b.unfilledQuantity(order)
+
+	message := fmt.Sprintf("order expired at %s with %.2f unfilled", order.GoodTillDate.Format(time.RFC3339), order.Quantity)
+
+	expiredOrder := b.createFailedOrder(order, order.Price, types.OrderReasonExpired, message)
+	expiredOrder.Status = types.OrderStatusCancelled
+
+	return b.state.StoreFailedOrder(expiredOrder)
+}
@@ -0,0 +1,230 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// WindowSpec is one window of a walk-forward run.
+type WindowSpec struct {
+	// Name names the window's results folder. Defaults to window_<index>.
+	Name string
+	// Start is the time of the first bar of the window (inclusive).
+	Start time.Time
+	// End is the time of the last bar of the window (inclusive).
+	End time.Time
+	// CarryOver starts the window with the cash and open positions the
+	// previous window ended with. Without it the window starts flat with the
+	// initial capital. Ignored for the first window.
+	CarryOver bool
+}
+
+// WindowResult is the outcome of one walk-forward window.
+type WindowResult struct {
+	Window           WindowSpec
+	RunID            string
+	ResultFolderPath string
+	// Stats are the per-symbol statistics of the run. A carried-over window
+	// shares its state with the previous windows, so its stats cover them too.
+	Stats []types.TradeStats
+	// Trades are the trades executed within the window.
+	Trades []types.Trade
+	// RealizedPnL is the sum of the PnL of Trades.
+	RealizedPnL float64
+	// Fees is the sum of the fees of Trades.
+	Fees float64
+}
+
+// WalkForwardSummary aggregates the windows of a walk-forward run.
+type WalkForwardSummary struct {
+	Windows        int
+	NumberOfTrades int
+	RealizedPnL    float64
+	Fees           float64
+}
+
+// WalkForwardResult is the walk-forward run of one strategy, config and data file.
+type WalkForwardResult struct {
+	StrategyName string
+	ConfigName   string
+	DataPath     string
+	Windows      []WindowResult
+	Summary      WalkForwardSummary
+}
+
+// WalkForwardRun runs every strategy, config and data file on each window in
+// turn, reading only the bars of the window's time range, and returns the
+// per-window results with their summary. The windows must be sequential and
+// must not overlap. Results are written like Run does, with one folder per window.
+func (b *BacktestEngineV1) WalkForwardRun(ctx context.Context, windows []WindowSpec, callbacks engine.LifecycleCallbacks) ([]WalkForwardResult, error) {
+	if err := b.preRunCheck(); err != nil {
+		return nil, err
+	}
+
+	windows, err := normalizeWindows(windows)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := b.strategyConfigItems()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionFolder := filepath.Join(b.resultsFolder, b.clock.Now().Format("20060102_150405"))
+	b.resultsFolder = sessionFolder
+
+	// The windows replace the configured time range for the duration of the run
+	startTime, endTime := b.config.StartTime, b.config.EndTime
+	defer func() {
+		b.config.StartTime, b.config.EndTime = startTime, endTime
+	}()
+
+	var runErr error
+
+	defer func() {
+		if callbacks.OnBacktestEnd != nil {
+			(*callbacks.OnBacktestEnd)(runErr)
+		}
+	}()
+
+	if callbacks.OnBacktestStart != nil {
+		if err := (*callbacks.OnBacktestStart)(len(b.strategies), len(configs), len(b.dataPaths)); err != nil {
+			runErr = errors.Wrap(errors.ErrCodeCallbackFailed, "OnBacktestStart callback failed", err)
+
+			return nil, runErr
+		}
+	}
+
+	var results []WalkForwardResult
+
+	for strategyIdx, strategy := range b.strategies {
+		if callbacks.OnStrategyStart != nil {
+			if err := (*callbacks.OnStrategyStart)(strategyIdx, strategy.Name(), len(b.strategies)); err != nil {
+				runErr = errors.Wrap(errors.ErrCodeCallbackFailed, "OnStrategyStart callback failed", err)
+
+				return nil, runErr
+			}
+		}
+
+		for configIdx, cfg := range configs {
+			for dataIdx, dataPath := range b.dataPaths {
+				result := WalkForwardResult{
+					StrategyName: strategy.Name(),
+					ConfigName:   cfg.name,
+					DataPath:     dataPath,
+					Windows:      make([]WindowResult, 0, len(windows)),
+					Summary:      WalkForwardSummary{Windows: 0, NumberOfTrades: 0, RealizedPnL: 0, Fees: 0},
+				}
+
+				for windowIdx, window := range windows {
+					b.config.StartTime = optional.Some(window.Start)
+					b.config.EndTime = optional.Some(window.End)
+
+					params := runIterationParams{
+						ctx:              ctx,
+						strategy:         strategy,
+						strategyPath:     b.strategyPaths[strategyIdx],
+						runID:            uuid.New().String(),
+						configIdx:        configIdx,
+						configName:       cfg.name,
+						configContent:    cfg.content,
+						dataIdx:          dataIdx,
+						dataPath:         dataPath,
+						callbacks:        callbacks,
+						resultFolderPath: filepath.Join(getResultFolder(cfg.name, dataPath, b, strategy), window.Name),
+						keepState:        windowIdx+1 < len(windows) && windows[windowIdx+1].CarryOver,
+					}
+
+					if err := b.runSingleIteration(params); err != nil {
+						runErr = err
+
+						return nil, runErr
+					}
+
+					windowResult := b.windowResult(window, params)
+					result.Windows = append(result.Windows, windowResult)
+					result.Summary.Windows++
+					result.Summary.NumberOfTrades += len(windowResult.Trades)
+					result.Summary.RealizedPnL += windowResult.RealizedPnL
+					result.Summary.Fees += windowResult.Fees
+				}
+
+				results = append(results, result)
+			}
+		}
+
+		if callbacks.OnStrategyEnd != nil {
+			(*callbacks.OnStrategyEnd)(strategyIdx, strategy.Name())
+		}
+	}
+
+	return results, nil
+}
+
+// windowResult collects the results the run of window captured.
+func (b *BacktestEngineV1) windowResult(window WindowSpec, params runIterationParams) WindowResult {
+	result := WindowResult{
+		Window:           window,
+		RunID:            params.runID,
+		ResultFolderPath: params.resultFolderPath,
+		Stats:            nil,
+		Trades:           nil,
+		RealizedPnL:      0,
+		Fees:             0,
+	}
+
+	if b.lastRun == nil {
+		return result
+	}
+
+	result.Stats = b.lastRun.stats
+
+	// A carried-over state still holds the trades of the previous windows
+	for _, trade := range b.lastRun.trades {
+		if trade.ExecutedAt.Before(window.Start) || trade.ExecutedAt.After(window.End) {
+			continue
+		}
+
+		result.Trades = append(result.Trades, trade)
+		result.RealizedPnL += trade.PnL
+		result.Fees += trade.Fee
+	}
+
+	return result
+}
+
+// normalizeWindows validates the windows and names the unnamed ones.
+func normalizeWindows(windows []WindowSpec) ([]WindowSpec, error) {
+	if len(windows) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidParameter, "walk-forward run needs at least one window")
+	}
+
+	normalized := make([]WindowSpec, len(windows))
+
+	for i, window := range windows {
+		if window.Start.IsZero() || window.End.IsZero() || window.End.Before(window.Start) {
+			return nil, errors.Newf(errors.ErrCodeInvalidParameter, "window %d must have a start before its end", i)
+		}
+
+		if i > 0 && !window.Start.After(windows[i-1].End) {
+			return nil, errors.Newf(errors.ErrCodeInvalidParameter, "window %d must start after window %d ends", i, i-1)
+		}
+
+		if window.Name == "" {
+			window.Name = fmt.Sprintf("window_%d", i)
+		}
+
+		normalized[i] = window
+	}
+
+	return normalized, nil
+}
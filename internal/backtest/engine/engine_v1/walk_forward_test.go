@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/moznion/go-optional"
+	engine_types "github.com/rxtech-lab/argo-trading/internal/backtest/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const walkForwardCapital = 100000
+
+// walkForwardBars is a synthetic series of one symbol with one bar a minute
+// whose close oscillates, so the momentum strategy trades in every window.
+func walkForwardBars(count int) []types.MarketData {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	bars := make([]types.MarketData, count)
+
+	for i := range bars {
+		price := 100 + 10*math.Sin(float64(i)*0.7)
+		bars[i] = types.MarketData{
+			Id:     "AAA-" + start.Add(time.Duration(i)*time.Minute).Format("1504"),
+			Symbol: "AAA",
+			Time:   start.Add(time.Duration(i) * time.Minute),
+			Open:   price,
+			High:   price + 1,
+			Low:    price - 1,
+			Close:  price,
+			Volume: 1_000_000,
+		}
+	}
+
+	return bars
+}
+
+// threeWindows splits bars into three windows of ten bars.
+func threeWindows(bars []types.MarketData, carryOver bool) []WindowSpec {
+	return []WindowSpec{
+		{Name: "", Start: bars[0].Time, End: bars[9].Time, CarryOver: false},
+		{Name: "", Start: bars[10].Time, End: bars[19].Time, CarryOver: carryOver},
+		{Name: "oos", Start: bars[20].Time, End: bars[29].Time, CarryOver: carryOver},
+	}
+}
+
+// newWalkForwardEngine returns an engine reading bars from a data source that
+// honors the requested time range like the DuckDB data source does.
+func newWalkForwardEngine(t *testing.T, bars []types.MarketData) *BacktestEngineV1 {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	inRange := func(bar types.MarketData, start, end optional.Option[time.Time]) bool {
+		return (start.IsNone() || !bar.Time.Before(start.Unwrap())) && (end.IsNone() || !bar.Time.After(end.Unwrap()))
+	}
+
+	mockDatasource := mocks.NewMockDataSource(ctrl)
+	mockDatasource.EXPECT().Initialize(gomock.Any()).Return(nil).AnyTimes()
+	mockDatasource.EXPECT().ReadAll(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(start, end optional.Option[time.Time]) func(yield func(types.MarketData, error) bool) {
+			return func(yield func(types.MarketData, error) bool) {
+				for _, bar := range bars {
+					if inRange(bar, start, end) && !yield(bar, nil) {
+						return
+					}
+				}
+			}
+		}).AnyTimes()
+	mockDatasource.EXPECT().Count(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(start, end optional.Option[time.Time]) (int, error) {
+			count := 0
+
+			for _, bar := range bars {
+				if inRange(bar, start, end) {
+					count++
+				}
+			}
+
+			return count, nil
+		}).AnyTimes()
+	mockDatasource.EXPECT().GetAllSymbols().Return([]string{"AAA"}, nil).AnyTimes()
+	mockDatasource.EXPECT().ReadLastData(gomock.Any()).Return(bars[len(bars)-1], nil).AnyTimes()
+
+	e, err := NewBacktestEngineV1()
+	require.NoError(t, err)
+
+	backtestEngine := e.(*BacktestEngineV1)
+	require.NoError(t, backtestEngine.Initialize("initial_capital: 100000"))
+	require.NoError(t, backtestEngine.SetDataSource(mockDatasource))
+	require.NoError(t, backtestEngine.LoadStrategy(newMomentumStrategy(0)))
+	require.NoError(t, backtestEngine.SetConfigContent([]string{""}))
+	require.NoError(t, backtestEngine.SetResultsFolder(t.TempDir()))
+	backtestEngine.dataPaths = []string{"bars.parquet"}
+
+	return backtestEngine
+}
+
+// cashFlow is the change of the cash balance caused by trade.
+func cashFlow(trade types.Trade) float64 {
+	notional := trade.ExecutedQty * trade.ExecutedPrice
+	if trade.Order.Side == types.PurchaseTypeBuy {
+		return -notional - trade.Fee
+	}
+
+	return notional - trade.Fee
+}
+
+func TestWalkForwardRun(t *testing.T) {
+	setTestVersion(t, "1.0.0")
+
+	t.Run("windows are isolated and the summary adds up", func(t *testing.T) {
+		bars := walkForwardBars(30)
+		windows := threeWindows(bars, false)
+		backtestEngine := newWalkForwardEngine(t, bars)
+
+		results, err := backtestEngine.WalkForwardRun(context.Background(), windows, engine_types.LifecycleCallbacks{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		result := results[0]
+		require.Len(t, result.Windows, 3)
+		assert.Equal(t, "MomentumStrategy", result.StrategyName)
+		assert.Equal(t, []string{"window_0", "window_1", "oos"},
+			[]string{result.Windows[0].Window.Name, result.Windows[1].Window.Name, result.Windows[2].Window.Name})
+
+		var trades int
+
+		var pnl, fees float64
+
+		for i, window := range result.Windows {
+			require.NotEmpty(t, window.Trades, "window %d should trade", i)
+
+			for _, trade := range window.Trades {
+				assert.False(t, trade.ExecutedAt.Before(windows[i].Start), "trade before window %d", i)
+				assert.False(t, trade.ExecutedAt.After(windows[i].End), "trade after window %d", i)
+			}
+
+			// Every window starts flat with the initial capital
+			first := window.Trades[0]
+			assert.Equal(t, types.PurchaseTypeBuy, first.Order.Side)
+			assert.InDelta(t, walkForwardCapital+cashFlow(first), first.Balance, 1e-6)
+
+			require.Len(t, window.Stats, 1)
+			assert.Equal(t, len(window.Trades), window.Stats[0].TradeResult.NumberOfTrades, "stats cover only the window")
+			assert.DirExists(t, window.ResultFolderPath)
+			assert.NotEmpty(t, window.RunID)
+
+			trades += len(window.Trades)
+			pnl += window.RealizedPnL
+			fees += window.Fees
+		}
+
+		assert.Equal(t, 3, result.Summary.Windows)
+		assert.Equal(t, trades, result.Summary.NumberOfTrades)
+		assert.InDelta(t, pnl, result.Summary.RealizedPnL, 1e-9)
+		assert.InDelta(t, fees, result.Summary.Fees, 1e-9)
+
+		// The configured time range is restored after the run
+		assert.True(t, backtestEngine.config.StartTime.IsNone())
+		assert.True(t, backtestEngine.config.EndTime.IsNone())
+	})
+
+	t.Run("carry over keeps cash and positions between windows", func(t *testing.T) {
+		bars := walkForwardBars(30)
+		windows := threeWindows(bars, true)
+		backtestEngine := newWalkForwardEngine(t, bars)
+
+		results, err := backtestEngine.WalkForwardRun(context.Background(), windows, engine_types.LifecycleCallbacks{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		result := results[0]
+		require.Len(t, result.Windows, 3)
+
+		var trades int
+
+		for i := 1; i < len(result.Windows); i++ {
+			previous := result.Windows[i-1].Trades
+			current := result.Windows[i].Trades
+			require.NotEmpty(t, previous)
+			require.NotEmpty(t, current)
+
+			// The first trade of the window continues from the cash the previous window ended with
+			last := previous[len(previous)-1]
+			assert.InDelta(t, last.Balance+cashFlow(current[0]), current[0].Balance, 1e-6)
+
+			for _, trade := range current {
+				assert.False(t, trade.ExecutedAt.Before(windows[i].Start))
+			}
+		}
+
+		for _, window := range result.Windows {
+			trades += len(window.Trades)
+		}
+
+		assert.Equal(t, trades, result.Summary.NumberOfTrades)
+		assert.Equal(t, trades, result.Windows[2].Stats[0].TradeResult.NumberOfTrades,
+			"stats of a carried-over window cover the previous windows")
+	})
+
+	t.Run("invalid windows", func(t *testing.T) {
+		bars := walkForwardBars(30)
+		backtestEngine := newWalkForwardEngine(t, bars)
+
+		for name, windows := range map[string][]WindowSpec{
+			"no windows":  nil,
+			"end first":   {{Start: bars[9].Time, End: bars[0].Time}},
+			"overlapping": {{Start: bars[0].Time, End: bars[9].Time}, {Start: bars[9].Time, End: bars[19].Time}},
+		} {
+			_, err := backtestEngine.WalkForwardRun(context.Background(), windows, engine_types.LifecycleCallbacks{})
+			assert.Error(t, err, name)
+		}
+	})
+}
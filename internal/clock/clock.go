@@ -0,0 +1,52 @@
+// Package clock abstracts wall-clock time so code that timestamps or waits can
+// be driven by a fake clock in tests.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and waits for durations to pass.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the time once d has passed.
+	After(d time.Duration) <-chan time.Time
+	// Sleep blocks until d has passed.
+	Sleep(d time.Duration)
+}
+
+// Real is the Clock of the time package.
+type Real struct{}
+
+// New returns the real clock.
+func New() Clock {
+	return Real{}
+}
+
+// Now implements Clock.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// After implements Clock.
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Sleep implements Clock.
+func (Real) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// SleepContext waits until d has passed on c, returning early with the
+// context's error when it is cancelled.
+func SleepContext(ctx context.Context, c Clock, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.After(d):
+		return nil
+	}
+}
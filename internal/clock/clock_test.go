@@ -0,0 +1,67 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("time only moves on advance", func(t *testing.T) {
+		f := NewFake(start)
+		assert.Equal(t, start, f.Now())
+
+		f.Advance(time.Minute)
+		assert.Equal(t, start.Add(time.Minute), f.Now())
+	})
+
+	t.Run("after fires once advanced past it", func(t *testing.T) {
+		f := NewFake(start)
+		ch := f.After(30 * time.Second)
+
+		f.Advance(29 * time.Second)
+		select {
+		case <-ch:
+			t.Fatal("fired before the duration passed")
+		default:
+		}
+
+		f.Advance(time.Second)
+		assert.Equal(t, start.Add(30*time.Second), <-ch)
+		assert.Equal(t, 0, f.Waiters())
+	})
+
+	t.Run("non-positive durations fire immediately", func(t *testing.T) {
+		f := NewFake(start)
+		assert.Equal(t, start, <-f.After(0))
+	})
+
+	t.Run("sleep ends when another goroutine advances", func(t *testing.T) {
+		f := NewFake(start)
+		done := make(chan struct{})
+
+		go func() {
+			f.Sleep(time.Second)
+			close(done)
+		}()
+
+		f.BlockUntil(1)
+		f.Advance(time.Second)
+		<-done
+	})
+}
+
+func TestSleepContext(t *testing.T) {
+	f := NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, SleepContext(ctx, f, time.Hour), context.Canceled)
+
+	assert.NoError(t, SleepContext(context.Background(), New(), time.Millisecond))
+}
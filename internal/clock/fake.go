@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when Advance is called. Waits started
+// with After or Sleep end once the time has been advanced past them.
+type Fake struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+// fakeWaiter is a pending After of a Fake.
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFake returns a fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{
+		mu:      sync.Mutex{},
+		changed: nil,
+		now:     now,
+		waiters: nil,
+	}
+	f.changed = sync.NewCond(&f.mu)
+
+	return f
+}
+
+// Now implements Clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// After implements Clock. A non-positive d fires immediately.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+
+		return ch
+	}
+
+	f.waiters = append(f.waiters, fakeWaiter{at: f.now.Add(d), ch: ch})
+	f.changed.Broadcast()
+
+	return ch
+}
+
+// Sleep implements Clock. It blocks until the clock is advanced by d.
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// Advance moves the time forward by d and ends the waits that are due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+
+	pending := f.waiters[:0]
+
+	for _, waiter := range f.waiters {
+		if waiter.at.After(f.now) {
+			pending = append(pending, waiter)
+
+			continue
+		}
+
+		waiter.ch <- f.now
+	}
+
+	f.waiters = pending
+	f.changed.Broadcast()
+}
+
+// Waiters returns the number of waits that have not ended yet.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil blocks until at least n waits are pending, so a test can advance
+// the clock once the code under test has started waiting.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.changed.Wait()
+	}
+}
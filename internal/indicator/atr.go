@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/cache"
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/pkg/errors"
@@ -29,6 +30,7 @@ func (a *ATR) Name() types.IndicatorType {
 }
 
 // Config configures the ATR indicator. Expected parameters: period (int).
+// A float period is accepted as well, since JSON configs decode numbers as float64.
 func (a *ATR) Config(params ...any) error {
 	if len(params) != 1 {
 		return errors.New(errors.ErrCodeMissingParameter, "Config expects 1 parameter: period (int)")
@@ -36,7 +38,12 @@ func (a *ATR) Config(params ...any) error {
 
 	period, ok := params[0].(int)
 	if !ok {
-		return errors.New(errors.ErrCodeInvalidType, "invalid type for period parameter, expected int")
+		periodFloat, ok := params[0].(float64)
+		if !ok {
+			return errors.New(errors.ErrCodeInvalidType, "invalid type for period parameter, expected int or float")
+		}
+
+		period = int(periodFloat)
 	}
 
 	if period <= 0 {
@@ -68,7 +75,15 @@ func (a *ATR) GetSignal(marketData types.MarketData, ctx IndicatorContext) (type
 	}, nil
 }
 
-// RawValue implements the Indicator interface.
+// RawValue implements the Indicator interface. It returns the ATR of a symbol
+// at the given time, smoothed with Wilder's method: the first value is the
+// average true range of period bars, and each later bar moves the ATR by
+// (TR - ATR) / period. Parameters: symbol (string), currentTime (time.Time),
+// ctx (IndicatorContext) and optionally period (int). A zero currentTime uses
+// the symbol's latest bar.
+//
+// The running ATR is kept in the cache of the context, so querying every bar
+// only reads the bars added since the previous query.
 func (a *ATR) RawValue(params ...any) (float64, error) {
 	if len(params) < 3 {
 		return 0, errors.New(errors.ErrCodeMissingParameter, "RawValue requires at least 3 parameters: symbol (string), currentTime (time.Time), ctx (IndicatorContext)")
@@ -89,57 +104,103 @@ func (a *ATR) RawValue(params ...any) (float64, error) {
 		return 0, errors.New(errors.ErrCodeInvalidType, "third parameter must be of type IndicatorContext")
 	}
 
-	// Get market data
-	var marketData types.MarketData
+	period := a.period
 
-	var err error
+	if len(params) >= 4 {
+		period, ok = params[3].(int)
+		if !ok {
+			return 0, errors.New(errors.ErrCodeInvalidType, "fourth parameter must be of type int (period)")
+		}
 
-	if !currentTime.IsZero() {
-		endTime := currentTime
-		startTime := endTime.Add(-time.Hour * 24)
+		if period <= 0 {
+			return 0, errors.Newf(errors.ErrCodeInvalidPeriod, "period must be a positive integer, got %d", period)
+		}
+	}
 
-		historicalData, err := ctx.DataSource.GetRange(startTime, endTime, optional.None[datasource.Interval]())
+	if currentTime.IsZero() {
+		marketData, err := ctx.DataSource.ReadLastData(symbol)
 		if err != nil {
-			return 0, errors.Wrap(errors.ErrCodeHistoricalDataFailed, "failed to get historical data", err)
+			return 0, errors.Wrap(errors.ErrCodeDataNotFound, "failed to get latest market data", err)
 		}
 
-		if len(historicalData) == 0 {
-			return 0, errors.New(errors.ErrCodeNoDataFound, "no historical data available for the specified time range")
-		}
+		currentTime = marketData.Time
+	}
 
-		// Check if we have enough data points for ATR calculation
-		if len(historicalData) < a.period {
-			return 0, errors.NewInsufficientDataErrorf(a.period, len(historicalData), symbol, "insufficient historical data for ATR calculation for symbol %s: required %d, got %d", symbol, a.period, len(historicalData))
+	key := cache.ATRStateKey{Symbol: symbol, Period: period}
+
+	var states map[cache.ATRStateKey]cache.ATRState
+	if cacheV1, ok := ctx.Cache.(*cache.CacheV1); ok {
+		states = cacheV1.ATRStates
+	}
+
+	state, ok := states[key]
+	if ok && !currentTime.Before(state.LastTime) {
+		if currentTime.Equal(state.LastTime) {
+			return state.ATR, nil
 		}
 
-		marketData = historicalData[len(historicalData)-1]
-	} else {
-		marketData, err = ctx.DataSource.ReadLastData(symbol)
-		if err != nil {
-			return 0, errors.Wrap(errors.ErrCodeDataNotFound, "failed to get latest market data", err)
+		// Continue from the cached ATR with the bars added since
+		bars, err := ctx.DataSource.GetRangeForSymbol(symbol, state.LastTime, currentTime, optional.None[datasource.Interval]())
+		if err == nil {
+			for _, bar := range bars {
+				if !bar.Time.After(state.LastTime) {
+					continue
+				}
+
+				state.ATR += (trueRange(bar, state.PrevClose) - state.ATR) / float64(period)
+				state.PrevClose = bar.Close
+				state.LastTime = bar.Time
+			}
+
+			states[key] = state
+
+			return state.ATR, nil
 		}
 	}
 
-	// Calculate True Range
-	tr := math.Max(
-		math.Max(
-			marketData.High-marketData.Low,
-			math.Abs(marketData.High-marketData.Close),
-		),
-		math.Abs(marketData.Low-marketData.Close),
-	)
-
-	// Get EMA indicator for smoothing
-	emaIndicator, err := ctx.IndicatorRegistry.GetIndicator(types.IndicatorTypeEMA)
+	state, err := a.seed(symbol, currentTime, period, ctx)
 	if err != nil {
-		return 0, errors.Wrap(errors.ErrCodeIndicatorNotFound, "failed to get EMA indicator", err)
+		return 0, err
+	}
+
+	if states != nil {
+		states[key] = state
 	}
 
-	// Calculate ATR using EMA
-	atrValue, err := emaIndicator.RawValue(marketData.Symbol, marketData.Time, ctx, a.period, tr)
+	return state.ATR, nil
+}
+
+// seed starts the ATR of a symbol from the average true range of the period
+// bars up to currentTime. The bar before them provides the first previous close.
+func (a *ATR) seed(symbol string, currentTime time.Time, period int, ctx IndicatorContext) (cache.ATRState, error) {
+	bars, err := ctx.DataSource.GetPreviousNumberOfDataPoints(currentTime, symbol, period+1)
 	if err != nil {
-		return 0, errors.Wrap(errors.ErrCodeIndicatorCalculation, "failed to calculate ATR", err)
+		return cache.ATRState{}, errors.Wrap(errors.ErrCodeHistoricalDataFailed, "failed to get historical data", err)
+	}
+
+	if len(bars) < period+1 {
+		return cache.ATRState{}, errors.NewInsufficientDataErrorf(period+1, len(bars), symbol, "insufficient historical data for ATR calculation for symbol %s: required %d, got %d", symbol, period+1, len(bars))
 	}
 
-	return atrValue, nil
+	sum := 0.0
+	for i := 1; i < len(bars); i++ {
+		sum += trueRange(bars[i], bars[i-1].Close)
+	}
+
+	last := bars[len(bars)-1]
+
+	return cache.ATRState{
+		LastTime:  last.Time,
+		PrevClose: last.Close,
+		ATR:       sum / float64(period),
+	}, nil
+}
+
+// trueRange returns the greatest of the bar's range and the distances from the
+// previous close to its high and low.
+func trueRange(bar types.MarketData, prevClose float64) float64 {
+	return math.Max(
+		bar.High-bar.Low,
+		math.Max(math.Abs(bar.High-prevClose), math.Abs(bar.Low-prevClose)),
+	)
 }
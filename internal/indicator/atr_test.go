@@ -2,7 +2,9 @@ package indicator
 
 import (
 	"testing"
+	"time"
 
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/cache"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/stretchr/testify/suite"
 )
@@ -108,3 +110,66 @@ func (suite *ATRTestSuite) TestConfigMultipleTimes() {
 	suite.NoError(err)
 	suite.Equal(30, atrImpl.period)
 }
+
+func (suite *ATRTestSuite) TestRawValueReference() {
+	bars := referenceBars("AAPL", time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC))
+	ctx := IndicatorContext{
+		DataSource:        newReferenceDataSource(bars),
+		IndicatorRegistry: nil,
+		Cache:             cache.NewCacheV1(),
+	}
+
+	atr := NewATR()
+	suite.Require().NoError(atr.Config(5))
+
+	// Wilder's ATR(5) of the reference series
+	expected := map[int]float64{5: 3.0, 6: 3.1, 8: 3.064, 11: 3.032768}
+
+	for i := 5; i < len(bars); i++ {
+		value, err := atr.RawValue("AAPL", bars[i].Time, ctx)
+		suite.Require().NoError(err)
+
+		if want, ok := expected[i]; ok {
+			suite.InDelta(want, value, 1e-9, "bar %d", i)
+		}
+	}
+
+	// Querying the same bar again returns the cached value
+	value, err := atr.RawValue("AAPL", bars[11].Time, ctx)
+	suite.Require().NoError(err)
+	suite.InDelta(3.032768, value, 1e-9)
+}
+
+func (suite *ATRTestSuite) TestRawValueSkippedBars() {
+	bars := referenceBars("AAPL", time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC))
+	ctx := IndicatorContext{
+		DataSource:        newReferenceDataSource(bars),
+		IndicatorRegistry: nil,
+		Cache:             cache.NewCacheV1(),
+	}
+
+	atr := NewATR()
+
+	// The bars between two queries are still smoothed in
+	_, err := atr.RawValue("AAPL", bars[5].Time, ctx, 5)
+	suite.Require().NoError(err)
+
+	value, err := atr.RawValue("AAPL", bars[11].Time, ctx, 5)
+	suite.Require().NoError(err)
+	suite.InDelta(3.032768, value, 1e-9)
+
+	// Without a cache the ATR starts from the bars before the queried one
+	ctx.Cache = nil
+	value, err = atr.RawValue("AAPL", bars[5].Time, ctx, 5)
+	suite.Require().NoError(err)
+	suite.InDelta(3.0, value, 1e-9)
+
+	_, err = atr.RawValue("AAPL", bars[4].Time, ctx, 5)
+	suite.Error(err)
+}
+
+func (suite *ATRTestSuite) TestConfigFloatPeriod() {
+	atr := NewATR()
+	suite.Require().NoError(atr.Config(float64(7)))
+	suite.Equal(7, atr.(*ATR).period)
+}
@@ -32,6 +32,8 @@ func (bb *BollingerBands) Name() types.IndicatorType {
 }
 
 // Config configures the Bollinger Bands indicator. Expected parameters: period (int), stdDev (float64), lookback (time.Duration).
+// So that the indicator can be configured from JSON, the period may also be a
+// float and the lookback a duration string such as "24h".
 func (bb *BollingerBands) Config(params ...any) error {
 	if len(params) != 3 {
 		return errors.New(errors.ErrCodeMissingParameter, "Config expects 3 parameters: period (int), stdDev (float64), lookback (time.Duration)")
@@ -39,7 +41,12 @@ func (bb *BollingerBands) Config(params ...any) error {
 
 	period, ok := params[0].(int)
 	if !ok {
-		return errors.New(errors.ErrCodeInvalidType, "invalid type for period parameter, expected int")
+		periodFloat, ok := params[0].(float64)
+		if !ok {
+			return errors.New(errors.ErrCodeInvalidType, "invalid type for period parameter, expected int or float")
+		}
+
+		period = int(periodFloat)
 	}
 
 	if period <= 0 {
@@ -55,8 +62,19 @@ func (bb *BollingerBands) Config(params ...any) error {
 		return errors.Newf(errors.ErrCodeInvalidStdDevPeriod, "stdDev must be a positive number, got %f", stdDev)
 	}
 
-	lookback, ok := params[2].(time.Duration)
-	if !ok {
+	var lookback time.Duration
+
+	switch value := params[2].(type) {
+	case time.Duration:
+		lookback = value
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Wrap(errors.ErrCodeInvalidType, "invalid type for lookback parameter, expected a duration string", err)
+		}
+
+		lookback = parsed
+	default:
 		return errors.New(errors.ErrCodeInvalidType, "invalid type for lookback parameter, expected time.Duration")
 	}
 
@@ -98,7 +116,7 @@ func (bb *BollingerBands) RawValue(params ...any) (float64, error) {
 	startTime := marketData.Time.Add(-bb.lookback)
 	endTime := marketData.Time
 
-	historicalData, err := ctx.DataSource.GetRange(startTime, endTime, optional.None[datasource.Interval]())
+	historicalData, err := ctx.DataSource.GetRangeForSymbol(marketData.Symbol, startTime, endTime, optional.None[datasource.Interval]())
 	if err != nil {
 		return 0, errors.Wrap(errors.ErrCodeHistoricalDataFailed, "failed to get historical data", err)
 	}
@@ -120,7 +138,7 @@ func (bb *BollingerBands) GetSignal(marketData types.MarketData, ctx IndicatorCo
 	startTime := marketData.Time.Add(-bb.lookback)
 	endTime := marketData.Time
 
-	historicalData, err := ctx.DataSource.GetRange(startTime, endTime, optional.None[datasource.Interval]())
+	historicalData, err := ctx.DataSource.GetRangeForSymbol(marketData.Symbol, startTime, endTime, optional.None[datasource.Interval]())
 	if err != nil {
 		return types.Signal{}, err
 	}
@@ -186,3 +186,53 @@ func (suite *BollingerBandsTestSuite) TestInsufficientDataError() {
 	suite.Equal("test error message", err.Error())
 	suite.True(errors.IsInsufficientDataError(err))
 }
+
+func (suite *BollingerBandsTestSuite) TestConfigFromJSON() {
+	bb := NewBollingerBands()
+	bbImpl := bb.(*BollingerBands)
+
+	// JSON configs decode numbers as float64 and durations as strings
+	err := bb.Config(float64(10), 1.5, "12h")
+	suite.NoError(err)
+	suite.Equal(10, bbImpl.period)
+	suite.Equal(time.Hour*12, bbImpl.lookback)
+
+	err = bb.Config(10, 1.5, "soon")
+	suite.Error(err)
+}
+
+func (suite *BollingerBandsTestSuite) TestGetSignalReference() {
+	start := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	bars := referenceBars("AAPL", start)
+	ctx := IndicatorContext{
+		// Bars of another symbol must not mix into the bands
+		DataSource:        newReferenceDataSource(bars, referenceBars("MSFT", start)[:6]),
+		IndicatorRegistry: nil,
+		Cache:             nil,
+	}
+
+	bb := NewBollingerBands()
+	suite.Require().NoError(bb.Config(5, 2.0, time.Hour))
+
+	// Bollinger Bands(5, 2) of the reference series: upper, middle, lower
+	expected := map[int][3]float64{
+		4:  {103.80665927567458, 100.8, 97.79334072432542},
+		7:  {105.36145515053249, 101.1, 96.8385448494675},
+		11: {105.25657137141715, 102.4, 99.54342862858286},
+	}
+
+	for i, want := range expected {
+		signal, err := bb.GetSignal(bars[i], ctx)
+		suite.Require().NoError(err)
+
+		rawValue, ok := signal.RawValue.(map[string]float64)
+		suite.Require().True(ok)
+		suite.InDelta(want[0], rawValue["upper"], 1e-9, "bar %d", i)
+		suite.InDelta(want[1], rawValue["middle"], 1e-9, "bar %d", i)
+		suite.InDelta(want[2], rawValue["lower"], 1e-9, "bar %d", i)
+
+		middle, err := bb.RawValue(bars[i], ctx)
+		suite.Require().NoError(err)
+		suite.InDelta(want[1], middle, 1e-9, "bar %d", i)
+	}
+}
@@ -2,10 +2,59 @@ package indicator
 
 import (
 	"testing"
+	"time"
 
+	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/stretchr/testify/suite"
 )
 
+// referenceBars returns a fixed series of one-minute bars starting at start.
+// The expected ATR, Bollinger Bands and VWAP values of the indicator tests
+// were calculated from this series independently of the indicators.
+func referenceBars(symbol string, start time.Time) []types.MarketData {
+	ohlcv := [][5]float64{
+		{100.0, 102.0, 99.0, 101.0, 1000},
+		{101.0, 103.5, 100.5, 103.0, 1500},
+		{103.0, 104.0, 101.0, 101.5, 1200},
+		{101.5, 102.5, 99.5, 100.0, 1800},
+		{100.0, 101.0, 98.0, 98.5, 2200},
+		{98.5, 100.5, 97.5, 100.0, 1600},
+		{100.0, 103.0, 99.5, 102.5, 2000},
+		{102.5, 105.0, 102.0, 104.5, 2500},
+		{104.5, 106.0, 103.0, 103.5, 1900},
+		{103.5, 104.5, 101.5, 102.0, 1700},
+		{102.0, 103.0, 100.0, 100.5, 2100},
+		{100.5, 102.0, 99.0, 101.5, 1400},
+	}
+
+	bars := make([]types.MarketData, len(ohlcv))
+	for i, v := range ohlcv {
+		bars[i] = types.MarketData{
+			Symbol: symbol,
+			Time:   start.Add(time.Duration(i) * time.Minute),
+			Open:   v[0],
+			High:   v[1],
+			Low:    v[2],
+			Close:  v[3],
+			Volume: v[4],
+		}
+	}
+
+	return bars
+}
+
+// newReferenceDataSource returns a data source holding bars.
+func newReferenceDataSource(bars ...[]types.MarketData) *MockDataSource {
+	ds := NewMockDataSource()
+	for _, series := range bars {
+		for _, bar := range series {
+			ds.AddData(bar)
+		}
+	}
+
+	return ds
+}
+
 type IndicatorInterfaceTestSuite struct {
 	suite.Suite
 }
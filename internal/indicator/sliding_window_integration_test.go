@@ -58,6 +58,16 @@ func (m *MockDataSource) GetRange(start time.Time, end time.Time, interval optio
 	return result, nil
 }
 
+func (m *MockDataSource) GetRangeForSymbol(symbol string, start time.Time, end time.Time, interval optional.Option[datasource.Interval]) ([]types.MarketData, error) {
+	var result []types.MarketData
+	for _, d := range m.data[symbol] {
+		if !d.Time.Before(start) && !d.Time.After(end) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
 func (m *MockDataSource) GetPreviousNumberOfDataPoints(end time.Time, symbol string, count int) ([]types.MarketData, error) {
 	symbolData, ok := m.data[symbol]
 	if !ok {
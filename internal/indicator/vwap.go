@@ -0,0 +1,157 @@
+package indicator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/cache"
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// VWAP represents the session Volume Weighted Average Price indicator. It is
+// the average typical price, (high + low + close) / 3, of the session's bars
+// weighted by their volume. A session is a calendar day in the configured time
+// zone and starts over at midnight.
+type VWAP struct {
+	location *time.Location
+}
+
+// NewVWAP creates a new VWAP indicator with sessions in UTC.
+func NewVWAP() Indicator {
+	return &VWAP{
+		location: time.UTC,
+	}
+}
+
+// Name returns the name of the indicator.
+func (v *VWAP) Name() types.IndicatorType {
+	return types.IndicatorTypeVWAP
+}
+
+// Config configures the VWAP indicator. Expected parameters: timezone (string),
+// the IANA name of the time zone whose midnight starts a session, such as
+// "America/New_York".
+func (v *VWAP) Config(params ...any) error {
+	if len(params) != 1 {
+		return errors.New(errors.ErrCodeMissingParameter, "Config expects 1 parameter: timezone (string)")
+	}
+
+	timezone, ok := params[0].(string)
+	if !ok {
+		return errors.New(errors.ErrCodeInvalidType, "invalid type for timezone parameter, expected string")
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return errors.Wrapf(errors.ErrCodeInvalidParameter, err, "unknown timezone %q", timezone)
+	}
+
+	v.location = location
+
+	return nil
+}
+
+// GetSignal calculates the VWAP of the bar's session.
+func (v *VWAP) GetSignal(marketData types.MarketData, ctx IndicatorContext) (types.Signal, error) {
+	vwapValue, err := v.RawValue(marketData, ctx)
+	if err != nil {
+		return types.Signal{}, err
+	}
+
+	return types.Signal{
+		Time:   marketData.Time,
+		Type:   types.SignalTypeNoAction, // VWAP is a reference price, not a signal by itself
+		Name:   string(v.Name()),
+		Reason: fmt.Sprintf("VWAP value: %.4f", vwapValue),
+		RawValue: map[string]float64{
+			"vwap": vwapValue,
+		},
+		Symbol:    marketData.Symbol,
+		Indicator: v.Name(),
+	}, nil
+}
+
+// RawValue implements the Indicator interface. It returns the VWAP of the
+// session of the given bar, up to and including the bar.
+// It expects types.MarketData as the first parameter and IndicatorContext as the second.
+//
+// The session's running totals are kept in the cache of the context, so
+// querying every bar only reads the bars added since the previous query.
+func (v *VWAP) RawValue(params ...any) (float64, error) {
+	if len(params) < 2 {
+		return 0, errors.New(errors.ErrCodeMissingParameter, "RawValue requires at least 2 parameters: types.MarketData and IndicatorContext")
+	}
+
+	marketData, ok := params[0].(types.MarketData)
+	if !ok {
+		return 0, errors.New(errors.ErrCodeInvalidType, "first parameter must be of type types.MarketData")
+	}
+
+	ctx, ok := params[1].(IndicatorContext)
+	if !ok {
+		return 0, errors.New(errors.ErrCodeInvalidType, "second parameter must be of type IndicatorContext")
+	}
+
+	local := marketData.Time.In(v.location)
+	sessionStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, v.location)
+
+	var states map[string]cache.VWAPState
+	if cacheV1, ok := ctx.Cache.(*cache.CacheV1); ok {
+		states = cacheV1.VWAPStates
+	}
+
+	// Continue the cached session if the bar belongs to it and is not older
+	// than its last bar; otherwise add up the session from its start
+	state, ok := states[marketData.Symbol]
+	if !ok || !state.SessionStart.Equal(sessionStart) || marketData.Time.Before(state.LastTime) {
+		state = cache.VWAPState{
+			SessionStart: sessionStart,
+			LastTime:     time.Time{},
+			PriceVolume:  0,
+			Volume:       0,
+			LastPrice:    0,
+		}
+	}
+
+	if !marketData.Time.Equal(state.LastTime) {
+		start := sessionStart
+		if !state.LastTime.IsZero() {
+			start = state.LastTime
+		}
+
+		bars, err := ctx.DataSource.GetRangeForSymbol(marketData.Symbol, start, marketData.Time, optional.None[datasource.Interval]())
+		if err != nil {
+			return 0, errors.Wrap(errors.ErrCodeHistoricalDataFailed, "failed to get historical data", err)
+		}
+
+		for _, bar := range bars {
+			if !state.LastTime.IsZero() && !bar.Time.After(state.LastTime) {
+				continue
+			}
+
+			typicalPrice := (bar.High + bar.Low + bar.Close) / 3
+			state.PriceVolume += typicalPrice * bar.Volume
+			state.Volume += bar.Volume
+			state.LastPrice = typicalPrice
+			state.LastTime = bar.Time
+		}
+	}
+
+	if state.LastTime.IsZero() {
+		return 0, errors.NewInsufficientDataErrorf(1, 0, marketData.Symbol, "no data in the current session for VWAP calculation for symbol %s", marketData.Symbol)
+	}
+
+	if states != nil {
+		states[marketData.Symbol] = state
+	}
+
+	// Until volume trades the VWAP is the latest typical price
+	if state.Volume == 0 {
+		return state.LastPrice, nil
+	}
+
+	return state.PriceVolume / state.Volume, nil
+}
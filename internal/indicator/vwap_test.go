@@ -0,0 +1,123 @@
+package indicator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/cache"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type VWAPTestSuite struct {
+	suite.Suite
+}
+
+func TestVWAPSuite(t *testing.T) {
+	suite.Run(t, new(VWAPTestSuite))
+}
+
+func (suite *VWAPTestSuite) TestNewVWAP() {
+	vwap := NewVWAP()
+	suite.Equal(types.IndicatorTypeVWAP, vwap.Name())
+	suite.Equal(time.UTC, vwap.(*VWAP).location)
+}
+
+func (suite *VWAPTestSuite) TestConfig() {
+	vwap := NewVWAP()
+
+	suite.Require().NoError(vwap.Config("America/New_York"))
+	suite.Equal("America/New_York", vwap.(*VWAP).location.String())
+
+	suite.Error(vwap.Config())
+	suite.Error(vwap.Config(5))
+	suite.Error(vwap.Config("Not/A_Zone"))
+}
+
+func (suite *VWAPTestSuite) TestRawValueReference() {
+	start := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	bars := referenceBars("AAPL", start)
+	ctx := IndicatorContext{
+		DataSource:        newReferenceDataSource(bars, referenceBars("MSFT", start)),
+		IndicatorRegistry: nil,
+		Cache:             cache.NewCacheV1(),
+	}
+
+	vwap := NewVWAP()
+
+	// Session VWAP of the reference series
+	expected := map[int]float64{
+		0:  100.66666666666667,
+		3:  101.44848484848487,
+		7:  101.30314009661835,
+		11: 101.62918660287082,
+	}
+
+	for i, bar := range bars {
+		signal, err := vwap.GetSignal(bar, ctx)
+		suite.Require().NoError(err)
+		suite.Equal(types.SignalTypeNoAction, signal.Type)
+
+		if want, ok := expected[i]; ok {
+			rawValue, ok := signal.RawValue.(map[string]float64)
+			suite.Require().True(ok)
+			suite.InDelta(want, rawValue["vwap"], 1e-9, "bar %d", i)
+		}
+	}
+
+	// A fresh cache adds up the session from its start
+	ctx.Cache = cache.NewCacheV1()
+	value, err := vwap.RawValue(bars[7], ctx)
+	suite.Require().NoError(err)
+	suite.InDelta(101.30314009661835, value, 1e-9)
+
+	// Without a cache the value is the same
+	ctx.Cache = nil
+	value, err = vwap.RawValue(bars[11], ctx)
+	suite.Require().NoError(err)
+	suite.InDelta(101.62918660287082, value, 1e-9)
+}
+
+func (suite *VWAPTestSuite) TestSessionReset() {
+	start := time.Date(2024, 1, 2, 23, 58, 0, 0, time.UTC)
+	bars := referenceBars("AAPL", start)[:4]
+	ctx := IndicatorContext{
+		DataSource:        newReferenceDataSource(bars),
+		IndicatorRegistry: nil,
+		Cache:             cache.NewCacheV1(),
+	}
+
+	vwap := NewVWAP()
+
+	_, err := vwap.RawValue(bars[1], ctx)
+	suite.Require().NoError(err)
+
+	// Bars 2 and 3 fall on the next day and start a new session
+	value, err := vwap.RawValue(bars[3], ctx)
+	suite.Require().NoError(err)
+
+	expected := ((104.0+101.0+101.5)/3*1200 + (102.5+99.5+100.0)/3*1800) / 3000
+	suite.InDelta(expected, value, 1e-9)
+
+	// In New York the four bars share one session
+	suite.Require().NoError(vwap.Config("America/New_York"))
+
+	value, err = vwap.RawValue(bars[3], ctx)
+	suite.Require().NoError(err)
+	suite.InDelta(101.44848484848487, value, 1e-9)
+}
+
+func (suite *VWAPTestSuite) TestRawValueNoData() {
+	ctx := IndicatorContext{
+		DataSource:        NewMockDataSource(),
+		IndicatorRegistry: nil,
+		Cache:             cache.NewCacheV1(),
+	}
+
+	_, err := NewVWAP().RawValue(types.MarketData{Symbol: "AAPL", Time: time.Now()}, ctx)
+	suite.True(errors.IsInsufficientDataError(err))
+
+	_, err = NewVWAP().RawValue("AAPL", ctx)
+	suite.Error(err)
+}
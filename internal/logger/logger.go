@@ -3,15 +3,43 @@ package logger
 import (
 	"os"
 
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// Format selects how console log entries are encoded.
+type Format string
+
+const (
+	// FormatJSON writes one JSON object per entry.
+	FormatJSON Format = "json"
+	// FormatConsole writes human-readable, tab-separated entries.
+	FormatConsole Format = "console"
+)
+
+// Options configures the console output of a Logger.
+type Options struct {
+	// Level is the minimum level written to the console. Empty means info.
+	Level types.LogLevel
+	// Format is the console encoding. Empty means FormatJSON.
+	Format Format
+	// Output receives the console entries. Nil keeps the current output,
+	// which is stdout for a new logger.
+	Output zapcore.WriteSyncer
+}
+
 // Logger wraps the zap logger with additional functionality.
 type Logger struct {
 	*zap.Logger
 
 	file *os.File
+	// console and fileCore are the cores teed into Logger, kept so the
+	// console and the file output can be replaced independently.
+	console  zapcore.Core
+	fileCore zapcore.Core
+	output   zapcore.WriteSyncer
 }
 
 // NewLogger creates a new logger instance with production configuration.
@@ -34,11 +62,48 @@ func NewLogger() (*Logger, error) {
 	}
 
 	return &Logger{
-		Logger: zapLogger,
-		file:   nil,
+		Logger:   zapLogger,
+		file:     nil,
+		console:  zapLogger.Core(),
+		fileCore: nil,
+		output:   zapcore.Lock(os.Stdout),
 	}, nil
 }
 
+// Configure replaces the console output with one that drops entries below
+// opts.Level and encodes them in opts.Format. The file output enabled by
+// EnableFileOutput is kept as is.
+func (l *Logger) Configure(opts Options) error {
+	level, err := zapLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+
+	var encoder zapcore.Encoder
+
+	switch opts.Format {
+	case "", FormatJSON:
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case FormatConsole:
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unknown log format %q", opts.Format)
+	}
+
+	if opts.Output != nil {
+		l.output = opts.Output
+	}
+
+	l.console = zapcore.NewCore(encoder, l.output, level)
+	l.rebuild()
+
+	return nil
+}
+
 // EnableFileOutput tees the logger's output to the given file path in addition
 // to the existing destinations (typically stdout). Subsequent log calls write
 // to both. Safe to call once per logger; calling again replaces the file sink.
@@ -51,10 +116,8 @@ func (l *Logger) EnableFileOutput(filePath string) error {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	fileEncoder := zapcore.NewJSONEncoder(encoderConfig)
-	fileCore := zapcore.NewCore(fileEncoder, zapcore.AddSync(file), zapcore.InfoLevel)
-
-	teed := zapcore.NewTee(l.Logger.Core(), fileCore)
-	l.Logger = zap.New(teed)
+	l.fileCore = zapcore.NewCore(fileEncoder, zapcore.AddSync(file), zapcore.InfoLevel)
+	l.rebuild()
 
 	if l.file != nil {
 		_ = l.file.Close()
@@ -75,3 +138,30 @@ func (l *Logger) Sync() error {
 
 	return nil
 }
+
+// rebuild points the embedded zap logger at the console and file cores.
+func (l *Logger) rebuild() {
+	if l.fileCore == nil {
+		l.Logger = zap.New(l.console)
+
+		return
+	}
+
+	l.Logger = zap.New(zapcore.NewTee(l.console, l.fileCore))
+}
+
+// zapLevel converts a log level to the zap level. Empty means info.
+func zapLevel(level types.LogLevel) (zapcore.Level, error) {
+	switch level {
+	case types.LogLevelDebug:
+		return zapcore.DebugLevel, nil
+	case "", types.LogLevelInfo:
+		return zapcore.InfoLevel, nil
+	case types.LogLevelWarning:
+		return zapcore.WarnLevel, nil
+	case types.LogLevelError:
+		return zapcore.ErrorLevel, nil
+	default:
+		return zapcore.InfoLevel, errors.Newf(errors.ErrCodeInvalidParameter, "unknown log level %q", level)
+	}
+}
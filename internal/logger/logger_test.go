@@ -1,9 +1,14 @@
 package logger
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap/zapcore"
 )
 
 type LoggerTestSuite struct {
@@ -61,3 +66,92 @@ func (suite *LoggerTestSuite) TestLoggerWithFields() {
 	// Should not panic
 	logger.With().Info("test message with fields")
 }
+
+func (suite *LoggerTestSuite) TestConfigure() {
+	tests := []struct {
+		name      string
+		options   Options
+		expectOut []string
+		expectNot []string
+	}{
+		{
+			name:      "default level drops debug",
+			options:   Options{},
+			expectOut: []string{`"msg":"info entry"`, `"msg":"warn entry"`},
+			expectNot: []string{"debug entry"},
+		},
+		{
+			name:      "warning level drops info",
+			options:   Options{Level: types.LogLevelWarning},
+			expectOut: []string{`"msg":"warn entry"`},
+			expectNot: []string{"debug entry", "info entry"},
+		},
+		{
+			name:      "debug level keeps everything",
+			options:   Options{Level: types.LogLevelDebug},
+			expectOut: []string{"debug entry", "info entry", "warn entry"},
+		},
+		{
+			name:      "console format",
+			options:   Options{Level: types.LogLevelInfo, Format: FormatConsole},
+			expectOut: []string{"INFO\tinfo entry", "WARN\twarn entry"},
+			expectNot: []string{`"msg"`},
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			logger, err := NewLogger()
+			suite.Require().NoError(err)
+
+			var out bytes.Buffer
+			tc.options.Output = zapcore.AddSync(&out)
+			suite.Require().NoError(logger.Configure(tc.options))
+
+			logger.Debug("debug entry")
+			logger.Info("info entry")
+			logger.Warn("warn entry")
+
+			for _, expected := range tc.expectOut {
+				suite.Contains(out.String(), expected)
+			}
+			for _, unexpected := range tc.expectNot {
+				suite.NotContains(out.String(), unexpected)
+			}
+		})
+	}
+}
+
+func (suite *LoggerTestSuite) TestConfigure_KeepsOutputAndFile() {
+	logger, err := NewLogger()
+	suite.Require().NoError(err)
+
+	var out bytes.Buffer
+	suite.Require().NoError(logger.Configure(Options{Output: zapcore.AddSync(&out)}))
+
+	filePath := filepath.Join(suite.T().TempDir(), "running.log")
+	suite.Require().NoError(logger.EnableFileOutput(filePath))
+
+	// Reconfiguring without an output keeps writing to the buffer and the file
+	suite.Require().NoError(logger.Configure(Options{Level: types.LogLevelError}))
+
+	logger.Warn("warn entry")
+	logger.Error("error entry")
+	suite.Require().NoError(logger.Sync())
+
+	suite.NotContains(out.String(), "warn entry")
+	suite.Contains(out.String(), "error entry")
+
+	file, err := os.ReadFile(filePath)
+	suite.Require().NoError(err)
+	suite.Contains(string(file), "warn entry", "the file output keeps its own level")
+	suite.Contains(string(file), "error entry")
+}
+
+func (suite *LoggerTestSuite) TestConfigure_Invalid() {
+	logger, err := NewLogger()
+	suite.Require().NoError(err)
+
+	suite.Error(logger.Configure(Options{Level: "verbose"}))
+	suite.Error(logger.Configure(Options{Format: "xml"}))
+}
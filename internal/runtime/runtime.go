@@ -31,9 +31,20 @@ type StrategyRuntime interface {
 	GetIdentifier() (string, error)
 }
 
+// Cloner is implemented by strategy runtimes that can create independent
+// instances of the same strategy, such as one per symbol when the backtest
+// engine evaluates symbols concurrently.
+type Cloner interface {
+	// Clone returns a new runtime of the same strategy that shares no state with
+	// this one. The clone must be given an api and a config before use.
+	Clone() (StrategyRuntime, error)
+}
+
 type RuntimeContext struct {
 	// DataSource provides the market data as well as the historical data
 	DataSource datasource.DataSource
+	// MarketDataCache holds the most recent candles of each symbol the engine has processed. Nil when the engine keeps none
+	MarketDataCache *datasource.SlidingWindowCache
 	// IndicatorRegistry is the registry of all indicators
 	IndicatorRegistry indicator.IndicatorRegistry
 	// Cache is the cache of the strategy
@@ -48,4 +59,6 @@ type RuntimeContext struct {
 	LogStorage log.Log
 	// CurrentMarketData tracks the market data being processed (for implicit log context)
 	CurrentMarketData *types.MarketData
+	// Interval is the candle interval of the market data, such as "1m". Empty while unknown
+	Interval string
 }
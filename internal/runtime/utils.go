@@ -17,6 +17,20 @@ func StrategyIntervalToDataSourceInterval(interval strategy.Interval) optional.O
 		return optional.Some(datasource.Interval15m)
 	case strategy.Interval_INTERVAL_30M:
 		return optional.Some(datasource.Interval30m)
+	case strategy.Interval_INTERVAL_1H:
+		return optional.Some(datasource.Interval1h)
+	case strategy.Interval_INTERVAL_4H:
+		return optional.Some(datasource.Interval4h)
+	case strategy.Interval_INTERVAL_6H:
+		return optional.Some(datasource.Interval6h)
+	case strategy.Interval_INTERVAL_8H:
+		return optional.Some(datasource.Interval8h)
+	case strategy.Interval_INTERVAL_12H:
+		return optional.Some(datasource.Interval12h)
+	case strategy.Interval_INTERVAL_1D:
+		return optional.Some(datasource.Interval1d)
+	case strategy.Interval_INTERVAL_1W:
+		return optional.Some(datasource.Interval1w)
 	default:
 		return optional.None[datasource.Interval]()
 	}
@@ -89,6 +103,8 @@ func StrategyIndicatorTypeToIndicatorType(indicatorType strategy.IndicatorType)
 		return types.IndicatorTypeRSI
 	case strategy.IndicatorType_INDICATOR_MACD:
 		return types.IndicatorTypeMACD
+	case strategy.IndicatorType_INDICATOR_BOLLINGER_BANDS:
+		return types.IndicatorTypeBollingerBands
 	case strategy.IndicatorType_INDICATOR_WILLIAMS_R:
 		return types.IndicatorTypeWilliamsR
 	case strategy.IndicatorType_INDICATOR_ADX:
@@ -111,6 +127,8 @@ func StrategyIndicatorTypeToIndicatorType(indicatorType strategy.IndicatorType)
 		return types.IndicatorTypeMA
 	case strategy.IndicatorType_INDICATOR_PSY:
 		return types.IndicatorTypePSY
+	case strategy.IndicatorType_INDICATOR_VWAP:
+		return types.IndicatorTypeVWAP
 	default:
 		return types.IndicatorTypeRSI
 	}
@@ -145,6 +163,8 @@ func IndicatorTypeToStrategyIndicatorType(indicatorType types.IndicatorType) str
 		return strategy.IndicatorType_INDICATOR_RSI
 	case types.IndicatorTypeMACD:
 		return strategy.IndicatorType_INDICATOR_MACD
+	case types.IndicatorTypeBollingerBands:
+		return strategy.IndicatorType_INDICATOR_BOLLINGER_BANDS
 	case types.IndicatorTypeWilliamsR:
 		return strategy.IndicatorType_INDICATOR_WILLIAMS_R
 	case types.IndicatorTypeADX:
@@ -167,6 +187,8 @@ func IndicatorTypeToStrategyIndicatorType(indicatorType types.IndicatorType) str
 		return strategy.IndicatorType_INDICATOR_MA
 	case types.IndicatorTypePSY:
 		return strategy.IndicatorType_INDICATOR_PSY
+	case types.IndicatorTypeVWAP:
+		return strategy.IndicatorType_INDICATOR_VWAP
 	default:
 		return strategy.IndicatorType_INDICATOR_RSI
 	}
@@ -36,6 +36,21 @@ func TestStrategyIntervalToDataSourceInterval(t *testing.T) {
 			input:    strategy.Interval_INTERVAL_30M,
 			expected: optional.Some(datasource.Interval30m),
 		},
+		{
+			name:     "1 hour interval",
+			input:    strategy.Interval_INTERVAL_1H,
+			expected: optional.Some(datasource.Interval1h),
+		},
+		{
+			name:     "1 day interval",
+			input:    strategy.Interval_INTERVAL_1D,
+			expected: optional.Some(datasource.Interval1d),
+		},
+		{
+			name:     "monthly interval is not aggregated",
+			input:    strategy.Interval_INTERVAL_1MONTH,
+			expected: optional.None[datasource.Interval](),
+		},
 		{
 			name:     "unknown interval returns None",
 			input:    strategy.Interval(999),
@@ -369,6 +384,16 @@ func TestStrategyIndicatorTypeToIndicatorType(t *testing.T) {
 			input:    strategy.IndicatorType_INDICATOR_PSY,
 			expected: types.IndicatorTypePSY,
 		},
+		{
+			name:     "Bollinger Bands indicator",
+			input:    strategy.IndicatorType_INDICATOR_BOLLINGER_BANDS,
+			expected: types.IndicatorTypeBollingerBands,
+		},
+		{
+			name:     "VWAP indicator",
+			input:    strategy.IndicatorType_INDICATOR_VWAP,
+			expected: types.IndicatorTypeVWAP,
+		},
 		{
 			name:     "unknown defaults to RSI",
 			input:    strategy.IndicatorType(999),
@@ -516,6 +541,16 @@ func TestIndicatorTypeToStrategyIndicatorType(t *testing.T) {
 			input:    types.IndicatorTypePSY,
 			expected: strategy.IndicatorType_INDICATOR_PSY,
 		},
+		{
+			name:     "Bollinger Bands indicator",
+			input:    types.IndicatorTypeBollingerBands,
+			expected: strategy.IndicatorType_INDICATOR_BOLLINGER_BANDS,
+		},
+		{
+			name:     "VWAP indicator",
+			input:    types.IndicatorTypeVWAP,
+			expected: strategy.IndicatorType_INDICATOR_VWAP,
+		},
 		{
 			name:     "unknown defaults to RSI",
 			input:    types.IndicatorType("unknown"),
@@ -9,11 +9,13 @@ import (
 	"github.com/knqyf263/go-plugin/types/known/emptypb"
 	"github.com/knqyf263/go-plugin/types/known/timestamppb"
 	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
 	i "github.com/rxtech-lab/argo-trading/internal/indicator"
 	"github.com/rxtech-lab/argo-trading/internal/log"
 	"github.com/rxtech-lab/argo-trading/internal/runtime"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
 	"github.com/rxtech-lab/argo-trading/pkg/strategy"
 	"go.uber.org/zap"
 )
@@ -203,7 +205,7 @@ func (s StrategyApiForWasm) GetOrderStatus(ctx context.Context, req *strategy.Ge
 	var orderStatus strategy.OrderStatus
 
 	switch status {
-	case types.OrderStatusPending:
+	case types.OrderStatusPending, types.OrderStatusPartiallyFilled:
 		orderStatus = strategy.OrderStatus_ORDER_STATUS_PENDING
 	case types.OrderStatusFilled:
 		orderStatus = strategy.OrderStatus_ORDER_STATUS_FILLED
@@ -230,16 +232,18 @@ func (s StrategyApiForWasm) GetPosition(ctx context.Context, req *strategy.GetPo
 	}
 
 	return &strategy.Position{
-		Symbol:           position.Symbol,
-		Quantity:         position.TotalLongPositionQuantity,
-		TotalInQuantity:  position.TotalLongInPositionQuantity,
-		TotalOutQuantity: position.TotalLongOutPositionQuantity,
-		TotalInAmount:    position.TotalLongInPositionAmount,
-		TotalOutAmount:   position.TotalLongOutPositionAmount,
-		TotalInFee:       position.TotalLongInFee,
-		TotalOutFee:      position.TotalLongOutFee,
-		OpenTimestamp:    timestamppb.New(position.OpenTimestamp),
-		StrategyName:     position.StrategyName,
+		Symbol:            position.Symbol,
+		Quantity:          position.TotalLongPositionQuantity,
+		TotalInQuantity:   position.TotalLongInPositionQuantity,
+		TotalOutQuantity:  position.TotalLongOutPositionQuantity,
+		TotalInAmount:     position.TotalLongInPositionAmount,
+		TotalOutAmount:    position.TotalLongOutPositionAmount,
+		TotalInFee:        position.TotalLongInFee,
+		TotalOutFee:       position.TotalLongOutFee,
+		OpenTimestamp:     timestamppb.New(position.OpenTimestamp),
+		StrategyName:      position.StrategyName,
+		AverageEntryPrice: position.AverageLongEntryPrice,
+		TotalCost:         position.TotalLongCost,
 	}, nil
 }
 
@@ -256,16 +260,18 @@ func (s StrategyApiForWasm) GetPositions(ctx context.Context, _ *emptypb.Empty)
 
 	for i, position := range positions {
 		response.Positions[i] = &strategy.Position{
-			Symbol:           position.Symbol,
-			Quantity:         position.TotalLongPositionQuantity,
-			TotalInQuantity:  position.TotalLongInPositionQuantity,
-			TotalOutQuantity: position.TotalLongOutPositionQuantity,
-			TotalInAmount:    position.TotalLongInPositionAmount,
-			TotalOutAmount:   position.TotalLongOutPositionAmount,
-			TotalInFee:       position.TotalLongInFee,
-			TotalOutFee:      position.TotalLongOutFee,
-			OpenTimestamp:    timestamppb.New(position.OpenTimestamp),
-			StrategyName:     position.StrategyName,
+			Symbol:            position.Symbol,
+			Quantity:          position.TotalLongPositionQuantity,
+			TotalInQuantity:   position.TotalLongInPositionQuantity,
+			TotalOutQuantity:  position.TotalLongOutPositionQuantity,
+			TotalInAmount:     position.TotalLongInPositionAmount,
+			TotalOutAmount:    position.TotalLongOutPositionAmount,
+			TotalInFee:        position.TotalLongInFee,
+			TotalOutFee:       position.TotalLongOutFee,
+			OpenTimestamp:     timestamppb.New(position.OpenTimestamp),
+			StrategyName:      position.StrategyName,
+			AverageEntryPrice: position.AverageLongEntryPrice,
+			TotalCost:         position.TotalLongCost,
 		}
 	}
 
@@ -273,10 +279,21 @@ func (s StrategyApiForWasm) GetPositions(ctx context.Context, _ *emptypb.Empty)
 }
 
 // GetRange implements strategy.StrategyApi.
+// When the request names a symbol, only that symbol's data is read and aggregated.
 func (s StrategyApiForWasm) GetRange(ctx context.Context, req *strategy.GetRangeRequest) (*strategy.GetRangeResponse, error) {
 	intervalValue := runtime.StrategyIntervalToDataSourceInterval(req.Interval)
 
-	data, err := s.runtimeContext.DataSource.GetRange(req.StartTime.AsTime(), req.EndTime.AsTime(), intervalValue)
+	var (
+		data []types.MarketData
+		err  error
+	)
+
+	if req.Symbol != "" {
+		data, err = s.runtimeContext.DataSource.GetRangeForSymbol(req.Symbol, req.StartTime.AsTime(), req.EndTime.AsTime(), intervalValue)
+	} else {
+		data, err = s.runtimeContext.DataSource.GetRange(req.StartTime.AsTime(), req.EndTime.AsTime(), intervalValue)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -317,6 +334,8 @@ func (s StrategyApiForWasm) GetSignal(ctx context.Context, req *strategy.GetSign
 		Open:   req.MarketData.Open,
 		Close:  req.MarketData.Close,
 		Volume: req.MarketData.Volume,
+		Bid:    0,
+		Ask:    0,
 		Time:   req.MarketData.Time.AsTime(),
 	}
 
@@ -369,6 +388,8 @@ func (s StrategyApiForWasm) Mark(ctx context.Context, req *strategy.MarkRequest)
 		Low:    req.MarketData.Low,
 		Close:  req.MarketData.Close,
 		Volume: req.MarketData.Volume,
+		Bid:    0,
+		Ask:    0,
 	}
 
 	// Convert protobuf SignalType to internal SignalType
@@ -393,6 +414,7 @@ func (s StrategyApiForWasm) Mark(ctx context.Context, req *strategy.MarkRequest)
 		Title:        req.Mark.Title,
 		Message:      req.Mark.Message,
 		Category:     req.Mark.Category,
+		StrategyName: "",
 		Signal:       optional.Some(signal),
 	}
 
@@ -422,8 +444,14 @@ func (s StrategyApiForWasm) PlaceMultipleOrders(ctx context.Context, req *strate
 				Reason:  order.Reason.Reason,
 				Message: order.Reason.Message,
 			},
-			TakeProfit: optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
-			StopLoss:   optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+			StopPrice:      0,
+			TrailOffset:    0,
+			TrailPercent:   false,
+			FilledQuantity: 0,
+			TimeInForce:    "",
+			GoodTillDate:   time.Time{},
+			TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+			StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
 		}
 
 		if order.TakeProfit != nil {
@@ -475,8 +503,14 @@ func (s StrategyApiForWasm) PlaceOrder(ctx context.Context, req *strategy.Execut
 			Reason:  reasonName,
 			Message: reasonMessage,
 		},
-		TakeProfit: optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
-		StopLoss:   optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopPrice:      0,
+		TrailOffset:    0,
+		TrailPercent:   false,
+		FilledQuantity: 0,
+		TimeInForce:    "",
+		GoodTillDate:   time.Time{},
+		TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
 	}
 
 	if req.TakeProfit != nil {
@@ -624,6 +658,8 @@ func (s StrategyApiForWasm) GetTrades(ctx context.Context, req *strategy.GetTrad
 			Fee:           trade.Fee,
 			Pnl:           trade.PnL,
 			CumulativePnl: trade.CumulativePnL,
+			RealizedPnl:   trade.RealizedPnL,
+			CostBasis:     trade.CostBasis,
 			PositionType:  runtime.PositionTypeToStrategyPositionType(trade.Order.PositionType),
 			StrategyName:  trade.Order.StrategyName,
 			Reason: &strategy.Reason{
@@ -675,6 +711,161 @@ func (s StrategyApiForWasm) Log(ctx context.Context, req *strategy.LogRequest) (
 	return &emptypb.Empty{}, nil
 }
 
+// GetCurrentTime implements strategy.StrategyApi.
+func (s StrategyApiForWasm) GetCurrentTime(ctx context.Context, _ *emptypb.Empty) (*strategy.GetCurrentTimeResponse, error) {
+	if s.runtimeContext.CurrentMarketData == nil {
+		return nil, errors.New(errors.ErrCodeDataNotFound, "no market data is being processed")
+	}
+
+	return &strategy.GetCurrentTimeResponse{
+		Time: timestamppb.New(s.runtimeContext.CurrentMarketData.Time),
+	}, nil
+}
+
+// GetInterval implements strategy.StrategyApi.
+func (s StrategyApiForWasm) GetInterval(ctx context.Context, _ *emptypb.Empty) (*strategy.GetIntervalResponse, error) {
+	return &strategy.GetIntervalResponse{
+		Interval: s.runtimeContext.Interval,
+	}, nil
+}
+
+// GetRecentCandles implements strategy.StrategyApi.
+func (s StrategyApiForWasm) GetRecentCandles(ctx context.Context, req *strategy.GetRecentCandlesRequest) (*strategy.GetRecentCandlesResponse, error) {
+	cache := s.runtimeContext.MarketDataCache
+	if cache == nil {
+		return nil, errors.New(errors.ErrCodeDataSourceUnavailable, "no market data cache is available")
+	}
+
+	count := int(req.N)
+	if count <= 0 {
+		return nil, errors.Newf(errors.ErrCodeInvalidParameter, "number of candles must be greater than zero: %d", count)
+	}
+
+	if count > cache.MaxSize() {
+		return nil, errors.Newf(errors.ErrCodeInvalidParameter,
+			"requested %d candles but the market data cache holds at most %d per symbol", count, cache.MaxSize())
+	}
+
+	// End at the bar being processed so candles that concurrent symbol
+	// workers cached ahead of it never leak into the window
+	var end time.Time
+
+	if s.runtimeContext.CurrentMarketData != nil {
+		end = s.runtimeContext.CurrentMarketData.Time
+	} else if last, ok := cache.GetLastData(req.Symbol); ok {
+		end = last.Time
+	}
+
+	data, ok := cache.GetPreviousDataPoints(end, req.Symbol, count)
+	if !ok {
+		available := min(cache.Size(req.Symbol), count-1)
+
+		return nil, errors.NewInsufficientDataErrorf(count, available, req.Symbol,
+			"insufficient candles cached for symbol %s: requested %d, got %d", req.Symbol, count, available)
+	}
+
+	response := &strategy.GetRecentCandlesResponse{
+		Data: make([]*strategy.MarketData, len(data)),
+	}
+
+	for i, d := range data {
+		response.Data[i] = &strategy.MarketData{
+			Symbol: d.Symbol,
+			High:   d.High,
+			Low:    d.Low,
+			Open:   d.Open,
+			Close:  d.Close,
+			Volume: d.Volume,
+			Time:   timestamppb.New(d.Time),
+		}
+	}
+
+	return response, nil
+}
+
+// GetCandlesForInterval implements strategy.StrategyApi.
+// The candles are aggregated from the data source's bars with the same
+// bucketing as GetRange. A candle is only returned once the bar being
+// processed completes it, so the bucket still forming never leaks ahead.
+func (s StrategyApiForWasm) GetCandlesForInterval(ctx context.Context, req *strategy.GetCandlesForIntervalRequest) (*strategy.GetCandlesForIntervalResponse, error) {
+	if s.runtimeContext.DataSource == nil {
+		return nil, errors.New(errors.ErrCodeDataSourceUnavailable, "no data source is available")
+	}
+
+	count := int(req.N)
+	if count <= 0 {
+		return nil, errors.Newf(errors.ErrCodeInvalidParameter, "number of candles must be greater than zero: %d", count)
+	}
+
+	interval := datasource.Interval(req.Interval)
+
+	candleLength, err := provider.IntervalDuration(req.Interval)
+	if err != nil {
+		return nil, errors.Wrapf(errors.ErrCodeInvalidParameter, err, "unsupported candle interval %q", req.Interval)
+	}
+
+	var end time.Time
+
+	if s.runtimeContext.CurrentMarketData != nil {
+		end = s.runtimeContext.CurrentMarketData.Time
+	} else {
+		last, err := s.runtimeContext.DataSource.ReadLastData(req.Symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		end = last.Time
+	}
+
+	// The bar being processed covers its own interval, so with 1m bars the one
+	// at 10:59 completes the 10:00 hourly candle. An unknown bar length only
+	// counts a candle as complete once a bar after it has arrived.
+	barLength, err := provider.IntervalDuration(s.runtimeContext.Interval)
+	if err != nil {
+		barLength = 0
+	}
+
+	formingStart := end.Truncate(candleLength)
+	start := formingStart.Add(-time.Duration(count) * candleLength)
+
+	data, err := s.runtimeContext.DataSource.GetRangeForSymbol(req.Symbol, start, end, optional.Some(interval))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		last := data[len(data)-1]
+		if last.Time.Add(candleLength).After(end.Add(barLength)) {
+			data = data[:len(data)-1]
+		}
+	}
+
+	if len(data) < count {
+		return nil, errors.NewInsufficientDataErrorf(count, len(data), req.Symbol,
+			"insufficient %s candles for symbol %s: requested %d, got %d", req.Interval, req.Symbol, count, len(data))
+	}
+
+	data = data[len(data)-count:]
+
+	response := &strategy.GetCandlesForIntervalResponse{
+		Data: make([]*strategy.MarketData, len(data)),
+	}
+
+	for i, d := range data {
+		response.Data[i] = &strategy.MarketData{
+			Symbol: d.Symbol,
+			High:   d.High,
+			Low:    d.Low,
+			Open:   d.Open,
+			Close:  d.Close,
+			Volume: d.Volume,
+			Time:   timestamppb.New(d.Time),
+		}
+	}
+
+	return response, nil
+}
+
 // convertStrategyLogLevel converts strategy.LogLevel to types.LogLevel.
 func convertStrategyLogLevel(level strategy.LogLevel) types.LogLevel {
 	switch level {
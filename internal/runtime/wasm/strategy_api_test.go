@@ -2,18 +2,23 @@ package wasm
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/knqyf263/go-plugin/types/known/emptypb"
 	"github.com/knqyf263/go-plugin/types/known/timestamppb"
+	_ "github.com/marcboeker/go-duckdb"
 	"github.com/moznion/go-optional"
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
 	"github.com/rxtech-lab/argo-trading/internal/log"
+	"github.com/rxtech-lab/argo-trading/internal/logger"
 	"github.com/rxtech-lab/argo-trading/internal/runtime"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/mocks"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
 	"github.com/rxtech-lab/argo-trading/pkg/strategy"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -399,6 +404,39 @@ func (suite *StrategyApiTestSuite) TestGetRange() {
 	suite.Equal(50000.0, response.Data[0].High)
 }
 
+func (suite *StrategyApiTestSuite) TestGetRangeForSymbol() {
+	startTime := time.Now().UTC()
+	endTime := startTime.Add(time.Hour)
+	data := []types.MarketData{
+		{
+			Symbol: "ETHUSDT",
+			High:   3000.0,
+			Low:    2900.0,
+			Open:   2950.0,
+			Close:  2980.0,
+			Volume: 10.0,
+			Time:   startTime,
+		},
+	}
+
+	suite.mockDataSource.EXPECT().GetRangeForSymbol(
+		"ETHUSDT",
+		startTime,
+		endTime,
+		optional.Some(datasource.Interval5m),
+	).Return(data, nil)
+
+	response, err := suite.api.GetRange(context.Background(), &strategy.GetRangeRequest{
+		Symbol:    "ETHUSDT",
+		StartTime: timestamppb.New(startTime),
+		EndTime:   timestamppb.New(endTime),
+		Interval:  strategy.Interval_INTERVAL_5M,
+	})
+	suite.NoError(err)
+	suite.Len(response.Data, 1)
+	suite.Equal("ETHUSDT", response.Data[0].Symbol)
+}
+
 func (suite *StrategyApiTestSuite) TestGetSignal() {
 	api := NewWasmStrategyApi(suite.runtimeContext)
 
@@ -852,6 +890,8 @@ func (suite *StrategyApiTestSuite) TestGetTrades() {
 			Fee:           5.2,
 			PnL:           1990.0,
 			CumulativePnL: 1990.0,
+			RealizedPnL:   1990.0,
+			CostBasis:     50005.0,
 		},
 	}
 
@@ -892,6 +932,8 @@ func (suite *StrategyApiTestSuite) TestGetTrades() {
 	suite.Equal("trade-order-2", response.Trades[1].OrderId)
 	suite.Equal(1990.0, response.Trades[1].Pnl)
 	suite.Equal(1990.0, response.Trades[1].CumulativePnl)
+	suite.Equal(1990.0, response.Trades[1].RealizedPnl)
+	suite.Equal(50005.0, response.Trades[1].CostBasis)
 }
 
 // TestGetTradesWithTimeFilter tests the GetTrades method with time filters
@@ -1202,3 +1244,199 @@ func TestBuildZapFields(t *testing.T) {
 		})
 	}
 }
+
+// TestGetCurrentTime tests that GetCurrentTime returns the time of the bar being processed
+func (suite *StrategyApiTestSuite) TestGetCurrentTime() {
+	barTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	suite.runtimeContext.CurrentMarketData = &types.MarketData{Symbol: "AAPL", Time: barTime}
+
+	resp, err := suite.api.GetCurrentTime(context.Background(), &emptypb.Empty{})
+	suite.Require().NoError(err)
+	suite.Equal(barTime, resp.Time.AsTime())
+}
+
+// TestGetCurrentTimeWithoutMarketData tests GetCurrentTime before the first bar
+func (suite *StrategyApiTestSuite) TestGetCurrentTimeWithoutMarketData() {
+	_, err := suite.api.GetCurrentTime(context.Background(), &emptypb.Empty{})
+	suite.Error(err)
+}
+
+// TestGetInterval tests that GetInterval returns the interval of the runtime context
+func (suite *StrategyApiTestSuite) TestGetInterval() {
+	resp, err := suite.api.GetInterval(context.Background(), &emptypb.Empty{})
+	suite.Require().NoError(err)
+	suite.Empty(resp.Interval)
+
+	suite.runtimeContext.Interval = "15m"
+
+	resp, err = suite.api.GetInterval(context.Background(), &emptypb.Empty{})
+	suite.Require().NoError(err)
+	suite.Equal("15m", resp.Interval)
+}
+
+// TestGetRecentCandles tests that GetRecentCandles returns the last candles up to the current bar in chronological order
+func (suite *StrategyApiTestSuite) TestGetRecentCandles() {
+	cache := datasource.NewSlidingWindowCache(10)
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 6; i++ {
+		cache.Add(types.MarketData{Symbol: "AAPL", Time: start.Add(time.Duration(i) * time.Minute), Close: float64(100 + i)})
+	}
+
+	cache.Add(types.MarketData{Symbol: "MSFT", Time: start, Close: 300})
+
+	suite.runtimeContext.MarketDataCache = cache
+	suite.runtimeContext.CurrentMarketData = &types.MarketData{Symbol: "AAPL", Time: start.Add(4 * time.Minute)}
+
+	resp, err := suite.api.GetRecentCandles(context.Background(), &strategy.GetRecentCandlesRequest{Symbol: "AAPL", N: 3})
+	suite.Require().NoError(err)
+	suite.Require().Len(resp.Data, 3)
+
+	for i, candle := range resp.Data {
+		suite.Equal("AAPL", candle.Symbol)
+		suite.Equal(start.Add(time.Duration(i+2)*time.Minute), candle.Time.AsTime())
+		suite.Equal(float64(102+i), candle.Close)
+	}
+}
+
+// TestGetRecentCandlesErrors tests the errors of GetRecentCandles
+func (suite *StrategyApiTestSuite) TestGetRecentCandlesErrors() {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := suite.api.GetRecentCandles(context.Background(), &strategy.GetRecentCandlesRequest{Symbol: "AAPL", N: 1})
+	suite.Error(err, "no cache")
+
+	cache := datasource.NewSlidingWindowCache(5)
+	cache.Add(types.MarketData{Symbol: "AAPL", Time: start})
+	cache.Add(types.MarketData{Symbol: "AAPL", Time: start.Add(time.Minute)})
+	suite.runtimeContext.MarketDataCache = cache
+
+	_, err = suite.api.GetRecentCandles(context.Background(), &strategy.GetRecentCandlesRequest{Symbol: "AAPL", N: 0})
+	suite.Error(err, "non-positive count")
+
+	_, err = suite.api.GetRecentCandles(context.Background(), &strategy.GetRecentCandlesRequest{Symbol: "AAPL", N: 6})
+	suite.Error(err, "count above the cache size")
+
+	_, err = suite.api.GetRecentCandles(context.Background(), &strategy.GetRecentCandlesRequest{Symbol: "AAPL", N: 3})
+	suite.True(errors.IsInsufficientDataError(err), "fewer candles cached than requested")
+
+	_, err = suite.api.GetRecentCandles(context.Background(), &strategy.GetRecentCandlesRequest{Symbol: "MSFT", N: 1})
+	suite.True(errors.IsInsufficientDataError(err), "no candles cached for the symbol")
+
+	resp, err := suite.api.GetRecentCandles(context.Background(), &strategy.GetRecentCandlesRequest{Symbol: "AAPL", N: 2})
+	suite.Require().NoError(err)
+	suite.Len(resp.Data, 2)
+}
+
+// useMinuteBarsDataSource replaces the mocked data source with a DuckDB data
+// source over count one-minute AAPL bars starting at start. Bar i opens at
+// 100+i, closes at 100.5+i, spans 1 above and below and trades 10+i.
+func (suite *StrategyApiTestSuite) useMinuteBarsDataSource(start time.Time, count int) {
+	parquetPath := filepath.Join(suite.T().TempDir(), "minute_bars.parquet")
+
+	db, err := sql.Open("duckdb", ":memory:")
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE market_data (time TIMESTAMP, symbol TEXT, open DOUBLE, high DOUBLE, low DOUBLE, close DOUBLE, volume DOUBLE)`)
+	suite.Require().NoError(err)
+
+	for i := 0; i < count; i++ {
+		price := 100 + float64(i)
+		_, err = db.Exec(`INSERT INTO market_data VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			start.Add(time.Duration(i)*time.Minute), "AAPL", price, price+1, price-1, price+0.5, 10+float64(i))
+		suite.Require().NoError(err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`COPY market_data TO '%s' (FORMAT PARQUET)`, parquetPath))
+	suite.Require().NoError(err)
+
+	l, err := logger.NewLogger()
+	suite.Require().NoError(err)
+
+	ds, err := datasource.NewDataSource(":memory:", l)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ds.Initialize(parquetPath))
+	suite.T().Cleanup(func() { ds.Close() })
+
+	suite.runtimeContext.DataSource = ds
+	suite.runtimeContext.Interval = "1m"
+}
+
+// TestGetCandlesForInterval tests that 120 one-minute bars are aggregated into
+// hourly candles that are only returned once complete
+func (suite *StrategyApiTestSuite) TestGetCandlesForInterval() {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	suite.useMinuteBarsDataSource(start, 120)
+
+	request := &strategy.GetCandlesForIntervalRequest{Symbol: "AAPL", Interval: "1h", N: 1}
+
+	for minute := 0; minute < 120; minute++ {
+		suite.runtimeContext.CurrentMarketData = &types.MarketData{Symbol: "AAPL", Time: start.Add(time.Duration(minute) * time.Minute)}
+
+		resp, err := suite.api.GetCandlesForInterval(context.Background(), request)
+
+		switch {
+		case minute < 59:
+			suite.True(errors.IsInsufficientDataError(err), "the first hour is still forming at minute %d", minute)
+		case minute < 119:
+			suite.Require().NoError(err, "minute %d", minute)
+			suite.Require().Len(resp.Data, 1)
+			suite.Equal(start, resp.Data[0].Time.AsTime(), "the second hour is still forming at minute %d", minute)
+		default:
+			suite.Require().NoError(err)
+			suite.Require().Len(resp.Data, 1)
+			suite.Equal(start.Add(time.Hour), resp.Data[0].Time.AsTime())
+		}
+	}
+
+	request.N = 2
+
+	resp, err := suite.api.GetCandlesForInterval(context.Background(), request)
+	suite.Require().NoError(err)
+	suite.Require().Len(resp.Data, 2)
+
+	for hour, candle := range resp.Data {
+		first := 100 + float64(hour*60)
+		last := first + 59
+
+		volume := 0.0
+		for i := hour * 60; i < hour*60+60; i++ {
+			volume += 10 + float64(i)
+		}
+
+		suite.Equal("AAPL", candle.Symbol)
+		suite.Equal(start.Add(time.Duration(hour)*time.Hour), candle.Time.AsTime())
+		suite.Equal(first, candle.Open)
+		suite.Equal(last+1, candle.High)
+		suite.Equal(first-1, candle.Low)
+		suite.Equal(last+0.5, candle.Close)
+		suite.Equal(volume, candle.Volume)
+	}
+}
+
+// TestGetCandlesForIntervalErrors tests the errors of GetCandlesForInterval
+func (suite *StrategyApiTestSuite) TestGetCandlesForIntervalErrors() {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	suite.useMinuteBarsDataSource(start, 120)
+	suite.runtimeContext.CurrentMarketData = &types.MarketData{Symbol: "AAPL", Time: start.Add(90 * time.Minute)}
+
+	_, err := suite.api.GetCandlesForInterval(context.Background(), &strategy.GetCandlesForIntervalRequest{Symbol: "AAPL", Interval: "1h", N: 0})
+	suite.Error(err, "non-positive count")
+
+	_, err = suite.api.GetCandlesForInterval(context.Background(), &strategy.GetCandlesForIntervalRequest{Symbol: "AAPL", Interval: "2M", N: 1})
+	suite.Error(err, "unsupported interval")
+
+	_, err = suite.api.GetCandlesForInterval(context.Background(), &strategy.GetCandlesForIntervalRequest{Symbol: "AAPL", Interval: "1h", N: 2})
+	suite.True(errors.IsInsufficientDataError(err), "only one hour is complete")
+
+	_, err = suite.api.GetCandlesForInterval(context.Background(), &strategy.GetCandlesForIntervalRequest{Symbol: "MSFT", Interval: "1h", N: 1})
+	suite.True(errors.IsInsufficientDataError(err), "no bars of the symbol")
+
+	suite.runtimeContext.CurrentMarketData = nil
+
+	resp, err := suite.api.GetCandlesForInterval(context.Background(), &strategy.GetCandlesForIntervalRequest{Symbol: "AAPL", Interval: "15m", N: 8})
+	suite.Require().NoError(err, "without a bar being processed the last bar of the data source ends the range")
+	suite.Len(resp.Data, 8)
+	suite.Equal(start.Add(105*time.Minute), resp.Data[7].Time.AsTime())
+}
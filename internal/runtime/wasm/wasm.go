@@ -41,6 +41,16 @@ func NewStrategyWasmRuntimeFromBytes(wasmBytes []byte) (runtime.StrategyRuntime,
 	}, nil
 }
 
+// Clone implements runtime.Cloner. The clone loads its own instance of the
+// wasm module when InitializeApi is called.
+func (s *StrategyWasmRuntime) Clone() (runtime.StrategyRuntime, error) {
+	return &StrategyWasmRuntime{
+		strategy:     nil,
+		wasmFilePath: s.wasmFilePath,
+		wasmBytes:    s.wasmBytes,
+	}, nil
+}
+
 // GetDescription implements runtime.StrategyRuntime.
 func (s *StrategyWasmRuntime) GetDescription() (string, error) {
 	if s.strategy == nil {
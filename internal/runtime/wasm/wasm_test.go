@@ -118,3 +118,27 @@ func (suite *StrategyTestSuite) TestBothNotSet() {
 	}))
 	suite.Require().Error(err)
 }
+
+// TestClone tests that a clone loads its own instance of the strategy
+func (suite *StrategyTestSuite) TestClone() {
+	testRuntime, err := NewStrategyWasmRuntime("../../../examples/strategy/plugin.wasm")
+	suite.Require().NoError(err)
+
+	cloner, ok := testRuntime.(runtime.Cloner)
+	suite.Require().True(ok)
+
+	clone, err := cloner.Clone()
+	suite.Require().NoError(err)
+	suite.NotSame(testRuntime, clone)
+
+	// The clone is not loaded until it gets an api of its own
+	suite.Empty(clone.Name())
+
+	err = clone.InitializeApi(NewWasmStrategyApi(&runtime.RuntimeContext{
+		Cache:             suite.mockCache,
+		TradingSystem:     suite.mockTradingSystem,
+		IndicatorRegistry: suite.mockIndicatorRegistry,
+	}))
+	suite.Require().NoError(err)
+	suite.NotEmpty(clone.Name())
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"time"
 
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
+	"github.com/rxtech-lab/argo-trading/internal/logger"
 	"github.com/rxtech-lab/argo-trading/internal/runtime"
 	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
 	"github.com/rxtech-lab/argo-trading/internal/trading/wallet"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
 	"github.com/rxtech-lab/argo-trading/pkg/strategy"
 )
 
@@ -30,8 +33,15 @@ type OnMarketDataCallback func(runID string, data types.MarketData) error
 // OnOrderPlacedCallback is called when an order is placed by the strategy.
 type OnOrderPlacedCallback func(order types.ExecuteOrder) error
 
-// OnOrderFilledCallback is called when an order is filled.
-type OnOrderFilledCallback func(order types.Order) error
+// OnOrderFilledCallback is called once when an order placed by the strategy
+// has filled, with the trade that filled it. A returned error is logged and
+// does not stop the engine.
+type OnOrderFilledCallback func(trade types.Trade) error
+
+// OnOrderRejectedCallback is called when the trading provider rejects an order
+// placed by the strategy, such as one below the exchange's minimum notional.
+// reason is the provider's explanation of the rejection.
+type OnOrderRejectedCallback func(order types.ExecuteOrder, reason string) error
 
 // OnErrorCallback is called when a non-fatal error occurs.
 type OnErrorCallback func(err error)
@@ -114,9 +124,13 @@ type LiveTradingCallbacks struct {
 	// OnOrderPlaced is called when an order is placed by the strategy.
 	OnOrderPlaced *OnOrderPlacedCallback
 
-	// OnOrderFilled is called when an order is filled.
+	// OnOrderFilled is called when an order placed by the strategy fills.
 	OnOrderFilled *OnOrderFilledCallback
 
+	// OnOrderRejected is called when the trading provider rejects an order
+	// placed by the strategy. The rejection is not fatal to the run.
+	OnOrderRejected *OnOrderRejectedCallback
+
 	// OnError is called when a non-fatal error occurs.
 	OnError *OnErrorCallback
 
@@ -156,6 +170,17 @@ type LiveTradingCallbacks struct {
 	OnAssetsChanged *OnAssetsChangedCallback
 }
 
+// PauseMode selects what the engine does with the strategy while it is paused.
+type PauseMode string
+
+const (
+	// PauseModeSuppressOrders keeps calling the strategy on every bar but drops
+	// (and logs) the orders it places.
+	PauseModeSuppressOrders PauseMode = "suppress_orders"
+	// PauseModeSkipStrategy does not call the strategy at all.
+	PauseModeSkipStrategy PauseMode = "skip_strategy"
+)
+
 // PrefetchConfig holds configuration for historical data prefetching.
 type PrefetchConfig struct {
 	// Enabled enables historical data prefetching before live trading starts
@@ -183,6 +208,127 @@ type LiveTradingEngineConfig struct {
 
 	// Prefetch configures historical data prefetching for indicator accuracy
 	Prefetch PrefetchConfig `json:"prefetch" yaml:"prefetch" jsonschema:"description=Historical data prefetch configuration"`
+
+	// SuppressOrdersDuringWarmup drops (and logs) any order the strategy places
+	// before the engine reaches EngineStatusRunning, i.e. during strategy
+	// initialization, prefetch and gap fill.
+	SuppressOrdersDuringWarmup bool `json:"suppress_orders_during_warmup" yaml:"suppress_orders_during_warmup" jsonschema:"description=Suppress and log strategy orders placed before the engine is running (prefetch and gap fill),default=false"`
+
+	// PauseMode selects how the engine pauses the strategy between Pause and
+	// Resume. Market data keeps flowing into the data cache either way.
+	// Defaults to PauseModeSuppressOrders.
+	PauseMode PauseMode `json:"pause_mode" yaml:"pause_mode" jsonschema:"description=While paused keep calling the strategy but drop its orders (suppress_orders) or skip the strategy (skip_strategy),enum=suppress_orders,enum=skip_strategy,default=suppress_orders"`
+
+	// WarmupBars is the number of bars of each symbol that only fill the data
+	// cache before the strategy is called. Unless prefetch is enabled they are
+	// backfilled from the market data provider's history, or from the data source
+	// set with SetWarmupDataSource, and any that cannot be loaded are taken from
+	// the stream. The engine reports EngineStatusWarmingUp until every symbol has
+	// warmed up.
+	WarmupBars int `json:"warmup_bars" yaml:"warmup_bars" jsonschema:"description=Number of bars per symbol added to the data cache before the strategy processes any bar,minimum=0,default=0"`
+
+	// MaxDrawdownPct halts trading once the account equity falls this fraction
+	// below its peak during the run, e.g. 0.2 for a 20% drawdown. Open orders are
+	// cancelled, further orders are rejected and EngineStatusHalted is reported.
+	// Zero disables the circuit breaker.
+	MaxDrawdownPct float64 `json:"max_drawdown_pct" yaml:"max_drawdown_pct" jsonschema:"description=Halt trading when equity falls this fraction below its peak (0 disables),minimum=0,maximum=1,default=0"`
+
+	// StopOnMaxDrawdown ends Run with an error when the drawdown circuit breaker
+	// trips. Otherwise the engine keeps streaming market data but no longer calls
+	// the strategy.
+	StopOnMaxDrawdown bool `json:"stop_on_max_drawdown" yaml:"stop_on_max_drawdown" jsonschema:"description=Stop the run instead of only suspending the strategy when the max drawdown is reached,default=false"`
+
+	// MaxOrdersPerDay caps the orders the strategy places per UTC day of the
+	// bar time. Orders over the limit are rejected with an ErrCodeDailyLimitReached
+	// error before reaching the provider. Zero disables the limit.
+	MaxOrdersPerDay int `json:"max_orders_per_day" yaml:"max_orders_per_day" jsonschema:"description=Largest number of orders the strategy may place per UTC day (0 disables),minimum=0,default=0"`
+
+	// MaxNotionalPerDay caps the notional, quantity times order price, of the
+	// orders the strategy places per UTC day. Orders without a price use the
+	// latest close of their symbol. Zero disables the limit.
+	MaxNotionalPerDay float64 `json:"max_notional_per_day" yaml:"max_notional_per_day" jsonschema:"description=Largest total notional of the orders the strategy may place per UTC day (0 disables),minimum=0,default=0"`
+
+	// OrderConcurrency is the number of orders of one PlaceMultipleOrders call
+	// submitted to the trading provider at the same time. Every order still
+	// waits for the provider's rate limiter. A failed order does not stop the
+	// others, and the failures are returned together. 0 or 1 places the orders
+	// one after another and stops at the first failure.
+	OrderConcurrency int `json:"order_concurrency" yaml:"order_concurrency" jsonschema:"description=Number of orders of a PlaceMultipleOrders call submitted in parallel (0 or 1 submits them one after another),minimum=0,default=0"`
+
+	// MarketDataFailover configures when the engine switches from the primary
+	// market data provider to the backup set via SetBackupMarketDataProvider.
+	MarketDataFailover provider.FailoverConfig `json:"market_data_failover" yaml:"market_data_failover" jsonschema:"description=Failover from the primary to the backup market data provider"`
+
+	// LiquidateOnStop flattens the traded symbols when Run returns: open orders
+	// are cancelled and the position in each symbol is closed with a market
+	// order, each reported through OnOrderPlaced. Other balances of the account
+	// are kept.
+	LiquidateOnStop bool `json:"liquidate_on_stop" yaml:"liquidate_on_stop" jsonschema:"description=Cancel open orders and close the positions in the traded symbols with market orders when the engine stops,default=false"`
+
+	// InitialBalance seeds a simulated paper account with this much cash in
+	// BaseCurrency. The strategy then trades against the paper account: orders
+	// fill at the latest cached market price and never reach the exchange, whose
+	// account is not read. Zero trades the exchange account.
+	InitialBalance float64 `json:"initial_balance" yaml:"initial_balance" jsonschema:"description=Cash of a simulated paper account; orders then fill locally instead of on the exchange (0 trades the exchange account),minimum=0,default=0"`
+
+	// BaseCurrency is the currency the paper account holds its cash in and
+	// reports balances in, e.g. USDT, and the currency ConvertBalances values
+	// the account in. Defaults to USDT.
+	BaseCurrency string `json:"base_currency" yaml:"base_currency" jsonschema:"description=Currency of the paper account cash and balances and of the converted account equity,default=USDT"`
+
+	// ConvertBalances reports the account equity as the value of every asset
+	// balance in BaseCurrency, for accounts holding several quote currencies
+	// such as USDT, BTC and EUR. Each asset is converted at the latest cached
+	// close of its pair with BaseCurrency, or at its ConversionRates entry when
+	// no such pair is streamed. Assets without a rate are left out with a
+	// warning. Only applies to trading providers that report balances per asset.
+	ConvertBalances bool `json:"convert_balances" yaml:"convert_balances" jsonschema:"description=Report the account equity as the value of all asset balances in the base currency,default=false"`
+
+	// ConversionRates is the value of one unit of an asset in BaseCurrency,
+	// keyed by asset, e.g. {"EUR": 1.08}. ConvertBalances uses it for assets
+	// whose pair with BaseCurrency has no cached bar.
+	ConversionRates map[string]float64 `json:"conversion_rates" yaml:"conversion_rates" jsonschema:"description=Value of one unit of each asset in the base currency used when no market price is cached"`
+
+	// PaperFeeRate is the fee the paper account charges on each fill as a
+	// fraction of its notional, e.g. 0.001 for 0.1%.
+	PaperFeeRate float64 `json:"paper_fee_rate" yaml:"paper_fee_rate" jsonschema:"description=Fee charged by the paper account as a fraction of the fill notional,minimum=0,maximum=1,default=0"`
+
+	// ReplaySpeed paces market data providers that replay recorded data, such
+	// as a CSV file: the engine waits the time between two bars divided by the
+	// speed before passing on the later bar, so 1 replays in real time and 10
+	// ten times faster. Zero replays as fast as possible. Live providers are
+	// never paced.
+	ReplaySpeed float64 `json:"replay_speed" yaml:"replay_speed" jsonschema:"description=Replay speed of recorded market data relative to real time (0 replays as fast as possible),minimum=0,default=0"`
+
+	// StaleDataIntervals reports the market data feed as ProviderStatusStale
+	// through OnProviderStatusChange once no bar has arrived for this many bar
+	// intervals of the market data provider. The next bar reports it connected
+	// again. Zero disables the check.
+	StaleDataIntervals int `json:"stale_data_intervals" yaml:"stale_data_intervals" jsonschema:"description=Report the market data feed stale after this many bar intervals without data (0 disables),minimum=0,default=0"`
+
+	// HaltOnStaleData cancels the strategy's open orders when the market data
+	// feed goes stale, so none of them fill at prices the strategy has not seen,
+	// and reports an ErrCodeTradingHalted error through OnError.
+	HaltOnStaleData bool `json:"halt_on_stale_data" yaml:"halt_on_stale_data" jsonschema:"description=Cancel the open orders when the market data feed goes stale,default=false"`
+
+	// ValidateOnly makes Run check the setup instead of trading: the engine
+	// configuration, the strategy initialization, the trading provider connection
+	// and that the trading provider knows every symbol. Each passed check is
+	// reported through OnStatusUpdate, and Run returns without streaming market
+	// data or placing orders.
+	ValidateOnly bool `json:"validate_only" yaml:"validate_only" jsonschema:"description=Only validate the configuration strategy provider connection and symbols then stop without streaming or placing orders,default=false"`
+
+	// LogLevel is the minimum level the engine and the strategy write to the
+	// console. It does not filter the strategy logs recorded in logs.parquet.
+	LogLevel types.LogLevel `json:"log_level" yaml:"log_level" jsonschema:"description=Minimum level of console log output,enum=debug,enum=info,enum=warning,enum=error,default=info"`
+
+	// LogFormat is the encoding of console log output.
+	LogFormat logger.Format `json:"log_format" yaml:"log_format" jsonschema:"description=Encoding of console log output,enum=json,enum=console,default=json"`
+
+	// StreamFlush sets how often persisted market data is flushed to its
+	// parquet file. The strategy's data source reads that file, so bars still
+	// buffered are not visible to it. The default flushes every bar.
+	StreamFlush writer.StreamingFlushConfig `json:"stream_flush" yaml:"stream_flush" jsonschema:"description=When persisted market data is flushed to its parquet file"`
 }
 
 // GetConfigSchema returns the JSON schema for LiveTradingEngineConfig.
@@ -213,6 +359,17 @@ type LiveTradingEngine interface {
 	// The provider must support the Stream() method.
 	SetMarketDataProvider(provider provider.Provider) error
 
+	// SetBackupMarketDataProvider configures a backup market data provider.
+	// When the primary provider's stream fails repeatedly the engine switches
+	// to the backup without stopping (see LiveTradingEngineConfig.MarketDataFailover).
+	SetBackupMarketDataProvider(provider provider.Provider) error
+
+	// SetWarmupDataSource configures a data source, such as a DuckDB database of
+	// parquet files, that the WarmupBars bars are read from instead of the market
+	// data provider's history. Bars that closed after the source's last bar are
+	// still taken from the provider, and streamed bars already backfilled are skipped.
+	SetWarmupDataSource(ds datasource.DataSource) error
+
 	// SetTradingProvider configures the trading provider.
 	SetTradingProvider(provider tradingprovider.TradingSystemProvider) error
 
@@ -224,6 +381,16 @@ type LiveTradingEngine interface {
 	// Blocks until context is cancelled or a fatal error occurs.
 	Run(ctx context.Context, callbacks LiveTradingCallbacks) error
 
+	// Pause stops the strategy from trading without stopping the engine. Market
+	// data keeps flowing into the data cache, while the strategy's orders are
+	// dropped or the strategy is not called, as set by PauseMode. The engine
+	// reports EngineStatusPaused on the next bar. Safe to call from any goroutine.
+	Pause()
+
+	// Resume lets the strategy trade again after Pause. The engine reports
+	// EngineStatusRunning on the next bar.
+	Resume()
+
 	// GetConfigSchema returns the JSON schema for engine configuration.
 	GetConfigSchema() (string, error)
 
@@ -232,4 +399,10 @@ type LiveTradingEngine interface {
 	// The wallet is callable outside Run() so the UI can show balance/assets
 	// without an active session.
 	Wallet() (wallet.Wallet, error)
+
+	// Account returns the trading provider strategies trade through. With
+	// InitialBalance set it is the paper account rather than the exchange
+	// account. Run sets it up before OnEngineStart is called, so read it from
+	// OnEngineStart or later. Nil until a trading provider is set.
+	Account() tradingprovider.TradingSystemProvider
 }
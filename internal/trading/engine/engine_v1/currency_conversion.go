@@ -0,0 +1,110 @@
+package engine_v1
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"go.uber.org/zap"
+)
+
+// currencyConverter wraps the trading provider when
+// LiveTradingEngineConfig.ConvertBalances is set and reports the equity of
+// GetAccountInfo as the value of every asset balance in the base currency.
+// An asset is converted at the latest cached close of its cross pair with the
+// base currency, e.g. BTCUSDT for BTC in USDT or USDTEUR inverted for EUR, and
+// otherwise at its rate in LiveTradingEngineConfig.ConversionRates. Assets
+// without a rate are left out of the equity with a warning. Accounts that do
+// not report balances per asset pass through unchanged, as do all other calls.
+type currencyConverter struct {
+	tradingprovider.TradingSystemProvider
+
+	baseCurrency string
+	rates        map[string]float64
+	latestBar    latestBarFunc
+	log          *logger.Logger
+
+	mu sync.Mutex
+	// missing is the assets last warned about for lacking a rate, so the
+	// warning is not repeated on every call.
+	missing map[string]bool
+}
+
+// newCurrencyConverter wraps inner to value balances in baseCurrency. rates is
+// the value of one unit of an asset in baseCurrency keyed by asset.
+func newCurrencyConverter(inner tradingprovider.TradingSystemProvider, baseCurrency string, rates map[string]float64, latestBar latestBarFunc, log *logger.Logger) *currencyConverter {
+	return &currencyConverter{
+		TradingSystemProvider: inner,
+		baseCurrency:          baseCurrency,
+		rates:                 rates,
+		latestBar:             latestBar,
+		log:                   log,
+		mu:                    sync.Mutex{},
+		missing:               map[string]bool{},
+	}
+}
+
+// GetAccountInfo implements tradingprovider.TradingSystemProvider.
+func (c *currencyConverter) GetAccountInfo() (types.AccountInfo, error) {
+	info, err := c.TradingSystemProvider.GetAccountInfo()
+	if err != nil || info.Balances == nil {
+		return info, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	equity := 0.0
+
+	// Sorted so the equity is summed in the same order on every call
+	for _, asset := range slices.Sorted(maps.Keys(info.Balances)) {
+		balance := info.Balances[asset]
+
+		rate, ok := c.rate(asset)
+		if !ok {
+			if !c.missing[asset] {
+				c.log.Warn("No conversion rate for asset, leaving it out of the equity",
+					zap.String("asset", asset),
+					zap.String("base_currency", c.baseCurrency),
+					zap.Float64("quantity", balance.Free+balance.Locked),
+				)
+			}
+
+			c.missing[asset] = true
+
+			continue
+		}
+
+		delete(c.missing, asset)
+
+		equity += (balance.Free + balance.Locked) * rate
+	}
+
+	info.Equity = equity
+
+	return info, nil
+}
+
+// rate returns the value of one unit of asset in the base currency.
+func (c *currencyConverter) rate(asset string) (float64, bool) {
+	asset = strings.ToUpper(asset)
+	if asset == c.baseCurrency {
+		return 1, true
+	}
+
+	if bar, ok := c.latestBar(asset + c.baseCurrency); ok && bar.Close > 0 {
+		return bar.Close, true
+	}
+
+	if bar, ok := c.latestBar(c.baseCurrency + asset); ok && bar.Close > 0 {
+		return 1 / bar.Close, true
+	}
+
+	rate, ok := c.rates[asset]
+
+	return rate, ok && rate > 0
+}
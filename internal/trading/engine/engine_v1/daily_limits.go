@@ -0,0 +1,168 @@
+package engine_v1
+
+import (
+	"sync"
+	"time"
+
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// dailyOrderLimiter wraps the trading provider handed to the strategy and
+// rejects orders once the strategy has placed LiveTradingEngineConfig.MaxOrdersPerDay
+// orders or MaxNotionalPerDay of notional on the current UTC day. The day is
+// taken from the time of the latest bar, so replayed data rolls over on the
+// replayed days. An OCO or bracket order counts as its entry. All other calls
+// pass through.
+type dailyOrderLimiter struct {
+	tradingprovider.TradingSystemProvider
+
+	maxOrders   int
+	maxNotional float64
+
+	mu sync.Mutex
+	// now is the time of the latest bar.
+	now time.Time
+	// lastPrices is the latest close of each symbol, the price of orders without one.
+	lastPrices map[string]float64
+	// day is the start of the UTC day the counts belong to.
+	day      time.Time
+	orders   int
+	notional float64
+}
+
+// newDailyOrderLimiter wraps inner with nothing placed yet. Zero disables a limit.
+func newDailyOrderLimiter(inner tradingprovider.TradingSystemProvider, maxOrders int, maxNotional float64) *dailyOrderLimiter {
+	return &dailyOrderLimiter{
+		TradingSystemProvider: inner,
+		maxOrders:             maxOrders,
+		maxNotional:           maxNotional,
+		mu:                    sync.Mutex{},
+		now:                   time.Time{},
+		lastPrices:            map[string]float64{},
+		day:                   time.Time{},
+		orders:                0,
+		notional:              0,
+	}
+}
+
+// OnMarketData records the bar's time and close.
+func (l *dailyOrderLimiter) OnMarketData(data types.MarketData) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.now = data.Time
+	l.lastPrices[data.Symbol] = data.Close
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (l *dailyOrderLimiter) PlaceOrder(order types.ExecuteOrder) error {
+	return l.place([]types.ExecuteOrder{order}, func() error {
+		return l.TradingSystemProvider.PlaceOrder(order)
+	})
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+func (l *dailyOrderLimiter) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	return l.place(orders, func() error {
+		return l.TradingSystemProvider.PlaceMultipleOrders(orders)
+	})
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+func (l *dailyOrderLimiter) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	var groupID string
+
+	err := l.place([]types.ExecuteOrder{entry}, func() error {
+		var err error
+
+		groupID, err = l.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+
+		return err
+	})
+
+	return groupID, err
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+func (l *dailyOrderLimiter) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	return l.place([]types.ExecuteOrder{entry}, func() error {
+		return l.TradingSystemProvider.PlaceBracketOrder(entry, stopLoss, takeProfit)
+	})
+}
+
+// place counts orders towards the day's limits and calls submit, or rejects
+// them when they would exceed a limit. Orders submit fails for are not counted.
+func (l *dailyOrderLimiter) place(orders []types.ExecuteOrder, submit func() error) error {
+	day, notional, err := l.reserve(orders)
+	if err != nil {
+		return err
+	}
+
+	if err := submit(); err != nil {
+		l.release(day, len(orders), notional)
+
+		return err
+	}
+
+	return nil
+}
+
+// reserve adds orders to the day's counts and returns the day and their notional,
+// or fails without counting them when they would exceed a limit.
+func (l *dailyOrderLimiter) reserve(orders []types.ExecuteOrder) (time.Time, float64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now.UTC()
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !day.Equal(l.day) {
+		l.day = day
+		l.orders = 0
+		l.notional = 0
+	}
+
+	notional := 0.0
+
+	for _, order := range orders {
+		price := order.Price
+		if price <= 0 {
+			price = l.lastPrices[order.Symbol]
+		}
+
+		notional += order.Quantity * price
+	}
+
+	if l.maxOrders > 0 && l.orders+len(orders) > l.maxOrders {
+		return day, 0, errors.Newf(errors.ErrCodeDailyLimitReached,
+			"order rejected (%s): %d of %d orders already placed today",
+			types.OrderReasonMaxOrdersPerDay, l.orders, l.maxOrders)
+	}
+
+	if l.maxNotional > 0 && l.notional+notional > l.maxNotional {
+		return day, 0, errors.Newf(errors.ErrCodeDailyLimitReached,
+			"order rejected (%s): notional %.2f would bring today's %.2f above the limit of %.2f",
+			types.OrderReasonMaxNotionalPerDay, notional, l.notional, l.maxNotional)
+	}
+
+	l.orders += len(orders)
+	l.notional += notional
+
+	return day, notional, nil
+}
+
+// release removes orders reserved on day from the counts, unless the day has
+// rolled over since.
+func (l *dailyOrderLimiter) release(day time.Time, orders int, notional float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !day.Equal(l.day) {
+		return
+	}
+
+	l.orders -= orders
+	l.notional -= notional
+}
@@ -0,0 +1,158 @@
+package engine_v1
+
+import (
+	"sync"
+
+	"github.com/rxtech-lab/argo-trading/internal/trading/engine"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// drawdownBreaker wraps the trading provider handed to the strategy and halts
+// trading once the account equity falls maxDrawdown below its peak, as set by
+// LiveTradingEngineConfig.MaxDrawdownPct. After it trips every order the
+// strategy places is rejected. All other calls pass through.
+type drawdownBreaker struct {
+	tradingprovider.TradingSystemProvider
+
+	maxDrawdown float64
+
+	mu      sync.RWMutex
+	peak    float64
+	seen    bool
+	tripped bool
+}
+
+// newDrawdownBreaker wraps inner with no equity observed yet.
+func newDrawdownBreaker(inner tradingprovider.TradingSystemProvider, maxDrawdown float64) *drawdownBreaker {
+	return &drawdownBreaker{
+		TradingSystemProvider: inner,
+		maxDrawdown:           maxDrawdown,
+		mu:                    sync.RWMutex{},
+		peak:                  0,
+		seen:                  false,
+		tripped:               false,
+	}
+}
+
+// Observe records the current equity and reports whether it tripped the
+// breaker, which happens once when equity falls below peak*(1-maxDrawdown).
+func (b *drawdownBreaker) Observe(equity float64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tripped {
+		return false
+	}
+
+	if !b.seen || equity > b.peak {
+		b.peak = equity
+		b.seen = true
+	}
+
+	if b.peak > 0 && equity < b.peak*(1-b.maxDrawdown) {
+		b.tripped = true
+
+		return true
+	}
+
+	return false
+}
+
+// Tripped reports whether trading has been halted.
+func (b *drawdownBreaker) Tripped() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.tripped
+}
+
+// Peak returns the highest equity observed.
+func (b *drawdownBreaker) Peak() float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.peak
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (b *drawdownBreaker) PlaceOrder(order types.ExecuteOrder) error {
+	if b.Tripped() {
+		return b.haltedError()
+	}
+
+	return b.TradingSystemProvider.PlaceOrder(order)
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+func (b *drawdownBreaker) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	if b.Tripped() {
+		return b.haltedError()
+	}
+
+	return b.TradingSystemProvider.PlaceMultipleOrders(orders)
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+func (b *drawdownBreaker) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	if b.Tripped() {
+		return "", b.haltedError()
+	}
+
+	return b.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+func (b *drawdownBreaker) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	if b.Tripped() {
+		return b.haltedError()
+	}
+
+	return b.TradingSystemProvider.PlaceBracketOrder(entry, stopLoss, takeProfit)
+}
+
+func (b *drawdownBreaker) haltedError() error {
+	return errors.Newf(errors.ErrCodeTradingHalted, "trading halted: max drawdown of %g reached", b.maxDrawdown)
+}
+
+// checkDrawdown feeds the account equity to the drawdown breaker. On the bar
+// the breaker trips it cancels all open orders, reports EngineStatusHalted and
+// calls onError, then returns the error that halted trading.
+func (e *LiveTradingEngineV1) checkDrawdown(status *engine.OnStatusUpdateCallback, onError *engine.OnErrorCallback) error {
+	info, err := e.tradingProvider.GetAccountInfo()
+	if err != nil {
+		e.log.Warn("Failed to get account equity for the drawdown check", zap.Error(err))
+
+		return nil
+	}
+
+	if !e.drawdownBreaker.Observe(info.Equity) {
+		return nil
+	}
+
+	haltErr := errors.Newf(errors.ErrCodeTradingHalted,
+		"trading halted: equity %.2f fell more than %g below its peak of %.2f",
+		info.Equity, e.config.MaxDrawdownPct, e.drawdownBreaker.Peak())
+
+	e.log.Error("Max drawdown reached, halting trading",
+		zap.Float64("equity", info.Equity),
+		zap.Float64("peak", e.drawdownBreaker.Peak()),
+		zap.Float64("max_drawdown_pct", e.config.MaxDrawdownPct),
+	)
+
+	if err := e.drawdownBreaker.CancelAllOrders(); err != nil {
+		e.log.Warn("Failed to cancel orders after max drawdown", zap.Error(err))
+	}
+
+	if status != nil {
+		_ = (*status)(types.EngineStatusHalted)
+	}
+
+	if onError != nil {
+		(*onError)(haltErr)
+	}
+
+	return haltErr
+}
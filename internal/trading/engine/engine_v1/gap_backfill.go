@@ -0,0 +1,118 @@
+package engine_v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/trading/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
+	"go.uber.org/zap"
+)
+
+// backfillGap loads the closed bars each symbol missed since its last persisted
+// bar, e.g. while the engine was down, from the market data provider's history
+// into the data cache and the parquet file before streaming starts. The engine
+// reports EngineStatusGapFilling while it does, and the backfilled range of
+// each symbol is reported through onProgress. A symbol whose history cannot be
+// loaded keeps its gap.
+func (e *LiveTradingEngineV1) backfillGap(ctx context.Context, statusCallback *engine.OnStatusUpdateCallback, onProgress *engine.OnPrefetchProgressCallback) {
+	interval := e.marketDataProvider.GetInterval()
+
+	barLength, err := provider.IntervalDuration(interval)
+	if err != nil {
+		e.log.Warn("Cannot detect gaps in persisted market data",
+			zap.String("interval", interval),
+			zap.Error(err),
+		)
+
+		return
+	}
+
+	now := e.clock.Now()
+	reported := false
+
+	for _, symbol := range e.marketDataProvider.GetSymbols() {
+		last, err := e.persistentDataSource.ReadLastData(symbol)
+		if err != nil {
+			// Nothing persisted yet, so there is no gap to fill
+			continue
+		}
+
+		// The bar after the last one may still be open and then arrives on the stream
+		start := last.Time.Add(barLength)
+		if now.Sub(start) < barLength {
+			continue
+		}
+
+		if !reported && statusCallback != nil {
+			reported = true
+			_ = (*statusCallback)(types.EngineStatusGapFilling)
+		}
+
+		bars, err := e.marketDataProvider.GetHistoricalKlines(ctx, symbol, interval, start, now)
+		if err != nil {
+			e.log.Warn("Failed to backfill gap in persisted market data",
+				zap.String("symbol", symbol),
+				zap.Time("from", start),
+				zap.Time("to", now),
+				zap.Error(err),
+			)
+
+			continue
+		}
+
+		missed := make([]types.MarketData, 0, len(bars))
+
+		for _, bar := range bars {
+			if bar.Time.After(last.Time) && !bar.Time.Add(barLength).After(now) {
+				missed = append(missed, bar)
+			}
+		}
+
+		sort.SliceStable(missed, func(i, j int) bool {
+			return missed[i].Time.Before(missed[j].Time)
+		})
+
+		if len(missed) == 0 {
+			continue
+		}
+
+		for _, bar := range missed {
+			if err := e.streamingWriter.Write(bar); err != nil {
+				e.log.Error("Failed to persist backfilled market data",
+					zap.String("symbol", bar.Symbol),
+					zap.Time("time", bar.Time),
+					zap.Error(err),
+				)
+			}
+
+			e.streamingDataSource.AddToCache(bar)
+		}
+
+		first, final := missed[0].Time, missed[len(missed)-1].Time
+
+		e.log.Info("Backfilled gap in persisted market data",
+			zap.String("symbol", symbol),
+			zap.Time("from", first),
+			zap.Time("to", final),
+			zap.Int("bars", len(missed)),
+		)
+
+		if onProgress != nil {
+			message := fmt.Sprintf("backfilled %d bars from %s to %s",
+				len(missed), first.Format(time.RFC3339), final.Format(time.RFC3339))
+			_ = (*onProgress)(symbol, float64(len(missed)), float64(len(missed)), message)
+		}
+	}
+
+	// The strategy's data source reads the parquet file, so it sees the gap
+	// only once the backfilled bars are flushed
+	if reported {
+		if err := e.streamingWriter.Flush(); err != nil {
+			e.log.Error("Failed to flush backfilled market data", zap.Error(err))
+		}
+	}
+}
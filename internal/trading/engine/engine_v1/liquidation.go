@@ -0,0 +1,140 @@
+package engine_v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/trading/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// LiquidationTimeout bounds the liquidation on stop when the run was stopped by
+// cancelling its context, which then can no longer bound it.
+const LiquidationTimeout = 30 * time.Second
+
+// liquidationContext returns the context the liquidation on stop runs under.
+// A run that ended on its own liquidates under its context, so cancelling it
+// aborts the liquidation. A run stopped by cancellation liquidates under a
+// detached context limited to LiquidationTimeout.
+func liquidationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Err() == nil {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(context.WithoutCancel(ctx), LiquidationTimeout)
+}
+
+// liquidatePositions cancels all open orders and closes the position in each
+// symbol the engine trades with a market order, reporting each order through
+// onOrderPlaced. Other positions are left alone: spot providers report every
+// asset balance as a position, including the quote and fee assets (USDT, BNB)
+// the account needs. An order that cannot be placed does not keep the other
+// positions open; liquidation stops once ctx is done.
+func (e *LiveTradingEngineV1) liquidatePositions(ctx context.Context, onOrderPlaced *engine.OnOrderPlacedCallback) error {
+	if err := e.tradingProvider.CancelAllOrders(); err != nil {
+		e.log.Warn("Failed to cancel open orders before liquidation", zap.Error(err))
+	}
+
+	var (
+		failed  []string
+		lastErr error
+	)
+
+	for _, symbol := range e.marketDataProvider.GetSymbols() {
+		position, err := e.tradingProvider.GetPosition(symbol)
+		if err != nil {
+			e.log.Warn("Failed to get position to liquidate", zap.String("symbol", symbol), zap.Error(err))
+
+			failed = append(failed, symbol)
+			lastErr = err
+
+			continue
+		}
+
+		position.Symbol = symbol
+		closing := make([]types.ExecuteOrder, 0, 2)
+
+		if position.TotalLongPositionQuantity > 0 {
+			closing = append(closing, e.liquidationOrder(position, types.PurchaseTypeSell, types.PositionTypeLong, position.TotalLongPositionQuantity))
+		}
+
+		if position.TotalShortPositionQuantity > 0 {
+			closing = append(closing, e.liquidationOrder(position, types.PurchaseTypeBuy, types.PositionTypeShort, position.TotalShortPositionQuantity))
+		}
+
+		for _, order := range closing {
+			if err := ctx.Err(); err != nil {
+				return errors.Wrap(errors.ErrCodeOrderFailed, "liquidation aborted", err)
+			}
+
+			if err := e.tradingProvider.PlaceOrder(order); err != nil {
+				e.log.Warn("Failed to place liquidation order", zap.String("symbol", order.Symbol), zap.Error(err))
+
+				failed = append(failed, order.Symbol)
+				lastErr = err
+
+				continue
+			}
+
+			e.log.Info("Liquidation order placed",
+				zap.String("symbol", order.Symbol),
+				zap.String("side", string(order.Side)),
+				zap.Float64("quantity", order.Quantity),
+			)
+
+			if onOrderPlaced != nil {
+				if err := (*onOrderPlaced)(order); err != nil {
+					e.log.Warn("OnOrderPlaced callback failed", zap.Error(err))
+				}
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Wrapf(errors.ErrCodeOrderFailed, lastErr, "failed to liquidate %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// liquidationOrder builds the market order closing quantity of a position. Its
+// price is the last cached close, or the average entry price before any bar.
+func (e *LiveTradingEngineV1) liquidationOrder(position types.Position, side types.PurchaseType, positionType types.PositionType, quantity float64) types.ExecuteOrder {
+	price := position.GetAverageLongPositionEntryPrice()
+	if positionType == types.PositionTypeShort {
+		price = position.GetAverageShortPositionEntryPrice()
+	}
+
+	if bar, ok := e.latestCachedBar(position.Symbol); ok {
+		price = bar.Close
+	}
+
+	return types.ExecuteOrder{
+		ID:        uuid.New().String(),
+		Symbol:    position.Symbol,
+		Side:      side,
+		OrderType: types.OrderTypeMarket,
+		Reason: types.Reason{
+			Reason:  types.OrderReasonLiquidation,
+			Message: fmt.Sprintf("liquidate %s position on engine stop", positionType),
+		},
+		Price:          price,
+		StrategyName:   position.StrategyName,
+		Quantity:       quantity,
+		PositionType:   positionType,
+		StopPrice:      0,
+		TrailOffset:    0,
+		TrailPercent:   false,
+		FilledQuantity: 0,
+		TimeInForce:    types.TimeInForceGTC,
+		GoodTillDate:   time.Time{},
+		TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+	}
+}
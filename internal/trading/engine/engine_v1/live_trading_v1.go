@@ -6,9 +6,13 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/cache"
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
+	"github.com/rxtech-lab/argo-trading/internal/clock"
 	"github.com/rxtech-lab/argo-trading/internal/indicator"
 	internalLog "github.com/rxtech-lab/argo-trading/internal/log"
 	"github.com/rxtech-lab/argo-trading/internal/logger"
@@ -38,7 +42,7 @@ const (
 // LiveTradingEngineV1 implements the LiveTradingEngine interface for real-time trading.
 type LiveTradingEngineV1 struct {
 	config              engine.LiveTradingEngineConfig
-	strategy            runtime.StrategyRuntime
+	strategies          []*loadedStrategy
 	strategyConfig      string
 	marketDataProvider  provider.Provider
 	tradingProvider     tradingprovider.TradingSystemProvider
@@ -50,13 +54,14 @@ type LiveTradingEngineV1 struct {
 	logStorage          internalLog.Log
 	initialized         bool
 
-	// strategyContext is the RuntimeContext bound to the WASM strategy API at
-	// init time. The tick loop mutates CurrentMarketData on this same struct so
-	// host callbacks (Log, Mark, etc.) can attach the current bar's symbol/time.
-	// Must be a single shared pointer — allocating a second context in Run()
-	// silently breaks host callbacks that gate on CurrentMarketData.
+	// strategyContext is the RuntimeContext built at init time that the
+	// context of each strategy derives from. See loadedStrategy.context.
 	strategyContext *runtime.RuntimeContext
 
+	// strategyMu is held while the strategies process a bar, so ReloadStrategy
+	// swaps a strategy only between bars.
+	strategyMu sync.Mutex
+
 	// Persistence fields for streaming data
 	dataDir              string                         // Directory for storing parquet files
 	providerName         string                         // Name of the data provider (e.g., "binance")
@@ -72,15 +77,73 @@ type LiveTradingEngineV1 struct {
 	// Prefetch management
 	prefetchManager *prefetch.PrefetchManager
 
+	// orderSuppressor gates strategy orders until the engine is running.
+	// Nil unless SuppressOrdersDuringWarmup is enabled.
+	orderSuppressor *orderSuppressingProvider
+
+	// drawdownBreaker halts strategy trading once equity falls MaxDrawdownPct
+	// below its peak. Nil unless MaxDrawdownPct is set.
+	drawdownBreaker *drawdownBreaker
+
+	// dailyLimiter rejects strategy orders over MaxOrdersPerDay or
+	// MaxNotionalPerDay. Nil unless either is set.
+	dailyLimiter *dailyOrderLimiter
+
+	// rejectionReporter reports the strategy's orders the provider rejects to
+	// OnOrderRejected. Nil unless the callback is registered.
+	rejectionReporter *orderRejectionReporter
+
+	// concurrentPlacer places the orders of PlaceMultipleOrders in parallel.
+	// Nil unless OrderConcurrency is above 1.
+	concurrentPlacer *concurrentOrderPlacer
+
+	// paperAccount simulates the trading account from InitialBalance in place of
+	// the exchange account. Nil unless InitialBalance is set.
+	paperAccount *paperAccount
+
+	// paused is set between Pause and Resume.
+	paused atomic.Bool
+
+	// pauseGate drops the strategy's orders while paused. Set by Run.
+	pauseGate *pauseGate
+
+	// currencyConverter values the account equity in BaseCurrency. Nil unless
+	// ConvertBalances is set.
+	currencyConverter *currencyConverter
+
+	// pendingOrders tracks the strategy's resting orders so they survive restarts.
+	// Nil unless persistence is enabled via NewLiveTradingEngineV1WithPersistence.
+	pendingOrders *pendingOrderTracker
+
+	// fillWatcher reports the strategy's orders to OnOrderFilled and the stats
+	// once they fill. Nil unless the callback is registered or stats are tracked.
+	fillWatcher *orderFillWatcher
+
+	// orderUpdates is the configured provider's push stream of order updates,
+	// which the fill watcher prefers over polling. Nil when the provider has none.
+	orderUpdates tradingprovider.OrderUpdateStreamer
+
+	// backupMarketDataProvider is composed with marketDataProvider at Run for failover.
+	backupMarketDataProvider provider.Provider
+
+	// warmupDataSource supplies the warm-up bars in place of the market data
+	// provider's history. Nil unless SetWarmupDataSource is called.
+	warmupDataSource datasource.DataSource
+
 	// Parquet writers for orders, trades, marks, logs
 	ordersWriter *writers.OrdersWriter
 	tradesWriter *writers.TradesWriter
 	marksWriter  *writers.MarksWriter
 	logsWriter   *writers.LogsWriter
 
-	// Provider status tracking
+	// Provider status tracking. statusMu guards both statuses, which the
+	// providers and the stale data watchdog update from their own goroutines.
+	statusMu         sync.Mutex
 	marketDataStatus types.ProviderConnectionStatus
 	tradingStatus    types.ProviderConnectionStatus
+
+	// clock tells the wall-clock time and paces replayed bars. Tests replace it with a fake clock.
+	clock clock.Clock
 }
 
 // NewLiveTradingEngineV1 creates a new LiveTradingEngineV1 instance without persistence.
@@ -91,32 +154,49 @@ func NewLiveTradingEngineV1() (engine.LiveTradingEngine, error) {
 	}
 
 	return &LiveTradingEngineV1{
-		config:               engine.LiveTradingEngineConfig{}, //nolint:exhaustruct // initialized via Initialize()
-		strategy:             nil,
-		strategyConfig:       "",
-		marketDataProvider:   nil,
-		tradingProvider:      nil,
-		streamingDataSource:  nil,
-		indicatorRegistry:    nil,
-		cache:                cache.NewCacheV1(),
-		marker:               nil,
-		log:                  log,
-		logStorage:           nil,
-		initialized:          false,
-		strategyContext:      nil,
-		dataDir:              "",
-		providerName:         "",
-		streamingWriter:      nil,
-		persistentDataSource: nil,
-		sessionManager:       nil,
-		statsTracker:         nil,
-		prefetchManager:      nil,
-		ordersWriter:         nil,
-		tradesWriter:         nil,
-		marksWriter:          nil,
-		logsWriter:           nil,
-		marketDataStatus:     types.ProviderStatusDisconnected,
-		tradingStatus:        types.ProviderStatusDisconnected,
+		config:                   engine.LiveTradingEngineConfig{}, //nolint:exhaustruct // initialized via Initialize()
+		strategies:               nil,
+		strategyContext:          nil,
+		strategyConfig:           "",
+		strategyMu:               sync.Mutex{},
+		marketDataProvider:       nil,
+		backupMarketDataProvider: nil,
+		warmupDataSource:         nil,
+		tradingProvider:          nil,
+		streamingDataSource:      nil,
+		indicatorRegistry:        nil,
+		cache:                    cache.NewCacheV1(),
+		marker:                   nil,
+		log:                      log,
+		logStorage:               nil,
+		initialized:              false,
+		dataDir:                  "",
+		providerName:             "",
+		streamingWriter:          nil,
+		persistentDataSource:     nil,
+		sessionManager:           nil,
+		statsTracker:             nil,
+		prefetchManager:          nil,
+		orderSuppressor:          nil,
+		drawdownBreaker:          nil,
+		dailyLimiter:             nil,
+		rejectionReporter:        nil,
+		concurrentPlacer:         nil,
+		paperAccount:             nil,
+		paused:                   atomic.Bool{},
+		pauseGate:                nil,
+		currencyConverter:        nil,
+		pendingOrders:            nil,
+		fillWatcher:              nil,
+		orderUpdates:             nil,
+		ordersWriter:             nil,
+		tradesWriter:             nil,
+		marksWriter:              nil,
+		logsWriter:               nil,
+		statusMu:                 sync.Mutex{},
+		marketDataStatus:         types.ProviderStatusDisconnected,
+		tradingStatus:            types.ProviderStatusDisconnected,
+		clock:                    clock.New(),
 	}, nil
 }
 
@@ -130,32 +210,49 @@ func NewLiveTradingEngineV1WithPersistence(dataDir, providerName string) (engine
 	}
 
 	return &LiveTradingEngineV1{
-		config:               engine.LiveTradingEngineConfig{}, //nolint:exhaustruct // initialized via Initialize()
-		strategy:             nil,
-		strategyConfig:       "",
-		marketDataProvider:   nil,
-		tradingProvider:      nil,
-		streamingDataSource:  nil,
-		indicatorRegistry:    nil,
-		cache:                cache.NewCacheV1(),
-		marker:               nil,
-		log:                  log,
-		logStorage:           nil,
-		initialized:          false,
-		strategyContext:      nil,
-		dataDir:              dataDir,
-		providerName:         providerName,
-		streamingWriter:      nil,
-		persistentDataSource: nil,
-		sessionManager:       nil,
-		statsTracker:         nil,
-		prefetchManager:      nil,
-		ordersWriter:         nil,
-		tradesWriter:         nil,
-		marksWriter:          nil,
-		logsWriter:           nil,
-		marketDataStatus:     types.ProviderStatusDisconnected,
-		tradingStatus:        types.ProviderStatusDisconnected,
+		config:                   engine.LiveTradingEngineConfig{}, //nolint:exhaustruct // initialized via Initialize()
+		strategies:               nil,
+		strategyContext:          nil,
+		strategyConfig:           "",
+		strategyMu:               sync.Mutex{},
+		marketDataProvider:       nil,
+		backupMarketDataProvider: nil,
+		warmupDataSource:         nil,
+		tradingProvider:          nil,
+		streamingDataSource:      nil,
+		indicatorRegistry:        nil,
+		cache:                    cache.NewCacheV1(),
+		marker:                   nil,
+		log:                      log,
+		logStorage:               nil,
+		initialized:              false,
+		dataDir:                  dataDir,
+		providerName:             providerName,
+		streamingWriter:          nil,
+		persistentDataSource:     nil,
+		sessionManager:           nil,
+		statsTracker:             nil,
+		prefetchManager:          nil,
+		orderSuppressor:          nil,
+		drawdownBreaker:          nil,
+		dailyLimiter:             nil,
+		rejectionReporter:        nil,
+		concurrentPlacer:         nil,
+		paperAccount:             nil,
+		paused:                   atomic.Bool{},
+		pauseGate:                nil,
+		currencyConverter:        nil,
+		pendingOrders:            nil,
+		fillWatcher:              nil,
+		orderUpdates:             nil,
+		ordersWriter:             nil,
+		tradesWriter:             nil,
+		marksWriter:              nil,
+		logsWriter:               nil,
+		statusMu:                 sync.Mutex{},
+		marketDataStatus:         types.ProviderStatusDisconnected,
+		tradingStatus:            types.ProviderStatusDisconnected,
+		clock:                    clock.New(),
 	}, nil
 }
 
@@ -166,6 +263,75 @@ func (e *LiveTradingEngineV1) Initialize(config engine.LiveTradingEngineConfig)
 		config.MarketDataCacheSize = DefaultMarketDataCacheSize
 	}
 
+	if config.WarmupBars < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "warmup bars must not be negative: %d", config.WarmupBars)
+	}
+
+	if config.MaxDrawdownPct < 0 || config.MaxDrawdownPct >= 1 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "max drawdown must be in [0, 1): %g", config.MaxDrawdownPct)
+	}
+
+	if config.MaxOrdersPerDay < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "max orders per day must not be negative: %d", config.MaxOrdersPerDay)
+	}
+
+	if config.MaxNotionalPerDay < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "max notional per day must not be negative: %g", config.MaxNotionalPerDay)
+	}
+
+	if config.OrderConcurrency < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "order concurrency must not be negative: %d", config.OrderConcurrency)
+	}
+
+	if config.InitialBalance < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "initial balance must not be negative: %g", config.InitialBalance)
+	}
+
+	if config.PaperFeeRate < 0 || config.PaperFeeRate >= 1 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "paper fee rate must be in [0, 1): %g", config.PaperFeeRate)
+	}
+
+	if config.ReplaySpeed < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "replay speed must not be negative: %g", config.ReplaySpeed)
+	}
+
+	if config.StaleDataIntervals < 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "stale data intervals must not be negative: %d", config.StaleDataIntervals)
+	}
+
+	if err := e.log.Configure(logger.Options{
+		Level:  config.LogLevel,
+		Format: config.LogFormat,
+		Output: nil,
+	}); err != nil {
+		return err
+	}
+
+	config.BaseCurrency = strings.ToUpper(config.BaseCurrency)
+	if config.BaseCurrency == "" {
+		config.BaseCurrency = DefaultBaseCurrency
+	}
+
+	switch config.PauseMode {
+	case "":
+		config.PauseMode = engine.PauseModeSuppressOrders
+	case engine.PauseModeSuppressOrders, engine.PauseModeSkipStrategy:
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unknown pause mode: %s", config.PauseMode)
+	}
+
+	conversionRates := make(map[string]float64, len(config.ConversionRates))
+
+	for asset, rate := range config.ConversionRates {
+		if rate <= 0 {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "conversion rate of %s must be positive: %g", asset, rate)
+		}
+
+		conversionRates[strings.ToUpper(asset)] = rate
+	}
+
+	config.ConversionRates = conversionRates
+
 	e.config = config
 
 	// Initialize indicator registry with standard indicators
@@ -177,6 +343,7 @@ func (e *LiveTradingEngineV1) Initialize(config engine.LiveTradingEngineConfig)
 	e.indicatorRegistry.RegisterIndicator(indicator.NewWaddahAttar())
 	e.indicatorRegistry.RegisterIndicator(indicator.NewRSI())
 	e.indicatorRegistry.RegisterIndicator(indicator.NewMA())
+	e.indicatorRegistry.RegisterIndicator(indicator.NewVWAP())
 
 	// Create streaming data source with configured cache size (used as fallback without persistence)
 	e.streamingDataSource = NewStreamingDataSource(config.MarketDataCacheSize)
@@ -212,7 +379,7 @@ func (e *LiveTradingEngineV1) LoadStrategyFromFile(strategyPath string) error {
 		return errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to create strategy runtime", err)
 	}
 
-	e.strategy = strategy
+	e.setStrategy(strategy)
 	e.log.Debug("Strategy loaded from file",
 		zap.String("path", strategyPath),
 	)
@@ -227,7 +394,7 @@ func (e *LiveTradingEngineV1) LoadStrategyFromBytes(strategyBytes []byte) error
 		return errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to create strategy runtime", err)
 	}
 
-	e.strategy = strategy
+	e.setStrategy(strategy)
 	e.log.Debug("Strategy loaded from bytes")
 
 	return nil
@@ -235,7 +402,7 @@ func (e *LiveTradingEngineV1) LoadStrategyFromBytes(strategyBytes []byte) error
 
 // LoadStrategy implements engine.LiveTradingEngine.
 func (e *LiveTradingEngineV1) LoadStrategy(strategy runtime.StrategyRuntime) error {
-	e.strategy = strategy
+	e.setStrategy(strategy)
 	e.log.Debug("Strategy loaded",
 		zap.String("name", strategy.Name()),
 	)
@@ -259,10 +426,41 @@ func (e *LiveTradingEngineV1) SetMarketDataProvider(marketProvider provider.Prov
 	return nil
 }
 
+// SetBackupMarketDataProvider implements engine.LiveTradingEngine.
+func (e *LiveTradingEngineV1) SetBackupMarketDataProvider(backupProvider provider.Provider) error {
+	if backupProvider == nil {
+		return errors.New(errors.ErrCodeInvalidParameter, "backup market data provider cannot be nil")
+	}
+
+	e.backupMarketDataProvider = backupProvider
+	e.log.Debug("Backup market data provider set")
+
+	return nil
+}
+
+// SetWarmupDataSource implements engine.LiveTradingEngine.
+func (e *LiveTradingEngineV1) SetWarmupDataSource(ds datasource.DataSource) error {
+	if ds == nil {
+		return errors.New(errors.ErrCodeInvalidParameter, "warmup data source cannot be nil")
+	}
+
+	e.warmupDataSource = ds
+	e.log.Debug("Warmup data source set")
+
+	return nil
+}
+
 // SetTradingProvider implements engine.LiveTradingEngine.
 func (e *LiveTradingEngineV1) SetTradingProvider(tradingProvider tradingprovider.TradingSystemProvider) error {
 	// Wrap with a logging decorator so strategy→host API calls are surfaced in running.log.
 	e.tradingProvider = tradingprovider.NewLoggingTradingSystemProvider(tradingProvider, e.log)
+
+	// The decorator does not expose optional interfaces, so take the stream from the provider itself
+	e.orderUpdates = nil
+	if streamer, ok := tradingProvider.(tradingprovider.OrderUpdateStreamer); ok {
+		e.orderUpdates = streamer
+	}
+
 	e.log.Debug("Trading provider set")
 
 	return nil
@@ -344,6 +542,8 @@ func (e *LiveTradingEngineV1) SetDataOutputPath(path string) error {
 func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTradingCallbacks) error {
 	var runErr error
 	firstDataReceived := false
+	// reportedPaused is whether EngineStatusPaused was the last status reported
+	reportedPaused := false
 
 	// Monotonically increasing sequence number for OnLiveDataChanged emissions.
 	var dataChangeSequence int64
@@ -370,8 +570,24 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 		}
 	}
 
+	// Set once the trading provider is reachable, so that stopping can liquidate
+	tradingConnected := false
+
 	// Always call OnEngineStop and cleanup when Run exits
 	defer func() {
+		// Flatten the account before anything else shuts down
+		if e.config.LiquidateOnStop && tradingConnected {
+			liquidationCtx, cancelLiquidation := liquidationContext(ctx)
+			if err := e.liquidatePositions(liquidationCtx, callbacks.OnOrderPlaced); err != nil {
+				e.log.Error("Failed to liquidate positions on stop", zap.Error(err))
+
+				if callbacks.OnError != nil {
+					(*callbacks.OnError)(err)
+				}
+			}
+			cancelLiquidation()
+		}
+
 		// Emit stopped status
 		if callbacks.OnStatusUpdate != nil {
 			_ = (*callbacks.OnStatusUpdate)(types.EngineStatusStopped)
@@ -384,6 +600,13 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 			}
 		}
 
+		// Save resting strategy orders so the next run can restore them
+		if e.pendingOrders != nil {
+			if err := e.pendingOrders.Save(e.pendingOrdersPath()); err != nil {
+				e.log.Warn("Failed to save pending orders", zap.Error(err))
+			}
+		}
+
 		// Cleanup parquet writers
 		if e.ordersWriter != nil {
 			if err := e.ordersWriter.Flush(); err != nil {
@@ -464,10 +687,18 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 		return err
 	}
 
+	// A validate-only run stops after checking the setup
+	if e.config.ValidateOnly {
+		runErr = e.validate(ctx, callbacks)
+
+		return runErr
+	}
+
 	// Initialize persistence components now that provider is available
 	if e.dataDir != "" && e.providerName != "" && e.streamingWriter == nil {
 		interval := e.marketDataProvider.GetInterval()
 		e.streamingWriter = writer.NewStreamingDuckDBWriter(e.dataDir, e.providerName, interval)
+		e.streamingWriter.SetFlushConfig(e.config.StreamFlush)
 		if err := e.streamingWriter.Initialize(); err != nil {
 			runErr = errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to initialize streaming writer", err)
 
@@ -497,6 +728,7 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 		interval := e.marketDataProvider.GetInterval()
 		runPath := e.sessionManager.GetCurrentRunPath()
 		e.streamingWriter = writer.NewStreamingDuckDBWriter(runPath, "live", interval)
+		e.streamingWriter.SetFlushConfig(e.config.StreamFlush)
 		if err := e.streamingWriter.Initialize(); err != nil {
 			runErr = errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to initialize streaming writer for session", err)
 
@@ -517,6 +749,41 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 		)
 	}
 
+	// Compose the primary and backup providers so stream failures switch over
+	if e.backupMarketDataProvider != nil {
+		e.marketDataProvider = provider.NewFailoverProvider(e.marketDataProvider, e.backupMarketDataProvider, e.config.MarketDataFailover)
+		e.backupMarketDataProvider = nil
+
+		e.log.Info("Market data failover enabled",
+			zap.Int("max_consecutive_errors", e.config.MarketDataFailover.MaxConsecutiveErrors),
+		)
+	}
+
+	// Trade a simulated account seeded with the initial balance instead of the
+	// exchange account. Its orders never reach the exchange, so neither do the
+	// exchange's order updates.
+	if e.config.InitialBalance > 0 && e.paperAccount == nil {
+		e.paperAccount = newPaperAccount(e.tradingProvider, e.config.InitialBalance, e.config.BaseCurrency, e.config.PaperFeeRate, e.latestCachedBar)
+		e.tradingProvider = tradingprovider.NewLoggingTradingSystemProvider(e.paperAccount, e.log)
+		e.orderUpdates = nil
+
+		e.log.Info("Paper trading enabled",
+			zap.Float64("initial_balance", e.config.InitialBalance),
+			zap.String("base_currency", e.config.BaseCurrency),
+		)
+	}
+
+	// Value the balances of accounts holding several quote currencies in the base currency
+	if e.config.ConvertBalances && e.currencyConverter == nil {
+		e.currencyConverter = newCurrencyConverter(e.tradingProvider, e.config.BaseCurrency, e.config.ConversionRates, e.latestCachedBar, e.log)
+		e.tradingProvider = e.currencyConverter
+
+		e.log.Info("Balance conversion enabled",
+			zap.String("base_currency", e.config.BaseCurrency),
+			zap.Int("conversion_rates", len(e.config.ConversionRates)),
+		)
+	}
+
 	// Set up provider status callbacks
 	e.setupProviderStatusCallbacks(callbacks.OnProviderStatusChange)
 
@@ -542,6 +809,101 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 	}
 
 	e.updateTradingStatus(types.ProviderStatusConnected, callbacks.OnProviderStatusChange)
+	tradingConnected = true
+
+	// Restore the resting orders saved by the previous run before the strategy
+	// sees any data, keeping only those the exchange still reports as open
+	if e.dataDir != "" && e.pendingOrders == nil {
+		e.pendingOrders = newPendingOrderTracker(e.tradingProvider, e.log)
+		if err := e.pendingOrders.Restore(e.pendingOrdersPath()); err != nil {
+			e.log.Warn("Failed to restore pending orders", zap.Error(err))
+		}
+
+		e.log.Info("Pending orders restored",
+			zap.Int("count", len(e.pendingOrders.PendingOrders())),
+		)
+	}
+
+	// Watch the strategy's orders for fills only when the host listens for them
+	// or the stats record them, since every check polls the provider. Providers
+	// that push order updates are polled only when their stream may have missed some.
+	e.fillWatcher = nil
+
+	var orderUpdates *orderUpdateFeed
+
+	if callbacks.OnOrderFilled != nil || e.statsTracker != nil {
+		e.fillWatcher = newOrderFillWatcher(e.strategyTradingProvider(), e.log, e.clock)
+
+		if e.orderUpdates != nil {
+			feedCtx, stopFeed := context.WithCancel(ctx)
+			defer stopFeed()
+
+			orderUpdates = startOrderUpdateFeed(feedCtx, e.orderUpdates, e.log)
+		}
+	}
+
+	// Cap the orders and notional the strategy places per day
+	e.dailyLimiter = nil
+
+	if e.config.MaxOrdersPerDay > 0 || e.config.MaxNotionalPerDay > 0 {
+		e.dailyLimiter = newDailyOrderLimiter(e.strategyTradingProvider(), e.config.MaxOrdersPerDay, e.config.MaxNotionalPerDay)
+	}
+
+	// Gate strategy orders until the engine is running so warmup does not trade
+	statusCallback := callbacks.OnStatusUpdate
+	if e.config.SuppressOrdersDuringWarmup {
+		e.orderSuppressor = newOrderSuppressingProvider(e.limitedTradingProvider(), e.log, e.logStorage, e.clock)
+
+		suppressor := e.orderSuppressor
+		forwardStatus := engine.OnStatusUpdateCallback(func(status types.EngineStatus) error {
+			suppressor.SetStatus(status)
+			if callbacks.OnStatusUpdate != nil {
+				return (*callbacks.OnStatusUpdate)(status)
+			}
+
+			return nil
+		})
+		statusCallback = &forwardStatus
+	}
+
+	// Halt trading once equity falls too far below its peak
+	e.drawdownBreaker = nil
+
+	if e.config.MaxDrawdownPct > 0 {
+		var inner tradingprovider.TradingSystemProvider = e.limitedTradingProvider()
+		if e.orderSuppressor != nil {
+			inner = e.orderSuppressor
+		}
+
+		e.drawdownBreaker = newDrawdownBreaker(inner, e.config.MaxDrawdownPct)
+	}
+
+	// Drop the strategy's orders while the engine is paused. Cleared first so
+	// the gate wraps this run's providers rather than the previous gate.
+	e.pauseGate = nil
+	e.pauseGate = newPauseGate(e.guardedTradingProvider(), &e.paused, e.log)
+
+	// Tell the host about orders the provider rejects, on top of every other wrapper
+	e.rejectionReporter = nil
+
+	if callbacks.OnOrderRejected != nil {
+		e.rejectionReporter = newOrderRejectionReporter(e.guardedTradingProvider(), e.log, *callbacks.OnOrderRejected)
+	}
+
+	// Submit the orders of PlaceMultipleOrders in parallel through every other wrapper
+	e.concurrentPlacer = nil
+
+	if e.config.OrderConcurrency > 1 {
+		e.concurrentPlacer = newConcurrentOrderPlacer(e.strategyFacingProvider(), e.config.OrderConcurrency)
+	}
+
+	// Hold back the running status until the first bars of every symbol are cached
+	var warmup *warmupTracker
+
+	if e.config.WarmupBars > 0 {
+		warmup = newWarmupTracker(e.config.WarmupBars, e.marketDataProvider.GetSymbols(), statusCallback)
+		statusCallback = warmup.StatusCallback()
+	}
 
 	// Initialize strategy
 	if err := e.initializeStrategy(); err != nil {
@@ -555,7 +917,7 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 		strategyInfo := types.StrategyInfo{
 			ID:      "", // Strategy ID not available from runtime
 			Version: "", // Strategy version not available from runtime
-			Name:    e.strategy.Name(),
+			Name:    strings.Join(e.strategyNames(), ", "),
 		}
 		e.statsTracker.Initialize(
 			e.marketDataProvider.GetSymbols(),
@@ -564,6 +926,14 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 			e.sessionManager.GetSessionStart(),
 			strategyInfo,
 		)
+		e.statsTracker.SetInterval(e.marketDataProvider.GetInterval())
+
+		// The equity curve's returns are relative to the equity at session start
+		if info, err := e.tradingProvider.GetAccountInfo(); err != nil {
+			e.log.Warn("Failed to get the starting equity, Sharpe and Sortino ratios are disabled", zap.Error(err))
+		} else {
+			e.statsTracker.SetStartingEquity(info.Equity)
+		}
 
 		// Update market data file path if streaming writer is available
 		if e.streamingWriter != nil {
@@ -585,7 +955,7 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 			e.marketDataProvider,
 			e.streamingWriter,
 			e.marketDataProvider.GetInterval(),
-			callbacks.OnStatusUpdate,
+			statusCallback,
 			callbacks.OnPrefetchProgress,
 		)
 
@@ -597,6 +967,20 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 		}
 	}
 
+	// Backfill the bars missed since the last persisted bar. The prefetch
+	// already resumes from the last persisted bar when enabled.
+	if e.persistentDataSource != nil && !e.config.Prefetch.Enabled {
+		e.backfillGap(ctx, statusCallback, callbacks.OnPrefetchProgress)
+	}
+
+	// Backfill the warm-up bars from history unless the prefetch already has.
+	// The stream takes over after the last backfilled bar of each symbol.
+	var warmupHandoff map[string]time.Time
+
+	if warmup != nil && !e.config.Prefetch.Enabled {
+		warmupHandoff = e.backfillWarmup(ctx, warmup)
+	}
+
 	// Call OnEngineStart callback
 	if callbacks.OnEngineStart != nil {
 		// Determine previousDataPath - if persistence is enabled, provide the parquet file path
@@ -617,7 +1001,20 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 		zap.Strings("symbols", e.marketDataProvider.GetSymbols()),
 		zap.String("interval", e.marketDataProvider.GetInterval()),
 	)
-	stream := e.marketDataProvider.Stream(ctx)
+	bars, err := e.marketDataStream(ctx)
+	if err != nil {
+		runErr = err
+
+		return runErr
+	}
+
+	stream := e.paceReplay(ctx, bars)
+
+	// Watch the stream for bars that stop arriving
+	watchdogCtx, stopWatchdog := context.WithCancel(ctx)
+	defer stopWatchdog()
+
+	staleness := e.startStaleWatchdog(watchdogCtx, callbacks)
 
 	// Cursors into the in-memory log/mark buffers: each tick only persists
 	// entries appended since the previous tick. Without this, GetLogs/GetMarks
@@ -658,6 +1055,24 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 			continue
 		}
 
+		if staleness != nil {
+			staleness.Observe()
+		}
+
+		// The stream may start with bars the warm-up already backfilled
+		if last, ok := warmupHandoff[data.Symbol]; ok {
+			if !data.Time.After(last) {
+				e.log.Debug("skipping streamed bar already backfilled for warmup",
+					zap.String("symbol", data.Symbol),
+					zap.Time("time", data.Time),
+				)
+
+				continue
+			}
+
+			delete(warmupHandoff, data.Symbol)
+		}
+
 		// Handle first data point - check for gaps
 		if !firstDataReceived {
 			firstDataReceived = true
@@ -670,9 +1085,15 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 				}
 			}
 
-			// Emit running status if no prefetch manager
-			if e.prefetchManager == nil && callbacks.OnStatusUpdate != nil {
-				_ = (*callbacks.OnStatusUpdate)(types.EngineStatusRunning)
+			// Emit running status unless the prefetch manager does
+			if (e.prefetchManager == nil || !e.config.Prefetch.Enabled) && statusCallback != nil {
+				_ = (*statusCallback)(types.EngineStatusRunning)
+			}
+
+			// Warmup is over; let strategy orders through from this bar on.
+			// With warm-up bars the tracker does this once they have streamed.
+			if e.orderSuppressor != nil && warmup == nil {
+				e.orderSuppressor.SetStatus(types.EngineStatusRunning)
 			}
 		}
 
@@ -718,9 +1139,21 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 		// Add to in-memory cache as well (used when persistence is not enabled)
 		e.streamingDataSource.AddToCache(data)
 
-		// Update current market data on the shared strategy context so host
+		// Fill the paper account's resting orders the bar traded through
+		if e.paperAccount != nil {
+			e.paperAccount.OnMarketData(data)
+		}
+
+		// Roll the daily order limits over on the bar time
+		if e.dailyLimiter != nil {
+			e.dailyLimiter.OnMarketData(data)
+		}
+
+		// Update current market data on each strategy context so host
 		// callbacks (Log, Mark) see the current bar.
-		e.strategyContext.CurrentMarketData = &data
+		for _, strategy := range e.strategies {
+			strategy.context.CurrentMarketData = &data
+		}
 
 		// Invoke OnMarketData callback
 		if callbacks.OnMarketData != nil {
@@ -736,27 +1169,105 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 			}
 		}
 
-		// Execute strategy
-		e.log.Info("processing strategy onTick",
-			zap.String("symbol", data.Symbol),
-			zap.Time("time", data.Time),
-			zap.Float64("close", data.Close),
-		)
-		if err := e.strategy.ProcessData(data); err != nil {
-			if callbacks.OnStrategyError != nil {
-				(*callbacks.OnStrategyError)(data, err)
+		warmingUp := warmup != nil && warmup.Observe(data)
+
+		// Report a pause or resume since the previous bar
+		paused := e.paused.Load()
+		if !warmingUp && paused != reportedPaused {
+			reportedPaused = paused
+
+			halted := e.drawdownBreaker != nil && e.drawdownBreaker.Tripped()
+			if statusCallback != nil && !halted {
+				status := types.EngineStatusRunning
+				if paused {
+					status = types.EngineStatusPaused
+				}
+
+				_ = (*statusCallback)(status)
+			}
+		}
+
+		// Check the drawdown before the strategy can trade on the bar
+		if !warmingUp && e.drawdownBreaker != nil && !e.drawdownBreaker.Tripped() {
+			if haltErr := e.checkDrawdown(statusCallback, callbacks.OnError); haltErr != nil && e.config.StopOnMaxDrawdown {
+				runErr = haltErr
+
+				return runErr
 			}
+		}
 
-			e.log.Warn("strategy returned error",
+		// Execute strategy, unless the bar only warms up the data cache,
+		// trading has been halted or the strategy is paused
+		switch {
+		case warmingUp:
+			e.log.Debug("skipping strategy onTick during warmup",
 				zap.String("symbol", data.Symbol),
-				zap.Error(err),
+				zap.Time("time", data.Time),
 			)
-			// Continue processing - don't abort on strategy errors
-		} else {
-			e.log.Info("strategy returned",
+		case e.drawdownBreaker != nil && e.drawdownBreaker.Tripped():
+			e.log.Debug("skipping strategy onTick, trading halted by max drawdown",
+				zap.String("symbol", data.Symbol),
+				zap.Time("time", data.Time),
+			)
+		case paused && e.config.PauseMode == engine.PauseModeSkipStrategy:
+			e.log.Debug("skipping strategy onTick, trading paused",
 				zap.String("symbol", data.Symbol),
 				zap.Time("time", data.Time),
 			)
+		default:
+			// Every strategy sees the bar; an error from one does not stop the others
+			e.strategyMu.Lock()
+			for _, strategy := range e.strategies {
+				name := strategy.runtime.Name()
+
+				e.log.Info("processing strategy onTick",
+					zap.String("strategy", name),
+					zap.String("symbol", data.Symbol),
+					zap.Time("time", data.Time),
+					zap.Float64("close", data.Close),
+				)
+
+				if err := strategy.runtime.ProcessData(data); err != nil {
+					if callbacks.OnStrategyError != nil {
+						(*callbacks.OnStrategyError)(data, err)
+					}
+
+					e.log.Warn("strategy returned error",
+						zap.String("strategy", name),
+						zap.String("symbol", data.Symbol),
+						zap.Error(err),
+					)
+					// Continue processing - don't abort on strategy errors
+				} else {
+					e.log.Info("strategy returned",
+						zap.String("strategy", name),
+						zap.String("symbol", data.Symbol),
+						zap.Time("time", data.Time),
+					)
+				}
+			}
+			e.strategyMu.Unlock()
+		}
+
+		// Report the strategy's orders that filled since the previous check
+		if e.fillWatcher != nil {
+			for _, trade := range e.collectOrderFills(orderUpdates) {
+				if e.statsTracker != nil {
+					e.statsTracker.RecordTrade(trade)
+				}
+
+				if callbacks.OnOrderFilled == nil {
+					continue
+				}
+
+				if err := (*callbacks.OnOrderFilled)(trade); err != nil {
+					e.log.Warn("OnOrderFilled callback failed",
+						zap.String("order_id", trade.Order.OrderID),
+						zap.Error(err),
+					)
+					// Continue processing - don't abort on callback errors
+				}
+			}
 		}
 
 		// Track which categories produced persisted writes this tick.
@@ -804,6 +1315,16 @@ func (e *LiveTradingEngineV1) Run(ctx context.Context, callbacks engine.LiveTrad
 
 		// Update and emit stats periodically
 		if e.statsTracker != nil {
+			// Sample the account equity once per bar for Sharpe, Sortino and drawdown
+			if info, err := e.tradingProvider.GetAccountInfo(); err != nil {
+				e.log.Warn("Failed to get account equity for stats", zap.Error(err))
+			} else {
+				e.statsTracker.SetUnrealizedPnL(info.UnrealizedPnL)
+				e.statsTracker.SetAccountEquity(info.Equity)
+			}
+
+			e.statsTracker.RecordEquitySnapshot(data.Time)
+
 			// Write stats to disk
 			if err := e.statsTracker.WriteStatsYAML(); err != nil {
 				e.log.Warn("Failed to write stats",
@@ -864,6 +1385,11 @@ func (e *LiveTradingEngineV1) Wallet() (wallet.Wallet, error) {
 	})
 }
 
+// Account implements engine.LiveTradingEngine.
+func (e *LiveTradingEngineV1) Account() tradingprovider.TradingSystemProvider {
+	return e.tradingProvider
+}
+
 // walletSnapshot captures the wallet fields tracked across ticks for change
 // detection. Asset quantities are keyed by symbol so we only fire OnAssetsChanged
 // when the set or quantity actually moves.
@@ -1018,26 +1544,122 @@ func assetMapsEqual(a, b map[string]float64) bool {
 	return true
 }
 
+// setStrategy makes strategy the only loaded strategy.
+func (e *LiveTradingEngineV1) setStrategy(strategy runtime.StrategyRuntime) {
+	e.strategyMu.Lock()
+	defer e.strategyMu.Unlock()
+
+	e.strategies = []*loadedStrategy{{runtime: strategy, context: nil}}
+}
+
+// strategyTradingProvider returns the trading provider orders from the strategy
+// go through: the fill watcher when fills are reported or recorded, then the
+// pending-order tracker when persistence is enabled, the configured provider
+// otherwise.
+func (e *LiveTradingEngineV1) strategyTradingProvider() tradingprovider.TradingSystemProvider {
+	if e.fillWatcher != nil {
+		return e.fillWatcher
+	}
+
+	if e.pendingOrders != nil {
+		return e.pendingOrders
+	}
+
+	return e.tradingProvider
+}
+
+// limitedTradingProvider returns the strategy's trading provider behind the
+// daily order limits, when they are set.
+func (e *LiveTradingEngineV1) limitedTradingProvider() tradingprovider.TradingSystemProvider {
+	if e.dailyLimiter != nil {
+		return e.dailyLimiter
+	}
+
+	return e.strategyTradingProvider()
+}
+
+// guardedTradingProvider returns the strategy's trading provider behind the
+// daily limits, the warmup order gate, the drawdown breaker and the pause gate,
+// when they are set.
+func (e *LiveTradingEngineV1) guardedTradingProvider() tradingprovider.TradingSystemProvider {
+	if e.pauseGate != nil {
+		return e.pauseGate
+	}
+
+	if e.drawdownBreaker != nil {
+		return e.drawdownBreaker
+	}
+
+	if e.orderSuppressor != nil {
+		return e.orderSuppressor
+	}
+
+	return e.limitedTradingProvider()
+}
+
+// strategyFacingProvider returns the guarded trading provider behind the
+// rejection reporter, when OnOrderRejected is registered.
+func (e *LiveTradingEngineV1) strategyFacingProvider() tradingprovider.TradingSystemProvider {
+	if e.rejectionReporter != nil {
+		return e.rejectionReporter
+	}
+
+	return e.guardedTradingProvider()
+}
+
+// collectOrderFills returns the strategy's orders that filled since the
+// previous call, from the pushed updates when feed is set and by polling the
+// provider otherwise or when the stream may have missed updates.
+func (e *LiveTradingEngineV1) collectOrderFills(feed *orderUpdateFeed) []types.Trade {
+	if feed == nil {
+		return e.fillWatcher.CheckFills()
+	}
+
+	updates, missed := feed.Drain()
+
+	fills := e.fillWatcher.ApplyUpdates(updates)
+	if missed {
+		fills = append(fills, e.fillWatcher.CheckFills()...)
+	}
+
+	return fills
+}
+
+// latestCachedBar returns the most recent bar of symbol in the market data cache.
+func (e *LiveTradingEngineV1) latestCachedBar(symbol string) (types.MarketData, bool) {
+	data, err := e.streamingDataSource.ReadLastData(symbol)
+	if err != nil {
+		return types.MarketData{}, false
+	}
+
+	return data, true
+}
+
+// pendingOrdersPath returns the file pending orders are persisted to.
+func (e *LiveTradingEngineV1) pendingOrdersPath() string {
+	return filepath.Join(e.dataDir, PendingOrdersFileName)
+}
+
 // preRunCheck validates that all required components are configured before running.
 func (e *LiveTradingEngineV1) preRunCheck() error {
 	if !e.initialized {
-		return errors.New(errors.ErrCodeBacktestInitFailed, "engine not initialized - call Initialize() first")
+		return engine.ErrNotInitialized
 	}
 
-	if e.strategy == nil {
-		return errors.New(errors.ErrCodeBacktestInitFailed, "strategy not loaded - call LoadStrategy*() first")
+	if len(e.strategies) == 0 {
+		return engine.ErrStrategyNotLoaded
 	}
 
 	if e.marketDataProvider == nil {
-		return errors.New(errors.ErrCodeBacktestInitFailed, "market data provider not set - call SetMarketDataProvider() first")
+		return engine.ErrNoMarketDataProvider
 	}
 
 	if e.tradingProvider == nil {
-		return errors.New(errors.ErrCodeBacktestInitFailed, "trading provider not set - call SetTradingProvider() first")
+		return engine.ErrNoTradingProvider
 	}
 
 	if len(e.marketDataProvider.GetSymbols()) == 0 {
-		return errors.New(errors.ErrCodeBacktestInitFailed, "no symbols configured")
+		return engine.ErrNoSymbols
 	}
 
 	if e.marketDataProvider.GetInterval() == "" {
@@ -1055,47 +1677,75 @@ func (e *LiveTradingEngineV1) initializeStrategy() error {
 		dataSource = e.persistentDataSource
 	}
 
-	// Build the shared RuntimeContext once and store the pointer on the engine.
-	// Run() mutates CurrentMarketData on this same struct each tick so host
-	// callbacks (Log, Mark) can attach the current bar's symbol/time.
+	// Build the RuntimeContext the strategy contexts derive from. Run() mutates
+	// CurrentMarketData on each strategy context every tick so host callbacks
+	// (Log, Mark, GetCurrentTime) can attach the current bar's symbol/time.
+	var tradingSystem tradingprovider.TradingSystemProvider = e.strategyFacingProvider()
+	if e.concurrentPlacer != nil {
+		tradingSystem = e.concurrentPlacer
+	}
+
+	var interval string
+	if e.marketDataProvider != nil {
+		interval = e.marketDataProvider.GetInterval()
+	}
+
 	e.strategyContext = &runtime.RuntimeContext{
 		DataSource:        dataSource,
+		MarketDataCache:   e.streamingDataSource.GetCache(),
 		IndicatorRegistry: e.indicatorRegistry,
 		Marker:            e.marker,
-		TradingSystem:     e.tradingProvider,
+		TradingSystem:     tradingSystem,
 		Cache:             e.cache,
 		Logger:            e.log,
 		LogStorage:        e.logStorage,
 		CurrentMarketData: nil,
+		Interval:          interval,
+	}
+
+	for _, strategy := range e.strategies {
+		strategy.context = e.newStrategyContext(strategy.runtime, *e.strategyContext)
+
+		if err := e.initializeStrategyRuntime(strategy); err != nil {
+			return err
+		}
+
+		e.log.Info("Strategy initialized",
+			zap.String("name", strategy.runtime.Name()),
+		)
 	}
 
+	return nil
+}
+
+// initializeStrategyRuntime binds the strategy to its RuntimeContext, checks
+// that it was compiled for a compatible engine version and initializes it with
+// the strategy config.
+func (e *LiveTradingEngineV1) initializeStrategyRuntime(loaded *loadedStrategy) error {
+	strategy := loaded.runtime
+
 	// Initialize strategy API first
-	err := e.strategy.InitializeApi(wasm.NewWasmStrategyApi(e.strategyContext))
+	err := strategy.InitializeApi(wasm.NewWasmStrategyApi(loaded.context))
 	if err != nil {
 		return errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to initialize strategy API", err)
 	}
 
 	// Check version compatibility between engine and strategy
-	strategyRuntimeVersion, err := e.strategy.GetRuntimeEngineVersion()
+	strategyRuntimeVersion, err := strategy.GetRuntimeEngineVersion()
 	if err != nil {
 		return errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to get strategy runtime version", err)
 	}
 
 	if err := version.CheckVersionCompatibility(version.Version, strategyRuntimeVersion); err != nil {
-		return errors.Wrapf(errors.ErrCodeVersionMismatch, err,
-			"version mismatch: engine version %s is incompatible with strategy compiled for version %s",
-			version.Version, strategyRuntimeVersion)
+		return fmt.Errorf("%w: engine version %s is incompatible with strategy compiled for version %s: %w",
+			engine.ErrVersionMismatch, version.Version, strategyRuntimeVersion, err)
 	}
 
 	// Initialize strategy with config
-	if err := e.strategy.Initialize(e.strategyConfig); err != nil {
+	if err := strategy.Initialize(e.strategyConfig); err != nil {
 		return errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to initialize strategy", err)
 	}
 
-	e.log.Info("Strategy initialized",
-		zap.String("name", e.strategy.Name()),
-	)
-
 	return nil
 }
 
@@ -1162,6 +1812,9 @@ func (l *LiveTradingLog) GetLogs() ([]internalLog.LogEntry, error) {
 
 // updateMarketDataStatus updates the market data provider status and emits a callback if registered.
 func (e *LiveTradingEngineV1) updateMarketDataStatus(status types.ProviderConnectionStatus, callback *engine.OnProviderStatusChangeCallback) {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+
 	if e.marketDataStatus != status {
 		e.marketDataStatus = status
 		e.emitProviderStatusUpdate(callback)
@@ -1170,6 +1823,9 @@ func (e *LiveTradingEngineV1) updateMarketDataStatus(status types.ProviderConnec
 
 // updateTradingStatus updates the trading provider status and emits a callback if registered.
 func (e *LiveTradingEngineV1) updateTradingStatus(status types.ProviderConnectionStatus, callback *engine.OnProviderStatusChangeCallback) {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+
 	if e.tradingStatus != status {
 		e.tradingStatus = status
 		e.emitProviderStatusUpdate(callback)
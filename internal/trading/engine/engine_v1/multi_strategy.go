@@ -0,0 +1,208 @@
+package engine_v1
+
+import (
+	"maps"
+
+	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/cache"
+	internalLog "github.com/rxtech-lab/argo-trading/internal/log"
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	"github.com/rxtech-lab/argo-trading/internal/marker"
+	"github.com/rxtech-lab/argo-trading/internal/runtime"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// StrategyLogField is the log entry field naming the strategy that wrote the
+// log when the engine runs several strategies.
+const StrategyLogField = "strategy"
+
+// loadedStrategy is a strategy the engine runs.
+type loadedStrategy struct {
+	runtime runtime.StrategyRuntime
+
+	// context is the RuntimeContext bound to the strategy API at init time. The
+	// tick loop mutates CurrentMarketData on this same struct so host callbacks
+	// (Log, Mark, etc.) can attach the current bar's symbol/time. Must be a
+	// single pointer per strategy — allocating a second context in Run()
+	// silently breaks host callbacks that gate on CurrentMarketData.
+	context *runtime.RuntimeContext
+}
+
+// LoadStrategies loads several strategies that run side by side on the same
+// market data. Every bar goes to each strategy in the given order. Orders,
+// marks and logs are tagged with the name of the strategy that made them, so
+// strategy names must be unique. Each strategy gets its own cache, and an error
+// from one strategy does not stop the others.
+func (e *LiveTradingEngineV1) LoadStrategies(strategies []runtime.StrategyRuntime) error {
+	if len(strategies) == 0 {
+		return errors.New(errors.ErrCodeInvalidParameter, "at least one strategy is required")
+	}
+
+	names := make(map[string]bool, len(strategies))
+	loaded := make([]*loadedStrategy, 0, len(strategies))
+
+	for i, strategy := range strategies {
+		if strategy == nil {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "strategy %d is nil", i)
+		}
+
+		name := strategy.Name()
+		if names[name] {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "duplicate strategy name %q", name)
+		}
+
+		names[name] = true
+		loaded = append(loaded, &loadedStrategy{runtime: strategy, context: nil})
+	}
+
+	e.strategyMu.Lock()
+	e.strategies = loaded
+	e.strategyMu.Unlock()
+
+	e.log.Debug("Strategies loaded",
+		zap.Strings("names", e.strategyNames()),
+	)
+
+	return nil
+}
+
+// strategyNames returns the names of the loaded strategies in load order.
+func (e *LiveTradingEngineV1) strategyNames() []string {
+	names := make([]string, 0, len(e.strategies))
+	for _, strategy := range e.strategies {
+		names = append(names, strategy.runtime.Name())
+	}
+
+	return names
+}
+
+// newStrategyContext builds the RuntimeContext of strategy from the shared one.
+// A lone strategy gets the shared context as is. With several strategies each
+// one tags its orders, marks and logs with its name and keeps its own cache.
+func (e *LiveTradingEngineV1) newStrategyContext(strategy runtime.StrategyRuntime, shared runtime.RuntimeContext) *runtime.RuntimeContext {
+	if len(e.strategies) < 2 {
+		return &shared
+	}
+
+	name := strategy.Name()
+	ctx := shared
+	ctx.TradingSystem = &strategyOrderTagger{TradingSystemProvider: shared.TradingSystem, strategyName: name}
+	ctx.Marker = &strategyMarker{inner: shared.Marker, strategyName: name}
+	ctx.LogStorage = &strategyLogStorage{inner: shared.LogStorage, strategyName: name}
+	ctx.Cache = cache.NewCacheV1()
+	ctx.Logger = &logger.Logger{Logger: shared.Logger.With(zap.String(StrategyLogField, name))}
+
+	return &ctx
+}
+
+// strategyOrderTagger sets the strategy name on every order a strategy places
+// when the engine runs several strategies. All other calls pass through.
+type strategyOrderTagger struct {
+	tradingprovider.TradingSystemProvider
+
+	strategyName string
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (p *strategyOrderTagger) PlaceOrder(order types.ExecuteOrder) error {
+	order.StrategyName = p.strategyName
+
+	return p.TradingSystemProvider.PlaceOrder(order)
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+func (p *strategyOrderTagger) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	tagged := make([]types.ExecuteOrder, len(orders))
+	for i, order := range orders {
+		order.StrategyName = p.strategyName
+		tagged[i] = order
+	}
+
+	return p.TradingSystemProvider.PlaceMultipleOrders(tagged)
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+func (p *strategyOrderTagger) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	entry.StrategyName = p.strategyName
+
+	return p.TradingSystemProvider.PlaceBracketOrder(entry, stopLoss, takeProfit)
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+func (p *strategyOrderTagger) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	entry.StrategyName = p.strategyName
+	takeProfit.StrategyName = p.strategyName
+	stopLoss.StrategyName = p.strategyName
+
+	return p.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+}
+
+// strategyMarker records a strategy's marks in the shared marker tagged with
+// the strategy name, and reads back only that strategy's marks.
+type strategyMarker struct {
+	inner        marker.Marker
+	strategyName string
+}
+
+// Mark implements marker.Marker.
+func (m *strategyMarker) Mark(marketData types.MarketData, mark types.Mark) error {
+	mark.StrategyName = m.strategyName
+
+	return m.inner.Mark(marketData, mark)
+}
+
+// GetMarks implements marker.Marker.
+func (m *strategyMarker) GetMarks() ([]types.Mark, error) {
+	marks, err := m.inner.GetMarks()
+	if err != nil {
+		return nil, err
+	}
+
+	own := make([]types.Mark, 0, len(marks))
+
+	for _, mark := range marks {
+		if mark.StrategyName == m.strategyName {
+			own = append(own, mark)
+		}
+	}
+
+	return own, nil
+}
+
+// strategyLogStorage records a strategy's logs in the shared log storage with
+// the strategy name in the StrategyLogField field, and reads back only that
+// strategy's logs.
+type strategyLogStorage struct {
+	inner        internalLog.Log
+	strategyName string
+}
+
+// Log implements log.Log.
+func (l *strategyLogStorage) Log(entry internalLog.LogEntry) error {
+	fields := make(map[string]string, len(entry.Fields)+1)
+	maps.Copy(fields, entry.Fields)
+	fields[StrategyLogField] = l.strategyName
+	entry.Fields = fields
+
+	return l.inner.Log(entry)
+}
+
+// GetLogs implements log.Log.
+func (l *strategyLogStorage) GetLogs() ([]internalLog.LogEntry, error) {
+	logs, err := l.inner.GetLogs()
+	if err != nil {
+		return nil, err
+	}
+
+	own := make([]internalLog.LogEntry, 0, len(logs))
+
+	for _, entry := range logs {
+		if entry.Fields[StrategyLogField] == l.strategyName {
+			own = append(own, entry)
+		}
+	}
+
+	return own, nil
+}
@@ -0,0 +1,105 @@
+package engine_v1
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// OrderFailure is an order of a PlaceMultipleOrders call that could not be placed.
+type OrderFailure struct {
+	// Index is the position of the order in the call.
+	Index int
+	Order types.ExecuteOrder
+	Err   error
+}
+
+// OrderBatchError reports the orders of a concurrent PlaceMultipleOrders call
+// that failed. The other orders of the call were placed.
+type OrderBatchError struct {
+	// Total is the number of orders in the call.
+	Total int
+	// Failures holds the failed orders in the order of the call.
+	Failures []OrderFailure
+}
+
+// Error implements error.
+func (e *OrderBatchError) Error() string {
+	messages := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		messages[i] = fmt.Sprintf("order %d (%s %s): %v", failure.Index, failure.Order.Side, failure.Order.Symbol, failure.Err)
+	}
+
+	return fmt.Sprintf("%d of %d orders failed: %s", len(e.Failures), e.Total, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the failed orders, so errors.Is and errors.As
+// match any of them.
+func (e *OrderBatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+
+	return errs
+}
+
+// concurrentOrderPlacer wraps the trading provider handed to the strategy and
+// places the orders of a PlaceMultipleOrders call in parallel, at most limit at
+// a time. Each order goes through PlaceOrder of the wrapped provider, so the
+// provider's rate limiter and every engine wrapper still apply per order. A
+// failed order does not stop the others; the failures are returned together as
+// an *OrderBatchError. All other calls pass through.
+type concurrentOrderPlacer struct {
+	tradingprovider.TradingSystemProvider
+
+	limit int
+}
+
+// newConcurrentOrderPlacer wraps inner, placing up to limit orders at a time.
+func newConcurrentOrderPlacer(inner tradingprovider.TradingSystemProvider, limit int) *concurrentOrderPlacer {
+	return &concurrentOrderPlacer{
+		TradingSystemProvider: inner,
+		limit:                 limit,
+	}
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+func (p *concurrentOrderPlacer) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	errs := make([]error, len(orders))
+	slots := make(chan struct{}, p.limit)
+
+	var wg sync.WaitGroup
+
+	for i, order := range orders {
+		slots <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			errs[i] = p.TradingSystemProvider.PlaceOrder(order)
+		}()
+	}
+
+	wg.Wait()
+
+	var failures []OrderFailure
+
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, OrderFailure{Index: i, Order: orders[i], Err: err})
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return &OrderBatchError{Total: len(orders), Failures: failures}
+}
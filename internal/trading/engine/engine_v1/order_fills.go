@@ -0,0 +1,440 @@
+package engine_v1
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/clock"
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"go.uber.org/zap"
+)
+
+// orderFillWatcher wraps the trading provider handed to the strategy and
+// remembers the orders the strategy placed until the provider reports them
+// filled, so the engine can tell the host about each execution once.
+// All other calls pass through.
+type orderFillWatcher struct {
+	tradingprovider.TradingSystemProvider
+
+	log   *logger.Logger
+	clock clock.Clock
+
+	mu     sync.Mutex
+	orders []types.ExecuteOrder
+	// fees sums the fees of the partial fills pushed for each watched order.
+	fees map[string]float64
+}
+
+// newOrderFillWatcher wraps inner with no watched orders.
+func newOrderFillWatcher(inner tradingprovider.TradingSystemProvider, log *logger.Logger, clock clock.Clock) *orderFillWatcher {
+	return &orderFillWatcher{
+		TradingSystemProvider: inner,
+		log:                   log,
+		clock:                 clock,
+		mu:                    sync.Mutex{},
+		orders:                nil,
+		fees:                  map[string]float64{},
+	}
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (w *orderFillWatcher) PlaceOrder(order types.ExecuteOrder) error {
+	if err := w.TradingSystemProvider.PlaceOrder(order); err != nil {
+		return err
+	}
+
+	w.watch(order)
+
+	return nil
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+// Orders are placed one by one so that a partial failure still watches the
+// orders that reached the exchange.
+func (w *orderFillWatcher) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	for _, order := range orders {
+		if err := w.PlaceOrder(order); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+func (w *orderFillWatcher) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	groupID, err := w.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+	if err != nil {
+		return "", err
+	}
+
+	w.watch(entry)
+	w.watch(takeProfit)
+	w.watch(stopLoss)
+
+	return groupID, nil
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+// The bracket is placed through PlaceOCOOrder so its legs are watched too.
+func (w *orderFillWatcher) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	return tradingprovider.PlaceBracketOrderAsOCO(w, entry, stopLoss, takeProfit)
+}
+
+// CancelOrder implements tradingprovider.TradingSystemProvider.
+func (w *orderFillWatcher) CancelOrder(orderID string) error {
+	if err := w.TradingSystemProvider.CancelOrder(orderID); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i, order := range w.orders {
+		if order.ID == orderID {
+			w.orders = append(w.orders[:i], w.orders[i+1:]...)
+			delete(w.fees, orderID)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// CancelAllOrders implements tradingprovider.TradingSystemProvider.
+func (w *orderFillWatcher) CancelAllOrders() error {
+	if err := w.TradingSystemProvider.CancelAllOrders(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.orders = nil
+	w.fees = map[string]float64{}
+
+	return nil
+}
+
+// CancelOrdersForSymbol implements tradingprovider.TradingSystemProvider.
+func (w *orderFillWatcher) CancelOrdersForSymbol(symbol string) error {
+	if err := w.TradingSystemProvider.CancelOrdersForSymbol(symbol); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.orders = slices.DeleteFunc(w.orders, func(order types.ExecuteOrder) bool {
+		if order.Symbol != symbol {
+			return false
+		}
+
+		delete(w.fees, order.ID)
+
+		return true
+	})
+
+	return nil
+}
+
+// WatchedOrders returns a copy of the orders waiting for a fill, oldest first.
+func (w *orderFillWatcher) WatchedOrders() []types.ExecuteOrder {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]types.ExecuteOrder(nil), w.orders...)
+}
+
+// watch records an order that was accepted by the provider.
+func (w *orderFillWatcher) watch(order types.ExecuteOrder) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.orders = append(w.orders, order)
+}
+
+// CheckFills asks the provider for the status of every watched order and
+// returns a trade for each one that has filled. Filled, cancelled, rejected and
+// failed orders stop being watched; pending and partially filled ones stay.
+// Orders whose status cannot be read are kept and checked again next time.
+func (w *orderFillWatcher) CheckFills() []types.Trade {
+	var fills []types.Trade
+
+	closed := map[string]bool{}
+
+	for _, order := range w.WatchedOrders() {
+		status, err := w.TradingSystemProvider.GetOrderStatus(order.ID)
+		if err != nil {
+			w.log.Warn("Failed to get order status",
+				zap.String("order_id", order.ID),
+				zap.Error(err),
+			)
+
+			continue
+		}
+
+		switch status {
+		case types.OrderStatusPending, types.OrderStatusPartiallyFilled:
+			continue
+		case types.OrderStatusFilled:
+			fills = append(fills, w.filledTrade(order))
+		case types.OrderStatusCancelled, types.OrderStatusRejected, types.OrderStatusFailed:
+			w.log.Debug("Order closed without a fill",
+				zap.String("order_id", order.ID),
+				zap.String("status", string(status)),
+			)
+		}
+
+		closed[order.ID] = true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Orders placed or cancelled while the statuses were read are left as they are
+	open := make([]types.ExecuteOrder, 0, len(w.orders))
+
+	for _, order := range w.orders {
+		if !closed[order.ID] {
+			open = append(open, order)
+		} else {
+			delete(w.fees, order.ID)
+		}
+	}
+
+	w.orders = open
+
+	return fills
+}
+
+// filledTrade returns the provider's trade for a filled order, tagged with the
+// strategy that placed it. When the order filled in several trades they are
+// combined into one at the average price. Providers that do not report the
+// trade yet get one built from the order.
+func (w *orderFillWatcher) filledTrade(order types.ExecuteOrder) types.Trade {
+	trades, err := w.TradingSystemProvider.GetTrades(types.TradeFilter{
+		Symbol:    order.Symbol,
+		StartTime: time.Time{},
+		EndTime:   time.Time{},
+		Limit:     0,
+	})
+	if err != nil {
+		w.log.Warn("Failed to get trades of filled order",
+			zap.String("order_id", order.ID),
+			zap.Error(err),
+		)
+	}
+
+	var fill types.Trade
+
+	found := false
+
+	for _, trade := range trades {
+		if trade.Order.OrderID != order.ID {
+			continue
+		}
+
+		if !found {
+			fill = trade
+			found = true
+
+			continue
+		}
+
+		quantity := fill.ExecutedQty + trade.ExecutedQty
+		if quantity > 0 {
+			fill.ExecutedPrice = (fill.ExecutedPrice*fill.ExecutedQty + trade.ExecutedPrice*trade.ExecutedQty) / quantity
+		}
+
+		fill.ExecutedQty = quantity
+		fill.Fee += trade.Fee
+		fill.PnL += trade.PnL
+
+		if trade.ExecutedAt.After(fill.ExecutedAt) {
+			fill.ExecutedAt = trade.ExecutedAt
+		}
+	}
+
+	if found {
+		fill.Order.StrategyName = order.StrategyName
+
+		return fill
+	}
+
+	//nolint:exhaustruct // the provider reported no trade details for this fill
+	return types.Trade{
+		Order: types.Order{
+			OrderID:      order.ID,
+			Symbol:       order.Symbol,
+			Side:         order.Side,
+			Quantity:     order.Quantity,
+			Price:        order.Price,
+			Timestamp:    w.clock.Now(),
+			IsCompleted:  true,
+			Status:       types.OrderStatusFilled,
+			Reason:       order.Reason,
+			StrategyName: order.StrategyName,
+			Fee:          0,
+			PositionType: order.PositionType,
+		},
+		ExecutedAt:    w.clock.Now(),
+		ExecutedQty:   order.Quantity,
+		ExecutedPrice: order.Price,
+	}
+}
+
+// ApplyUpdates matches order updates pushed by the provider to the watched
+// orders and returns a trade for each one that filled. Closed orders stop being
+// watched like in CheckFills. An update is matched by order ID, or by the
+// order's parameters when the provider assigned an ID of its own.
+func (w *orderFillWatcher) ApplyUpdates(updates []types.OrderUpdate) []types.Trade {
+	var fills []types.Trade
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, update := range updates {
+		index := w.findOrder(update)
+		if index < 0 {
+			continue
+		}
+
+		order := w.orders[index]
+		if update.LastFillQuantity > 0 {
+			w.fees[order.ID] += update.Fee
+		}
+
+		switch update.Status {
+		case types.OrderStatusPending, types.OrderStatusPartiallyFilled:
+			continue
+		case types.OrderStatusFilled:
+			fills = append(fills, w.tradeFromUpdate(order, update))
+		case types.OrderStatusCancelled, types.OrderStatusRejected, types.OrderStatusFailed:
+			w.log.Debug("Order closed without a fill",
+				zap.String("order_id", order.ID),
+				zap.String("status", string(update.Status)),
+			)
+		}
+
+		w.orders = append(w.orders[:index], w.orders[index+1:]...)
+		delete(w.fees, order.ID)
+	}
+
+	return fills
+}
+
+// findOrder returns the index of the watched order update belongs to, or -1.
+// The caller must hold w.mu.
+func (w *orderFillWatcher) findOrder(update types.OrderUpdate) int {
+	for i, order := range w.orders {
+		if order.ID == update.OrderID || order.ID == update.ClientOrderID {
+			return i
+		}
+	}
+
+	//nolint:exhaustruct // only the fields compared by FindMatchingOpenOrder are needed
+	placed := types.ExecuteOrder{
+		ID:        update.OrderID,
+		Symbol:    update.Symbol,
+		Side:      update.Side,
+		OrderType: update.OrderType,
+		Price:     update.Price,
+		Quantity:  update.Quantity,
+		StopPrice: update.StopPrice,
+	}
+
+	return tradingprovider.FindMatchingOpenOrder(placed, w.orders, make([]bool, len(w.orders)))
+}
+
+// tradeFromUpdate builds the trade of an order from the update that filled it.
+// The caller must hold w.mu.
+func (w *orderFillWatcher) tradeFromUpdate(order types.ExecuteOrder, update types.OrderUpdate) types.Trade {
+	fee := w.fees[order.ID]
+
+	//nolint:exhaustruct // PnL and position fields are not known from an order update
+	return types.Trade{
+		Order: types.Order{
+			OrderID:      order.ID,
+			Symbol:       order.Symbol,
+			Side:         order.Side,
+			Quantity:     update.FilledQuantity,
+			Price:        update.AverageFillPrice,
+			Timestamp:    update.Time,
+			IsCompleted:  true,
+			Status:       types.OrderStatusFilled,
+			Reason:       order.Reason,
+			StrategyName: order.StrategyName,
+			Fee:          fee,
+			PositionType: order.PositionType,
+		},
+		ExecutedAt:    update.Time,
+		ExecutedQty:   update.FilledQuantity,
+		ExecutedPrice: update.AverageFillPrice,
+		Fee:           fee,
+	}
+}
+
+// orderUpdateFeed collects the order updates a streaming provider pushes in the
+// background, so the engine can apply them between ticks.
+type orderUpdateFeed struct {
+	mu      sync.Mutex
+	updates []types.OrderUpdate
+	// stale is set when the stream reported an error or ended, since updates
+	// may have been missed.
+	stale bool
+	ended bool
+}
+
+// startOrderUpdateFeed consumes the updates of streamer until ctx is cancelled
+// or the stream ends.
+func startOrderUpdateFeed(ctx context.Context, streamer tradingprovider.OrderUpdateStreamer, log *logger.Logger) *orderUpdateFeed {
+	feed := &orderUpdateFeed{
+		mu:      sync.Mutex{},
+		updates: nil,
+		stale:   false,
+		ended:   false,
+	}
+
+	go func() {
+		for update, err := range streamer.StreamOrderUpdates(ctx) {
+			feed.mu.Lock()
+			if err != nil {
+				feed.stale = true
+			} else {
+				feed.updates = append(feed.updates, update)
+			}
+			feed.mu.Unlock()
+
+			if err != nil {
+				log.Warn("Order update stream error", zap.Error(err))
+			}
+		}
+
+		feed.mu.Lock()
+		feed.stale = true
+		feed.ended = true
+		feed.mu.Unlock()
+	}()
+
+	return feed
+}
+
+// Drain returns the updates received since the previous call, and whether
+// updates may have been missed in that time. Once the stream has ended every
+// call reports missed updates.
+func (f *orderUpdateFeed) Drain() (updates []types.OrderUpdate, missed bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	updates, missed = f.updates, f.stale
+	f.updates = nil
+	f.stale = f.ended
+
+	return updates, missed
+}
@@ -0,0 +1,103 @@
+package engine_v1
+
+import (
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// orderRejectionReporter wraps the trading provider handed to the strategy and
+// reports the orders the provider rejects to OnOrderRejected. The rejection is
+// still returned to the strategy. All other calls pass through.
+type orderRejectionReporter struct {
+	tradingprovider.TradingSystemProvider
+
+	log        *logger.Logger
+	onRejected func(order types.ExecuteOrder, reason string) error
+}
+
+// newOrderRejectionReporter wraps inner, calling onRejected for each rejected order.
+func newOrderRejectionReporter(
+	inner tradingprovider.TradingSystemProvider,
+	log *logger.Logger,
+	onRejected func(order types.ExecuteOrder, reason string) error,
+) *orderRejectionReporter {
+	return &orderRejectionReporter{
+		TradingSystemProvider: inner,
+		log:                   log,
+		onRejected:            onRejected,
+	}
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (r *orderRejectionReporter) PlaceOrder(order types.ExecuteOrder) error {
+	err := r.TradingSystemProvider.PlaceOrder(order)
+	r.report(order, err)
+
+	return err
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+// Orders are placed one by one so a rejection is reported with the order the
+// provider refused.
+func (r *orderRejectionReporter) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	for _, order := range orders {
+		if err := r.PlaceOrder(order); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+// A rejected group is reported with its entry order.
+func (r *orderRejectionReporter) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	groupID, err := r.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+	r.report(entry, err)
+
+	return groupID, err
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+// A rejected bracket is reported with its entry order.
+func (r *orderRejectionReporter) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	err := r.TradingSystemProvider.PlaceBracketOrder(entry, stopLoss, takeProfit)
+	r.report(entry, err)
+
+	return err
+}
+
+// report calls onRejected when err is a rejection by the provider. Orders the
+// engine refused itself, such as those over the daily limits, are not reported.
+func (r *orderRejectionReporter) report(order types.ExecuteOrder, err error) {
+	reason, ok := rejectionReason(err)
+	if !ok {
+		return
+	}
+
+	r.log.Warn("Order rejected by the trading provider",
+		zap.String("order_id", order.ID),
+		zap.String("symbol", order.Symbol),
+		zap.String("reason", reason),
+	)
+
+	if err := r.onRejected(order, reason); err != nil {
+		r.log.Warn("OnOrderRejected callback failed",
+			zap.String("order_id", order.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// rejectionReason returns the provider's reason when err rejects an order.
+func rejectionReason(err error) (string, bool) {
+	var rejection *errors.Error
+	if !errors.As(err, &rejection) || rejection.Code != errors.ErrCodeOrderRejected {
+		return "", false
+	}
+
+	return rejection.Message, true
+}
@@ -0,0 +1,151 @@
+package engine_v1
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/rxtech-lab/argo-trading/internal/clock"
+	internalLog "github.com/rxtech-lab/argo-trading/internal/log"
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"go.uber.org/zap"
+)
+
+// SuppressedOrderLogMessage is the strategy log message recorded for every order
+// the strategy attempts to place while order placement is suppressed.
+const SuppressedOrderLogMessage = "order suppressed during warmup"
+
+// orderSuppressingProvider wraps the trading provider handed to the strategy.
+// While suppression is active (strategy initialization, prefetch and gap fill),
+// order placement is logged and dropped instead of reaching the broker, so the
+// strategy cannot trade on an incomplete history. All other calls pass through.
+type orderSuppressingProvider struct {
+	tradingprovider.TradingSystemProvider
+
+	log        *logger.Logger
+	logStorage internalLog.Log
+	clock      clock.Clock
+
+	mu     sync.RWMutex
+	status types.EngineStatus
+	active bool
+}
+
+// newOrderSuppressingProvider wraps inner and starts with suppression active.
+func newOrderSuppressingProvider(inner tradingprovider.TradingSystemProvider, log *logger.Logger, logStorage internalLog.Log, clock clock.Clock) *orderSuppressingProvider {
+	return &orderSuppressingProvider{
+		TradingSystemProvider: inner,
+		log:                   log,
+		logStorage:            logStorage,
+		clock:                 clock,
+		mu:                    sync.RWMutex{},
+		status:                types.EngineStatusPrefetching,
+		active:                true,
+	}
+}
+
+// SetStatus records the engine status. Suppression ends once the engine is running.
+func (p *orderSuppressingProvider) SetStatus(status types.EngineStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status = status
+	if status == types.EngineStatusRunning {
+		p.active = false
+	}
+}
+
+// Suppressing reports whether order placement is currently suppressed.
+func (p *orderSuppressingProvider) Suppressing() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.active
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (p *orderSuppressingProvider) PlaceOrder(order types.ExecuteOrder) error {
+	if p.Suppressing() {
+		p.logSuppressed(order)
+
+		return nil
+	}
+
+	return p.TradingSystemProvider.PlaceOrder(order)
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+func (p *orderSuppressingProvider) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	if p.Suppressing() {
+		for _, order := range orders {
+			p.logSuppressed(order)
+		}
+
+		return nil
+	}
+
+	return p.TradingSystemProvider.PlaceMultipleOrders(orders)
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+// While suppressing, the whole group is dropped and logged under its entry order.
+func (p *orderSuppressingProvider) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	if p.Suppressing() {
+		p.logSuppressed(entry)
+
+		return "", nil
+	}
+
+	return p.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+// While suppressing, the bracket is dropped and logged under its entry order.
+func (p *orderSuppressingProvider) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	if p.Suppressing() {
+		p.logSuppressed(entry)
+
+		return nil
+	}
+
+	return p.TradingSystemProvider.PlaceBracketOrder(entry, stopLoss, takeProfit)
+}
+
+// logSuppressed records a suppressed order in the engine log and, when strategy
+// log storage is enabled, in the strategy logs so it is persisted with the session.
+func (p *orderSuppressingProvider) logSuppressed(order types.ExecuteOrder) {
+	p.mu.RLock()
+	status := p.status
+	p.mu.RUnlock()
+
+	p.log.Warn(SuppressedOrderLogMessage,
+		zap.String("status", string(status)),
+		zap.String("symbol", order.Symbol),
+		zap.String("side", string(order.Side)),
+		zap.String("order_type", string(order.OrderType)),
+		zap.Float64("price", order.Price),
+		zap.Float64("quantity", order.Quantity),
+	)
+
+	if p.logStorage == nil {
+		return
+	}
+
+	if err := p.logStorage.Log(internalLog.LogEntry{
+		Timestamp: p.clock.Now(),
+		Symbol:    order.Symbol,
+		Level:     types.LogLevelWarning,
+		Message:   SuppressedOrderLogMessage,
+		Fields: map[string]string{
+			"status":     string(status),
+			"side":       string(order.Side),
+			"order_type": string(order.OrderType),
+			"price":      strconv.FormatFloat(order.Price, 'f', -1, 64),
+			"quantity":   strconv.FormatFloat(order.Quantity, 'f', -1, 64),
+			"reason":     order.Reason.Reason,
+		},
+	}); err != nil {
+		p.log.Warn("Failed to store suppressed order log", zap.Error(err))
+	}
+}
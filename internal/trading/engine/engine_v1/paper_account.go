@@ -0,0 +1,596 @@
+package engine_v1
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// DefaultBaseCurrency is the paper account currency used when
+// LiveTradingEngineConfig.BaseCurrency is empty.
+const DefaultBaseCurrency = "USDT"
+
+// paperFundsTolerance absorbs float rounding when an order spends the whole balance.
+const paperFundsTolerance = 1e-9
+
+// latestBarFunc returns the most recent bar cached for a symbol.
+type latestBarFunc func(symbol string) (types.MarketData, bool)
+
+// paperPosition is a long holding of the paper account.
+type paperPosition struct {
+	quantity float64
+	// cost is what the held quantity cost including the buy fees
+	cost float64
+	// entryCost is what the held quantity cost excluding fees
+	entryCost     float64
+	fees          float64
+	openTimestamp time.Time
+	strategyName  string
+}
+
+// paperAccount is a simulated spot account that replaces the exchange account
+// when LiveTradingEngineConfig.InitialBalance is set. It starts with the
+// initial balance in cash and fills orders locally against the latest bars in
+// the market data cache: market orders at the last close, limit orders at the
+// last close when marketable and at their limit price once a later bar trades
+// through it. Only long positions are supported. Calls it does not simulate,
+// such as SetOnStatusChange, go to the wrapped provider.
+type paperAccount struct {
+	tradingprovider.TradingSystemProvider
+
+	baseCurrency string
+	feeRate      float64
+	latestBar    latestBarFunc
+
+	mu          sync.Mutex
+	cash        float64
+	realizedPnL float64
+	totalFees   float64
+	positions   map[string]*paperPosition
+	openOrders  []types.ExecuteOrder
+	orderStatus map[string]types.OrderStatus
+	trades      []types.Trade
+}
+
+// newPaperAccount creates a paper account holding initialBalance in baseCurrency.
+func newPaperAccount(inner tradingprovider.TradingSystemProvider, initialBalance float64, baseCurrency string, feeRate float64, latestBar latestBarFunc) *paperAccount {
+	return &paperAccount{
+		TradingSystemProvider: inner,
+		baseCurrency:          baseCurrency,
+		feeRate:               feeRate,
+		latestBar:             latestBar,
+		mu:                    sync.Mutex{},
+		cash:                  initialBalance,
+		realizedPnL:           0,
+		totalFees:             0,
+		positions:             make(map[string]*paperPosition),
+		openOrders:            []types.ExecuteOrder{},
+		orderStatus:           make(map[string]types.OrderStatus),
+		trades:                []types.Trade{},
+	}
+}
+
+// OnMarketData fills the resting limit orders of the bar's symbol that the bar traded through.
+func (p *paperAccount) OnMarketData(data types.MarketData) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	remaining := p.openOrders[:0]
+
+	for _, order := range p.openOrders {
+		if order.Symbol != data.Symbol || !limitReached(order, data) {
+			remaining = append(remaining, order)
+
+			continue
+		}
+
+		if err := p.fill(order, order.Price, data.Time); err != nil {
+			p.orderStatus[order.ID] = types.OrderStatusFailed
+		}
+	}
+
+	p.openOrders = remaining
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) PlaceOrder(order types.ExecuteOrder) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.placeOrder(order)
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, order := range orders {
+		if err := p.placeOrder(order); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) PlaceOCOOrder(_, _, _ types.ExecuteOrder) (string, error) {
+	return "", errors.New(errors.ErrCodeInvalidOrder, "OCO orders are not supported by the paper account")
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) PlaceBracketOrder(_ types.ExecuteOrder, _, _ float64) error {
+	return errors.New(errors.ErrCodeInvalidOrder, "bracket orders are not supported by the paper account")
+}
+
+func (p *paperAccount) placeOrder(order types.ExecuteOrder) error {
+	if order.Quantity <= 0 {
+		return errors.Newf(errors.ErrCodeInvalidOrder, "order quantity must be greater than zero: %f", order.Quantity)
+	}
+
+	if order.PositionType == types.PositionTypeShort {
+		return errors.New(errors.ErrCodeInvalidOrder, "short positions are not supported by the paper account")
+	}
+
+	if order.OrderType != types.OrderTypeMarket && order.OrderType != types.OrderTypeLimit {
+		return errors.Newf(errors.ErrCodeInvalidOrder, "order type %s is not supported by the paper account", order.OrderType)
+	}
+
+	bar, ok := p.latestBar(order.Symbol)
+	if !ok {
+		return errors.Newf(errors.ErrCodeMarketDataMissing, "no market price for %s yet", order.Symbol)
+	}
+
+	if order.ID == "" {
+		order.ID = uuid.New().String()
+	}
+
+	marketable := order.OrderType == types.OrderTypeMarket ||
+		(order.Side == types.PurchaseTypeBuy && order.Price >= bar.Close) ||
+		(order.Side == types.PurchaseTypeSell && order.Price <= bar.Close)
+
+	if !marketable {
+		p.openOrders = append(p.openOrders, order)
+		p.orderStatus[order.ID] = types.OrderStatusPending
+
+		return nil
+	}
+
+	if err := p.fill(order, bar.Close, bar.Time); err != nil {
+		p.orderStatus[order.ID] = types.OrderStatusFailed
+
+		return err
+	}
+
+	return nil
+}
+
+// fill executes order at price, moving cash and the position and recording the trade.
+func (p *paperAccount) fill(order types.ExecuteOrder, price float64, executedAt time.Time) error {
+	notional := order.Quantity * price
+	fee := notional * p.feeRate
+
+	position, ok := p.positions[order.Symbol]
+	if !ok {
+		position = &paperPosition{quantity: 0, cost: 0, entryCost: 0, fees: 0, openTimestamp: executedAt, strategyName: order.StrategyName}
+	}
+
+	var pnl, averageCost float64
+
+	switch order.Side {
+	case types.PurchaseTypeBuy:
+		if notional+fee > p.cash+paperFundsTolerance {
+			return errors.Newf(errors.ErrCodeOrderFailed, "insufficient %s balance: %f needed, %f available", p.baseCurrency, notional+fee, p.cash)
+		}
+
+		if position.quantity == 0 {
+			position.openTimestamp = executedAt
+			position.strategyName = order.StrategyName
+		}
+
+		p.cash -= notional + fee
+		position.quantity += order.Quantity
+		position.cost += notional + fee
+		position.entryCost += notional
+		position.fees += fee
+		averageCost = position.cost / position.quantity
+	case types.PurchaseTypeSell:
+		if order.Quantity > position.quantity+paperFundsTolerance {
+			return errors.Newf(errors.ErrCodeOrderFailed, "insufficient %s position: %f held, %f to sell", order.Symbol, position.quantity, order.Quantity)
+		}
+
+		averageCost = position.cost / position.quantity
+		pnl = notional - fee - averageCost*order.Quantity
+
+		p.cash += notional - fee
+		p.realizedPnL += pnl
+		position.entryCost -= position.entryCost / position.quantity * order.Quantity
+		position.quantity -= order.Quantity
+		position.cost -= averageCost * order.Quantity
+
+		if position.quantity <= paperFundsTolerance {
+			position.quantity = 0
+			position.cost = 0
+			position.entryCost = 0
+		}
+	default:
+		return errors.Newf(errors.ErrCodeInvalidOrder, "unknown order side: %s", order.Side)
+	}
+
+	p.totalFees += fee
+	p.positions[order.Symbol] = position
+	p.orderStatus[order.ID] = types.OrderStatusFilled
+
+	p.trades = append(p.trades, types.Trade{
+		Order: types.Order{
+			OrderID:      order.ID,
+			Symbol:       order.Symbol,
+			Side:         order.Side,
+			Quantity:     order.Quantity,
+			Price:        price,
+			Timestamp:    executedAt,
+			IsCompleted:  true,
+			Status:       types.OrderStatusFilled,
+			Reason:       order.Reason,
+			StrategyName: order.StrategyName,
+			Fee:          fee,
+			PositionType: types.PositionTypeLong,
+		},
+		ExecutedAt:      executedAt,
+		ExecutedQty:     order.Quantity,
+		ExecutedPrice:   price,
+		Fee:             fee,
+		PnL:             pnl,
+		CumulativePnL:   0,
+		LIFOPnL:         0,
+		RealizedPnL:     0,
+		CostBasis:       0,
+		OpenPositionQty: position.quantity,
+		Balance:         p.cash,
+		HoldTime:        0,
+		AverageCost:     averageCost,
+	})
+
+	return nil
+}
+
+// limitReached reports whether the bar traded through the limit price of order.
+func limitReached(order types.ExecuteOrder, bar types.MarketData) bool {
+	if order.Side == types.PurchaseTypeBuy {
+		return bar.Low <= order.Price
+	}
+
+	return bar.High >= order.Price
+}
+
+// GetPositions implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) GetPositions() ([]types.Position, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	positions := make([]types.Position, 0, len(p.positions))
+
+	for symbol, position := range p.positions {
+		if position.quantity > 0 {
+			positions = append(positions, position.toPosition(symbol))
+		}
+	}
+
+	return positions, nil
+}
+
+// GetPosition implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) GetPosition(symbol string) (types.Position, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	position, ok := p.positions[symbol]
+	if !ok {
+		position = &paperPosition{quantity: 0, cost: 0, entryCost: 0, fees: 0, openTimestamp: time.Time{}, strategyName: ""}
+	}
+
+	return position.toPosition(symbol), nil
+}
+
+func (pos *paperPosition) toPosition(symbol string) types.Position {
+	averageEntryPrice := 0.0
+	if pos.quantity > 0 {
+		averageEntryPrice = pos.entryCost / pos.quantity
+	}
+
+	return types.Position{
+		Symbol:                        symbol,
+		TotalLongPositionQuantity:     pos.quantity,
+		TotalShortPositionQuantity:    0,
+		TotalLongInPositionQuantity:   pos.quantity,
+		TotalLongOutPositionQuantity:  0,
+		TotalLongInPositionAmount:     pos.cost - pos.fees,
+		TotalLongOutPositionAmount:    0,
+		TotalShortInPositionQuantity:  0,
+		TotalShortOutPositionQuantity: 0,
+		TotalShortInPositionAmount:    0,
+		TotalShortOutPositionAmount:   0,
+		TotalLongInFee:                pos.fees,
+		TotalLongOutFee:               0,
+		TotalShortInFee:               0,
+		TotalShortOutFee:              0,
+		AverageLongEntryPrice:         averageEntryPrice,
+		TotalLongCost:                 pos.entryCost,
+		AverageShortEntryPrice:        0,
+		TotalShortCost:                0,
+		OpenTimestamp:                 pos.openTimestamp,
+		StrategyName:                  pos.strategyName,
+	}
+}
+
+// CancelOrder implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) CancelOrder(orderID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, order := range p.openOrders {
+		if order.ID == orderID {
+			p.openOrders = append(p.openOrders[:i], p.openOrders[i+1:]...)
+			p.orderStatus[orderID] = types.OrderStatusCancelled
+
+			return nil
+		}
+	}
+
+	return errors.Newf(errors.ErrCodeOrderFailed, "order %s is not open", orderID)
+}
+
+// CancelAllOrders implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) CancelAllOrders() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, order := range p.openOrders {
+		p.orderStatus[order.ID] = types.OrderStatusCancelled
+	}
+
+	p.openOrders = []types.ExecuteOrder{}
+
+	return nil
+}
+
+// CancelOrdersForSymbol implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) CancelOrdersForSymbol(symbol string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.openOrders = slices.DeleteFunc(p.openOrders, func(order types.ExecuteOrder) bool {
+		if order.Symbol != symbol {
+			return false
+		}
+
+		p.orderStatus[order.ID] = types.OrderStatusCancelled
+
+		return true
+	})
+
+	return nil
+}
+
+// GetOrderStatus implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) GetOrderStatus(orderID string) (types.OrderStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status, ok := p.orderStatus[orderID]
+	if !ok {
+		return "", errors.Newf(errors.ErrCodeOrderFailed, "order %s not found", orderID)
+	}
+
+	return status, nil
+}
+
+// GetAccountInfo implements tradingprovider.TradingSystemProvider.
+// Holdings are valued in the base currency at the close of their latest cached
+// bar, or at their cost when no bar has been cached yet.
+func (p *paperAccount) GetAccountInfo() (types.AccountInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	holdings := 0.0
+	unrealizedPnL := 0.0
+
+	for symbol, position := range p.positions {
+		if position.quantity == 0 {
+			continue
+		}
+
+		value := position.cost
+		if bar, ok := p.latestBar(symbol); ok {
+			value = position.quantity * bar.Close
+		}
+
+		holdings += value
+		unrealizedPnL += value - position.cost
+	}
+
+	return types.AccountInfo{
+		Balance:       p.cash,
+		Equity:        p.cash + holdings,
+		BuyingPower:   p.cash,
+		RealizedPnL:   p.realizedPnL,
+		UnrealizedPnL: unrealizedPnL,
+		TotalFees:     p.totalFees,
+		MarginUsed:    0,
+		FreeMargin:    0,
+		Balances:      nil,
+	}, nil
+}
+
+// GetAssets implements tradingprovider.TradingSystemProvider.
+// The cash is reported as the base currency and each holding as the asset its
+// symbol trades against the base currency, e.g. BTC for BTCUSDT.
+func (p *paperAccount) GetAssets() ([]types.Asset, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	assets := []types.Asset{}
+
+	if p.cash > 0 {
+		assets = append(assets, types.Asset{Symbol: p.baseCurrency, Quantity: p.cash, BaseCurrency: "", BaseCurrencyValue: nil})
+	}
+
+	for symbol, position := range p.positions {
+		if position.quantity == 0 {
+			continue
+		}
+
+		asset := symbol
+		if base, ok := strings.CutSuffix(symbol, p.baseCurrency); ok && base != "" {
+			asset = base
+		}
+
+		assets = append(assets, types.Asset{Symbol: asset, Quantity: position.quantity, BaseCurrency: "", BaseCurrencyValue: nil})
+	}
+
+	return assets, nil
+}
+
+// GetPrices implements tradingprovider.TradingSystemProvider.
+// Prices come from the market data cache, so only streamed symbols have one.
+// An empty list returns the prices of the held symbols.
+func (p *paperAccount) GetPrices(symbols []string) (map[string]float64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(symbols) == 0 {
+		for symbol, position := range p.positions {
+			if position.quantity > 0 {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+
+	prices := make(map[string]float64, len(symbols))
+
+	for _, symbol := range symbols {
+		if bar, ok := p.latestBar(symbol); ok {
+			prices[symbol] = bar.Close
+		}
+	}
+
+	return prices, nil
+}
+
+// GetOpenOrders implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) GetOpenOrders() ([]types.ExecuteOrder, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	orders := make([]types.ExecuteOrder, len(p.openOrders))
+	copy(orders, p.openOrders)
+
+	return orders, nil
+}
+
+// GetTrades implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) GetTrades(filter types.TradeFilter) ([]types.Trade, error) {
+	page, err := p.GetTradesPage(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Trades, nil
+}
+
+// GetTradesPage implements tradingprovider.TradingSystemProvider.
+// The cursor is the index of the last trade returned.
+func (p *paperAccount) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start := 0
+
+	if filter.Cursor != "" {
+		last, err := strconv.Atoi(filter.Cursor)
+		if err != nil || last < 0 {
+			return types.TradePage{}, errors.Newf(errors.ErrCodeInvalidParameter, "invalid trade cursor %q", filter.Cursor)
+		}
+
+		start = last + 1
+	}
+
+	page := types.TradePage{Trades: []types.Trade{}, NextCursor: ""}
+	lastIndex := start - 1
+
+	for i := start; i < len(p.trades); i++ {
+		trade := p.trades[i]
+
+		if filter.Symbol != "" && trade.Order.Symbol != filter.Symbol {
+			continue
+		}
+
+		if !filter.StartTime.IsZero() && trade.ExecutedAt.Before(filter.StartTime) {
+			continue
+		}
+
+		if !filter.EndTime.IsZero() && trade.ExecutedAt.After(filter.EndTime) {
+			continue
+		}
+
+		if filter.Limit > 0 && len(page.Trades) == filter.Limit {
+			page.NextCursor = strconv.Itoa(lastIndex)
+
+			break
+		}
+
+		page.Trades = append(page.Trades, trade)
+		lastIndex = i
+	}
+
+	return page, nil
+}
+
+// GetMaxBuyQuantity implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) GetMaxBuyQuantity(_ string, price float64) (float64, error) {
+	if price <= 0 {
+		return 0, errors.New(errors.ErrCodeInvalidParameter, "price must be greater than zero")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.cash / (price * (1 + p.feeRate)), nil
+}
+
+// GetMaxSellQuantity implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) GetMaxSellQuantity(symbol string) (float64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if position, ok := p.positions[symbol]; ok {
+		return position.quantity, nil
+	}
+
+	return 0, nil
+}
+
+// CalculatePositionSize implements tradingprovider.TradingSystemProvider.
+func (p *paperAccount) CalculatePositionSize(_ string, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	info, err := p.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	return tradingprovider.RiskPositionSize(info.Equity, entryPrice, stopPrice, riskFraction)
+}
+
+// CheckConnection implements tradingprovider.TradingSystemProvider.
+// The paper account is local, so it is always reachable.
+func (p *paperAccount) CheckConnection(_ context.Context) error {
+	return nil
+}
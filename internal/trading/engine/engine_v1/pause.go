@@ -0,0 +1,105 @@
+package engine_v1
+
+import (
+	"sync/atomic"
+
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"go.uber.org/zap"
+)
+
+// PausedOrderLogMessage is the engine log message recorded for every order the
+// strategy attempts to place while the engine is paused.
+const PausedOrderLogMessage = "order suppressed while paused"
+
+// pauseGate wraps the trading provider handed to the strategy and drops (and
+// logs) the orders it places while the engine is paused. All other calls,
+// including cancellations, pass through.
+type pauseGate struct {
+	tradingprovider.TradingSystemProvider
+
+	paused *atomic.Bool
+	log    *logger.Logger
+}
+
+// newPauseGate wraps inner, dropping orders while paused is set.
+func newPauseGate(inner tradingprovider.TradingSystemProvider, paused *atomic.Bool, log *logger.Logger) *pauseGate {
+	return &pauseGate{
+		TradingSystemProvider: inner,
+		paused:                paused,
+		log:                   log,
+	}
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (g *pauseGate) PlaceOrder(order types.ExecuteOrder) error {
+	if g.paused.Load() {
+		g.logSuppressed(order)
+
+		return nil
+	}
+
+	return g.TradingSystemProvider.PlaceOrder(order)
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+func (g *pauseGate) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	if g.paused.Load() {
+		for _, order := range orders {
+			g.logSuppressed(order)
+		}
+
+		return nil
+	}
+
+	return g.TradingSystemProvider.PlaceMultipleOrders(orders)
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+// While paused, the whole group is dropped and logged under its entry order.
+func (g *pauseGate) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	if g.paused.Load() {
+		g.logSuppressed(entry)
+
+		return "", nil
+	}
+
+	return g.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+// While paused, the bracket is dropped and logged under its entry order.
+func (g *pauseGate) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	if g.paused.Load() {
+		g.logSuppressed(entry)
+
+		return nil
+	}
+
+	return g.TradingSystemProvider.PlaceBracketOrder(entry, stopLoss, takeProfit)
+}
+
+func (g *pauseGate) logSuppressed(order types.ExecuteOrder) {
+	g.log.Warn(PausedOrderLogMessage,
+		zap.String("symbol", order.Symbol),
+		zap.String("side", string(order.Side)),
+		zap.String("order_type", string(order.OrderType)),
+		zap.Float64("price", order.Price),
+		zap.Float64("quantity", order.Quantity),
+	)
+}
+
+// Pause implements engine.LiveTradingEngine.
+func (e *LiveTradingEngineV1) Pause() {
+	if !e.paused.Swap(true) {
+		e.log.Info("Trading paused", zap.String("pause_mode", string(e.config.PauseMode)))
+	}
+}
+
+// Resume implements engine.LiveTradingEngine.
+func (e *LiveTradingEngineV1) Resume() {
+	if e.paused.Swap(false) {
+		e.log.Info("Trading resumed")
+	}
+}
@@ -0,0 +1,251 @@
+package engine_v1
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/rxtech-lab/argo-trading/internal/logger"
+	tradingprovider "github.com/rxtech-lab/argo-trading/internal/trading/provider"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// PendingOrdersFileName is the file under the persistence data directory that
+// holds the strategy's resting orders between engine runs.
+const PendingOrdersFileName = "pending_orders.json"
+
+// pendingOrderTracker wraps the trading provider handed to the strategy and
+// remembers the resting (non-market) orders the strategy placed, so they can be
+// saved when the engine stops and restored when it starts again. The exchange
+// only keeps the order itself; the tracker keeps the strategy's view of it
+// (its order ID, reason, take profit and stop loss). All other calls pass through.
+type pendingOrderTracker struct {
+	tradingprovider.TradingSystemProvider
+
+	log *logger.Logger
+
+	mu     sync.Mutex
+	orders []types.ExecuteOrder
+}
+
+// newPendingOrderTracker wraps inner with an empty pending-order set.
+func newPendingOrderTracker(inner tradingprovider.TradingSystemProvider, log *logger.Logger) *pendingOrderTracker {
+	return &pendingOrderTracker{
+		TradingSystemProvider: inner,
+		log:                   log,
+		mu:                    sync.Mutex{},
+		orders:                nil,
+	}
+}
+
+// PlaceOrder implements tradingprovider.TradingSystemProvider.
+func (t *pendingOrderTracker) PlaceOrder(order types.ExecuteOrder) error {
+	if err := t.TradingSystemProvider.PlaceOrder(order); err != nil {
+		return err
+	}
+
+	t.track(order)
+
+	return nil
+}
+
+// PlaceMultipleOrders implements tradingprovider.TradingSystemProvider.
+// Orders are placed one by one so that a partial failure still tracks the
+// orders that reached the exchange.
+func (t *pendingOrderTracker) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	for _, order := range orders {
+		if err := t.PlaceOrder(order); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlaceOCOOrder implements tradingprovider.TradingSystemProvider.
+// The entry and both legs are tracked; legs of an emulated group that are not
+// placed yet are dropped by the next reconciliation.
+func (t *pendingOrderTracker) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	groupID, err := t.TradingSystemProvider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+	if err != nil {
+		return "", err
+	}
+
+	t.track(entry)
+	t.track(takeProfit)
+	t.track(stopLoss)
+
+	return groupID, nil
+}
+
+// PlaceBracketOrder implements tradingprovider.TradingSystemProvider.
+// The bracket is placed through PlaceOCOOrder so its legs are tracked too.
+func (t *pendingOrderTracker) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	return tradingprovider.PlaceBracketOrderAsOCO(t, entry, stopLoss, takeProfit)
+}
+
+// CancelOrder implements tradingprovider.TradingSystemProvider.
+func (t *pendingOrderTracker) CancelOrder(orderID string) error {
+	if err := t.TradingSystemProvider.CancelOrder(orderID); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, order := range t.orders {
+		if order.ID == orderID {
+			t.orders = append(t.orders[:i], t.orders[i+1:]...)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// CancelAllOrders implements tradingprovider.TradingSystemProvider.
+func (t *pendingOrderTracker) CancelAllOrders() error {
+	if err := t.TradingSystemProvider.CancelAllOrders(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.orders = nil
+
+	return nil
+}
+
+// CancelOrdersForSymbol implements tradingprovider.TradingSystemProvider.
+func (t *pendingOrderTracker) CancelOrdersForSymbol(symbol string) error {
+	if err := t.TradingSystemProvider.CancelOrdersForSymbol(symbol); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.orders = slices.DeleteFunc(t.orders, func(order types.ExecuteOrder) bool {
+		return order.Symbol == symbol
+	})
+
+	return nil
+}
+
+// PendingOrders returns a copy of the tracked orders, oldest first.
+func (t *pendingOrderTracker) PendingOrders() []types.ExecuteOrder {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]types.ExecuteOrder(nil), t.orders...)
+}
+
+// track records an order that was accepted by the provider. Market orders fill
+// immediately and are never pending.
+func (t *pendingOrderTracker) track(order types.ExecuteOrder) {
+	if order.OrderType == types.OrderTypeMarket {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.orders = append(t.orders, order)
+}
+
+// reconcile keeps the tracked orders that are still open on the exchange and
+// drops the ones that filled or were cancelled. Each exchange order accounts for
+// at most one tracked order, so an order is never counted twice.
+func (t *pendingOrderTracker) reconcile(orders []types.ExecuteOrder) error {
+	openOrders, err := t.TradingSystemProvider.GetOpenOrders()
+	if err != nil {
+		return err
+	}
+
+	matched := make([]bool, len(openOrders))
+	open := make([]types.ExecuteOrder, 0, len(orders))
+
+	for _, order := range orders {
+		index := tradingprovider.FindMatchingOpenOrder(order, openOrders, matched)
+		if index < 0 {
+			t.log.Info("Pending order is no longer open on the exchange",
+				zap.String("order_id", order.ID),
+				zap.String("symbol", order.Symbol),
+			)
+
+			continue
+		}
+
+		matched[index] = true
+		open = append(open, order)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.orders = open
+
+	return nil
+}
+
+// Save reconciles the tracked orders with the exchange and writes the ones
+// still open to path. The file is removed when no order is pending.
+// If the exchange cannot be reached, every tracked order is saved.
+func (t *pendingOrderTracker) Save(path string) error {
+	if len(t.PendingOrders()) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(errors.ErrCodeBacktestDataPathError, "failed to remove pending orders file", err)
+		}
+
+		return nil
+	}
+
+	if err := t.reconcile(t.PendingOrders()); err != nil {
+		t.log.Warn("Failed to reconcile pending orders before saving, saving all", zap.Error(err))
+	}
+
+	data, err := json.MarshalIndent(t.PendingOrders(), "", "  ")
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeBacktestDataPathError, "failed to encode pending orders", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return errors.Wrap(errors.ErrCodeBacktestDataPathError, "failed to write pending orders file", err)
+	}
+
+	return nil
+}
+
+// Restore loads the orders saved by a previous run from path and keeps the ones
+// the exchange still reports as open. A missing file restores nothing.
+func (t *pendingOrderTracker) Restore(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeBacktestDataPathError, "failed to read pending orders file", err)
+	}
+
+	var orders []types.ExecuteOrder
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return errors.Wrap(errors.ErrCodeBacktestDataPathError, "failed to decode pending orders file", err)
+	}
+
+	if err := t.reconcile(orders); err != nil {
+		// Keep every saved order rather than losing track of them
+		t.mu.Lock()
+		t.orders = orders
+		t.mu.Unlock()
+
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to reconcile pending orders with the exchange", err)
+	}
+
+	return nil
+}
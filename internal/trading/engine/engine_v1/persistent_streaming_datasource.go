@@ -12,6 +12,7 @@ import (
 	"github.com/rxtech-lab/argo-trading/internal/backtest/engine/engine_v1/datasource"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
 )
 
 // PersistentStreamingDataSource implements datasource.DataSource for live streaming with file persistence.
@@ -133,7 +134,54 @@ func (p *PersistentStreamingDataSource) GetRange(start time.Time, end time.Time,
 		ORDER BY time ASC
 	`, p.parquetPath)
 
-	rows, err := p.db.Query(query, start, end)
+	return p.queryRange(query, start, end)
+}
+
+// GetRangeForSymbol implements datasource.DataSource.
+// Returns data of a single symbol from the parquet file within the specified time range.
+// With an interval the bars are aggregated into candles of that interval.
+func (p *PersistentStreamingDataSource) GetRangeForSymbol(symbol string, start time.Time, end time.Time, interval optional.Option[datasource.Interval]) ([]types.MarketData, error) {
+	if !p.hasData() {
+		return []types.MarketData{}, nil
+	}
+
+	if interval.IsSome() {
+		candleLength, err := provider.IntervalDuration(string(interval.Unwrap()))
+		if err != nil {
+			return nil, err
+		}
+
+		query := fmt.Sprintf(`
+			SELECT
+				time_bucket(INTERVAL '%d seconds', time) AS bucket_time,
+				symbol,
+				arg_min(open, time),
+				MAX(high),
+				MIN(low),
+				arg_max(close, time),
+				SUM(volume)
+			FROM read_parquet('%s')
+			WHERE symbol = $1 AND time >= $2 AND time <= $3
+			GROUP BY bucket_time, symbol
+			ORDER BY bucket_time ASC
+		`, int(candleLength/time.Second), p.parquetPath)
+
+		return p.queryRange(query, symbol, start, end)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT time, symbol, open, high, low, close, volume
+		FROM read_parquet('%s')
+		WHERE symbol = $1 AND time >= $2 AND time <= $3
+		ORDER BY time ASC
+	`, p.parquetPath)
+
+	return p.queryRange(query, symbol, start, end)
+}
+
+// queryRange runs a range query and scans the resulting market data rows.
+func (p *PersistentStreamingDataSource) queryRange(query string, args ...interface{}) ([]types.MarketData, error) {
+	rows, err := p.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -690,3 +690,51 @@ func (suite *PersistentStreamingDataSourceTestSuite) createTestParquetAtPath(par
 	_, err = db.Exec("COPY (SELECT * FROM market_data ORDER BY time ASC) TO '" + parquetPath + "' (FORMAT PARQUET)")
 	suite.Require().NoError(err)
 }
+
+func (suite *PersistentStreamingDataSourceTestSuite) TestGetRangeForSymbolWithInterval() {
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	testData := make([]types.MarketData, 10)
+	for i := 0; i < 10; i++ {
+		testData[i] = types.MarketData{
+			Symbol: "BTCUSDT",
+			Time:   baseTime.Add(time.Duration(i) * time.Minute),
+			Open:   100.0 + float64(i),
+			High:   110.0 + float64(i),
+			Low:    90.0 + float64(i),
+			Close:  105.0 + float64(i),
+			Volume: 10.0,
+		}
+	}
+
+	parquetPath := suite.createTestParquet("test_get_range_interval.parquet", testData)
+
+	ds := NewPersistentStreamingDataSource(parquetPath, "1m")
+	err := ds.Initialize("")
+	suite.Require().NoError(err)
+	defer ds.Close()
+
+	result, err := ds.GetRangeForSymbol("BTCUSDT", baseTime, baseTime.Add(9*time.Minute), optional.Some(datasource.Interval5m))
+	suite.Require().NoError(err)
+	suite.Require().Len(result, 2)
+
+	suite.Equal(baseTime, result[0].Time)
+	suite.Equal(100.0, result[0].Open)
+	suite.Equal(114.0, result[0].High)
+	suite.Equal(90.0, result[0].Low)
+	suite.Equal(109.0, result[0].Close)
+	suite.Equal(50.0, result[0].Volume)
+
+	suite.Equal(baseTime.Add(5*time.Minute), result[1].Time)
+	suite.Equal(105.0, result[1].Open)
+	suite.Equal(114.0, result[1].Close)
+
+	// Any interval the provider parser reads is aggregated, not only the backtest ones
+	result, err = ds.GetRangeForSymbol("BTCUSDT", baseTime, baseTime.Add(9*time.Minute), optional.Some(datasource.Interval("3m")))
+	suite.Require().NoError(err)
+	suite.Require().Len(result, 4)
+	suite.Equal(baseTime.Add(9*time.Minute), result[3].Time)
+	suite.Equal(10.0, result[3].Volume)
+
+	_, err = ds.GetRangeForSymbol("BTCUSDT", baseTime, baseTime.Add(9*time.Minute), optional.Some(datasource.Interval("2M")))
+	suite.Error(err)
+}
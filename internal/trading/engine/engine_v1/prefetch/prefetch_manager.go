@@ -64,88 +64,37 @@ func (p *PrefetchManager) Initialize(
 	p.gapToleranceUnit = parseIntervalDuration(interval)
 }
 
-// parseIntervalDuration converts interval string to time.Duration.
+// parseIntervalDuration returns the bar length of interval, or a minute when
+// the interval has no fixed length.
 func parseIntervalDuration(interval string) time.Duration {
-	switch interval {
-	case "1s":
-		return time.Second
-	case "1m":
-		return time.Minute
-	case "3m":
-		return 3 * time.Minute
-	case "5m":
-		return 5 * time.Minute
-	case "15m":
-		return 15 * time.Minute
-	case "30m":
-		return 30 * time.Minute
-	case "1h":
-		return time.Hour
-	case "2h":
-		return 2 * time.Hour
-	case "4h":
-		return 4 * time.Hour
-	case "6h":
-		return 6 * time.Hour
-	case "8h":
-		return 8 * time.Hour
-	case "12h":
-		return 12 * time.Hour
-	case "1d":
-		return 24 * time.Hour
-	case "3d":
-		return 72 * time.Hour
-	case "1w":
-		return 168 * time.Hour
-	default:
+	duration, err := provider.IntervalDuration(interval)
+	if err != nil {
 		return time.Minute
 	}
+
+	return duration
 }
 
-// intervalToTimespan converts interval string to Polygon Timespan.
+// intervalToTimespan returns the Polygon timespan of interval, or a minute for
+// an invalid interval.
 func intervalToTimespan(interval string) models.Timespan {
-	switch interval {
-	case "1s":
-		return models.Second
-	case "1m", "3m", "5m", "15m", "30m":
-		return models.Minute
-	case "1h", "2h", "4h", "6h", "8h", "12h":
-		return models.Hour
-	case "1d", "3d":
-		return models.Day
-	case "1w":
-		return models.Week
-	default:
+	_, timespan, err := provider.ParseInterval(interval)
+	if err != nil {
 		return models.Minute
 	}
+
+	return timespan
 }
 
-// intervalToMultiplier extracts the multiplier from interval string.
+// intervalToMultiplier returns the multiplier of interval, or 1 for an invalid
+// interval.
 func intervalToMultiplier(interval string) int {
-	switch interval {
-	case "1s", "1m", "1h", "1d", "1w":
-		return 1
-	case "3m", "3d":
-		return 3
-	case "5m":
-		return 5
-	case "15m":
-		return 15
-	case "30m":
-		return 30
-	case "2h":
-		return 2
-	case "4h":
-		return 4
-	case "6h":
-		return 6
-	case "8h":
-		return 8
-	case "12h":
-		return 12
-	default:
+	multiplier, _, err := provider.ParseInterval(interval)
+	if err != nil {
 		return 1
 	}
+
+	return multiplier
 }
 
 // emitStatus sends a status update callback.
@@ -55,6 +55,8 @@ func (s *PrefetchManagerTestSuite) TestParseIntervalDuration() {
 		{"1d", 24 * time.Hour},
 		{"3d", 72 * time.Hour},
 		{"1w", 168 * time.Hour},
+		{"2d", 48 * time.Hour},
+		{"1M", time.Minute},      // No fixed length
 		{"unknown", time.Minute}, // Default
 	}
 
@@ -84,6 +86,7 @@ func (s *PrefetchManagerTestSuite) TestIntervalToMultiplier() {
 		{"1d", 1},
 		{"3d", 3},
 		{"1w", 1},
+		{"90m", 90},
 		{"unknown", 1}, // Default
 	}
 
@@ -179,6 +182,7 @@ func (s *PrefetchManagerTestSuite) TestIntervalToTimespan() {
 		{"1d", models.Day},
 		{"3d", models.Day},
 		{"1w", models.Week},
+		{"1M", models.Month},
 		{"unknown", models.Minute}, // Default
 	}
 
@@ -0,0 +1,59 @@
+package engine_v1
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/clock"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
+)
+
+// paceReplay paces the stream by the configured replay speed when the market
+// data provider replays recorded data. Live streams and a zero speed are
+// returned unchanged.
+func (e *LiveTradingEngineV1) paceReplay(ctx context.Context, stream iter.Seq2[types.MarketData, error]) iter.Seq2[types.MarketData, error] {
+	replayer, ok := e.marketDataProvider.(provider.Replayer)
+	if e.config.ReplaySpeed <= 0 || !ok || !replayer.IsReplay() {
+		return stream
+	}
+
+	return paceStream(ctx, stream, e.config.ReplaySpeed, func(ctx context.Context, d time.Duration) error {
+		return clock.SleepContext(ctx, e.clock, d)
+	})
+}
+
+// paceStream waits the time between consecutive bars divided by speed before
+// yielding the later bar. Errors and bars that are not later than the previous
+// bar are yielded without waiting. The stream ends when sleep fails, which
+// happens once the context is cancelled.
+func paceStream(
+	ctx context.Context,
+	stream iter.Seq2[types.MarketData, error],
+	speed float64,
+	sleep func(ctx context.Context, d time.Duration) error,
+) iter.Seq2[types.MarketData, error] {
+	return func(yield func(types.MarketData, error) bool) {
+		var previous time.Time
+
+		for data, err := range stream {
+			if err == nil {
+				if !previous.IsZero() && data.Time.After(previous) {
+					wait := time.Duration(float64(data.Time.Sub(previous)) / speed)
+					if sleep(ctx, wait) != nil {
+						return
+					}
+				}
+
+				if data.Time.After(previous) {
+					previous = data.Time
+				}
+			}
+
+			if !yield(data, err) {
+				return
+			}
+		}
+	}
+}
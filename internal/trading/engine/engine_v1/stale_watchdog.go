@@ -0,0 +1,147 @@
+package engine_v1
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/clock"
+	"github.com/rxtech-lab/argo-trading/internal/trading/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
+	"go.uber.org/zap"
+)
+
+// staleWatchdog notices when the market data feed goes quiet, as set by
+// LiveTradingEngineConfig.StaleDataIntervals. onChange is called with true once
+// no bar has been observed for threshold, and with false on the next bar.
+type staleWatchdog struct {
+	clock     clock.Clock
+	threshold time.Duration
+	onChange  func(stale bool)
+
+	mu      sync.Mutex
+	lastBar time.Time
+	stale   bool
+}
+
+// newStaleWatchdog returns a watchdog that counts the quiet time from now.
+func newStaleWatchdog(c clock.Clock, threshold time.Duration, onChange func(stale bool)) *staleWatchdog {
+	return &staleWatchdog{
+		clock:     c,
+		threshold: threshold,
+		onChange:  onChange,
+		mu:        sync.Mutex{},
+		lastBar:   c.Now(),
+		stale:     false,
+	}
+}
+
+// Observe records that a bar arrived, which ends a stale period.
+func (w *staleWatchdog) Observe() {
+	w.mu.Lock()
+	w.lastBar = w.clock.Now()
+	wasStale := w.stale
+	w.stale = false
+	w.mu.Unlock()
+
+	if wasStale {
+		w.onChange(false)
+	}
+}
+
+// Run checks the feed until ctx is done. It waits until the threshold has
+// passed since the last bar, or a whole threshold again while the feed is stale.
+func (w *staleWatchdog) Run(ctx context.Context) {
+	for {
+		w.mu.Lock()
+
+		wait := w.threshold
+		if !w.stale {
+			wait -= w.clock.Now().Sub(w.lastBar)
+		}
+
+		w.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.clock.After(wait):
+		}
+
+		w.check()
+	}
+}
+
+// check marks the feed stale once the threshold has passed since the last bar.
+func (w *staleWatchdog) check() {
+	w.mu.Lock()
+	wentStale := !w.stale && w.clock.Now().Sub(w.lastBar) >= w.threshold
+	if wentStale {
+		w.stale = true
+	}
+	w.mu.Unlock()
+
+	if wentStale {
+		w.onChange(true)
+	}
+}
+
+// startStaleWatchdog starts watching the market data feed for the configured
+// number of bar intervals. It returns nil when the check is disabled or the
+// provider's interval is unknown.
+func (e *LiveTradingEngineV1) startStaleWatchdog(ctx context.Context, callbacks engine.LiveTradingCallbacks) *staleWatchdog {
+	if e.config.StaleDataIntervals <= 0 {
+		return nil
+	}
+
+	interval := e.marketDataProvider.GetInterval()
+
+	barLength, err := provider.IntervalDuration(interval)
+	if err != nil {
+		e.log.Warn("Cannot check the market data feed for staleness",
+			zap.String("interval", interval),
+			zap.Error(err),
+		)
+
+		return nil
+	}
+
+	threshold := time.Duration(e.config.StaleDataIntervals) * barLength
+
+	watchdog := newStaleWatchdog(e.clock, threshold, func(stale bool) {
+		if !stale {
+			e.log.Info("Market data feed resumed")
+			e.updateMarketDataStatus(types.ProviderStatusConnected, callbacks.OnProviderStatusChange)
+
+			return
+		}
+
+		e.log.Warn("Market data feed is stale",
+			zap.Duration("threshold", threshold),
+		)
+		e.updateMarketDataStatus(types.ProviderStatusStale, callbacks.OnProviderStatusChange)
+
+		if e.config.HaltOnStaleData {
+			e.haltOnStaleData(threshold, callbacks.OnError)
+		}
+	})
+
+	go watchdog.Run(ctx)
+
+	return watchdog
+}
+
+// haltOnStaleData cancels the strategy's open orders after the feed went
+// stale and reports the halt through onError.
+func (e *LiveTradingEngineV1) haltOnStaleData(threshold time.Duration, onError *engine.OnErrorCallback) {
+	if err := e.strategyTradingProvider().CancelAllOrders(); err != nil {
+		e.log.Warn("Failed to cancel orders after the market data feed went stale", zap.Error(err))
+	}
+
+	if onError != nil {
+		(*onError)(errors.Newf(errors.ErrCodeTradingHalted,
+			"trading halted: no market data for %s, open orders cancelled", threshold))
+	}
+}
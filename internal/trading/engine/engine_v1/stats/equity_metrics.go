@@ -0,0 +1,160 @@
+package stats
+
+import (
+	"math"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
+)
+
+// DefaultEquityWindowSize is the number of equity snapshots kept for the rolling
+// Sharpe and Sortino ratios.
+const DefaultEquityWindowSize = 1000
+
+// periodsPerYearBase is the length of a trading year. Live sessions run around the
+// clock (crypto markets never close), so a calendar year is used.
+const periodsPerYearBase = 365 * 24 * time.Hour
+
+// equityRing is a fixed-size ring buffer of equity snapshots, oldest first.
+type equityRing struct {
+	values []float64
+	start  int
+	size   int
+}
+
+func newEquityRing(capacity int) *equityRing {
+	return &equityRing{
+		values: make([]float64, capacity),
+		start:  0,
+		size:   0,
+	}
+}
+
+// push appends a value, overwriting the oldest one when the buffer is full.
+func (r *equityRing) push(value float64) {
+	if len(r.values) == 0 {
+		return
+	}
+
+	if r.size < len(r.values) {
+		r.values[(r.start+r.size)%len(r.values)] = value
+		r.size++
+
+		return
+	}
+
+	r.values[r.start] = value
+	r.start = (r.start + 1) % len(r.values)
+}
+
+// replaceLast overwrites the newest value.
+func (r *equityRing) replaceLast(value float64) {
+	if r.size == 0 {
+		r.push(value)
+
+		return
+	}
+
+	r.values[(r.start+r.size-1)%len(r.values)] = value
+}
+
+// last returns the newest value, or zero when the buffer is empty.
+func (r *equityRing) last() float64 {
+	if r.size == 0 {
+		return 0
+	}
+
+	return r.values[(r.start+r.size-1)%len(r.values)]
+}
+
+// returns returns the period-over-period returns of the buffered values, which
+// are PnL on top of the starting equity: equity_t / equity_{t-1} - 1. Periods
+// whose previous equity is zero have no defined return and are skipped, and
+// without a positive starting equity there are no returns at all.
+func (r *equityRing) returns(startingEquity float64) []float64 {
+	if r.size < 2 || startingEquity <= 0 {
+		return nil
+	}
+
+	changes := make([]float64, 0, r.size-1)
+	previous := startingEquity + r.values[r.start]
+
+	for i := 1; i < r.size; i++ {
+		current := startingEquity + r.values[(r.start+i)%len(r.values)]
+		if previous != 0 {
+			changes = append(changes, current/previous-1)
+		}
+
+		previous = current
+	}
+
+	return changes
+}
+
+// AnnualizationFactor returns the number of periods of the given interval in a
+// year (e.g. 365 for 1d and 8760 for 1h). Intervals use the provider format
+// such as 1m, 4h or 1w. It returns 1 for an empty, unknown or monthly interval
+// so ratios are reported per period.
+func AnnualizationFactor(interval string) float64 {
+	duration, err := provider.IntervalDuration(interval)
+	if err != nil {
+		return 1
+	}
+
+	return float64(periodsPerYearBase) / float64(duration)
+}
+
+// sharpeRatio returns mean(returns) / stdev(returns) * sqrt(annualization), using
+// the sample standard deviation and a zero risk-free rate. Zero when fewer than
+// two returns exist or the returns have zero variance.
+func sharpeRatio(returns []float64, annualization float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+
+	mean := meanOf(returns)
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+
+	stdev := math.Sqrt(variance / float64(len(returns)-1))
+	if stdev == 0 {
+		return 0
+	}
+
+	return mean / stdev * math.Sqrt(annualization)
+}
+
+// sortinoRatio returns mean(returns) / downside deviation * sqrt(annualization).
+// The downside deviation is the root mean square of the negative returns over
+// all periods. Zero when fewer than two returns exist or no return is negative.
+func sortinoRatio(returns []float64, annualization float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+
+	downside := 0.0
+	for _, r := range returns {
+		if r < 0 {
+			downside += r * r
+		}
+	}
+
+	downsideDeviation := math.Sqrt(downside / float64(len(returns)))
+	if downsideDeviation == 0 {
+		return 0
+	}
+
+	return meanOf(returns) / downsideDeviation * math.Sqrt(annualization)
+}
+
+func meanOf(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+
+	return total / float64(len(values))
+}
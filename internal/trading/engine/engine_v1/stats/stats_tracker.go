@@ -26,6 +26,12 @@ type StatsAccumulator struct {
 	TotalInvestment float64
 	HoldingTimes    []int     // in seconds
 	ClosedPnLs      []float64 // per closing-trade PnL
+
+	// Equity curve (PnL relative to the starting equity) sampled once per bar
+	EquityCurve       *equityRing
+	LastEquityTime    time.Time
+	EquityPeak        float64
+	EquityMaxDrawdown float64
 }
 
 // StatsTracker tracks live trading statistics in real-time.
@@ -37,12 +43,27 @@ type StatsTracker struct {
 	currentDate  string
 	strategyInfo types.StrategyInfo
 
+	// annualizationFactor is the number of bars per year, derived from the provider interval
+	annualizationFactor float64
+
+	// startingEquity is the account equity at session start. The equity curve
+	// holds PnL, so its returns are taken relative to this base.
+	startingEquity float64
+
+	// accountEquity is the latest account equity reported by the trading
+	// provider, valid when hasAccountEquity is set.
+	accountEquity    float64
+	hasAccountEquity bool
+
 	// Daily accumulators (reset on date boundary)
 	dailyStats *StatsAccumulator
 
 	// Cumulative accumulators (from session start)
 	cumulativeStats *StatsAccumulator
 
+	// Cumulative accumulators per strategy, keyed by the trade's strategy name
+	strategyStats map[string]*StatsAccumulator
+
 	// File paths for parquet files
 	ordersFilePath     string
 	tradesFilePath     string
@@ -64,24 +85,29 @@ type StatsTracker struct {
 // NewStatsTracker creates a new StatsTracker instance.
 func NewStatsTracker(log *logger.Logger) *StatsTracker {
 	return &StatsTracker{
-		symbols:            nil,
-		runID:              "",
-		runName:            "",
-		sessionStart:       time.Time{},
-		currentDate:        "",
-		strategyInfo:       types.StrategyInfo{}, //nolint:exhaustruct // initialized via Initialize()
-		ordersFilePath:     "",
-		tradesFilePath:     "",
-		marksFilePath:      "",
-		logsFilePath:       "",
-		marketDataFilePath: "",
-		statsOutputPath:    "",
-		lastUpdated:        time.Time{},
-		dirty:              false,
-		dailyStats:         newStatsAccumulator(),
-		cumulativeStats:    newStatsAccumulator(),
-		mu:                 sync.Mutex{},
-		logger:             log,
+		symbols:             nil,
+		runID:               "",
+		runName:             "",
+		sessionStart:        time.Time{},
+		currentDate:         "",
+		strategyInfo:        types.StrategyInfo{}, //nolint:exhaustruct // initialized via Initialize()
+		annualizationFactor: AnnualizationFactor(""),
+		startingEquity:      0,
+		accountEquity:       0,
+		hasAccountEquity:    false,
+		ordersFilePath:      "",
+		tradesFilePath:      "",
+		marksFilePath:       "",
+		logsFilePath:        "",
+		marketDataFilePath:  "",
+		statsOutputPath:     "",
+		lastUpdated:         time.Time{},
+		dirty:               false,
+		dailyStats:          newStatsAccumulator(),
+		cumulativeStats:     newStatsAccumulator(),
+		strategyStats:       make(map[string]*StatsAccumulator),
+		mu:                  sync.Mutex{},
+		logger:              log,
 	}
 }
 
@@ -102,6 +128,11 @@ func newStatsAccumulator() *StatsAccumulator {
 		TotalInvestment: 0,
 		HoldingTimes:    make([]int, 0),
 		ClosedPnLs:      make([]float64, 0),
+
+		EquityCurve:       newEquityRing(DefaultEquityWindowSize),
+		LastEquityTime:    time.Time{},
+		EquityPeak:        0,
+		EquityMaxDrawdown: 0,
 	}
 }
 
@@ -178,6 +209,17 @@ func (s *StatsTracker) RecordTrade(trade types.Trade) {
 	// Update both daily and cumulative stats
 	s.updateAccumulator(s.dailyStats, trade)
 	s.updateAccumulator(s.cumulativeStats, trade)
+
+	if name := trade.Order.StrategyName; name != "" {
+		acc, ok := s.strategyStats[name]
+		if !ok {
+			acc = newStatsAccumulator()
+			s.strategyStats[name] = acc
+		}
+
+		s.updateAccumulator(acc, trade)
+	}
+
 	s.lastUpdated = time.Now()
 	s.dirty = true
 
@@ -257,6 +299,91 @@ func (s *StatsTracker) SetUnrealizedPnL(unrealizedPnL float64) {
 	s.dirty = true
 }
 
+// SetInterval sets the bar interval of the market data (e.g. 1m or 1h). It
+// determines the annualization factor of the Sharpe and Sortino ratios:
+// the number of bars in a 365-day year, since live markets trade around the clock.
+func (s *StatsTracker) SetInterval(interval string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.annualizationFactor = AnnualizationFactor(interval)
+}
+
+// SetStartingEquity sets the account equity at session start. The Sharpe and
+// Sortino ratios use the returns of this equity plus the PnL, so they stay zero
+// until the equity is set.
+func (s *StatsTracker) SetStartingEquity(equity float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.startingEquity = equity
+}
+
+// SetAccountEquity sets the account equity reported by the trading provider.
+// Once set, the equity curve follows the account equity relative to the
+// starting equity rather than the PnL of the recorded trades, which misses
+// what providers do not report per trade, such as the value of spot holdings.
+func (s *StatsTracker) SetAccountEquity(equity float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accountEquity = equity
+	s.hasAccountEquity = true
+}
+
+// RecordEquitySnapshot samples the current equity for the bar at the given
+// time: the account equity relative to the starting equity, or realized plus
+// unrealized PnL while the account equity is unknown. Several snapshots for the
+// same bar time (one per symbol) keep only the latest value, so the equity
+// curve has one point per bar. Stats are only marked as changed when the
+// equity moved since the previous snapshot.
+func (s *StatsTracker) RecordEquitySnapshot(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recordEquity(s.dailyStats, at)
+
+	if s.recordEquity(s.cumulativeStats, at) {
+		s.lastUpdated = time.Now()
+		s.dirty = true
+	}
+}
+
+// recordEquity appends the accumulator's current equity to its equity curve and
+// updates the maximum drawdown. It reports whether the equity differs from the
+// previous snapshot.
+//
+//nolint:funcorder // helper method used by RecordEquitySnapshot
+func (s *StatsTracker) recordEquity(acc *StatsAccumulator, at time.Time) bool {
+	equity := acc.RealizedPnL + acc.UnrealizedPnL
+	if s.hasAccountEquity && s.startingEquity > 0 {
+		equity = s.accountEquity - s.startingEquity
+	}
+
+	changed := acc.EquityCurve.size == 0 || equity != acc.EquityCurve.last()
+
+	if acc.EquityCurve.size > 0 && at.Equal(acc.LastEquityTime) {
+		acc.EquityCurve.replaceLast(equity)
+	} else {
+		if acc.EquityCurve.size == 0 {
+			acc.EquityPeak = equity
+		}
+
+		acc.EquityCurve.push(equity)
+		acc.LastEquityTime = at
+	}
+
+	if equity > acc.EquityPeak {
+		acc.EquityPeak = equity
+	}
+
+	if drawdown := acc.EquityPeak - equity; drawdown > acc.EquityMaxDrawdown {
+		acc.EquityMaxDrawdown = drawdown
+	}
+
+	return changed
+}
+
 // HandleDateBoundary handles the transition to a new date.
 // Resets daily stats while keeping cumulative stats.
 func (s *StatsTracker) HandleDateBoundary(newDate string) {
@@ -290,7 +417,44 @@ func (s *StatsTracker) GetCumulativeStats() types.LiveTradeStats {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return s.buildLiveTradeStats(s.cumulativeStats, s.sessionStart.Format("2006-01-02"))
+	return s.buildCumulativeStats(s.sessionStart.Format("2006-01-02"))
+}
+
+// GetStrategyStats returns the cumulative statistics of each strategy that
+// recorded a trade, keyed by strategy name.
+func (s *StatsTracker) GetStrategyStats() map[string]types.LiveTradeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buildStrategyStats(s.sessionStart.Format("2006-01-02"))
+}
+
+// buildCumulativeStats builds the cumulative statistics, broken down by
+// strategy when trades of more than one strategy were recorded.
+//
+//nolint:funcorder // helper method used by GetCumulativeStats and WriteStatsYAML
+func (s *StatsTracker) buildCumulativeStats(date string) types.LiveTradeStats {
+	stats := s.buildLiveTradeStats(s.cumulativeStats, date)
+	if len(s.strategyStats) > 1 {
+		stats.Strategies = s.buildStrategyStats(date)
+	}
+
+	return stats
+}
+
+// buildStrategyStats builds the cumulative statistics of each strategy.
+//
+//nolint:funcorder // helper method used by GetStrategyStats and buildCumulativeStats
+func (s *StatsTracker) buildStrategyStats(date string) map[string]types.LiveTradeStats {
+	stats := make(map[string]types.LiveTradeStats, len(s.strategyStats))
+
+	for name, acc := range s.strategyStats {
+		strategyStats := s.buildLiveTradeStats(acc, date)
+		strategyStats.Strategy = types.StrategyInfo{ID: "", Version: "", Name: name}
+		stats[name] = strategyStats
+	}
+
+	return stats
 }
 
 // buildLiveTradeStats builds a LiveTradeStats from an accumulator.
@@ -371,6 +535,15 @@ func (s *StatsTracker) buildLiveTradeStats(acc *StatsAccumulator, date string) t
 		}
 	}
 
+	returns := acc.EquityCurve.returns(s.startingEquity)
+	equityMetrics := types.EquityMetrics{
+		SharpeRatio:         sharpeRatio(returns, s.annualizationFactor),
+		SortinoRatio:        sortinoRatio(returns, s.annualizationFactor),
+		MaxDrawdown:         acc.EquityMaxDrawdown,
+		AnnualizationFactor: s.annualizationFactor,
+		Samples:             acc.EquityCurve.size,
+	}
+
 	pnlPercentage := 0.0
 	if acc.TotalInvestment > 0 {
 		pnlPercentage = (acc.RealizedPnL + acc.UnrealizedPnL) / acc.TotalInvestment * 100
@@ -405,12 +578,14 @@ func (s *StatsTracker) buildLiveTradeStats(acc *StatsAccumulator, date string) t
 		},
 		TradeHoldingTime:   holdingTime,
 		TotalFees:          acc.TotalFees,
+		EquityMetrics:      equityMetrics,
 		OrdersFilePath:     s.ordersFilePath,
 		TradesFilePath:     s.tradesFilePath,
 		MarksFilePath:      s.marksFilePath,
 		LogsFilePath:       s.logsFilePath,
 		MarketDataFilePath: s.marketDataFilePath,
 		Strategy:           s.strategyInfo,
+		Strategies:         nil,
 	}
 }
 
@@ -428,7 +603,7 @@ func (s *StatsTracker) WriteStatsYAML() error {
 		return nil // Nothing changed since last write
 	}
 
-	stats := s.buildLiveTradeStats(s.cumulativeStats, s.currentDate)
+	stats := s.buildCumulativeStats(s.currentDate)
 
 	if err := types.WriteLiveTradeStats(s.statsOutputPath, stats); err != nil {
 		return err
@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
@@ -474,3 +475,226 @@ func (s *StatsTrackerTestSuite) TestRecordTrade_ZeroPnL() {
 	s.Equal(0, stats.TradeResult.NumberOfLosingTrades)
 	s.Equal(0.0, stats.TradeResult.WinRate)
 }
+
+func (s *StatsTrackerTestSuite) TestEquityMetrics_KnownSequence() {
+	st := NewStatsTracker(s.logger)
+	st.Initialize([]string{"BTCUSDT"}, "run_1", "run_1", time.Now(), types.StrategyInfo{})
+	st.SetInterval("1d")
+	st.SetStartingEquity(100)
+
+	// Equity 100, 110, 105, 115, 100, 120
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, pnl := range []float64{0, 10, 5, 15, 0, 20} {
+		st.SetUnrealizedPnL(pnl)
+		st.RecordEquitySnapshot(start.Add(time.Duration(i) * 24 * time.Hour))
+	}
+
+	metrics := st.GetCumulativeStats().EquityMetrics
+
+	// Returns 0.1, -0.04545, 0.09524, -0.13043, 0.2: mean = 0.04387,
+	// sample stdev = 0.13089, downside deviation = 0.06177
+	s.Equal(365.0, metrics.AnnualizationFactor)
+	s.Equal(6, metrics.Samples)
+	s.Equal(15.0, metrics.MaxDrawdown)
+	s.InDelta(0.04386975/0.13088818*19.104973, metrics.SharpeRatio, 1e-4)
+	s.InDelta(0.04386975/0.06177273*19.104973, metrics.SortinoRatio, 1e-4)
+}
+
+func (s *StatsTrackerTestSuite) TestEquityMetrics_ReturnsAreRelativeToEquity() {
+	sharpe := func(startingEquity float64, pnls []float64) float64 {
+		st := NewStatsTracker(s.logger)
+		st.SetStartingEquity(startingEquity)
+
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i, pnl := range pnls {
+			st.SetUnrealizedPnL(pnl)
+			st.RecordEquitySnapshot(start.Add(time.Duration(i) * time.Hour))
+		}
+
+		return st.GetCumulativeStats().EquityMetrics.SharpeRatio
+	}
+
+	// The same percentage moves on a ten times larger account give the same ratio
+	small := sharpe(1000, []float64{0, 10, 5, 30, 20})
+	large := sharpe(10000, []float64{0, 100, 50, 300, 200})
+
+	s.NotZero(small)
+	s.InDelta(small, large, 1e-9)
+}
+
+func (s *StatsTrackerTestSuite) TestEquityMetrics_SkipsZeroEquity() {
+	st := NewStatsTracker(s.logger)
+	st.SetStartingEquity(100)
+
+	// The equity is wiped out, so the return after it is undefined and skipped
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, pnl := range []float64{0, -100, -50, -40} {
+		st.SetUnrealizedPnL(pnl)
+		st.RecordEquitySnapshot(start.Add(time.Duration(i) * time.Hour))
+	}
+
+	metrics := st.GetCumulativeStats().EquityMetrics
+
+	s.False(math.IsNaN(metrics.SharpeRatio) || math.IsInf(metrics.SharpeRatio, 0))
+	s.False(math.IsNaN(metrics.SortinoRatio) || math.IsInf(metrics.SortinoRatio, 0))
+	s.NotZero(metrics.SharpeRatio)
+}
+
+func (s *StatsTrackerTestSuite) TestEquityMetrics_NoStartingEquity() {
+	st := NewStatsTracker(s.logger)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, pnl := range []float64{0, 10, 5, 15} {
+		st.SetUnrealizedPnL(pnl)
+		st.RecordEquitySnapshot(start.Add(time.Duration(i) * time.Hour))
+	}
+
+	metrics := st.GetCumulativeStats().EquityMetrics
+
+	s.Equal(0.0, metrics.SharpeRatio)
+	s.Equal(0.0, metrics.SortinoRatio)
+	s.Equal(5.0, metrics.MaxDrawdown)
+}
+
+func (s *StatsTrackerTestSuite) TestEquityMetrics_FollowAccountEquity() {
+	st := NewStatsTracker(s.logger)
+	st.SetStartingEquity(1000)
+
+	// A spot fill reports no PnL; the equity curve follows the account instead
+	st.RecordTrade(types.Trade{Order: types.Order{Side: types.PurchaseTypeBuy}, ExecutedQty: 1, ExecutedPrice: 500})
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, equity := range []float64{1000, 1050, 1020, 1080} {
+		st.SetAccountEquity(equity)
+		st.RecordEquitySnapshot(start.Add(time.Duration(i) * time.Hour))
+	}
+
+	metrics := st.GetCumulativeStats().EquityMetrics
+
+	s.Equal(4, metrics.Samples)
+	s.Equal(30.0, metrics.MaxDrawdown)
+	s.NotZero(metrics.SharpeRatio)
+	s.NotZero(metrics.SortinoRatio)
+}
+
+func (s *StatsTrackerTestSuite) TestEquityMetrics_SameBarKeepsLatestValue() {
+	st := NewStatsTracker(s.logger)
+	bar := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	st.SetUnrealizedPnL(10)
+	st.RecordEquitySnapshot(bar)
+	st.SetUnrealizedPnL(-10)
+	st.RecordEquitySnapshot(bar)
+	st.SetUnrealizedPnL(5)
+	st.RecordEquitySnapshot(bar.Add(time.Minute))
+
+	metrics := st.GetCumulativeStats().EquityMetrics
+
+	s.Equal(2, metrics.Samples)
+	s.Equal(20.0, metrics.MaxDrawdown, "drawdown includes intra-bar updates")
+	s.Equal(0.0, metrics.SharpeRatio, "a single return has no variance")
+}
+
+func (s *StatsTrackerTestSuite) TestEquityMetrics_RollingWindow() {
+	st := NewStatsTracker(s.logger)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < DefaultEquityWindowSize+10; i++ {
+		st.SetUnrealizedPnL(float64(i % 3))
+		st.RecordEquitySnapshot(start.Add(time.Duration(i) * time.Minute))
+	}
+
+	s.Equal(DefaultEquityWindowSize, st.GetCumulativeStats().EquityMetrics.Samples)
+}
+
+func (s *StatsTrackerTestSuite) TestEquityMetrics_DailyResetOnDateBoundary() {
+	st := NewStatsTracker(s.logger)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	st.SetUnrealizedPnL(10)
+	st.RecordEquitySnapshot(start)
+	st.SetUnrealizedPnL(0)
+	st.RecordEquitySnapshot(start.Add(time.Hour))
+
+	st.HandleDateBoundary("2024-01-02")
+
+	s.Equal(0.0, st.GetDailyStats().EquityMetrics.MaxDrawdown)
+	s.Equal(0, st.GetDailyStats().EquityMetrics.Samples)
+	s.Equal(10.0, st.GetCumulativeStats().EquityMetrics.MaxDrawdown)
+}
+
+func (s *StatsTrackerTestSuite) TestAnnualizationFactor() {
+	tests := []struct {
+		interval string
+		expected float64
+	}{
+		{"1m", 525600},
+		{"5m", 105120},
+		{"1h", 8760},
+		{"4h", 2190},
+		{"1d", 365},
+		{"1w", 365.0 / 7},
+		{"", 1},
+		{"bogus", 1},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.interval, func() {
+			s.InDelta(tc.expected, AnnualizationFactor(tc.interval), 1e-9)
+		})
+	}
+}
+
+func (s *StatsTrackerTestSuite) TestRecordTrade_SegregatesStrategies() {
+	st := NewStatsTracker(s.logger)
+	st.Initialize([]string{"BTCUSDT"}, "run_1", "run_1", time.Now(), types.StrategyInfo{})
+
+	trade := func(strategyName string, pnl float64) types.Trade {
+		return types.Trade{
+			Order: types.Order{
+				OrderID:      "order-" + strategyName,
+				Symbol:       "BTCUSDT",
+				Side:         types.PurchaseTypeSell,
+				Quantity:     1.0,
+				Price:        51000.0,
+				Timestamp:    time.Now().Add(-time.Hour),
+				IsCompleted:  true,
+				Status:       types.OrderStatusFilled,
+				Reason:       types.Reason{Reason: "strategy", Message: "Test"},
+				StrategyName: strategyName,
+				Fee:          1.0,
+				PositionType: types.PositionTypeLong,
+			},
+			ExecutedAt:    time.Now(),
+			ExecutedQty:   1.0,
+			ExecutedPrice: 51000.0,
+			Fee:           1.0,
+			PnL:           pnl,
+		}
+	}
+
+	st.RecordTrade(trade("momentum", 300))
+	st.RecordTrade(trade("momentum", -100))
+
+	// A single strategy adds no breakdown
+	s.Nil(st.GetCumulativeStats().Strategies)
+
+	st.RecordTrade(trade("meanrevert", 50))
+
+	cumulative := st.GetCumulativeStats()
+	s.Equal(3, cumulative.TradeResult.NumberOfTrades)
+	s.Equal(250.0, cumulative.TradePnl.RealizedPnL)
+	s.Require().Len(cumulative.Strategies, 2)
+
+	momentum := cumulative.Strategies["momentum"]
+	s.Equal("momentum", momentum.Strategy.Name)
+	s.Equal(2, momentum.TradeResult.NumberOfTrades)
+	s.Equal(1, momentum.TradeResult.NumberOfWinningTrades)
+	s.Equal(200.0, momentum.TradePnl.RealizedPnL)
+
+	meanRevert := cumulative.Strategies["meanrevert"]
+	s.Equal(1, meanRevert.TradeResult.NumberOfTrades)
+	s.Equal(50.0, meanRevert.TradePnl.RealizedPnL)
+
+	s.Equal(cumulative.Strategies, st.GetStrategyStats())
+}
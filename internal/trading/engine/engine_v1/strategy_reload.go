@@ -0,0 +1,89 @@
+package engine_v1
+
+import (
+	"github.com/rxtech-lab/argo-trading/internal/runtime"
+	"github.com/rxtech-lab/argo-trading/internal/runtime/wasm"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ReloadStrategyFromFile replaces the running strategy with the WASM strategy at
+// strategyPath. See ReloadStrategy.
+func (e *LiveTradingEngineV1) ReloadStrategyFromFile(strategyPath string) error {
+	strategy, err := wasm.NewStrategyWasmRuntime(strategyPath)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeStrategyRuntimeError, "failed to create strategy runtime", err)
+	}
+
+	return e.ReloadStrategy(strategy)
+}
+
+// ReloadStrategy replaces a running strategy without stopping the engine. With
+// several strategies loaded it replaces the one with the same name, otherwise
+// the only one. The swap waits for the bar being processed, then initializes
+// the new strategy with the current strategy config, and the next bar goes to
+// the new strategy. If the new strategy cannot be initialized, for instance
+// because it was built for an incompatible engine version, the old strategy
+// stays active.
+//
+// Before Run initializes the strategies, ReloadStrategy only swaps them.
+func (e *LiveTradingEngineV1) ReloadStrategy(strategy runtime.StrategyRuntime) error {
+	e.strategyMu.Lock()
+	defer e.strategyMu.Unlock()
+
+	target, err := e.reloadTarget(strategy.Name())
+	if err != nil {
+		return err
+	}
+
+	if e.strategyContext == nil {
+		e.strategies[target] = &loadedStrategy{runtime: strategy, context: nil}
+
+		return nil
+	}
+
+	previous := e.strategies[target]
+	replacement := &loadedStrategy{
+		runtime: strategy,
+		context: e.newStrategyContext(strategy, *e.strategyContext),
+	}
+
+	if err := e.initializeStrategyRuntime(replacement); err != nil {
+		e.log.Warn("Strategy reload rejected, keeping the current strategy",
+			zap.String("current", previous.runtime.Name()),
+			zap.Error(err),
+		)
+
+		return err
+	}
+
+	// Keep the bar the previous strategy was last given
+	replacement.context.CurrentMarketData = previous.context.CurrentMarketData
+	e.strategies[target] = replacement
+
+	e.log.Info("Strategy reloaded",
+		zap.String("previous", previous.runtime.Name()),
+		zap.String("name", strategy.Name()),
+	)
+
+	return nil
+}
+
+// reloadTarget returns the index of the loaded strategy a strategy named name
+// replaces.
+func (e *LiveTradingEngineV1) reloadTarget(name string) (int, error) {
+	switch len(e.strategies) {
+	case 0:
+		return 0, errors.New(errors.ErrCodeBacktestInitFailed, "strategy not loaded - call LoadStrategy*() first")
+	case 1:
+		return 0, nil
+	}
+
+	for i, loaded := range e.strategies {
+		if loaded.runtime.Name() == name {
+			return i, nil
+		}
+	}
+
+	return 0, errors.Newf(errors.ErrCodeInvalidParameter, "no loaded strategy named %q to reload", name)
+}
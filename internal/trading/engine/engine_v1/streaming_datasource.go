@@ -79,6 +79,22 @@ func (s *StreamingDataSource) GetRange(start time.Time, end time.Time, interval
 	return nil, ErrDataNotFound
 }
 
+// GetRangeForSymbol implements datasource.DataSource.
+// Returns the cached data of a single symbol if available.
+func (s *StreamingDataSource) GetRangeForSymbol(symbol string, start time.Time, end time.Time, interval optional.Option[datasource.Interval]) ([]types.MarketData, error) {
+	// Aggregation (interval) is not supported in streaming cache
+	if interval.IsSome() {
+		return nil, ErrNotSupported
+	}
+
+	if data, ok := s.cache.GetRangeForSymbol(symbol, start, end); ok {
+		return data, nil
+	}
+
+	// Cache miss - in streaming mode, we can only return what's in the cache
+	return nil, ErrDataNotFound
+}
+
 // GetPreviousNumberOfDataPoints implements datasource.DataSource.
 // Returns data from the cache if available.
 func (s *StreamingDataSource) GetPreviousNumberOfDataPoints(end time.Time, symbol string, count int) ([]types.MarketData, error) {
@@ -0,0 +1,30 @@
+package engine_v1
+
+import (
+	"context"
+	"iter"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
+	"go.uber.org/zap"
+)
+
+// marketDataStream returns the bar stream the engine consumes. A provider that
+// streams ticks has its ticks aggregated into bars of its configured interval;
+// other providers stream their bars directly.
+func (e *LiveTradingEngineV1) marketDataStream(ctx context.Context) (iter.Seq2[types.MarketData, error], error) {
+	tickStreamer, ok := e.marketDataProvider.(provider.TickStreamer)
+	if !ok {
+		return e.marketDataProvider.Stream(ctx), nil
+	}
+
+	interval, err := provider.IntervalDuration(e.marketDataProvider.GetInterval())
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInvalidConfiguration, "cannot aggregate ticks into bars", err)
+	}
+
+	e.log.Info("Aggregating ticks into bars", zap.Duration("interval", interval))
+
+	return provider.AggregateTicks(tickStreamer.StreamTicks(ctx), interval), nil
+}
@@ -0,0 +1,79 @@
+package engine_v1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rxtech-lab/argo-trading/internal/trading/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// validate runs the checks of a validate-only run after preRunCheck passed:
+// it initializes the strategy with order placement suppressed, checks the
+// trading provider connection and that the provider prices every symbol.
+// Each passed check is reported through OnStatusUpdate; the first failure is
+// reported through OnError and returned.
+func (e *LiveTradingEngineV1) validate(ctx context.Context, callbacks engine.LiveTradingCallbacks) error {
+	fail := func(err error) error {
+		e.log.Error("Validation failed", zap.Error(err))
+
+		if callbacks.OnError != nil {
+			(*callbacks.OnError)(err)
+		}
+
+		return err
+	}
+
+	e.reportValidated(callbacks, types.EngineStatusConfigValidated)
+
+	// The suppressor never sees the running status, so orders the strategy
+	// places while initializing are dropped
+	e.orderSuppressor = newOrderSuppressingProvider(e.strategyTradingProvider(), e.log, e.logStorage, e.clock)
+
+	if err := e.initializeStrategy(); err != nil {
+		return fail(err)
+	}
+
+	e.reportValidated(callbacks, types.EngineStatusStrategyValidated)
+
+	if err := e.tradingProvider.CheckConnection(ctx); err != nil {
+		return fail(errors.Wrap(errors.ErrCodeBacktestInitFailed, "trading provider precheck failed", err))
+	}
+
+	e.reportValidated(callbacks, types.EngineStatusConnectionValidated)
+
+	symbols := e.marketDataProvider.GetSymbols()
+
+	prices, err := e.tradingProvider.GetPrices(symbols)
+	if err != nil {
+		return fail(errors.Wrap(errors.ErrCodeBacktestInitFailed, "failed to look up symbols on the trading provider", err))
+	}
+
+	var unknown []string
+
+	for _, symbol := range symbols {
+		if _, ok := prices[symbol]; !ok {
+			unknown = append(unknown, symbol)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fail(errors.Newf(errors.ErrCodeInvalidConfiguration,
+			"symbols not tradable on the trading provider: %s", strings.Join(unknown, ", ")))
+	}
+
+	e.reportValidated(callbacks, types.EngineStatusSymbolsValidated)
+
+	return nil
+}
+
+// reportValidated logs a passed validation check and reports it through OnStatusUpdate.
+func (e *LiveTradingEngineV1) reportValidated(callbacks engine.LiveTradingCallbacks, status types.EngineStatus) {
+	e.log.Info("Validation check passed", zap.String("check", string(status)))
+
+	if callbacks.OnStatusUpdate != nil {
+		_ = (*callbacks.OnStatusUpdate)(status)
+	}
+}
@@ -0,0 +1,260 @@
+package engine_v1
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/trading/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
+	"go.uber.org/zap"
+)
+
+// warmupTracker counts the bars of each symbol during the warm-up
+// period set by LiveTradingEngineConfig.WarmupBars, and holds back the running
+// status until every symbol has warmed up.
+type warmupTracker struct {
+	bars    int
+	symbols []string
+	seen    map[string]int
+	done    bool
+	// next receives the status updates passed through StatusCallback.
+	next *engine.OnStatusUpdateCallback
+}
+
+// newWarmupTracker returns a tracker that warms up each of symbols for bars
+// bars and forwards status updates to next, which may be nil.
+func newWarmupTracker(bars int, symbols []string, next *engine.OnStatusUpdateCallback) *warmupTracker {
+	return &warmupTracker{
+		bars:    bars,
+		symbols: symbols,
+		seen:    map[string]int{},
+		done:    false,
+		next:    next,
+	}
+}
+
+// StatusCallback returns a callback that forwards status updates to the
+// tracker's callback, reporting EngineStatusRunning as EngineStatusWarmingUp
+// while the warm-up period lasts.
+func (w *warmupTracker) StatusCallback() *engine.OnStatusUpdateCallback {
+	callback := engine.OnStatusUpdateCallback(func(status types.EngineStatus) error {
+		if status == types.EngineStatusRunning && !w.done {
+			status = types.EngineStatusWarmingUp
+		}
+
+		return w.emit(status)
+	})
+
+	return &callback
+}
+
+// Observe counts data and reports whether it falls in the warm-up period of its
+// symbol. The bar that completes the warm-up of the last symbol reports the
+// engine as running.
+func (w *warmupTracker) Observe(data types.MarketData) bool {
+	w.seen[data.Symbol]++
+	warmingUp := w.seen[data.Symbol] <= w.bars
+
+	if !w.done && w.allWarm() {
+		w.done = true
+		_ = w.emit(types.EngineStatusRunning)
+	}
+
+	return warmingUp
+}
+
+// Backfill counts bars of symbol that were loaded from history toward its
+// warm-up. The running status is left to the first streamed bar.
+func (w *warmupTracker) Backfill(symbol string, bars int) {
+	w.seen[symbol] += bars
+	w.done = w.allWarm()
+}
+
+// allWarm reports whether every symbol has streamed its warm-up bars.
+func (w *warmupTracker) allWarm() bool {
+	for _, symbol := range w.symbols {
+		if w.seen[symbol] < w.bars {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (w *warmupTracker) emit(status types.EngineStatus) error {
+	if w.next == nil {
+		return nil
+	}
+
+	return (*w.next)(status)
+}
+
+// backfillWarmup loads the last WarmupBars closed bars of every symbol into the
+// data cache, and into the parquet file when persistence is enabled, so the
+// strategy need not wait for them to stream. The bars come from the warmup data
+// source when one is set and from the market data provider's history otherwise.
+// A symbol whose bars cannot be loaded warms up from the stream instead.
+//
+// It returns the time of the last bar loaded for each symbol, where the stream
+// takes over.
+func (e *LiveTradingEngineV1) backfillWarmup(ctx context.Context, warmup *warmupTracker) map[string]time.Time {
+	interval := e.marketDataProvider.GetInterval()
+
+	barLength, err := provider.IntervalDuration(interval)
+	if err != nil {
+		e.log.Warn("Cannot backfill warm-up bars, warming up from the stream",
+			zap.String("interval", interval),
+			zap.Error(err),
+		)
+
+		return nil
+	}
+
+	end := e.clock.Now()
+	handoff := make(map[string]time.Time)
+
+	for _, symbol := range e.marketDataProvider.GetSymbols() {
+		var closed []types.MarketData
+
+		if e.warmupDataSource != nil {
+			closed = e.warmupBarsFromSource(ctx, symbol, interval, barLength, end)
+		}
+
+		// Without warmup data source bars for the symbol, take them all from the provider
+		if len(closed) == 0 {
+			closed, err = e.warmupBarsFromProvider(ctx, symbol, interval, barLength, end)
+			if err != nil {
+				e.log.Warn("Failed to backfill warm-up bars, warming up from the stream",
+					zap.String("symbol", symbol),
+					zap.Error(err),
+				)
+
+				continue
+			}
+		}
+
+		for _, bar := range closed {
+			if e.streamingWriter != nil {
+				if err := e.streamingWriter.Write(bar); err != nil {
+					e.log.Error("Failed to persist backfilled market data",
+						zap.String("symbol", bar.Symbol),
+						zap.Time("time", bar.Time),
+						zap.Error(err),
+					)
+				}
+			}
+
+			e.streamingDataSource.AddToCache(bar)
+		}
+
+		if len(closed) > 0 {
+			handoff[symbol] = closed[len(closed)-1].Time
+		}
+
+		warmup.Backfill(symbol, len(closed))
+
+		e.log.Info("Backfilled warm-up bars",
+			zap.String("symbol", symbol),
+			zap.Int("bars", len(closed)),
+		)
+	}
+
+	// Indicators read the parquet file when persistence is enabled, so flush the
+	// warm-up bars before the first streamed bar needs them
+	if e.streamingWriter != nil && len(handoff) > 0 {
+		if err := e.streamingWriter.Flush(); err != nil {
+			e.log.Error("Failed to flush backfilled warm-up bars", zap.Error(err))
+		}
+	}
+
+	return handoff
+}
+
+// warmupBarsFromProvider returns the last WarmupBars bars of symbol that closed
+// by end from the market data provider's history.
+func (e *LiveTradingEngineV1) warmupBarsFromProvider(ctx context.Context, symbol, interval string, barLength time.Duration, end time.Time) ([]types.MarketData, error) {
+	// One bar more than needed, since the newest one may still be open
+	start := end.Add(-time.Duration(e.config.WarmupBars+1) * barLength)
+
+	bars, err := e.marketDataProvider.GetHistoricalKlines(ctx, symbol, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return lastBars(closedBars(bars, barLength, end), e.config.WarmupBars), nil
+}
+
+// warmupBarsFromSource returns the last WarmupBars bars of symbol that closed by
+// end from the warmup data source. The bars that closed after the last one the
+// source holds are added from the market data provider's history, so the warm-up
+// continues up to the bar the stream starts with.
+func (e *LiveTradingEngineV1) warmupBarsFromSource(ctx context.Context, symbol, interval string, barLength time.Duration, end time.Time) []types.MarketData {
+	bars, err := e.warmupDataSource.GetPreviousNumberOfDataPoints(end, symbol, e.config.WarmupBars+1)
+	if err != nil {
+		e.log.Warn("Failed to read warm-up bars from the warmup data source",
+			zap.String("symbol", symbol),
+			zap.Error(err),
+		)
+
+		return nil
+	}
+
+	bars = closedBars(bars, barLength, end)
+	if len(bars) == 0 {
+		return nil
+	}
+
+	// The bar after the source's last one may still be open and then arrives on the stream
+	last := bars[len(bars)-1].Time
+	start := last.Add(barLength)
+
+	if end.Sub(start) >= barLength {
+		missed, err := e.marketDataProvider.GetHistoricalKlines(ctx, symbol, interval, start, end)
+		if err != nil {
+			e.log.Warn("Failed to load the bars after the warmup data source",
+				zap.String("symbol", symbol),
+				zap.Time("from", start),
+				zap.Time("to", end),
+				zap.Error(err),
+			)
+		}
+
+		missed = closedBars(missed, barLength, end)
+		sort.SliceStable(missed, func(i, j int) bool {
+			return missed[i].Time.Before(missed[j].Time)
+		})
+
+		for _, bar := range missed {
+			if bar.Time.After(last) {
+				bars = append(bars, bar)
+				last = bar.Time
+			}
+		}
+	}
+
+	return lastBars(bars, e.config.WarmupBars)
+}
+
+// closedBars returns the bars that closed by end.
+func closedBars(bars []types.MarketData, barLength time.Duration, end time.Time) []types.MarketData {
+	closed := make([]types.MarketData, 0, len(bars))
+
+	for _, bar := range bars {
+		if !bar.Time.Add(barLength).After(end) {
+			closed = append(closed, bar)
+		}
+	}
+
+	return closed
+}
+
+// lastBars returns the last count bars.
+func lastBars(bars []types.MarketData, count int) []types.MarketData {
+	if len(bars) > count {
+		return bars[len(bars)-count:]
+	}
+
+	return bars
+}
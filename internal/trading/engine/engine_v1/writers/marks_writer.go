@@ -71,7 +71,8 @@ func (w *MarksWriter) Initialize() error {
 			level TEXT,
 			title TEXT,
 			message TEXT,
-			category TEXT
+			category TEXT,
+			strategy_name TEXT
 		)
 	`)
 	if err != nil {
@@ -80,10 +81,11 @@ func (w *MarksWriter) Initialize() error {
 		return fmt.Errorf("failed to create marks table: %w", err)
 	}
 
-	// Load existing data from parquet file if it exists
+	// Load existing data from parquet file if it exists. Columns are matched by
+	// name so files written before strategy_name was added still load.
 	if _, err := os.Stat(w.outputPath); err == nil {
 		_, err = w.db.Exec(fmt.Sprintf(`
-			INSERT INTO marks
+			INSERT INTO marks BY NAME
 			SELECT * FROM read_parquet('%s')
 		`, w.outputPath))
 		if err != nil {
@@ -129,11 +131,11 @@ func (w *MarksWriter) Write(mark types.Mark) error {
 
 	_, err = w.db.Exec(`
 		INSERT INTO marks (id, market_data_id, signal_type, signal_name, signal_time, signal_symbol,
-			color, shape, level, title, message, category)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			color, shape, level, title, message, category, strategy_name)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, nextID, mark.MarketDataId, signalType, signalName, signalTime, signalSymbol,
 		string(mark.Color), string(mark.Shape), string(mark.Level),
-		mark.Title, mark.Message, mark.Category)
+		mark.Title, mark.Message, mark.Category, mark.StrategyName)
 	if err != nil {
 		return fmt.Errorf("failed to insert mark: %w", err)
 	}
@@ -1,6 +1,8 @@
 package writers
 
 import (
+	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -404,6 +406,49 @@ func (s *WritersTestSuite) TestMarksWriter_WriteAndCount() {
 	s.FileExists(outputPath)
 }
 
+func (s *WritersTestSuite) TestMarksWriter_LoadsFileWithoutStrategyName() {
+	outputPath := filepath.Join(s.tempDir, "marks.parquet")
+
+	// Write a marks file in the layout used before strategy_name was added
+	db, err := sql.Open("duckdb", ":memory:")
+	s.Require().NoError(err)
+	_, err = db.Exec(fmt.Sprintf(`
+		COPY (
+			SELECT 100 AS id, 'md-1' AS market_data_id, NULL::TEXT AS signal_type, NULL::TEXT AS signal_name,
+				NULL::TIMESTAMP AS signal_time, NULL::TEXT AS signal_symbol, 'green' AS color, 'circle' AS shape,
+				'info' AS level, 'Old' AS title, 'old mark' AS message, 'entry' AS category
+		) TO '%s' (FORMAT PARQUET)
+	`, outputPath))
+	s.Require().NoError(err)
+	s.Require().NoError(db.Close())
+
+	w := NewMarksWriter(outputPath)
+	s.Require().NoError(w.Initialize())
+
+	defer w.Close()
+
+	mark := types.Mark{
+		MarketDataId: "md-2",
+		Color:        types.MarkColorRed,
+		Shape:        types.MarkShapeSquare,
+		Level:        types.MarkLevelInfo,
+		Title:        "New",
+		Message:      "new mark",
+		Category:     "exit",
+		Signal:       optional.None[types.Signal](),
+		StrategyName: "Momentum",
+	}
+	s.Require().NoError(w.Write(mark))
+
+	count, err := w.GetMarkCount()
+	s.Require().NoError(err)
+	s.Equal(2, count)
+
+	var strategyName string
+	s.Require().NoError(w.db.QueryRow("SELECT strategy_name FROM marks WHERE title = 'New'").Scan(&strategyName))
+	s.Equal("Momentum", strategyName)
+}
+
 func (s *WritersTestSuite) TestMarksWriter_WriteWithSignal() {
 	outputPath := filepath.Join(s.tempDir, "marks.parquet")
 	w := NewMarksWriter(outputPath)
@@ -0,0 +1,22 @@
+package engine
+
+import "github.com/rxtech-lab/argo-trading/pkg/errors"
+
+// Errors returned by LiveTradingEngine.Run when the engine is not ready to
+// run. Callers can match them with errors.Is; the errors returned may wrap
+// them with more detail.
+var (
+	// ErrNotInitialized is returned when Run is called before Initialize.
+	ErrNotInitialized = errors.New(errors.ErrCodeBacktestInitFailed, "engine not initialized - call Initialize() first")
+	// ErrStrategyNotLoaded is returned when Run is called before a strategy was loaded.
+	ErrStrategyNotLoaded = errors.New(errors.ErrCodeBacktestInitFailed, "strategy not loaded - call LoadStrategy*() first")
+	// ErrNoMarketDataProvider is returned when no market data provider was set.
+	ErrNoMarketDataProvider = errors.New(errors.ErrCodeBacktestInitFailed, "market data provider not set - call SetMarketDataProvider() first")
+	// ErrNoTradingProvider is returned when no trading provider was set.
+	ErrNoTradingProvider = errors.New(errors.ErrCodeBacktestInitFailed, "trading provider not set - call SetTradingProvider() first")
+	// ErrNoSymbols is returned when the market data provider has no symbols.
+	ErrNoSymbols = errors.New(errors.ErrCodeBacktestInitFailed, "no symbols configured")
+	// ErrVersionMismatch is wrapped by the error returned when a strategy was
+	// compiled for an engine version incompatible with this one.
+	ErrVersionMismatch = errors.New(errors.ErrCodeVersionMismatch, "version mismatch")
+)
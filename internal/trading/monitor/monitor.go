@@ -0,0 +1,237 @@
+// Package monitor records the live state of a running trading engine from its
+// callbacks and serves it over HTTP, as JSON at /status and in the Prometheus
+// text format at /metrics.
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/trading/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// barRefreshInterval is the least time between two account reads caused by
+// bars. Reading the account costs exchange request weight, so bars closing
+// faster than this do not each trigger a read. Order events always do.
+const barRefreshInterval = 30 * time.Second
+
+// AccountSource reports the account figures the engine callbacks do not carry.
+// TradingSystemProvider satisfies it.
+type AccountSource interface {
+	GetAccountInfo() (types.AccountInfo, error)
+	GetOpenOrders() ([]types.ExecuteOrder, error)
+}
+
+// Status is the live state of the engine as served at /status.
+type Status struct {
+	// EngineStatus is the last status the engine reported. Empty until the
+	// engine reports one.
+	EngineStatus types.EngineStatus `json:"engine_status"`
+	// LastMarketDataTime is the time of the last bar the engine processed.
+	LastMarketDataTime time.Time `json:"last_market_data_time"`
+	// OpenOrders is the number of open orders on the trading provider.
+	OpenOrders int `json:"open_orders"`
+	// Equity is the account equity reported by the trading provider.
+	Equity float64 `json:"equity"`
+	// MarketDataStatus is the connection state of the market data provider.
+	MarketDataStatus types.ProviderConnectionStatus `json:"market_data_status"`
+	// TradingStatus is the connection state of the trading provider.
+	TradingStatus types.ProviderConnectionStatus `json:"trading_status"`
+	// AccountUpdated is when OpenOrders and Equity were last read.
+	AccountUpdated time.Time `json:"account_updated"`
+	// AccountError is the error of the last account read, if it failed. The
+	// account figures then keep the values of the last successful read.
+	AccountError string `json:"account_error,omitempty"`
+}
+
+// Monitor records the engine state from its callbacks. Account figures are
+// read from the AccountSource by Run on its own goroutine, so the callbacks
+// only take a lock and never wait on the broker. The source is guarded by mu.
+type Monitor struct {
+	mu      sync.RWMutex
+	status  Status
+	source  AccountSource
+	refresh chan struct{}
+	now     func() time.Time
+}
+
+// New creates a Monitor that reads account figures from source. A nil source
+// leaves OpenOrders and Equity at zero until SetSource is called.
+func New(source AccountSource) *Monitor {
+	return &Monitor{
+		mu:      sync.RWMutex{},
+		status:  Status{}, //nolint:exhaustruct // filled in by the callbacks
+		source:  source,
+		refresh: make(chan struct{}, 1),
+		now:     time.Now,
+	}
+}
+
+// SetSource replaces the account source and requests a read from it. The
+// engine only sets up the account strategies trade, such as the paper account,
+// once it starts, so the source is typically set from OnEngineStart.
+func (m *Monitor) SetSource(source AccountSource) {
+	m.mu.Lock()
+	m.source = source
+	m.mu.Unlock()
+
+	m.requestRefresh()
+}
+
+// Status returns a copy of the current state.
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.status
+}
+
+// Wrap returns callbacks that record the engine state before calling the
+// given callbacks. Callbacks the monitor does not need are passed through
+// unchanged; in particular the wallet callbacks stay unset when they are,
+// since registering them makes the engine query the broker on every bar.
+func (m *Monitor) Wrap(callbacks engine.LiveTradingCallbacks) engine.LiveTradingCallbacks {
+	wrapped := callbacks
+
+	onStatus := engine.OnStatusUpdateCallback(func(status types.EngineStatus) error {
+		m.update(func(s *Status) { s.EngineStatus = status })
+
+		if callbacks.OnStatusUpdate != nil {
+			return (*callbacks.OnStatusUpdate)(status)
+		}
+
+		return nil
+	})
+	wrapped.OnStatusUpdate = &onStatus
+
+	onMarketData := engine.OnMarketDataCallback(func(runID string, data types.MarketData) error {
+		refreshDue := false
+
+		m.update(func(s *Status) {
+			if data.Time.After(s.LastMarketDataTime) {
+				s.LastMarketDataTime = data.Time
+			}
+
+			refreshDue = m.now().Sub(s.AccountUpdated) >= barRefreshInterval
+		})
+
+		if refreshDue {
+			m.requestRefresh()
+		}
+
+		if callbacks.OnMarketData != nil {
+			return (*callbacks.OnMarketData)(runID, data)
+		}
+
+		return nil
+	})
+	wrapped.OnMarketData = &onMarketData
+
+	onProviderStatus := engine.OnProviderStatusChangeCallback(func(status types.ProviderStatusUpdate) error {
+		m.update(func(s *Status) {
+			s.MarketDataStatus = status.MarketDataStatus
+			s.TradingStatus = status.TradingStatus
+		})
+
+		if callbacks.OnProviderStatusChange != nil {
+			return (*callbacks.OnProviderStatusChange)(status)
+		}
+
+		return nil
+	})
+	wrapped.OnProviderStatusChange = &onProviderStatus
+
+	onOrderPlaced := engine.OnOrderPlacedCallback(func(order types.ExecuteOrder) error {
+		m.requestRefresh()
+
+		if callbacks.OnOrderPlaced != nil {
+			return (*callbacks.OnOrderPlaced)(order)
+		}
+
+		return nil
+	})
+	wrapped.OnOrderPlaced = &onOrderPlaced
+
+	onOrderFilled := engine.OnOrderFilledCallback(func(trade types.Trade) error {
+		m.requestRefresh()
+
+		if callbacks.OnOrderFilled != nil {
+			return (*callbacks.OnOrderFilled)(trade)
+		}
+
+		return nil
+	})
+	wrapped.OnOrderFilled = &onOrderFilled
+
+	return wrapped
+}
+
+// Run reads the account figures once and again after every order event, after
+// bars at most every barRefreshInterval, and when the source is set, until ctx
+// is cancelled. Events that arrive while a read is in progress are coalesced
+// into one more read.
+func (m *Monitor) Run(ctx context.Context) {
+	m.refreshAccount()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.refresh:
+			m.refreshAccount()
+		}
+	}
+}
+
+// requestRefresh asks Run for an account read without waiting for it.
+func (m *Monitor) requestRefresh() {
+	select {
+	case m.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// refreshAccount reads the equity and open orders from the account source, if
+// one is set.
+func (m *Monitor) refreshAccount() {
+	m.mu.RLock()
+	source := m.source
+	m.mu.RUnlock()
+
+	if source == nil {
+		return
+	}
+
+	info, err := source.GetAccountInfo()
+	if err != nil {
+		m.update(func(s *Status) { s.AccountError = err.Error() })
+
+		return
+	}
+
+	orders, err := source.GetOpenOrders()
+	if err != nil {
+		m.update(func(s *Status) { s.AccountError = err.Error() })
+
+		return
+	}
+
+	now := m.now()
+
+	m.update(func(s *Status) {
+		s.Equity = info.Equity
+		s.OpenOrders = len(orders)
+		s.AccountUpdated = now
+		s.AccountError = ""
+	})
+}
+
+// update applies fn to the state under the write lock.
+func (m *Monitor) update(fn func(s *Status)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fn(&m.status)
+}
@@ -0,0 +1,263 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/trading/engine"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type MonitorTestSuite struct {
+	suite.Suite
+	ctrl   *gomock.Controller
+	source *mocks.MockTradingSystemProvider
+}
+
+func TestMonitorSuite(t *testing.T) {
+	suite.Run(t, new(MonitorTestSuite))
+}
+
+func (s *MonitorTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.source = mocks.NewMockTradingSystemProvider(s.ctrl)
+}
+
+func (s *MonitorTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// mockEngine stands in for a running live engine: Run reports a status, the
+// provider connections and bars through the callbacks, then blocks until the
+// context is cancelled like a live stream does.
+type mockEngine struct {
+	bars []types.MarketData
+}
+
+func (e mockEngine) Run(ctx context.Context, callbacks engine.LiveTradingCallbacks) error {
+	_ = (*callbacks.OnProviderStatusChange)(types.ProviderStatusUpdate{
+		MarketDataStatus: types.ProviderStatusConnected,
+		TradingStatus:    types.ProviderStatusDisconnected,
+	})
+	_ = (*callbacks.OnStatusUpdate)(types.EngineStatusRunning)
+
+	for _, bar := range e.bars {
+		if err := (*callbacks.OnMarketData)("run-1", bar); err != nil {
+			return err
+		}
+	}
+
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+// startServer serves m on a local port until the test ends and returns its base URL.
+func (s *MonitorTestSuite) startServer(ctx context.Context, m *Monitor) string {
+	var listenConfig net.ListenConfig
+
+	listener, err := listenConfig.Listen(ctx, "tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+
+	served := make(chan error, 1)
+
+	go func() { served <- m.Serve(ctx, listener) }()
+
+	s.T().Cleanup(func() { s.NoError(<-served) })
+
+	return "http://" + listener.Addr().String()
+}
+
+func (s *MonitorTestSuite) get(url string) (string, http.Header) {
+	resp, err := http.Get(url)
+	s.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+
+	return string(body), resp.Header
+}
+
+func (s *MonitorTestSuite) TestStatus_RunningEngine() {
+	s.source.EXPECT().GetAccountInfo().Return(types.AccountInfo{Equity: 10250.5}, nil).AnyTimes()
+	s.source.EXPECT().GetOpenOrders().Return([]types.ExecuteOrder{{ID: "a"}, {ID: "b"}}, nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := New(s.source)
+	baseURL := s.startServer(ctx, m)
+
+	go m.Run(ctx)
+
+	lastBar := time.Date(2026, 3, 2, 10, 1, 0, 0, time.UTC)
+	eng := mockEngine{bars: []types.MarketData{
+		{Symbol: "BTCUSDT", Time: lastBar.Add(-time.Minute), Close: 100},
+		{Symbol: "BTCUSDT", Time: lastBar, Close: 101},
+	}}
+
+	engineDone := make(chan error, 1)
+
+	go func() { engineDone <- eng.Run(ctx, m.Wrap(engine.LiveTradingCallbacks{})) }()
+
+	// Wait for the last bar and the account read to reach the monitor
+	s.Eventually(func() bool {
+		status := m.Status()
+
+		return status.OpenOrders == 2 && status.LastMarketDataTime.Equal(lastBar)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	body, header := s.get(baseURL + "/status")
+	s.Equal("application/json", header.Get("Content-Type"))
+
+	var status map[string]any
+	s.Require().NoError(json.Unmarshal([]byte(body), &status))
+
+	s.Equal("running", status["engine_status"])
+	s.Equal(lastBar.Format(time.RFC3339), status["last_market_data_time"])
+	s.Equal(10250.5, status["equity"])
+	s.Equal("connected", status["market_data_status"])
+	s.Equal("disconnected", status["trading_status"])
+	s.NotContains(status, "account_error")
+
+	cancel()
+	s.ErrorIs(<-engineDone, context.Canceled)
+}
+
+func (s *MonitorTestSuite) TestMetrics() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := New(nil)
+	baseURL := s.startServer(ctx, m)
+
+	callbacks := m.Wrap(engine.LiveTradingCallbacks{})
+	s.Require().NoError((*callbacks.OnStatusUpdate)(types.EngineStatusHalted))
+	s.Require().NoError((*callbacks.OnMarketData)("run-1", types.MarketData{Symbol: "BTCUSDT", Time: time.Unix(1700000000, 0)}))
+	s.Require().NoError((*callbacks.OnProviderStatusChange)(types.ProviderStatusUpdate{
+		MarketDataStatus: types.ProviderStatusConnected,
+		TradingStatus:    types.ProviderStatusConnected,
+	}))
+
+	body, header := s.get(baseURL + "/metrics")
+
+	s.Contains(header.Get("Content-Type"), "text/plain")
+	s.Contains(body, "# TYPE argo_engine_status gauge\n")
+	s.Contains(body, "argo_engine_status{status=\"halted\"} 1\n")
+	s.Contains(body, "argo_last_market_data_timestamp_seconds 1700000000\n")
+	s.Contains(body, "argo_open_orders 0\n")
+	s.Contains(body, "argo_equity 0\n")
+	s.Contains(body, "argo_provider_connected{provider=\"market_data\"} 1\n")
+	s.Contains(body, "argo_provider_connected{provider=\"trading\"} 1\n")
+}
+
+func (s *MonitorTestSuite) TestWrap_CallsInnerCallbacks() {
+	var statuses []types.EngineStatus
+
+	var bars int
+
+	onStatus := engine.OnStatusUpdateCallback(func(status types.EngineStatus) error {
+		statuses = append(statuses, status)
+
+		return nil
+	})
+	onMarketData := engine.OnMarketDataCallback(func(_ string, _ types.MarketData) error {
+		bars++
+
+		return errors.New("stop")
+	})
+
+	m := New(nil)
+	callbacks := m.Wrap(engine.LiveTradingCallbacks{
+		OnStatusUpdate: &onStatus,
+		OnMarketData:   &onMarketData,
+	})
+
+	s.NoError((*callbacks.OnStatusUpdate)(types.EngineStatusRunning))
+	s.EqualError((*callbacks.OnMarketData)("run-1", types.MarketData{Time: time.Unix(60, 0)}), "stop")
+
+	s.Equal([]types.EngineStatus{types.EngineStatusRunning}, statuses)
+	s.Equal(1, bars)
+	s.Equal(types.EngineStatusRunning, m.Status().EngineStatus)
+	s.Equal(time.Unix(60, 0), m.Status().LastMarketDataTime)
+	s.Nil(callbacks.OnOrderChanged, "wallet callbacks stay unset")
+}
+
+func (s *MonitorTestSuite) TestRefreshAccount_KeepsFiguresOnError() {
+	gomock.InOrder(
+		s.source.EXPECT().GetAccountInfo().Return(types.AccountInfo{Equity: 500}, nil),
+		s.source.EXPECT().GetOpenOrders().Return([]types.ExecuteOrder{{ID: "a"}}, nil),
+		s.source.EXPECT().GetAccountInfo().Return(types.AccountInfo{}, errors.New("broker down")),
+	)
+
+	m := New(s.source)
+
+	m.refreshAccount()
+	m.refreshAccount()
+
+	status := m.Status()
+	s.Equal(500.0, status.Equity)
+	s.Equal(1, status.OpenOrders)
+	s.Equal("broker down", status.AccountError)
+}
+
+func (s *MonitorTestSuite) TestSetSource_ReadsTheNewSource() {
+	s.source.EXPECT().GetAccountInfo().Return(types.AccountInfo{Equity: 1000}, nil).AnyTimes()
+	s.source.EXPECT().GetOpenOrders().Return(nil, nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := New(nil)
+
+	go m.Run(ctx)
+
+	// The paper account only exists once the engine has started
+	m.SetSource(s.source)
+
+	s.Eventually(func() bool {
+		return m.Status().Equity == 1000
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func (s *MonitorTestSuite) TestBars_RefreshAtMostEveryInterval() {
+	s.source.EXPECT().GetAccountInfo().Return(types.AccountInfo{Equity: 1000}, nil)
+	s.source.EXPECT().GetOpenOrders().Return(nil, nil)
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	m := New(s.source)
+	m.now = func() time.Time { return now }
+	m.refreshAccount()
+
+	callbacks := m.Wrap(engine.LiveTradingCallbacks{})
+	bar := func() {
+		s.Require().NoError((*callbacks.OnMarketData)("run-1", types.MarketData{Symbol: "BTCUSDT", Time: now}))
+	}
+
+	now = now.Add(time.Second)
+	bar()
+	s.Empty(m.refresh, "a bar right after a read does not read again")
+
+	now = now.Add(barRefreshInterval)
+	bar()
+	s.Len(m.refresh, 1, "a bar after the interval requests a read")
+
+	// Order events always request a read
+	<-m.refresh
+	s.Require().NoError((*callbacks.OnOrderPlaced)(types.ExecuteOrder{}))
+	s.Len(m.refresh, 1)
+}
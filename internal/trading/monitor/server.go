@@ -0,0 +1,128 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// shutdownTimeout bounds how long the server waits for in-flight requests
+// once its context is cancelled.
+const shutdownTimeout = 5 * time.Second
+
+// Handler returns the HTTP handler serving /status and /metrics.
+func (m *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", m.serveStatus)
+	mux.HandleFunc("GET /metrics", m.serveMetrics)
+
+	return mux
+}
+
+// ListenAndServe listens on addr and serves the monitor until ctx is cancelled.
+func (m *Monitor) ListenAndServe(ctx context.Context, addr string) error {
+	var listenConfig net.ListenConfig
+
+	listener, err := listenConfig.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	return m.Serve(ctx, listener)
+}
+
+// Serve serves the monitor on listener until ctx is cancelled, then shuts the
+// server down. It returns nil after a shutdown caused by ctx.
+func (m *Monitor) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{ //nolint:exhaustruct // defaults for the remaining settings
+		Handler:           m.Handler(),
+		ReadHeaderTimeout: shutdownTimeout,
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+
+			_ = server.Shutdown(shutdownCtx)
+		case <-done:
+		}
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+
+	return nil
+}
+
+// serveStatus writes the current state as JSON.
+func (m *Monitor) serveStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(m.Status())
+}
+
+// serveMetrics writes the current state in the Prometheus text format.
+func (m *Monitor) serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_, _ = w.Write([]byte(formatMetrics(m.Status())))
+}
+
+// formatMetrics renders status in the Prometheus text exposition format.
+func formatMetrics(status Status) string {
+	var b strings.Builder
+
+	writeGauge := func(name, help string, samples ...string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+
+		for _, sample := range samples {
+			fmt.Fprintf(&b, "%s%s\n", name, sample)
+		}
+	}
+
+	var engineStatus []string
+	if status.EngineStatus != "" {
+		engineStatus = append(engineStatus, fmt.Sprintf("{status=%q} 1", status.EngineStatus))
+	}
+
+	writeGauge("argo_engine_status", "Current engine status.", engineStatus...)
+
+	var lastMarketData int64
+	if !status.LastMarketDataTime.IsZero() {
+		lastMarketData = status.LastMarketDataTime.Unix()
+	}
+
+	writeGauge("argo_last_market_data_timestamp_seconds", "Unix time of the last processed bar.",
+		fmt.Sprintf(" %d", lastMarketData))
+	writeGauge("argo_open_orders", "Number of open orders on the trading provider.",
+		fmt.Sprintf(" %d", status.OpenOrders))
+	writeGauge("argo_equity", "Account equity reported by the trading provider.",
+		fmt.Sprintf(" %g", status.Equity))
+	writeGauge("argo_provider_connected", "Whether the provider is connected.",
+		fmt.Sprintf("{provider=\"market_data\"} %d", connected(status.MarketDataStatus)),
+		fmt.Sprintf("{provider=\"trading\"} %d", connected(status.TradingStatus)))
+
+	return b.String()
+}
+
+// connected converts a connection status to a gauge value.
+func connected(status types.ProviderConnectionStatus) int {
+	if status == types.ProviderStatusConnected {
+		return 1
+	}
+
+	return 0
+}
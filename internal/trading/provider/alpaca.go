@@ -0,0 +1,1177 @@
+package tradingprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/internal/utils"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// AlpacaDecimalPrecision is the default quantity precision. Alpaca accepts
+	// fractional share quantities with up to 9 decimal places.
+	AlpacaDecimalPrecision = 9
+	// AlpacaLiveBaseURL is the Alpaca trading REST API endpoint for real-funds accounts.
+	AlpacaLiveBaseURL = "https://api.alpaca.markets"
+	// AlpacaPaperBaseURL is the Alpaca trading REST API endpoint for paper accounts.
+	AlpacaPaperBaseURL = "https://paper-api.alpaca.markets"
+	// AlpacaDataURL is the Alpaca market data REST API endpoint.
+	AlpacaDataURL = "https://data.alpaca.markets"
+	// alpacaActivitiesPageSize is the number of fills requested per activities page.
+	alpacaActivitiesPageSize = 100
+	// alpacaCashAsset is the asset the cash balance of an Alpaca account is reported as.
+	alpacaCashAsset = "USD"
+	// alpacaMaxClientOrderIDLength is the longest client order ID Alpaca accepts.
+	alpacaMaxClientOrderIDLength = 128
+)
+
+// Alpaca order statuses as returned by the orders endpoints.
+const (
+	alpacaOrderStatusNew                = "new"
+	alpacaOrderStatusAccepted           = "accepted"
+	alpacaOrderStatusPendingNew         = "pending_new"
+	alpacaOrderStatusAcceptedForBidding = "accepted_for_bidding"
+	alpacaOrderStatusPendingCancel      = "pending_cancel"
+	alpacaOrderStatusPendingReplace     = "pending_replace"
+	alpacaOrderStatusHeld               = "held"
+	alpacaOrderStatusCalculated         = "calculated"
+	alpacaOrderStatusStopped            = "stopped"
+	alpacaOrderStatusDoneForDay         = "done_for_day"
+	alpacaOrderStatusPartiallyFilled    = "partially_filled"
+	alpacaOrderStatusFilled             = "filled"
+	alpacaOrderStatusCanceled           = "canceled"
+	alpacaOrderStatusReplaced           = "replaced"
+	alpacaOrderStatusRejected           = "rejected"
+	alpacaOrderStatusSuspended          = "suspended"
+	alpacaOrderStatusExpired            = "expired"
+)
+
+// AlpacaOrderRequest contains the parameters of a new order. Numeric values are
+// decimal strings, as Alpaca expects them; empty values are omitted.
+type AlpacaOrderRequest struct {
+	Symbol       string `json:"symbol"`
+	Qty          string `json:"qty"`
+	Side         string `json:"side"`
+	Type         string `json:"type"`
+	TimeInForce  string `json:"time_in_force"`
+	LimitPrice   string `json:"limit_price,omitempty"`
+	StopPrice    string `json:"stop_price,omitempty"`
+	TrailPrice   string `json:"trail_price,omitempty"`
+	TrailPercent string `json:"trail_percent,omitempty"`
+	// ClientOrderID is a unique ID the order can be looked up by.
+	ClientOrderID string `json:"client_order_id,omitempty"`
+}
+
+// AlpacaOrder is an order returned by the orders endpoints.
+type AlpacaOrder struct {
+	ID             string    `json:"id"`
+	ClientOrderID  string    `json:"client_order_id"`
+	Symbol         string    `json:"symbol"`
+	CreatedAt      time.Time `json:"created_at"`
+	Qty            string    `json:"qty"`
+	FilledQty      string    `json:"filled_qty"`
+	FilledAvgPrice string    `json:"filled_avg_price"`
+	Type           string    `json:"type"`
+	Side           string    `json:"side"`
+	TimeInForce    string    `json:"time_in_force"`
+	LimitPrice     string    `json:"limit_price"`
+	StopPrice      string    `json:"stop_price"`
+	TrailPrice     string    `json:"trail_price"`
+	TrailPercent   string    `json:"trail_percent"`
+	Status         string    `json:"status"`
+}
+
+// AlpacaAccount is the account returned by the account endpoint.
+type AlpacaAccount struct {
+	Cash          string `json:"cash"`
+	Equity        string `json:"equity"`
+	BuyingPower   string `json:"buying_power"`
+	InitialMargin string `json:"initial_margin"`
+}
+
+// AlpacaPosition is an open position returned by the positions endpoint.
+// Qty is negative for short positions.
+type AlpacaPosition struct {
+	Symbol        string `json:"symbol"`
+	Qty           string `json:"qty"`
+	Side          string `json:"side"`
+	CostBasis     string `json:"cost_basis"`
+	UnrealizedPnL string `json:"unrealized_pl"`
+}
+
+// AlpacaFillActivity is a fill returned by the account activities endpoint.
+type AlpacaFillActivity struct {
+	ID              string    `json:"id"`
+	TransactionTime time.Time `json:"transaction_time"`
+	OrderID         string    `json:"order_id"`
+	Symbol          string    `json:"symbol"`
+	Side            string    `json:"side"`
+	Qty             string    `json:"qty"`
+	Price           string    `json:"price"`
+}
+
+// AlpacaActivitiesRequest selects a page of fill activities, oldest first.
+type AlpacaActivitiesRequest struct {
+	// After and Until limit the fills to a time range. Zero times leave the range open.
+	After time.Time
+	Until time.Time
+	// PageToken is the ID of the last fill of the previous page.
+	PageToken string
+	PageSize  int
+}
+
+// AlpacaClient abstracts the Alpaca trading and market data REST APIs for testing.
+type AlpacaClient interface {
+	// PlaceOrder submits an order and returns it as Alpaca accepted it.
+	PlaceOrder(ctx context.Context, request AlpacaOrderRequest) (*AlpacaOrder, error)
+	// GetOrder returns an order by its Alpaca order ID.
+	GetOrder(ctx context.Context, orderID string) (*AlpacaOrder, error)
+	// GetOrderByClientOrderID returns an order by the client order ID it was placed with.
+	GetOrderByClientOrderID(ctx context.Context, clientOrderID string) (*AlpacaOrder, error)
+	// ListOpenOrders returns the open orders, oldest first.
+	ListOpenOrders(ctx context.Context) ([]AlpacaOrder, error)
+	// CancelOrder cancels an open order by its Alpaca order ID.
+	CancelOrder(ctx context.Context, orderID string) error
+	// CancelAllOrders cancels every open order.
+	CancelAllOrders(ctx context.Context) error
+	// GetAccount returns the account balances.
+	GetAccount(ctx context.Context) (*AlpacaAccount, error)
+	// GetPositions returns the open positions.
+	GetPositions(ctx context.Context) ([]AlpacaPosition, error)
+	// GetFillActivities returns a page of fills, oldest first.
+	GetFillActivities(ctx context.Context, request AlpacaActivitiesRequest) ([]AlpacaFillActivity, error)
+	// GetLatestPrices returns the price of the latest trade of each symbol.
+	// Symbols without a trade are omitted.
+	GetLatestPrices(ctx context.Context, symbols []string) (map[string]float64, error)
+}
+
+// alpacaErrorResponse is the body of a failed Alpaca REST request.
+type alpacaErrorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// realAlpacaClient calls the Alpaca REST APIs over HTTP, authenticating with
+// the key ID and secret key headers.
+type realAlpacaClient struct {
+	httpClient *http.Client
+	baseURL    string
+	dataURL    string
+	apiKey     string
+	secretKey  string
+}
+
+func newRealAlpacaClient(config AlpacaProviderConfig, paper bool) *realAlpacaClient {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = AlpacaLiveBaseURL
+		if paper {
+			baseURL = AlpacaPaperBaseURL
+		}
+	}
+
+	dataURL := config.DataURL
+	if dataURL == "" {
+		dataURL = AlpacaDataURL
+	}
+
+	return &realAlpacaClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second}, //nolint:exhaustruct // default transport
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		dataURL:    strings.TrimRight(dataURL, "/"),
+		apiKey:     config.ApiKey,
+		secretKey:  config.SecretKey,
+	}
+}
+
+func (c *realAlpacaClient) PlaceOrder(ctx context.Context, request AlpacaOrderRequest) (*AlpacaOrder, error) {
+	var order AlpacaOrder
+	if err := c.request(ctx, http.MethodPost, c.baseURL+"/v2/orders", request, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (c *realAlpacaClient) GetOrder(ctx context.Context, orderID string) (*AlpacaOrder, error) {
+	var order AlpacaOrder
+	if err := c.request(ctx, http.MethodGet, c.baseURL+"/v2/orders/"+url.PathEscape(orderID), nil, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (c *realAlpacaClient) GetOrderByClientOrderID(ctx context.Context, clientOrderID string) (*AlpacaOrder, error) {
+	params := url.Values{}
+	params.Set("client_order_id", clientOrderID)
+
+	var order AlpacaOrder
+	if err := c.request(ctx, http.MethodGet, c.baseURL+"/v2/orders:by_client_order_id?"+params.Encode(), nil, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (c *realAlpacaClient) ListOpenOrders(ctx context.Context) ([]AlpacaOrder, error) {
+	params := url.Values{}
+	params.Set("status", "open")
+	params.Set("direction", "asc")
+	params.Set("limit", "500")
+
+	var orders []AlpacaOrder
+	if err := c.request(ctx, http.MethodGet, c.baseURL+"/v2/orders?"+params.Encode(), nil, &orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (c *realAlpacaClient) CancelOrder(ctx context.Context, orderID string) error {
+	return c.request(ctx, http.MethodDelete, c.baseURL+"/v2/orders/"+url.PathEscape(orderID), nil, nil)
+}
+
+func (c *realAlpacaClient) CancelAllOrders(ctx context.Context) error {
+	return c.request(ctx, http.MethodDelete, c.baseURL+"/v2/orders", nil, nil)
+}
+
+func (c *realAlpacaClient) GetAccount(ctx context.Context) (*AlpacaAccount, error) {
+	var account AlpacaAccount
+	if err := c.request(ctx, http.MethodGet, c.baseURL+"/v2/account", nil, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (c *realAlpacaClient) GetPositions(ctx context.Context) ([]AlpacaPosition, error) {
+	var positions []AlpacaPosition
+	if err := c.request(ctx, http.MethodGet, c.baseURL+"/v2/positions", nil, &positions); err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+func (c *realAlpacaClient) GetFillActivities(ctx context.Context, request AlpacaActivitiesRequest) ([]AlpacaFillActivity, error) {
+	params := url.Values{}
+	params.Set("direction", "asc")
+	params.Set("page_size", strconv.Itoa(request.PageSize))
+
+	if !request.After.IsZero() {
+		params.Set("after", request.After.UTC().Format(time.RFC3339Nano))
+	}
+
+	if !request.Until.IsZero() {
+		params.Set("until", request.Until.UTC().Format(time.RFC3339Nano))
+	}
+
+	if request.PageToken != "" {
+		params.Set("page_token", request.PageToken)
+	}
+
+	var activities []AlpacaFillActivity
+	if err := c.request(ctx, http.MethodGet, c.baseURL+"/v2/account/activities/FILL?"+params.Encode(), nil, &activities); err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+func (c *realAlpacaClient) GetLatestPrices(ctx context.Context, symbols []string) (map[string]float64, error) {
+	params := url.Values{}
+	params.Set("symbols", strings.Join(symbols, ","))
+
+	var result struct {
+		Trades map[string]struct {
+			Price float64 `json:"p"`
+		} `json:"trades"`
+	}
+
+	if err := c.request(ctx, http.MethodGet, c.dataURL+"/v2/stocks/trades/latest?"+params.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]float64, len(result.Trades))
+	for symbol, trade := range result.Trades {
+		prices[symbol] = trade.Price
+	}
+
+	return prices, nil
+}
+
+// request sends an authenticated request with an optional JSON body and
+// decodes the JSON response into result unless it is nil.
+func (c *realAlpacaClient) request(ctx context.Context, method, endpoint string, body any, result any) error {
+	var reader io.Reader
+
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode alpaca request: %w", err)
+		}
+
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create alpaca request: %w", err)
+	}
+
+	req.Header.Set("APCA-API-KEY-ID", c.apiKey)
+	req.Header.Set("APCA-API-SECRET-KEY", c.secretKey)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("alpaca request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read alpaca response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr alpacaErrorResponse
+		if json.Unmarshal(payload, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("alpaca api error (status %d, code %d): %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+		}
+
+		return fmt.Errorf("alpaca api error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(payload)))
+	}
+
+	if result == nil || len(payload) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(payload, result); err != nil {
+		return fmt.Errorf("failed to parse alpaca response: %w", err)
+	}
+
+	return nil
+}
+
+// AlpacaTradingSystemProvider implements TradingSystemProvider using the Alpaca
+// trading API for US equities. Like the Binance provider it is stateless - all
+// data is fetched from Alpaca - apart from the OCO groups it emulates and the
+// Alpaca IDs of the orders it placed. Orders are placed with their order ID as
+// the client order ID and looked up by it.
+// Quantities are rounded to the decimal precision of their symbol, so
+// fractionable symbols trade fractional shares and symbols configured with a
+// precision of 0 trade whole shares.
+type AlpacaTradingSystemProvider struct {
+	client                 AlpacaClient
+	decimalPrecision       int
+	symbolDecimalPrecision map[string]int
+	onStatusChange         OnStatusChange
+	oco                    *OCOEmulator
+	// placedOrderIDs maps the Alpaca ID of each order placed to the ID it was placed with.
+	placedOrderIDs map[string]string
+	// clientOrderIDs holds the client order IDs orders were placed with.
+	clientOrderIDs map[string]bool
+	ordersMu       sync.Mutex
+}
+
+// NewAlpacaTradingSystemProvider creates a new Alpaca trading system. Orders of
+// a paper provider go to the Alpaca paper trading environment.
+func NewAlpacaTradingSystemProvider(config AlpacaProviderConfig, paper bool) (*AlpacaTradingSystemProvider, error) {
+	debugLog.Info("NewAlpacaTradingSystemProvider",
+		zap.Bool("hasApiKey", config.ApiKey != ""),
+		zap.Bool("hasSecretKey", config.SecretKey != ""),
+		zap.String("baseURL", config.BaseURL),
+		zap.Bool("paper", paper),
+	)
+
+	provider := newAlpacaTradingSystemProviderWithClient(newRealAlpacaClient(config, paper))
+	provider.symbolDecimalPrecision = config.SymbolDecimalPrecision
+	provider.oco = NewOCOEmulator(provider, DefaultOCOPollInterval)
+
+	return provider, nil
+}
+
+// newAlpacaTradingSystemProviderWithClient creates a new Alpaca trading system with a custom client.
+// This is used for testing with mock clients. Emulated OCO groups are not watched
+// in the background; tests call CheckFills on the emulator directly.
+func newAlpacaTradingSystemProviderWithClient(client AlpacaClient) *AlpacaTradingSystemProvider {
+	provider := &AlpacaTradingSystemProvider{
+		client:                 client,
+		decimalPrecision:       AlpacaDecimalPrecision,
+		symbolDecimalPrecision: nil,
+		onStatusChange:         nil,
+		oco:                    nil,
+		placedOrderIDs:         map[string]string{},
+		clientOrderIDs:         map[string]bool{},
+		ordersMu:               sync.Mutex{},
+	}
+	provider.oco = NewOCOEmulator(provider, 0)
+
+	return provider
+}
+
+// PlaceOrder places a single order on Alpaca. Fractional quantities are sent
+// as day orders, the only time in force Alpaca accepts for them.
+func (a *AlpacaTradingSystemProvider) PlaceOrder(order types.ExecuteOrder) error {
+	var side string
+
+	switch order.Side {
+	case types.PurchaseTypeBuy:
+		side = "buy"
+	case types.PurchaseTypeSell:
+		side = "sell"
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unsupported order side: %s", order.Side)
+	}
+
+	if order.Quantity <= 0 {
+		return errors.New(errors.ErrCodeInvalidParameter, "order quantity must be greater than zero")
+	}
+
+	precision := a.precision(order.Symbol)
+
+	roundedQuantity := utils.RoundToDecimalPrecision(order.Quantity, precision)
+	if roundedQuantity <= 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter,
+			"order quantity %.9f is too small after rounding to %d decimal places",
+			order.Quantity, precision)
+	}
+
+	request := AlpacaOrderRequest{
+		Symbol:        order.Symbol,
+		Qty:           strconv.FormatFloat(roundedQuantity, 'f', -1, 64),
+		Side:          side,
+		Type:          "",
+		TimeInForce:   toAlpacaTimeInForce(order.TimeInForce),
+		LimitPrice:    "",
+		StopPrice:     "",
+		TrailPrice:    "",
+		TrailPercent:  "",
+		ClientOrderID: "",
+	}
+
+	// The order ID is sent as the client order ID so the order can be looked up by it
+	if len(order.ID) <= alpacaMaxClientOrderIDLength {
+		request.ClientOrderID = order.ID
+	}
+
+	switch order.OrderType {
+	case types.OrderTypeMarket:
+		request.Type = "market"
+	case types.OrderTypeLimit:
+		request.Type = "limit"
+		request.LimitPrice = strconv.FormatFloat(order.Price, 'f', -1, 64)
+	case types.OrderTypeStopMarket:
+		request.Type = "stop"
+		request.StopPrice = strconv.FormatFloat(order.StopPrice, 'f', -1, 64)
+	case types.OrderTypeStopLimit:
+		request.Type = "stop_limit"
+		request.StopPrice = strconv.FormatFloat(order.StopPrice, 'f', -1, 64)
+		request.LimitPrice = strconv.FormatFloat(order.Price, 'f', -1, 64)
+	case types.OrderTypeTrailingStop:
+		request.Type = "trailing_stop"
+		if order.TrailPercent {
+			request.TrailPercent = strconv.FormatFloat(order.TrailOffset, 'f', -1, 64)
+		} else {
+			request.TrailPrice = strconv.FormatFloat(order.TrailOffset, 'f', -1, 64)
+		}
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unsupported order type: %s", order.OrderType)
+	}
+
+	if roundedQuantity != math.Trunc(roundedQuantity) {
+		request.TimeInForce = "day"
+	}
+
+	placed, err := a.client.PlaceOrder(context.Background(), request)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to place order on Alpaca", err)
+	}
+
+	a.rememberOrder(request.ClientOrderID, placed)
+
+	return nil
+}
+
+// PlaceMultipleOrders places multiple orders sequentially.
+func (a *AlpacaTradingSystemProvider) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	for _, order := range orders {
+		if err := a.PlaceOrder(order); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlaceOCOOrder places an OCO group. The legs are placed as separate orders and
+// the remaining leg is cancelled once the other one fills.
+func (a *AlpacaTradingSystemProvider) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	return a.oco.PlaceOCOOrder(entry, takeProfit, stopLoss)
+}
+
+// PlaceBracketOrder implements TradingSystemProvider through the emulated OCO
+// groups of PlaceOCOOrder.
+func (a *AlpacaTradingSystemProvider) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	return PlaceBracketOrderAsOCO(a, entry, stopLoss, takeProfit)
+}
+
+// GetPositions returns the open positions, sorted by symbol.
+func (a *AlpacaTradingSystemProvider) GetPositions() ([]types.Position, error) {
+	alpacaPositions, err := a.client.GetPositions(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get positions from Alpaca", err)
+	}
+
+	positions := make([]types.Position, 0, len(alpacaPositions))
+
+	for _, ap := range alpacaPositions {
+		positions = append(positions, convertAlpacaPosition(ap))
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Symbol < positions[j].Symbol
+	})
+
+	return positions, nil
+}
+
+// GetPosition returns the position for a specific symbol.
+func (a *AlpacaTradingSystemProvider) GetPosition(symbol string) (types.Position, error) {
+	positions, err := a.GetPositions()
+	if err != nil {
+		return types.Position{}, err
+	}
+
+	for _, pos := range positions {
+		if pos.Symbol == symbol {
+			return pos, nil
+		}
+	}
+
+	return convertAlpacaPosition(AlpacaPosition{Symbol: symbol, Qty: "0", Side: "long", CostBasis: "0", UnrealizedPnL: "0"}), nil
+}
+
+// CancelOrder cancels an order by its Alpaca order ID.
+func (a *AlpacaTradingSystemProvider) CancelOrder(orderID string) error {
+	if err := a.client.CancelOrder(context.Background(), orderID); err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to cancel order on Alpaca", err)
+	}
+
+	return nil
+}
+
+// CancelAllOrders cancels all open orders.
+func (a *AlpacaTradingSystemProvider) CancelAllOrders() error {
+	if err := a.client.CancelAllOrders(context.Background()); err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to cancel orders on Alpaca", err)
+	}
+
+	return nil
+}
+
+// CancelOrdersForSymbol cancels the open orders of a symbol one by one.
+func (a *AlpacaTradingSystemProvider) CancelOrdersForSymbol(symbol string) error {
+	openOrders, err := a.GetOpenOrders()
+	if err != nil {
+		return err
+	}
+
+	for _, order := range openOrders {
+		if order.Symbol != symbol {
+			continue
+		}
+
+		if err := a.CancelOrder(order.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetOrderStatus returns the status of an order by the ID it was placed with
+// or by its Alpaca order ID.
+func (a *AlpacaTradingSystemProvider) GetOrderStatus(orderID string) (types.OrderStatus, error) {
+	var (
+		order *AlpacaOrder
+		err   error
+	)
+
+	if a.isClientOrderID(orderID) {
+		order, err = a.client.GetOrderByClientOrderID(context.Background(), orderID)
+	} else {
+		order, err = a.client.GetOrder(context.Background(), orderID)
+	}
+
+	if err != nil {
+		return types.OrderStatusFailed, errors.Wrap(errors.ErrCodeOrderFailed, "failed to query order from Alpaca", err)
+	}
+
+	return mapAlpacaOrderStatus(order.Status), nil
+}
+
+// GetAccountInfo returns the current account state. The unrealized P&L is the
+// sum over the open positions.
+func (a *AlpacaTradingSystemProvider) GetAccountInfo() (types.AccountInfo, error) {
+	ctx := context.Background()
+
+	account, err := a.client.GetAccount(ctx)
+	if err != nil {
+		return types.AccountInfo{}, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get account from Alpaca", err)
+	}
+
+	positions, err := a.client.GetPositions(ctx)
+	if err != nil {
+		return types.AccountInfo{}, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get positions from Alpaca", err)
+	}
+
+	var unrealizedPnL float64
+
+	for _, position := range positions {
+		pnl, _ := strconv.ParseFloat(position.UnrealizedPnL, 64)
+		unrealizedPnL += pnl
+	}
+
+	cash, _ := strconv.ParseFloat(account.Cash, 64)
+	equity, _ := strconv.ParseFloat(account.Equity, 64)
+	buyingPower, _ := strconv.ParseFloat(account.BuyingPower, 64)
+	initialMargin, _ := strconv.ParseFloat(account.InitialMargin, 64)
+
+	return types.AccountInfo{
+		Balance:       cash,
+		Equity:        equity,
+		BuyingPower:   buyingPower,
+		RealizedPnL:   0, // Not reported by the account endpoint
+		UnrealizedPnL: unrealizedPnL,
+		TotalFees:     0, // Alpaca charges no commission on equities
+		MarginUsed:    initialMargin,
+		FreeMargin:    math.Max(equity-initialMargin, 0),
+		Balances:      nil,
+	}, nil
+}
+
+// GetAssets returns the cash balance as USD and the quantity of every open
+// position. Zero-quantity assets are omitted.
+func (a *AlpacaTradingSystemProvider) GetAssets() ([]types.Asset, error) {
+	account, err := a.client.GetAccount(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get account from Alpaca", err)
+	}
+
+	positions, err := a.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]types.Asset, 0, len(positions)+1)
+
+	if cash, _ := strconv.ParseFloat(account.Cash, 64); cash != 0 {
+		assets = append(assets, types.Asset{Symbol: alpacaCashAsset, Quantity: cash, BaseCurrency: "", BaseCurrencyValue: nil})
+	}
+
+	for _, position := range positions {
+		quantity := position.TotalLongPositionQuantity - position.TotalShortPositionQuantity
+		if quantity == 0 {
+			continue
+		}
+
+		assets = append(assets, types.Asset{Symbol: position.Symbol, Quantity: quantity, BaseCurrency: "", BaseCurrencyValue: nil})
+	}
+
+	return assets, nil
+}
+
+// GetPrices returns the price of the latest trade of each requested symbol. An
+// empty list returns the prices of the symbols with an open position, since
+// Alpaca cannot list the prices of every tradable symbol at once.
+func (a *AlpacaTradingSystemProvider) GetPrices(symbols []string) (map[string]float64, error) {
+	if len(symbols) == 0 {
+		positions, err := a.GetPositions()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, position := range positions {
+			symbols = append(symbols, position.Symbol)
+		}
+
+		if len(symbols) == 0 {
+			return map[string]float64{}, nil
+		}
+	}
+
+	prices, err := a.client.GetLatestPrices(context.Background(), symbols)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get prices from Alpaca", err)
+	}
+
+	out := make(map[string]float64, len(prices))
+
+	for symbol, price := range prices {
+		if price > 0 {
+			out[symbol] = price
+		}
+	}
+
+	return out, nil
+}
+
+// GetOpenOrders returns all open orders, oldest first.
+func (a *AlpacaTradingSystemProvider) GetOpenOrders() ([]types.ExecuteOrder, error) {
+	alpacaOrders, err := a.client.ListOpenOrders(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get open orders from Alpaca", err)
+	}
+
+	sort.SliceStable(alpacaOrders, func(i, j int) bool {
+		return alpacaOrders[i].CreatedAt.Before(alpacaOrders[j].CreatedAt)
+	})
+
+	orders := make([]types.ExecuteOrder, 0, len(alpacaOrders))
+
+	for _, ao := range alpacaOrders {
+		order, convertErr := convertAlpacaOrderToExecuteOrder(ao)
+		if convertErr != nil {
+			continue // Skip orders that can't be converted
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// GetTrades returns the fills of the account with optional filtering, oldest
+// first. With a limit, the most recent matching fills are returned.
+func (a *AlpacaTradingSystemProvider) GetTrades(filter types.TradeFilter) ([]types.Trade, error) {
+	trades := make([]types.Trade, 0)
+	cursor := ""
+
+	for {
+		page, err := a.GetTradesPage(types.TradeFilter{
+			Symbol:    filter.Symbol,
+			StartTime: filter.StartTime,
+			EndTime:   filter.EndTime,
+			Limit:     alpacaActivitiesPageSize,
+			Cursor:    cursor,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		trades = append(trades, page.Trades...)
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	if filter.Limit > 0 && len(trades) > filter.Limit {
+		trades = trades[len(trades)-filter.Limit:]
+	}
+
+	return trades, nil
+}
+
+// GetTradesPage returns one page of fills, oldest first. Alpaca pages fills by
+// the ID of the last fill of the previous page, which is used as the cursor.
+// The symbol filter is applied to the page, so a page can hold fewer trades
+// than the limit while more pages follow. Trades of orders the provider placed
+// carry the ID the order was placed with.
+func (a *AlpacaTradingSystemProvider) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	pageSize := filter.Limit
+	if pageSize <= 0 {
+		pageSize = alpacaActivitiesPageSize
+	}
+
+	activities, err := a.client.GetFillActivities(context.Background(), AlpacaActivitiesRequest{
+		After:     filter.StartTime,
+		Until:     filter.EndTime,
+		PageToken: filter.Cursor,
+		PageSize:  pageSize,
+	})
+	if err != nil {
+		return types.TradePage{}, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get trades from Alpaca", err)
+	}
+
+	page := types.TradePage{Trades: make([]types.Trade, 0, len(activities)), NextCursor: ""}
+
+	for _, activity := range activities {
+		if filter.Symbol != "" && activity.Symbol != filter.Symbol {
+			continue
+		}
+
+		trade := convertAlpacaFillToTrade(activity)
+		trade.Order.OrderID = a.placedOrderID(activity.OrderID)
+		page.Trades = append(page.Trades, trade)
+	}
+
+	if len(activities) == pageSize {
+		page.NextCursor = activities[len(activities)-1].ID
+	}
+
+	return page, nil
+}
+
+// GetMaxBuyQuantity returns the maximum quantity that can be bought at the
+// given price with the account's buying power, rounded down to the decimal
+// precision of the symbol. Alpaca charges no commission on equities.
+func (a *AlpacaTradingSystemProvider) GetMaxBuyQuantity(symbol string, price float64) (float64, error) {
+	if price <= 0 {
+		return 0, errors.New(errors.ErrCodeInvalidParameter, "price must be greater than zero")
+	}
+
+	accountInfo, err := a.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.RoundToDecimalPrecision(accountInfo.BuyingPower/price, a.precision(symbol)), nil
+}
+
+// GetMaxSellQuantity returns the long position quantity of a symbol.
+func (a *AlpacaTradingSystemProvider) GetMaxSellQuantity(symbol string) (float64, error) {
+	position, err := a.GetPosition(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	return position.TotalLongPositionQuantity, nil
+}
+
+// CalculatePositionSize sizes a position by the risk taken to its stop, using
+// the account equity reported by Alpaca, rounded down to the decimal precision
+// of the symbol.
+func (a *AlpacaTradingSystemProvider) CalculatePositionSize(symbol string, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	accountInfo, err := a.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	quantity, err := RiskPositionSize(accountInfo.Equity, entryPrice, stopPrice, riskFraction)
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.RoundToDecimalPrecision(quantity, a.precision(symbol)), nil
+}
+
+// CheckConnection verifies connectivity and authentication by fetching the account.
+func (a *AlpacaTradingSystemProvider) CheckConnection(ctx context.Context) error {
+	if _, err := a.client.GetAccount(ctx); err != nil {
+		debugLog.Warn("CheckConnection: failed to reach Alpaca", zap.Error(err))
+		a.emitStatus(types.ProviderStatusDisconnected)
+
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to connect to Alpaca API", err)
+	}
+
+	a.emitStatus(types.ProviderStatusConnected)
+
+	return nil
+}
+
+// SetOnStatusChange sets a callback that will be called when the connection status changes.
+func (a *AlpacaTradingSystemProvider) SetOnStatusChange(callback OnStatusChange) {
+	a.onStatusChange = callback
+}
+
+// emitStatus emits a status change if a callback is registered.
+func (a *AlpacaTradingSystemProvider) emitStatus(status types.ProviderConnectionStatus) {
+	if a.onStatusChange != nil {
+		a.onStatusChange(status)
+	}
+}
+
+// precision returns the decimal precision order quantities of symbol are rounded to.
+func (a *AlpacaTradingSystemProvider) precision(symbol string) int {
+	return utils.SymbolDecimalPrecision(a.symbolDecimalPrecision, symbol, a.decimalPrecision)
+}
+
+// rememberOrder records the client order ID an order was placed with.
+func (a *AlpacaTradingSystemProvider) rememberOrder(clientOrderID string, placed *AlpacaOrder) {
+	if clientOrderID == "" {
+		return
+	}
+
+	a.ordersMu.Lock()
+	defer a.ordersMu.Unlock()
+
+	a.clientOrderIDs[clientOrderID] = true
+
+	if placed != nil && placed.ID != "" {
+		a.placedOrderIDs[placed.ID] = clientOrderID
+	}
+}
+
+// isClientOrderID reports whether an order was placed with orderID as its client order ID.
+func (a *AlpacaTradingSystemProvider) isClientOrderID(orderID string) bool {
+	a.ordersMu.Lock()
+	defer a.ordersMu.Unlock()
+
+	return a.clientOrderIDs[orderID]
+}
+
+// placedOrderID returns the ID an Alpaca order was placed with, or the Alpaca
+// order ID for orders the provider did not place.
+func (a *AlpacaTradingSystemProvider) placedOrderID(alpacaOrderID string) string {
+	a.ordersMu.Lock()
+	defer a.ordersMu.Unlock()
+
+	if orderID, ok := a.placedOrderIDs[alpacaOrderID]; ok {
+		return orderID
+	}
+
+	return alpacaOrderID
+}
+
+// mapAlpacaOrderStatus maps Alpaca order status to our OrderStatus type.
+func mapAlpacaOrderStatus(status string) types.OrderStatus {
+	switch status {
+	case alpacaOrderStatusNew, alpacaOrderStatusAccepted, alpacaOrderStatusPendingNew,
+		alpacaOrderStatusAcceptedForBidding, alpacaOrderStatusPendingCancel, alpacaOrderStatusPendingReplace,
+		alpacaOrderStatusHeld, alpacaOrderStatusCalculated, alpacaOrderStatusStopped, alpacaOrderStatusDoneForDay:
+		return types.OrderStatusPending
+	case alpacaOrderStatusPartiallyFilled:
+		return types.OrderStatusPartiallyFilled
+	case alpacaOrderStatusFilled:
+		return types.OrderStatusFilled
+	case alpacaOrderStatusCanceled, alpacaOrderStatusReplaced:
+		return types.OrderStatusCancelled
+	case alpacaOrderStatusRejected, alpacaOrderStatusSuspended:
+		return types.OrderStatusRejected
+	case alpacaOrderStatusExpired:
+		return types.OrderStatusFailed
+	default:
+		return types.OrderStatusFailed
+	}
+}
+
+// toAlpacaTimeInForce maps our TimeInForce to the Alpaca one. An empty time
+// in force is good-till-cancelled.
+func toAlpacaTimeInForce(timeInForce types.TimeInForce) string {
+	switch timeInForce {
+	case types.TimeInForceIOC:
+		return "ioc"
+	case types.TimeInForceFOK:
+		return "fok"
+	case types.TimeInForceGTC:
+		return "gtc"
+	default:
+		return "gtc"
+	}
+}
+
+// fromAlpacaTimeInForce maps an Alpaca time in force to ours. Day orders are
+// reported as good-till-cancelled, as they stay open for the session.
+func fromAlpacaTimeInForce(timeInForce string) types.TimeInForce {
+	switch timeInForce {
+	case "ioc":
+		return types.TimeInForceIOC
+	case "fok":
+		return types.TimeInForceFOK
+	default:
+		return types.TimeInForceGTC
+	}
+}
+
+// mapAlpacaSide maps an Alpaca order or fill side to our PurchaseType.
+func mapAlpacaSide(side string) (types.PurchaseType, error) {
+	switch side {
+	case "buy":
+		return types.PurchaseTypeBuy, nil
+	case "sell", "sell_short":
+		return types.PurchaseTypeSell, nil
+	default:
+		return "", errors.Newf(errors.ErrCodeInvalidParameter, "unknown side: %s", side)
+	}
+}
+
+// convertAlpacaPosition converts an Alpaca position to our Position type. The
+// cost basis is recorded as the amount the position was opened with.
+func convertAlpacaPosition(ap AlpacaPosition) types.Position {
+	quantity, _ := strconv.ParseFloat(ap.Qty, 64)
+	costBasis, _ := strconv.ParseFloat(ap.CostBasis, 64)
+	quantity = math.Abs(quantity)
+	costBasis = math.Abs(costBasis)
+
+	position := types.Position{
+		Symbol:                        ap.Symbol,
+		TotalLongPositionQuantity:     0,
+		TotalShortPositionQuantity:    0,
+		TotalLongInPositionQuantity:   0,
+		TotalLongOutPositionQuantity:  0,
+		TotalLongInPositionAmount:     0,
+		TotalLongOutPositionAmount:    0,
+		TotalShortInPositionQuantity:  0,
+		TotalShortOutPositionQuantity: 0,
+		TotalShortInPositionAmount:    0,
+		TotalShortOutPositionAmount:   0,
+		TotalLongInFee:                0,
+		TotalLongOutFee:               0,
+		TotalShortInFee:               0,
+		TotalShortOutFee:              0,
+		AverageLongEntryPrice:         0,
+		TotalLongCost:                 0,
+		AverageShortEntryPrice:        0,
+		TotalShortCost:                0,
+		OpenTimestamp:                 time.Time{},
+		StrategyName:                  "",
+	}
+
+	averageEntryPrice := 0.0
+	if quantity > 0 {
+		averageEntryPrice = costBasis / quantity
+	}
+
+	if ap.Side == "short" {
+		position.TotalShortPositionQuantity = quantity
+		position.TotalShortInPositionQuantity = quantity
+		position.TotalShortInPositionAmount = costBasis
+		position.AverageShortEntryPrice = averageEntryPrice
+		position.TotalShortCost = costBasis
+	} else {
+		position.TotalLongPositionQuantity = quantity
+		position.TotalLongInPositionQuantity = quantity
+		position.TotalLongInPositionAmount = costBasis
+		position.AverageLongEntryPrice = averageEntryPrice
+		position.TotalLongCost = costBasis
+	}
+
+	return position
+}
+
+// convertAlpacaOrderToExecuteOrder converts an Alpaca order to our ExecuteOrder type.
+func convertAlpacaOrderToExecuteOrder(ao AlpacaOrder) (types.ExecuteOrder, error) {
+	side, err := mapAlpacaSide(ao.Side)
+	if err != nil {
+		return types.ExecuteOrder{}, err
+	}
+
+	quantity, _ := strconv.ParseFloat(ao.Qty, 64)
+	filledQuantity, _ := strconv.ParseFloat(ao.FilledQty, 64)
+	limitPrice, _ := strconv.ParseFloat(ao.LimitPrice, 64)
+	stopPrice, _ := strconv.ParseFloat(ao.StopPrice, 64)
+
+	var orderType types.OrderType
+
+	var trailOffset float64
+
+	trailPercent := false
+
+	switch ao.Type {
+	case "market":
+		orderType = types.OrderTypeMarket
+	case "limit":
+		orderType = types.OrderTypeLimit
+	case "stop":
+		orderType = types.OrderTypeStopMarket
+	case "stop_limit":
+		orderType = types.OrderTypeStopLimit
+	case "trailing_stop":
+		orderType = types.OrderTypeTrailingStop
+		trailOffset, _ = strconv.ParseFloat(ao.TrailPrice, 64)
+
+		if ao.TrailPercent != "" {
+			trailOffset, _ = strconv.ParseFloat(ao.TrailPercent, 64)
+			trailPercent = true
+		}
+	default:
+		return types.ExecuteOrder{}, errors.Newf(errors.ErrCodeInvalidParameter, "unknown order type: %s", ao.Type)
+	}
+
+	return types.ExecuteOrder{
+		ID:        ao.ID,
+		Symbol:    ao.Symbol,
+		Side:      side,
+		OrderType: orderType,
+		Reason: types.Reason{
+			Reason:  types.OrderReasonStrategy,
+			Message: "Order from Alpaca",
+		},
+		Price:          limitPrice,
+		StrategyName:   "",
+		Quantity:       quantity,
+		PositionType:   types.PositionTypeLong,
+		StopPrice:      stopPrice,
+		TrailOffset:    trailOffset,
+		TrailPercent:   trailPercent,
+		FilledQuantity: filledQuantity,
+		TimeInForce:    fromAlpacaTimeInForce(ao.TimeInForce),
+		GoodTillDate:   time.Time{},
+		TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+	}, nil
+}
+
+// convertAlpacaFillToTrade converts an Alpaca fill activity to our Trade type.
+// Short sales are reported as sells of a short position.
+func convertAlpacaFillToTrade(activity AlpacaFillActivity) types.Trade {
+	quantity, _ := strconv.ParseFloat(activity.Qty, 64)
+	price, _ := strconv.ParseFloat(activity.Price, 64)
+
+	side := types.PurchaseTypeSell
+	if activity.Side == "buy" {
+		side = types.PurchaseTypeBuy
+	}
+
+	positionType := types.PositionTypeLong
+	if activity.Side == "sell_short" {
+		positionType = types.PositionTypeShort
+	}
+
+	return types.Trade{
+		Order: types.Order{
+			OrderID:      activity.OrderID,
+			Symbol:       activity.Symbol,
+			Side:         side,
+			Quantity:     quantity,
+			Price:        price,
+			Timestamp:    activity.TransactionTime,
+			IsCompleted:  true,
+			Status:       types.OrderStatusFilled,
+			Reason:       types.Reason{Reason: types.OrderReasonStrategy, Message: "Fill from Alpaca " + activity.ID},
+			StrategyName: "",
+			Fee:          0,
+			PositionType: positionType,
+		},
+		ExecutedAt:      activity.TransactionTime,
+		ExecutedQty:     quantity,
+		ExecutedPrice:   price,
+		Fee:             0, // Alpaca charges no commission on equities
+		PnL:             0, // Not available from fill activities
+		CumulativePnL:   0, // Not available from fill activities
+		LIFOPnL:         0, // Not available from fill activities
+		RealizedPnL:     0, // Not available from fill activities
+		CostBasis:       0, // Not available from fill activities
+		OpenPositionQty: 0,
+		Balance:         0,
+		HoldTime:        0,
+		AverageCost:     0,
+	}
+}
+
+// Ensure AlpacaTradingSystemProvider implements TradingSystemProvider.
+var _ TradingSystemProvider = (*AlpacaTradingSystemProvider)(nil)
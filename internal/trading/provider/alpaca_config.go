@@ -0,0 +1,43 @@
+package tradingprovider
+
+import (
+	"encoding/json"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// AlpacaProviderConfig contains configuration for Alpaca trading.
+type AlpacaProviderConfig struct {
+	ApiKey    string `json:"apiKey" jsonschema:"title=API Key,description=Alpaca API key ID" keychain:"true" validate:"required"`
+	SecretKey string `json:"secretKey" jsonschema:"title=Secret Key,description=Alpaca API secret key" keychain:"true" validate:"required"`
+	BaseURL   string `json:"baseUrl,omitempty" jsonschema:"title=Base URL,description=Custom trading REST API base URL (optional). Defaults to the live or paper endpoint of the provider."`
+	DataURL   string `json:"dataUrl,omitempty" jsonschema:"title=Data URL,description=Custom market data REST API base URL used for latest prices (optional). Defaults to https://data.alpaca.markets."`
+	// SymbolDecimalPrecision is the number of decimal places order quantities of
+	// individual symbols are rounded to. Symbols set to 0 trade whole shares only.
+	SymbolDecimalPrecision map[string]int `json:"symbolDecimalPrecision,omitempty" jsonschema:"title=Symbol Decimal Precision,description=Decimal places allowed for the order quantity of individual symbols such as AAPL (optional). Use 0 for symbols that are not fractionable. Other symbols use 9." validate:"omitempty,dive,min=0,max=9"`
+}
+
+// Validate validates the AlpacaProviderConfig struct.
+func (c *AlpacaProviderConfig) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(c); err != nil {
+		return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid alpaca provider config", err)
+	}
+
+	return nil
+}
+
+// parseAlpacaConfig parses a JSON configuration string into an AlpacaProviderConfig.
+func parseAlpacaConfig(jsonConfig string) (*AlpacaProviderConfig, error) {
+	var config AlpacaProviderConfig
+	if err := json.Unmarshal([]byte(jsonConfig), &config); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInvalidParameter, "failed to parse alpaca config", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
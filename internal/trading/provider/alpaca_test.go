@@ -0,0 +1,387 @@
+package tradingprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockAlpacaClient implements AlpacaClient interface for testing
+type mockAlpacaClient struct {
+	placeOrderRequests []AlpacaOrderRequest
+	placeOrderErr      error
+	orders             map[string]AlpacaOrder
+	openOrders         []AlpacaOrder
+	cancelledOrderIDs  []string
+	cancelAllCalled    bool
+	account            *AlpacaAccount
+	positions          []AlpacaPosition
+	positionsErr       error
+	fills              []AlpacaFillActivity
+	prices             map[string]float64
+}
+
+func newMockAlpacaClient() *mockAlpacaClient {
+	return &mockAlpacaClient{
+		orders: map[string]AlpacaOrder{},
+		prices: map[string]float64{},
+	}
+}
+
+func (m *mockAlpacaClient) PlaceOrder(_ context.Context, request AlpacaOrderRequest) (*AlpacaOrder, error) {
+	m.placeOrderRequests = append(m.placeOrderRequests, request)
+	if m.placeOrderErr != nil {
+		return nil, m.placeOrderErr
+	}
+
+	return &AlpacaOrder{ID: "61e69015-8549-4bfd-b9c3-01e75843f47d", ClientOrderID: request.ClientOrderID, Symbol: request.Symbol, Status: "accepted"}, nil
+}
+
+func (m *mockAlpacaClient) GetOrderByClientOrderID(_ context.Context, clientOrderID string) (*AlpacaOrder, error) {
+	for _, order := range m.orders {
+		if order.ClientOrderID == clientOrderID {
+			return &order, nil
+		}
+	}
+
+	return nil, errors.New("order not found")
+}
+
+func (m *mockAlpacaClient) GetOrder(_ context.Context, orderID string) (*AlpacaOrder, error) {
+	order, ok := m.orders[orderID]
+	if !ok {
+		return nil, errors.New("order not found")
+	}
+
+	return &order, nil
+}
+
+func (m *mockAlpacaClient) ListOpenOrders(_ context.Context) ([]AlpacaOrder, error) {
+	return m.openOrders, nil
+}
+
+func (m *mockAlpacaClient) CancelOrder(_ context.Context, orderID string) error {
+	m.cancelledOrderIDs = append(m.cancelledOrderIDs, orderID)
+
+	return nil
+}
+
+func (m *mockAlpacaClient) CancelAllOrders(_ context.Context) error {
+	m.cancelAllCalled = true
+
+	return nil
+}
+
+func (m *mockAlpacaClient) GetAccount(_ context.Context) (*AlpacaAccount, error) {
+	if m.account == nil {
+		return nil, errors.New("account not configured")
+	}
+
+	return m.account, nil
+}
+
+func (m *mockAlpacaClient) GetPositions(_ context.Context) ([]AlpacaPosition, error) {
+	return m.positions, m.positionsErr
+}
+
+func (m *mockAlpacaClient) GetFillActivities(_ context.Context, _ AlpacaActivitiesRequest) ([]AlpacaFillActivity, error) {
+	return m.fills, nil
+}
+
+func (m *mockAlpacaClient) GetLatestPrices(_ context.Context, _ []string) (map[string]float64, error) {
+	return m.prices, nil
+}
+
+type AlpacaTradingTestSuite struct {
+	suite.Suite
+}
+
+func TestAlpacaTradingSuite(t *testing.T) {
+	suite.Run(t, new(AlpacaTradingTestSuite))
+}
+
+// Unit Tests - Config
+
+func (suite *AlpacaTradingTestSuite) TestParseAlpacaConfig() {
+	tests := []struct {
+		name          string
+		jsonConfig    string
+		expectedError string
+	}{
+		{name: "valid", jsonConfig: `{"apiKey": "key", "secretKey": "secret"}`},
+		{name: "valid with symbol precision", jsonConfig: `{"apiKey": "key", "secretKey": "secret", "symbolDecimalPrecision": {"BRK.A": 0}}`},
+		{name: "missing secret key", jsonConfig: `{"apiKey": "key"}`, expectedError: "invalid alpaca provider config"},
+		{name: "precision out of range", jsonConfig: `{"apiKey": "key", "secretKey": "secret", "symbolDecimalPrecision": {"AAPL": 12}}`, expectedError: "invalid alpaca provider config"},
+		{name: "invalid json", jsonConfig: `{invalid}`, expectedError: "failed to parse alpaca config"},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			config, err := parseAlpacaConfig(tc.jsonConfig)
+			if tc.expectedError != "" {
+				suite.Error(err)
+				suite.Contains(err.Error(), tc.expectedError)
+
+				return
+			}
+
+			suite.NoError(err)
+			suite.Equal("key", config.ApiKey)
+		})
+	}
+}
+
+func (suite *AlpacaTradingTestSuite) TestMapAlpacaOrderStatus() {
+	tests := []struct {
+		status   string
+		expected types.OrderStatus
+	}{
+		{"new", types.OrderStatusPending},
+		{"accepted", types.OrderStatusPending},
+		{"pending_new", types.OrderStatusPending},
+		{"done_for_day", types.OrderStatusPending},
+		{"partially_filled", types.OrderStatusPartiallyFilled},
+		{"filled", types.OrderStatusFilled},
+		{"canceled", types.OrderStatusCancelled},
+		{"replaced", types.OrderStatusCancelled},
+		{"rejected", types.OrderStatusRejected},
+		{"suspended", types.OrderStatusRejected},
+		{"expired", types.OrderStatusFailed},
+		{"unknown", types.OrderStatusFailed},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.status, func() {
+			suite.Equal(tc.expected, mapAlpacaOrderStatus(tc.status))
+		})
+	}
+}
+
+// PlaceOrder Tests
+
+func (suite *AlpacaTradingTestSuite) TestPlaceOrder_MarketBuy_Success() {
+	mockClient := newMockAlpacaClient()
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "AAPL",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  10,
+	})
+	suite.NoError(err)
+	suite.Require().Len(mockClient.placeOrderRequests, 1)
+	suite.Equal(AlpacaOrderRequest{
+		Symbol:      "AAPL",
+		Qty:         "10",
+		Side:        "buy",
+		Type:        "market",
+		TimeInForce: "gtc",
+	}, mockClient.placeOrderRequests[0])
+}
+
+func (suite *AlpacaTradingTestSuite) TestPlaceOrder_FractionalMarketBuy_UsesDay() {
+	mockClient := newMockAlpacaClient()
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:      "AAPL",
+		Side:        types.PurchaseTypeBuy,
+		OrderType:   types.OrderTypeMarket,
+		Quantity:    0.1234567891234,
+		TimeInForce: types.TimeInForceGTC,
+	})
+	suite.NoError(err)
+	suite.Require().Len(mockClient.placeOrderRequests, 1)
+	suite.Equal("0.123456789", mockClient.placeOrderRequests[0].Qty)
+	suite.Equal("day", mockClient.placeOrderRequests[0].TimeInForce)
+}
+
+func (suite *AlpacaTradingTestSuite) TestPlaceOrder_LimitSell_Success() {
+	mockClient := newMockAlpacaClient()
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:      "MSFT",
+		Side:        types.PurchaseTypeSell,
+		OrderType:   types.OrderTypeLimit,
+		Quantity:    5,
+		Price:       415.25,
+		TimeInForce: types.TimeInForceIOC,
+	})
+	suite.NoError(err)
+	suite.Require().Len(mockClient.placeOrderRequests, 1)
+	suite.Equal(AlpacaOrderRequest{
+		Symbol:      "MSFT",
+		Qty:         "5",
+		Side:        "sell",
+		Type:        "limit",
+		TimeInForce: "ioc",
+		LimitPrice:  "415.25",
+	}, mockClient.placeOrderRequests[0])
+}
+
+func (suite *AlpacaTradingTestSuite) TestPlaceOrder_LimitBuy_WholeSharesSymbol() {
+	mockClient := newMockAlpacaClient()
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+	provider.symbolDecimalPrecision = map[string]int{"BRK.A": 0}
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BRK.A",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeLimit,
+		Quantity:  2.7,
+		Price:     620000,
+	})
+	suite.NoError(err)
+	suite.Require().Len(mockClient.placeOrderRequests, 1)
+	suite.Equal("2", mockClient.placeOrderRequests[0].Qty)
+	suite.Equal("gtc", mockClient.placeOrderRequests[0].TimeInForce)
+	suite.Equal("620000", mockClient.placeOrderRequests[0].LimitPrice)
+
+	// Less than a whole share cannot be ordered
+	err = provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BRK.A",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeLimit,
+		Quantity:  0.5,
+		Price:     620000,
+	})
+	suite.Error(err)
+	suite.Contains(err.Error(), "too small after rounding")
+	suite.Len(mockClient.placeOrderRequests, 1)
+}
+
+func (suite *AlpacaTradingTestSuite) TestPlaceOrder_InvalidOrders() {
+	mockClient := newMockAlpacaClient()
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{Symbol: "AAPL", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 0})
+	suite.Error(err)
+
+	err = provider.PlaceOrder(types.ExecuteOrder{Symbol: "AAPL", Side: "hold", OrderType: types.OrderTypeMarket, Quantity: 1})
+	suite.Error(err)
+
+	suite.Empty(mockClient.placeOrderRequests)
+}
+
+func (suite *AlpacaTradingTestSuite) TestPlaceOrder_APIError() {
+	mockClient := newMockAlpacaClient()
+	mockClient.placeOrderErr = errors.New("insufficient buying power")
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{Symbol: "AAPL", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 1})
+	suite.Error(err)
+	suite.Contains(err.Error(), "failed to place order on Alpaca")
+}
+
+// GetPositions Tests
+
+func (suite *AlpacaTradingTestSuite) TestGetPositions_Success() {
+	mockClient := newMockAlpacaClient()
+	mockClient.positions = []AlpacaPosition{
+		{Symbol: "TSLA", Qty: "-3", Side: "short", CostBasis: "-750", UnrealizedPnL: "12"},
+		{Symbol: "AAPL", Qty: "1.5", Side: "long", CostBasis: "270.3", UnrealizedPnL: "4.5"},
+	}
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	positions, err := provider.GetPositions()
+	suite.NoError(err)
+	suite.Require().Len(positions, 2)
+
+	// Positions are sorted by symbol
+	suite.Equal("AAPL", positions[0].Symbol)
+	suite.Equal(1.5, positions[0].TotalLongPositionQuantity)
+	suite.Equal(270.3, positions[0].TotalLongInPositionAmount)
+	suite.Equal(0.0, positions[0].TotalShortPositionQuantity)
+	suite.Equal("TSLA", positions[1].Symbol)
+	suite.Equal(3.0, positions[1].TotalShortPositionQuantity)
+	suite.Equal(750.0, positions[1].TotalShortInPositionAmount)
+	suite.Equal(0.0, positions[1].TotalLongPositionQuantity)
+}
+
+func (suite *AlpacaTradingTestSuite) TestGetPositions_APIError() {
+	mockClient := newMockAlpacaClient()
+	mockClient.positionsErr = errors.New("unauthorized")
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	positions, err := provider.GetPositions()
+	suite.Error(err)
+	suite.Nil(positions)
+}
+
+func (suite *AlpacaTradingTestSuite) TestGetPosition_NotFound() {
+	mockClient := newMockAlpacaClient()
+	mockClient.positions = []AlpacaPosition{{Symbol: "AAPL", Qty: "1", Side: "long", CostBasis: "180", UnrealizedPnL: "0"}}
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	position, err := provider.GetPosition("MSFT")
+	suite.NoError(err)
+	suite.Equal("MSFT", position.Symbol)
+	suite.Equal(0.0, position.TotalLongPositionQuantity)
+}
+
+// Order Management Tests
+
+func (suite *AlpacaTradingTestSuite) TestGetOrderStatus() {
+	mockClient := newMockAlpacaClient()
+	mockClient.orders["order-1"] = AlpacaOrder{ID: "order-1", Symbol: "AAPL", Status: "partially_filled"}
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	status, err := provider.GetOrderStatus("order-1")
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusPartiallyFilled, status)
+
+	_, err = provider.GetOrderStatus("missing")
+	suite.Error(err)
+}
+
+func (suite *AlpacaTradingTestSuite) TestGetOrderStatus_ByPlacedOrderID() {
+	mockClient := newMockAlpacaClient()
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	orderID := "3f1c8e2a-5b7d-4c9e-8a1f-2d6b4e8c0a93"
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		ID:        orderID,
+		Symbol:    "AAPL",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  10,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(mockClient.placeOrderRequests, 1)
+	suite.Equal(orderID, mockClient.placeOrderRequests[0].ClientOrderID)
+
+	alpacaOrderID := "61e69015-8549-4bfd-b9c3-01e75843f47d"
+	mockClient.orders[alpacaOrderID] = AlpacaOrder{ID: alpacaOrderID, ClientOrderID: orderID, Symbol: "AAPL", Status: "filled"}
+
+	status, err := provider.GetOrderStatus(orderID)
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusFilled, status)
+
+	// Fills of the order carry the ID it was placed with; other fills keep the Alpaca order ID
+	mockClient.fills = []AlpacaFillActivity{
+		{ID: "fill-1", OrderID: alpacaOrderID, Symbol: "AAPL", Side: "buy", Qty: "10", Price: "190"},
+		{ID: "fill-2", OrderID: "b0b6dd9d-8b9b-48a9-ba46-b9d54906e415", Symbol: "AAPL", Side: "sell", Qty: "1", Price: "191"},
+	}
+
+	trades, err := provider.GetTrades(types.TradeFilter{Symbol: "AAPL"})
+	suite.Require().NoError(err)
+	suite.Require().Len(trades, 2)
+	suite.Equal(orderID, trades[0].Order.OrderID)
+	suite.Equal("b0b6dd9d-8b9b-48a9-ba46-b9d54906e415", trades[1].Order.OrderID)
+}
+
+func (suite *AlpacaTradingTestSuite) TestCancelOrders() {
+	mockClient := newMockAlpacaClient()
+	provider := newAlpacaTradingSystemProviderWithClient(mockClient)
+
+	suite.NoError(provider.CancelOrder("order-1"))
+	suite.Equal([]string{"order-1"}, mockClient.cancelledOrderIDs)
+
+	suite.NoError(provider.CancelAllOrders())
+	suite.True(mockClient.cancelAllCalled)
+}
@@ -2,10 +2,17 @@ package tradingprovider
 
 import (
 	"context"
+	"io"
+	"net"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/google/uuid"
 	"github.com/moznion/go-optional"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/internal/utils"
@@ -21,8 +28,38 @@ const (
 	// 8 decimals allows for satoshi-level precision (0.00000001 BTC) for BTC-like assets.
 	// Production systems should use symbol-specific precision from Binance exchange info (e.g. LOT_SIZE, PRICE_FILTER).
 	BinanceDecimalPrecision = 8
+
+	// DefaultBinanceRateLimitPerMinute is the default request weight budget per minute.
+	// It is a fifth of Binance's 6000 weight/minute IP limit, leaving room for
+	// market data requests made from the same IP.
+	DefaultBinanceRateLimitPerMinute = 1200
+
+	// DefaultBinanceBNBFeeDiscount is the discount Binance grants on spot trading
+	// fees paid in BNB.
+	DefaultBinanceBNBFeeDiscount = 0.25
+)
+
+// DefaultBinanceStablecoinAssets are the assets summed into the account balance
+// when BinanceProviderConfig.StablecoinAssets is unset.
+var DefaultBinanceStablecoinAssets = []string{"USDT", "BUSD", "USD"}
+
+// Request weights of the Binance spot endpoints used by the provider.
+const (
+	binanceWeightCreateOrder   = 1
+	binanceWeightCreateOCO     = 1  // POST /api/v3/order/oco
+	binanceWeightOpenOrdersAll = 80 // GET /api/v3/openOrders without a symbol
+	binanceWeightGetOrder      = 4  // GET /api/v3/order
+	binanceWeightAccountTrades = 20 // GET /api/v3/myTrades
+	binanceWeightUserStream    = 2  // POST and PUT /api/v3/userDataStream
+	binanceWeightExchangeInfo  = 20 // GET /api/v3/exchangeInfo
 )
 
+// binanceDefaultTradesLimit is the page size Binance uses for myTrades without a limit.
+const binanceDefaultTradesLimit = 500
+
+// binanceClientOrderIDPattern matches the client order IDs Binance accepts.
+var binanceClientOrderIDPattern = regexp.MustCompile(`^[.A-Z:/a-z0-9_-]{1,36}$`)
+
 // Service interfaces for mocking the Binance API
 
 // CreateOrderService interface for creating orders.
@@ -33,14 +70,38 @@ type CreateOrderService interface {
 	Quantity(quantity string) CreateOrderService
 	Price(price string) CreateOrderService
 	TimeInForce(tif binance.TimeInForceType) CreateOrderService
+	NewClientOrderID(clientOrderID string) CreateOrderService
 	Do(ctx context.Context) (*binance.CreateOrderResponse, error)
 }
 
+// CreateOCOService interface for creating OCO order lists.
+type CreateOCOService interface {
+	Symbol(symbol string) CreateOCOService
+	Side(side binance.SideType) CreateOCOService
+	Quantity(quantity string) CreateOCOService
+	Price(price string) CreateOCOService
+	StopPrice(stopPrice string) CreateOCOService
+	StopLimitPrice(stopLimitPrice string) CreateOCOService
+	StopLimitTimeInForce(tif binance.TimeInForceType) CreateOCOService
+	ListClientOrderID(listClientOrderID string) CreateOCOService
+	LimitClientOrderID(limitClientOrderID string) CreateOCOService
+	StopClientOrderID(stopClientOrderID string) CreateOCOService
+	Do(ctx context.Context) (*binance.CreateOCOResponse, error)
+}
+
 // GetAccountService interface for getting account info.
 type GetAccountService interface {
 	Do(ctx context.Context) (*binance.Account, error)
 }
 
+// GetOrderService interface for querying a single open or closed order.
+type GetOrderService interface {
+	Symbol(symbol string) GetOrderService
+	OrderID(orderID int64) GetOrderService
+	OrigClientOrderID(clientOrderID string) GetOrderService
+	Do(ctx context.Context) (*binance.Order, error)
+}
+
 // ListOpenOrdersService interface for listing open orders.
 type ListOpenOrdersService interface {
 	Do(ctx context.Context) ([]*binance.Order, error)
@@ -65,6 +126,7 @@ type ListTradesService interface {
 	Limit(limit int) ListTradesService
 	StartTime(startTime int64) ListTradesService
 	EndTime(endTime int64) ListTradesService
+	FromID(fromID int64) ListTradesService
 	Do(ctx context.Context) ([]*binance.TradeV3, error)
 }
 
@@ -81,16 +143,38 @@ type ListPricesService interface {
 	Do(ctx context.Context) ([]*binance.SymbolPrice, error)
 }
 
+// StartUserStreamService interface for creating a user data stream listen key.
+type StartUserStreamService interface {
+	Do(ctx context.Context) (listenKey string, err error)
+}
+
+// KeepaliveUserStreamService interface for extending the validity of a listen key.
+type KeepaliveUserStreamService interface {
+	ListenKey(listenKey string) KeepaliveUserStreamService
+	Do(ctx context.Context) error
+}
+
+// ExchangeInfoService interface for fetching the trading rules of symbols.
+type ExchangeInfoService interface {
+	Symbol(symbol string) ExchangeInfoService
+	Do(ctx context.Context) (*binance.ExchangeInfo, error)
+}
+
 // BinanceClient interface abstracts the Binance client for testing.
 type BinanceClient interface {
 	NewCreateOrderService() CreateOrderService
+	NewCreateOCOService() CreateOCOService
 	NewGetAccountService() GetAccountService
 	NewListOpenOrdersService() ListOpenOrdersService
+	NewGetOrderService() GetOrderService
 	NewCancelOrderService() CancelOrderService
 	NewCancelOpenOrdersService() CancelOpenOrdersService
 	NewListTradesService() ListTradesService
 	NewTradeFeeService() TradeFeeService
 	NewListPricesService() ListPricesService
+	NewStartUserStreamService() StartUserStreamService
+	NewKeepaliveUserStreamService() KeepaliveUserStreamService
+	NewExchangeInfoService() ExchangeInfoService
 }
 
 // realBinanceClient wraps the actual binance.Client.
@@ -102,6 +186,10 @@ func (r *realBinanceClient) NewCreateOrderService() CreateOrderService {
 	return &realCreateOrderService{service: r.client.NewCreateOrderService()}
 }
 
+func (r *realBinanceClient) NewCreateOCOService() CreateOCOService {
+	return &realCreateOCOService{service: r.client.NewCreateOCOService()}
+}
+
 func (r *realBinanceClient) NewGetAccountService() GetAccountService {
 	return &realGetAccountService{service: r.client.NewGetAccountService()}
 }
@@ -110,6 +198,10 @@ func (r *realBinanceClient) NewListOpenOrdersService() ListOpenOrdersService {
 	return &realListOpenOrdersService{service: r.client.NewListOpenOrdersService()}
 }
 
+func (r *realBinanceClient) NewGetOrderService() GetOrderService {
+	return &realGetOrderService{service: r.client.NewGetOrderService()}
+}
+
 func (r *realBinanceClient) NewCancelOrderService() CancelOrderService {
 	return &realCancelOrderService{service: r.client.NewCancelOrderService()}
 }
@@ -130,6 +222,18 @@ func (r *realBinanceClient) NewListPricesService() ListPricesService {
 	return &realListPricesService{service: r.client.NewListPricesService()}
 }
 
+func (r *realBinanceClient) NewStartUserStreamService() StartUserStreamService {
+	return &realStartUserStreamService{service: r.client.NewStartUserStreamService()}
+}
+
+func (r *realBinanceClient) NewKeepaliveUserStreamService() KeepaliveUserStreamService {
+	return &realKeepaliveUserStreamService{service: r.client.NewKeepaliveUserStreamService()}
+}
+
+func (r *realBinanceClient) NewExchangeInfoService() ExchangeInfoService {
+	return &realExchangeInfoService{service: r.client.NewExchangeInfoService()}
+}
+
 // Real service wrappers
 
 type realCreateOrderService struct {
@@ -172,10 +276,84 @@ func (s *realCreateOrderService) TimeInForce(tif binance.TimeInForceType) Create
 	return s
 }
 
+func (s *realCreateOrderService) NewClientOrderID(clientOrderID string) CreateOrderService {
+	s.service = s.service.NewClientOrderID(clientOrderID)
+
+	return s
+}
+
 func (s *realCreateOrderService) Do(ctx context.Context) (*binance.CreateOrderResponse, error) {
 	return s.service.Do(ctx)
 }
 
+type realCreateOCOService struct {
+	service *binance.CreateOCOService
+}
+
+func (s *realCreateOCOService) Symbol(symbol string) CreateOCOService {
+	s.service = s.service.Symbol(symbol)
+
+	return s
+}
+
+func (s *realCreateOCOService) Side(side binance.SideType) CreateOCOService {
+	s.service = s.service.Side(side)
+
+	return s
+}
+
+func (s *realCreateOCOService) Quantity(quantity string) CreateOCOService {
+	s.service = s.service.Quantity(quantity)
+
+	return s
+}
+
+func (s *realCreateOCOService) Price(price string) CreateOCOService {
+	s.service = s.service.Price(price)
+
+	return s
+}
+
+func (s *realCreateOCOService) StopPrice(stopPrice string) CreateOCOService {
+	s.service = s.service.StopPrice(stopPrice)
+
+	return s
+}
+
+func (s *realCreateOCOService) StopLimitPrice(stopLimitPrice string) CreateOCOService {
+	s.service = s.service.StopLimitPrice(stopLimitPrice)
+
+	return s
+}
+
+func (s *realCreateOCOService) StopLimitTimeInForce(tif binance.TimeInForceType) CreateOCOService {
+	s.service = s.service.StopLimitTimeInForce(tif)
+
+	return s
+}
+
+func (s *realCreateOCOService) ListClientOrderID(listClientOrderID string) CreateOCOService {
+	s.service = s.service.ListClientOrderID(listClientOrderID)
+
+	return s
+}
+
+func (s *realCreateOCOService) LimitClientOrderID(limitClientOrderID string) CreateOCOService {
+	s.service = s.service.LimitClientOrderID(limitClientOrderID)
+
+	return s
+}
+
+func (s *realCreateOCOService) StopClientOrderID(stopClientOrderID string) CreateOCOService {
+	s.service = s.service.StopClientOrderID(stopClientOrderID)
+
+	return s
+}
+
+func (s *realCreateOCOService) Do(ctx context.Context) (*binance.CreateOCOResponse, error) {
+	return s.service.Do(ctx)
+}
+
 type realGetAccountService struct {
 	service *binance.GetAccountService
 }
@@ -192,6 +370,32 @@ func (s *realListOpenOrdersService) Do(ctx context.Context) ([]*binance.Order, e
 	return s.service.Do(ctx)
 }
 
+type realGetOrderService struct {
+	service *binance.GetOrderService
+}
+
+func (s *realGetOrderService) Symbol(symbol string) GetOrderService {
+	s.service = s.service.Symbol(symbol)
+
+	return s
+}
+
+func (s *realGetOrderService) OrderID(orderID int64) GetOrderService {
+	s.service = s.service.OrderID(orderID)
+
+	return s
+}
+
+func (s *realGetOrderService) OrigClientOrderID(clientOrderID string) GetOrderService {
+	s.service = s.service.OrigClientOrderID(clientOrderID)
+
+	return s
+}
+
+func (s *realGetOrderService) Do(ctx context.Context) (*binance.Order, error) {
+	return s.service.Do(ctx)
+}
+
 type realCancelOrderService struct {
 	service *binance.CancelOrderService
 }
@@ -256,6 +460,12 @@ func (s *realListTradesService) EndTime(endTime int64) ListTradesService {
 	return s
 }
 
+func (s *realListTradesService) FromID(fromID int64) ListTradesService {
+	s.service = s.service.FromID(fromID)
+
+	return s
+}
+
 func (s *realListTradesService) Do(ctx context.Context) ([]*binance.TradeV3, error) {
 	return s.service.Do(ctx)
 }
@@ -288,12 +498,73 @@ func (s *realListPricesService) Do(ctx context.Context) ([]*binance.SymbolPrice,
 	return s.service.Do(ctx)
 }
 
+type realStartUserStreamService struct {
+	service *binance.StartUserStreamService
+}
+
+func (s *realStartUserStreamService) Do(ctx context.Context) (string, error) {
+	return s.service.Do(ctx)
+}
+
+type realKeepaliveUserStreamService struct {
+	service *binance.KeepaliveUserStreamService
+}
+
+func (s *realKeepaliveUserStreamService) ListenKey(listenKey string) KeepaliveUserStreamService {
+	s.service = s.service.ListenKey(listenKey)
+
+	return s
+}
+
+func (s *realKeepaliveUserStreamService) Do(ctx context.Context) error {
+	return s.service.Do(ctx)
+}
+
+type realExchangeInfoService struct {
+	service *binance.ExchangeInfoService
+}
+
+func (s *realExchangeInfoService) Symbol(symbol string) ExchangeInfoService {
+	s.service = s.service.Symbol(symbol)
+
+	return s
+}
+
+func (s *realExchangeInfoService) Do(ctx context.Context) (*binance.ExchangeInfo, error) {
+	return s.service.Do(ctx)
+}
+
 // BinanceTradingSystemProvider implements TradingSystemProvider using Binance API.
-// It is stateless - all data is fetched directly from the Binance API.
+// All data is fetched directly from the Binance API; the only state kept is the
+// symbol of each order seen, which Binance requires to query an order.
 type BinanceTradingSystemProvider struct {
 	client           BinanceClient
 	decimalPrecision int
-	onStatusChange   OnStatusChange
+	// symbolDecimalPrecision overrides decimalPrecision for the listed symbols.
+	symbolDecimalPrecision map[string]int
+	onStatusChange         OnStatusChange
+	retryPolicy            RetryPolicy
+	sleep                  sleepFunc
+	rateLimiter            *tokenBucket
+	userDataWs             BinanceUserDataWebSocket
+	// stablecoinAssets are the assets summed into the account balance.
+	stablecoinAssets map[string]bool
+	// quoteAssets are the assets whose free balance is summed into the buying
+	// power. Nil uses stablecoinAssets.
+	quoteAssets map[string]bool
+	// bnbFeeDiscount is the discount on fees paid in BNB. 0 means fees are
+	// not paid in BNB.
+	bnbFeeDiscount float64
+	// orderSymbols maps the ID of each order placed or listed to its symbol.
+	orderSymbols map[string]string
+	// clientOrderSymbols maps the client order ID of each order placed to its symbol.
+	clientOrderSymbols map[string]string
+	// clientOrderIDs maps the Binance ID of each order placed to its client order ID.
+	clientOrderIDs map[int64]string
+	orderSymbolsMu sync.Mutex
+	// symbolFilters caches the trading rules of each symbol orders were placed for.
+	symbolFilters   map[string]binanceSymbolFilters
+	symbolFiltersMu sync.Mutex
 }
 
 // NewBinanceTradingSystemProvider creates a new Binance trading system.
@@ -322,9 +593,23 @@ func NewBinanceTradingSystemProvider(config BinanceProviderConfig, useTestnet bo
 	)
 
 	return &BinanceTradingSystemProvider{
-		client:           &realBinanceClient{client: client},
-		decimalPrecision: BinanceDecimalPrecision,
-		onStatusChange:   nil,
+		client:                 &realBinanceClient{client: client},
+		decimalPrecision:       BinanceDecimalPrecision,
+		symbolDecimalPrecision: config.SymbolDecimalPrecision,
+		onStatusChange:         nil,
+		retryPolicy:            config.RetryPolicy(),
+		sleep:                  sleepContext,
+		rateLimiter:            newTokenBucket(config.RequestWeightPerMinute(), time.Now, sleepContext),
+		userDataWs:             &binanceUserDataWebSocket{},
+		stablecoinAssets:       config.StablecoinAssetSet(),
+		quoteAssets:            config.QuoteAssetSet(),
+		bnbFeeDiscount:         config.FeeAssetDiscount(),
+		orderSymbols:           map[string]string{},
+		clientOrderSymbols:     map[string]string{},
+		clientOrderIDs:         map[int64]string{},
+		orderSymbolsMu:         sync.Mutex{},
+		symbolFilters:          map[string]binanceSymbolFilters{},
+		symbolFiltersMu:        sync.Mutex{},
 	}, nil
 }
 
@@ -332,9 +617,23 @@ func NewBinanceTradingSystemProvider(config BinanceProviderConfig, useTestnet bo
 // This is used for testing with mock clients.
 func newBinanceTradingSystemProviderWithClient(client BinanceClient) *BinanceTradingSystemProvider {
 	return &BinanceTradingSystemProvider{
-		client:           client,
-		decimalPrecision: BinanceDecimalPrecision,
-		onStatusChange:   nil,
+		client:                 client,
+		decimalPrecision:       BinanceDecimalPrecision,
+		symbolDecimalPrecision: nil,
+		onStatusChange:         nil,
+		retryPolicy:            DefaultRetryPolicy(),
+		sleep:                  sleepContext,
+		rateLimiter:            newTokenBucket(DefaultBinanceRateLimitPerMinute, time.Now, sleepContext),
+		userDataWs:             &binanceUserDataWebSocket{},
+		stablecoinAssets:       assetSet(DefaultBinanceStablecoinAssets),
+		quoteAssets:            nil,
+		bnbFeeDiscount:         0,
+		orderSymbols:           map[string]string{},
+		clientOrderSymbols:     map[string]string{},
+		clientOrderIDs:         map[int64]string{},
+		orderSymbolsMu:         sync.Mutex{},
+		symbolFilters:          map[string]binanceSymbolFilters{},
+		symbolFiltersMu:        sync.Mutex{},
 	}
 }
 
@@ -342,16 +641,35 @@ func newBinanceTradingSystemProviderWithClient(client BinanceClient) *BinanceTra
 // This is used for testing with different decimal precisions.
 func newBinanceTradingSystemProviderWithPrecision(client BinanceClient, decimalPrecision int) *BinanceTradingSystemProvider {
 	return &BinanceTradingSystemProvider{
-		client:           client,
-		decimalPrecision: decimalPrecision,
-		onStatusChange:   nil,
+		client:                 client,
+		decimalPrecision:       decimalPrecision,
+		symbolDecimalPrecision: nil,
+		onStatusChange:         nil,
+		retryPolicy:            DefaultRetryPolicy(),
+		sleep:                  sleepContext,
+		rateLimiter:            newTokenBucket(DefaultBinanceRateLimitPerMinute, time.Now, sleepContext),
+		userDataWs:             &binanceUserDataWebSocket{},
+		stablecoinAssets:       assetSet(DefaultBinanceStablecoinAssets),
+		quoteAssets:            nil,
+		bnbFeeDiscount:         0,
+		orderSymbols:           map[string]string{},
+		clientOrderSymbols:     map[string]string{},
+		clientOrderIDs:         map[int64]string{},
+		orderSymbolsMu:         sync.Mutex{},
+		symbolFilters:          map[string]binanceSymbolFilters{},
+		symbolFiltersMu:        sync.Mutex{},
 	}
 }
 
 // PlaceOrder places a single order on Binance.
+// Transient failures (network errors, rate limits, server errors) are retried
+// with exponential backoff according to the configured retry policy.
 func (b *BinanceTradingSystemProvider) PlaceOrder(order types.ExecuteOrder) error {
-	ctx := context.Background()
+	return b.placeOrder(context.Background(), order)
+}
 
+// placeOrder places an order, aborting pending retries when ctx is cancelled.
+func (b *BinanceTradingSystemProvider) placeOrder(ctx context.Context, order types.ExecuteOrder) error {
 	// Map order side
 	var side binance.SideType
 
@@ -381,37 +699,174 @@ func (b *BinanceTradingSystemProvider) PlaceOrder(order types.ExecuteOrder) erro
 		return errors.New(errors.ErrCodeInvalidParameter, "order quantity must be greater than zero")
 	}
 
-	roundedQuantity := utils.RoundToDecimalPrecision(order.Quantity, b.decimalPrecision)
+	precision := b.precision(order.Symbol)
+
+	roundedQuantity := utils.RoundToDecimalPrecision(order.Quantity, precision)
 	if roundedQuantity <= 0 {
 		return errors.Newf(errors.ErrCodeInvalidParameter,
 			"order quantity %.8f is too small after rounding to %d decimal places",
-			order.Quantity, b.decimalPrecision)
+			order.Quantity, precision)
 	}
 
-	// Create order service
-	orderService := b.client.NewCreateOrderService().
-		Symbol(order.Symbol).
-		Side(side).
-		Type(orderType).
-		Quantity(strconv.FormatFloat(roundedQuantity, 'f', b.decimalPrecision, 64))
+	// Check the symbol's trading rules locally instead of spending a request
+	// on an order Binance would reject
+	filters := b.exchangeFilters(ctx, order.Symbol)
 
-	// For limit orders, add price and time in force
-	if order.OrderType == types.OrderTypeLimit {
-		orderService = orderService.
-			Price(strconv.FormatFloat(order.Price, 'f', -1, 64)).
-			TimeInForce(binance.TimeInForceTypeGTC)
+	quantity, price, err := filters.apply(order, roundedQuantity)
+	if err != nil {
+		return err
 	}
 
-	// Execute order
-	_, err := orderService.Do(ctx)
+	// Every attempt reuses the same client order ID so Binance rejects a retry
+	// of an order that was accepted but whose response was lost. The order ID is
+	// sent as the client order ID so the order can be looked up by it.
+	clientOrderID := binanceClientOrderID(order.ID)
+
+	err = retryWithBackoff(ctx, b.retryPolicy, b.sleep, isRetryableBinanceError, func(attempt int) error {
+		if err := b.rateLimiter.Wait(ctx, binanceWeightCreateOrder); err != nil {
+			return err
+		}
+
+		orderService := b.client.NewCreateOrderService().
+			Symbol(order.Symbol).
+			Side(side).
+			Type(orderType).
+			Quantity(filters.formatQuantity(quantity, precision)).
+			NewClientOrderID(clientOrderID)
+
+		// For limit orders, add price and time in force
+		if order.OrderType == types.OrderTypeLimit {
+			orderService = orderService.
+				Price(filters.formatPrice(price)).
+				TimeInForce(toBinanceTimeInForce(order.TimeInForce))
+		}
+
+		response, doErr := orderService.Do(ctx)
+		if doErr != nil && attempt > 1 && isBinanceDuplicateOrderError(doErr) {
+			// An earlier attempt reached Binance even though it reported a failure
+			return nil
+		}
+
+		if doErr == nil && response != nil {
+			b.rememberOrderSymbol(response.OrderID, order.Symbol)
+			b.rememberClientOrder(clientOrderID, response.OrderID, order.Symbol)
+		}
+
+		return doErr
+	})
 	if err != nil {
-		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to place order on Binance", err)
+		return binanceOrderError("failed to place order on Binance", err)
 	}
 
+	b.rememberClientOrder(clientOrderID, 0, order.Symbol)
+
 	return nil
 }
 
+// PlaceOCOOrder places entry and then both exit legs as a native Binance OCO
+// order list: a limit maker order for the take profit and a stop-loss (or
+// stop-loss-limit) order for the stop loss. Binance cancels the remaining leg
+// when the other one fills. The returned group ID is the order list's client ID.
+//
+// Spot OCO legs sell holdings, so entry should be a market order: the legs of a
+// resting limit entry are rejected for insufficient balance until it fills.
+func (b *BinanceTradingSystemProvider) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	if err := ValidateOCOOrder(entry, takeProfit, stopLoss); err != nil {
+		return "", err
+	}
+
+	if takeProfit.Quantity != stopLoss.Quantity {
+		return "", errors.New(errors.ErrCodeInvalidParameter, "Binance OCO legs must have the same quantity")
+	}
+
+	var side binance.SideType
+
+	switch takeProfit.Side {
+	case types.PurchaseTypeBuy:
+		side = binance.SideTypeBuy
+	case types.PurchaseTypeSell:
+		side = binance.SideTypeSell
+	default:
+		return "", errors.Newf(errors.ErrCodeInvalidParameter, "unsupported order side: %s", takeProfit.Side)
+	}
+
+	precision := b.precision(takeProfit.Symbol)
+
+	roundedQuantity := utils.RoundToDecimalPrecision(takeProfit.Quantity, precision)
+	if roundedQuantity <= 0 {
+		return "", errors.Newf(errors.ErrCodeInvalidParameter,
+			"OCO quantity %.8f is too small after rounding to %d decimal places",
+			takeProfit.Quantity, precision)
+	}
+
+	ctx := context.Background()
+
+	if err := b.placeOrder(ctx, entry); err != nil {
+		return "", err
+	}
+
+	// Like single orders, retries reuse the list client order ID so a lost
+	// response cannot create a second order list
+	listClientOrderID := uuid.NewString()
+	limitClientOrderID := binanceClientOrderID(takeProfit.ID)
+	stopClientOrderID := binanceClientOrderID(stopLoss.ID)
+
+	err := retryWithBackoff(ctx, b.retryPolicy, b.sleep, isRetryableBinanceError, func(attempt int) error {
+		if err := b.rateLimiter.Wait(ctx, binanceWeightCreateOCO); err != nil {
+			return err
+		}
+
+		service := b.client.NewCreateOCOService().
+			Symbol(takeProfit.Symbol).
+			Side(side).
+			Quantity(strconv.FormatFloat(roundedQuantity, 'f', precision, 64)).
+			Price(strconv.FormatFloat(takeProfit.Price, 'f', -1, 64)).
+			StopPrice(strconv.FormatFloat(stopLoss.StopPrice, 'f', -1, 64)).
+			ListClientOrderID(listClientOrderID).
+			LimitClientOrderID(limitClientOrderID).
+			StopClientOrderID(stopClientOrderID)
+
+		// Without a stop limit price the stop leg is a stop-loss (market) order
+		if stopLoss.OrderType == types.OrderTypeStopLimit {
+			service = service.
+				StopLimitPrice(strconv.FormatFloat(stopLoss.Price, 'f', -1, 64)).
+				StopLimitTimeInForce(binance.TimeInForceTypeGTC)
+		}
+
+		response, doErr := service.Do(ctx)
+		if doErr != nil && attempt > 1 && isBinanceDuplicateOrderError(doErr) {
+			return nil
+		}
+
+		if doErr == nil && response != nil {
+			for _, leg := range response.Orders {
+				b.rememberOrderSymbol(leg.OrderID, leg.Symbol)
+				b.rememberClientOrder(leg.ClientOrderID, leg.OrderID, leg.Symbol)
+			}
+		}
+
+		return doErr
+	})
+	if err != nil {
+		return "", binanceOrderError("failed to place OCO order on Binance", err)
+	}
+
+	b.rememberClientOrder(limitClientOrderID, 0, takeProfit.Symbol)
+	b.rememberClientOrder(stopClientOrderID, 0, stopLoss.Symbol)
+
+	return listClientOrderID, nil
+}
+
+// PlaceBracketOrder implements TradingSystemProvider. The legs are placed as a
+// native OCO order list right after the entry, so the entry should be a market
+// order, as for PlaceOCOOrder.
+func (b *BinanceTradingSystemProvider) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	return PlaceBracketOrderAsOCO(b, entry, stopLoss, takeProfit)
+}
+
 // PlaceMultipleOrders places multiple orders sequentially.
+// Each order waits for the rate limiter, so large batches are spread out
+// instead of exceeding the request weight budget.
 func (b *BinanceTradingSystemProvider) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
 	for _, order := range orders {
 		if err := b.PlaceOrder(order); err != nil {
@@ -455,6 +910,10 @@ func (b *BinanceTradingSystemProvider) GetPositions() ([]types.Position, error)
 				TotalLongOutFee:               0,
 				TotalShortInFee:               0,
 				TotalShortOutFee:              0,
+				AverageLongEntryPrice:         0,
+				TotalLongCost:                 0,
+				AverageShortEntryPrice:        0,
+				TotalShortCost:                0,
 				OpenTimestamp:                 time.Time{},
 				StrategyName:                  "",
 			})
@@ -464,7 +923,8 @@ func (b *BinanceTradingSystemProvider) GetPositions() ([]types.Position, error)
 	return positions, nil
 }
 
-// GetPosition returns the position for a specific symbol.
+// GetPosition returns the position for a specific asset, or for a trading pair
+// the balance of its base asset: the position in BTCUSDT is the BTC balance.
 func (b *BinanceTradingSystemProvider) GetPosition(symbol string) (types.Position, error) {
 	positions, err := b.GetPositions()
 	if err != nil {
@@ -477,6 +937,16 @@ func (b *BinanceTradingSystemProvider) GetPosition(symbol string) (types.Positio
 		}
 	}
 
+	if baseAsset := b.exchangeFilters(context.Background(), symbol).baseAsset; baseAsset != "" {
+		for _, pos := range positions {
+			if pos.Symbol == baseAsset {
+				pos.Symbol = symbol
+
+				return pos, nil
+			}
+		}
+	}
+
 	// Return empty position if not found
 	return types.Position{
 		Symbol:                        symbol,
@@ -494,6 +964,10 @@ func (b *BinanceTradingSystemProvider) GetPosition(symbol string) (types.Positio
 		TotalLongOutFee:               0,
 		TotalShortInFee:               0,
 		TotalShortOutFee:              0,
+		AverageLongEntryPrice:         0,
+		TotalLongCost:                 0,
+		AverageShortEntryPrice:        0,
+		TotalShortCost:                0,
 		OpenTimestamp:                 time.Time{},
 		StrategyName:                  "",
 	}, nil
@@ -562,27 +1036,74 @@ func (b *BinanceTradingSystemProvider) CancelAllOrders() error {
 	return nil
 }
 
-// GetOrderStatus returns the status of an order.
+// CancelOrdersForSymbol cancels the open orders of a symbol with a single request.
+func (b *BinanceTradingSystemProvider) CancelOrdersForSymbol(symbol string) error {
+	err := b.client.NewCancelOpenOrdersService().
+		Symbol(symbol).
+		Do(context.Background())
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to cancel orders on Binance", err)
+	}
+
+	return nil
+}
+
+// GetOrderStatus returns the status of an order by its client order ID, which
+// is the ID it was placed with, or by its Binance order ID. An order whose symbol
+// is known, having been placed or listed by the provider, is queried directly,
+// so filled and cancelled orders report their final status. Other orders are
+// looked up among the open orders and are reported failed when not found.
 func (b *BinanceTradingSystemProvider) GetOrderStatus(orderID string) (types.OrderStatus, error) {
 	ctx := context.Background()
 
-	// First check open orders
+	if symbol, ok := b.clientOrderSymbol(orderID); ok {
+		return b.queryOrderStatus(ctx, b.client.NewGetOrderService().Symbol(symbol).OrigClientOrderID(orderID))
+	}
+
+	if symbol, ok := b.orderSymbol(orderID); ok {
+		binanceOrderID, err := strconv.ParseInt(orderID, 10, 64)
+		if err != nil {
+			return types.OrderStatusFailed, errors.Wrap(errors.ErrCodeInvalidParameter, "invalid order ID format", err)
+		}
+
+		return b.queryOrderStatus(ctx, b.client.NewGetOrderService().Symbol(symbol).OrderID(binanceOrderID))
+	}
+
+	if err := b.rateLimiter.Wait(ctx, binanceWeightOpenOrdersAll); err != nil {
+		return types.OrderStatusFailed, errors.Wrap(errors.ErrCodeOrderFailed, "rate limiter wait aborted", err)
+	}
+
 	openOrders, err := b.client.NewListOpenOrdersService().Do(ctx)
 	if err != nil {
 		return types.OrderStatusFailed, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get open orders from Binance", err)
 	}
 
 	for _, order := range openOrders {
-		if strconv.FormatInt(order.OrderID, 10) == orderID {
+		b.rememberOrderSymbol(order.OrderID, order.Symbol)
+
+		if strconv.FormatInt(order.OrderID, 10) == orderID || order.ClientOrderID == orderID {
 			return mapBinanceOrderStatus(order.Status), nil
 		}
 	}
 
-	// Order not in open orders - it might be filled or cancelled
-	// We would need the symbol to query specific order, returning failed for now
+	// Without the symbol a closed order cannot be queried
 	return types.OrderStatusFailed, nil
 }
 
+// queryOrderStatus returns the status of the order selected by service.
+func (b *BinanceTradingSystemProvider) queryOrderStatus(ctx context.Context, service GetOrderService) (types.OrderStatus, error) {
+	if err := b.rateLimiter.Wait(ctx, binanceWeightGetOrder); err != nil {
+		return types.OrderStatusFailed, errors.Wrap(errors.ErrCodeOrderFailed, "rate limiter wait aborted", err)
+	}
+
+	order, err := service.Do(ctx)
+	if err != nil {
+		return types.OrderStatusFailed, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get order from Binance", err)
+	}
+
+	return mapBinanceOrderStatus(order.Status), nil
+}
+
 // GetAccountInfo returns the current account state.
 func (b *BinanceTradingSystemProvider) GetAccountInfo() (types.AccountInfo, error) {
 	ctx := context.Background()
@@ -592,23 +1113,32 @@ func (b *BinanceTradingSystemProvider) GetAccountInfo() (types.AccountInfo, erro
 		return types.AccountInfo{}, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get account info from Binance", err)
 	}
 
-	// Calculate total balance in quote currency (assuming USDT as base)
-	var totalBalance float64
+	// The aggregated balance counts the stablecoins at face value, the buying
+	// power the free balance of the quote assets
+	var totalBalance, buyingPower float64
 
-	for _, balance := range account.Balances {
-		if balance.Asset == "USDT" || balance.Asset == "BUSD" || balance.Asset == "USD" {
-			free, _ := strconv.ParseFloat(balance.Free, 64)
-			locked, _ := strconv.ParseFloat(balance.Locked, 64)
-			totalBalance += free + locked
-		}
+	quoteAssets := b.quoteAssets
+	if quoteAssets == nil {
+		quoteAssets = b.stablecoinAssets
 	}
 
-	// Calculate buying power (free balance in quote currency)
-	var buyingPower float64
+	balances := make(map[string]types.AssetBalance, len(account.Balances))
 
 	for _, balance := range account.Balances {
-		if balance.Asset == "USDT" || balance.Asset == "BUSD" || balance.Asset == "USD" {
-			free, _ := strconv.ParseFloat(balance.Free, 64)
+		free, _ := strconv.ParseFloat(balance.Free, 64)
+		locked, _ := strconv.ParseFloat(balance.Locked, 64)
+
+		if free+locked <= 0 {
+			continue
+		}
+
+		balances[balance.Asset] = types.AssetBalance{Free: free, Locked: locked}
+
+		if b.stablecoinAssets[balance.Asset] {
+			totalBalance += free + locked
+		}
+
+		if quoteAssets[balance.Asset] {
 			buyingPower += free
 		}
 	}
@@ -621,6 +1151,8 @@ func (b *BinanceTradingSystemProvider) GetAccountInfo() (types.AccountInfo, erro
 		UnrealizedPnL: 0, // Would need current prices to calculate
 		TotalFees:     0, // Not directly available from account info
 		MarginUsed:    0, // Not applicable for spot
+		FreeMargin:    0, // Not applicable for spot
+		Balances:      balances,
 	}, nil
 }
 
@@ -691,6 +1223,10 @@ func (b *BinanceTradingSystemProvider) GetPrices(symbols []string) (map[string]f
 func (b *BinanceTradingSystemProvider) GetOpenOrders() ([]types.ExecuteOrder, error) {
 	ctx := context.Background()
 
+	if err := b.rateLimiter.Wait(ctx, binanceWeightOpenOrdersAll); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "rate limiter wait aborted", err)
+	}
+
 	binanceOrders, err := b.client.NewListOpenOrdersService().Do(ctx)
 	if err != nil {
 		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get open orders from Binance", err)
@@ -699,6 +1235,8 @@ func (b *BinanceTradingSystemProvider) GetOpenOrders() ([]types.ExecuteOrder, er
 	orders := make([]types.ExecuteOrder, 0, len(binanceOrders))
 
 	for _, bo := range binanceOrders {
+		b.rememberOrderSymbol(bo.OrderID, bo.Symbol)
+
 		order, convertErr := convertBinanceOrderToExecuteOrder(bo)
 		if convertErr != nil {
 			continue // Skip orders that can't be converted
@@ -710,7 +1248,8 @@ func (b *BinanceTradingSystemProvider) GetOpenOrders() ([]types.ExecuteOrder, er
 	return orders, nil
 }
 
-// GetTrades returns executed trades with optional filtering.
+// GetTrades returns executed trades with optional filtering. Trades of orders
+// the provider placed carry the ID the order was placed with.
 func (b *BinanceTradingSystemProvider) GetTrades(filter types.TradeFilter) ([]types.Trade, error) {
 	ctx := context.Background()
 
@@ -732,21 +1271,106 @@ func (b *BinanceTradingSystemProvider) GetTrades(filter types.TradeFilter) ([]ty
 		tradeService = tradeService.EndTime(filter.EndTime.UnixMilli())
 	}
 
+	if err := b.rateLimiter.Wait(ctx, binanceWeightAccountTrades); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "rate limiter wait aborted", err)
+	}
+
 	binanceTrades, err := tradeService.Do(ctx)
 	if err != nil {
 		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get trades from Binance", err)
 	}
 
 	trades := make([]types.Trade, 0, len(binanceTrades))
+	fees := b.newBNBFeeValuer(filter.Symbol)
 
 	for _, bt := range binanceTrades {
 		trade := convertBinanceTradeToTrade(bt, filter.Symbol)
+		trade.Order.OrderID = b.placedOrderID(bt.OrderID)
+		fees.apply(&trade, bt)
 		trades = append(trades, trade)
 	}
 
 	return trades, nil
 }
 
+// GetTradesPage returns one page of executed trades of a symbol, oldest first.
+// Pages are walked with Binance's fromId: the cursor is the id of the last trade
+// returned. Binance does not combine fromId with a time range, so after the first
+// page the time range is applied to the returned trades instead.
+func (b *BinanceTradingSystemProvider) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	ctx := context.Background()
+
+	if filter.Symbol == "" {
+		return types.TradePage{}, errors.New(errors.ErrCodeInvalidParameter, "symbol is required for GetTradesPage on Binance")
+	}
+
+	pageSize := filter.Limit
+	if pageSize <= 0 {
+		pageSize = binanceDefaultTradesLimit
+	}
+
+	tradeService := b.client.NewListTradesService().Symbol(filter.Symbol).Limit(pageSize)
+
+	switch {
+	case filter.Cursor != "":
+		lastID, err := strconv.ParseInt(filter.Cursor, 10, 64)
+		if err != nil || lastID < 0 {
+			return types.TradePage{}, errors.Newf(errors.ErrCodeInvalidParameter, "invalid trade cursor %q", filter.Cursor)
+		}
+
+		tradeService = tradeService.FromID(lastID + 1)
+	case !filter.StartTime.IsZero() || !filter.EndTime.IsZero():
+		if !filter.StartTime.IsZero() {
+			tradeService = tradeService.StartTime(filter.StartTime.UnixMilli())
+		}
+
+		if !filter.EndTime.IsZero() {
+			tradeService = tradeService.EndTime(filter.EndTime.UnixMilli())
+		}
+	default:
+		// Without fromId Binance returns the most recent trades, so start at the oldest
+		tradeService = tradeService.FromID(0)
+	}
+
+	if err := b.rateLimiter.Wait(ctx, binanceWeightAccountTrades); err != nil {
+		return types.TradePage{}, errors.Wrap(errors.ErrCodeOrderFailed, "rate limiter wait aborted", err)
+	}
+
+	binanceTrades, err := tradeService.Do(ctx)
+	if err != nil {
+		return types.TradePage{}, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get trades from Binance", err)
+	}
+
+	page := types.TradePage{Trades: make([]types.Trade, 0, len(binanceTrades)), NextCursor: ""}
+	pastEnd := false
+	fees := b.newBNBFeeValuer(filter.Symbol)
+
+	for _, bt := range binanceTrades {
+		trade := convertBinanceTradeToTrade(bt, filter.Symbol)
+		trade.Order.OrderID = b.placedOrderID(bt.OrderID)
+		fees.apply(&trade, bt)
+
+		if !filter.StartTime.IsZero() && trade.ExecutedAt.Before(filter.StartTime) {
+			continue
+		}
+
+		if !filter.EndTime.IsZero() && trade.ExecutedAt.After(filter.EndTime) {
+			pastEnd = true
+
+			break
+		}
+
+		page.Trades = append(page.Trades, trade)
+	}
+
+	// A full page may be followed by more trades; an empty next page ends the walk
+	if len(binanceTrades) == pageSize && !pastEnd {
+		page.NextCursor = strconv.FormatInt(binanceTrades[len(binanceTrades)-1].ID, 10)
+	}
+
+	return page, nil
+}
+
 // GetMaxBuyQuantity returns the maximum quantity that can be bought at the given price.
 // It takes into account the current balance and commission fees.
 func (b *BinanceTradingSystemProvider) GetMaxBuyQuantity(symbol string, price float64) (float64, error) {
@@ -761,25 +1385,10 @@ func (b *BinanceTradingSystemProvider) GetMaxBuyQuantity(symbol string, price fl
 
 	buyingPower := accountInfo.BuyingPower
 
-	// Try to fetch symbol-specific trade fee to reserve enough balance for commission.
-	// If this call fails or returns invalid data, we fall back to a default fee rate.
-	var feeRate float64 = 0.001 // Default 0.1% fee rate as fallback
-
-	if symbol != "" {
-		ctx := context.Background()
-		tradeFees, feeErr := b.client.NewTradeFeeService().Symbol(symbol).Do(ctx)
-
-		if feeErr == nil && len(tradeFees) > 0 {
-			feeInfo := tradeFees[0]
-
-			// Use taker fee as it's typically the higher rate and safer for market orders
-			if feeInfo.TakerCommission != "" {
-				if takerFee, parseErr := strconv.ParseFloat(feeInfo.TakerCommission, 64); parseErr == nil {
-					feeRate = takerFee
-				}
-			}
-		}
-	}
+	// Reserve enough balance for the taker commission, as it is typically the
+	// higher rate and safer for market orders. With BNB fee payment the
+	// discounted rate applies while the free BNB covers the fee.
+	feeRate := b.effectiveFeeRate(symbol, b.tradeFeeRates(symbol).taker, buyingPower, accountInfo.Balances)
 
 	// Adjust buying power to account for fees
 	// effectiveBuyingPower = buyingPower / (1 + feeRate)
@@ -788,7 +1397,7 @@ func (b *BinanceTradingSystemProvider) GetMaxBuyQuantity(symbol string, price fl
 	// Max quantity = effective buying power / price
 	maxQty := effectiveBuyingPower / price
 
-	return maxQty, nil
+	return utils.RoundToDecimalPrecision(maxQty, b.precision(symbol)), nil
 }
 
 // GetMaxSellQuantity returns the maximum quantity that can be sold for a symbol.
@@ -798,7 +1407,24 @@ func (b *BinanceTradingSystemProvider) GetMaxSellQuantity(symbol string) (float6
 		return 0, err
 	}
 
-	return position.TotalLongPositionQuantity, nil
+	return utils.RoundToDecimalPrecision(position.TotalLongPositionQuantity, b.precision(symbol)), nil
+}
+
+// CalculatePositionSize sizes a position by the risk taken to its stop, using
+// the account equity reported by Binance, rounded down to the decimal precision
+// of the symbol.
+func (b *BinanceTradingSystemProvider) CalculatePositionSize(symbol string, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	accountInfo, err := b.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	quantity, err := RiskPositionSize(accountInfo.Equity, entryPrice, stopPrice, riskFraction)
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.RoundToDecimalPrecision(quantity, b.precision(symbol)), nil
 }
 
 // CheckConnection verifies if the trading provider is connected by performing a health check.
@@ -832,6 +1458,73 @@ func (b *BinanceTradingSystemProvider) emitStatus(status types.ProviderConnectio
 	}
 }
 
+// rememberOrderSymbol records the symbol of an order for GetOrderStatus.
+func (b *BinanceTradingSystemProvider) rememberOrderSymbol(orderID int64, symbol string) {
+	if symbol == "" {
+		return
+	}
+
+	b.orderSymbolsMu.Lock()
+	defer b.orderSymbolsMu.Unlock()
+
+	b.orderSymbols[strconv.FormatInt(orderID, 10)] = symbol
+}
+
+// rememberClientOrder records the symbol and Binance order ID of an order placed
+// with a client order ID. A zero orderID records only the symbol.
+func (b *BinanceTradingSystemProvider) rememberClientOrder(clientOrderID string, orderID int64, symbol string) {
+	if clientOrderID == "" || symbol == "" {
+		return
+	}
+
+	b.orderSymbolsMu.Lock()
+	defer b.orderSymbolsMu.Unlock()
+
+	b.clientOrderSymbols[clientOrderID] = symbol
+
+	if orderID != 0 {
+		b.clientOrderIDs[orderID] = clientOrderID
+	}
+}
+
+// clientOrderSymbol returns the recorded symbol of an order placed with the client order ID.
+func (b *BinanceTradingSystemProvider) clientOrderSymbol(clientOrderID string) (string, bool) {
+	b.orderSymbolsMu.Lock()
+	defer b.orderSymbolsMu.Unlock()
+
+	symbol, ok := b.clientOrderSymbols[clientOrderID]
+
+	return symbol, ok
+}
+
+// placedOrderID returns the client order ID a Binance order was placed with,
+// or the Binance order ID for orders the provider did not place.
+func (b *BinanceTradingSystemProvider) placedOrderID(orderID int64) string {
+	b.orderSymbolsMu.Lock()
+	defer b.orderSymbolsMu.Unlock()
+
+	if clientOrderID, ok := b.clientOrderIDs[orderID]; ok {
+		return clientOrderID
+	}
+
+	return strconv.FormatInt(orderID, 10)
+}
+
+// orderSymbol returns the recorded symbol of an order.
+func (b *BinanceTradingSystemProvider) orderSymbol(orderID string) (string, bool) {
+	b.orderSymbolsMu.Lock()
+	defer b.orderSymbolsMu.Unlock()
+
+	symbol, ok := b.orderSymbols[orderID]
+
+	return symbol, ok
+}
+
+// precision returns the decimal precision order quantities of symbol are rounded to.
+func (b *BinanceTradingSystemProvider) precision(symbol string) int {
+	return utils.SymbolDecimalPrecision(b.symbolDecimalPrecision, symbol, b.decimalPrecision)
+}
+
 // Helper functions
 
 // mapBinanceOrderStatus maps Binance order status to our OrderStatus type.
@@ -852,9 +1545,97 @@ func mapBinanceOrderStatus(status binance.OrderStatusType) types.OrderStatus {
 	}
 }
 
+// toBinanceTimeInForce maps our TimeInForce to the Binance one. An empty time
+// in force is good-till-cancelled.
+func toBinanceTimeInForce(timeInForce types.TimeInForce) binance.TimeInForceType {
+	switch timeInForce {
+	case types.TimeInForceIOC:
+		return binance.TimeInForceTypeIOC
+	case types.TimeInForceFOK:
+		return binance.TimeInForceTypeFOK
+	default:
+		return binance.TimeInForceTypeGTC
+	}
+}
+
+// mapBinanceTimeInForce maps a Binance time in force to our TimeInForce type.
+func mapBinanceTimeInForce(timeInForce binance.TimeInForceType) types.TimeInForce {
+	switch timeInForce {
+	case binance.TimeInForceTypeIOC:
+		return types.TimeInForceIOC
+	case binance.TimeInForceTypeFOK:
+		return types.TimeInForceFOK
+	default:
+		return types.TimeInForceGTC
+	}
+}
+
+// retryableBinanceErrorCodes are Binance API error codes caused by load or
+// connectivity on Binance's side rather than by the request itself.
+var retryableBinanceErrorCodes = map[int64]bool{
+	-1000: true, // UNKNOWN
+	-1001: true, // DISCONNECTED
+	-1003: true, // TOO_MANY_REQUESTS (HTTP 429/418)
+	-1006: true, // UNEXPECTED_RESP
+	-1007: true, // TIMEOUT
+	-1008: true, // SERVER_BUSY
+	-1015: true, // TOO_MANY_ORDERS
+}
+
+// isRetryableBinanceError reports whether a failed request may succeed when sent again.
+// Network errors, rate limits and 5xx responses are retryable; request errors
+// such as insufficient balance or an invalid symbol are not.
+func isRetryableBinanceError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) {
+		// Responses without a Binance error body come from the gateway (502/503/504)
+		return !apiErr.IsValid() || retryableBinanceErrorCodes[apiErr.Code]
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isBinanceDuplicateOrderError reports whether Binance rejected an order because
+// its client order ID has already been used.
+func isBinanceDuplicateOrderError(err error) bool {
+	var apiErr *common.APIError
+
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.Message, "Duplicate order")
+}
+
+// binanceClientOrderID returns the client order ID an order with orderID is
+// placed with: the order ID itself, or a generated one when Binance would not
+// accept it.
+func binanceClientOrderID(orderID string) string {
+	if binanceClientOrderIDPattern.MatchString(orderID) {
+		return orderID
+	}
+
+	return uuid.NewString()
+}
+
+// binanceOrderError wraps an error placing an order. When Binance refused the
+// order, such as for failing a lot size or notional filter, the error carries
+// ErrCodeOrderRejected with Binance's message as the reason.
+func binanceOrderError(message string, err error) error {
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) && apiErr.IsValid() && !isRetryableBinanceError(err) {
+		return errors.Wrap(errors.ErrCodeOrderRejected, apiErr.Message, err)
+	}
+
+	return errors.Wrap(errors.ErrCodeOrderFailed, message, err)
+}
+
 // convertBinanceOrderToExecuteOrder converts a Binance order to our ExecuteOrder type.
 func convertBinanceOrderToExecuteOrder(bo *binance.Order) (types.ExecuteOrder, error) {
 	quantity, _ := strconv.ParseFloat(bo.OrigQuantity, 64)
+	filledQuantity, _ := strconv.ParseFloat(bo.ExecutedQuantity, 64)
 	price, _ := strconv.ParseFloat(bo.Price, 64)
 
 	var side types.PurchaseType
@@ -888,12 +1669,18 @@ func convertBinanceOrderToExecuteOrder(bo *binance.Order) (types.ExecuteOrder, e
 			Reason:  types.OrderReasonStrategy,
 			Message: "Order from Binance",
 		},
-		Price:        price,
-		StrategyName: "",
-		Quantity:     quantity,
-		PositionType: types.PositionTypeLong, // Spot only supports long
-		TakeProfit:   optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
-		StopLoss:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		Price:          price,
+		StrategyName:   "",
+		Quantity:       quantity,
+		PositionType:   types.PositionTypeLong, // Spot only supports long
+		StopPrice:      0,
+		TrailOffset:    0,
+		TrailPercent:   false,
+		FilledQuantity: filledQuantity,
+		TimeInForce:    mapBinanceTimeInForce(bo.TimeInForce),
+		GoodTillDate:   time.Time{},
+		TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
 	}, nil
 }
 
@@ -932,6 +1719,8 @@ func convertBinanceTradeToTrade(bt *binance.TradeV3, symbol string) types.Trade
 		PnL:             0, // Not directly available from trade
 		CumulativePnL:   0, // Not directly available from trade
 		LIFOPnL:         0, // Not directly available from trade
+		RealizedPnL:     0, // Not directly available from trade
+		CostBasis:       0, // Not directly available from trade
 		OpenPositionQty: 0,
 		Balance:         0,
 		HoldTime:        0,
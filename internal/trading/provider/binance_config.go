@@ -2,6 +2,7 @@ package tradingprovider
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/rxtech-lab/argo-trading/pkg/errors"
@@ -12,6 +13,89 @@ type BinanceProviderConfig struct {
 	ApiKey    string `json:"apiKey" jsonschema:"title=API Key,description=Binance API key" keychain:"true" validate:"required"`
 	SecretKey string `json:"secretKey" jsonschema:"title=Secret Key,description=Binance API secret key" keychain:"true" validate:"required"`
 	BaseURL   string `json:"baseUrl,omitempty" jsonschema:"title=Base URL,description=Custom REST API base URL (optional). If set takes precedence over useTestnet."`
+	// RetryMaxAttempts is the number of attempts made to place an order, including the first one.
+	RetryMaxAttempts int `json:"retryMaxAttempts,omitempty" jsonschema:"title=Retry Max Attempts,description=Attempts made to place an order when Binance is unavailable (optional). Defaults to 3." validate:"omitempty,min=1,max=10"`
+	// RetryBaseDelayMs is the backoff before the first retry in milliseconds. It doubles on every retry.
+	RetryBaseDelayMs int `json:"retryBaseDelayMs,omitempty" jsonschema:"title=Retry Base Delay (ms),description=Wait before the first retry in milliseconds (optional). Defaults to 200." validate:"omitempty,min=1"`
+	// RetryMaxDelayMs caps the backoff between two attempts in milliseconds.
+	RetryMaxDelayMs int `json:"retryMaxDelayMs,omitempty" jsonschema:"title=Retry Max Delay (ms),description=Longest wait between two attempts in milliseconds (optional). Defaults to 5000." validate:"omitempty,min=1"`
+	// RateLimitPerMinute is the request weight the provider may spend per minute.
+	RateLimitPerMinute int `json:"rateLimitPerMinute,omitempty" jsonschema:"title=Rate Limit (weight/min),description=Binance request weight the provider may use per minute (optional). Defaults to 1200." validate:"omitempty,min=1"`
+	// SymbolDecimalPrecision is the number of decimal places order quantities of
+	// individual symbols are rounded to, keyed by Binance symbol.
+	SymbolDecimalPrecision map[string]int `json:"symbolDecimalPrecision,omitempty" jsonschema:"title=Symbol Decimal Precision,description=Decimal places allowed for the order quantity of individual symbols keyed by symbol such as BTCUSDT (optional). Other symbols use 8." validate:"omitempty,dive,min=0"`
+	// StablecoinAssets are the assets summed into the aggregated account balance,
+	// and into the buying power unless QuoteAssets is set.
+	StablecoinAssets []string `json:"stablecoinAssets,omitempty" jsonschema:"title=Stablecoin Assets,description=Assets counted at face value in the account balance and buying power such as USDT and USDC (optional). Defaults to USDT BUSD and USD." validate:"omitempty,dive,required"`
+	// QuoteAssets are the assets whose free balance makes up the buying power,
+	// e.g. USDC alone when trading USDC-quoted pairs.
+	QuoteAssets []string `json:"quoteAssets,omitempty" jsonschema:"title=Quote Assets,description=Assets whose free balance counts toward the buying power such as USDC for USDC-quoted pairs (optional). Defaults to the stablecoin assets." validate:"omitempty,dive,required"`
+	// PayFeesInBNB is whether the account has BNB fee payment enabled, which
+	// Binance charges at a discount while the free BNB covers the fee.
+	PayFeesInBNB bool `json:"payFeesInBnb,omitempty" jsonschema:"title=Pay Fees In BNB,description=Whether trading fees are paid in BNB at a discount (optional). Must match the BNB fee setting of the Binance account."`
+	// BNBFeeDiscount is the fraction taken off the trading fee when it is paid in BNB.
+	BNBFeeDiscount float64 `json:"bnbFeeDiscount,omitempty" jsonschema:"title=BNB Fee Discount,description=Fraction taken off the trading fee when it is paid in BNB (optional). Defaults to 0.25." validate:"omitempty,gt=0,lt=1"`
+}
+
+// StablecoinAssetSet returns the configured stablecoin assets, or the defaults when unset.
+func (c *BinanceProviderConfig) StablecoinAssetSet() map[string]bool {
+	if len(c.StablecoinAssets) == 0 {
+		return assetSet(DefaultBinanceStablecoinAssets)
+	}
+
+	return assetSet(c.StablecoinAssets)
+}
+
+// QuoteAssetSet returns the configured quote assets, or the stablecoin assets when unset.
+func (c *BinanceProviderConfig) QuoteAssetSet() map[string]bool {
+	if len(c.QuoteAssets) == 0 {
+		return c.StablecoinAssetSet()
+	}
+
+	return assetSet(c.QuoteAssets)
+}
+
+// RequestWeightPerMinute returns the configured request weight budget, or the
+// default when unset.
+func (c *BinanceProviderConfig) RequestWeightPerMinute() int {
+	if c.RateLimitPerMinute > 0 {
+		return c.RateLimitPerMinute
+	}
+
+	return DefaultBinanceRateLimitPerMinute
+}
+
+// FeeAssetDiscount returns the discount on fees paid in BNB, the default when
+// BNBFeeDiscount is unset, or 0 when PayFeesInBNB is off.
+func (c *BinanceProviderConfig) FeeAssetDiscount() float64 {
+	if !c.PayFeesInBNB {
+		return 0
+	}
+
+	if c.BNBFeeDiscount > 0 {
+		return c.BNBFeeDiscount
+	}
+
+	return DefaultBinanceBNBFeeDiscount
+}
+
+// RetryPolicy returns the order retry policy, using the defaults for unset fields.
+func (c *BinanceProviderConfig) RetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+
+	if c.RetryMaxAttempts > 0 {
+		policy.MaxAttempts = c.RetryMaxAttempts
+	}
+
+	if c.RetryBaseDelayMs > 0 {
+		policy.BaseDelay = time.Duration(c.RetryBaseDelayMs) * time.Millisecond
+	}
+
+	if c.RetryMaxDelayMs > 0 {
+		policy.MaxDelay = time.Duration(c.RetryMaxDelayMs) * time.Millisecond
+	}
+
+	return policy
 }
 
 // Validate validates the BinanceProviderConfig struct.
@@ -37,3 +121,13 @@ func parseBinanceConfig(jsonConfig string) (*BinanceProviderConfig, error) {
 
 	return &config, nil
 }
+
+// assetSet returns the set of the given assets.
+func assetSet(assets []string) map[string]bool {
+	set := make(map[string]bool, len(assets))
+	for _, asset := range assets {
+		set[asset] = true
+	}
+
+	return set
+}
@@ -2,6 +2,7 @@ package tradingprovider
 
 import (
 	"testing"
+	"time"
 
 	"github.com/rxtech-lab/argo-trading/pkg/strategy"
 	"github.com/stretchr/testify/suite"
@@ -19,3 +20,105 @@ func (suite *BinanceConfigTestSuite) TestKeychainFields() {
 	fields := strategy.GetKeychainFields(BinanceProviderConfig{})
 	suite.Equal([]string{"apiKey", "secretKey"}, fields)
 }
+
+func (suite *BinanceConfigTestSuite) TestRetryPolicy() {
+	tests := []struct {
+		name     string
+		config   BinanceProviderConfig
+		expected RetryPolicy
+	}{
+		{
+			name:     "defaults when unset",
+			config:   BinanceProviderConfig{ApiKey: "key", SecretKey: "secret"},
+			expected: DefaultRetryPolicy(),
+		},
+		{
+			name: "configured values",
+			config: BinanceProviderConfig{
+				ApiKey:           "key",
+				SecretKey:        "secret",
+				RetryMaxAttempts: 5,
+				RetryBaseDelayMs: 50,
+				RetryMaxDelayMs:  1000,
+			},
+			expected: RetryPolicy{MaxAttempts: 5, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second},
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			suite.NoError(tc.config.Validate())
+			suite.Equal(tc.expected, tc.config.RetryPolicy())
+		})
+	}
+}
+
+func (suite *BinanceConfigTestSuite) TestRetryPolicy_InvalidMaxAttempts() {
+	config := BinanceProviderConfig{ApiKey: "key", SecretKey: "secret", RetryMaxAttempts: 11}
+	suite.Error(config.Validate())
+}
+
+func (suite *BinanceConfigTestSuite) TestRequestWeightPerMinute() {
+	config := BinanceProviderConfig{ApiKey: "key", SecretKey: "secret"}
+	suite.Equal(DefaultBinanceRateLimitPerMinute, config.RequestWeightPerMinute())
+
+	config.RateLimitPerMinute = 300
+	suite.Equal(300, config.RequestWeightPerMinute())
+}
+
+func (suite *BinanceConfigTestSuite) TestSymbolDecimalPrecision() {
+	config, err := parseBinanceConfig(`{"apiKey":"key","secretKey":"secret","symbolDecimalPrecision":{"BTCUSDT":5,"DOGEUSDT":0}}`)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]int{"BTCUSDT": 5, "DOGEUSDT": 0}, config.SymbolDecimalPrecision)
+
+	provider, err := NewBinanceTradingSystemProvider(*config, true)
+	suite.Require().NoError(err)
+	suite.Equal(5, provider.precision("BTCUSDT"))
+	suite.Equal(0, provider.precision("DOGEUSDT"))
+	suite.Equal(BinanceDecimalPrecision, provider.precision("ETHUSDT"))
+
+	_, err = parseBinanceConfig(`{"apiKey":"key","secretKey":"secret","symbolDecimalPrecision":{"BTCUSDT":-1}}`)
+	suite.Error(err)
+}
+
+func (suite *BinanceConfigTestSuite) TestStablecoinAssets() {
+	config, err := parseBinanceConfig(`{"apiKey":"key","secretKey":"secret"}`)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]bool{"USDT": true, "BUSD": true, "USD": true}, config.StablecoinAssetSet())
+
+	config, err = parseBinanceConfig(`{"apiKey":"key","secretKey":"secret","stablecoinAssets":["USDT","USDC","FDUSD"]}`)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]bool{"USDT": true, "USDC": true, "FDUSD": true}, config.StablecoinAssetSet())
+
+	_, err = parseBinanceConfig(`{"apiKey":"key","secretKey":"secret","stablecoinAssets":[""]}`)
+	suite.Error(err)
+}
+
+func (suite *BinanceConfigTestSuite) TestQuoteAssets() {
+	config, err := parseBinanceConfig(`{"apiKey":"key","secretKey":"secret","stablecoinAssets":["USDT","USDC"]}`)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]bool{"USDT": true, "USDC": true}, config.QuoteAssetSet(), "defaults to the stablecoin assets")
+
+	config, err = parseBinanceConfig(`{"apiKey":"key","secretKey":"secret","quoteAssets":["USDC"]}`)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]bool{"USDC": true}, config.QuoteAssetSet())
+	suite.Equal(map[string]bool{"USDT": true, "BUSD": true, "USD": true}, config.StablecoinAssetSet())
+
+	_, err = parseBinanceConfig(`{"apiKey":"key","secretKey":"secret","quoteAssets":[""]}`)
+	suite.Error(err)
+}
+
+func (suite *BinanceConfigTestSuite) TestFeeAssetDiscount() {
+	config := BinanceProviderConfig{ApiKey: "key", SecretKey: "secret"}
+	suite.Equal(0.0, config.FeeAssetDiscount())
+
+	config.PayFeesInBNB = true
+	suite.Equal(DefaultBinanceBNBFeeDiscount, config.FeeAssetDiscount())
+
+	config.BNBFeeDiscount = 0.1
+	suite.NoError(config.Validate())
+	suite.Equal(0.1, config.FeeAssetDiscount())
+
+	config.BNBFeeDiscount = 1
+	suite.Error(config.Validate())
+}
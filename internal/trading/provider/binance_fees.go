@@ -0,0 +1,165 @@
+package tradingprovider
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+const (
+	// binanceDefaultFeeRate is the fee rate assumed when Binance does not report
+	// the rates of a symbol.
+	binanceDefaultFeeRate = 0.001 // 0.1%
+
+	// binanceFeeAsset is the asset Binance charges discounted fees in.
+	binanceFeeAsset = "BNB"
+)
+
+// binanceFeeRates are the standard commission rates of a symbol.
+type binanceFeeRates struct {
+	maker float64
+	taker float64
+}
+
+// bnbFeeValuer values the commissions of a symbol's trades that Binance
+// charged in BNB. Their amount is in BNB, so the fee is recorded in the quote
+// asset at the BNB-discounted rate instead. The symbol's rates are fetched
+// once, with the first such trade.
+type bnbFeeValuer struct {
+	provider *BinanceTradingSystemProvider
+	symbol   string
+	rates    *binanceFeeRates
+}
+
+// tradeFeeRates fetches the commission rates of a symbol, falling back to the
+// default rate when the call fails or returns invalid data.
+func (b *BinanceTradingSystemProvider) tradeFeeRates(symbol string) binanceFeeRates {
+	rates := binanceFeeRates{maker: binanceDefaultFeeRate, taker: binanceDefaultFeeRate}
+
+	if symbol == "" {
+		return rates
+	}
+
+	tradeFees, err := b.client.NewTradeFeeService().Symbol(symbol).Do(context.Background())
+	if err != nil || len(tradeFees) == 0 {
+		return rates
+	}
+
+	if maker, parseErr := strconv.ParseFloat(tradeFees[0].MakerCommission, 64); parseErr == nil {
+		rates.maker = maker
+	}
+
+	if taker, parseErr := strconv.ParseFloat(tradeFees[0].TakerCommission, 64); parseErr == nil {
+		rates.taker = taker
+	}
+
+	return rates
+}
+
+// bnbFeeRate returns the rate a fee of the given standard rate is charged at
+// when paid in BNB.
+func (b *BinanceTradingSystemProvider) bnbFeeRate(rate float64) float64 {
+	return rate * (1 - b.bnbFeeDiscount)
+}
+
+// effectiveFeeRate returns the rate the fee of an order of notional, in the
+// quote asset of symbol, is charged at. With BNB fee payment it is the
+// discounted rate when the free BNB in balances covers the discounted fee.
+// Otherwise, or when the BNB price is unknown, Binance charges the standard
+// rate in the traded assets.
+func (b *BinanceTradingSystemProvider) effectiveFeeRate(symbol string, rate, notional float64, balances map[string]types.AssetBalance) float64 {
+	if b.bnbFeeDiscount <= 0 {
+		return rate
+	}
+
+	bnbPrice, ok := b.bnbPrice(symbol)
+	if !ok {
+		return rate
+	}
+
+	discounted := b.bnbFeeRate(rate)
+	if balances[binanceFeeAsset].Free < notional*discounted/bnbPrice {
+		return rate
+	}
+
+	return discounted
+}
+
+// bnbPrice returns the price of one BNB in the quote asset of symbol.
+func (b *BinanceTradingSystemProvider) bnbPrice(symbol string) (float64, bool) {
+	quote := b.quoteAssetOf(symbol)
+
+	switch quote {
+	case "":
+		return 0, false
+	case binanceFeeAsset:
+		return 1, true
+	}
+
+	pair := binanceFeeAsset + quote
+
+	prices, err := b.GetPrices([]string{pair})
+	if err != nil {
+		return 0, false
+	}
+
+	price, ok := prices[pair]
+
+	return price, ok
+}
+
+// quoteAssetOf returns the quote asset of symbol: the longest of BNB, the
+// quote assets and the stablecoin assets the symbol ends with, or "" when it
+// ends with none of them.
+func (b *BinanceTradingSystemProvider) quoteAssetOf(symbol string) string {
+	quote := ""
+
+	consider := func(asset string) {
+		if len(asset) > len(quote) && len(asset) < len(symbol) && strings.HasSuffix(symbol, asset) {
+			quote = asset
+		}
+	}
+
+	consider(binanceFeeAsset)
+
+	for asset := range b.quoteAssets {
+		consider(asset)
+	}
+
+	for asset := range b.stablecoinAssets {
+		consider(asset)
+	}
+
+	return quote
+}
+
+// newBNBFeeValuer returns the valuer of the BNB commissions of symbol's trades.
+func (b *BinanceTradingSystemProvider) newBNBFeeValuer(symbol string) *bnbFeeValuer {
+	return &bnbFeeValuer{provider: b, symbol: symbol, rates: nil}
+}
+
+// apply records the fee of trade, converted from bt, in the quote asset when
+// its commission was paid in BNB at the discounted rate. Commissions charged
+// in the traded assets keep the standard amount Binance reported.
+func (v *bnbFeeValuer) apply(trade *types.Trade, bt *binance.TradeV3) {
+	if v.provider.bnbFeeDiscount <= 0 || bt.CommissionAsset != binanceFeeAsset {
+		return
+	}
+
+	if v.rates == nil {
+		rates := v.provider.tradeFeeRates(v.symbol)
+		v.rates = &rates
+	}
+
+	rate := v.rates.taker
+	if bt.IsMaker {
+		rate = v.rates.maker
+	}
+
+	fee := trade.ExecutedQty * trade.ExecutedPrice * v.provider.bnbFeeRate(rate)
+	trade.Fee = fee
+	trade.Order.Fee = fee
+}
@@ -0,0 +1,225 @@
+package tradingprovider
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// binanceFilterEpsilon absorbs floating point error when a quantity or price
+// is divided by its step, so 0.3 is a whole number of 0.1 steps.
+const binanceFilterEpsilon = 1e-9
+
+// binanceSymbolFilters are the trading rules Binance enforces on the orders of
+// a symbol: the LOT_SIZE, PRICE_FILTER and NOTIONAL (or legacy MIN_NOTIONAL)
+// filters of its exchange info. Zero values are rules the symbol does not have.
+type binanceSymbolFilters struct {
+	// baseAsset is the asset the orders of the symbol buy and sell, BTC for BTCUSDT.
+	baseAsset string
+
+	stepSize     float64
+	stepDecimals int
+	minQuantity  float64
+	maxQuantity  float64
+
+	tickSize     float64
+	tickDecimals int
+	minPrice     float64
+	maxPrice     float64
+
+	minNotional float64
+	// minNotionalForMarket is whether minNotional also applies to market orders.
+	minNotionalForMarket bool
+}
+
+// newBinanceSymbolFilters reads the filters of a symbol's exchange info.
+func newBinanceSymbolFilters(symbol *binance.Symbol) binanceSymbolFilters {
+	var filters binanceSymbolFilters
+
+	filters.baseAsset = symbol.BaseAsset
+
+	if lotSize := symbol.LotSizeFilter(); lotSize != nil {
+		filters.stepSize, filters.stepDecimals = parseBinanceStep(lotSize.StepSize)
+		filters.minQuantity = parseBinanceNumber(lotSize.MinQuantity)
+		filters.maxQuantity = parseBinanceNumber(lotSize.MaxQuantity)
+	}
+
+	if price := symbol.PriceFilter(); price != nil {
+		filters.tickSize, filters.tickDecimals = parseBinanceStep(price.TickSize)
+		filters.minPrice = parseBinanceNumber(price.MinPrice)
+		filters.maxPrice = parseBinanceNumber(price.MaxPrice)
+	}
+
+	if notional := symbol.NotionalFilter(); notional != nil {
+		filters.minNotional = parseBinanceNumber(notional.MinNotional)
+		filters.minNotionalForMarket = notional.ApplyMinToMarket
+	}
+
+	// Older symbols describe the minimum notional with MIN_NOTIONAL instead
+	for _, filter := range symbol.Filters {
+		if filter["filterType"] != string(binance.SymbolFilterTypeMinNotional) {
+			continue
+		}
+
+		if value, ok := filter["minNotional"].(string); ok {
+			filters.minNotional = parseBinanceNumber(value)
+		}
+
+		if applyToMarket, ok := filter["applyToMarket"].(bool); ok {
+			filters.minNotionalForMarket = applyToMarket
+		}
+	}
+
+	return filters
+}
+
+// apply checks order against the filters and returns the quantity rounded down
+// to the lot step and the price rounded to the nearest tick. Orders that break
+// a filter fail with ErrCodeOrderRejected without reaching Binance. The
+// notional of a market order is only checked when the order carries a price.
+func (f binanceSymbolFilters) apply(order types.ExecuteOrder, quantity float64) (float64, float64, error) {
+	if f.stepSize > 0 {
+		quantity = roundToStep(math.Floor(quantity/f.stepSize+binanceFilterEpsilon)*f.stepSize, f.stepDecimals)
+	}
+
+	if quantity <= 0 || quantity < f.minQuantity {
+		return 0, 0, errors.Newf(errors.ErrCodeOrderRejected,
+			"order quantity %g of %s is below the minimum quantity %g", order.Quantity, order.Symbol, f.minQuantity)
+	}
+
+	if f.maxQuantity > 0 && quantity > f.maxQuantity {
+		return 0, 0, errors.Newf(errors.ErrCodeOrderRejected,
+			"order quantity %g of %s is above the maximum quantity %g", quantity, order.Symbol, f.maxQuantity)
+	}
+
+	price := order.Price
+	if f.tickSize > 0 && price > 0 {
+		price = roundToStep(math.Round(price/f.tickSize)*f.tickSize, f.tickDecimals)
+	}
+
+	if order.OrderType == types.OrderTypeLimit {
+		if price < f.minPrice {
+			return 0, 0, errors.Newf(errors.ErrCodeOrderRejected,
+				"order price %g of %s is below the minimum price %g", price, order.Symbol, f.minPrice)
+		}
+
+		if f.maxPrice > 0 && price > f.maxPrice {
+			return 0, 0, errors.Newf(errors.ErrCodeOrderRejected,
+				"order price %g of %s is above the maximum price %g", price, order.Symbol, f.maxPrice)
+		}
+	}
+
+	checkNotional := order.OrderType == types.OrderTypeLimit || (f.minNotionalForMarket && price > 0)
+	if checkNotional && quantity*price < f.minNotional {
+		return 0, 0, errors.Newf(errors.ErrCodeOrderRejected,
+			"order notional %g of %s is below the minimum notional %g", quantity*price, order.Symbol, f.minNotional)
+	}
+
+	return quantity, price, nil
+}
+
+// formatQuantity formats a quantity with the decimals of the lot step,
+// or precision when the symbol has no lot step.
+func (f binanceSymbolFilters) formatQuantity(quantity float64, precision int) string {
+	if f.stepSize > 0 {
+		return strconv.FormatFloat(quantity, 'f', f.stepDecimals, 64)
+	}
+
+	return strconv.FormatFloat(quantity, 'f', precision, 64)
+}
+
+// formatPrice formats a price with the decimals of the tick, or as short as
+// possible when the symbol has no tick.
+func (f binanceSymbolFilters) formatPrice(price float64) string {
+	if f.tickSize > 0 {
+		return strconv.FormatFloat(price, 'f', f.tickDecimals, 64)
+	}
+
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// exchangeFilters returns the trading rules of symbol, fetching them from
+// Binance's exchange info on first use and caching them afterwards. A symbol
+// missing from the exchange info has no rules. When the exchange info cannot
+// be fetched the order is left for Binance to check, and the rules are fetched
+// again for the next order.
+func (b *BinanceTradingSystemProvider) exchangeFilters(ctx context.Context, symbol string) binanceSymbolFilters {
+	b.symbolFiltersMu.Lock()
+	filters, ok := b.symbolFilters[symbol]
+	b.symbolFiltersMu.Unlock()
+
+	if ok {
+		return filters
+	}
+
+	if err := b.rateLimiter.Wait(ctx, binanceWeightExchangeInfo); err != nil {
+		return binanceSymbolFilters{} //nolint:exhaustruct // no rules are known
+	}
+
+	info, err := b.client.NewExchangeInfoService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		debugLog.Warn("Failed to fetch Binance exchange info, placing the order unchecked",
+			zap.String("symbol", symbol),
+			zap.Error(err),
+		)
+
+		return binanceSymbolFilters{} //nolint:exhaustruct // no rules are known
+	}
+
+	filters = binanceSymbolFilters{} //nolint:exhaustruct // the symbol is not listed
+
+	for i := range info.Symbols {
+		if info.Symbols[i].Symbol == symbol {
+			filters = newBinanceSymbolFilters(&info.Symbols[i])
+
+			break
+		}
+	}
+
+	b.symbolFiltersMu.Lock()
+	b.symbolFilters[symbol] = filters
+	b.symbolFiltersMu.Unlock()
+
+	return filters
+}
+
+// parseBinanceStep parses a step such as "0.00100000" and returns it with its
+// number of significant decimals.
+func parseBinanceStep(value string) (float64, int) {
+	step := parseBinanceNumber(value)
+	if step <= 0 {
+		return 0, 0
+	}
+
+	decimals := 0
+	if _, fraction, ok := strings.Cut(value, "."); ok {
+		decimals = len(strings.TrimRight(fraction, "0"))
+	}
+
+	return step, decimals
+}
+
+// parseBinanceNumber parses a decimal string of the exchange info, returning
+// zero for a missing or malformed value.
+func parseBinanceNumber(value string) float64 {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+
+	return number
+}
+
+// roundToStep removes the floating point error left by a multiple of a step
+// with the given number of decimals.
+func roundToStep(value float64, decimals int) float64 {
+	multiplier := math.Pow10(decimals)
+
+	return math.Round(value*multiplier) / multiplier
+}
@@ -3,11 +3,16 @@ package tradingprovider
 import (
 	"context"
 	"errors"
+	"io"
+	"net"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
 	"github.com/rxtech-lab/argo-trading/internal/types"
+	argoErrors "github.com/rxtech-lab/argo-trading/pkg/errors"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -16,25 +21,35 @@ import (
 // mockBinanceClient implements BinanceClient interface for testing
 type mockBinanceClient struct {
 	createOrderService      *mockCreateOrderService
+	createOCOService        *mockCreateOCOService
 	getAccountService       *mockGetAccountService
 	listOpenOrdersService   *mockListOpenOrdersService
+	getOrderService         *mockGetOrderService
 	cancelOrderService      *mockCancelOrderService
 	cancelOpenOrdersService *mockCancelOpenOrdersService
 	listTradesService       *mockListTradesService
 	tradeFeeService         *mockTradeFeeService
 	listPricesService       *mockListPricesService
+	startUserStreamService  *mockStartUserStreamService
+	keepaliveUserStream     *mockKeepaliveUserStreamService
+	exchangeInfoService     *mockExchangeInfoService
 }
 
 func newMockBinanceClient() *mockBinanceClient {
 	return &mockBinanceClient{
 		createOrderService:      &mockCreateOrderService{},
+		createOCOService:        &mockCreateOCOService{},
 		getAccountService:       &mockGetAccountService{},
 		listOpenOrdersService:   &mockListOpenOrdersService{},
+		getOrderService:         &mockGetOrderService{},
 		cancelOrderService:      &mockCancelOrderService{},
 		cancelOpenOrdersService: &mockCancelOpenOrdersService{},
 		listTradesService:       &mockListTradesService{},
 		tradeFeeService:         &mockTradeFeeService{},
 		listPricesService:       &mockListPricesService{},
+		startUserStreamService:  &mockStartUserStreamService{},
+		keepaliveUserStream:     &mockKeepaliveUserStreamService{},
+		exchangeInfoService:     &mockExchangeInfoService{},
 	}
 }
 
@@ -42,6 +57,10 @@ func (m *mockBinanceClient) NewCreateOrderService() CreateOrderService {
 	return m.createOrderService
 }
 
+func (m *mockBinanceClient) NewCreateOCOService() CreateOCOService {
+	return m.createOCOService
+}
+
 func (m *mockBinanceClient) NewGetAccountService() GetAccountService {
 	return m.getAccountService
 }
@@ -50,6 +69,10 @@ func (m *mockBinanceClient) NewListOpenOrdersService() ListOpenOrdersService {
 	return m.listOpenOrdersService
 }
 
+func (m *mockBinanceClient) NewGetOrderService() GetOrderService {
+	return m.getOrderService
+}
+
 func (m *mockBinanceClient) NewCancelOrderService() CancelOrderService {
 	return m.cancelOrderService
 }
@@ -70,16 +93,32 @@ func (m *mockBinanceClient) NewListPricesService() ListPricesService {
 	return m.listPricesService
 }
 
+func (m *mockBinanceClient) NewStartUserStreamService() StartUserStreamService {
+	return m.startUserStreamService
+}
+
+func (m *mockBinanceClient) NewKeepaliveUserStreamService() KeepaliveUserStreamService {
+	return m.keepaliveUserStream
+}
+
+func (m *mockBinanceClient) NewExchangeInfoService() ExchangeInfoService {
+	return m.exchangeInfoService
+}
+
 // mockCreateOrderService implements CreateOrderService
 type mockCreateOrderService struct {
-	response *binance.CreateOrderResponse
-	err      error
-	symbol   string
-	side     binance.SideType
-	orderTyp binance.OrderType
-	quantity string
-	price    string
-	tif      binance.TimeInForceType
+	response      *binance.CreateOrderResponse
+	err           error
+	errs          []error // returned by the first calls, in order, before err
+	calls         int
+	symbol        string
+	side          binance.SideType
+	orderTyp      binance.OrderType
+	quantity      string
+	price         string
+	tif           binance.TimeInForceType
+	clientOrderID string
+	onDo          func()
 }
 
 func (m *mockCreateOrderService) Symbol(symbol string) CreateOrderService {
@@ -112,7 +151,94 @@ func (m *mockCreateOrderService) TimeInForce(tif binance.TimeInForceType) Create
 	return m
 }
 
+func (m *mockCreateOrderService) NewClientOrderID(clientOrderID string) CreateOrderService {
+	m.clientOrderID = clientOrderID
+	return m
+}
+
 func (m *mockCreateOrderService) Do(_ context.Context) (*binance.CreateOrderResponse, error) {
+	m.calls++
+	if m.onDo != nil {
+		m.onDo()
+	}
+
+	if m.calls <= len(m.errs) {
+		return nil, m.errs[m.calls-1]
+	}
+
+	return m.response, m.err
+}
+
+// mockCreateOCOService implements CreateOCOService
+type mockCreateOCOService struct {
+	response           *binance.CreateOCOResponse
+	err                error
+	calls              int
+	symbol             string
+	side               binance.SideType
+	quantity           string
+	price              string
+	stopPrice          string
+	stopLimitPrice     string
+	stopLimitTIF       binance.TimeInForceType
+	listClientOrderID  string
+	limitClientOrderID string
+	stopClientOrderID  string
+}
+
+func (m *mockCreateOCOService) Symbol(symbol string) CreateOCOService {
+	m.symbol = symbol
+	return m
+}
+
+func (m *mockCreateOCOService) Side(side binance.SideType) CreateOCOService {
+	m.side = side
+	return m
+}
+
+func (m *mockCreateOCOService) Quantity(quantity string) CreateOCOService {
+	m.quantity = quantity
+	return m
+}
+
+func (m *mockCreateOCOService) Price(price string) CreateOCOService {
+	m.price = price
+	return m
+}
+
+func (m *mockCreateOCOService) StopPrice(stopPrice string) CreateOCOService {
+	m.stopPrice = stopPrice
+	return m
+}
+
+func (m *mockCreateOCOService) StopLimitPrice(stopLimitPrice string) CreateOCOService {
+	m.stopLimitPrice = stopLimitPrice
+	return m
+}
+
+func (m *mockCreateOCOService) StopLimitTimeInForce(tif binance.TimeInForceType) CreateOCOService {
+	m.stopLimitTIF = tif
+	return m
+}
+
+func (m *mockCreateOCOService) ListClientOrderID(listClientOrderID string) CreateOCOService {
+	m.listClientOrderID = listClientOrderID
+	return m
+}
+
+func (m *mockCreateOCOService) LimitClientOrderID(limitClientOrderID string) CreateOCOService {
+	m.limitClientOrderID = limitClientOrderID
+	return m
+}
+
+func (m *mockCreateOCOService) StopClientOrderID(stopClientOrderID string) CreateOCOService {
+	m.stopClientOrderID = stopClientOrderID
+	return m
+}
+
+func (m *mockCreateOCOService) Do(_ context.Context) (*binance.CreateOCOResponse, error) {
+	m.calls++
+
 	return m.response, m.err
 }
 
@@ -130,12 +256,46 @@ func (m *mockGetAccountService) Do(_ context.Context) (*binance.Account, error)
 type mockListOpenOrdersService struct {
 	orders []*binance.Order
 	err    error
+	calls  int
 }
 
 func (m *mockListOpenOrdersService) Do(_ context.Context) ([]*binance.Order, error) {
+	m.calls++
+
 	return m.orders, m.err
 }
 
+// mockGetOrderService implements GetOrderService
+type mockGetOrderService struct {
+	order         *binance.Order
+	err           error
+	calls         int
+	symbol        string
+	orderID       int64
+	clientOrderID string
+}
+
+func (m *mockGetOrderService) Symbol(symbol string) GetOrderService {
+	m.symbol = symbol
+	return m
+}
+
+func (m *mockGetOrderService) OrderID(orderID int64) GetOrderService {
+	m.orderID = orderID
+	return m
+}
+
+func (m *mockGetOrderService) OrigClientOrderID(clientOrderID string) GetOrderService {
+	m.clientOrderID = clientOrderID
+	return m
+}
+
+func (m *mockGetOrderService) Do(_ context.Context) (*binance.Order, error) {
+	m.calls++
+
+	return m.order, m.err
+}
+
 // mockCancelOrderService implements CancelOrderService
 type mockCancelOrderService struct {
 	response *binance.CancelOrderResponse
@@ -181,6 +341,9 @@ type mockListTradesService struct {
 	limit     int
 	startTime int64
 	endTime   int64
+	fromID    int64
+	hasFromID bool
+	calls     int
 }
 
 func (m *mockListTradesService) Symbol(symbol string) ListTradesService {
@@ -203,8 +366,31 @@ func (m *mockListTradesService) EndTime(endTime int64) ListTradesService {
 	return m
 }
 
+func (m *mockListTradesService) FromID(fromID int64) ListTradesService {
+	m.fromID = fromID
+	m.hasFromID = true
+	return m
+}
+
+// Do returns the trades; with a fromId it pages like Binance, from that id up to the limit.
 func (m *mockListTradesService) Do(_ context.Context) ([]*binance.TradeV3, error) {
-	return m.trades, m.err
+	m.calls++
+	if !m.hasFromID || m.err != nil {
+		return m.trades, m.err
+	}
+
+	page := []*binance.TradeV3{}
+	for _, trade := range m.trades {
+		if trade.ID < m.fromID {
+			continue
+		}
+		if m.limit > 0 && len(page) == m.limit {
+			break
+		}
+		page = append(page, trade)
+	}
+
+	return page, nil
 }
 
 // mockTradeFeeService implements TradeFeeService
@@ -239,6 +425,61 @@ func (m *mockListPricesService) Do(_ context.Context) ([]*binance.SymbolPrice, e
 	return m.prices, m.err
 }
 
+// mockStartUserStreamService implements StartUserStreamService.
+// Each call returns the next listen key in keys.
+type mockStartUserStreamService struct {
+	keys  []string
+	err   error
+	calls int
+}
+
+func (m *mockStartUserStreamService) Do(_ context.Context) (string, error) {
+	m.calls++
+	if m.err != nil {
+		return "", m.err
+	}
+
+	return m.keys[min(m.calls, len(m.keys))-1], nil
+}
+
+// mockKeepaliveUserStreamService implements KeepaliveUserStreamService.
+type mockKeepaliveUserStreamService struct {
+	err        error
+	listenKeys []string
+}
+
+func (m *mockKeepaliveUserStreamService) ListenKey(listenKey string) KeepaliveUserStreamService {
+	m.listenKeys = append(m.listenKeys, listenKey)
+	return m
+}
+
+func (m *mockKeepaliveUserStreamService) Do(_ context.Context) error {
+	return m.err
+}
+
+// mockExchangeInfoService implements ExchangeInfoService.
+// Without symbols set, every symbol is unlisted and has no trading rules.
+type mockExchangeInfoService struct {
+	symbols []binance.Symbol
+	err     error
+	calls   int
+	symbol  string
+}
+
+func (m *mockExchangeInfoService) Symbol(symbol string) ExchangeInfoService {
+	m.symbol = symbol
+	return m
+}
+
+func (m *mockExchangeInfoService) Do(_ context.Context) (*binance.ExchangeInfo, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &binance.ExchangeInfo{Symbols: m.symbols}, nil
+}
+
 type BinanceTradingTestSuite struct {
 	suite.Suite
 }
@@ -428,6 +669,42 @@ func (suite *BinanceTradingTestSuite) TestPlaceOrder_LimitBuy_Success() {
 	suite.Equal(binance.TimeInForceTypeGTC, mockClient.createOrderService.tif)
 }
 
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_LimitTimeInForce() {
+	tests := []struct {
+		name        string
+		timeInForce types.TimeInForce
+		expected    binance.TimeInForceType
+	}{
+		{name: "empty defaults to GTC", timeInForce: "", expected: binance.TimeInForceTypeGTC},
+		{name: "GTC", timeInForce: types.TimeInForceGTC, expected: binance.TimeInForceTypeGTC},
+		{name: "IOC", timeInForce: types.TimeInForceIOC, expected: binance.TimeInForceTypeIOC},
+		{name: "FOK", timeInForce: types.TimeInForceFOK, expected: binance.TimeInForceTypeFOK},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			mockClient := newMockBinanceClient()
+			mockClient.createOrderService.response = &binance.CreateOrderResponse{
+				OrderID: 12348,
+				Symbol:  "BTCUSDT",
+			}
+
+			provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+			err := provider.PlaceOrder(types.ExecuteOrder{
+				Symbol:      "BTCUSDT",
+				Side:        types.PurchaseTypeBuy,
+				OrderType:   types.OrderTypeLimit,
+				Quantity:    0.001,
+				Price:       50000.0,
+				TimeInForce: tt.timeInForce,
+			})
+			suite.NoError(err)
+			suite.Equal(tt.expected, mockClient.createOrderService.tif)
+		})
+	}
+}
+
 func (suite *BinanceTradingTestSuite) TestPlaceOrder_UnsupportedSide_Error() {
 	mockClient := newMockBinanceClient()
 	provider := newBinanceTradingSystemProviderWithClient(mockClient)
@@ -478,8 +755,336 @@ func (suite *BinanceTradingTestSuite) TestPlaceOrder_APIError() {
 	suite.Contains(err.Error(), "failed to place order")
 }
 
+// recordSleeps replaces the provider's backoff sleep with one that records the
+// requested delays without waiting.
+func recordSleeps(provider *BinanceTradingSystemProvider) *[]time.Duration {
+	delays := []time.Duration{}
+	provider.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	return &delays
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_RetriesTransientErrors() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.errs = []error{
+		&common.APIError{Code: -1008, Message: "Server is currently overloaded with other requests."},
+		&url.Error{Op: "Post", URL: "https://api.binance.com/api/v3/order", Err: io.ErrUnexpectedEOF},
+	}
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 1, Symbol: "BTCUSDT"}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	delays := recordSleeps(provider)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.001,
+	})
+	suite.NoError(err)
+	suite.Equal(3, mockClient.createOrderService.calls)
+	suite.Equal([]time.Duration{DefaultRetryBaseDelay, 2 * DefaultRetryBaseDelay}, *delays)
+	suite.NotEmpty(mockClient.createOrderService.clientOrderID)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_DoesNotRetryRequestErrors() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.err = &common.APIError{Code: -2010, Message: "Account has insufficient balance for requested action."}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	delays := recordSleeps(provider)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.001,
+	})
+	suite.Error(err)
+	suite.Contains(err.Error(), "insufficient balance")
+	suite.Equal(1, mockClient.createOrderService.calls)
+	suite.Empty(*delays)
+}
+
+// btcusdtExchangeInfo is the exchange info of BTCUSDT with its spot trading rules.
+func btcusdtExchangeInfo() []binance.Symbol {
+	return []binance.Symbol{{
+		Symbol: "BTCUSDT",
+		Filters: []map[string]any{
+			{"filterType": "PRICE_FILTER", "minPrice": "0.01000000", "maxPrice": "1000000.00000000", "tickSize": "0.01000000"},
+			{"filterType": "LOT_SIZE", "minQty": "0.00001000", "maxQty": "9000.00000000", "stepSize": "0.00001000"},
+			{"filterType": "NOTIONAL", "minNotional": "5.00000000", "applyMinToMarket": true, "maxNotional": "9000000.00000000", "applyMaxToMarket": false, "avgPriceMins": float64(5)},
+		},
+	}}
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_BelowMinNotionalRejectedLocally() {
+	mockClient := newMockBinanceClient()
+	mockClient.exchangeInfoService.symbols = btcusdtExchangeInfo()
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeLimit,
+		Quantity:  0.00005,
+		Price:     60000,
+	})
+	suite.Require().Error(err)
+	suite.True(argoErrors.HasCode(err, argoErrors.ErrCodeOrderRejected))
+	suite.Contains(err.Error(), "below the minimum notional 5")
+	suite.Equal(0, mockClient.createOrderService.calls, "the order should not reach Binance")
+	suite.Equal("BTCUSDT", mockClient.exchangeInfoService.symbol)
+
+	// A market order is checked against its price as well
+	err = provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeSell,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.00005,
+		Price:     60000,
+	})
+	suite.True(argoErrors.HasCode(err, argoErrors.ErrCodeOrderRejected))
+	suite.Equal(0, mockClient.createOrderService.calls)
+
+	// Below the lot size once rounded down to the step
+	err = provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.000009,
+	})
+	suite.True(argoErrors.HasCode(err, argoErrors.ErrCodeOrderRejected))
+	suite.Equal(1, mockClient.exchangeInfoService.calls, "the trading rules should be cached")
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_SnapsToTickAndStep() {
+	mockClient := newMockBinanceClient()
+	mockClient.exchangeInfoService.symbols = btcusdtExchangeInfo()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 1, Symbol: "BTCUSDT"}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeLimit,
+		Quantity:  0.0012345678,
+		Price:     60123.456789,
+	})
+	suite.Require().NoError(err)
+	suite.Equal("60123.46", mockClient.createOrderService.price)
+	suite.Equal("0.00123", mockClient.createOrderService.quantity)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_UncheckedWithoutExchangeInfo() {
+	mockClient := newMockBinanceClient()
+	mockClient.exchangeInfoService.err = errors.New("exchange info unavailable")
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 1, Symbol: "BTCUSDT"}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	order := types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeLimit,
+		Quantity:  0.001,
+		Price:     60123.456789,
+	}
+
+	suite.Require().NoError(provider.PlaceOrder(order))
+	suite.Equal("60123.456789", mockClient.createOrderService.price)
+
+	// The exchange info is fetched again for the next order
+	mockClient.exchangeInfoService.err = nil
+	mockClient.exchangeInfoService.symbols = btcusdtExchangeInfo()
+
+	suite.Require().NoError(provider.PlaceOrder(order))
+	suite.Equal("60123.46", mockClient.createOrderService.price)
+	suite.Equal(2, mockClient.exchangeInfoService.calls)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_RejectionCarriesReason() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.err = &common.APIError{Code: -1013, Message: "Filter failure: NOTIONAL"}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.001,
+	})
+	suite.Require().Error(err)
+
+	var rejection *argoErrors.Error
+	suite.Require().True(argoErrors.As(err, &rejection))
+	suite.Equal(argoErrors.ErrCodeOrderRejected, rejection.Code)
+	suite.Equal("Filter failure: NOTIONAL", rejection.Message)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_StopsAfterMaxAttempts() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.err = &common.APIError{Code: -1003, Message: "Too many requests"}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	provider.retryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	delays := recordSleeps(provider)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.001,
+	})
+	suite.Error(err)
+	suite.Equal(4, mockClient.createOrderService.calls)
+	suite.Equal([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, *delays)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_DuplicateOnRetryMeansAccepted() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.errs = []error{
+		&common.APIError{Code: -1007, Message: "Timeout waiting for response from backend server."},
+	}
+	mockClient.createOrderService.err = &common.APIError{Code: -2010, Message: "Duplicate order sent."}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	recordSleeps(provider)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.001,
+	})
+	suite.NoError(err)
+	suite.Equal(2, mockClient.createOrderService.calls)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_CancelledContextAbortsRetries() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.err = &common.APIError{Code: -1001, Message: "Internal error; unable to process your request. Please try again."}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleepContext(ctx, d)
+	}
+
+	err := provider.placeOrder(ctx, types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.001,
+	})
+	suite.Error(err)
+	suite.ErrorIs(err, context.Canceled)
+	suite.Equal(1, mockClient.createOrderService.calls)
+}
+
+func (suite *BinanceTradingTestSuite) TestIsRetryableBinanceError() {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"server busy", &common.APIError{Code: -1008, Message: "busy"}, true},
+		{"rate limited", &common.APIError{Code: -1003, Message: "Too much request weight used"}, true},
+		{"gateway error without body", &common.APIError{Response: []byte("<html>502 Bad Gateway</html>")}, true},
+		{"network error", &url.Error{Op: "Post", URL: "https://api.binance.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"insufficient balance", &common.APIError{Code: -2010, Message: "Account has insufficient balance for requested action."}, false},
+		{"invalid symbol", &common.APIError{Code: -1121, Message: "Invalid symbol."}, false},
+		{"context cancelled", context.Canceled, false},
+		{"unknown error", errors.New("boom"), false},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.expected, isRetryableBinanceError(tc.err))
+		})
+	}
+}
+
 // PlaceMultipleOrders Tests
 
+func (suite *BinanceTradingTestSuite) TestPlaceMultipleOrders_RateLimited() {
+	clock := newFakeClock()
+	start := clock.Now()
+
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 1, Symbol: "BTCUSDT"}
+
+	var callOffsets []time.Duration
+	mockClient.createOrderService.onDo = func() {
+		callOffsets = append(callOffsets, clock.Now().Sub(start))
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	provider.rateLimiter = newTokenBucket(5, clock.Now, clock.Sleep)
+	// The symbol's trading rules were fetched before, so only orders use the budget
+	provider.symbolFilters["BTCUSDT"] = binanceSymbolFilters{}
+
+	orders := make([]types.ExecuteOrder, 10)
+	for i := range orders {
+		orders[i] = types.ExecuteOrder{
+			Symbol:    "BTCUSDT",
+			Side:      types.PurchaseTypeBuy,
+			OrderType: types.OrderTypeMarket,
+			Quantity:  0.001,
+		}
+	}
+
+	err := provider.PlaceMultipleOrders(orders)
+	suite.NoError(err)
+
+	// The first five orders use the initial burst, then one order every 12 seconds
+	suite.Equal([]time.Duration{
+		0, 0, 0, 0, 0,
+		12 * time.Second, 24 * time.Second, 36 * time.Second, 48 * time.Second, 60 * time.Second,
+	}, callOffsets)
+
+	// No rolling minute after the burst holds more than the budget
+	for i := 5; i < len(callOffsets); i++ {
+		inWindow := 0
+		for _, offset := range callOffsets[5:] {
+			if offset > callOffsets[i]-time.Minute && offset <= callOffsets[i] {
+				inWindow++
+			}
+		}
+		suite.LessOrEqual(inWindow, 5)
+	}
+}
+
+func (suite *BinanceTradingTestSuite) TestGetOpenOrders_WaitsForRequestWeight() {
+	clock := newFakeClock()
+	mockClient := newMockBinanceClient()
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	provider.rateLimiter = newTokenBucket(120, clock.Now, clock.Sleep)
+
+	_, err := provider.GetOpenOrders()
+	suite.NoError(err)
+	suite.Empty(clock.sleeps)
+
+	// The second call needs 80 weight but only 40 remains (half a second per token)
+	_, err = provider.GetOpenOrders()
+	suite.NoError(err)
+	suite.Equal([]time.Duration{20 * time.Second}, clock.sleeps)
+
+	_, err = provider.GetTrades(types.TradeFilter{Symbol: "BTCUSDT"})
+	suite.NoError(err)
+	suite.Equal([]time.Duration{20 * time.Second, 10 * time.Second}, clock.sleeps)
+}
+
 func (suite *BinanceTradingTestSuite) TestPlaceMultipleOrders_Success() {
 	mockClient := newMockBinanceClient()
 	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 12345}
@@ -510,6 +1115,152 @@ func (suite *BinanceTradingTestSuite) TestPlaceMultipleOrders_FailsOnFirstError(
 	suite.Error(err)
 }
 
+// PlaceOCOOrder Tests
+
+// ocoTestOrders returns a market entry with a take profit at 55000 and a stop loss triggering at 48000.
+func ocoTestOrders(stopType types.OrderType) (types.ExecuteOrder, types.ExecuteOrder, types.ExecuteOrder) {
+	entry := types.ExecuteOrder{Symbol: "BTCUSDT", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 0.01}
+	takeProfit := types.ExecuteOrder{Symbol: "BTCUSDT", Side: types.PurchaseTypeSell, OrderType: types.OrderTypeLimit, Price: 55000, Quantity: 0.01}
+	stopLoss := types.ExecuteOrder{Symbol: "BTCUSDT", Side: types.PurchaseTypeSell, OrderType: stopType, Price: 47900, StopPrice: 48000, Quantity: 0.01}
+
+	return entry, takeProfit, stopLoss
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOCOOrder_UsesNativeOrderList() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 1}
+	mockClient.createOCOService.response = &binance.CreateOCOResponse{OrderListID: 7}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	groupID, err := provider.PlaceOCOOrder(ocoTestOrders(types.OrderTypeStopMarket))
+	suite.NoError(err)
+
+	// The entry is placed first as a regular order
+	suite.Equal(1, mockClient.createOrderService.calls)
+	suite.Equal(binance.SideTypeBuy, mockClient.createOrderService.side)
+
+	oco := mockClient.createOCOService
+	suite.Equal(1, oco.calls)
+	suite.Equal("BTCUSDT", oco.symbol)
+	suite.Equal(binance.SideTypeSell, oco.side)
+	suite.Equal("0.01000000", oco.quantity)
+	suite.Equal("55000", oco.price)
+	suite.Equal("48000", oco.stopPrice)
+	suite.Empty(oco.stopLimitPrice, "a stop-market leg has no stop limit price")
+	suite.Equal(oco.listClientOrderID, groupID)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOCOOrder_LegsUseTheirOrderIDs() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 1}
+	mockClient.createOCOService.response = &binance.CreateOCOResponse{OrderListID: 7}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	entry, takeProfit, stopLoss := ocoTestOrders(types.OrderTypeStopMarket)
+	entry.ID = "entry-1"
+	takeProfit.ID = "take-profit-1"
+	stopLoss.ID = "stop-loss-1"
+
+	_, err := provider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+	suite.Require().NoError(err)
+	suite.Equal("entry-1", mockClient.createOrderService.clientOrderID)
+	suite.Equal("take-profit-1", mockClient.createOCOService.limitClientOrderID)
+	suite.Equal("stop-loss-1", mockClient.createOCOService.stopClientOrderID)
+
+	// The stop loss leg is queried by its ID
+	mockClient.getOrderService.order = &binance.Order{Symbol: "BTCUSDT", Status: binance.OrderStatusTypeNew}
+
+	status, err := provider.GetOrderStatus("stop-loss-1")
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusPending, status)
+	suite.Equal("stop-loss-1", mockClient.getOrderService.clientOrderID)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOCOOrder_StopLimitLeg() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOCOService.response = &binance.CreateOCOResponse{OrderListID: 7}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	_, err := provider.PlaceOCOOrder(ocoTestOrders(types.OrderTypeStopLimit))
+	suite.NoError(err)
+	suite.Equal("47900", mockClient.createOCOService.stopLimitPrice)
+	suite.Equal(binance.TimeInForceTypeGTC, mockClient.createOCOService.stopLimitTIF)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOCOOrder_InvalidLegs() {
+	mockClient := newMockBinanceClient()
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	entry, takeProfit, stopLoss := ocoTestOrders(types.OrderTypeStopMarket)
+	stopLoss.OrderType = types.OrderTypeLimit
+
+	_, err := provider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+	suite.Error(err)
+	suite.Contains(err.Error(), "stop loss must be a stop order")
+	suite.Zero(mockClient.createOrderService.calls, "nothing is placed when the legs are invalid")
+	suite.Zero(mockClient.createOCOService.calls)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOCOOrder_EntryFailure() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.err = errors.New("insufficient balance")
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	_, err := provider.PlaceOCOOrder(ocoTestOrders(types.OrderTypeStopMarket))
+	suite.Error(err)
+	suite.Zero(mockClient.createOCOService.calls, "the legs are not placed when the entry fails")
+}
+
+// bracketTestEntry returns a market buy opening a long position at 50000.
+func bracketTestEntry() types.ExecuteOrder {
+	return types.ExecuteOrder{
+		Symbol:       "BTCUSDT",
+		Side:         types.PurchaseTypeBuy,
+		OrderType:    types.OrderTypeMarket,
+		Price:        50000,
+		Quantity:     0.01,
+		PositionType: types.PositionTypeLong,
+	}
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceBracketOrder_UsesNativeOrderList() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 1}
+	mockClient.createOCOService.response = &binance.CreateOCOResponse{OrderListID: 7}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	entry := bracketTestEntry()
+
+	err := provider.PlaceBracketOrder(entry, 48000, 55000)
+	suite.NoError(err)
+	suite.Equal(1, mockClient.createOrderService.calls)
+
+	oco := mockClient.createOCOService
+	suite.Equal(1, oco.calls)
+	suite.Equal(binance.SideTypeSell, oco.side)
+	suite.Equal("0.01000000", oco.quantity)
+	suite.Equal("55000", oco.price)
+	suite.Equal("48000", oco.stopPrice)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceBracketOrder_StopAboveEntry() {
+	mockClient := newMockBinanceClient()
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	entry := bracketTestEntry()
+
+	err := provider.PlaceBracketOrder(entry, entry.Price+1000, 55000)
+	suite.Error(err)
+	suite.Contains(err.Error(), "must be below the entry price")
+	suite.Zero(mockClient.createOrderService.calls, "nothing is placed when the stop is on the wrong side")
+	suite.Zero(mockClient.createOCOService.calls)
+}
+
 // GetPositions Tests
 
 func (suite *BinanceTradingTestSuite) TestGetPositions_Success() {
@@ -582,20 +1333,46 @@ func (suite *BinanceTradingTestSuite) TestGetPosition_Found() {
 	suite.Equal(2.0, position.TotalLongPositionQuantity)
 }
 
-func (suite *BinanceTradingTestSuite) TestGetPosition_NotFound() {
+func (suite *BinanceTradingTestSuite) TestGetPosition_NotFound() {
+	mockClient := newMockBinanceClient()
+	mockClient.getAccountService.account = &binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "BTC", Free: "1.5", Locked: "0.5"},
+		},
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	position, err := provider.GetPosition("ETH")
+	suite.NoError(err)
+	suite.Equal("ETH", position.Symbol)
+	suite.Equal(0.0, position.TotalLongPositionQuantity)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetPosition_TradingPairUsesBaseAssetBalance() {
 	mockClient := newMockBinanceClient()
 	mockClient.getAccountService.account = &binance.Account{
 		Balances: []binance.Balance{
 			{Asset: "BTC", Free: "1.5", Locked: "0.5"},
+			{Asset: "USDT", Free: "1000", Locked: "0"},
+			{Asset: "BNB", Free: "0.2", Locked: "0"},
 		},
 	}
+	symbols := btcusdtExchangeInfo()
+	symbols[0].BaseAsset = "BTC"
+	symbols[0].QuoteAsset = "USDT"
+	mockClient.exchangeInfoService.symbols = symbols
 
 	provider := newBinanceTradingSystemProviderWithClient(mockClient)
 
-	position, err := provider.GetPosition("ETH")
+	position, err := provider.GetPosition("BTCUSDT")
 	suite.NoError(err)
-	suite.Equal("ETH", position.Symbol)
-	suite.Equal(0.0, position.TotalLongPositionQuantity)
+	suite.Equal("BTCUSDT", position.Symbol)
+	suite.Equal(2.0, position.TotalLongPositionQuantity)
+
+	quantity, err := provider.GetMaxSellQuantity("BTCUSDT")
+	suite.NoError(err)
+	suite.Equal(2.0, quantity)
 }
 
 func (suite *BinanceTradingTestSuite) TestGetPosition_APIError() {
@@ -724,6 +1501,29 @@ func (suite *BinanceTradingTestSuite) TestCancelAllOrders_APIError_OnCancel() {
 	suite.Contains(err.Error(), "failed to cancel orders")
 }
 
+// CancelOrdersForSymbol Tests
+
+func (suite *BinanceTradingTestSuite) TestCancelOrdersForSymbol_Success() {
+	mockClient := newMockBinanceClient()
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	err := provider.CancelOrdersForSymbol("ETHUSDT")
+	suite.NoError(err)
+	suite.Equal("ETHUSDT", mockClient.cancelOpenOrdersService.symbol)
+}
+
+func (suite *BinanceTradingTestSuite) TestCancelOrdersForSymbol_APIError() {
+	mockClient := newMockBinanceClient()
+	mockClient.cancelOpenOrdersService.err = errors.New("cancel failed")
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	err := provider.CancelOrdersForSymbol("BTCUSDT")
+	suite.Error(err)
+	suite.Contains(err.Error(), "failed to cancel orders")
+}
+
 // GetOrderStatus Tests
 
 func (suite *BinanceTradingTestSuite) TestGetOrderStatus_Found_New() {
@@ -774,6 +1574,136 @@ func (suite *BinanceTradingTestSuite) TestGetOrderStatus_APIError() {
 	suite.Equal(types.OrderStatusFailed, status)
 }
 
+func (suite *BinanceTradingTestSuite) TestGetOrderStatus_ClosedOrderPlacedByProvider() {
+	tests := []struct {
+		name     string
+		status   binance.OrderStatusType
+		expected types.OrderStatus
+	}{
+		{name: "filled", status: binance.OrderStatusTypeFilled, expected: types.OrderStatusFilled},
+		{name: "cancelled", status: binance.OrderStatusTypeCanceled, expected: types.OrderStatusCancelled},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			mockClient := newMockBinanceClient()
+			mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 12345, Symbol: "BTCUSDT"}
+			// The order is no longer open
+			mockClient.listOpenOrdersService.orders = []*binance.Order{}
+			mockClient.getOrderService.order = &binance.Order{OrderID: 12345, Symbol: "BTCUSDT", Status: tc.status}
+
+			provider := newBinanceTradingSystemProviderWithClient(mockClient)
+			suite.Require().NoError(provider.PlaceOrder(types.ExecuteOrder{
+				Symbol:    "BTCUSDT",
+				Side:      types.PurchaseTypeBuy,
+				OrderType: types.OrderTypeMarket,
+				Quantity:  0.01,
+			}))
+
+			status, err := provider.GetOrderStatus("12345")
+			suite.NoError(err)
+			suite.Equal(tc.expected, status)
+			suite.Equal(1, mockClient.getOrderService.calls)
+			suite.Equal("BTCUSDT", mockClient.getOrderService.symbol)
+			suite.Equal(int64(12345), mockClient.getOrderService.orderID)
+		})
+	}
+}
+
+func (suite *BinanceTradingTestSuite) TestGetOrderStatus_ClosedOrderSeenOpenBefore() {
+	mockClient := newMockBinanceClient()
+	mockClient.listOpenOrdersService.orders = []*binance.Order{
+		{OrderID: 12345, Symbol: "ETHUSDT", Status: binance.OrderStatusTypeNew},
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	status, err := provider.GetOrderStatus("12345")
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusPending, status)
+
+	// The order fills and leaves the open orders
+	mockClient.listOpenOrdersService.orders = []*binance.Order{}
+	mockClient.getOrderService.order = &binance.Order{OrderID: 12345, Symbol: "ETHUSDT", Status: binance.OrderStatusTypeFilled}
+
+	status, err = provider.GetOrderStatus("12345")
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusFilled, status)
+	suite.Equal("ETHUSDT", mockClient.getOrderService.symbol)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetOrderStatus_GetOrderAPIError() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 12345, Symbol: "BTCUSDT"}
+	mockClient.getOrderService.err = errors.New("API error")
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	suite.Require().NoError(provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.01,
+	}))
+
+	status, err := provider.GetOrderStatus("12345")
+	suite.Error(err)
+	suite.Equal(types.OrderStatusFailed, status)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetOrderStatus_ByPlacedOrderID() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 12345, Symbol: "BTCUSDT"}
+	mockClient.getOrderService.order = &binance.Order{OrderID: 12345, Symbol: "BTCUSDT", Status: binance.OrderStatusTypeFilled}
+	mockClient.listTradesService.trades = []*binance.TradeV3{
+		{ID: 1, OrderID: 12345, Price: "50000", Quantity: "0.006", Commission: "0.01", IsBuyer: true, Time: 1700000000000},
+		{ID: 2, OrderID: 12345, Price: "50010", Quantity: "0.004", Commission: "0.01", IsBuyer: true, Time: 1700000000500},
+		{ID: 3, OrderID: 99999, Price: "50020", Quantity: "0.1", Commission: "0.02", IsBuyer: false, Time: 1700000001000},
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	orderID := "8d5e0c3a-2f4b-4a7e-9c1d-6b2a0f3e4d51"
+	suite.Require().NoError(provider.PlaceOrder(types.ExecuteOrder{
+		ID:        orderID,
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.01,
+	}))
+	suite.Equal(orderID, mockClient.createOrderService.clientOrderID)
+
+	// The order is queried by the ID it was placed with, without listing the open orders
+	status, err := provider.GetOrderStatus(orderID)
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusFilled, status)
+	suite.Equal("BTCUSDT", mockClient.getOrderService.symbol)
+	suite.Equal(orderID, mockClient.getOrderService.clientOrderID)
+	suite.Zero(mockClient.listOpenOrdersService.calls)
+
+	// Its trades carry the same ID
+	trades, err := provider.GetTrades(types.TradeFilter{Symbol: "BTCUSDT"})
+	suite.Require().NoError(err)
+	suite.Require().Len(trades, 3)
+	suite.Equal(orderID, trades[0].Order.OrderID)
+	suite.Equal(orderID, trades[1].Order.OrderID)
+	suite.Equal("99999", trades[2].Order.OrderID)
+}
+
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_GeneratesClientOrderIDBinanceAccepts() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 12345, Symbol: "BTCUSDT"}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	suite.Require().NoError(provider.PlaceOrder(types.ExecuteOrder{
+		ID:        "an order ID that is far too long for a Binance client order ID",
+		Symbol:    "BTCUSDT",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.01,
+	}))
+	suite.Regexp(binanceClientOrderIDPattern, mockClient.createOrderService.clientOrderID)
+}
+
 // GetAccountInfo Tests
 
 func (suite *BinanceTradingTestSuite) TestGetAccountInfo_Success() {
@@ -795,6 +1725,80 @@ func (suite *BinanceTradingTestSuite) TestGetAccountInfo_Success() {
 	suite.Equal(1700.0, accountInfo.Equity)
 }
 
+func (suite *BinanceTradingTestSuite) TestGetAccountInfo_AssetBalances() {
+	mockClient := newMockBinanceClient()
+	mockClient.getAccountService.account = &binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "BTC", Free: "0.5", Locked: "0.25"},
+			{Asset: "ETH", Free: "2", Locked: "0"},
+			{Asset: "USDT", Free: "1000", Locked: "500"},
+			{Asset: "USDC", Free: "300", Locked: "100"},
+			{Asset: "BNB", Free: "0", Locked: "0"}, // Empty assets are omitted
+		},
+	}
+
+	expectedBalances := map[string]types.AssetBalance{
+		"BTC":  {Free: 0.5, Locked: 0.25},
+		"ETH":  {Free: 2, Locked: 0},
+		"USDT": {Free: 1000, Locked: 500},
+		"USDC": {Free: 300, Locked: 100},
+	}
+
+	suite.Run("default stablecoins exclude USDC", func() {
+		provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+		accountInfo, err := provider.GetAccountInfo()
+		suite.Require().NoError(err)
+		suite.Equal(expectedBalances, accountInfo.Balances)
+		suite.Equal(1500.0, accountInfo.Balance)
+		suite.Equal(1000.0, accountInfo.BuyingPower)
+	})
+
+	suite.Run("configured stablecoins are aggregated", func() {
+		provider := newBinanceTradingSystemProviderWithClient(mockClient)
+		provider.stablecoinAssets = assetSet([]string{"USDT", "USDC"})
+
+		accountInfo, err := provider.GetAccountInfo()
+		suite.Require().NoError(err)
+		suite.Equal(expectedBalances, accountInfo.Balances)
+		suite.Equal(1900.0, accountInfo.Balance)     // 1000 + 500 + 300 + 100
+		suite.Equal(1300.0, accountInfo.BuyingPower) // 1000 + 300 (free only)
+		suite.Equal(1900.0, accountInfo.Equity)
+	})
+}
+
+func (suite *BinanceTradingTestSuite) TestGetAccountInfo_QuoteAssets() {
+	mockClient := newMockBinanceClient()
+	mockClient.getAccountService.account = &binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "USDT", Free: "1000", Locked: "500"},
+			{Asset: "USDC", Free: "300", Locked: "100"},
+			{Asset: "BTC", Free: "1.0", Locked: "0"},
+		},
+	}
+	mockClient.tradeFeeService.fees = []*binance.TradeFeeDetails{
+		{Symbol: "BTCUSDC", TakerCommission: "0.001"},
+	}
+
+	provider, err := NewBinanceTradingSystemProvider(BinanceProviderConfig{
+		ApiKey:      "key",
+		SecretKey:   "secret",
+		QuoteAssets: []string{"USDC"},
+	}, false)
+	suite.Require().NoError(err)
+	provider.client = mockClient
+
+	accountInfo, err := provider.GetAccountInfo()
+	suite.Require().NoError(err)
+	suite.Equal(300.0, accountInfo.BuyingPower, "only the free USDC counts")
+	suite.Equal(1500.0, accountInfo.Balance, "the balance still sums the stablecoins")
+
+	maxQty, err := provider.GetMaxBuyQuantity("BTCUSDC", 100.0)
+	suite.Require().NoError(err)
+	// 300 / 1.001 / 100
+	suite.InDelta(2.997, maxQty, 0.0001)
+}
+
 func (suite *BinanceTradingTestSuite) TestGetAccountInfo_EmptyBalances() {
 	mockClient := newMockBinanceClient()
 	mockClient.getAccountService.account = &binance.Account{
@@ -819,13 +1823,34 @@ func (suite *BinanceTradingTestSuite) TestGetAccountInfo_APIError() {
 	suite.Error(err)
 }
 
+func (suite *BinanceTradingTestSuite) TestCalculatePositionSize_FloorsToPrecision() {
+	mockClient := newMockBinanceClient()
+	mockClient.getAccountService.account = &binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "USDT", Free: "1000", Locked: "0"},
+		},
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	provider.symbolDecimalPrecision = map[string]int{"DOGEUSDT": 0}
+
+	// Risking 1% of 1000 on a 3 stop distance sizes 3.3333... units
+	quantity, err := provider.CalculatePositionSize("BTCUSDT", 100, 97, 0.01)
+	suite.Require().NoError(err)
+	suite.Equal(3.33333333, quantity)
+
+	quantity, err = provider.CalculatePositionSize("DOGEUSDT", 100, 97, 0.01)
+	suite.Require().NoError(err)
+	suite.Equal(3.0, quantity)
+}
+
 // GetOpenOrders Tests
 
 func (suite *BinanceTradingTestSuite) TestGetOpenOrders_Success() {
 	mockClient := newMockBinanceClient()
 	mockClient.listOpenOrdersService.orders = []*binance.Order{
 		{OrderID: 12345, Symbol: "BTCUSDT", Side: binance.SideTypeBuy, Type: binance.OrderTypeLimit, OrigQuantity: "0.001", Price: "50000"},
-		{OrderID: 12346, Symbol: "ETHUSDT", Side: binance.SideTypeSell, Type: binance.OrderTypeMarket, OrigQuantity: "0.01", Price: "0"},
+		{OrderID: 12346, Symbol: "ETHUSDT", Side: binance.SideTypeSell, Type: binance.OrderTypeLimit, OrigQuantity: "0.01", ExecutedQuantity: "0.004", Price: "3000"},
 	}
 
 	provider := newBinanceTradingSystemProviderWithClient(mockClient)
@@ -835,6 +1860,8 @@ func (suite *BinanceTradingTestSuite) TestGetOpenOrders_Success() {
 	suite.Len(orders, 2)
 	suite.Equal("12345", orders[0].ID)
 	suite.Equal("BTCUSDT", orders[0].Symbol)
+	suite.Zero(orders[0].FilledQuantity)
+	suite.InDelta(0.004, orders[1].FilledQuantity, 1e-12)
 }
 
 func (suite *BinanceTradingTestSuite) TestGetOpenOrders_Empty() {
@@ -918,6 +1945,84 @@ func (suite *BinanceTradingTestSuite) TestGetTrades_APIError() {
 	suite.Error(err)
 }
 
+func (suite *BinanceTradingTestSuite) TestGetTradesPage_RequiresSymbol() {
+	mockClient := newMockBinanceClient()
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	_, err := provider.GetTradesPage(types.TradeFilter{Limit: 2})
+	suite.Error(err)
+	suite.Contains(err.Error(), "symbol is required")
+}
+
+func (suite *BinanceTradingTestSuite) TestGetTradesPage_PagesWithFromID() {
+	mockClient := newMockBinanceClient()
+	for id := int64(101); id <= 105; id++ {
+		mockClient.listTradesService.trades = append(mockClient.listTradesService.trades, &binance.TradeV3{
+			ID: id, OrderID: id * 10, Price: "50000", Quantity: "0.001", Commission: "0", Time: 1609459200000 + id, IsBuyer: true,
+		})
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	var orderIDs []string
+	fromIDs := []int64{}
+	filter := types.TradeFilter{Symbol: "BTCUSDT", Limit: 2}
+	pageSizes := []int{}
+
+	for {
+		page, err := provider.GetTradesPage(filter)
+		suite.Require().NoError(err)
+		fromIDs = append(fromIDs, mockClient.listTradesService.fromID)
+		pageSizes = append(pageSizes, len(page.Trades))
+
+		for _, trade := range page.Trades {
+			orderIDs = append(orderIDs, trade.Order.OrderID)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		suite.Require().Less(len(pageSizes), 5, "pagination did not terminate")
+		filter.Cursor = page.NextCursor
+	}
+
+	suite.Equal([]int{2, 2, 1}, pageSizes)
+	suite.Equal([]int64{0, 103, 105}, fromIDs)
+	suite.Equal([]string{"1010", "1020", "1030", "1040", "1050"}, orderIDs)
+	suite.Equal(2, mockClient.listTradesService.limit)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetTradesPage_InvalidCursor() {
+	mockClient := newMockBinanceClient()
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	_, err := provider.GetTradesPage(types.TradeFilter{Symbol: "BTCUSDT", Cursor: "abc"})
+	suite.Error(err)
+	suite.Equal(0, mockClient.listTradesService.calls)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetTradesPage_StopsAtEndTime() {
+	mockClient := newMockBinanceClient()
+	for id := int64(1); id <= 4; id++ {
+		mockClient.listTradesService.trades = append(mockClient.listTradesService.trades, &binance.TradeV3{
+			ID: id, OrderID: id, Price: "50000", Quantity: "0.001", Commission: "0", Time: 1609459200000 + id*1000, IsBuyer: true,
+		})
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	page, err := provider.GetTradesPage(types.TradeFilter{
+		Symbol:  "BTCUSDT",
+		Limit:   3,
+		Cursor:  "0",
+		EndTime: time.UnixMilli(1609459202000),
+	})
+	suite.NoError(err)
+	suite.Len(page.Trades, 2)
+	suite.Empty(page.NextCursor)
+}
+
 // GetMaxBuyQuantity Tests
 
 func (suite *BinanceTradingTestSuite) TestGetMaxBuyQuantity_InvalidPrice() {
@@ -981,6 +2086,115 @@ func (suite *BinanceTradingTestSuite) TestGetMaxBuyQuantity_AccountAPIError() {
 	suite.Error(err)
 }
 
+// newBNBFeeProvider returns a provider paying fees in BNB at the default
+// discount, with a 1% taker and 0.5% maker rate and BNB priced at 500 USDT.
+func (suite *BinanceTradingTestSuite) newBNBFeeProvider(mockClient *mockBinanceClient) *BinanceTradingSystemProvider {
+	mockClient.tradeFeeService.fees = []*binance.TradeFeeDetails{
+		{Symbol: "BTCUSDT", MakerCommission: "0.005", TakerCommission: "0.01"},
+	}
+	mockClient.listPricesService.prices = []*binance.SymbolPrice{
+		{Symbol: "BNBUSDT", Price: "500"},
+	}
+
+	provider, err := NewBinanceTradingSystemProvider(BinanceProviderConfig{
+		ApiKey:       "key",
+		SecretKey:    "secret",
+		PayFeesInBNB: true,
+	}, false)
+	suite.Require().NoError(err)
+	provider.client = mockClient
+
+	return provider
+}
+
+func (suite *BinanceTradingTestSuite) TestGetMaxBuyQuantity_BNBFeeDiscount() {
+	mockClient := newMockBinanceClient()
+	mockClient.getAccountService.account = &binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "USDT", Free: "10000", Locked: "0"},
+			{Asset: "BNB", Free: "0.2", Locked: "0"},
+		},
+	}
+
+	provider := suite.newBNBFeeProvider(mockClient)
+
+	maxQty, err := provider.GetMaxBuyQuantity("BTCUSDT", 50000.0)
+	suite.NoError(err)
+	// The discounted fee of 10000 * 0.0075 = 75 USDT is 0.15 BNB, covered by the balance
+	// maxQty = 10000 / 1.0075 / 50000
+	suite.InDelta(0.19851117, maxQty, 1e-8)
+	suite.Equal([]string{"BNBUSDT"}, mockClient.listPricesService.symbols)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetMaxBuyQuantity_BNBExhausted() {
+	mockClient := newMockBinanceClient()
+	mockClient.getAccountService.account = &binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "USDT", Free: "10000", Locked: "0"},
+			{Asset: "BNB", Free: "0.1", Locked: "0.5"},
+		},
+	}
+
+	provider := suite.newBNBFeeProvider(mockClient)
+
+	maxQty, err := provider.GetMaxBuyQuantity("BTCUSDT", 50000.0)
+	suite.NoError(err)
+	// 0.1 free BNB does not cover the 0.15 BNB fee, so the standard rate applies
+	// maxQty = 10000 / 1.01 / 50000
+	suite.InDelta(0.19801980, maxQty, 1e-8)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetMaxBuyQuantity_BNBPriceUnavailable() {
+	mockClient := newMockBinanceClient()
+	mockClient.getAccountService.account = &binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "USDT", Free: "10000", Locked: "0"},
+			{Asset: "BNB", Free: "10", Locked: "0"},
+		},
+	}
+
+	provider := suite.newBNBFeeProvider(mockClient)
+	mockClient.listPricesService.err = errors.New("API error")
+
+	maxQty, err := provider.GetMaxBuyQuantity("BTCUSDT", 50000.0)
+	suite.NoError(err)
+	suite.InDelta(0.19801980, maxQty, 1e-8)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetTrades_BNBCommissionAtDiscountedRate() {
+	mockClient := newMockBinanceClient()
+	mockClient.listTradesService.trades = []*binance.TradeV3{
+		{ID: 1, OrderID: 1, Price: "50000", Quantity: "0.1", Commission: "0.075", CommissionAsset: "BNB", IsBuyer: true},
+		{ID: 2, OrderID: 2, Price: "50000", Quantity: "0.1", Commission: "0.025", CommissionAsset: "BNB", IsMaker: true},
+		{ID: 3, OrderID: 3, Price: "50000", Quantity: "0.1", Commission: "50", CommissionAsset: "USDT"},
+	}
+
+	provider := suite.newBNBFeeProvider(mockClient)
+
+	trades, err := provider.GetTrades(types.TradeFilter{Symbol: "BTCUSDT"})
+	suite.Require().NoError(err)
+	suite.Require().Len(trades, 3)
+	// 5000 USDT notional at the discounted taker and maker rates
+	suite.InDelta(37.5, trades[0].Fee, 1e-9)
+	suite.InDelta(37.5, trades[0].Order.Fee, 1e-9)
+	suite.InDelta(18.75, trades[1].Fee, 1e-9)
+	// Without enough BNB Binance charged the standard fee in USDT
+	suite.Equal(50.0, trades[2].Fee)
+}
+
+func (suite *BinanceTradingTestSuite) TestGetTrades_BNBCommissionWithoutFeeAsset() {
+	mockClient := newMockBinanceClient()
+	mockClient.listTradesService.trades = []*binance.TradeV3{
+		{ID: 1, OrderID: 1, Price: "50000", Quantity: "0.1", Commission: "0.075", CommissionAsset: "BNB"},
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+
+	trades, err := provider.GetTrades(types.TradeFilter{Symbol: "BTCUSDT"})
+	suite.Require().NoError(err)
+	suite.Equal(0.075, trades[0].Fee, "the reported commission is kept when BNB fee payment is off")
+}
+
 // GetMaxSellQuantity Tests
 
 func (suite *BinanceTradingTestSuite) TestGetMaxSellQuantity_Success() {
@@ -1042,6 +2256,23 @@ func (suite *BinanceTradingTestSuite) TestConvertBinanceOrderToExecuteOrder() {
 		suite.Equal(types.OrderTypeLimit, result.OrderType)
 		suite.Equal(0.001, result.Quantity)
 		suite.Equal(50000.0, result.Price)
+		suite.Equal(types.TimeInForceGTC, result.TimeInForce)
+	})
+
+	suite.Run("ImmediateOrCancelLimit", func() {
+		order := &binance.Order{
+			OrderID:      12348,
+			Symbol:       "BTCUSDT",
+			Side:         binance.SideTypeBuy,
+			Type:         binance.OrderTypeLimit,
+			TimeInForce:  binance.TimeInForceTypeIOC,
+			OrigQuantity: "0.001",
+			Price:        "50000",
+		}
+
+		result, err := convertBinanceOrderToExecuteOrder(order)
+		suite.NoError(err)
+		suite.Equal(types.TimeInForceIOC, result.TimeInForce)
 	})
 
 	suite.Run("SellMarket", func() {
@@ -1388,6 +2619,29 @@ func (suite *BinanceTradingTestSuite) TestPlaceOrder_CustomPrecision_Success() {
 	suite.Equal("0.01", mockClient.createOrderService.quantity)
 }
 
+func (suite *BinanceTradingTestSuite) TestPlaceOrder_SymbolPrecision() {
+	mockClient := newMockBinanceClient()
+	mockClient.createOrderService.response = &binance.CreateOrderResponse{OrderID: 12345}
+
+	provider := newBinanceTradingSystemProviderWithClient(mockClient)
+	provider.symbolDecimalPrecision = map[string]int{"DOGEUSDT": 0}
+
+	place := func(symbol string, quantity float64) string {
+		err := provider.PlaceOrder(types.ExecuteOrder{
+			Symbol:    symbol,
+			Side:      types.PurchaseTypeBuy,
+			OrderType: types.OrderTypeMarket,
+			Quantity:  quantity,
+		})
+		suite.Require().NoError(err)
+
+		return mockClient.createOrderService.quantity
+	}
+
+	suite.Equal("0.12345678", place("BTCUSDT", 0.123456789), "symbols without a precision use the default")
+	suite.Equal("150", place("DOGEUSDT", 150.9))
+}
+
 func (suite *BinanceTradingTestSuite) TestPlaceOrder_CustomPrecision_TooSmall_Error() {
 	// Test with precision=1, quantity=0.01 - should fail (0.01 rounds to 0.0 with precision 1)
 	mockClient := newMockBinanceClient()
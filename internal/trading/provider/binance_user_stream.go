@@ -0,0 +1,235 @@
+package tradingprovider
+
+import (
+	"context"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// binanceListenKeyKeepalive is how often the listen key of an open user data
+// stream is extended. Binance expires listen keys 60 minutes after the last keepalive.
+const binanceListenKeyKeepalive = 30 * time.Minute
+
+// BinanceUserDataWebSocket defines the WebSocket connection of a Binance user
+// data stream, which pushes account and order events.
+type BinanceUserDataWebSocket interface {
+	// WsUserDataServe connects to the user data stream of listenKey.
+	// Returns: doneC (closes when connection ends), stopC (close to stop), error
+	WsUserDataServe(listenKey string, handler binance.WsUserDataHandler, errHandler binance.ErrHandler) (doneC chan struct{}, stopC chan struct{}, err error)
+}
+
+// binanceUserDataWebSocket wraps the real binance user data WebSocket.
+type binanceUserDataWebSocket struct{}
+
+func (w *binanceUserDataWebSocket) WsUserDataServe(listenKey string, handler binance.WsUserDataHandler, errHandler binance.ErrHandler) (chan struct{}, chan struct{}, error) {
+	//nolint:staticcheck // listen key streams are still served on the spot API the provider uses
+	return binance.WsUserDataServe(listenKey, handler, errHandler)
+}
+
+// StreamOrderUpdates implements OrderUpdateStreamer. It opens a user data stream
+// and yields an update for every executionReport Binance pushes. The listen key
+// is kept alive every 30 minutes, and when the connection drops or cannot be
+// opened a new one is made with a fresh listen key after a backoff.
+func (b *BinanceTradingSystemProvider) StreamOrderUpdates(ctx context.Context) iter.Seq2[types.OrderUpdate, error] {
+	return func(yield func(types.OrderUpdate, error) bool) {
+		failures := 0
+
+		for {
+			connected, keepGoing := b.serveUserDataStream(ctx, yield)
+			if !keepGoing || ctx.Err() != nil {
+				return
+			}
+
+			if connected {
+				failures = 0
+			}
+
+			failures++
+
+			debugLog.Warn("StreamOrderUpdates: user data stream closed, reconnecting",
+				zap.Int("attempt", failures),
+			)
+
+			if err := b.sleep(ctx, b.retryPolicy.backoff(failures)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// serveUserDataStream yields the order updates of one user data stream
+// connection until it closes. It reports whether the connection was opened and
+// whether the consumer wants more updates.
+func (b *BinanceTradingSystemProvider) serveUserDataStream(ctx context.Context, yield func(types.OrderUpdate, error) bool) (connected bool, keepGoing bool) {
+	// noUpdate is yielded along with errors
+	var noUpdate types.OrderUpdate
+
+	listenKey, err := b.startUserDataStream(ctx)
+	if err != nil {
+		return false, yield(noUpdate, err)
+	}
+
+	events := make(chan *binance.WsUserDataEvent)
+	errs := make(chan error)
+
+	// closed unblocks the WebSocket handlers once this connection is abandoned
+	closed := make(chan struct{})
+	defer close(closed)
+
+	handler := func(event *binance.WsUserDataEvent) {
+		select {
+		case events <- event:
+		case <-closed:
+		}
+	}
+	errHandler := func(err error) {
+		select {
+		case errs <- err:
+		case <-closed:
+		}
+	}
+
+	doneC, stopC, err := b.userDataWs.WsUserDataServe(listenKey, handler, errHandler)
+	if err != nil {
+		return false, yield(noUpdate, errors.Wrap(errors.ErrCodeDataSourceUnavailable, "failed to connect to Binance user data stream", err))
+	}
+	defer close(stopC)
+
+	keepalive := time.NewTicker(binanceListenKeyKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, false
+		case <-doneC:
+			return true, true
+		case <-keepalive.C:
+			if err := b.keepAliveUserDataStream(ctx, listenKey); err != nil {
+				if !yield(noUpdate, err) {
+					return true, false
+				}
+			}
+		case err := <-errs:
+			if !yield(noUpdate, errors.Wrap(errors.ErrCodeDataSourceUnavailable, "Binance user data stream error", err)) {
+				return true, false
+			}
+		case event := <-events:
+			if event.Event != binance.UserDataEventTypeExecutionReport {
+				continue
+			}
+
+			if !yield(convertBinanceOrderUpdate(event.OrderUpdate), nil) {
+				return true, false
+			}
+		}
+	}
+}
+
+// startUserDataStream requests a listen key for a new user data stream.
+func (b *BinanceTradingSystemProvider) startUserDataStream(ctx context.Context) (string, error) {
+	var listenKey string
+
+	err := retryWithBackoff(ctx, b.retryPolicy, b.sleep, isRetryableBinanceError, func(int) error {
+		if err := b.rateLimiter.Wait(ctx, binanceWeightUserStream); err != nil {
+			return err
+		}
+
+		key, err := b.client.NewStartUserStreamService().Do(ctx)
+		listenKey = key
+
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrap(errors.ErrCodeDataSourceUnavailable, "failed to start Binance user data stream", err)
+	}
+
+	return listenKey, nil
+}
+
+// keepAliveUserDataStream extends the validity of listenKey by 60 minutes.
+func (b *BinanceTradingSystemProvider) keepAliveUserDataStream(ctx context.Context, listenKey string) error {
+	err := retryWithBackoff(ctx, b.retryPolicy, b.sleep, isRetryableBinanceError, func(int) error {
+		if err := b.rateLimiter.Wait(ctx, binanceWeightUserStream); err != nil {
+			return err
+		}
+
+		return b.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx)
+	})
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDataSourceUnavailable, "failed to keep Binance user data stream alive", err)
+	}
+
+	return nil
+}
+
+// convertBinanceOrderUpdate converts the order of an executionReport event to an OrderUpdate.
+// Unlike mapBinanceOrderStatus, partially filled orders keep their own status.
+func convertBinanceOrderUpdate(u binance.WsOrderUpdate) types.OrderUpdate {
+	quantity, _ := strconv.ParseFloat(u.Volume, 64)
+	price, _ := strconv.ParseFloat(u.Price, 64)
+	stopPrice, _ := strconv.ParseFloat(u.StopPrice, 64)
+	filledQuantity, _ := strconv.ParseFloat(u.FilledVolume, 64)
+	filledQuote, _ := strconv.ParseFloat(u.FilledQuoteVolume, 64)
+	lastFillQuantity, _ := strconv.ParseFloat(u.LatestVolume, 64)
+	lastFillPrice, _ := strconv.ParseFloat(u.LatestPrice, 64)
+	fee, _ := strconv.ParseFloat(u.FeeCost, 64)
+
+	side := types.PurchaseTypeBuy
+	if binance.SideType(u.Side) == binance.SideTypeSell {
+		side = types.PurchaseTypeSell
+	}
+
+	var orderType types.OrderType
+
+	switch binance.OrderType(u.Type) {
+	case binance.OrderTypeMarket:
+		orderType = types.OrderTypeMarket
+	case binance.OrderTypeStopLoss:
+		orderType = types.OrderTypeStopMarket
+	case binance.OrderTypeStopLossLimit:
+		orderType = types.OrderTypeStopLimit
+	default:
+		orderType = types.OrderTypeLimit // Limit, limit maker and take profit orders
+	}
+
+	status := mapBinanceOrderStatus(binance.OrderStatusType(u.Status))
+	if binance.OrderStatusType(u.Status) == binance.OrderStatusTypePartiallyFilled {
+		status = types.OrderStatusPartiallyFilled
+	}
+
+	// Cancellations carry the ID of the cancel request; the order's own ID is the original one
+	clientOrderID := u.ClientOrderId
+	if u.OrigCustomOrderId != "" {
+		clientOrderID = u.OrigCustomOrderId
+	}
+
+	averageFillPrice := 0.0
+	if filledQuantity > 0 {
+		averageFillPrice = filledQuote / filledQuantity
+	}
+
+	return types.OrderUpdate{
+		OrderID:          strconv.FormatInt(u.Id, 10),
+		ClientOrderID:    clientOrderID,
+		Symbol:           u.Symbol,
+		Side:             side,
+		OrderType:        orderType,
+		Status:           status,
+		Price:            price,
+		StopPrice:        stopPrice,
+		Quantity:         quantity,
+		FilledQuantity:   filledQuantity,
+		AverageFillPrice: averageFillPrice,
+		LastFillQuantity: lastFillQuantity,
+		LastFillPrice:    lastFillPrice,
+		Fee:              fee,
+		Time:             time.UnixMilli(u.TransactionTime),
+	}
+}
@@ -0,0 +1,226 @@
+package tradingprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// mockUserDataWebSocket implements BinanceUserDataWebSocket. Each connection
+// runs serve in its own goroutine; the connection drops when serve closes doneC.
+type mockUserDataWebSocket struct {
+	mu         sync.Mutex
+	listenKeys []string
+	err        error
+	serve      func(conn int, push func(message string), doneC chan struct{})
+}
+
+func (m *mockUserDataWebSocket) WsUserDataServe(listenKey string, handler binance.WsUserDataHandler, errHandler binance.ErrHandler) (chan struct{}, chan struct{}, error) {
+	m.mu.Lock()
+	m.listenKeys = append(m.listenKeys, listenKey)
+	conn := len(m.listenKeys)
+	m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+
+	// push decodes a raw message the way the binance library does
+	push := func(message string) {
+		event := new(binance.WsUserDataEvent)
+		if err := json.Unmarshal([]byte(message), event); err != nil {
+			errHandler(err)
+			return
+		}
+
+		if event.Event == binance.UserDataEventTypeExecutionReport {
+			if err := json.Unmarshal([]byte(message), &event.OrderUpdate); err != nil {
+				errHandler(err)
+				return
+			}
+		}
+
+		handler(event)
+	}
+
+	doneC := make(chan struct{})
+	stopC := make(chan struct{})
+
+	go m.serve(conn, push, doneC)
+
+	return doneC, stopC, nil
+}
+
+func (m *mockUserDataWebSocket) connections() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string(nil), m.listenKeys...)
+}
+
+const partiallyFilledExecutionReport = `{
+	"e": "executionReport", "E": 1700000000100, "s": "BTCUSDT", "c": "client-1",
+	"S": "BUY", "o": "LIMIT", "f": "GTC", "q": "2.00000000", "p": "50000.00000000",
+	"P": "0.00000000", "x": "TRADE", "X": "PARTIALLY_FILLED", "i": 4242,
+	"l": "0.50000000", "z": "0.50000000", "L": "50000.00000000", "n": "0.02500000",
+	"N": "USDT", "T": 1700000000000, "t": 77, "Z": "25000.00000000", "C": ""
+}`
+
+const filledExecutionReport = `{
+	"e": "executionReport", "E": 1700000060100, "s": "BTCUSDT", "c": "client-1",
+	"S": "BUY", "o": "LIMIT", "f": "GTC", "q": "2.00000000", "p": "50000.00000000",
+	"P": "0.00000000", "x": "TRADE", "X": "FILLED", "i": 4242,
+	"l": "1.50000000", "z": "2.00000000", "L": "49000.00000000", "n": "0.07350000",
+	"N": "USDT", "T": 1700000060000, "t": 78, "Z": "98500.00000000", "C": ""
+}`
+
+const canceledExecutionReport = `{
+	"e": "executionReport", "E": 1700000000100, "s": "ETHUSDT", "c": "cancel-request",
+	"S": "SELL", "o": "STOP_LOSS_LIMIT", "q": "1.00000000", "p": "3000.00000000",
+	"x": "CANCELED", "X": "CANCELED", "i": 99, "l": "0", "z": "0", "L": "0",
+	"n": "0", "T": 1700000000000, "Z": "0", "C": "client-2"
+}`
+
+func (suite *BinanceTradingTestSuite) TestStreamOrderUpdates_MapsExecutionReports() {
+	client := newMockBinanceClient()
+	client.startUserStreamService.keys = []string{"listen-key"}
+
+	ws := &mockUserDataWebSocket{
+		serve: func(_ int, push func(string), _ chan struct{}) {
+			push(`{"e": "outboundAccountPosition", "E": 1700000000000, "u": 1700000000000, "B": []}`)
+			push(partiallyFilledExecutionReport)
+			push(filledExecutionReport)
+			push(canceledExecutionReport)
+		},
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(client)
+	provider.userDataWs = ws
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var updates []types.OrderUpdate
+
+	for update, err := range provider.StreamOrderUpdates(ctx) {
+		suite.Require().NoError(err)
+
+		updates = append(updates, update)
+		if len(updates) == 3 {
+			break
+		}
+	}
+
+	suite.Require().Len(updates, 3)
+	suite.Equal([]string{"listen-key"}, ws.connections())
+
+	partial := updates[0]
+	suite.Equal("4242", partial.OrderID)
+	suite.Equal("client-1", partial.ClientOrderID)
+	suite.Equal("BTCUSDT", partial.Symbol)
+	suite.Equal(types.PurchaseTypeBuy, partial.Side)
+	suite.Equal(types.OrderTypeLimit, partial.OrderType)
+	suite.Equal(types.OrderStatusPartiallyFilled, partial.Status)
+	suite.Equal(50000.0, partial.Price)
+	suite.Equal(2.0, partial.Quantity)
+	suite.Equal(0.5, partial.FilledQuantity)
+	suite.Equal(0.5, partial.LastFillQuantity)
+	suite.Equal(50000.0, partial.LastFillPrice)
+	suite.Equal(0.025, partial.Fee)
+	suite.Equal(time.UnixMilli(1700000000000), partial.Time)
+
+	filled := updates[1]
+	suite.Equal(types.OrderStatusFilled, filled.Status)
+	suite.Equal(2.0, filled.FilledQuantity)
+	suite.InDelta(49250.0, filled.AverageFillPrice, 1e-9)
+	suite.Equal(1.5, filled.LastFillQuantity)
+	suite.Equal(49000.0, filled.LastFillPrice)
+
+	canceled := updates[2]
+	suite.Equal(types.OrderStatusCancelled, canceled.Status)
+	suite.Equal("client-2", canceled.ClientOrderID)
+	suite.Equal(types.PurchaseTypeSell, canceled.Side)
+	suite.Equal(types.OrderTypeStopLimit, canceled.OrderType)
+	suite.Zero(canceled.AverageFillPrice)
+}
+
+func (suite *BinanceTradingTestSuite) TestStreamOrderUpdates_ReconnectsWithNewListenKey() {
+	client := newMockBinanceClient()
+	client.startUserStreamService.keys = []string{"first-key", "second-key"}
+
+	ws := &mockUserDataWebSocket{
+		serve: func(conn int, push func(string), doneC chan struct{}) {
+			if conn == 1 {
+				close(doneC)
+				return
+			}
+
+			push(filledExecutionReport)
+		},
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(client)
+	provider.userDataWs = ws
+	sleeps := recordSleeps(provider)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for update, err := range provider.StreamOrderUpdates(ctx) {
+		suite.Require().NoError(err)
+		suite.Equal(types.OrderStatusFilled, update.Status)
+
+		break
+	}
+
+	suite.Equal([]string{"first-key", "second-key"}, ws.connections())
+	suite.Equal([]time.Duration{DefaultRetryBaseDelay}, *sleeps)
+}
+
+func (suite *BinanceTradingTestSuite) TestStreamOrderUpdates_YieldsConnectionErrors() {
+	client := newMockBinanceClient()
+	client.startUserStreamService.keys = []string{"listen-key"}
+
+	ws := &mockUserDataWebSocket{
+		err:   errors.New("dial tcp: connection refused"),
+		serve: nil,
+	}
+
+	provider := newBinanceTradingSystemProviderWithClient(client)
+	provider.userDataWs = ws
+	recordSleeps(provider)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errorCount := 0
+
+	for _, err := range provider.StreamOrderUpdates(ctx) {
+		suite.Require().Error(err)
+		suite.Contains(err.Error(), "failed to connect to Binance user data stream")
+
+		errorCount++
+		if errorCount == 2 {
+			break
+		}
+	}
+
+	suite.Equal(2, errorCount)
+	suite.Len(ws.connections(), 2)
+}
+
+func (suite *BinanceTradingTestSuite) TestKeepAliveUserDataStream() {
+	client := newMockBinanceClient()
+	provider := newBinanceTradingSystemProviderWithClient(client)
+
+	suite.Require().NoError(provider.keepAliveUserDataStream(context.Background(), "listen-key"))
+	suite.Equal([]string{"listen-key"}, client.keepaliveUserStream.listenKeys)
+
+	client.keepaliveUserStream.err = errors.New("listen key does not exist")
+	suite.Error(provider.keepAliveUserDataStream(context.Background(), "listen-key"))
+}
@@ -0,0 +1,105 @@
+package tradingprovider
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// ValidateBracketOrder checks that stopLoss and takeProfit protect the position
+// opened by entry: below and above the entry price for a long position, above
+// and below it for a short one.
+func ValidateBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	if entry.Price <= 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "bracket entry price must be greater than zero: %f", entry.Price)
+	}
+
+	if stopLoss <= 0 || takeProfit <= 0 {
+		return errors.New(errors.ErrCodeInvalidParameter, "bracket stop loss and take profit must be greater than zero")
+	}
+
+	switch entry.PositionType {
+	case types.PositionTypeLong:
+		if entry.Side != types.PurchaseTypeBuy {
+			return errors.New(errors.ErrCodeInvalidParameter, "bracket entry of a long position must be a buy order")
+		}
+
+		if stopLoss >= entry.Price {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "long bracket stop loss %f must be below the entry price %f", stopLoss, entry.Price)
+		}
+
+		if takeProfit <= entry.Price {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "long bracket take profit %f must be above the entry price %f", takeProfit, entry.Price)
+		}
+	case types.PositionTypeShort:
+		if entry.Side != types.PurchaseTypeSell {
+			return errors.New(errors.ErrCodeInvalidParameter, "bracket entry of a short position must be a sell order")
+		}
+
+		if stopLoss <= entry.Price {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "short bracket stop loss %f must be above the entry price %f", stopLoss, entry.Price)
+		}
+
+		if takeProfit >= entry.Price {
+			return errors.Newf(errors.ErrCodeInvalidParameter, "short bracket take profit %f must be below the entry price %f", takeProfit, entry.Price)
+		}
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unsupported bracket position type: %s", entry.PositionType)
+	}
+
+	return nil
+}
+
+// BracketLegs returns the take profit limit order and the stop-market stop loss
+// closing quantity of the position opened by entry.
+func BracketLegs(entry types.ExecuteOrder, stopLoss, takeProfit, quantity float64) (types.ExecuteOrder, types.ExecuteOrder) {
+	side := types.PurchaseTypeSell
+	if entry.Side == types.PurchaseTypeSell {
+		side = types.PurchaseTypeBuy
+	}
+
+	leg := func(orderType types.OrderType, price, stopPrice float64, reason string) types.ExecuteOrder {
+		return types.ExecuteOrder{
+			ID:             "",
+			Symbol:         entry.Symbol,
+			Side:           side,
+			OrderType:      orderType,
+			Reason:         types.Reason{Reason: reason, Message: "bracket order leg"},
+			Price:          price,
+			StrategyName:   entry.StrategyName,
+			Quantity:       quantity,
+			PositionType:   entry.PositionType,
+			StopPrice:      stopPrice,
+			TrailOffset:    0,
+			TrailPercent:   false,
+			FilledQuantity: 0,
+			TimeInForce:    types.TimeInForceGTC,
+			GoodTillDate:   time.Time{},
+			TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+			StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		}
+	}
+
+	return leg(types.OrderTypeLimit, takeProfit, 0, types.OrderReasonTakeProfit),
+		leg(types.OrderTypeStopMarket, stopLoss, stopLoss, types.OrderReasonStopLoss)
+}
+
+// PlaceBracketOrderAsOCO places a bracket order through the OCO primitive of
+// provider: the entry together with a take profit and a stop loss sized to the
+// entry quantity.
+func PlaceBracketOrderAsOCO(provider TradingSystemProvider, entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	if err := ValidateBracketOrder(entry, stopLoss, takeProfit); err != nil {
+		return err
+	}
+
+	takeProfitOrder, stopLossOrder := BracketLegs(entry, stopLoss, takeProfit, entry.Quantity)
+	takeProfitOrder.ID = uuid.New().String()
+	stopLossOrder.ID = uuid.New().String()
+
+	_, err := provider.PlaceOCOOrder(entry, takeProfitOrder, stopLossOrder)
+
+	return err
+}
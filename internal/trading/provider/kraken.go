@@ -0,0 +1,1252 @@
+package tradingprovider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/internal/utils"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// KrakenDecimalPrecision is the default volume precision used when rounding order quantities.
+	// Kraken publishes per-pair lot decimals; 8 is the finest precision any pair accepts.
+	KrakenDecimalPrecision = 8
+	// KrakenBaseURL is the Kraken spot REST API endpoint.
+	KrakenBaseURL = "https://api.kraken.com"
+	// krakenDefaultTakerFee is the taker fee of the lowest volume tier, used when the
+	// account fee cannot be fetched.
+	krakenDefaultTakerFee = 0.0026
+	// krakenTradesPageSize is the number of trades Kraken returns per TradesHistory page.
+	krakenTradesPageSize = 50
+)
+
+// krakenClientOrderIDPattern matches the client order IDs Kraken accepts: a
+// UUID, with or without dashes, or up to 18 characters of free text.
+var krakenClientOrderIDPattern = regexp.MustCompile(
+	`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{32}|[ -~]{1,18})$`)
+
+// Kraken order statuses as returned by the OpenOrders and QueryOrders endpoints.
+const (
+	krakenOrderStatusPending  = "pending"
+	krakenOrderStatusOpen     = "open"
+	krakenOrderStatusClosed   = "closed"
+	krakenOrderStatusCanceled = "canceled"
+	krakenOrderStatusExpired  = "expired"
+)
+
+// krakenQuoteAssets are the balances counted towards account balance and buying power.
+// Kraken reports fiat currencies with a Z prefix (ZUSD) on older accounts.
+var krakenQuoteAssets = map[string]bool{
+	"ZUSD": true,
+	"USD":  true,
+	"USDT": true,
+	"USDC": true,
+}
+
+// KrakenBalance is an extended balance entry of an asset.
+type KrakenBalance struct {
+	// Balance is the total amount of the asset.
+	Balance string `json:"balance"`
+	// HoldTrade is the amount reserved by open orders.
+	HoldTrade string `json:"hold_trade"`
+}
+
+// KrakenOrderDescription describes the parameters an order was placed with.
+type KrakenOrderDescription struct {
+	Pair      string `json:"pair"`
+	Type      string `json:"type"`
+	OrderType string `json:"ordertype"`
+	Price     string `json:"price"`
+	// Price2 is the limit price of stop-loss-limit orders.
+	Price2 string `json:"price2"`
+}
+
+// KrakenOrder is an order returned by the OpenOrders and QueryOrders endpoints.
+type KrakenOrder struct {
+	Status         string                 `json:"status"`
+	OpenTime       float64                `json:"opentm"`
+	Description    KrakenOrderDescription `json:"descr"`
+	Volume         string                 `json:"vol"`
+	VolumeExecuted string                 `json:"vol_exec"`
+	Price          string                 `json:"price"`
+	Fee            string                 `json:"fee"`
+}
+
+// KrakenTrade is an executed trade returned by the TradesHistory endpoint.
+type KrakenTrade struct {
+	OrderTxID string  `json:"ordertxid"`
+	Pair      string  `json:"pair"`
+	Time      float64 `json:"time"`
+	Type      string  `json:"type"`
+	OrderType string  `json:"ordertype"`
+	Price     string  `json:"price"`
+	Fee       string  `json:"fee"`
+	Volume    string  `json:"vol"`
+}
+
+// KrakenTradesHistory is a page of trades, newest first. Count is the total
+// number of trades matching the query across all pages.
+type KrakenTradesHistory struct {
+	Trades map[string]KrakenTrade `json:"trades"`
+	Count  int                    `json:"count"`
+}
+
+// KrakenTicker is the ticker of a pair. LastTrade holds the price and lot volume
+// of the most recent trade.
+type KrakenTicker struct {
+	LastTrade []string `json:"c"`
+}
+
+// KrakenAssetPair is a tradable pair. Base and Quote are the Kraken asset names
+// balances are keyed by (XXBT and ZUSD for XBTUSD).
+type KrakenAssetPair struct {
+	Altname string `json:"altname"`
+	Base    string `json:"base"`
+	Quote   string `json:"quote"`
+}
+
+// KrakenAddOrderRequest contains the parameters of an AddOrder call.
+type KrakenAddOrderRequest struct {
+	Pair      string
+	Type      string
+	OrderType string
+	Volume    string
+	// Price is the limit price of limit orders and the trigger price of stop orders.
+	Price string
+	// Price2 is the limit price of stop-loss-limit orders.
+	Price2 string
+	// TimeInForce is the timeinforce parameter of limit orders. Empty leaves the
+	// Kraken default, good-till-cancelled.
+	TimeInForce string
+	// ClientOrderID is the cl_ord_id the order is placed with. Empty sends none.
+	ClientOrderID string
+}
+
+// KrakenClient abstracts the Kraken REST API for testing.
+type KrakenClient interface {
+	// AddOrder places an order and returns its transaction IDs.
+	AddOrder(ctx context.Context, request KrakenAddOrderRequest) ([]string, error)
+	// GetBalances returns the extended balances keyed by asset.
+	GetBalances(ctx context.Context) (map[string]KrakenBalance, error)
+	// GetOpenOrders returns the open orders keyed by transaction ID.
+	GetOpenOrders(ctx context.Context) (map[string]KrakenOrder, error)
+	// QueryOrders returns the requested orders keyed by transaction ID.
+	QueryOrders(ctx context.Context, txIDs []string) (map[string]KrakenOrder, error)
+	// GetTradesHistory returns a page of trades executed between start and end.
+	// Zero times leave the range open.
+	GetTradesHistory(ctx context.Context, start, end time.Time, offset int) (*KrakenTradesHistory, error)
+	// CancelOrder cancels an open order by transaction ID.
+	CancelOrder(ctx context.Context, txID string) error
+	// CancelAllOrders cancels every open order.
+	CancelAllOrders(ctx context.Context) error
+	// GetTakerFee returns the taker fee of the account for a pair as a fraction (0.0026 = 0.26%).
+	GetTakerFee(ctx context.Context, pair string) (float64, error)
+	// GetTickers returns the tickers keyed by pair. An empty list returns every pair.
+	GetTickers(ctx context.Context, pairs []string) (map[string]KrakenTicker, error)
+	// GetAssetPairs returns the asset pairs keyed by pair. An empty list returns every pair.
+	GetAssetPairs(ctx context.Context, pairs []string) (map[string]KrakenAssetPair, error)
+}
+
+// krakenResponse is the envelope of every Kraken REST response.
+type krakenResponse struct {
+	Error  []string        `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// realKrakenClient calls the Kraken REST API over HTTP.
+// Private endpoints are signed with HMAC-SHA512 as described in the Kraken API docs.
+type realKrakenClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	secret     []byte
+	lastNonce  atomic.Int64
+}
+
+func newRealKrakenClient(config KrakenProviderConfig) (*realKrakenClient, error) {
+	secret, err := base64.StdEncoding.DecodeString(config.SecretKey)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInvalidConfiguration, "kraken secret key must be base64 encoded", err)
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = KrakenBaseURL
+	}
+
+	//nolint:exhaustruct // lastNonce starts at zero
+	return &realKrakenClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second}, //nolint:exhaustruct // default transport
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     config.ApiKey,
+		secret:     secret,
+	}, nil
+}
+
+func (c *realKrakenClient) AddOrder(ctx context.Context, request KrakenAddOrderRequest) ([]string, error) {
+	params := url.Values{}
+	params.Set("pair", request.Pair)
+	params.Set("type", request.Type)
+	params.Set("ordertype", request.OrderType)
+	params.Set("volume", request.Volume)
+
+	if request.Price != "" {
+		params.Set("price", request.Price)
+	}
+
+	if request.Price2 != "" {
+		params.Set("price2", request.Price2)
+	}
+
+	if request.TimeInForce != "" {
+		params.Set("timeinforce", request.TimeInForce)
+	}
+
+	if request.ClientOrderID != "" {
+		params.Set("cl_ord_id", request.ClientOrderID)
+	}
+
+	var result struct {
+		TxID []string `json:"txid"`
+	}
+
+	if err := c.privateRequest(ctx, "AddOrder", params, &result); err != nil {
+		return nil, err
+	}
+
+	return result.TxID, nil
+}
+
+func (c *realKrakenClient) GetBalances(ctx context.Context) (map[string]KrakenBalance, error) {
+	var result map[string]KrakenBalance
+	if err := c.privateRequest(ctx, "BalanceEx", url.Values{}, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *realKrakenClient) GetOpenOrders(ctx context.Context) (map[string]KrakenOrder, error) {
+	var result struct {
+		Open map[string]KrakenOrder `json:"open"`
+	}
+
+	if err := c.privateRequest(ctx, "OpenOrders", url.Values{}, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Open, nil
+}
+
+func (c *realKrakenClient) QueryOrders(ctx context.Context, txIDs []string) (map[string]KrakenOrder, error) {
+	params := url.Values{}
+	params.Set("txid", strings.Join(txIDs, ","))
+
+	var result map[string]KrakenOrder
+	if err := c.privateRequest(ctx, "QueryOrders", params, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *realKrakenClient) GetTradesHistory(ctx context.Context, start, end time.Time, offset int) (*KrakenTradesHistory, error) {
+	params := url.Values{}
+	params.Set("ofs", strconv.Itoa(offset))
+
+	if !start.IsZero() {
+		params.Set("start", strconv.FormatInt(start.Unix(), 10))
+	}
+
+	if !end.IsZero() {
+		params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	}
+
+	var result KrakenTradesHistory
+	if err := c.privateRequest(ctx, "TradesHistory", params, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *realKrakenClient) CancelOrder(ctx context.Context, txID string) error {
+	params := url.Values{}
+	params.Set("txid", txID)
+
+	var result json.RawMessage
+
+	return c.privateRequest(ctx, "CancelOrder", params, &result)
+}
+
+func (c *realKrakenClient) CancelAllOrders(ctx context.Context) error {
+	var result json.RawMessage
+
+	return c.privateRequest(ctx, "CancelAll", url.Values{}, &result)
+}
+
+func (c *realKrakenClient) GetTakerFee(ctx context.Context, pair string) (float64, error) {
+	params := url.Values{}
+	params.Set("pair", pair)
+
+	var result struct {
+		Fees map[string]struct {
+			Fee string `json:"fee"`
+		} `json:"fees"`
+	}
+
+	if err := c.privateRequest(ctx, "TradeVolume", params, &result); err != nil {
+		return 0, err
+	}
+
+	// Kraken normalizes the pair name (XBTUSD -> XXBTZUSD), so take the only entry
+	for _, fee := range result.Fees {
+		percent, err := strconv.ParseFloat(fee.Fee, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid kraken fee %q: %w", fee.Fee, err)
+		}
+
+		return percent / 100, nil
+	}
+
+	return 0, fmt.Errorf("kraken returned no fee for pair %s", pair)
+}
+
+func (c *realKrakenClient) GetTickers(ctx context.Context, pairs []string) (map[string]KrakenTicker, error) {
+	params := url.Values{}
+	if len(pairs) > 0 {
+		params.Set("pair", strings.Join(pairs, ","))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/0/public/Ticker?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kraken request: %w", err)
+	}
+
+	var result map[string]KrakenTicker
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *realKrakenClient) GetAssetPairs(ctx context.Context, pairs []string) (map[string]KrakenAssetPair, error) {
+	params := url.Values{}
+	if len(pairs) > 0 {
+		params.Set("pair", strings.Join(pairs, ","))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/0/public/AssetPairs?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kraken request: %w", err)
+	}
+
+	var result map[string]KrakenAssetPair
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// privateRequest sends a signed POST request to a private endpoint.
+func (c *realKrakenClient) privateRequest(ctx context.Context, method string, params url.Values, result any) error {
+	path := "/0/private/" + method
+	nonce := c.nextNonce()
+	params.Set("nonce", nonce)
+	body := params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create kraken request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("API-Key", c.apiKey)
+	req.Header.Set("API-Sign", signKrakenRequest(c.secret, path, nonce, body))
+
+	return c.do(req, result)
+}
+
+// do executes a request and decodes the result of the response envelope.
+func (c *realKrakenClient) do(req *http.Request, result any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("kraken request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read kraken response: %w", err)
+	}
+
+	var envelope krakenResponse
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return fmt.Errorf("failed to parse kraken response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if len(envelope.Error) > 0 {
+		return fmt.Errorf("kraken api error: %s", strings.Join(envelope.Error, "; "))
+	}
+
+	if err := json.Unmarshal(envelope.Result, result); err != nil {
+		return fmt.Errorf("failed to parse kraken result: %w", err)
+	}
+
+	return nil
+}
+
+// nextNonce returns a strictly increasing nonce, as Kraken rejects reused nonces.
+func (c *realKrakenClient) nextNonce() string {
+	for {
+		last := c.lastNonce.Load()
+
+		next := time.Now().UnixMilli()
+		if next <= last {
+			next = last + 1
+		}
+
+		if c.lastNonce.CompareAndSwap(last, next) {
+			return strconv.FormatInt(next, 10)
+		}
+	}
+}
+
+// signKrakenRequest computes the API-Sign header:
+// base64(HMAC-SHA512(path + SHA256(nonce + body), secret)).
+func signKrakenRequest(secret []byte, path, nonce, body string) string {
+	digest := sha256.Sum256([]byte(nonce + body))
+
+	mac := hmac.New(sha512.New, secret)
+	mac.Write([]byte(path))
+	mac.Write(digest[:])
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// KrakenTradingSystemProvider implements TradingSystemProvider using the Kraken spot API.
+// Like the Binance provider it is stateless - all data is fetched from Kraken -
+// apart from the OCO groups it emulates, since Kraken has no native OCO orders,
+// and the orders it placed: their time in force, which Kraken does not report,
+// and the transaction IDs of their order IDs, as Kraken cannot query orders by
+// client order ID.
+// Symbols are Kraken pair names (e.g. XBTUSD) and positions are keyed by Kraken
+// asset names (e.g. XXBT). GetPosition maps a pair to its base asset.
+type KrakenTradingSystemProvider struct {
+	client           KrakenClient
+	decimalPrecision int
+	onStatusChange   OnStatusChange
+	oco              *OCOEmulator
+	// orderTimeInForce maps the transaction ID of each order placed with a time
+	// in force other than good-till-cancelled to it.
+	orderTimeInForce map[string]types.TimeInForce
+	// orderTxIDs maps the ID of each order placed to its transaction ID, and
+	// placedOrderIDs maps the transaction ID back.
+	orderTxIDs     map[string]string
+	placedOrderIDs map[string]string
+	ordersMu       sync.Mutex
+	// pairBaseAssets caches the base asset of each pair positions were requested for.
+	pairBaseAssets   map[string]string
+	pairBaseAssetsMu sync.Mutex
+}
+
+// NewKrakenTradingSystemProvider creates a new Kraken trading system.
+// Kraken has no spot sandbox, so every order is placed with real funds.
+func NewKrakenTradingSystemProvider(config KrakenProviderConfig) (*KrakenTradingSystemProvider, error) {
+	debugLog.Info("NewKrakenTradingSystemProvider",
+		zap.Bool("hasApiKey", config.ApiKey != ""),
+		zap.Bool("hasSecretKey", config.SecretKey != ""),
+		zap.String("baseURL", config.BaseURL),
+	)
+
+	client, err := newRealKrakenClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := newKrakenTradingSystemProviderWithClient(client)
+	provider.oco = NewOCOEmulator(provider, DefaultOCOPollInterval)
+
+	return provider, nil
+}
+
+// newKrakenTradingSystemProviderWithClient creates a new Kraken trading system with a custom client.
+// This is used for testing with mock clients. Emulated OCO groups are not watched
+// in the background; tests call CheckFills on the emulator directly.
+func newKrakenTradingSystemProviderWithClient(client KrakenClient) *KrakenTradingSystemProvider {
+	provider := &KrakenTradingSystemProvider{
+		client:           client,
+		decimalPrecision: KrakenDecimalPrecision,
+		onStatusChange:   nil,
+		oco:              nil,
+		orderTimeInForce: map[string]types.TimeInForce{},
+		orderTxIDs:       map[string]string{},
+		placedOrderIDs:   map[string]string{},
+		ordersMu:         sync.Mutex{},
+		pairBaseAssets:   map[string]string{},
+		pairBaseAssetsMu: sync.Mutex{},
+	}
+	provider.oco = NewOCOEmulator(provider, 0)
+
+	return provider
+}
+
+// PlaceOrder places a single order on Kraken.
+func (k *KrakenTradingSystemProvider) PlaceOrder(order types.ExecuteOrder) error {
+	ctx := context.Background()
+
+	var side string
+
+	switch order.Side {
+	case types.PurchaseTypeBuy:
+		side = "buy"
+	case types.PurchaseTypeSell:
+		side = "sell"
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unsupported order side: %s", order.Side)
+	}
+
+	var orderType string
+
+	switch order.OrderType {
+	case types.OrderTypeMarket:
+		orderType = "market"
+	case types.OrderTypeLimit:
+		orderType = "limit"
+	case types.OrderTypeStopMarket:
+		orderType = "stop-loss"
+	case types.OrderTypeStopLimit:
+		orderType = "stop-loss-limit"
+	default:
+		return errors.Newf(errors.ErrCodeInvalidParameter, "unsupported order type: %s", order.OrderType)
+	}
+
+	if order.Quantity <= 0 {
+		return errors.New(errors.ErrCodeInvalidParameter, "order quantity must be greater than zero")
+	}
+
+	// Kraken spot has no fill-or-kill orders
+	if order.TimeInForce == types.TimeInForceFOK {
+		return errors.New(errors.ErrCodeInvalidParameter, "fill-or-kill orders are not supported on Kraken")
+	}
+
+	roundedQuantity := utils.RoundToDecimalPrecision(order.Quantity, k.decimalPrecision)
+	if roundedQuantity <= 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter,
+			"order quantity %.8f is too small after rounding to %d decimal places",
+			order.Quantity, k.decimalPrecision)
+	}
+
+	request := KrakenAddOrderRequest{
+		Pair:          order.Symbol,
+		Type:          side,
+		OrderType:     orderType,
+		Volume:        strconv.FormatFloat(roundedQuantity, 'f', k.decimalPrecision, 64),
+		Price:         "",
+		Price2:        "",
+		TimeInForce:   "",
+		ClientOrderID: "",
+	}
+
+	// The order ID is sent as the client order ID when Kraken accepts it
+	if krakenClientOrderIDPattern.MatchString(order.ID) {
+		request.ClientOrderID = order.ID
+	}
+
+	switch order.OrderType {
+	case types.OrderTypeLimit:
+		request.Price = strconv.FormatFloat(order.Price, 'f', -1, 64)
+		// Market orders fill immediately, so the time in force only applies to limit orders
+		if order.TimeInForce == types.TimeInForceIOC {
+			request.TimeInForce = "IOC"
+		}
+	case types.OrderTypeStopMarket:
+		request.Price = strconv.FormatFloat(order.StopPrice, 'f', -1, 64)
+	case types.OrderTypeStopLimit:
+		request.Price = strconv.FormatFloat(order.StopPrice, 'f', -1, 64)
+		request.Price2 = strconv.FormatFloat(order.Price, 'f', -1, 64)
+	}
+
+	txIDs, err := k.client.AddOrder(ctx, request)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to place order on Kraken", err)
+	}
+
+	k.rememberOrder(order, request, txIDs)
+
+	return nil
+}
+
+// rememberOrder records the transaction IDs of a placed order.
+func (k *KrakenTradingSystemProvider) rememberOrder(order types.ExecuteOrder, request KrakenAddOrderRequest, txIDs []string) {
+	k.ordersMu.Lock()
+	defer k.ordersMu.Unlock()
+
+	for _, txID := range txIDs {
+		if request.TimeInForce != "" {
+			k.orderTimeInForce[txID] = order.TimeInForce
+		}
+
+		if order.ID != "" {
+			k.orderTxIDs[order.ID] = txID
+			k.placedOrderIDs[txID] = order.ID
+		}
+	}
+}
+
+// txIDOf returns the transaction ID of the order placed with orderID, or
+// orderID itself when it is not the ID of a placed order.
+func (k *KrakenTradingSystemProvider) txIDOf(orderID string) string {
+	k.ordersMu.Lock()
+	defer k.ordersMu.Unlock()
+
+	if txID, ok := k.orderTxIDs[orderID]; ok {
+		return txID
+	}
+
+	return orderID
+}
+
+// placedOrderID returns the ID the order with the transaction ID was placed
+// with, or the transaction ID for orders the provider did not place.
+func (k *KrakenTradingSystemProvider) placedOrderID(txID string) string {
+	k.ordersMu.Lock()
+	defer k.ordersMu.Unlock()
+
+	if orderID, ok := k.placedOrderIDs[txID]; ok {
+		return orderID
+	}
+
+	return txID
+}
+
+// timeInForceOf returns the time in force an order was placed with.
+func (k *KrakenTradingSystemProvider) timeInForceOf(txID string) types.TimeInForce {
+	k.ordersMu.Lock()
+	defer k.ordersMu.Unlock()
+
+	if timeInForce, ok := k.orderTimeInForce[txID]; ok {
+		return timeInForce
+	}
+
+	return types.TimeInForceGTC
+}
+
+// PlaceOCOOrder places an OCO group. Kraken has no native OCO orders, so the
+// legs are placed as separate orders and the remaining leg is cancelled once the
+// other one fills.
+func (k *KrakenTradingSystemProvider) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	return k.oco.PlaceOCOOrder(entry, takeProfit, stopLoss)
+}
+
+// PlaceBracketOrder implements TradingSystemProvider through the emulated OCO
+// groups of PlaceOCOOrder.
+func (k *KrakenTradingSystemProvider) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	return PlaceBracketOrderAsOCO(k, entry, stopLoss, takeProfit)
+}
+
+// PlaceMultipleOrders places multiple orders sequentially.
+func (k *KrakenTradingSystemProvider) PlaceMultipleOrders(orders []types.ExecuteOrder) error {
+	for _, order := range orders {
+		if err := k.PlaceOrder(order); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetPositions returns all positions derived from account balances.
+func (k *KrakenTradingSystemProvider) GetPositions() ([]types.Position, error) {
+	balances, err := k.client.GetBalances(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get balances from Kraken", err)
+	}
+
+	assets := make([]string, 0, len(balances))
+	for asset := range balances {
+		assets = append(assets, asset)
+	}
+
+	sort.Strings(assets)
+
+	positions := make([]types.Position, 0, len(assets))
+
+	for _, asset := range assets {
+		total, _ := strconv.ParseFloat(balances[asset].Balance, 64)
+		if total <= 0 {
+			continue
+		}
+
+		positions = append(positions, newKrakenPosition(asset, total))
+	}
+
+	return positions, nil
+}
+
+// GetPosition returns the position for a specific asset, or for a pair the
+// balance of its base asset: the position in XBTUSD is the XXBT balance.
+func (k *KrakenTradingSystemProvider) GetPosition(symbol string) (types.Position, error) {
+	positions, err := k.GetPositions()
+	if err != nil {
+		return types.Position{}, err
+	}
+
+	for _, pos := range positions {
+		if pos.Symbol == symbol {
+			return pos, nil
+		}
+	}
+
+	if baseAsset := k.pairBaseAsset(context.Background(), symbol); baseAsset != "" {
+		for _, pos := range positions {
+			if pos.Symbol == baseAsset {
+				pos.Symbol = symbol
+
+				return pos, nil
+			}
+		}
+	}
+
+	return newKrakenPosition(symbol, 0), nil
+}
+
+// pairBaseAsset returns the base asset of a pair, fetching it from Kraken on
+// first use and caching it afterwards. Symbols that are not pairs, and pairs
+// whose asset pair cannot be fetched, have no base asset.
+func (k *KrakenTradingSystemProvider) pairBaseAsset(ctx context.Context, pair string) string {
+	k.pairBaseAssetsMu.Lock()
+	baseAsset, ok := k.pairBaseAssets[pair]
+	k.pairBaseAssetsMu.Unlock()
+
+	if ok {
+		return baseAsset
+	}
+
+	assetPairs, err := k.client.GetAssetPairs(ctx, []string{pair})
+	if err != nil {
+		debugLog.Warn("Failed to fetch Kraken asset pair", zap.String("pair", pair), zap.Error(err))
+
+		return ""
+	}
+
+	// Kraken normalizes the pair name (XBTUSD -> XXBTZUSD), so take the only entry
+	for _, assetPair := range assetPairs {
+		baseAsset = assetPair.Base
+	}
+
+	k.pairBaseAssetsMu.Lock()
+	k.pairBaseAssets[pair] = baseAsset
+	k.pairBaseAssetsMu.Unlock()
+
+	return baseAsset
+}
+
+// CancelOrder cancels an order by transaction ID.
+func (k *KrakenTradingSystemProvider) CancelOrder(orderID string) error {
+	if err := k.client.CancelOrder(context.Background(), orderID); err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to cancel order on Kraken", err)
+	}
+
+	return nil
+}
+
+// CancelAllOrders cancels all open orders.
+func (k *KrakenTradingSystemProvider) CancelAllOrders() error {
+	if err := k.client.CancelAllOrders(context.Background()); err != nil {
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to cancel orders on Kraken", err)
+	}
+
+	return nil
+}
+
+// CancelOrdersForSymbol cancels the open orders of a symbol one by one, as
+// Kraken has no endpoint cancelling the orders of a single pair.
+func (k *KrakenTradingSystemProvider) CancelOrdersForSymbol(symbol string) error {
+	openOrders, err := k.GetOpenOrders()
+	if err != nil {
+		return err
+	}
+
+	for _, order := range openOrders {
+		if order.Symbol != symbol {
+			continue
+		}
+
+		if err := k.CancelOrder(order.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetOrderStatus returns the status of an order by the ID it was placed with or
+// by its transaction ID.
+// Unlike Binance, Kraken can look up closed orders by transaction ID alone.
+func (k *KrakenTradingSystemProvider) GetOrderStatus(orderID string) (types.OrderStatus, error) {
+	txID := k.txIDOf(orderID)
+
+	orders, err := k.client.QueryOrders(context.Background(), []string{txID})
+	if err != nil {
+		return types.OrderStatusFailed, errors.Wrap(errors.ErrCodeOrderFailed, "failed to query order from Kraken", err)
+	}
+
+	order, ok := orders[txID]
+	if !ok {
+		return types.OrderStatusFailed, errors.Newf(errors.ErrCodeDataNotFound, "order not found: %s", orderID)
+	}
+
+	return mapKrakenOrderStatus(order.Status), nil
+}
+
+// GetAccountInfo returns the current account state.
+func (k *KrakenTradingSystemProvider) GetAccountInfo() (types.AccountInfo, error) {
+	balances, err := k.client.GetBalances(context.Background())
+	if err != nil {
+		return types.AccountInfo{}, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get balances from Kraken", err)
+	}
+
+	var totalBalance, buyingPower float64
+
+	for asset, balance := range balances {
+		if !krakenQuoteAssets[asset] {
+			continue
+		}
+
+		total, _ := strconv.ParseFloat(balance.Balance, 64)
+		held, _ := strconv.ParseFloat(balance.HoldTrade, 64)
+		totalBalance += total
+		buyingPower += math.Max(total-held, 0)
+	}
+
+	return types.AccountInfo{
+		Balance:       totalBalance,
+		Equity:        totalBalance, // For spot, equity equals balance
+		BuyingPower:   buyingPower,
+		RealizedPnL:   0, // Not tracked in spot
+		UnrealizedPnL: 0, // Would need current prices to calculate
+		TotalFees:     0, // Not available from balances
+		MarginUsed:    0, // Not applicable for spot
+		FreeMargin:    0, // Not applicable for spot
+		Balances:      nil,
+	}, nil
+}
+
+// GetAssets returns all asset balances reported by Kraken, including amounts
+// held by open orders. Zero-quantity assets are omitted.
+func (k *KrakenTradingSystemProvider) GetAssets() ([]types.Asset, error) {
+	positions, err := k.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]types.Asset, 0, len(positions))
+
+	for _, position := range positions {
+		assets = append(assets, types.Asset{
+			Symbol:            position.Symbol,
+			Quantity:          position.TotalLongPositionQuantity,
+			BaseCurrency:      "",
+			BaseCurrencyValue: nil,
+		})
+	}
+
+	return assets, nil
+}
+
+// GetPrices returns the last trade price of each requested pair via the public
+// Ticker endpoint, keyed by the requested pair name. Kraken keys its response by
+// the normalized pair name (XXBTZUSD for XBTUSD), which cannot be mapped back when
+// several pairs share a request, so each pair is requested on its own. Without
+// symbols every pair is returned under its normalized name.
+func (k *KrakenTradingSystemProvider) GetPrices(symbols []string) (map[string]float64, error) {
+	ctx := context.Background()
+
+	if len(symbols) == 0 {
+		return k.tickerPrices(ctx, nil)
+	}
+
+	out := make(map[string]float64, len(symbols))
+
+	for _, symbol := range symbols {
+		prices, err := k.tickerPrices(ctx, []string{symbol})
+		if err != nil {
+			return nil, err
+		}
+
+		// A single requested pair is keyed by the caller's name so lookups succeed
+		for _, price := range prices {
+			out[symbol] = price
+		}
+	}
+
+	return out, nil
+}
+
+// tickerPrices returns the last trade price of the pairs keyed by the pair name
+// Kraken returns.
+func (k *KrakenTradingSystemProvider) tickerPrices(ctx context.Context, pairs []string) (map[string]float64, error) {
+	tickers, err := k.client.GetTickers(ctx, pairs)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get prices from Kraken", err)
+	}
+
+	out := make(map[string]float64, len(tickers))
+
+	for pair, ticker := range tickers {
+		if len(ticker.LastTrade) == 0 {
+			continue
+		}
+
+		price, parseErr := strconv.ParseFloat(ticker.LastTrade[0], 64)
+		if parseErr != nil || price <= 0 {
+			continue
+		}
+
+		out[pair] = price
+	}
+
+	return out, nil
+}
+
+// GetOpenOrders returns all open orders, oldest first.
+func (k *KrakenTradingSystemProvider) GetOpenOrders() ([]types.ExecuteOrder, error) {
+	krakenOrders, err := k.client.GetOpenOrders(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get open orders from Kraken", err)
+	}
+
+	txIDs := make([]string, 0, len(krakenOrders))
+	for txID := range krakenOrders {
+		txIDs = append(txIDs, txID)
+	}
+
+	sort.Slice(txIDs, func(i, j int) bool {
+		return krakenOrders[txIDs[i]].OpenTime < krakenOrders[txIDs[j]].OpenTime
+	})
+
+	orders := make([]types.ExecuteOrder, 0, len(txIDs))
+
+	for _, txID := range txIDs {
+		order, convertErr := convertKrakenOrderToExecuteOrder(txID, krakenOrders[txID], k.timeInForceOf(txID))
+		if convertErr != nil {
+			continue // Skip orders that can't be converted
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// GetTrades returns executed trades with optional filtering, oldest first.
+// The symbol filter matches the pair name Kraken reports in the trade history.
+// With a limit, the most recent matching trades are returned. Trades of orders
+// the provider placed carry the ID the order was placed with.
+func (k *KrakenTradingSystemProvider) GetTrades(filter types.TradeFilter) ([]types.Trade, error) {
+	ctx := context.Background()
+	trades := make([]types.Trade, 0)
+
+	// Kraken pages trades newest first, so stop as soon as the limit is reached
+	for offset := 0; ; offset += krakenTradesPageSize {
+		page, err := k.client.GetTradesHistory(ctx, filter.StartTime, filter.EndTime, offset)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrCodeOrderFailed, "failed to get trades from Kraken", err)
+		}
+
+		for tradeID, kt := range page.Trades {
+			if filter.Symbol != "" && kt.Pair != filter.Symbol {
+				continue
+			}
+
+			trade := convertKrakenTradeToTrade(tradeID, kt)
+			trade.Order.OrderID = k.placedOrderID(kt.OrderTxID)
+			trades = append(trades, trade)
+		}
+
+		if len(page.Trades) == 0 || offset+krakenTradesPageSize >= page.Count ||
+			(filter.Limit > 0 && len(trades) >= filter.Limit) {
+			break
+		}
+	}
+
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].ExecutedAt.Before(trades[j].ExecutedAt)
+	})
+
+	if filter.Limit > 0 && len(trades) > filter.Limit {
+		trades = trades[len(trades)-filter.Limit:]
+	}
+
+	return trades, nil
+}
+
+// GetTradesPage returns the trades of GetTrades as a single page. Kraken pages its
+// trade history by offset from the newest trade, which shifts as trades are added,
+// so it cannot offer a stable cursor.
+func (k *KrakenTradingSystemProvider) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	if filter.Cursor != "" {
+		return types.TradePage{}, errors.New(errors.ErrCodeInvalidParameter, "trade cursors are not supported on Kraken")
+	}
+
+	trades, err := k.GetTrades(filter)
+	if err != nil {
+		return types.TradePage{}, err
+	}
+
+	return types.TradePage{Trades: trades, NextCursor: ""}, nil
+}
+
+// GetMaxBuyQuantity returns the maximum quantity that can be bought at the given price.
+// It reserves the account's taker fee for the pair, falling back to the base tier fee.
+func (k *KrakenTradingSystemProvider) GetMaxBuyQuantity(symbol string, price float64) (float64, error) {
+	if price <= 0 {
+		return 0, errors.New(errors.ErrCodeInvalidParameter, "price must be greater than zero")
+	}
+
+	accountInfo, err := k.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	feeRate := krakenDefaultTakerFee
+
+	if symbol != "" {
+		if takerFee, feeErr := k.client.GetTakerFee(context.Background(), symbol); feeErr == nil && takerFee >= 0 {
+			feeRate = takerFee
+		}
+	}
+
+	return accountInfo.BuyingPower / (1 + feeRate) / price, nil
+}
+
+// GetMaxSellQuantity returns the maximum quantity that can be sold for an asset.
+func (k *KrakenTradingSystemProvider) GetMaxSellQuantity(symbol string) (float64, error) {
+	position, err := k.GetPosition(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	return position.TotalLongPositionQuantity, nil
+}
+
+// CalculatePositionSize sizes a position by the risk taken to its stop, using
+// the account equity reported by Kraken, rounded down to the volume precision
+// orders are placed with.
+func (k *KrakenTradingSystemProvider) CalculatePositionSize(symbol string, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	accountInfo, err := k.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	quantity, err := RiskPositionSize(accountInfo.Equity, entryPrice, stopPrice, riskFraction)
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.RoundToDecimalPrecision(quantity, k.decimalPrecision), nil
+}
+
+// CheckConnection verifies connectivity and authentication by fetching the account balances.
+func (k *KrakenTradingSystemProvider) CheckConnection(ctx context.Context) error {
+	if _, err := k.client.GetBalances(ctx); err != nil {
+		debugLog.Warn("CheckConnection: failed to reach Kraken", zap.Error(err))
+		k.emitStatus(types.ProviderStatusDisconnected)
+
+		return errors.Wrap(errors.ErrCodeOrderFailed, "failed to connect to Kraken API", err)
+	}
+
+	k.emitStatus(types.ProviderStatusConnected)
+
+	return nil
+}
+
+// SetOnStatusChange sets a callback that will be called when the connection status changes.
+func (k *KrakenTradingSystemProvider) SetOnStatusChange(callback OnStatusChange) {
+	k.onStatusChange = callback
+}
+
+// emitStatus emits a status change if a callback is registered.
+func (k *KrakenTradingSystemProvider) emitStatus(status types.ProviderConnectionStatus) {
+	if k.onStatusChange != nil {
+		k.onStatusChange(status)
+	}
+}
+
+// mapKrakenOrderStatus maps Kraken order status to our OrderStatus type.
+func mapKrakenOrderStatus(status string) types.OrderStatus {
+	switch status {
+	case krakenOrderStatusPending, krakenOrderStatusOpen:
+		return types.OrderStatusPending
+	case krakenOrderStatusClosed:
+		return types.OrderStatusFilled
+	case krakenOrderStatusCanceled:
+		return types.OrderStatusCancelled
+	case krakenOrderStatusExpired:
+		return types.OrderStatusFailed
+	default:
+		return types.OrderStatusFailed
+	}
+}
+
+// newKrakenPosition returns a spot (long only) position for an asset balance.
+func newKrakenPosition(asset string, quantity float64) types.Position {
+	return types.Position{
+		Symbol:                        asset,
+		TotalLongPositionQuantity:     quantity,
+		TotalShortPositionQuantity:    0,
+		TotalLongInPositionQuantity:   0,
+		TotalLongOutPositionQuantity:  0,
+		TotalLongInPositionAmount:     0,
+		TotalLongOutPositionAmount:    0,
+		TotalShortInPositionQuantity:  0,
+		TotalShortOutPositionQuantity: 0,
+		TotalShortInPositionAmount:    0,
+		TotalShortOutPositionAmount:   0,
+		TotalLongInFee:                0,
+		TotalLongOutFee:               0,
+		TotalShortInFee:               0,
+		TotalShortOutFee:              0,
+		AverageLongEntryPrice:         0,
+		TotalLongCost:                 0,
+		AverageShortEntryPrice:        0,
+		TotalShortCost:                0,
+		OpenTimestamp:                 time.Time{},
+		StrategyName:                  "",
+	}
+}
+
+// mapKrakenSide maps a Kraken buy/sell type to our PurchaseType.
+func mapKrakenSide(side string) (types.PurchaseType, error) {
+	switch side {
+	case "buy":
+		return types.PurchaseTypeBuy, nil
+	case "sell":
+		return types.PurchaseTypeSell, nil
+	default:
+		return "", errors.Newf(errors.ErrCodeInvalidParameter, "unknown side: %s", side)
+	}
+}
+
+// convertKrakenOrderToExecuteOrder converts a Kraken order to our ExecuteOrder type.
+// Kraken does not report the time in force of an order, so the caller passes it.
+func convertKrakenOrderToExecuteOrder(txID string, ko KrakenOrder, timeInForce types.TimeInForce) (types.ExecuteOrder, error) {
+	quantity, _ := strconv.ParseFloat(ko.Volume, 64)
+	filledQuantity, _ := strconv.ParseFloat(ko.VolumeExecuted, 64)
+	price, _ := strconv.ParseFloat(ko.Description.Price, 64)
+
+	side, err := mapKrakenSide(ko.Description.Type)
+	if err != nil {
+		return types.ExecuteOrder{}, err
+	}
+
+	var stopPrice float64
+
+	orderType := types.OrderTypeLimit // Default to limit for take-profit types
+
+	switch ko.Description.OrderType {
+	case "market":
+		orderType = types.OrderTypeMarket
+	case "stop-loss":
+		orderType = types.OrderTypeStopMarket
+		stopPrice = price
+	case "stop-loss-limit":
+		orderType = types.OrderTypeStopLimit
+		stopPrice = price
+		price, _ = strconv.ParseFloat(ko.Description.Price2, 64)
+	}
+
+	return types.ExecuteOrder{
+		ID:        txID,
+		Symbol:    ko.Description.Pair,
+		Side:      side,
+		OrderType: orderType,
+		Reason: types.Reason{
+			Reason:  types.OrderReasonStrategy,
+			Message: "Order from Kraken",
+		},
+		Price:          price,
+		StrategyName:   "",
+		Quantity:       quantity,
+		PositionType:   types.PositionTypeLong, // Spot only supports long
+		StopPrice:      stopPrice,
+		TrailOffset:    0,
+		TrailPercent:   false,
+		FilledQuantity: filledQuantity,
+		TimeInForce:    timeInForce,
+		GoodTillDate:   time.Time{},
+		TakeProfit:     optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+		StopLoss:       optional.None[types.ExecuteOrderTakeProfitOrStopLoss](),
+	}, nil
+}
+
+// convertKrakenTradeToTrade converts a Kraken trade to our Trade type.
+func convertKrakenTradeToTrade(tradeID string, kt KrakenTrade) types.Trade {
+	quantity, _ := strconv.ParseFloat(kt.Volume, 64)
+	price, _ := strconv.ParseFloat(kt.Price, 64)
+	fee, _ := strconv.ParseFloat(kt.Fee, 64)
+
+	side := types.PurchaseTypeSell
+	if kt.Type == "buy" {
+		side = types.PurchaseTypeBuy
+	}
+
+	seconds, fraction := math.Modf(kt.Time)
+	executedAt := time.Unix(int64(seconds), int64(fraction*float64(time.Second)))
+
+	return types.Trade{
+		Order: types.Order{
+			OrderID:      kt.OrderTxID,
+			Symbol:       kt.Pair,
+			Side:         side,
+			Quantity:     quantity,
+			Price:        price,
+			Timestamp:    executedAt,
+			IsCompleted:  true,
+			Status:       types.OrderStatusFilled,
+			Reason:       types.Reason{Reason: types.OrderReasonStrategy, Message: "Trade from Kraken " + tradeID},
+			StrategyName: "",
+			Fee:          fee,
+			PositionType: types.PositionTypeLong,
+		},
+		ExecutedAt:      executedAt,
+		ExecutedQty:     quantity,
+		ExecutedPrice:   price,
+		Fee:             fee,
+		PnL:             0, // Not available from trade history
+		CumulativePnL:   0, // Not available from trade history
+		LIFOPnL:         0, // Not available from trade history
+		RealizedPnL:     0, // Not available from trade history
+		CostBasis:       0, // Not available from trade history
+		OpenPositionQty: 0,
+		Balance:         0,
+		HoldTime:        0,
+		AverageCost:     0,
+	}
+}
+
+// Ensure KrakenTradingSystemProvider implements TradingSystemProvider.
+var _ TradingSystemProvider = (*KrakenTradingSystemProvider)(nil)
@@ -0,0 +1,39 @@
+package tradingprovider
+
+import (
+	"encoding/json"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// KrakenProviderConfig contains configuration for Kraken trading.
+type KrakenProviderConfig struct {
+	ApiKey    string `json:"apiKey" jsonschema:"title=API Key,description=Kraken API key" keychain:"true" validate:"required"`
+	SecretKey string `json:"secretKey" jsonschema:"title=Secret Key,description=Kraken private key (base64 encoded)" keychain:"true" validate:"required,base64"`
+	BaseURL   string `json:"baseUrl,omitempty" jsonschema:"title=Base URL,description=Custom REST API base URL (optional). Defaults to https://api.kraken.com."`
+}
+
+// Validate validates the KrakenProviderConfig struct.
+func (c *KrakenProviderConfig) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(c); err != nil {
+		return errors.Wrap(errors.ErrCodeInvalidParameter, "invalid kraken provider config", err)
+	}
+
+	return nil
+}
+
+// parseKrakenConfig parses a JSON configuration string into a KrakenProviderConfig.
+func parseKrakenConfig(jsonConfig string) (*KrakenProviderConfig, error) {
+	var config KrakenProviderConfig
+	if err := json.Unmarshal([]byte(jsonConfig), &config); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInvalidParameter, "failed to parse kraken config", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,861 @@
+package tradingprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	argoErrors "github.com/rxtech-lab/argo-trading/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockKrakenClient implements KrakenClient interface for testing
+type mockKrakenClient struct {
+	addOrderRequests []KrakenAddOrderRequest
+	addOrderErr      error
+	balances         map[string]KrakenBalance
+	balancesErr      error
+	openOrders       map[string]KrakenOrder
+	openOrdersErr    error
+	queriedOrders    map[string]KrakenOrder
+	queryOrdersErr   error
+	queriedTxIDs     [][]string
+	tradePages       []*KrakenTradesHistory
+	tradesErr        error
+	tradeOffsets     []int
+	cancelledTxID    string
+	cancelErr        error
+	cancelAllCalled  bool
+	takerFee         float64
+	takerFeeErr      error
+	tickers          map[string]KrakenTicker
+	tickersErr       error
+	// pairNames maps requested pairs to the normalized names tickers are keyed by.
+	// Without it every ticker is returned.
+	pairNames      map[string]string
+	tickerRequests [][]string
+	assetPairs     map[string]KrakenAssetPair
+}
+
+func newMockKrakenClient() *mockKrakenClient {
+	return &mockKrakenClient{
+		balances:      map[string]KrakenBalance{},
+		openOrders:    map[string]KrakenOrder{},
+		queriedOrders: map[string]KrakenOrder{},
+		tickers:       map[string]KrakenTicker{},
+	}
+}
+
+func (m *mockKrakenClient) AddOrder(_ context.Context, request KrakenAddOrderRequest) ([]string, error) {
+	m.addOrderRequests = append(m.addOrderRequests, request)
+	if m.addOrderErr != nil {
+		return nil, m.addOrderErr
+	}
+
+	return []string{"OQCLML-BW3P3-BUCMWZ"}, nil
+}
+
+func (m *mockKrakenClient) GetBalances(_ context.Context) (map[string]KrakenBalance, error) {
+	return m.balances, m.balancesErr
+}
+
+func (m *mockKrakenClient) GetOpenOrders(_ context.Context) (map[string]KrakenOrder, error) {
+	return m.openOrders, m.openOrdersErr
+}
+
+func (m *mockKrakenClient) QueryOrders(_ context.Context, txIDs []string) (map[string]KrakenOrder, error) {
+	m.queriedTxIDs = append(m.queriedTxIDs, txIDs)
+
+	return m.queriedOrders, m.queryOrdersErr
+}
+
+func (m *mockKrakenClient) GetTradesHistory(_ context.Context, _, _ time.Time, offset int) (*KrakenTradesHistory, error) {
+	m.tradeOffsets = append(m.tradeOffsets, offset)
+	if m.tradesErr != nil {
+		return nil, m.tradesErr
+	}
+
+	page := offset / krakenTradesPageSize
+	if page >= len(m.tradePages) {
+		return &KrakenTradesHistory{Trades: map[string]KrakenTrade{}, Count: 0}, nil
+	}
+
+	return m.tradePages[page], nil
+}
+
+func (m *mockKrakenClient) CancelOrder(_ context.Context, txID string) error {
+	m.cancelledTxID = txID
+
+	return m.cancelErr
+}
+
+func (m *mockKrakenClient) CancelAllOrders(_ context.Context) error {
+	m.cancelAllCalled = true
+
+	return m.cancelErr
+}
+
+func (m *mockKrakenClient) GetTakerFee(_ context.Context, _ string) (float64, error) {
+	return m.takerFee, m.takerFeeErr
+}
+
+func (m *mockKrakenClient) GetTickers(_ context.Context, pairs []string) (map[string]KrakenTicker, error) {
+	m.tickerRequests = append(m.tickerRequests, pairs)
+	if m.tickersErr != nil || len(pairs) == 0 || m.pairNames == nil {
+		return m.tickers, m.tickersErr
+	}
+
+	tickers := map[string]KrakenTicker{}
+
+	for _, pair := range pairs {
+		name := m.pairNames[pair]
+		if ticker, ok := m.tickers[name]; ok {
+			tickers[name] = ticker
+		}
+	}
+
+	return tickers, nil
+}
+
+func (m *mockKrakenClient) GetAssetPairs(_ context.Context, pairs []string) (map[string]KrakenAssetPair, error) {
+	assetPairs := map[string]KrakenAssetPair{}
+
+	for _, pair := range pairs {
+		if assetPair, ok := m.assetPairs[pair]; ok {
+			assetPairs[m.pairNames[pair]] = assetPair
+		}
+	}
+
+	if len(assetPairs) == 0 {
+		return nil, errors.New("EQuery:Unknown asset pair")
+	}
+
+	return assetPairs, nil
+}
+
+type KrakenTradingTestSuite struct {
+	suite.Suite
+}
+
+func TestKrakenTradingSuite(t *testing.T) {
+	suite.Run(t, new(KrakenTradingTestSuite))
+}
+
+// Unit Tests - Config
+
+func (suite *KrakenTradingTestSuite) TestParseKrakenConfig() {
+	tests := []struct {
+		name          string
+		jsonConfig    string
+		expectedError string
+	}{
+		{name: "valid", jsonConfig: `{"apiKey": "key", "secretKey": "c2VjcmV0"}`},
+		{name: "missing api key", jsonConfig: `{"secretKey": "c2VjcmV0"}`, expectedError: "invalid kraken provider config"},
+		{name: "secret is not base64", jsonConfig: `{"apiKey": "key", "secretKey": "not base64!"}`, expectedError: "invalid kraken provider config"},
+		{name: "invalid json", jsonConfig: `{invalid}`, expectedError: "failed to parse kraken config"},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			config, err := parseKrakenConfig(tc.jsonConfig)
+			if tc.expectedError != "" {
+				suite.Error(err)
+				suite.Contains(err.Error(), tc.expectedError)
+
+				return
+			}
+
+			suite.NoError(err)
+			suite.Equal("key", config.ApiKey)
+		})
+	}
+}
+
+func (suite *KrakenTradingTestSuite) TestSignKrakenRequest() {
+	// Example from the Kraken REST API authentication documentation
+	secret, err := base64.StdEncoding.DecodeString("kQH5HW/8p1uGOVjbgWA7FunAmGO8lsSUXNsu3eow76sz84Q18fWxnyRzBHCd3pd5nE9qa99HAZtuZuj6F1huXg==")
+	suite.Require().NoError(err)
+
+	body := "nonce=1616492376594&ordertype=limit&pair=XBTUSD&price=37500&type=buy&volume=1.25"
+	signature := signKrakenRequest(secret, "/0/private/AddOrder", "1616492376594", body)
+	suite.Equal("4/dpxb3iT4tp/ZCVEwSnEsLxx0bqyhLpdfOpc6fn7OR8+UClSV5n9E6aSS8MPtnRfp32bAb0nmbRn6H8ndwLUQ==", signature)
+}
+
+func (suite *KrakenTradingTestSuite) TestMapKrakenOrderStatus() {
+	tests := []struct {
+		status   string
+		expected types.OrderStatus
+	}{
+		{"pending", types.OrderStatusPending},
+		{"open", types.OrderStatusPending},
+		{"closed", types.OrderStatusFilled},
+		{"canceled", types.OrderStatusCancelled},
+		{"expired", types.OrderStatusFailed},
+		{"unknown", types.OrderStatusFailed},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.status, func() {
+			suite.Equal(tc.expected, mapKrakenOrderStatus(tc.status))
+		})
+	}
+}
+
+// PlaceOrder Tests
+
+func (suite *KrakenTradingTestSuite) TestPlaceOrder_MarketBuy_Success() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "XBTUSD",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.001,
+	})
+	suite.NoError(err)
+	suite.Require().Len(mockClient.addOrderRequests, 1)
+	suite.Equal(KrakenAddOrderRequest{
+		Pair:      "XBTUSD",
+		Type:      "buy",
+		OrderType: "market",
+		Volume:    "0.00100000",
+		Price:     "",
+	}, mockClient.addOrderRequests[0])
+}
+
+// PlaceOCOOrder Tests (emulated)
+
+// krakenOCOOrders returns a market entry with a take profit at 55000 and a stop loss triggering at 48000.
+func krakenOCOOrders() (types.ExecuteOrder, types.ExecuteOrder, types.ExecuteOrder) {
+	entry := types.ExecuteOrder{Symbol: "XBTUSD", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 0.01}
+	takeProfit := types.ExecuteOrder{Symbol: "XBTUSD", Side: types.PurchaseTypeSell, OrderType: types.OrderTypeLimit, Price: 55000, Quantity: 0.01}
+	stopLoss := types.ExecuteOrder{Symbol: "XBTUSD", Side: types.PurchaseTypeSell, OrderType: types.OrderTypeStopMarket, Price: 48000, StopPrice: 48000, Quantity: 0.01}
+
+	return entry, takeProfit, stopLoss
+}
+
+// krakenOCOLegs returns the open orders Kraken reports for the legs of krakenOCOOrders.
+func krakenOCOLegs() (KrakenOrder, KrakenOrder) {
+	takeProfit := KrakenOrder{
+		Status:      "open",
+		Description: KrakenOrderDescription{Pair: "XBTUSD", Type: "sell", OrderType: "limit", Price: "55000"},
+		Volume:      "0.01000000",
+	}
+	stopLoss := KrakenOrder{
+		Status:      "open",
+		Description: KrakenOrderDescription{Pair: "XBTUSD", Type: "sell", OrderType: "stop-loss", Price: "48000"},
+		Volume:      "0.01000000",
+	}
+
+	return takeProfit, stopLoss
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOCOOrder_PlacesLegsAfterMarketEntry() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	groupID, err := provider.PlaceOCOOrder(krakenOCOOrders())
+	suite.NoError(err)
+	suite.NotEmpty(groupID)
+	suite.Equal(1, provider.oco.ActiveGroups())
+
+	suite.Require().Len(mockClient.addOrderRequests, 3)
+	suite.Equal("market", mockClient.addOrderRequests[0].OrderType)
+	suite.Equal("limit", mockClient.addOrderRequests[1].OrderType)
+	suite.Equal("55000", mockClient.addOrderRequests[1].Price)
+	suite.Equal("stop-loss", mockClient.addOrderRequests[2].OrderType)
+	suite.Equal("48000", mockClient.addOrderRequests[2].Price)
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOCOOrder_CancelsRemainingLeg() {
+	tests := []struct {
+		name              string
+		filledLeg         string
+		expectedCancelled string
+	}{
+		{name: "take profit fills", filledLeg: "take profit", expectedCancelled: "STOP-LOSS-TX"},
+		{name: "stop loss fills", filledLeg: "stop loss", expectedCancelled: "TAKE-PROFIT-TX"},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			mockClient := newMockKrakenClient()
+			provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+			_, err := provider.PlaceOCOOrder(krakenOCOOrders())
+			suite.Require().NoError(err)
+
+			// Both legs resting: nothing to do
+			takeProfit, stopLoss := krakenOCOLegs()
+			mockClient.openOrders = map[string]KrakenOrder{"TAKE-PROFIT-TX": takeProfit, "STOP-LOSS-TX": stopLoss}
+			suite.NoError(provider.oco.CheckFills())
+			suite.Empty(mockClient.cancelledTxID)
+			suite.Equal(1, provider.oco.ActiveGroups())
+
+			// One leg filled and left the order book
+			if tc.filledLeg == "take profit" {
+				delete(mockClient.openOrders, "TAKE-PROFIT-TX")
+			} else {
+				delete(mockClient.openOrders, "STOP-LOSS-TX")
+			}
+
+			suite.NoError(provider.oco.CheckFills())
+			suite.Equal(tc.expectedCancelled, mockClient.cancelledTxID)
+			suite.Zero(provider.oco.ActiveGroups())
+		})
+	}
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOCOOrder_LimitEntryWaitsForFill() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	entry, takeProfit, stopLoss := krakenOCOOrders()
+	entry.OrderType = types.OrderTypeLimit
+	entry.Price = 50000
+
+	_, err := provider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+	suite.NoError(err)
+	suite.Len(mockClient.addOrderRequests, 1, "legs wait for the entry to fill")
+
+	mockClient.openOrders = map[string]KrakenOrder{"ENTRY-TX": {
+		Status:      "open",
+		Description: KrakenOrderDescription{Pair: "XBTUSD", Type: "buy", OrderType: "limit", Price: "50000"},
+		Volume:      "0.01000000",
+	}}
+	suite.NoError(provider.oco.CheckFills())
+	suite.Len(mockClient.addOrderRequests, 1)
+
+	mockClient.openOrders = map[string]KrakenOrder{}
+	suite.NoError(provider.oco.CheckFills())
+	suite.Len(mockClient.addOrderRequests, 3)
+	suite.Equal(1, provider.oco.ActiveGroups())
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOCOOrder_InvalidLegs() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	entry, takeProfit, stopLoss := krakenOCOOrders()
+	takeProfit.Side = types.PurchaseTypeBuy
+
+	_, err := provider.PlaceOCOOrder(entry, takeProfit, stopLoss)
+	suite.Error(err)
+	suite.Contains(err.Error(), "opposite side")
+	suite.Empty(mockClient.addOrderRequests)
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOrder_StopLimit_Success() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "XBTUSD",
+		Side:      types.PurchaseTypeSell,
+		OrderType: types.OrderTypeStopLimit,
+		Quantity:  0.5,
+		Price:     47900,
+		StopPrice: 48000,
+	})
+	suite.NoError(err)
+	suite.Require().Len(mockClient.addOrderRequests, 1)
+	suite.Equal("stop-loss-limit", mockClient.addOrderRequests[0].OrderType)
+	suite.Equal("48000", mockClient.addOrderRequests[0].Price)
+	suite.Equal("47900", mockClient.addOrderRequests[0].Price2)
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOrder_LimitSell_Success() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "ETHUSD",
+		Side:      types.PurchaseTypeSell,
+		OrderType: types.OrderTypeLimit,
+		Quantity:  1.123456789,
+		Price:     3500.5,
+	})
+	suite.NoError(err)
+	suite.Require().Len(mockClient.addOrderRequests, 1)
+	suite.Equal("sell", mockClient.addOrderRequests[0].Type)
+	suite.Equal("limit", mockClient.addOrderRequests[0].OrderType)
+	suite.Equal("1.12345678", mockClient.addOrderRequests[0].Volume)
+	suite.Equal("3500.5", mockClient.addOrderRequests[0].Price)
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOrder_TimeInForce() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:      "XBTUSD",
+		Side:        types.PurchaseTypeBuy,
+		OrderType:   types.OrderTypeLimit,
+		Quantity:    0.1,
+		Price:       50000,
+		TimeInForce: types.TimeInForceIOC,
+	})
+	suite.Require().NoError(err)
+
+	err = provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "XBTUSD",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeLimit,
+		Quantity:  0.1,
+		Price:     49000,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(mockClient.addOrderRequests, 2)
+	suite.Equal("IOC", mockClient.addOrderRequests[0].TimeInForce)
+	suite.Equal("", mockClient.addOrderRequests[1].TimeInForce)
+
+	// Kraken does not report the time in force, so the placed one is kept
+	mockClient.openOrders = map[string]KrakenOrder{
+		"OQCLML-BW3P3-BUCMWZ": {
+			Status:      "open",
+			Description: KrakenOrderDescription{Pair: "XBTUSD", Type: "buy", OrderType: "limit", Price: "50000"},
+			Volume:      "0.1",
+		},
+		"OOTHER": {
+			Status:      "open",
+			Description: KrakenOrderDescription{Pair: "XBTUSD", Type: "buy", OrderType: "limit", Price: "48000"},
+			Volume:      "0.1",
+		},
+	}
+
+	orders, err := provider.GetOpenOrders()
+	suite.Require().NoError(err)
+	suite.Require().Len(orders, 2)
+
+	for _, order := range orders {
+		if order.ID == "OQCLML-BW3P3-BUCMWZ" {
+			suite.Equal(types.TimeInForceIOC, order.TimeInForce)
+		} else {
+			suite.Equal(types.TimeInForceGTC, order.TimeInForce)
+		}
+	}
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOrder_RejectsFillOrKill() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:      "XBTUSD",
+		Side:        types.PurchaseTypeBuy,
+		OrderType:   types.OrderTypeLimit,
+		Quantity:    0.1,
+		Price:       50000,
+		TimeInForce: types.TimeInForceFOK,
+	})
+	suite.Require().Error(err)
+	suite.True(argoErrors.HasCode(err, argoErrors.ErrCodeInvalidParameter))
+	suite.Empty(mockClient.addOrderRequests)
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOrder_InvalidOrders() {
+	tests := []struct {
+		name          string
+		order         types.ExecuteOrder
+		expectedError string
+	}{
+		{
+			name:          "unsupported side",
+			order:         types.ExecuteOrder{Symbol: "XBTUSD", Side: "INVALID", OrderType: types.OrderTypeMarket, Quantity: 1},
+			expectedError: "unsupported order side",
+		},
+		{
+			name:          "unsupported order type",
+			order:         types.ExecuteOrder{Symbol: "XBTUSD", Side: types.PurchaseTypeBuy, OrderType: "STOP", Quantity: 1},
+			expectedError: "unsupported order type",
+		},
+		{
+			name:          "zero quantity",
+			order:         types.ExecuteOrder{Symbol: "XBTUSD", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 0},
+			expectedError: "must be greater than zero",
+		},
+		{
+			name:          "quantity below precision",
+			order:         types.ExecuteOrder{Symbol: "XBTUSD", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 0.000000001},
+			expectedError: "too small after rounding",
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			mockClient := newMockKrakenClient()
+			provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+			err := provider.PlaceOrder(tc.order)
+			suite.Error(err)
+			suite.Contains(err.Error(), tc.expectedError)
+			suite.Empty(mockClient.addOrderRequests)
+		})
+	}
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOrder_APIError() {
+	mockClient := newMockKrakenClient()
+	mockClient.addOrderErr = errors.New("EOrder:Insufficient funds")
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceOrder(types.ExecuteOrder{
+		Symbol:    "XBTUSD",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  1,
+	})
+	suite.Error(err)
+	suite.Contains(err.Error(), "failed to place order on Kraken")
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceMultipleOrders_FailsOnFirstError() {
+	mockClient := newMockKrakenClient()
+	mockClient.addOrderErr = errors.New("API error")
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	err := provider.PlaceMultipleOrders([]types.ExecuteOrder{
+		{Symbol: "XBTUSD", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 1},
+		{Symbol: "ETHUSD", Side: types.PurchaseTypeBuy, OrderType: types.OrderTypeMarket, Quantity: 1},
+	})
+	suite.Error(err)
+	suite.Len(mockClient.addOrderRequests, 1)
+}
+
+// GetPositions Tests
+
+func (suite *KrakenTradingTestSuite) TestGetPositions_Success() {
+	mockClient := newMockKrakenClient()
+	mockClient.balances = map[string]KrakenBalance{
+		"XXBT": {Balance: "2.0", HoldTrade: "0.5"},
+		"XETH": {Balance: "10.0", HoldTrade: "0"},
+		"ZUSD": {Balance: "0", HoldTrade: "0"}, // Should be filtered out
+	}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	positions, err := provider.GetPositions()
+	suite.NoError(err)
+	suite.Require().Len(positions, 2)
+
+	// Positions are sorted by asset name
+	suite.Equal("XETH", positions[0].Symbol)
+	suite.Equal(10.0, positions[0].TotalLongPositionQuantity)
+	suite.Equal("XXBT", positions[1].Symbol)
+	suite.Equal(2.0, positions[1].TotalLongPositionQuantity) // Held amounts are still owned
+}
+
+func (suite *KrakenTradingTestSuite) TestGetPositions_EmptyBalances() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	positions, err := provider.GetPositions()
+	suite.NoError(err)
+	suite.Empty(positions)
+}
+
+func (suite *KrakenTradingTestSuite) TestGetPositions_APIError() {
+	mockClient := newMockKrakenClient()
+	mockClient.balancesErr = errors.New("EAPI:Invalid key")
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	positions, err := provider.GetPositions()
+	suite.Error(err)
+	suite.Nil(positions)
+}
+
+func (suite *KrakenTradingTestSuite) TestGetPosition_NotFound() {
+	mockClient := newMockKrakenClient()
+	mockClient.balances = map[string]KrakenBalance{"XXBT": {Balance: "1", HoldTrade: "0"}}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	position, err := provider.GetPosition("XETH")
+	suite.NoError(err)
+	suite.Equal("XETH", position.Symbol)
+	suite.Equal(0.0, position.TotalLongPositionQuantity)
+}
+
+func (suite *KrakenTradingTestSuite) TestGetPosition_PairUsesBaseAssetBalance() {
+	mockClient := newMockKrakenClient()
+	mockClient.balances = map[string]KrakenBalance{
+		"XXBT": {Balance: "0.75", HoldTrade: "0"},
+		"ZUSD": {Balance: "1000", HoldTrade: "0"},
+	}
+	mockClient.pairNames = map[string]string{"XBTUSD": "XXBTZUSD"}
+	mockClient.assetPairs = map[string]KrakenAssetPair{"XBTUSD": {Altname: "XBTUSD", Base: "XXBT", Quote: "ZUSD"}}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	position, err := provider.GetPosition("XBTUSD")
+	suite.NoError(err)
+	suite.Equal("XBTUSD", position.Symbol)
+	suite.Equal(0.75, position.TotalLongPositionQuantity)
+
+	quantity, err := provider.GetMaxSellQuantity("XBTUSD")
+	suite.NoError(err)
+	suite.Equal(0.75, quantity)
+}
+
+// Order Management Tests
+
+func (suite *KrakenTradingTestSuite) TestGetOrderStatus() {
+	mockClient := newMockKrakenClient()
+	mockClient.queriedOrders = map[string]KrakenOrder{"OABC": {Status: "closed"}}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	status, err := provider.GetOrderStatus("OABC")
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusFilled, status)
+
+	status, err = provider.GetOrderStatus("OMISSING")
+	suite.Error(err)
+	suite.Equal(types.OrderStatusFailed, status)
+}
+
+func (suite *KrakenTradingTestSuite) TestGetOrderStatus_ByPlacedOrderID() {
+	mockClient := newMockKrakenClient()
+	mockClient.queriedOrders = map[string]KrakenOrder{"OQCLML-BW3P3-BUCMWZ": {Status: "closed"}}
+	mockClient.tradePages = []*KrakenTradesHistory{{
+		Trades: map[string]KrakenTrade{
+			"TFIRST":  {OrderTxID: "OQCLML-BW3P3-BUCMWZ", Pair: "XXBTZUSD", Time: 1700000000, Type: "buy", Price: "50000", Volume: "0.1"},
+			"TSECOND": {OrderTxID: "OOTHER", Pair: "XXBTZUSD", Time: 1700000100, Type: "sell", Price: "50100", Volume: "0.2"},
+		},
+		Count: 2,
+	}}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	orderID := "8d5e0c3a-2f4b-4a7e-9c1d-6b2a0f3e4d51"
+	suite.Require().NoError(provider.PlaceOrder(types.ExecuteOrder{
+		ID:        orderID,
+		Symbol:    "XBTUSD",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.1,
+	}))
+	suite.Require().Len(mockClient.addOrderRequests, 1)
+	suite.Equal(orderID, mockClient.addOrderRequests[0].ClientOrderID)
+
+	// The order is queried by the transaction ID Kraken assigned to it
+	status, err := provider.GetOrderStatus(orderID)
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusFilled, status)
+	suite.Equal([][]string{{"OQCLML-BW3P3-BUCMWZ"}}, mockClient.queriedTxIDs)
+
+	// Its trades carry the ID it was placed with
+	trades, err := provider.GetTrades(types.TradeFilter{})
+	suite.Require().NoError(err)
+	suite.Require().Len(trades, 2)
+	suite.Equal(orderID, trades[0].Order.OrderID)
+	suite.Equal("OOTHER", trades[1].Order.OrderID)
+}
+
+func (suite *KrakenTradingTestSuite) TestPlaceOrder_SkipsClientOrderIDKrakenRejects() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	suite.Require().NoError(provider.PlaceOrder(types.ExecuteOrder{
+		ID:        "an order ID too long for Kraken",
+		Symbol:    "XBTUSD",
+		Side:      types.PurchaseTypeBuy,
+		OrderType: types.OrderTypeMarket,
+		Quantity:  0.1,
+	}))
+	suite.Require().Len(mockClient.addOrderRequests, 1)
+	suite.Empty(mockClient.addOrderRequests[0].ClientOrderID)
+
+	// The order is still known by its ID
+	mockClient.queriedOrders = map[string]KrakenOrder{"OQCLML-BW3P3-BUCMWZ": {Status: "open"}}
+
+	status, err := provider.GetOrderStatus("an order ID too long for Kraken")
+	suite.NoError(err)
+	suite.Equal(types.OrderStatusPending, status)
+}
+
+func (suite *KrakenTradingTestSuite) TestGetOpenOrders_SortedAndConverted() {
+	mockClient := newMockKrakenClient()
+	mockClient.openOrders = map[string]KrakenOrder{
+		"OSECOND": {
+			Status:      "open",
+			OpenTime:    1700000100.5,
+			Description: KrakenOrderDescription{Pair: "ETHUSD", Type: "sell", OrderType: "limit", Price: "2000"},
+			Volume:      "1.5",
+		},
+		"OFIRST": {
+			Status:      "open",
+			OpenTime:    1700000000.1,
+			Description: KrakenOrderDescription{Pair: "XBTUSD", Type: "buy", OrderType: "market", Price: "0"},
+			Volume:      "0.1",
+		},
+		"OBROKEN": {
+			Status:      "open",
+			OpenTime:    1700000050,
+			Description: KrakenOrderDescription{Pair: "XBTUSD", Type: "hold", OrderType: "limit"},
+		},
+	}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	orders, err := provider.GetOpenOrders()
+	suite.NoError(err)
+	suite.Require().Len(orders, 2)
+	suite.Equal("OFIRST", orders[0].ID)
+	suite.Equal(types.PurchaseTypeBuy, orders[0].Side)
+	suite.Equal(types.OrderTypeMarket, orders[0].OrderType)
+	suite.Equal("OSECOND", orders[1].ID)
+	suite.Equal(types.OrderTypeLimit, orders[1].OrderType)
+	suite.Equal(2000.0, orders[1].Price)
+	suite.Equal(1.5, orders[1].Quantity)
+}
+
+func (suite *KrakenTradingTestSuite) TestCancelOrders() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	suite.NoError(provider.CancelOrder("OABC"))
+	suite.Equal("OABC", mockClient.cancelledTxID)
+
+	suite.NoError(provider.CancelAllOrders())
+	suite.True(mockClient.cancelAllCalled)
+
+	mockClient.cancelErr = errors.New("EOrder:Unknown order")
+	suite.Error(provider.CancelOrder("OMISSING"))
+}
+
+func (suite *KrakenTradingTestSuite) TestGetTrades_PaginatesAndFilters() {
+	mockClient := newMockKrakenClient()
+	mockClient.tradePages = []*KrakenTradesHistory{
+		{
+			Count: 52,
+			Trades: map[string]KrakenTrade{
+				"T3": {OrderTxID: "O3", Pair: "XXBTZUSD", Time: 1700000300, Type: "sell", Price: "36000", Fee: "1.5", Volume: "0.1"},
+				"T2": {OrderTxID: "O2", Pair: "XETHZUSD", Time: 1700000200, Type: "buy", Price: "2000", Fee: "0.5", Volume: "1"},
+			},
+		},
+		{
+			Count: 52,
+			Trades: map[string]KrakenTrade{
+				"T1": {OrderTxID: "O1", Pair: "XXBTZUSD", Time: 1700000100.25, Type: "buy", Price: "35000", Fee: "1", Volume: "0.1"},
+			},
+		},
+	}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	trades, err := provider.GetTrades(types.TradeFilter{Symbol: "XXBTZUSD"})
+	suite.NoError(err)
+	suite.Equal([]int{0, 50}, mockClient.tradeOffsets)
+	suite.Require().Len(trades, 2)
+	suite.Equal("O1", trades[0].Order.OrderID)
+	suite.Equal(types.PurchaseTypeBuy, trades[0].Order.Side)
+	suite.Equal(time.Unix(1700000100, 250000000), trades[0].ExecutedAt)
+	suite.Equal("O3", trades[1].Order.OrderID)
+	suite.Equal(1.5, trades[1].Fee)
+
+	// A limit satisfied by the first page stops paging
+	mockClient.tradeOffsets = nil
+	trades, err = provider.GetTrades(types.TradeFilter{Limit: 1})
+	suite.NoError(err)
+	suite.Equal([]int{0}, mockClient.tradeOffsets)
+	suite.Require().Len(trades, 1)
+	suite.Equal("O3", trades[0].Order.OrderID)
+}
+
+// Account Tests
+
+func (suite *KrakenTradingTestSuite) TestGetAccountInfoAndMaxQuantities() {
+	mockClient := newMockKrakenClient()
+	mockClient.balances = map[string]KrakenBalance{
+		"ZUSD": {Balance: "1000", HoldTrade: "200"},
+		"USDT": {Balance: "26", HoldTrade: "0"},
+		"XXBT": {Balance: "0.5", HoldTrade: "0"},
+	}
+	mockClient.takerFee = 0.004
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	info, err := provider.GetAccountInfo()
+	suite.NoError(err)
+	suite.Equal(1026.0, info.Balance)
+	suite.Equal(826.0, info.BuyingPower)
+
+	maxBuy, err := provider.GetMaxBuyQuantity("XBTUSD", 100)
+	suite.NoError(err)
+	suite.InDelta(826.0/1.004/100, maxBuy, 1e-9)
+
+	mockClient.takerFeeErr = errors.New("EGeneral:Permission denied")
+	maxBuy, err = provider.GetMaxBuyQuantity("XBTUSD", 100)
+	suite.NoError(err)
+	suite.InDelta(826.0/(1+krakenDefaultTakerFee)/100, maxBuy, 1e-9)
+
+	_, err = provider.GetMaxBuyQuantity("XBTUSD", 0)
+	suite.Error(err)
+
+	maxSell, err := provider.GetMaxSellQuantity("XXBT")
+	suite.NoError(err)
+	suite.Equal(0.5, maxSell)
+}
+
+func (suite *KrakenTradingTestSuite) TestCalculatePositionSize_FloorsToPrecision() {
+	mockClient := newMockKrakenClient()
+	mockClient.balances = map[string]KrakenBalance{
+		"ZUSD": {Balance: "1000", HoldTrade: "0"},
+	}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	// Risking 1% of 1000 on a 3 stop distance sizes 3.3333... units
+	quantity, err := provider.CalculatePositionSize("XBTUSD", 100, 97, 0.01)
+	suite.Require().NoError(err)
+	suite.Equal(3.33333333, quantity)
+
+	provider.decimalPrecision = 2
+	quantity, err = provider.CalculatePositionSize("XBTUSD", 100, 97, 0.01)
+	suite.Require().NoError(err)
+	suite.Equal(3.33, quantity)
+}
+
+func (suite *KrakenTradingTestSuite) TestGetPrices() {
+	mockClient := newMockKrakenClient()
+	mockClient.tickers = map[string]KrakenTicker{"XXBTZUSD": {LastTrade: []string{"36500.1", "0.01"}}}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	prices, err := provider.GetPrices([]string{"XBTUSD"})
+	suite.NoError(err)
+	suite.Equal(map[string]float64{"XBTUSD": 36500.1}, prices)
+
+	mockClient.tickers["XETHZUSD"] = KrakenTicker{LastTrade: []string{"2000", "1"}}
+	mockClient.tickers["BROKEN"] = KrakenTicker{LastTrade: nil}
+	prices, err = provider.GetPrices(nil)
+	suite.NoError(err)
+	suite.Equal(map[string]float64{"XXBTZUSD": 36500.1, "XETHZUSD": 2000}, prices)
+}
+
+func (suite *KrakenTradingTestSuite) TestGetPrices_MultiplePairsKeyedByRequestedName() {
+	mockClient := newMockKrakenClient()
+	mockClient.tickers = map[string]KrakenTicker{
+		"XXBTZUSD": {LastTrade: []string{"36500.1", "0.01"}},
+		"XETHZUSD": {LastTrade: []string{"2000", "1"}},
+	}
+	mockClient.pairNames = map[string]string{"XBTUSD": "XXBTZUSD", "ETHUSD": "XETHZUSD", "DOGEUSD": "XDGUSD"}
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	prices, err := provider.GetPrices([]string{"XBTUSD", "ETHUSD", "DOGEUSD"})
+	suite.NoError(err)
+	suite.Equal(map[string]float64{"XBTUSD": 36500.1, "ETHUSD": 2000}, prices)
+	suite.Equal([][]string{{"XBTUSD"}, {"ETHUSD"}, {"DOGEUSD"}}, mockClient.tickerRequests)
+}
+
+func (suite *KrakenTradingTestSuite) TestCheckConnection_EmitsStatus() {
+	mockClient := newMockKrakenClient()
+	provider := newKrakenTradingSystemProviderWithClient(mockClient)
+
+	var statuses []types.ProviderConnectionStatus
+	provider.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		statuses = append(statuses, status)
+	})
+
+	suite.NoError(provider.CheckConnection(context.Background()))
+
+	mockClient.balancesErr = errors.New("connection refused")
+	suite.Error(provider.CheckConnection(context.Background()))
+
+	suite.Equal([]types.ProviderConnectionStatus{types.ProviderStatusConnected, types.ProviderStatusDisconnected}, statuses)
+}
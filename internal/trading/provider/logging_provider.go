@@ -50,6 +50,40 @@ func (p *LoggingTradingSystemProvider) PlaceMultipleOrders(orders []types.Execut
 	return err
 }
 
+func (p *LoggingTradingSystemProvider) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	p.log.Info("strategy wants to call api",
+		zap.String("api", "PlaceOCOOrder"),
+		zap.String("symbol", entry.Symbol),
+		zap.Any("side", entry.Side),
+		zap.Float64("take_profit", takeProfit.Price),
+		zap.Float64("stop_loss", stopLoss.StopPrice),
+		zap.Float64("quantity", entry.Quantity),
+	)
+	groupID, err := p.inner.PlaceOCOOrder(entry, takeProfit, stopLoss)
+	if err != nil {
+		p.log.Warn("api call failed", zap.String("api", "PlaceOCOOrder"), zap.Error(err))
+	}
+
+	return groupID, err
+}
+
+func (p *LoggingTradingSystemProvider) PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error {
+	p.log.Info("strategy wants to call api",
+		zap.String("api", "PlaceBracketOrder"),
+		zap.String("symbol", entry.Symbol),
+		zap.Any("side", entry.Side),
+		zap.Float64("take_profit", takeProfit),
+		zap.Float64("stop_loss", stopLoss),
+		zap.Float64("quantity", entry.Quantity),
+	)
+	err := p.inner.PlaceBracketOrder(entry, stopLoss, takeProfit)
+	if err != nil {
+		p.log.Warn("api call failed", zap.String("api", "PlaceBracketOrder"), zap.Error(err))
+	}
+
+	return err
+}
+
 func (p *LoggingTradingSystemProvider) GetPositions() ([]types.Position, error) {
 	p.log.Info("strategy wants to call api", zap.String("api", "GetPositions"))
 
@@ -88,6 +122,19 @@ func (p *LoggingTradingSystemProvider) CancelAllOrders() error {
 	return err
 }
 
+func (p *LoggingTradingSystemProvider) CancelOrdersForSymbol(symbol string) error {
+	p.log.Info("strategy wants to call api",
+		zap.String("api", "CancelOrdersForSymbol"),
+		zap.String("symbol", symbol),
+	)
+	err := p.inner.CancelOrdersForSymbol(symbol)
+	if err != nil {
+		p.log.Warn("api call failed", zap.String("api", "CancelOrdersForSymbol"), zap.Error(err))
+	}
+
+	return err
+}
+
 func (p *LoggingTradingSystemProvider) GetOrderStatus(orderID string) (types.OrderStatus, error) {
 	p.log.Info("strategy wants to call api",
 		zap.String("api", "GetOrderStatus"),
@@ -133,6 +180,15 @@ func (p *LoggingTradingSystemProvider) GetTrades(filter types.TradeFilter) ([]ty
 	return p.inner.GetTrades(filter)
 }
 
+func (p *LoggingTradingSystemProvider) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	p.log.Info("strategy wants to call api",
+		zap.String("api", "GetTradesPage"),
+		zap.Any("filter", filter),
+	)
+
+	return p.inner.GetTradesPage(filter)
+}
+
 func (p *LoggingTradingSystemProvider) GetMaxBuyQuantity(symbol string, price float64) (float64, error) {
 	p.log.Info("strategy wants to call api",
 		zap.String("api", "GetMaxBuyQuantity"),
@@ -152,6 +208,18 @@ func (p *LoggingTradingSystemProvider) GetMaxSellQuantity(symbol string) (float6
 	return p.inner.GetMaxSellQuantity(symbol)
 }
 
+func (p *LoggingTradingSystemProvider) CalculatePositionSize(symbol string, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	p.log.Info("strategy wants to call api",
+		zap.String("api", "CalculatePositionSize"),
+		zap.String("symbol", symbol),
+		zap.Float64("entryPrice", entryPrice),
+		zap.Float64("stopPrice", stopPrice),
+		zap.Float64("riskFraction", riskFraction),
+	)
+
+	return p.inner.CalculatePositionSize(symbol, entryPrice, stopPrice, riskFraction)
+}
+
 func (p *LoggingTradingSystemProvider) CheckConnection(ctx context.Context) error {
 	return p.inner.CheckConnection(ctx)
 }
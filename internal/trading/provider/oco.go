@@ -0,0 +1,292 @@
+package tradingprovider
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DefaultOCOPollInterval is how often emulated OCO groups are checked for fills.
+const DefaultOCOPollInterval = 5 * time.Second
+
+// ValidateOCOOrder checks that takeProfit and stopLoss can form an OCO group
+// closing the position opened by entry: all three orders are on the same symbol,
+// both legs are on the opposite side of the entry, the take profit is a limit
+// order and the stop loss is a stop order with a trigger price.
+func ValidateOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) error {
+	if takeProfit.Symbol != entry.Symbol || stopLoss.Symbol != entry.Symbol {
+		return errors.New(errors.ErrCodeInvalidParameter, "OCO legs must be on the same symbol as the entry order")
+	}
+
+	if takeProfit.Side == entry.Side || stopLoss.Side == entry.Side {
+		return errors.New(errors.ErrCodeInvalidParameter, "OCO legs must be on the opposite side of the entry order")
+	}
+
+	if takeProfit.OrderType != types.OrderTypeLimit {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "OCO take profit must be a limit order, got %s", takeProfit.OrderType)
+	}
+
+	if !stopLoss.IsStopOrder() {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "OCO stop loss must be a stop order, got %s", stopLoss.OrderType)
+	}
+
+	if stopLoss.StopPrice <= 0 {
+		return errors.Newf(errors.ErrCodeInvalidParameter, "OCO stop loss stop price must be greater than zero: %f", stopLoss.StopPrice)
+	}
+
+	if takeProfit.Quantity <= 0 || stopLoss.Quantity <= 0 {
+		return errors.New(errors.ErrCodeInvalidParameter, "OCO leg quantity must be greater than zero")
+	}
+
+	return nil
+}
+
+// FindMatchingOpenOrder returns the index of the first exchange order in
+// openOrders that is not yet matched and corresponds to order, or -1.
+// Exchanges assign their own order IDs, so orders also match on symbol, side,
+// type, quantity and their limit or stop price.
+func FindMatchingOpenOrder(order types.ExecuteOrder, openOrders []types.ExecuteOrder, matched []bool) int {
+	for i, open := range openOrders {
+		if matched[i] {
+			continue
+		}
+
+		if open.ID == order.ID || sameOrderParameters(order, open) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// sameOrderParameters reports whether two orders were placed with the same parameters.
+func sameOrderParameters(a, b types.ExecuteOrder) bool {
+	if a.Symbol != b.Symbol || a.Side != b.Side || a.OrderType != b.OrderType || a.Quantity != b.Quantity {
+		return false
+	}
+
+	if a.IsStopOrder() && a.StopPrice != b.StopPrice {
+		return false
+	}
+
+	// Market and stop-market orders carry no limit price
+	return a.OrderType == types.OrderTypeMarket || a.OrderType == types.OrderTypeStopMarket || a.Price == b.Price
+}
+
+// OCOEmulator provides PlaceOCOOrder for providers without native OCO support.
+// The legs are placed as two independent orders once the entry has filled and
+// the group is then watched: as soon as one leg is no longer open on the
+// exchange, the other one is cancelled.
+//
+// Fills are detected by polling the provider's open orders. With a positive poll
+// interval a background watcher runs while groups are active; otherwise the
+// owner calls CheckFills itself.
+type OCOEmulator struct {
+	provider     TradingSystemProvider
+	pollInterval time.Duration
+
+	mu       sync.Mutex
+	groups   map[string]*emulatedOCOGroup
+	watching bool
+}
+
+// emulatedOCOGroup is an OCO group waiting for its entry or for one of its legs to fill.
+type emulatedOCOGroup struct {
+	entry       types.ExecuteOrder
+	entryFilled bool
+	takeProfit  types.ExecuteOrder
+	stopLoss    types.ExecuteOrder
+}
+
+// NewOCOEmulator creates an emulator placing orders through provider.
+func NewOCOEmulator(provider TradingSystemProvider, pollInterval time.Duration) *OCOEmulator {
+	return &OCOEmulator{
+		provider:     provider,
+		pollInterval: pollInterval,
+		mu:           sync.Mutex{},
+		groups:       map[string]*emulatedOCOGroup{},
+		watching:     false,
+	}
+}
+
+// PlaceOCOOrder places entry and registers the take profit and stop loss legs.
+// Market entries fill immediately, so their legs are placed right away; the legs
+// of a limit entry are placed once the entry is no longer open.
+func (e *OCOEmulator) PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (string, error) {
+	if err := ValidateOCOOrder(entry, takeProfit, stopLoss); err != nil {
+		return "", err
+	}
+
+	if err := e.provider.PlaceOrder(entry); err != nil {
+		return "", err
+	}
+
+	group := &emulatedOCOGroup{
+		entry:       entry,
+		entryFilled: entry.OrderType == types.OrderTypeMarket,
+		takeProfit:  takeProfit,
+		stopLoss:    stopLoss,
+	}
+
+	if group.entryFilled {
+		if err := e.placeLegs(group); err != nil {
+			return "", err
+		}
+	}
+
+	groupID := uuid.NewString()
+
+	e.mu.Lock()
+	e.groups[groupID] = group
+	e.mu.Unlock()
+
+	e.startWatching()
+
+	return groupID, nil
+}
+
+// ActiveGroups returns the number of OCO groups still being watched.
+func (e *OCOEmulator) ActiveGroups() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return len(e.groups)
+}
+
+// CheckFills compares the active groups with the exchange's open orders. Legs are
+// placed for entries that have filled, and once one leg of a group has left the
+// order book the other leg is cancelled and the group is done.
+func (e *OCOEmulator) CheckFills() error {
+	openOrders, err := e.provider.GetOpenOrders()
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	matched := make([]bool, len(openOrders))
+
+	for groupID, group := range e.groups {
+		if !group.entryFilled {
+			if index := FindMatchingOpenOrder(group.entry, openOrders, matched); index >= 0 {
+				matched[index] = true
+
+				continue
+			}
+
+			group.entryFilled = true
+			if err := e.placeLegs(group); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		takeProfit := FindMatchingOpenOrder(group.takeProfit, openOrders, matched)
+		if takeProfit >= 0 {
+			matched[takeProfit] = true
+		}
+
+		stopLoss := FindMatchingOpenOrder(group.stopLoss, openOrders, matched)
+		if stopLoss >= 0 {
+			matched[stopLoss] = true
+		}
+
+		switch {
+		case takeProfit >= 0 && stopLoss >= 0:
+			continue
+		case takeProfit >= 0:
+			err = e.provider.CancelOrder(openOrders[takeProfit].ID)
+		case stopLoss >= 0:
+			err = e.provider.CancelOrder(openOrders[stopLoss].ID)
+		}
+
+		if err != nil {
+			return errors.Wrap(errors.ErrCodeOrderFailed, "failed to cancel the remaining OCO leg", err)
+		}
+
+		delete(e.groups, groupID)
+	}
+
+	return nil
+}
+
+// placeLegs places the take profit and stop loss orders of a group.
+func (e *OCOEmulator) placeLegs(group *emulatedOCOGroup) error {
+	if err := e.provider.PlaceOrder(group.takeProfit); err != nil {
+		return err
+	}
+
+	if err := e.provider.PlaceOrder(group.stopLoss); err != nil {
+		// Do not leave a take profit without its stop loss
+		e.cancelOpenOrder(group.takeProfit)
+
+		return err
+	}
+
+	return nil
+}
+
+// cancelOpenOrder cancels the exchange order matching order, if it is still open.
+// It is best effort: failures are only logged.
+func (e *OCOEmulator) cancelOpenOrder(order types.ExecuteOrder) {
+	openOrders, err := e.provider.GetOpenOrders()
+	if err != nil {
+		debugLog.Warn("failed to list open orders to cancel OCO leg", zap.Error(err))
+
+		return
+	}
+
+	index := FindMatchingOpenOrder(order, openOrders, make([]bool, len(openOrders)))
+	if index < 0 {
+		return
+	}
+
+	if err := e.provider.CancelOrder(openOrders[index].ID); err != nil {
+		debugLog.Warn("failed to cancel OCO leg", zap.String("order_id", openOrders[index].ID), zap.Error(err))
+	}
+}
+
+// startWatching starts the background watcher if it is enabled and not running.
+func (e *OCOEmulator) startWatching() {
+	if e.pollInterval <= 0 {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.watching {
+		return
+	}
+
+	e.watching = true
+
+	go e.watch()
+}
+
+// watch polls for fills until no group is left.
+func (e *OCOEmulator) watch() {
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := e.CheckFills(); err != nil {
+			debugLog.Warn("failed to check OCO fills", zap.Error(err))
+		}
+
+		e.mu.Lock()
+		if len(e.groups) == 0 {
+			e.watching = false
+			e.mu.Unlock()
+
+			return
+		}
+		e.mu.Unlock()
+	}
+}
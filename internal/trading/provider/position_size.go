@@ -0,0 +1,36 @@
+package tradingprovider
+
+import (
+	"math"
+
+	"github.com/rxtech-lab/argo-trading/pkg/errors"
+)
+
+// RiskPositionSize returns the quantity at which a stop out at stopPrice loses
+// riskFraction of equity: equity*riskFraction / |entryPrice-stopPrice|. The stop
+// may be on either side of the entry, so it works for longs and shorts alike.
+// Providers round the result to their own quantity precision.
+func RiskPositionSize(equity, entryPrice, stopPrice, riskFraction float64) (float64, error) {
+	if entryPrice <= 0 {
+		return 0, errors.Newf(errors.ErrCodeInvalidParameter, "entry price must be greater than zero: %f", entryPrice)
+	}
+
+	if stopPrice <= 0 {
+		return 0, errors.Newf(errors.ErrCodeInvalidParameter, "stop price must be greater than zero: %f", stopPrice)
+	}
+
+	if riskFraction <= 0 || riskFraction > 1 {
+		return 0, errors.Newf(errors.ErrCodeInvalidParameter, "risk fraction must be in (0, 1]: %f", riskFraction)
+	}
+
+	riskPerShare := math.Abs(entryPrice - stopPrice)
+	if riskPerShare == 0 {
+		return 0, errors.New(errors.ErrCodeInvalidParameter, "stop price must differ from the entry price")
+	}
+
+	if equity <= 0 {
+		return 0, nil
+	}
+
+	return equity * riskFraction / riskPerShare, nil
+}
@@ -0,0 +1,78 @@
+package tradingprovider
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a request-weight rate limiter. It holds up to capacity tokens,
+// one token is added every tokenInterval, and a request consumes as many tokens
+// as its weight. Callers block until enough tokens are available.
+type tokenBucket struct {
+	mu            sync.Mutex
+	capacity      float64
+	tokens        float64
+	tokenInterval time.Duration
+	last          time.Time
+	now           func() time.Time
+	sleep         sleepFunc
+}
+
+// newTokenBucket creates a full bucket that refills perMinute tokens per minute.
+// now and sleep are injectable so tests can drive the limiter with a fake clock.
+func newTokenBucket(perMinute int, now func() time.Time, sleep sleepFunc) *tokenBucket {
+	return &tokenBucket{
+		mu:            sync.Mutex{},
+		capacity:      float64(perMinute),
+		tokens:        float64(perMinute),
+		tokenInterval: time.Minute / time.Duration(perMinute),
+		last:          now(),
+		now:           now,
+		sleep:         sleep,
+	}
+}
+
+// Wait blocks until weight tokens are available and consumes them. Weights above
+// the bucket capacity are capped so a single heavy request cannot block forever.
+// It returns the context's error when ctx is cancelled while waiting.
+func (b *tokenBucket) Wait(ctx context.Context, weight int) error {
+	cost := math.Min(float64(weight), b.capacity)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		b.mu.Lock()
+		b.refill()
+
+		if b.tokens >= cost {
+			b.tokens -= cost
+			b.mu.Unlock()
+
+			return nil
+		}
+
+		wait := time.Duration(math.Ceil((cost - b.tokens) * float64(b.tokenInterval)))
+		b.mu.Unlock()
+
+		if err := b.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// refill adds the tokens earned since the last refill. Callers must hold mu.
+func (b *tokenBucket) refill() {
+	now := b.now()
+
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens = math.Min(b.capacity, b.tokens+float64(elapsed)/float64(b.tokenInterval))
+	b.last = now
+}
@@ -0,0 +1,89 @@
+package tradingprovider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeClock is a manually advanced clock. Sleeping advances the clock instead of waiting.
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+
+	return nil
+}
+
+type RateLimiterTestSuite struct {
+	suite.Suite
+}
+
+func TestRateLimiterSuite(t *testing.T) {
+	suite.Run(t, new(RateLimiterTestSuite))
+}
+
+func (suite *RateLimiterTestSuite) TestBurstThenRefill() {
+	clock := newFakeClock()
+	bucket := newTokenBucket(60, clock.Now, clock.Sleep)
+
+	// A full bucket serves its capacity without waiting
+	suite.NoError(bucket.Wait(context.Background(), 60))
+	suite.Empty(clock.sleeps)
+
+	// One token is added every second
+	suite.NoError(bucket.Wait(context.Background(), 3))
+	suite.Equal([]time.Duration{3 * time.Second}, clock.sleeps)
+}
+
+func (suite *RateLimiterTestSuite) TestIdleTimeRefillsUpToCapacity() {
+	clock := newFakeClock()
+	bucket := newTokenBucket(10, clock.Now, clock.Sleep)
+
+	suite.NoError(bucket.Wait(context.Background(), 10))
+	clock.now = clock.now.Add(time.Hour)
+
+	suite.NoError(bucket.Wait(context.Background(), 10))
+	suite.Empty(clock.sleeps)
+
+	suite.NoError(bucket.Wait(context.Background(), 1))
+	suite.Equal([]time.Duration{6 * time.Second}, clock.sleeps)
+}
+
+func (suite *RateLimiterTestSuite) TestWeightAboveCapacityIsCapped() {
+	clock := newFakeClock()
+	bucket := newTokenBucket(5, clock.Now, clock.Sleep)
+
+	suite.NoError(bucket.Wait(context.Background(), 80))
+	suite.Empty(clock.sleeps)
+}
+
+func (suite *RateLimiterTestSuite) TestCancelledContextStopsWaiting() {
+	clock := newFakeClock()
+	bucket := newTokenBucket(1, clock.Now, clock.Sleep)
+	suite.NoError(bucket.Wait(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.ErrorIs(bucket.Wait(ctx, 1), context.Canceled)
+	suite.Empty(clock.sleeps)
+}
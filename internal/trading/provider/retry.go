@@ -0,0 +1,98 @@
+package tradingprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the number of attempts, including the first call,
+	// made for a retryable request when no policy is configured.
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryBaseDelay is the wait before the first retry. Each further retry doubles it.
+	DefaultRetryBaseDelay = 200 * time.Millisecond
+	// DefaultRetryMaxDelay caps the wait between two attempts.
+	DefaultRetryMaxDelay = 5 * time.Second
+)
+
+// RetryPolicy controls how failed broker requests are retried with exponential backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first call.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between two attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+	}
+}
+
+// backoff returns the wait before the given retry, where 1 is the first retry.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+// sleepFunc waits for the given duration, returning early with the context's
+// error when it is cancelled.
+type sleepFunc func(ctx context.Context, d time.Duration) error
+
+// sleepContext is the sleepFunc used outside of tests.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryWithBackoff calls fn until it succeeds, fails with an error isRetryable
+// rejects, or the policy's attempts are used up. fn receives the attempt number
+// starting at 1. A cancelled context aborts the retries and is reported along
+// with the last error.
+func retryWithBackoff(ctx context.Context, policy RetryPolicy, sleep sleepFunc, isRetryable func(error) bool, fn func(attempt int) error) error {
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err == nil {
+				return ctxErr
+			}
+
+			return fmt.Errorf("%w (retry aborted: %w)", err, ctxErr)
+		}
+
+		err = fn(attempt)
+		if err == nil || attempt >= policy.MaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		debugLog.Warn("retrying failed request",
+			zap.Int("attempt", attempt),
+			zap.Int("maxAttempts", policy.MaxAttempts),
+			zap.Error(err),
+		)
+
+		if sleepErr := sleep(ctx, policy.backoff(attempt)); sleepErr != nil {
+			return fmt.Errorf("%w (retry aborted: %w)", err, sleepErr)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package tradingprovider
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	"github.com/rxtech-lab/argo-trading/internal/types"
 	"github.com/rxtech-lab/argo-trading/pkg/strategy"
@@ -24,6 +25,18 @@ type TradingSystemProvider interface {
 	CancelOrder(orderID string) error
 	// CancelAllOrders cancels all orders
 	CancelAllOrders() error
+	// CancelOrdersForSymbol cancels the open orders of a symbol, leaving the
+	// orders of other symbols open
+	CancelOrdersForSymbol(symbol string) error
+	// PlaceOCOOrder places entry together with a take profit and a stop loss that
+	// cancel each other: once one leg fills, the other is cancelled. Both legs close
+	// the position opened by entry. Returns the ID of the OCO group.
+	PlaceOCOOrder(entry, takeProfit, stopLoss types.ExecuteOrder) (groupID string, err error)
+	// PlaceBracketOrder places entry and, once it fills, protects the filled
+	// quantity with a take profit limit order at takeProfit and a stop loss at
+	// stopLoss that cancel each other. It returns an error if the stop loss or take
+	// profit is not on the protective side of the entry price.
+	PlaceBracketOrder(entry types.ExecuteOrder, stopLoss, takeProfit float64) error
 	// GetOrderStatus returns the status of an order
 	GetOrderStatus(orderID string) (types.OrderStatus, error)
 	// GetAccountInfo returns the current account state including balance, equity, and P&L
@@ -40,12 +53,21 @@ type TradingSystemProvider interface {
 	GetOpenOrders() ([]types.ExecuteOrder, error)
 	// GetTrades returns executed trades with optional filtering
 	GetTrades(filter types.TradeFilter) ([]types.Trade, error)
+	// GetTradesPage returns one page of at most filter.Limit executed trades starting
+	// after filter.Cursor, with the cursor of the next page, so long histories can be
+	// read without loading every trade at once.
+	GetTradesPage(filter types.TradeFilter) (types.TradePage, error)
 	// GetMaxBuyQuantity returns the maximum quantity that can be bought at the given price.
 	// It takes into account the current balance and commission fees.
 	GetMaxBuyQuantity(symbol string, price float64) (float64, error)
 	// GetMaxSellQuantity returns the maximum quantity that can be sold for a symbol.
 	// This is the total long position quantity for the symbol.
 	GetMaxSellQuantity(symbol string) (float64, error)
+	// CalculatePositionSize returns the quantity to trade so that a stop out at
+	// stopPrice loses riskFraction of the account equity, for example 0.01 to risk
+	// 1%. The quantity is rounded down like GetMaxBuyQuantity. It returns an error
+	// if stopPrice equals entryPrice.
+	CalculatePositionSize(symbol string, entryPrice, stopPrice, riskFraction float64) (float64, error)
 	// CheckConnection verifies if the trading provider is connected by performing a health check.
 	// Returns nil if connected, error otherwise.
 	CheckConnection(ctx context.Context) error
@@ -53,11 +75,23 @@ type TradingSystemProvider interface {
 	SetOnStatusChange(callback OnStatusChange)
 }
 
+// OrderUpdateStreamer is implemented by providers that push order updates as
+// they happen instead of having their order status polled.
+type OrderUpdateStreamer interface {
+	// StreamOrderUpdates yields the updates of the account's orders until ctx is
+	// cancelled. Connection problems are yielded as errors; the stream ends when
+	// the provider gives up on it.
+	StreamOrderUpdates(ctx context.Context) iter.Seq2[types.OrderUpdate, error]
+}
+
 type ProviderType string
 
 const (
 	ProviderBinancePaper ProviderType = "binance-paper"
 	ProviderBinanceLive  ProviderType = "binance-live"
+	ProviderKrakenLive   ProviderType = "kraken-live"
+	ProviderAlpacaPaper  ProviderType = "alpaca-paper"
+	ProviderAlpacaLive   ProviderType = "alpaca-live"
 )
 
 type ProviderInfo struct {
@@ -80,6 +114,24 @@ var providerRegistry = map[ProviderType]ProviderInfo{
 		Description:    "Binance live environment for real-funds cryptocurrency trading",
 		IsPaperTrading: false,
 	},
+	ProviderKrakenLive: {
+		Name:           string(ProviderKrakenLive),
+		DisplayName:    "Kraken Live",
+		Description:    "Kraken spot exchange for real-funds cryptocurrency trading",
+		IsPaperTrading: false,
+	},
+	ProviderAlpacaPaper: {
+		Name:           string(ProviderAlpacaPaper),
+		DisplayName:    "Alpaca Paper",
+		Description:    "Alpaca paper trading for US equities without real funds",
+		IsPaperTrading: true,
+	},
+	ProviderAlpacaLive: {
+		Name:           string(ProviderAlpacaLive),
+		DisplayName:    "Alpaca Live",
+		Description:    "Alpaca live brokerage for real-funds US equities trading",
+		IsPaperTrading: false,
+	},
 }
 
 func GetSupportedProviders() []string {
@@ -106,10 +158,28 @@ func GetProviderConfigSchema(providerName string) (string, error) {
 	switch ProviderType(providerName) {
 	case ProviderBinancePaper, ProviderBinanceLive:
 		return strategy.ToJSONSchema(BinanceProviderConfig{
+			ApiKey:             "",
+			SecretKey:          "",
+			BaseURL:            "",
+			RetryMaxAttempts:   0,
+			RetryBaseDelayMs:   0,
+			RetryMaxDelayMs:    0,
+			RateLimitPerMinute: 0,
+		})
+	case ProviderKrakenLive:
+		return strategy.ToJSONSchema(KrakenProviderConfig{
 			ApiKey:    "",
 			SecretKey: "",
 			BaseURL:   "",
 		})
+	case ProviderAlpacaPaper, ProviderAlpacaLive:
+		return strategy.ToJSONSchema(AlpacaProviderConfig{
+			ApiKey:                 "",
+			SecretKey:              "",
+			BaseURL:                "",
+			DataURL:                "",
+			SymbolDecimalPrecision: nil,
+		})
 	default:
 		return "", fmt.Errorf("unsupported trading provider: %s", providerName)
 	}
@@ -121,6 +191,12 @@ func GetProviderKeychainFields(providerName string) ([]string, error) {
 	case ProviderBinancePaper, ProviderBinanceLive:
 		//nolint:exhaustruct // Empty struct is intentional for field introspection
 		return strategy.GetKeychainFields(BinanceProviderConfig{}), nil
+	case ProviderKrakenLive:
+		//nolint:exhaustruct // Empty struct is intentional for field introspection
+		return strategy.GetKeychainFields(KrakenProviderConfig{}), nil
+	case ProviderAlpacaPaper, ProviderAlpacaLive:
+		//nolint:exhaustruct // Empty struct is intentional for field introspection
+		return strategy.GetKeychainFields(AlpacaProviderConfig{}), nil
 	default:
 		return nil, fmt.Errorf("unsupported trading provider: %s", providerName)
 	}
@@ -131,6 +207,10 @@ func ParseProviderConfig(providerName string, jsonConfig string) (any, error) {
 	switch ProviderType(providerName) {
 	case ProviderBinancePaper, ProviderBinanceLive:
 		return parseBinanceConfig(jsonConfig)
+	case ProviderKrakenLive:
+		return parseKrakenConfig(jsonConfig)
+	case ProviderAlpacaPaper, ProviderAlpacaLive:
+		return parseAlpacaConfig(jsonConfig)
 	default:
 		return nil, fmt.Errorf("unsupported trading provider: %s", providerName)
 	}
@@ -155,6 +235,22 @@ func NewTradingSystemProvider(providerType ProviderType, config any) (TradingSys
 
 		return NewBinanceTradingSystemProvider(*cfg, false) // useTestnet=false
 
+	case ProviderKrakenLive:
+		cfg, ok := config.(*KrakenProviderConfig)
+		if !ok {
+			return nil, fmt.Errorf("invalid config type for kraken live provider")
+		}
+
+		return NewKrakenTradingSystemProvider(*cfg)
+
+	case ProviderAlpacaPaper, ProviderAlpacaLive:
+		cfg, ok := config.(*AlpacaProviderConfig)
+		if !ok {
+			return nil, fmt.Errorf("invalid config type for alpaca provider")
+		}
+
+		return NewAlpacaTradingSystemProvider(*cfg, providerType == ProviderAlpacaPaper)
+
 	default:
 		return nil, fmt.Errorf("unsupported trading provider: %s", providerType)
 	}
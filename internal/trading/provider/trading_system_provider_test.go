@@ -156,6 +156,50 @@ func (suite *TradingSystemProviderTestSuite) TestNewTradingSystemProvider_Binanc
 	suite.NotNil(provider)
 }
 
+func (suite *TradingSystemProviderTestSuite) TestNewTradingSystemProvider_KrakenLive() {
+	config, err := ParseProviderConfig("kraken-live", `{"apiKey": "test-api-key", "secretKey": "dGVzdC1zZWNyZXQta2V5"}`)
+	suite.Require().NoError(err)
+
+	provider, err := NewTradingSystemProvider(ProviderKrakenLive, config)
+	suite.NoError(err)
+	suite.IsType(&KrakenTradingSystemProvider{}, provider)
+
+	info, err := GetProviderInfo("kraken-live")
+	suite.NoError(err)
+	suite.False(info.IsPaperTrading)
+
+	fields, err := GetProviderKeychainFields("kraken-live")
+	suite.NoError(err)
+	suite.ElementsMatch([]string{"apiKey", "secretKey"}, fields)
+
+	_, err = NewTradingSystemProvider(ProviderKrakenLive, &BinanceProviderConfig{ApiKey: "key", SecretKey: "secret"})
+	suite.ErrorContains(err, "invalid config type")
+}
+
+func (suite *TradingSystemProviderTestSuite) TestNewTradingSystemProvider_Alpaca() {
+	for _, providerType := range []ProviderType{ProviderAlpacaPaper, ProviderAlpacaLive} {
+		suite.Run(string(providerType), func() {
+			config, err := ParseProviderConfig(string(providerType), `{"apiKey": "test-api-key", "secretKey": "test-secret-key"}`)
+			suite.Require().NoError(err)
+
+			provider, err := NewTradingSystemProvider(providerType, config)
+			suite.NoError(err)
+			suite.IsType(&AlpacaTradingSystemProvider{}, provider)
+
+			info, err := GetProviderInfo(string(providerType))
+			suite.NoError(err)
+			suite.Equal(providerType == ProviderAlpacaPaper, info.IsPaperTrading)
+
+			fields, err := GetProviderKeychainFields(string(providerType))
+			suite.NoError(err)
+			suite.ElementsMatch([]string{"apiKey", "secretKey"}, fields)
+
+			_, err = NewTradingSystemProvider(providerType, &BinanceProviderConfig{ApiKey: "key", SecretKey: "secret"})
+			suite.ErrorContains(err, "invalid config type")
+		})
+	}
+}
+
 func (suite *TradingSystemProviderTestSuite) TestNewTradingSystemProvider_InvalidConfigType_BinancePaper() {
 	// Pass wrong config type
 	config := "invalid config"
@@ -197,3 +241,39 @@ func (suite *TradingSystemProviderTestSuite) TestNewTradingSystemProvider_EmptyP
 	suite.Error(err)
 	suite.Contains(err.Error(), "unsupported trading provider")
 }
+
+// Unit Tests - Position Sizing
+
+func (suite *TradingSystemProviderTestSuite) TestRiskPositionSize() {
+	tests := []struct {
+		name         string
+		equity       float64
+		entryPrice   float64
+		stopPrice    float64
+		riskFraction float64
+		expected     float64
+		expectError  bool
+	}{
+		{name: "long stop below entry", equity: 10000, entryPrice: 50, stopPrice: 48, riskFraction: 0.01, expected: 50},
+		{name: "short stop above entry", equity: 10000, entryPrice: 50, stopPrice: 52.5, riskFraction: 0.01, expected: 40},
+		{name: "no equity", equity: 0, entryPrice: 50, stopPrice: 48, riskFraction: 0.01, expected: 0},
+		{name: "stop equals entry", equity: 10000, entryPrice: 50, stopPrice: 50, riskFraction: 0.01, expectError: true},
+		{name: "zero entry price", equity: 10000, entryPrice: 0, stopPrice: 48, riskFraction: 0.01, expectError: true},
+		{name: "zero stop price", equity: 10000, entryPrice: 50, stopPrice: 0, riskFraction: 0.01, expectError: true},
+		{name: "risk fraction above one", equity: 10000, entryPrice: 50, stopPrice: 48, riskFraction: 2, expectError: true},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			quantity, err := RiskPositionSize(tt.equity, tt.entryPrice, tt.stopPrice, tt.riskFraction)
+			if tt.expectError {
+				suite.Error(err)
+
+				return
+			}
+
+			suite.NoError(err)
+			suite.InDelta(tt.expected, quantity, 1e-9)
+		})
+	}
+}
@@ -14,12 +14,19 @@ import (
 // the wallet never calls in these tests.
 type noopProvider struct{}
 
-func (noopProvider) PlaceOrder(types.ExecuteOrder) error                { return nil }
-func (noopProvider) PlaceMultipleOrders([]types.ExecuteOrder) error     { return nil }
-func (noopProvider) GetPositions() ([]types.Position, error)            { return nil, nil }
-func (noopProvider) GetPosition(string) (types.Position, error)         { return types.Position{}, nil }
-func (noopProvider) CancelOrder(string) error                           { return nil }
-func (noopProvider) CancelAllOrders() error                             { return nil }
+func (noopProvider) PlaceOrder(types.ExecuteOrder) error            { return nil }
+func (noopProvider) PlaceMultipleOrders([]types.ExecuteOrder) error { return nil }
+func (noopProvider) GetPositions() ([]types.Position, error)        { return nil, nil }
+func (noopProvider) GetPosition(string) (types.Position, error)     { return types.Position{}, nil }
+func (noopProvider) CancelOrder(string) error                       { return nil }
+func (noopProvider) CancelAllOrders() error                         { return nil }
+func (noopProvider) CancelOrdersForSymbol(string) error             { return nil }
+func (noopProvider) PlaceOCOOrder(types.ExecuteOrder, types.ExecuteOrder, types.ExecuteOrder) (string, error) {
+	return "", nil
+}
+func (noopProvider) PlaceBracketOrder(types.ExecuteOrder, float64, float64) error {
+	return nil
+}
 func (noopProvider) GetOrderStatus(string) (types.OrderStatus, error)   { return "", nil }
 func (noopProvider) GetAccountInfo() (types.AccountInfo, error)         { return types.AccountInfo{}, nil }
 func (noopProvider) GetAssets() ([]types.Asset, error)                  { return nil, nil }
@@ -28,8 +35,11 @@ func (noopProvider) GetOpenOrders() ([]types.ExecuteOrder, error)       { return
 func (noopProvider) GetTrades(types.TradeFilter) ([]types.Trade, error) { return nil, nil }
 func (noopProvider) GetMaxBuyQuantity(string, float64) (float64, error) { return 0, nil }
 func (noopProvider) GetMaxSellQuantity(string) (float64, error)         { return 0, nil }
-func (noopProvider) CheckConnection(context.Context) error              { return nil }
-func (noopProvider) SetOnStatusChange(tradingprovider.OnStatusChange)   {}
+func (noopProvider) CalculatePositionSize(string, float64, float64, float64) (float64, error) {
+	return 0, nil
+}
+func (noopProvider) CheckConnection(context.Context) error            { return nil }
+func (noopProvider) SetOnStatusChange(tradingprovider.OnStatusChange) {}
 
 // fakeProvider satisfies just enough of TradingSystemProvider for wallet tests
 // — the wallet only calls GetAccountInfo, GetAssets, GetPrices, and GetTrades.
@@ -234,3 +244,7 @@ func (a *walletProviderAdapter) GetTrades(filter types.TradeFilter) ([]types.Tra
 	a.inner.lastFilter = filter
 	return a.inner.trades, a.inner.tradesErr
 }
+func (a *walletProviderAdapter) GetTradesPage(filter types.TradeFilter) (types.TradePage, error) {
+	a.inner.lastFilter = filter
+	return types.TradePage{Trades: a.inner.trades}, a.inner.tradesErr
+}
@@ -18,6 +18,19 @@ type AccountInfo struct {
 	TotalFees float64 `json:"total_fees" yaml:"total_fees"`
 	// MarginUsed is the margin currently in use (for margin trading)
 	MarginUsed float64 `json:"margin_used" yaml:"margin_used"`
+	// FreeMargin is the equity, including realized P&L, that is not used as margin
+	FreeMargin float64 `json:"free_margin" yaml:"free_margin"`
+	// Balances is the balance of every asset held, keyed by asset (e.g. "USDC").
+	// Nil when the provider does not report balances per asset.
+	Balances map[string]AssetBalance `json:"balances" yaml:"balances"`
+}
+
+// AssetBalance is the balance of one asset of an exchange account.
+type AssetBalance struct {
+	// Free is the quantity available for new orders
+	Free float64 `json:"free" yaml:"free"`
+	// Locked is the quantity held by open orders
+	Locked float64 `json:"locked" yaml:"locked"`
 }
 
 // TradeFilter is used to filter trades when querying trade history.
@@ -30,4 +43,38 @@ type TradeFilter struct {
 	EndTime time.Time `json:"end_time" yaml:"end_time"`
 	// Limit limits the number of trades returned (0 means no limit)
 	Limit int `json:"limit" yaml:"limit"`
+	// Cursor resumes a paged query after the last trade of the previous page.
+	// It is the NextCursor of a TradePage and is opaque to callers (empty means the first page).
+	Cursor string `json:"cursor" yaml:"cursor"`
+}
+
+// TradePage is one page of a trade history query.
+type TradePage struct {
+	// Trades are the trades of this page, oldest first
+	Trades []Trade `json:"trades" yaml:"trades"`
+	// NextCursor is set as TradeFilter.Cursor to fetch the next page. Empty when this is the last page.
+	NextCursor string `json:"next_cursor" yaml:"next_cursor"`
+}
+
+// TimeRange limits a query to the times between Start and End, both inclusive.
+// A zero Start or End leaves that side of the range open.
+type TimeRange struct {
+	Start time.Time `json:"start" yaml:"start"`
+	End   time.Time `json:"end" yaml:"end"`
+}
+
+// EquitySnapshot is the account state at one point of an equity curve.
+type EquitySnapshot struct {
+	// Time is the market data time the snapshot was taken at
+	Time time.Time `json:"time" yaml:"time"`
+	// Balance is the cash balance
+	Balance float64 `json:"balance" yaml:"balance"`
+	// PositionValue is the market value of the open positions, net of the cost to cover short positions
+	PositionValue float64 `json:"position_value" yaml:"position_value"`
+	// Equity is the balance plus the position value
+	Equity float64 `json:"equity" yaml:"equity"`
+	// UnrealizedPnL is the profit/loss of the open positions
+	UnrealizedPnL float64 `json:"unrealized_pnl" yaml:"unrealized_pnl"`
+	// RealizedPnL is the profit/loss of the closed positions
+	RealizedPnL float64 `json:"realized_pnl" yaml:"realized_pnl"`
 }
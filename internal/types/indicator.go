@@ -18,4 +18,5 @@ const (
 	IndicatorTypeATR                   IndicatorType = "atr"
 	IndicatorTypeMA                    IndicatorType = "ma"
 	IndicatorTypePSY                   IndicatorType = "psy"
+	IndicatorTypeVWAP                  IndicatorType = "vwap"
 )
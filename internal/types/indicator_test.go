@@ -30,6 +30,7 @@ func (suite *IndicatorTestSuite) TestIndicatorTypeConstants() {
 	suite.Equal(IndicatorType("atr"), IndicatorTypeATR)
 	suite.Equal(IndicatorType("ma"), IndicatorTypeMA)
 	suite.Equal(IndicatorType("psy"), IndicatorTypePSY)
+	suite.Equal(IndicatorType("vwap"), IndicatorTypeVWAP)
 }
 
 func (suite *IndicatorTestSuite) TestIndicatorTypeAsString() {
@@ -18,11 +18,39 @@ const (
 	// EngineStatusGapFilling indicates the engine is filling gaps in historical data.
 	EngineStatusGapFilling EngineStatus = "gap_filling"
 
+	// EngineStatusWarmingUp indicates the engine is streaming the first bars of
+	// each symbol into the data cache without calling the strategy.
+	EngineStatusWarmingUp EngineStatus = "warming_up"
+
 	// EngineStatusRunning indicates the engine is processing live market data.
 	EngineStatusRunning EngineStatus = "running"
 
+	// EngineStatusHalted indicates the max drawdown circuit breaker has halted
+	// trading. Market data is still processed unless the run was stopped.
+	EngineStatusHalted EngineStatus = "halted"
+
+	// EngineStatusPaused indicates the engine was paused: market data is still
+	// processed but the strategy does not trade until it is resumed.
+	EngineStatusPaused EngineStatus = "paused"
+
 	// EngineStatusStopped indicates the engine has stopped.
 	EngineStatusStopped EngineStatus = "stopped"
+
+	// EngineStatusConfigValidated indicates a validate-only run found the engine
+	// configured with a strategy, providers, symbols and an interval.
+	EngineStatusConfigValidated EngineStatus = "config_validated"
+
+	// EngineStatusStrategyValidated indicates a validate-only run initialized the
+	// strategy, including its engine version check.
+	EngineStatusStrategyValidated EngineStatus = "strategy_validated"
+
+	// EngineStatusConnectionValidated indicates a validate-only run reached the
+	// trading provider with valid credentials.
+	EngineStatusConnectionValidated EngineStatus = "connection_validated"
+
+	// EngineStatusSymbolsValidated indicates a validate-only run found every
+	// configured symbol on the trading provider.
+	EngineStatusSymbolsValidated EngineStatus = "symbols_validated"
 )
 
 // ProviderConnectionStatus represents the connection state of a provider.
@@ -34,6 +62,13 @@ const (
 
 	// ProviderStatusDisconnected indicates the provider is disconnected.
 	ProviderStatusDisconnected ProviderConnectionStatus = "disconnected"
+
+	// ProviderStatusReconnecting indicates the provider lost its connection and is reconnecting.
+	ProviderStatusReconnecting ProviderConnectionStatus = "reconnecting"
+
+	// ProviderStatusStale indicates the market data provider has sent no bar for
+	// longer than the configured number of intervals.
+	ProviderStatusStale ProviderConnectionStatus = "stale"
 )
 
 // ProviderStatusUpdate contains the status update for market data and trading providers.
@@ -45,6 +80,27 @@ type ProviderStatusUpdate struct {
 	TradingStatus ProviderConnectionStatus `json:"trading_status"`
 }
 
+// EquityMetrics contains risk metrics computed from the equity curve of a live
+// session, i.e. realized plus unrealized PnL sampled once per market data bar.
+type EquityMetrics struct {
+	// SharpeRatio is the rolling annualized Sharpe ratio of the per-bar equity
+	// changes with a zero risk-free rate.
+	SharpeRatio float64 `yaml:"sharpe_ratio" json:"sharpe_ratio"`
+
+	// SortinoRatio is the rolling annualized Sortino ratio of the per-bar equity
+	// changes, penalizing only negative changes.
+	SortinoRatio float64 `yaml:"sortino_ratio" json:"sortino_ratio"`
+
+	// MaxDrawdown is the largest peak-to-trough decline of the equity curve.
+	MaxDrawdown float64 `yaml:"max_drawdown" json:"max_drawdown"`
+
+	// AnnualizationFactor is the number of bars per year used to annualize the ratios.
+	AnnualizationFactor float64 `yaml:"annualization_factor" json:"annualization_factor"`
+
+	// Samples is the number of equity snapshots in the rolling window.
+	Samples int `yaml:"samples" json:"samples"`
+}
+
 // LiveTradeStats contains statistics for a live trading session.
 type LiveTradeStats struct {
 	// ID is the unique identifier for this trading session (UUID format).
@@ -77,6 +133,9 @@ type LiveTradeStats struct {
 	// TotalFees is the sum of all trading fees paid.
 	TotalFees float64 `yaml:"total_fees" json:"total_fees"`
 
+	// EquityMetrics contains Sharpe, Sortino and drawdown of the equity curve.
+	EquityMetrics EquityMetrics `yaml:"equity_metrics" json:"equity_metrics"`
+
 	// OrdersFilePath is the path to the orders parquet file.
 	OrdersFilePath string `yaml:"orders_file_path" json:"orders_file_path"`
 
@@ -94,6 +153,10 @@ type LiveTradeStats struct {
 
 	// Strategy contains metadata about the strategy that generated these stats.
 	Strategy StrategyInfo `yaml:"strategy" json:"strategy"`
+
+	// Strategies breaks the trade statistics down by strategy when the session
+	// runs more than one, keyed by strategy name.
+	Strategies map[string]LiveTradeStats `yaml:"strategies,omitempty" json:"strategies,omitempty"`
 }
 
 // DailyLiveTradeStats contains both daily and cumulative statistics for a session.
@@ -174,7 +237,14 @@ func NewLiveTradeStats(runID string, runName string, symbols []string, strategy
 			Median:      0,
 			Percentiles: Percentiles{P25: 0, P50: 0, P75: 0, P90: 0, P95: 0, P99: 0},
 		},
-		TotalFees:          0,
+		TotalFees: 0,
+		EquityMetrics: EquityMetrics{
+			SharpeRatio:         0,
+			SortinoRatio:        0,
+			MaxDrawdown:         0,
+			AnnualizationFactor: 0,
+			Samples:             0,
+		},
 		OrdersFilePath:     "",
 		TradesFilePath:     "",
 		MarksFilePath:      "",
@@ -240,6 +240,7 @@ func (s *LiveStatisticsTestSuite) TestEngineStatus_Values() {
 	s.Equal(EngineStatus("prefetching"), EngineStatusPrefetching)
 	s.Equal(EngineStatus("gap_filling"), EngineStatusGapFilling)
 	s.Equal(EngineStatus("running"), EngineStatusRunning)
+	s.Equal(EngineStatus("halted"), EngineStatusHalted)
 	s.Equal(EngineStatus("stopped"), EngineStatusStopped)
 }
 
@@ -251,6 +252,7 @@ func (s *LiveStatisticsTestSuite) TestProviderConnectionStatus_Values() {
 	// Verify enum values are correct strings
 	s.Equal(ProviderConnectionStatus("connected"), ProviderStatusConnected)
 	s.Equal(ProviderConnectionStatus("disconnected"), ProviderStatusDisconnected)
+	s.Equal(ProviderConnectionStatus("reconnecting"), ProviderStatusReconnecting)
 }
 
 func (s *LiveStatisticsTestSuite) TestProviderStatusUpdate_Struct() {
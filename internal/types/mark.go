@@ -38,4 +38,6 @@ type Mark struct {
 	Message      string
 	Category     string
 	Signal       optional.Option[Signal]
+	// StrategyName is the strategy that made the mark when an engine runs several
+	StrategyName string
 }
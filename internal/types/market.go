@@ -11,4 +11,21 @@ type MarketData struct {
 	Low    float64   `csv:"low"`
 	Close  float64   `csv:"close"`
 	Volume float64   `csv:"volume"`
+	// Bid and Ask are the best quotes at the close of the bar. Both are 0 when the data has no quotes.
+	Bid float64 `csv:"bid"`
+	Ask float64 `csv:"ask"`
+}
+
+// HasQuote reports whether the bar carries a bid and an ask.
+func (m MarketData) HasQuote() bool {
+	return m.Bid > 0 && m.Ask > 0
+}
+
+// Tick is a single trade reported by a market data provider that streams
+// trades instead of completed bars.
+type Tick struct {
+	Symbol string    `csv:"symbol"`
+	Price  float64   `csv:"price"`
+	Size   float64   `csv:"size"`
+	Time   time.Time `csv:"time"`
 }
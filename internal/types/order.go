@@ -16,12 +16,17 @@ type OrderStatus string
 
 type PositionType string
 
+// TimeInForce is how long an order stays open before it is cancelled.
+type TimeInForce string
+
 const (
 	OrderStatusPending   OrderStatus = "PENDING"
 	OrderStatusFilled    OrderStatus = "FILLED"
 	OrderStatusCancelled OrderStatus = "CANCELLED"
 	OrderStatusRejected  OrderStatus = "REJECTED"
 	OrderStatusFailed    OrderStatus = "FAILED"
+	// OrderStatusPartiallyFilled is an open order of which only part of the quantity has filled.
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
 )
 
 const (
@@ -37,6 +42,23 @@ const (
 const (
 	OrderTypeMarket OrderType = "MARKET"
 	OrderTypeLimit  OrderType = "LIMIT"
+	// OrderTypeStopMarket becomes a market order once the stop price is crossed.
+	OrderTypeStopMarket OrderType = "STOP_MARKET"
+	// OrderTypeStopLimit becomes a limit order at Price once the stop price is crossed.
+	OrderTypeStopLimit OrderType = "STOP_LIMIT"
+	// OrderTypeTrailingStop is a stop-market order whose stop follows the best price
+	// by TrailOffset: upwards for a sell, downwards for a buy. The stop never loosens.
+	OrderTypeTrailingStop OrderType = "TRAILING_STOP"
+)
+
+const (
+	// TimeInForceGTC keeps the order open until it fills or is cancelled. It is the
+	// default when TimeInForce is empty.
+	TimeInForceGTC TimeInForce = "GTC"
+	// TimeInForceIOC fills what it can immediately and cancels the remainder.
+	TimeInForceIOC TimeInForce = "IOC"
+	// TimeInForceFOK fills the whole quantity immediately or is rejected.
+	TimeInForceFOK TimeInForce = "FOK"
 )
 
 const (
@@ -47,6 +69,34 @@ const (
 	OrderReasonInsufficientSellPower string = "insufficient_selling_power"
 	OrderReasonInvalidQuantity       string = "invalid_quantity"
 	OrderReasonInvalidPrice          string = "invalid_price"
+	// OrderReasonBenchmarkRelativeStop marks an exit triggered by underperforming the benchmark.
+	OrderReasonBenchmarkRelativeStop string = "benchmark_relative_stop"
+	// OrderReasonEntryThrottled marks an entry rejected by the per-bar entry limit.
+	OrderReasonEntryThrottled string = "entry_throttled"
+	// OrderReasonSessionClose marks orders closed or rejected by the session-end auto-flatten.
+	OrderReasonSessionClose string = "session_close"
+	// OrderReasonCooldown marks an order rejected within the cooldown of the symbol's last accepted order.
+	OrderReasonCooldown string = "cooldown"
+	// OrderReasonMaxOrdersPerDay marks an order rejected because the day's order limit was reached.
+	OrderReasonMaxOrdersPerDay string = "max_orders_per_day"
+	// OrderReasonMaxNotionalPerDay marks an order rejected because it would exceed the day's notional limit.
+	OrderReasonMaxNotionalPerDay string = "max_notional_per_day"
+	// OrderReasonMaxPositions marks an order rejected because it would open a position beyond the maximum open positions.
+	OrderReasonMaxPositions string = "max_positions"
+	// OrderReasonPositionLimit marks an order rejected because it would take a symbol's position beyond its size limit.
+	OrderReasonPositionLimit string = "position_limit"
+	// OrderReasonMinHolding marks an order rejected because it would close a position before its minimum holding period.
+	OrderReasonMinHolding string = "min_holding"
+	// OrderReasonLiquidation marks orders closing the positions when the live engine stops.
+	OrderReasonLiquidation string = "liquidation"
+	// OrderReasonImmediateOrCancel marks the unfilled remainder of an IOC order that was cancelled.
+	OrderReasonImmediateOrCancel string = "immediate_or_cancel"
+	// OrderReasonFillOrKill marks a FOK order rejected because it could not fill in full.
+	OrderReasonFillOrKill string = "fill_or_kill"
+	// OrderReasonExpired marks a pending order cancelled because its GoodTillDate passed.
+	OrderReasonExpired string = "expired"
+	// OrderReasonMarginCall marks orders closing a position whose equity fell below the maintenance margin.
+	OrderReasonMarginCall string = "margin_call"
 )
 
 type Reason struct {
@@ -64,12 +114,28 @@ type ExecuteOrder struct {
 	ID           string       `yaml:"id" json:"id" csv:"id" validate:"required,uuid"`
 	Symbol       string       `yaml:"symbol" json:"symbol" csv:"symbol" validate:"required"`
 	Side         PurchaseType `yaml:"side" json:"side" csv:"side" validate:"required,oneof=BUY SELL"`
-	OrderType    OrderType    `yaml:"order_type" json:"order_type" csv:"order_type" validate:"required,oneof=MARKET LIMIT"`
+	OrderType    OrderType    `yaml:"order_type" json:"order_type" csv:"order_type" validate:"required,oneof=MARKET LIMIT STOP_MARKET STOP_LIMIT TRAILING_STOP"`
 	Reason       Reason       `yaml:"reason" json:"reason" csv:"reason" validate:"required"`
 	Price        float64      `yaml:"price" json:"price" csv:"price" validate:"required,gt=0"`
 	StrategyName string       `yaml:"strategy_name" json:"strategy_name" csv:"strategy_name" validate:"required"`
 	Quantity     float64      `yaml:"quantity" json:"quantity" csv:"quantity" validate:"required,gt=0"`
 	PositionType PositionType `yaml:"position_type" json:"position_type" csv:"position_type" validate:"required,oneof=LONG SHORT"`
+	// StopPrice is the trigger price for stop orders (OrderTypeStopMarket and OrderTypeStopLimit).
+	// A sell stop triggers when the price falls to or below it, a buy stop when the price rises to or above it.
+	StopPrice float64 `yaml:"stop_price" json:"stop_price" csv:"stop_price" validate:"gte=0"`
+	// TrailOffset is how far the stop of an OrderTypeTrailingStop order trails the best price.
+	// It is a price distance, or a percentage of the best price when TrailPercent is set.
+	TrailOffset float64 `yaml:"trail_offset" json:"trail_offset" csv:"trail_offset" validate:"gte=0"`
+	// TrailPercent makes TrailOffset a percentage (2 means 2%) instead of a price distance.
+	TrailPercent bool `yaml:"trail_percent" json:"trail_percent" csv:"trail_percent"`
+	// FilledQuantity is the part of Quantity that has already filled. It is non-zero
+	// for open orders that filled partially.
+	FilledQuantity float64 `yaml:"filled_quantity" json:"filled_quantity" csv:"filled_quantity" validate:"gte=0"`
+	// TimeInForce is how long the order stays open. Empty means TimeInForceGTC.
+	TimeInForce TimeInForce `yaml:"time_in_force" json:"time_in_force" csv:"time_in_force" validate:"omitempty,oneof=GTC IOC FOK"`
+	// GoodTillDate cancels the order if it is still pending after this time.
+	// The zero time keeps the order open until it fills or is cancelled.
+	GoodTillDate time.Time `yaml:"good_till_date" json:"good_till_date" csv:"good_till_date"`
 	// TakeProfit is the take profit order. Can be nil if not set.
 	TakeProfit optional.Option[ExecuteOrderTakeProfitOrStopLoss] `yaml:"take_profit" json:"take_profit" csv:"take_profit"`
 	// StopLoss is the stop loss order. Can be nil if not set.
@@ -125,6 +191,11 @@ func (eo *ExecuteOrder) Validate() error {
 	return nil
 }
 
+// IsStopOrder reports whether the order waits for its stop price to be crossed before executing.
+func (eo *ExecuteOrder) IsStopOrder() bool {
+	return eo.OrderType == OrderTypeStopMarket || eo.OrderType == OrderTypeStopLimit
+}
+
 // Validate validates the Order struct.
 func (o *Order) Validate() error {
 	validate := validator.New()
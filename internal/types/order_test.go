@@ -245,6 +245,38 @@ func TestExecuteOrderValidate(t *testing.T) {
 			},
 			shouldError: false,
 		},
+		{
+			name: "valid stop limit order",
+			order: ExecuteOrder{
+				ID:           uuid.New().String(),
+				Symbol:       "BTC/USD",
+				Side:         PurchaseTypeSell,
+				OrderType:    OrderTypeStopLimit,
+				Reason:       Reason{Reason: "test", Message: "test"},
+				Price:        94.0,
+				StopPrice:    95.0,
+				StrategyName: "test-strategy",
+				Quantity:     1.0,
+				PositionType: PositionTypeLong,
+			},
+			shouldError: false,
+		},
+		{
+			name: "invalid order - negative stop price",
+			order: ExecuteOrder{
+				ID:           uuid.New().String(),
+				Symbol:       "BTC/USD",
+				Side:         PurchaseTypeSell,
+				OrderType:    OrderTypeStopMarket,
+				Reason:       Reason{Reason: "test", Message: "test"},
+				Price:        95.0,
+				StopPrice:    -1.0,
+				StrategyName: "test-strategy",
+				Quantity:     1.0,
+				PositionType: PositionTypeLong,
+			},
+			shouldError: true,
+		},
 	}
 
 	for _, tt := range tests {
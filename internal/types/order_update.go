@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+// OrderUpdate is a change of an order's state pushed by a trading provider,
+// such as the order being accepted, filling partially or in full, or being cancelled.
+type OrderUpdate struct {
+	// OrderID is the provider's ID of the order.
+	OrderID string `yaml:"order_id" json:"order_id"`
+	// ClientOrderID is the ID the order was placed with, if the provider supports one.
+	ClientOrderID string       `yaml:"client_order_id" json:"client_order_id"`
+	Symbol        string       `yaml:"symbol" json:"symbol"`
+	Side          PurchaseType `yaml:"side" json:"side"`
+	OrderType     OrderType    `yaml:"order_type" json:"order_type"`
+	Status        OrderStatus  `yaml:"status" json:"status"`
+	// Price is the limit price of the order, 0 for market orders.
+	Price float64 `yaml:"price" json:"price"`
+	// StopPrice is the trigger price of stop orders, 0 for other orders.
+	StopPrice float64 `yaml:"stop_price" json:"stop_price"`
+	Quantity  float64 `yaml:"quantity" json:"quantity"`
+	// FilledQuantity is the total quantity filled so far.
+	FilledQuantity float64 `yaml:"filled_quantity" json:"filled_quantity"`
+	// AverageFillPrice is the average price of everything filled so far.
+	AverageFillPrice float64 `yaml:"average_fill_price" json:"average_fill_price"`
+	// LastFillQuantity and LastFillPrice describe the fill that caused this
+	// update. Both are 0 for updates without a fill.
+	LastFillQuantity float64 `yaml:"last_fill_quantity" json:"last_fill_quantity"`
+	LastFillPrice    float64 `yaml:"last_fill_price" json:"last_fill_price"`
+	// Fee is the fee charged for the last fill.
+	Fee  float64   `yaml:"fee" json:"fee"`
+	Time time.Time `yaml:"time" json:"time"`
+}
@@ -31,6 +31,16 @@ type Trade struct {
 	// alongside the FIFO-based PnL field to make the cost-basis comparison
 	// against the *last buy* explicit.
 	LIFOPnL float64 `csv:"lifo_pnl"`
+	// RealizedPnL is the profit and loss realized by a closing trade when it is
+	// matched against the oldest open entry lots (FIFO), net of the exit fee and
+	// the pro-rated entry fees. Unlike PnL it does not depend on the portfolio
+	// calculation strategy. For opening trades it is 0.
+	RealizedPnL float64 `csv:"realized_pnl"`
+	// CostBasis is the entry cost of the lots a closing trade was matched
+	// against by FIFO, including their pro-rated entry fees. For a covering
+	// buy that closes a short it is the matched entry proceeds less fees. For
+	// opening trades it is 0.
+	CostBasis float64 `csv:"cost_basis"`
 	// OpenPositionQty is the open position quantity after this trade.
 	// For long positions, it is the net long quantity. For short positions, it is the net short quantity.
 	OpenPositionQty float64 `csv:"open_position_qty"`
@@ -79,6 +89,21 @@ type Position struct {
 	TotalShortInFee  float64 `csv:"total_short_in_fee"`
 	TotalShortOutFee float64 `csv:"total_short_out_fee"`
 
+	// AverageLongEntryPrice is the average price of the open long lots weighted
+	// by their filled quantity, excluding fees. Partial exits release the lots at
+	// this average, so it only changes when lots are added. It is 0 when no long
+	// position is open.
+	AverageLongEntryPrice float64 `csv:"average_long_entry_price"`
+	// TotalLongCost is the entry cost of the open long lots, the open long
+	// quantity times AverageLongEntryPrice.
+	TotalLongCost float64 `csv:"total_long_cost"`
+	// AverageShortEntryPrice is the average price of the open short lots weighted
+	// by their filled quantity, excluding fees. It is 0 when no short position is open.
+	AverageShortEntryPrice float64 `csv:"average_short_entry_price"`
+	// TotalShortCost is the entry proceeds of the open short lots, the open short
+	// quantity times AverageShortEntryPrice.
+	TotalShortCost float64 `csv:"total_short_cost"`
+
 	OpenTimestamp time.Time `csv:"open_timestamp"`
 	StrategyName  string    `csv:"strategy_name"`
 }
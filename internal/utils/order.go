@@ -37,9 +37,34 @@ func RoundToDecimalPrecision(quantity float64, decimalPrecision int) float64 {
 	return math.Floor(quantity*multiplier) / multiplier
 }
 
+// SymbolDecimalPrecision returns the decimal precision configured for symbol in
+// precisions, or fallback when the symbol has none.
+func SymbolDecimalPrecision(precisions map[string]int, symbol string, fallback int) int {
+	if precision, ok := precisions[symbol]; ok {
+		return precision
+	}
+
+	return fallback
+}
+
 // CalculateOrderQuantityByPercentage calculates the quantity of an order by the given percentage of the balance.
 func CalculateOrderQuantityByPercentage(balance float64, price float64, commissionFee commission_fee.CommissionFee, percentage float64) float64 {
 	quantity := balance * percentage
 
 	return CalculateMaxQuantity(quantity, price, commissionFee)
 }
+
+// CalculateOrderQuantityByATR sizes an order so that an adverse move of
+// atrMultiple * atr costs riskPercentage of equity. For a fixed risk target the
+// returned quantity scales inversely with ATR: doubling volatility halves the
+// position. Returns 0 when any input is non-positive.
+func CalculateOrderQuantityByATR(equity float64, riskPercentage float64, atr float64, atrMultiple float64) float64 {
+	if equity <= 0 || riskPercentage <= 0 || atr <= 0 || atrMultiple <= 0 {
+		return 0
+	}
+
+	targetRisk := equity * riskPercentage
+	riskPerUnit := atr * atrMultiple
+
+	return targetRisk / riskPerUnit
+}
@@ -191,3 +191,89 @@ func (suite *UtilsTestSuite) TestRoundToDecimalPrecision() {
 		})
 	}
 }
+
+func (suite *UtilsTestSuite) TestSymbolDecimalPrecision() {
+	precisions := map[string]int{"BTC/USD": 8, "AAPL": 0}
+
+	suite.Equal(8, SymbolDecimalPrecision(precisions, "BTC/USD", 2))
+	suite.Equal(0, SymbolDecimalPrecision(precisions, "AAPL", 2), "a configured precision of 0 is not the fallback")
+	suite.Equal(2, SymbolDecimalPrecision(precisions, "MSFT", 2))
+	suite.Equal(2, SymbolDecimalPrecision(nil, "MSFT", 2))
+}
+
+func (suite *UtilsTestSuite) TestCalculateOrderQuantityByATR() {
+	tests := []struct {
+		name           string
+		equity         float64
+		riskPercentage float64
+		atr            float64
+		atrMultiple    float64
+		expectedQty    float64
+	}{
+		{
+			name:           "One ATR risk",
+			equity:         10000.0,
+			riskPercentage: 0.01,
+			atr:            2.0,
+			atrMultiple:    1.0,
+			expectedQty:    50.0,
+		},
+		{
+			name:           "Two ATR multiple halves the quantity",
+			equity:         10000.0,
+			riskPercentage: 0.01,
+			atr:            2.0,
+			atrMultiple:    2.0,
+			expectedQty:    25.0,
+		},
+		{
+			name:           "Zero ATR",
+			equity:         10000.0,
+			riskPercentage: 0.01,
+			atr:            0.0,
+			atrMultiple:    1.0,
+			expectedQty:    0.0,
+		},
+		{
+			name:           "Zero equity",
+			equity:         0.0,
+			riskPercentage: 0.01,
+			atr:            2.0,
+			atrMultiple:    1.0,
+			expectedQty:    0.0,
+		},
+		{
+			name:           "Negative multiple",
+			equity:         10000.0,
+			riskPercentage: 0.01,
+			atr:            2.0,
+			atrMultiple:    -1.0,
+			expectedQty:    0.0,
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			qty := CalculateOrderQuantityByATR(tc.equity, tc.riskPercentage, tc.atr, tc.atrMultiple)
+			suite.Assert().InDelta(tc.expectedQty, qty, 0.0001)
+		})
+	}
+}
+
+func (suite *UtilsTestSuite) TestCalculateOrderQuantityByATR_ScalesInverselyWithATR() {
+	const (
+		equity         = 50000.0
+		riskPercentage = 0.02
+		atrMultiple    = 1.5
+	)
+
+	base := CalculateOrderQuantityByATR(equity, riskPercentage, 1.0, atrMultiple)
+	suite.Require().Greater(base, 0.0)
+
+	for _, factor := range []float64{2, 4, 10} {
+		qty := CalculateOrderQuantityByATR(equity, riskPercentage, factor, atrMultiple)
+		suite.Assert().InDelta(base/factor, qty, 0.0001)
+		// The dollar risk of an atrMultiple*ATR move stays at the target.
+		suite.Assert().InDelta(equity*riskPercentage, qty*factor*atrMultiple, 0.0001)
+	}
+}
@@ -53,6 +53,7 @@ const (
 	ErrCodeOrderFailed       ErrorCode = 500
 	ErrCodePositionNotFound  ErrorCode = 501
 	ErrCodeMarketDataMissing ErrorCode = 502
+	ErrCodeOrderRejected     ErrorCode = 503
 
 	// ErrCodeBacktestStateNil indicates backtest state is nil (600-699 range).
 	ErrCodeBacktestStateNil      ErrorCode = 600
@@ -74,4 +75,8 @@ const (
 
 	// ErrCodeCallbackFailed indicates a callback execution failed (800-899 range).
 	ErrCodeCallbackFailed ErrorCode = 800
+
+	// ErrCodeTradingHalted indicates trading was halted by a risk limit (900-999 range).
+	ErrCodeTradingHalted     ErrorCode = 900
+	ErrCodeDailyLimitReached ErrorCode = 901
 )
@@ -118,6 +118,7 @@ func (suite *ErrorTestSuite) TestErrorCodeValues() {
 	suite.Equal(ErrorCode(600), ErrCodeBacktestStateNil)
 	suite.Equal(ErrorCode(700), ErrCodeMarketDataFetchFailed)
 	suite.Equal(ErrorCode(800), ErrCodeCallbackFailed)
+	suite.Equal(ErrorCode(900), ErrCodeTradingHalted)
 }
 
 func (suite *ErrorTestSuite) TestInsufficientDataError() {
@@ -0,0 +1,228 @@
+package marketdata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
+)
+
+// downloadChunk is one slice of a chunked download.
+type downloadChunk struct {
+	start time.Time
+	end   time.Time
+}
+
+// downloadChunked downloads the range of params in chunks of ChunkDays days.
+// Every completed chunk is written to its own parquet file in a parts
+// directory next to the output file, and chunks that already have a part file
+// or rows in an existing output file are skipped, so a failed download can be
+// resumed by running it again. A chunk that still fails after its retries
+// does not stop the other chunks from downloading. Once every chunk is present
+// the parts are merged into the output file and the parts directory is removed.
+func (c *Client) downloadChunked(ctx context.Context, params DownloadParams) error {
+	if c.config.WriterType != WriterDuckDB {
+		return fmt.Errorf("unsupported writer type: %s", c.config.WriterType)
+	}
+
+	outputPath := c.outputPath(params)
+	partsDir := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".parts"
+
+	if err := os.MkdirAll(partsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create parts directory %s: %w", partsDir, err)
+	}
+
+	db, err := sql.Open("duckdb", ":memory:")
+	if err != nil {
+		return fmt.Errorf("failed to open DuckDB connection: %w", err)
+	}
+	defer db.Close()
+
+	_, statErr := os.Stat(outputPath)
+	outputExists := statErr == nil
+
+	chunks := splitIntoChunks(params.StartDate, params.EndDate, c.config.ChunkDays)
+
+	var failed []string
+
+	var lastErr error
+
+	for i, chunk := range chunks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		partPath := filepath.Join(partsDir, fmt.Sprintf("%s_%s.parquet",
+			chunk.start.Format("2006-01-02"), chunk.end.Format("2006-01-02")))
+
+		done, err := c.chunkDownloaded(db, chunk, partPath, outputPath, outputExists)
+		if err != nil {
+			return err
+		}
+
+		if !done {
+			if err := c.downloadChunkWithRetry(ctx, params, chunk, partPath); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				failed = append(failed, chunk.start.Format("2006-01-02"))
+				lastErr = err
+
+				continue
+			}
+		}
+
+		if c.onProgress != nil {
+			c.onProgress(float64(i+1), float64(len(chunks)),
+				fmt.Sprintf("Downloaded %s %s", params.Ticker, chunk.start.Format("2006-01-02")))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("download failed for %s, run the download again to fetch the missing chunks: %w",
+			strings.Join(failed, ", "), lastErr)
+	}
+
+	return mergeParts(db, outputPath, outputExists, partsDir)
+}
+
+// chunkDownloaded reports whether a chunk has a part file or rows in the
+// output file of an earlier download.
+func (c *Client) chunkDownloaded(db *sql.DB, chunk downloadChunk, partPath string, outputPath string, outputExists bool) (bool, error) {
+	if _, err := os.Stat(partPath); err == nil {
+		return true, nil
+	}
+
+	if !outputExists {
+		return false, nil
+	}
+
+	var count int
+
+	err := db.QueryRow(fmt.Sprintf(`SELECT count(*) FROM read_parquet('%s') WHERE time BETWEEN ? AND ?`, outputPath),
+		chunk.start, chunk.end).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to read existing data from %s: %w", outputPath, err)
+	}
+
+	return count > 0, nil
+}
+
+// downloadChunkWithRetry downloads one chunk into partPath, retrying failed
+// attempts MaxRetries times with a doubling backoff.
+func (c *Client) downloadChunkWithRetry(ctx context.Context, params DownloadParams, chunk downloadChunk, partPath string) error {
+	backoff := c.config.RetryBackoff
+
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		err = c.downloadChunk(ctx, params, chunk, partPath)
+		if err == nil || attempt >= c.config.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		fmt.Printf("Warning: download of %s %s failed (attempt %d of %d), retrying in %s: %v\n",
+			params.Ticker, chunk.start.Format("2006-01-02"), attempt+1, c.config.MaxRetries+1, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// downloadChunk downloads one chunk into partPath. A failed attempt leaves no
+// part file behind, so the chunk is fetched again on the next run.
+func (c *Client) downloadChunk(ctx context.Context, params DownloadParams, chunk downloadChunk, partPath string) error {
+	// Providers write to a temporary file that is only renamed to the part
+	// file once the chunk is complete.
+	tempPath := partPath + ".tmp"
+	partWriter := writer.NewDuckDBWriter(tempPath)
+
+	defer func() {
+		if err := partWriter.Close(); err != nil {
+			fmt.Printf("Warning: failed to close writer: %v\n", err)
+		}
+
+		os.Remove(tempPath)
+	}()
+
+	c.provider.ConfigWriter(partWriter)
+
+	_, err := c.provider.Download(ctx, params.Ticker, chunk.start, chunk.end, params.Multiplier, params.Timespan, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tempPath, partPath); err != nil {
+		return fmt.Errorf("failed to save %s: %w", partPath, err)
+	}
+
+	return nil
+}
+
+// mergeParts writes the rows of every part file, together with the rows of an
+// existing output file, to the output file and removes the parts directory.
+func mergeParts(db *sql.DB, outputPath string, outputExists bool, partsDir string) error {
+	parts, err := filepath.Glob(filepath.Join(partsDir, "*.parquet"))
+	if err != nil {
+		return fmt.Errorf("failed to list parts in %s: %w", partsDir, err)
+	}
+
+	sources := parts
+	if outputExists {
+		sources = append([]string{outputPath}, parts...)
+	}
+
+	if len(sources) > 0 {
+		quoted := make([]string, len(sources))
+		for i, source := range sources {
+			quoted[i] = fmt.Sprintf("'%s'", source)
+		}
+
+		tempPath := outputPath + ".tmp"
+
+		_, err = db.Exec(fmt.Sprintf(`COPY (SELECT * FROM read_parquet([%s]) ORDER BY time) TO '%s' (FORMAT PARQUET)`,
+			strings.Join(quoted, ", "), tempPath))
+		if err != nil {
+			return fmt.Errorf("failed to merge parts into %s: %w", outputPath, err)
+		}
+
+		if err := os.Rename(tempPath, outputPath); err != nil {
+			return fmt.Errorf("failed to save %s: %w", outputPath, err)
+		}
+	}
+
+	if err := os.RemoveAll(partsDir); err != nil {
+		return fmt.Errorf("failed to remove parts directory %s: %w", partsDir, err)
+	}
+
+	return nil
+}
+
+// splitIntoChunks splits the range from start to end into consecutive chunks
+// of days days. Every chunk but the last ends one millisecond before the next
+// one starts, so no bar is fetched twice.
+func splitIntoChunks(start time.Time, end time.Time, days int) []downloadChunk {
+	var chunks []downloadChunk
+
+	for chunkStart := start; chunkStart.Before(end); chunkStart = chunkStart.AddDate(0, 0, days) {
+		chunkEnd := chunkStart.AddDate(0, 0, days).Add(-time.Millisecond)
+		if !chunkEnd.Before(end) {
+			chunkEnd = end
+		}
+
+		chunks = append(chunks, downloadChunk{start: chunkStart, end: chunkEnd})
+	}
+
+	return chunks
+}
@@ -0,0 +1,229 @@
+package marketdata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/polygon-io/client-go/rest/models"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
+)
+
+// dayAggsIterator yields three minute bars at the start of a trading day.
+type dayAggsIterator struct {
+	aggs  []models.Agg
+	index int
+	err   error
+}
+
+func (it *dayAggsIterator) Next() bool {
+	if it.err != nil || it.index >= len(it.aggs) {
+		return false
+	}
+
+	it.index++
+
+	return true
+}
+
+func (it *dayAggsIterator) Item() models.Agg {
+	return it.aggs[it.index-1]
+}
+
+func (it *dayAggsIterator) Err() error {
+	return it.err
+}
+
+// flakyPolygonAPI serves three bars for every requested day and fails the
+// requests of the days in failures until their count of failures is used up.
+// A negative count fails every request of the day.
+type flakyPolygonAPI struct {
+	requested []string
+	failures  map[string]int
+}
+
+func (api *flakyPolygonAPI) ListAggs(_ context.Context, params *models.ListAggsParams, _ ...models.RequestOption) provider.PolygonAggsIterator {
+	day := time.Time(params.From).UTC()
+	api.requested = append(api.requested, day.Format("2006-01-02"))
+
+	if remaining := api.failures[day.Format("2006-01-02")]; remaining != 0 {
+		api.failures[day.Format("2006-01-02")] = remaining - 1
+
+		return &dayAggsIterator{aggs: nil, index: 0, err: errors.New("polygon: 502 bad gateway")}
+	}
+
+	open := day.Add(14*time.Hour + 30*time.Minute)
+	aggs := make([]models.Agg, 3)
+
+	for i := range aggs {
+		//nolint:exhaustruct // only the fields written by the download are needed
+		aggs[i] = models.Agg{
+			Timestamp: models.Millis(open.Add(time.Duration(i) * time.Minute)),
+			Open:      100,
+			High:      101,
+			Low:       99,
+			Close:     100.5,
+			Volume:    1000,
+		}
+	}
+
+	return &dayAggsIterator{aggs: aggs, index: 0, err: nil}
+}
+
+// newChunkedClient returns a client downloading from api in daily chunks into dataPath.
+func (suite *ClientTestSuite) newChunkedClient(api provider.PolygonAPIClient, dataPath string, maxRetries int, onProgress provider.OnDownloadProgress) *Client {
+	return &Client{
+		provider: provider.NewPolygonClientWithAPI(api, []string{"AAPL"}, "1m"),
+		config: ClientConfig{
+			ProviderType: ProviderPolygon,
+			WriterType:   WriterDuckDB,
+			DataPath:     dataPath,
+			ChunkDays:    1,
+			MaxRetries:   maxRetries,
+			RetryBackoff: time.Millisecond,
+		},
+		validate:   validator.New(),
+		onProgress: onProgress,
+	}
+}
+
+// readBarDays returns the day of every bar in a parquet file in order.
+func (suite *ClientTestSuite) readBarDays(path string) []string {
+	db, err := sql.Open("duckdb", ":memory:")
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	rows, err := db.Query(fmt.Sprintf(`SELECT strftime(time, '%%Y-%%m-%%d') FROM read_parquet('%s') ORDER BY time`, path))
+	suite.Require().NoError(err)
+	defer rows.Close()
+
+	var days []string
+
+	for rows.Next() {
+		var day string
+		suite.Require().NoError(rows.Scan(&day))
+		days = append(days, day)
+	}
+
+	suite.Require().NoError(rows.Err())
+
+	return days
+}
+
+func (suite *ClientTestSuite) TestChunkedDownloadResumesFailedDay() {
+	dataPath := suite.T().TempDir()
+	params := DownloadParams{
+		Ticker:     "AAPL",
+		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+		Multiplier: 1,
+		Timespan:   models.Minute,
+	}
+	outputPath := filepath.Join(dataPath, "AAPL_2024-01-01_2024-01-04_1_minute.parquet")
+	partsDir := filepath.Join(dataPath, "AAPL_2024-01-01_2024-01-04_1_minute.parts")
+
+	// The second day fails on both attempts
+	api := &flakyPolygonAPI{failures: map[string]int{"2024-01-02": -1}}
+
+	var progress []string
+
+	client := suite.newChunkedClient(api, dataPath, 1, func(current float64, total float64, message string) {
+		progress = append(progress, fmt.Sprintf("%.0f/%.0f %s", current, total, message))
+	})
+
+	err := client.Download(context.Background(), params)
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "2024-01-02")
+	suite.Equal([]string{"2024-01-01", "2024-01-02", "2024-01-02", "2024-01-03"}, api.requested)
+	suite.Equal([]string{"1/3 Downloaded AAPL 2024-01-01", "3/3 Downloaded AAPL 2024-01-03"}, progress)
+
+	// The completed days are kept, the failed one is not
+	suite.NoFileExists(outputPath)
+	suite.FileExists(filepath.Join(partsDir, "2024-01-01_2024-01-01.parquet"))
+	suite.FileExists(filepath.Join(partsDir, "2024-01-03_2024-01-03.parquet"))
+	suite.NoFileExists(filepath.Join(partsDir, "2024-01-02_2024-01-02.parquet"))
+
+	// Running the download again only fetches the missing day
+	api = &flakyPolygonAPI{failures: map[string]int{}}
+	client = suite.newChunkedClient(api, dataPath, 1, nil)
+
+	suite.Require().NoError(client.Download(context.Background(), params))
+	suite.Equal([]string{"2024-01-02"}, api.requested)
+
+	suite.NoDirExists(partsDir)
+	suite.Equal([]string{
+		"2024-01-01", "2024-01-01", "2024-01-01",
+		"2024-01-02", "2024-01-02", "2024-01-02",
+		"2024-01-03", "2024-01-03", "2024-01-03",
+	}, suite.readBarDays(outputPath))
+
+	// A completed download is found in the output file and not fetched again
+	api = &flakyPolygonAPI{failures: map[string]int{}}
+	client = suite.newChunkedClient(api, dataPath, 1, nil)
+
+	suite.Require().NoError(client.Download(context.Background(), params))
+	suite.Empty(api.requested)
+	suite.Len(suite.readBarDays(outputPath), 9)
+}
+
+func (suite *ClientTestSuite) TestChunkedDownloadRetriesFailedChunk() {
+	dataPath := suite.T().TempDir()
+	params := DownloadParams{
+		Ticker:     "AAPL",
+		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		Multiplier: 1,
+		Timespan:   models.Minute,
+	}
+
+	// The first day fails twice and succeeds on its last retry
+	api := &flakyPolygonAPI{failures: map[string]int{"2024-01-01": 2}}
+	client := suite.newChunkedClient(api, dataPath, 2, nil)
+
+	suite.Require().NoError(client.Download(context.Background(), params))
+	suite.Equal([]string{"2024-01-01", "2024-01-01", "2024-01-01", "2024-01-02"}, api.requested)
+	suite.Len(suite.readBarDays(filepath.Join(dataPath, "AAPL_2024-01-01_2024-01-03_1_minute.parquet")), 6)
+}
+
+func (suite *ClientTestSuite) TestSplitIntoChunks() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	chunks := splitIntoChunks(start, end, 2)
+	suite.Equal([]downloadChunk{
+		{start: start, end: start.AddDate(0, 0, 2).Add(-time.Millisecond)},
+		{start: start.AddDate(0, 0, 2), end: start.AddDate(0, 0, 4).Add(-time.Millisecond)},
+		{start: start.AddDate(0, 0, 4), end: end},
+	}, chunks)
+}
+
+func (suite *ClientTestSuite) TestChunkedDownloadKeepsPartsOnCancel() {
+	dataPath := suite.T().TempDir()
+	params := DownloadParams{
+		Ticker:     "AAPL",
+		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		Multiplier: 1,
+		Timespan:   models.Minute,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := &flakyPolygonAPI{failures: map[string]int{}}
+	client := suite.newChunkedClient(api, dataPath, 0, func(current float64, total float64, message string) {
+		cancel()
+	})
+
+	suite.ErrorIs(client.Download(ctx, params), context.Canceled)
+	suite.Equal([]string{"2024-01-01"}, api.requested)
+	suite.FileExists(filepath.Join(dataPath, "AAPL_2024-01-01_2024-01-03_1_minute.parts", "2024-01-01_2024-01-01.parquet"))
+
+	_, err := os.Stat(filepath.Join(dataPath, "AAPL_2024-01-01_2024-01-03_1_minute.parquet"))
+	suite.True(os.IsNotExist(err))
+}
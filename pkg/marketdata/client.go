@@ -19,6 +19,8 @@ type ProviderType string
 const (
 	ProviderPolygon ProviderType = "polygon"
 	ProviderBinance ProviderType = "binance"
+	// ProviderCSV imports OHLCV rows from a local CSV file instead of downloading them.
+	ProviderCSV ProviderType = "csv"
 )
 
 // WriterType defines the type of market data writer.
@@ -30,10 +32,22 @@ const (
 
 // ClientConfig holds the configuration for the market data client.
 type ClientConfig struct {
-	ProviderType  ProviderType `validate:"required,oneof=polygon binance"`
-	WriterType    WriterType   `validate:"required,oneof=duckdb"`
-	DataPath      string       `validate:"required"`
-	PolygonApiKey string       `validate:"required_if=ProviderType polygon"`
+	ProviderType  ProviderType        `validate:"required,oneof=polygon binance csv"`
+	WriterType    WriterType          `validate:"required,oneof=duckdb"`
+	DataPath      string              `validate:"required"`
+	PolygonApiKey string              `validate:"required_if=ProviderType polygon"`
+	CSV           *provider.CSVConfig `validate:"required_if=ProviderType csv"`
+	// ChunkDays splits a download into chunks of this many days that are
+	// fetched one after another and kept on disk as they complete, so a failed
+	// download resumes from the first missing chunk. 0 downloads the whole
+	// range in one request.
+	ChunkDays int `validate:"min=0"`
+	// MaxRetries is the number of times a failed chunk is retried before the
+	// download fails. Only used with ChunkDays.
+	MaxRetries int `validate:"min=0"`
+	// RetryBackoff is the wait before the first retry of a chunk. It doubles
+	// with each further retry.
+	RetryBackoff time.Duration `validate:"min=0"`
 }
 
 // DownloadParams holds the parameters for a market data download request.
@@ -80,6 +94,11 @@ func NewClient(config ClientConfig, onProgress provider.OnDownloadProgress) (*Cl
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Binance client: %w", err)
 		}
+	case ProviderCSV:
+		marketProvider, err = provider.NewCSVClient(config.CSV)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CSV client: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported provider type: %s", config.ProviderType)
 	}
@@ -134,6 +153,10 @@ func (c *Client) Download(ctx context.Context, params DownloadParams) error {
 		return fmt.Errorf("invalid download parameters: %w", err)
 	}
 
+	if c.config.ChunkDays > 0 {
+		return c.downloadChunked(ctx, params)
+	}
+
 	// Setup writer
 	marketWriter, err := c.setupWriter(params)
 	if err != nil {
@@ -173,14 +196,7 @@ func (c *Client) Download(ctx context.Context, params DownloadParams) error {
 func (c *Client) setupWriter(params DownloadParams) (writer.MarketDataWriter, error) {
 	switch c.config.WriterType {
 	case WriterDuckDB:
-		// Construct filename: TICKER_START_END_MULTIPLIER_TIMESPAN.parquet
-		outputFileName := fmt.Sprintf("%s_%s_%s_%d_%s.parquet",
-			params.Ticker,
-			params.StartDate.Format("2006-01-02"),
-			params.EndDate.Format("2006-01-02"),
-			params.Multiplier,
-			params.Timespan)
-		outputPath := filepath.Join(c.config.DataPath, outputFileName)
+		outputPath := c.outputPath(params)
 
 		// check if datapath exist. Otherwise, create it
 		if _, err := os.Stat(c.config.DataPath); os.IsNotExist(err) {
@@ -200,3 +216,16 @@ func (c *Client) setupWriter(params DownloadParams) (writer.MarketDataWriter, er
 		return nil, fmt.Errorf("unsupported writer type: %s", c.config.WriterType)
 	}
 }
+
+// outputPath returns the path of the file a download is written to.
+func (c *Client) outputPath(params DownloadParams) string {
+	// Construct filename: TICKER_START_END_MULTIPLIER_TIMESPAN.parquet
+	outputFileName := fmt.Sprintf("%s_%s_%s_%d_%s.parquet",
+		params.Ticker,
+		params.StartDate.Format("2006-01-02"),
+		params.EndDate.Format("2006-01-02"),
+		params.Multiplier,
+		params.Timespan)
+
+	return filepath.Join(c.config.DataPath, outputFileName)
+}
@@ -3,12 +3,14 @@ package marketdata
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/polygon-io/client-go/rest/models"
 	"github.com/rxtech-lab/argo-trading/mocks"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/provider"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 )
@@ -625,3 +627,44 @@ func (suite *ClientTestSuite) TestNewClientFromBinanceConfig() {
 	suite.Equal(WriterDuckDB, client.config.WriterType)
 	suite.Equal(suite.tempDir, client.config.DataPath)
 }
+
+// TestClientDownloadFromCSV tests that the csv provider imports rows into a parquet file
+func (suite *ClientTestSuite) TestClientDownloadFromCSV() {
+	config := ClientConfig{
+		ProviderType:  ProviderCSV,
+		WriterType:    WriterDuckDB,
+		DataPath:      suite.tempDir,
+		PolygonApiKey: "",
+		CSV:           &provider.CSVConfig{FilePath: "provider/testdata/ohlcv.csv"},
+	}
+
+	client, err := NewClient(config, nil)
+	suite.Require().NoError(err)
+
+	err = client.Download(context.Background(), DownloadParams{
+		Ticker:     "AAPL",
+		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+		Multiplier: 1,
+		Timespan:   models.Minute,
+	})
+	suite.Require().NoError(err)
+
+	suite.FileExists(filepath.Join(suite.tempDir, "AAPL_2024-01-01_2024-01-04_1_minute.parquet"))
+}
+
+// TestNewClientWithCSVProviderRequiresConfig tests that the csv provider requires a CSV config
+func (suite *ClientTestSuite) TestNewClientWithCSVProviderRequiresConfig() {
+	config := ClientConfig{
+		ProviderType:  ProviderCSV,
+		WriterType:    WriterDuckDB,
+		DataPath:      suite.tempDir,
+		PolygonApiKey: "",
+		CSV:           nil,
+	}
+
+	client, err := NewClient(config, nil)
+	suite.Error(err)
+	suite.Nil(client)
+	suite.Contains(err.Error(), "invalid client configuration")
+}
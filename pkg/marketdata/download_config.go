@@ -105,6 +105,10 @@ func (c *PolygonDownloadConfig) ToClientConfig(dataPath string) ClientConfig {
 		WriterType:    WriterDuckDB,
 		DataPath:      dataPath,
 		PolygonApiKey: c.ApiKey,
+		CSV:           nil,
+		ChunkDays:     0,
+		MaxRetries:    0,
+		RetryBackoff:  0,
 	}
 }
 
@@ -115,6 +119,10 @@ func (c *BinanceDownloadConfig) ToClientConfig(dataPath string) ClientConfig {
 		WriterType:    WriterDuckDB,
 		DataPath:      dataPath,
 		PolygonApiKey: "",
+		CSV:           nil,
+		ChunkDays:     0,
+		MaxRetries:    0,
+		RetryBackoff:  0,
 	}
 }
 
@@ -0,0 +1,629 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/polygon-io/client-go/rest/models"
+	"go.uber.org/zap"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
+)
+
+const (
+	// AlpacaDataURL is the Alpaca market data REST API endpoint.
+	AlpacaDataURL = "https://data.alpaca.markets"
+	// AlpacaStreamURL is the Alpaca stock market data WebSocket endpoint. The
+	// feed (iex or sip) is appended to it.
+	AlpacaStreamURL = "wss://stream.data.alpaca.markets/v2/"
+	// AlpacaFeedIEX is the free feed of the IEX exchange.
+	AlpacaFeedIEX = "iex"
+	// AlpacaFeedSIP is the consolidated feed of all US exchanges. It requires a
+	// market data subscription.
+	AlpacaFeedSIP = "sip"
+	// AlpacaStreamInterval is the only bar interval published on the Alpaca
+	// bars channel.
+	AlpacaStreamInterval = "1m"
+	// alpacaBarsLimit is the number of bars requested per bars page.
+	alpacaBarsLimit = 10000
+)
+
+// AlpacaBar is a bar of the Alpaca bars endpoint and bars channel.
+type AlpacaBar struct {
+	Time   time.Time `json:"t"`
+	Open   float64   `json:"o"`
+	High   float64   `json:"h"`
+	Low    float64   `json:"l"`
+	Close  float64   `json:"c"`
+	Volume float64   `json:"v"`
+}
+
+// AlpacaDataClient abstracts the Alpaca market data REST API for testing.
+type AlpacaDataClient interface {
+	// GetBars returns a page of the bars of symbol at timeframe (such as 1Min)
+	// that open between start and end, oldest first, together with the token of
+	// the next page. The token is empty on the last page.
+	GetBars(ctx context.Context, symbol string, timeframe string, start, end time.Time, pageToken string) ([]AlpacaBar, string, error)
+}
+
+// alpacaStreamMessage is a message of the Alpaca WebSocket. Every frame holds
+// a list of them.
+type alpacaStreamMessage struct {
+	AlpacaBar
+
+	Type    string `json:"T"`
+	Symbol  string `json:"S"`
+	Message string `json:"msg"`
+	Code    int    `json:"code"`
+}
+
+// alpacaStreamAction authenticates or subscribes on the Alpaca WebSocket.
+type alpacaStreamAction struct {
+	Action string   `json:"action"`
+	Key    string   `json:"key,omitempty"`
+	Secret string   `json:"secret,omitempty"`
+	Bars   []string `json:"bars,omitempty"`
+}
+
+// realAlpacaDataClient calls the Alpaca market data REST API over HTTP.
+type realAlpacaDataClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	secretKey  string
+	feed       string
+}
+
+func (c *realAlpacaDataClient) GetBars(ctx context.Context, symbol string, timeframe string, start, end time.Time, pageToken string) ([]AlpacaBar, string, error) {
+	params := url.Values{}
+	params.Set("timeframe", timeframe)
+	params.Set("start", start.UTC().Format(time.RFC3339))
+	params.Set("end", end.UTC().Format(time.RFC3339))
+	params.Set("limit", strconv.Itoa(alpacaBarsLimit))
+	params.Set("adjustment", "raw")
+	params.Set("feed", c.feed)
+
+	if pageToken != "" {
+		params.Set("page_token", pageToken)
+	}
+
+	endpoint := fmt.Sprintf("%s/v2/stocks/%s/bars?%s", c.baseURL, url.PathEscape(symbol), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create alpaca request: %w", err)
+	}
+
+	req.Header.Set("APCA-API-KEY-ID", c.apiKey)
+	req.Header.Set("APCA-API-SECRET-KEY", c.secretKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("alpaca request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read alpaca response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("alpaca api error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(payload)))
+	}
+
+	var result struct {
+		Bars          []AlpacaBar `json:"bars"`
+		NextPageToken string      `json:"next_page_token"`
+	}
+
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return nil, "", fmt.Errorf("failed to parse alpaca bars: %w", err)
+	}
+
+	return result.Bars, result.NextPageToken, nil
+}
+
+// AlpacaClient provides US equity bars from Alpaca: historical bars from the
+// REST API and live minute bars from the WebSocket of the configured feed.
+// Dropped connections are reconnected with a backoff.
+type AlpacaClient struct {
+	dataClient     AlpacaDataClient
+	wsURL          string
+	apiKey         string
+	secretKey      string
+	writer         writer.MarketDataWriter
+	onStatusChange OnStatusChange
+	symbols        []string
+	interval       string
+	reconnect      reconnectPolicy
+}
+
+// NewAlpacaClient creates an Alpaca market data provider.
+func NewAlpacaClient(config *AlpacaStreamConfig) (Provider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required for alpaca provider")
+	}
+
+	if config.ApiKey == "" || config.SecretKey == "" {
+		return nil, fmt.Errorf("apiKey and secretKey are required")
+	}
+
+	feed := config.Feed
+	if feed == "" {
+		feed = AlpacaFeedIEX
+	}
+
+	dataClient := &realAlpacaDataClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second}, //nolint:exhaustruct // default transport
+		baseURL:    AlpacaDataURL,
+		apiKey:     config.ApiKey,
+		secretKey:  config.SecretKey,
+		feed:       feed,
+	}
+
+	return NewAlpacaClientWithEndpoints(dataClient, AlpacaStreamURL+feed, config.ApiKey, config.SecretKey, config.Symbols, config.Interval), nil
+}
+
+// NewAlpacaClientWithEndpoints creates an AlpacaClient with a custom REST client
+// and WebSocket URL. This is useful for testing with mock clients and servers.
+func NewAlpacaClientWithEndpoints(dataClient AlpacaDataClient, wsURL string, apiKey string, secretKey string, symbols []string, interval string) *AlpacaClient {
+	return &AlpacaClient{
+		dataClient:     dataClient,
+		wsURL:          wsURL,
+		apiKey:         apiKey,
+		secretKey:      secretKey,
+		writer:         nil,
+		onStatusChange: nil,
+		symbols:        symbols,
+		interval:       interval,
+		reconnect:      defaultReconnectPolicy(),
+	}
+}
+
+// GetSymbols returns the list of symbols configured for streaming.
+func (c *AlpacaClient) GetSymbols() []string {
+	return c.symbols
+}
+
+// GetInterval returns the candlestick interval configured for streaming.
+func (c *AlpacaClient) GetInterval() string {
+	return c.interval
+}
+
+func (c *AlpacaClient) ConfigWriter(w writer.MarketDataWriter) {
+	c.writer = w
+}
+
+// SetOnStatusChange sets a callback that will be called when the WebSocket connection
+// status changes (connected/disconnected/reconnecting).
+func (c *AlpacaClient) SetOnStatusChange(callback OnStatusChange) {
+	c.onStatusChange = callback
+}
+
+// Download downloads the bars of ticker between startDate and endDate to the
+// configured writer, one bars page at a time.
+func (c *AlpacaClient) Download(ctx context.Context, ticker string, startDate time.Time, endDate time.Time, multiplier int, timespan models.Timespan, onProgress OnDownloadProgress) (path string, err error) {
+	timeframe, err := convertTimespanToAlpacaTimeframe(timespan, multiplier)
+	if err != nil {
+		return "", err
+	}
+
+	if c.writer == nil {
+		return "", fmt.Errorf("writer is not configured")
+	}
+
+	if err := c.writer.Initialize(); err != nil {
+		return "", fmt.Errorf("failed to initialize writer: %w", err)
+	}
+
+	written := 0
+	pageToken := ""
+
+	for {
+		if err := ctx.Err(); err != nil {
+			if written == 0 {
+				c.cleanupFileIfExists()
+			}
+
+			return "", err
+		}
+
+		bars, nextPageToken, err := c.dataClient.GetBars(ctx, ticker, timeframe, startDate, endDate, pageToken)
+		if err != nil {
+			_, _ = c.writer.Finalize()
+
+			if written == 0 {
+				c.cleanupFileIfExists()
+			}
+
+			return "", fmt.Errorf("failed to fetch bars from Alpaca: %w", err)
+		}
+
+		for _, bar := range bars {
+			if err := c.writer.Write(convertAlpacaBarToMarketData(ticker, bar)); err != nil {
+				if written == 0 {
+					c.cleanupFileIfExists()
+				}
+
+				return "", fmt.Errorf("failed to write data: %w", err)
+			}
+
+			written++
+		}
+
+		if onProgress != nil && len(bars) > 0 {
+			onProgress(
+				float64(bars[len(bars)-1].Time.Sub(startDate).Milliseconds()),
+				float64(endDate.Sub(startDate).Milliseconds()),
+				fmt.Sprintf("Downloading %s bars from Alpaca", ticker),
+			)
+		}
+
+		if nextPageToken == "" {
+			break
+		}
+
+		pageToken = nextPageToken
+	}
+
+	outputPath, err := c.writer.Finalize()
+	if err != nil {
+		return "", fmt.Errorf("failed to finalize writer: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// GetHistoricalKlines returns the bars of symbol at interval that open between
+// start and end from the Alpaca bars endpoint, oldest first.
+func (c *AlpacaClient) GetHistoricalKlines(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]types.MarketData, error) {
+	multiplier, timespan, err := ParseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	timeframe, err := convertTimespanToAlpacaTimeframe(timespan, multiplier)
+	if err != nil {
+		return nil, err
+	}
+
+	var bars []types.MarketData
+
+	pageToken := ""
+
+	for {
+		page, nextPageToken, err := c.dataClient.GetBars(ctx, symbol, timeframe, start, end, pageToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch bars from Alpaca: %w", err)
+		}
+
+		for _, bar := range page {
+			bars = append(bars, convertAlpacaBarToMarketData(symbol, bar))
+		}
+
+		if nextPageToken == "" {
+			return bars, nil
+		}
+
+		pageToken = nextPageToken
+	}
+}
+
+// Stream implements Provider.Stream for live minute bars from the Alpaca
+// WebSocket. It authenticates, subscribes to the bars of all configured
+// symbols and yields each bar as it arrives. When an established connection
+// drops, the error is yielded and the symbols are resubscribed after a
+// backoff; bars replayed by the new connection are skipped. The iterator
+// terminates when the context is cancelled, the consumer stops, or the first
+// connection or every reconnect attempt fails.
+func (c *AlpacaClient) Stream(ctx context.Context) iter.Seq2[types.MarketData, error] {
+	return func(yield func(types.MarketData, error) bool) {
+		if len(c.symbols) == 0 {
+			//nolint:exhaustruct // empty struct for error case
+			yield(types.MarketData{}, fmt.Errorf("no symbols provided for streaming"))
+
+			return
+		}
+
+		if c.interval != AlpacaStreamInterval {
+			//nolint:exhaustruct // empty struct for error case
+			yield(types.MarketData{}, fmt.Errorf("invalid interval: %s (alpaca only streams %s bars)", c.interval, AlpacaStreamInterval))
+
+			return
+		}
+
+		// Bars replayed by a reconnected stream were already yielded
+		yield = dedupBars(yield)
+
+		connected := false
+		failures := 0
+
+		for {
+			established, stopped, err := c.streamConnection(ctx, yield)
+			if stopped || ctx.Err() != nil {
+				return
+			}
+
+			if established {
+				connected = true
+				failures = 0
+			} else {
+				failures++
+			}
+
+			//nolint:exhaustruct // empty struct for error case
+			if err != nil && !yield(types.MarketData{}, err) {
+				return
+			}
+
+			if !connected || failures >= c.reconnect.maxAttempts {
+				return
+			}
+
+			debugLog.Warn("Alpaca stream: reconnecting", zap.Int("failures", failures), zap.Error(err))
+			c.emitStatus(types.ProviderStatusReconnecting)
+
+			if !c.reconnect.wait(ctx, failures) {
+				return
+			}
+		}
+	}
+}
+
+// streamConnection runs a single WebSocket connection until it drops, the
+// context is cancelled or the consumer stops. It reports whether the
+// connection was authenticated and subscribed, whether streaming should stop,
+// and the error that ended the connection.
+func (c *AlpacaClient) streamConnection(ctx context.Context, yield func(types.MarketData, error) bool) (bool, bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsURL, nil)
+	if err != nil {
+		c.emitStatus(types.ProviderStatusDisconnected)
+
+		return false, false, fmt.Errorf("failed to connect to alpaca websocket: %w", err)
+	}
+	defer conn.Close()
+
+	if err := c.subscribe(conn); err != nil {
+		c.emitStatus(types.ProviderStatusDisconnected)
+
+		return false, false, err
+	}
+
+	c.emitStatus(types.ProviderStatusConnected)
+	defer c.emitStatus(types.ProviderStatusDisconnected)
+
+	// Unblock the read loop when the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		messages, err := readAlpacaMessages(conn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return true, true, nil
+			}
+
+			return true, false, fmt.Errorf("alpaca websocket connection lost: %w", err)
+		}
+
+		for _, message := range messages {
+			switch message.Type {
+			case "b":
+				if !yield(convertAlpacaBarToMarketData(message.Symbol, message.AlpacaBar), nil) {
+					return true, true, nil
+				}
+			case "error":
+				//nolint:exhaustruct // empty struct for error case
+				if !yield(types.MarketData{}, fmt.Errorf("alpaca websocket error %d: %s", message.Code, message.Message)) {
+					return true, true, nil
+				}
+			}
+		}
+	}
+}
+
+// subscribe authenticates the connection and subscribes to the bars of the
+// configured symbols, waiting for Alpaca to confirm each step.
+func (c *AlpacaClient) subscribe(conn *websocket.Conn) error {
+	steps := []struct {
+		action  alpacaStreamAction
+		confirm func(alpacaStreamMessage) bool
+	}{
+		{
+			action: alpacaStreamAction{Action: "auth", Key: c.apiKey, Secret: c.secretKey, Bars: nil},
+			confirm: func(message alpacaStreamMessage) bool {
+				return message.Type == "success" && message.Message == "authenticated"
+			},
+		},
+		{
+			action: alpacaStreamAction{Action: "subscribe", Key: "", Secret: "", Bars: c.symbols},
+			confirm: func(message alpacaStreamMessage) bool {
+				return message.Type == "subscription"
+			},
+		},
+	}
+
+	for _, step := range steps {
+		if err := conn.WriteJSON(step.action); err != nil {
+			return fmt.Errorf("failed to send alpaca %s message: %w", step.action.Action, err)
+		}
+
+		if err := awaitAlpacaConfirmation(conn, step.confirm); err != nil {
+			return fmt.Errorf("alpaca %s failed: %w", step.action.Action, err)
+		}
+	}
+
+	return nil
+}
+
+// awaitAlpacaConfirmation reads messages until one is confirmed. An error
+// message fails the wait.
+func awaitAlpacaConfirmation(conn *websocket.Conn, confirm func(alpacaStreamMessage) bool) error {
+	for {
+		messages, err := readAlpacaMessages(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if message.Type == "error" {
+				return fmt.Errorf("error %d: %s", message.Code, message.Message)
+			}
+
+			if confirm(message) {
+				return nil
+			}
+		}
+	}
+}
+
+// readAlpacaMessages reads one frame of messages from the connection. Messages
+// other than bars and control messages, such as trades, are skipped: JSON keys
+// match case-insensitively, so their fields would clash with those of a bar.
+func readAlpacaMessages(conn *websocket.Conn) ([]alpacaStreamMessage, error) {
+	_, payload, err := conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse alpaca message: %w", err)
+	}
+
+	messages := make([]alpacaStreamMessage, 0, len(raw))
+
+	for _, item := range raw {
+		// The timestamp key t is declared so that it does not fill the type
+		var header struct {
+			Type string          `json:"T"`
+			Time json.RawMessage `json:"t"`
+		}
+
+		if err := json.Unmarshal(item, &header); err != nil {
+			return nil, fmt.Errorf("failed to parse alpaca message: %w", err)
+		}
+
+		switch header.Type {
+		case "b", "error", "success", "subscription":
+		default:
+			continue
+		}
+
+		var message alpacaStreamMessage
+		if err := json.Unmarshal(item, &message); err != nil {
+			return nil, fmt.Errorf("failed to parse alpaca message: %w", err)
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// emitStatus emits a status change if a callback is registered.
+func (c *AlpacaClient) emitStatus(status types.ProviderConnectionStatus) {
+	if c.onStatusChange != nil {
+		c.onStatusChange(status)
+	}
+}
+
+// cleanupFileIfExists removes the output file of a download that failed
+// before any data was written.
+func (c *AlpacaClient) cleanupFileIfExists() {
+	if c.writer == nil {
+		return
+	}
+
+	outputPath := c.writer.GetOutputPath()
+	if outputPath == "" {
+		return
+	}
+
+	if _, err := os.Stat(outputPath); err == nil {
+		if removeErr := os.Remove(outputPath); removeErr != nil {
+			log.Printf("Warning: failed to remove file %s: %v", outputPath, removeErr)
+		}
+	}
+}
+
+// convertTimespanToAlpacaTimeframe converts a timespan and multiplier to an
+// Alpaca bar timeframe such as 15Min.
+func convertTimespanToAlpacaTimeframe(timespan models.Timespan, multiplier int) (string, error) {
+	if multiplier <= 0 {
+		return "", fmt.Errorf("invalid multiplier for Alpaca: %d", multiplier)
+	}
+
+	switch timespan {
+	case models.Minute:
+		if multiplier > 59 {
+			return "", fmt.Errorf("unsupported minute multiplier for Alpaca: %d", multiplier)
+		}
+
+		return fmt.Sprintf("%dMin", multiplier), nil
+	case models.Hour:
+		if multiplier > 23 {
+			return "", fmt.Errorf("unsupported hour multiplier for Alpaca: %d", multiplier)
+		}
+
+		return fmt.Sprintf("%dHour", multiplier), nil
+	case models.Day:
+		if multiplier != 1 {
+			return "", fmt.Errorf("unsupported daily multiplier for Alpaca: %d", multiplier)
+		}
+
+		return "1Day", nil
+	case models.Week:
+		if multiplier != 1 {
+			return "", fmt.Errorf("unsupported weekly multiplier for Alpaca: %d", multiplier)
+		}
+
+		return "1Week", nil
+	case models.Month:
+		switch multiplier {
+		case 1, 2, 3, 4, 6, 12:
+			return fmt.Sprintf("%dMonth", multiplier), nil
+		default:
+			return "", fmt.Errorf("unsupported monthly multiplier for Alpaca: %d", multiplier)
+		}
+	default:
+		return "", fmt.Errorf("unsupported timespan for Alpaca: %s", timespan)
+	}
+}
+
+// convertAlpacaBarToMarketData converts an Alpaca bar of symbol to MarketData.
+func convertAlpacaBarToMarketData(symbol string, bar AlpacaBar) types.MarketData {
+	return types.MarketData{
+		Id:     "",
+		Symbol: symbol,
+		Time:   bar.Time.UTC(),
+		Open:   bar.Open,
+		High:   bar.High,
+		Low:    bar.Low,
+		Close:  bar.Close,
+		Volume: bar.Volume,
+		Bid:    0,
+		Ask:    0,
+	}
+}
@@ -0,0 +1,411 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/polygon-io/client-go/rest/models"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type AlpacaProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestAlpacaProviderSuite(t *testing.T) {
+	suite.Run(t, new(AlpacaProviderTestSuite))
+}
+
+// alpacaBarRequest records the arguments of a GetBars call.
+type alpacaBarRequest struct {
+	symbol    string
+	timeframe string
+	pageToken string
+}
+
+// mockAlpacaDataClient implements AlpacaDataClient by serving one page per call.
+type mockAlpacaDataClient struct {
+	pages    [][]AlpacaBar
+	err      error
+	requests []alpacaBarRequest
+}
+
+func (m *mockAlpacaDataClient) GetBars(_ context.Context, symbol string, timeframe string, _, _ time.Time, pageToken string) ([]AlpacaBar, string, error) {
+	m.requests = append(m.requests, alpacaBarRequest{symbol: symbol, timeframe: timeframe, pageToken: pageToken})
+	if m.err != nil {
+		return nil, "", m.err
+	}
+
+	page := len(m.requests) - 1
+	if page >= len(m.pages) {
+		return nil, "", nil
+	}
+
+	nextPageToken := ""
+	if page < len(m.pages)-1 {
+		nextPageToken = fmt.Sprintf("page-%d", page+1)
+	}
+
+	return m.pages[page], nextPageToken, nil
+}
+
+// alpacaBarMessage builds a bars channel frame for a single minute bar.
+func alpacaBarMessage(symbol string, start time.Time, open, high, low, closePrice, volume float64) string {
+	return fmt.Sprintf(`[{"T":"b","S":"%s","o":%g,"h":%g,"l":%g,"c":%g,"v":%g,"t":"%s"}]`,
+		symbol, open, high, low, closePrice, volume, start.Format(time.RFC3339))
+}
+
+// mockAlpacaServer is a WebSocket server that replays one list of messages per connection.
+type mockAlpacaServer struct {
+	server      *httptest.Server
+	mu          sync.Mutex
+	connections int
+	actions     []alpacaStreamAction
+}
+
+// newMockAlpacaServer starts a server. Each connection confirms the auth and
+// subscribe messages and then writes the messages of its session. After the
+// last session the connection is kept open until the client disconnects.
+func newMockAlpacaServer(sessions ...[]string) *mockAlpacaServer {
+	mock := &mockAlpacaServer{}
+	upgrader := websocket.Upgrader{CheckOrigin: func(_ *http.Request) bool { return true }}
+
+	mock.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mock.mu.Lock()
+		session := mock.connections
+		mock.connections++
+		mock.mu.Unlock()
+
+		replies := []string{
+			`[{"T":"success","msg":"authenticated"}]`,
+			`[{"T":"subscription","trades":[],"quotes":[],"bars":["AAPL"]}]`,
+		}
+
+		for _, reply := range replies {
+			var action alpacaStreamAction
+			if err := conn.ReadJSON(&action); err != nil {
+				return
+			}
+
+			mock.mu.Lock()
+			mock.actions = append(mock.actions, action)
+			mock.mu.Unlock()
+
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(reply)); err != nil {
+				return
+			}
+		}
+
+		if session < len(sessions) {
+			for _, message := range sessions[session] {
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+					return
+				}
+			}
+
+			if session < len(sessions)-1 {
+				// Drop the connection to force a reconnect
+				return
+			}
+		}
+
+		// Keep the connection open until the client closes it
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return mock
+}
+
+func (m *mockAlpacaServer) url() string {
+	return "ws" + strings.TrimPrefix(m.server.URL, "http")
+}
+
+func (suite *AlpacaProviderTestSuite) TestStreamBars() {
+	start := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	server := newMockAlpacaServer([]string{
+		alpacaBarMessage("AAPL", start, 185.1, 185.4, 184.9, 185.3, 12000),
+		`[{"T":"t","S":"AAPL","p":185.3,"s":100,"t":"2024-01-02T14:31:01Z"}]`,
+		alpacaBarMessage("AAPL", start.Add(time.Minute), 185.3, 185.6, 185.2, 185.5, 8000),
+	})
+	defer server.server.Close()
+
+	client := NewAlpacaClientWithEndpoints(&mockAlpacaDataClient{}, server.url(), "key", "secret", []string{"AAPL"}, "1m")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var received []types.MarketData
+
+	for data, err := range client.Stream(ctx) {
+		suite.Require().NoError(err)
+
+		received = append(received, data)
+		if len(received) == 2 {
+			break
+		}
+	}
+
+	suite.Require().Len(received, 2)
+	suite.Equal(types.MarketData{
+		Id:     "",
+		Symbol: "AAPL",
+		Time:   start,
+		Open:   185.1,
+		High:   185.4,
+		Low:    184.9,
+		Close:  185.3,
+		Volume: 12000,
+	}, received[0])
+	suite.Equal(start.Add(time.Minute), received[1].Time)
+	suite.Equal(185.5, received[1].Close)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	suite.Equal([]alpacaStreamAction{
+		{Action: "auth", Key: "key", Secret: "secret"},
+		{Action: "subscribe", Bars: []string{"AAPL"}},
+	}, server.actions)
+}
+
+func (suite *AlpacaProviderTestSuite) TestStreamReconnectsAfterDrop() {
+	start := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	server := newMockAlpacaServer(
+		[]string{
+			alpacaBarMessage("AAPL", start, 185.1, 185.4, 184.9, 185.3, 12000),
+		},
+		[]string{
+			// The bar replayed by the new connection is skipped
+			alpacaBarMessage("AAPL", start, 185.1, 185.4, 184.9, 185.3, 12000),
+			alpacaBarMessage("AAPL", start.Add(time.Minute), 185.3, 185.6, 185.2, 185.5, 8000),
+		},
+	)
+	defer server.server.Close()
+
+	client := NewAlpacaClientWithEndpoints(&mockAlpacaDataClient{}, server.url(), "key", "secret", []string{"AAPL"}, "1m")
+	client.reconnect.baseDelay = time.Millisecond
+
+	var mu sync.Mutex
+
+	var statuses []types.ProviderConnectionStatus
+
+	client.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		statuses = append(statuses, status)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var received []types.MarketData
+
+	var streamErrors []error
+
+	for data, err := range client.Stream(ctx) {
+		if err != nil {
+			streamErrors = append(streamErrors, err)
+
+			continue
+		}
+
+		received = append(received, data)
+		if len(received) == 2 {
+			break
+		}
+	}
+
+	suite.Require().Len(received, 2)
+	suite.Equal(start, received[0].Time)
+	suite.Equal(start.Add(time.Minute), received[1].Time)
+
+	suite.Require().Len(streamErrors, 1)
+	suite.Contains(streamErrors[0].Error(), "alpaca websocket connection lost")
+
+	mu.Lock()
+	defer mu.Unlock()
+	suite.Equal([]types.ProviderConnectionStatus{
+		types.ProviderStatusConnected,
+		types.ProviderStatusDisconnected,
+		types.ProviderStatusReconnecting,
+		types.ProviderStatusConnected,
+		types.ProviderStatusDisconnected,
+	}, statuses)
+}
+
+func (suite *AlpacaProviderTestSuite) TestStreamAuthError() {
+	upgrader := websocket.Upgrader{CheckOrigin: func(_ *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var action alpacaStreamAction
+		if err := conn.ReadJSON(&action); err != nil {
+			return
+		}
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`[{"T":"error","code":402,"msg":"auth failed"}]`))
+	}))
+	defer server.Close()
+
+	client := NewAlpacaClientWithEndpoints(&mockAlpacaDataClient{}, "ws"+strings.TrimPrefix(server.URL, "http"), "key", "bad", []string{"AAPL"}, "1m")
+
+	var errs []error
+
+	for _, err := range client.Stream(context.Background()) {
+		errs = append(errs, err)
+	}
+
+	// A connection that never authenticated is not retried
+	suite.Require().Len(errs, 1)
+	suite.Contains(errs[0].Error(), "alpaca auth failed")
+	suite.Contains(errs[0].Error(), "auth failed")
+}
+
+func (suite *AlpacaProviderTestSuite) TestStreamInvalidConfig() {
+	tests := []struct {
+		name     string
+		symbols  []string
+		interval string
+		errMsg   string
+	}{
+		{"empty symbols", nil, "1m", "no symbols provided"},
+		{"unsupported interval", []string{"AAPL"}, "5m", "invalid interval"},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			client := NewAlpacaClientWithEndpoints(&mockAlpacaDataClient{}, "ws://127.0.0.1:1", "key", "secret", tc.symbols, tc.interval)
+
+			var errs []error
+
+			for _, err := range client.Stream(context.Background()) {
+				errs = append(errs, err)
+			}
+
+			suite.Require().Len(errs, 1)
+			suite.Contains(errs[0].Error(), tc.errMsg)
+		})
+	}
+}
+
+func (suite *AlpacaProviderTestSuite) TestGetHistoricalKlines_Paginates() {
+	start := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	dataClient := &mockAlpacaDataClient{pages: [][]AlpacaBar{
+		{{Time: start, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 100}},
+		{{Time: start.Add(15 * time.Minute), Open: 1.5, High: 2.5, Low: 1, Close: 2, Volume: 200}},
+	}}
+	client := NewAlpacaClientWithEndpoints(dataClient, "", "key", "secret", []string{"AAPL"}, "1m")
+
+	bars, err := client.GetHistoricalKlines(context.Background(), "AAPL", "15m", start, start.Add(time.Hour))
+	suite.Require().NoError(err)
+	suite.Require().Len(bars, 2)
+	suite.Equal("AAPL", bars[0].Symbol)
+	suite.Equal(start, bars[0].Time)
+	suite.Equal(2.0, bars[1].Close)
+
+	suite.Equal([]alpacaBarRequest{
+		{symbol: "AAPL", timeframe: "15Min", pageToken: ""},
+		{symbol: "AAPL", timeframe: "15Min", pageToken: "page-1"},
+	}, dataClient.requests)
+
+	_, err = client.GetHistoricalKlines(context.Background(), "AAPL", "30s", start, start.Add(time.Hour))
+	suite.ErrorContains(err, "unsupported timespan")
+
+	dataClient.err = errors.New("forbidden")
+	_, err = client.GetHistoricalKlines(context.Background(), "AAPL", "1m", start, start.Add(time.Hour))
+	suite.ErrorContains(err, "failed to fetch bars from Alpaca")
+}
+
+func (suite *AlpacaProviderTestSuite) TestDownload() {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	dataClient := &mockAlpacaDataClient{pages: [][]AlpacaBar{
+		{{Time: start, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 100}},
+		{{Time: start.AddDate(0, 0, 1), Open: 1.5, High: 2.5, Low: 1, Close: 2, Volume: 200}},
+	}}
+	client := NewAlpacaClientWithEndpoints(dataClient, "", "key", "secret", nil, "")
+	mockW := &mockWriter{outputPath: "/tmp/alpaca.parquet"}
+	client.ConfigWriter(mockW)
+
+	path, err := client.Download(context.Background(), "AAPL", start, start.AddDate(0, 0, 2), 1, models.Day, nil)
+	suite.Require().NoError(err)
+	suite.Equal("/tmp/alpaca.parquet", path)
+	suite.Len(mockW.writtenData, 2)
+	suite.Equal("1Day", dataClient.requests[0].timeframe)
+	suite.Equal(1, mockW.finalizeCallCount)
+}
+
+func (suite *AlpacaProviderTestSuite) TestConvertTimespanToAlpacaTimeframe() {
+	tests := []struct {
+		timespan   models.Timespan
+		multiplier int
+		expected   string
+		errMsg     string
+	}{
+		{models.Minute, 1, "1Min", ""},
+		{models.Minute, 60, "", "unsupported minute multiplier"},
+		{models.Hour, 4, "4Hour", ""},
+		{models.Day, 1, "1Day", ""},
+		{models.Day, 2, "", "unsupported daily multiplier"},
+		{models.Week, 1, "1Week", ""},
+		{models.Month, 3, "3Month", ""},
+		{models.Month, 5, "", "unsupported monthly multiplier"},
+		{models.Second, 1, "", "unsupported timespan"},
+	}
+
+	for _, tc := range tests {
+		suite.Run(fmt.Sprintf("%d %s", tc.multiplier, tc.timespan), func() {
+			timeframe, err := convertTimespanToAlpacaTimeframe(tc.timespan, tc.multiplier)
+			if tc.errMsg != "" {
+				suite.ErrorContains(err, tc.errMsg)
+
+				return
+			}
+
+			suite.NoError(err)
+			suite.Equal(tc.expected, timeframe)
+		})
+	}
+}
+
+func (suite *AlpacaProviderTestSuite) TestNewMarketDataProvider() {
+	p, err := NewMarketDataProvider(ProviderAlpaca, &AlpacaStreamConfig{
+		BaseStreamConfig: BaseStreamConfig{Symbols: []string{"AAPL"}, Interval: "1m"},
+		ApiKey:           "key",
+		SecretKey:        "secret",
+	})
+	suite.Require().NoError(err)
+	suite.Equal([]string{"AAPL"}, p.GetSymbols())
+	suite.Equal("1m", p.GetInterval())
+
+	_, err = NewMarketDataProvider(ProviderAlpaca, &CoinbaseStreamConfig{})
+	suite.Error(err)
+
+	_, err = ParseAlpacaStreamConfig(`{"symbols":["AAPL"],"interval":"5m","apiKey":"key","secretKey":"secret"}`)
+	suite.ErrorContains(err, "alpaca only supports the 1m interval")
+
+	_, err = ParseAlpacaStreamConfig(`{"symbols":["AAPL"],"interval":"1m","apiKey":"key","secretKey":"secret","feed":"otc"}`)
+	suite.Error(err)
+}
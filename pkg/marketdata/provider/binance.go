@@ -209,6 +209,7 @@ type BinanceClient struct {
 	onStatusChange OnStatusChange
 	symbols        []string
 	interval       string
+	reconnect      reconnectPolicy
 }
 
 func NewBinanceClient(config *BinanceStreamConfig) (Provider, error) {
@@ -236,6 +237,7 @@ func NewBinanceClient(config *BinanceStreamConfig) (Provider, error) {
 		onStatusChange: nil,
 		symbols:        config.Symbols,
 		interval:       config.Interval,
+		reconnect:      defaultReconnectPolicy(),
 	}, nil
 }
 
@@ -248,6 +250,7 @@ func NewBinanceClientWithAPI(apiClient BinanceAPIClient, symbols []string, inter
 		onStatusChange: nil,
 		symbols:        symbols,
 		interval:       interval,
+		reconnect:      defaultReconnectPolicy(),
 	}
 }
 
@@ -260,6 +263,7 @@ func NewBinanceClientWithWebSocket(apiClient BinanceAPIClient, wsService Binance
 		onStatusChange: nil,
 		symbols:        symbols,
 		interval:       interval,
+		reconnect:      defaultReconnectPolicy(),
 	}
 }
 
@@ -291,6 +295,7 @@ func NewBinanceClientWithEndpoints(config BinanceEndpointConfig, symbols []strin
 		onStatusChange: nil,
 		symbols:        symbols,
 		interval:       interval,
+		reconnect:      defaultReconnectPolicy(),
 	}, nil
 }
 
@@ -484,6 +489,48 @@ func (c *BinanceClient) Download(ctx context.Context, ticker string, startDate t
 	return outputPath, nil
 }
 
+// GetHistoricalKlines implements Provider.GetHistoricalKlines with the klines REST
+// endpoint. Binance returns at most 1000 klines per request, so longer ranges are
+// fetched page by page, each page starting after the close of the previous one.
+func (c *BinanceClient) GetHistoricalKlines(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]types.MarketData, error) {
+	if !isValidBinanceInterval(interval) {
+		return nil, fmt.Errorf("unsupported interval for Binance: %s", interval)
+	}
+
+	endTimeMillis := end.UnixMilli()
+	currentStartTime := start.UnixMilli()
+
+	var bars []types.MarketData
+
+	for currentStartTime <= endTimeMillis {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		klines, err := c.apiClient.NewKlinesService().
+			Symbol(symbol).
+			Interval(interval).
+			StartTime(currentStartTime).
+			EndTime(endTimeMillis).
+			Limit(binanceKlinesLimit).
+			Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch klines from Binance: %w", err)
+		}
+
+		bars = append(bars, convertKlines(symbol, klines)...)
+
+		// A page that is not full is the last one
+		if len(klines) < binanceKlinesLimit {
+			break
+		}
+
+		currentStartTime = klines[len(klines)-1].CloseTime + 1
+	}
+
+	return bars, nil
+}
+
 // processKlines converts Binance kline data to our internal MarketData format and writes it.
 // When the writer implements writer.BatchWriter, all rows in a page are persisted in a
 // single batch call — much faster for bulk download because writers like the streaming
@@ -493,7 +540,28 @@ func processKlines(w writer.MarketDataWriter, ticker string, klines []*binance.K
 		return nil
 	}
 
-	batch := make([]types.MarketData, 0, len(klines))
+	batch := convertKlines(ticker, klines)
+
+	if bw, ok := w.(writer.BatchWriter); ok {
+		if err := bw.WriteBatch(batch); err != nil {
+			return fmt.Errorf("failed to write market data batch: %w", err)
+		}
+
+		return nil
+	}
+
+	for _, md := range batch {
+		if err := w.Write(md); err != nil {
+			return fmt.Errorf("failed to write market data: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// convertKlines converts Binance klines of ticker to our internal MarketData format.
+func convertKlines(ticker string, klines []*binance.Kline) []types.MarketData {
+	bars := make([]types.MarketData, 0, len(klines))
 
 	for _, k := range klines {
 		open, _ := strconv.ParseFloat(k.Open, 64)
@@ -502,7 +570,7 @@ func processKlines(w writer.MarketDataWriter, ticker string, klines []*binance.K
 		closePrice, _ := strconv.ParseFloat(k.Close, 64)
 		volume, _ := strconv.ParseFloat(k.Volume, 64)
 
-		batch = append(batch, types.MarketData{
+		bars = append(bars, types.MarketData{
 			Id:     "",
 			Symbol: ticker,
 			Time:   time.UnixMilli(k.OpenTime), // Using OpenTime as the timestamp for the bar
@@ -511,25 +579,13 @@ func processKlines(w writer.MarketDataWriter, ticker string, klines []*binance.K
 			Low:    low,
 			Close:  closePrice,
 			Volume: volume,
+			Bid:    0,
+			Ask:    0,
 			// VWAP and N (trade count) might not be directly available in standard klines
 		})
 	}
 
-	if bw, ok := w.(writer.BatchWriter); ok {
-		if err := bw.WriteBatch(batch); err != nil {
-			return fmt.Errorf("failed to write market data batch: %w", err)
-		}
-
-		return nil
-	}
-
-	for _, md := range batch {
-		if err := w.Write(md); err != nil {
-			return fmt.Errorf("failed to write market data: %w", err)
-		}
-	}
-
-	return nil
+	return bars
 }
 
 // convertTimespanToBinanceInterval converts the polygon timespan and multiplier to a Binance interval string.
@@ -619,6 +675,11 @@ func (c *BinanceClient) Stream(ctx context.Context) iter.Seq2[types.MarketData,
 			}
 		}
 
+		// sessionCtx stops the per-symbol goroutines, including their reconnects,
+		// when the stream ends
+		sessionCtx, stopSessions := context.WithCancel(ctx)
+		defer stopSessions()
+
 		// Start WebSocket connection for each symbol
 		for _, symbol := range symbols {
 			wg.Add(1)
@@ -650,41 +711,69 @@ func (c *BinanceClient) Stream(ctx context.Context) iter.Seq2[types.MarketData,
 					}
 				}
 
-				doneC, stopC, err := c.wsService.WsKlineServe(sym, interval, handler, errHandler)
-				if err != nil {
-					debugLog.Warn("Stream: WebSocket connection FAILED", zap.String("symbol", sym), zap.Error(err))
-
-					select {
-					case errChan <- fmt.Errorf("failed to start websocket for %s: %w", sym, err):
-					default:
+				// connected is set once the symbol's stream was established. Only a
+				// dropped connection is reconnected, a symbol that cannot connect at
+				// all ends its stream.
+				connected := false
+				failures := 0
+
+				for {
+					doneC, stopC, err := c.wsService.WsKlineServe(sym, interval, handler, errHandler)
+					if err != nil {
+						debugLog.Warn("Stream: WebSocket connection FAILED", zap.String("symbol", sym), zap.Error(err))
+
+						select {
+						case errChan <- fmt.Errorf("failed to start websocket for %s: %w", sym, err):
+						default:
+						}
+
+						// Emit disconnected status on connection failure
+						c.emitStatus(types.ProviderStatusDisconnected)
+
+						failures++
+						if !connected || failures >= c.reconnect.maxAttempts {
+							return
+						}
+					} else {
+						debugLog.Info("Stream: WebSocket connection ESTABLISHED", zap.String("symbol", sym))
+
+						// Emit connected status when WebSocket connection is established
+						c.emitStatus(types.ProviderStatusConnected)
+
+						connected = true
+						failures = 0
+
+						entry := &stopChanEntry{ch: stopC, closed: false}
+
+						mu.Lock()
+						stopChannels = append(stopChannels, entry)
+						mu.Unlock()
+
+						// Wait for the stream to stop or the connection to close
+						select {
+						case <-sessionCtx.Done():
+							safeStop(entry)
+							// Emit disconnected status when connection is closed
+							c.emitStatus(types.ProviderStatusDisconnected)
+
+							return
+						case <-doneC:
+							// Emit disconnected status when connection is closed
+							c.emitStatus(types.ProviderStatusDisconnected)
+						}
+
+						if sessionCtx.Err() != nil {
+							return
+						}
 					}
 
-					// Emit disconnected status on connection failure
-					c.emitStatus(types.ProviderStatusDisconnected)
+					// The connection dropped, resubscribe the symbol after a backoff
+					debugLog.Info("Stream: WebSocket RECONNECTING", zap.String("symbol", sym), zap.Int("failures", failures))
+					c.emitStatus(types.ProviderStatusReconnecting)
 
-					return
-				}
-
-				debugLog.Info("Stream: WebSocket connection ESTABLISHED", zap.String("symbol", sym))
-
-				// Emit connected status when WebSocket connection is established
-				c.emitStatus(types.ProviderStatusConnected)
-
-				entry := &stopChanEntry{ch: stopC, closed: false}
-
-				mu.Lock()
-				stopChannels = append(stopChannels, entry)
-				mu.Unlock()
-
-				// Wait for context cancellation or connection close
-				select {
-				case <-ctx.Done():
-					safeStop(entry)
-					// Emit disconnected status when connection is closed
-					c.emitStatus(types.ProviderStatusDisconnected)
-				case <-doneC:
-					// Emit disconnected status when connection is closed
-					c.emitStatus(types.ProviderStatusDisconnected)
+					if !c.reconnect.wait(sessionCtx, failures) {
+						return
+					}
 				}
 			}(symbol)
 		}
@@ -701,6 +790,8 @@ func (c *BinanceClient) Stream(ctx context.Context) iter.Seq2[types.MarketData,
 
 		// Cleanup function - stops all connections and closes channels
 		cleanup := func() {
+			stopSessions()
+
 			mu.Lock()
 			channels := make([]*stopChanEntry, len(stopChannels))
 			copy(channels, stopChannels)
@@ -763,7 +854,8 @@ func (c *BinanceClient) Stream(ctx context.Context) iter.Seq2[types.MarketData,
 			}
 		}()
 
-		yieldLoop(ctx, allDone, dataChan, errChan, yield)
+		// Bars replayed by a reconnected stream were already yielded
+		yieldLoop(ctx, allDone, dataChan, errChan, dedupBars(yield))
 	}
 }
 
@@ -854,6 +946,8 @@ func convertWsKlineToMarketData(event *BinanceWsKlineEvent) types.MarketData {
 		Low:    low,
 		Close:  closePrice,
 		Volume: volume,
+		Bid:    0,
+		Ask:    0,
 	}
 }
 
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -617,3 +618,116 @@ func (suite *BinanceStreamTestSuite) TestStreamEmitsDisconnectedOnError() {
 	// Should have received disconnected status on failure
 	suite.Contains(statusChanges, types.ProviderStatusDisconnected, "Should have received disconnected status on connection error")
 }
+
+// reconnectingBinanceWebSocketService serves one session of events per
+// WsKlineServe call. Every session but the last drops its connection after
+// its events, the last one stays open until it is stopped.
+type reconnectingBinanceWebSocketService struct {
+	mu       sync.Mutex
+	sessions [][]*BinanceWsKlineEvent
+	serves   int
+}
+
+func (m *reconnectingBinanceWebSocketService) WsKlineServe(
+	symbol string,
+	interval string,
+	handler WsKlineHandler,
+	errHandler WsErrorHandler,
+) (doneC chan struct{}, stopC chan struct{}, err error) {
+	m.mu.Lock()
+	session := min(m.serves, len(m.sessions)-1)
+	m.serves++
+	m.mu.Unlock()
+
+	doneC = make(chan struct{})
+	stopC = make(chan struct{})
+
+	go func() {
+		defer close(doneC)
+
+		for _, event := range m.sessions[session] {
+			handler(event)
+		}
+
+		if session < len(m.sessions)-1 {
+			errHandler(errors.New("connection reset by peer"))
+
+			return
+		}
+
+		<-stopC
+	}()
+
+	return doneC, stopC, nil
+}
+
+func finalKline(symbol string, startTime int64, closePrice string) *BinanceWsKlineEvent {
+	return &BinanceWsKlineEvent{
+		Symbol: symbol,
+		Kline: BinanceWsKline{
+			StartTime: startTime,
+			Open:      closePrice,
+			High:      closePrice,
+			Low:       closePrice,
+			Close:     closePrice,
+			Volume:    "1.0",
+			IsFinal:   true,
+		},
+	}
+}
+
+func (suite *BinanceStreamTestSuite) TestStreamReconnectsAfterDrop() {
+	mockWs := &reconnectingBinanceWebSocketService{
+		sessions: [][]*BinanceWsKlineEvent{
+			{finalKline("BTCUSDT", 1704067200000, "100"), finalKline("BTCUSDT", 1704067260000, "101")},
+			// The resubscribed stream replays the last bar before the connection dropped
+			{finalKline("BTCUSDT", 1704067260000, "101"), finalKline("BTCUSDT", 1704067320000, "102"), finalKline("BTCUSDT", 1704067380000, "103")},
+		},
+	}
+	client := NewBinanceClientWithWebSocket(&mockStreamAPIClient{}, mockWs, []string{"BTCUSDT"}, "1m")
+	client.reconnect = reconnectPolicy{maxAttempts: 3, baseDelay: 10 * time.Millisecond, maxDelay: 50 * time.Millisecond}
+
+	var mu sync.Mutex
+
+	var statusChanges []types.ProviderConnectionStatus
+
+	client.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		statusChanges = append(statusChanges, status)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var closes []float64
+
+	var streamErrors []error
+
+	for data, err := range client.Stream(ctx) {
+		if err != nil {
+			streamErrors = append(streamErrors, err)
+
+			continue
+		}
+
+		closes = append(closes, data.Close)
+		if len(closes) == 4 {
+			break
+		}
+	}
+
+	suite.Equal([]float64{100, 101, 102, 103}, closes, "bars continue across the reconnect without duplicates")
+	suite.Equal(2, mockWs.serves, "the symbol is resubscribed once")
+
+	for _, err := range streamErrors {
+		suite.Contains(err.Error(), "connection reset by peer")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	suite.Contains(statusChanges, types.ProviderStatusReconnecting)
+	suite.Equal(types.ProviderStatusConnected, statusChanges[0])
+}
@@ -1359,3 +1359,63 @@ func (suite *BinanceClientTestSuite) TestDownloadNilProgressCallbackPagination()
 		suite.Equal(2, mockAPI.callCount)
 	})
 }
+
+// TestGetHistoricalKlinesPagination tests that a full first page is followed by
+// a request for the next page and that the pages are returned in order.
+func (suite *BinanceClientTestSuite) TestGetHistoricalKlinesPagination() {
+	startMillis := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	kline := func(i int) *binance.Kline {
+		openTime := startMillis + int64(i)*60000
+
+		return &binance.Kline{
+			OpenTime:  openTime,
+			Open:      "42000.00",
+			High:      "42100.00",
+			Low:       "41900.00",
+			Close:     "42050.00",
+			Volume:    "10",
+			CloseTime: openTime + 59999,
+		}
+	}
+
+	firstPage := make([]*binance.Kline, binanceKlinesLimit)
+	for i := range firstPage {
+		firstPage[i] = kline(i)
+	}
+
+	secondPage := []*binance.Kline{kline(binanceKlinesLimit), kline(binanceKlinesLimit + 1)}
+
+	mockAPI := &mockBinanceAPIClient{
+		klinesPerCall: [][]*binance.Kline{firstPage, secondPage},
+	}
+	client := NewBinanceClientWithAPI(mockAPI, []string{"BTCUSDT"}, "1m")
+
+	bars, err := client.GetHistoricalKlines(
+		context.Background(),
+		"BTCUSDT",
+		"1m",
+		time.UnixMilli(startMillis),
+		time.UnixMilli(startMillis+int64(binanceKlinesLimit+2)*60000),
+	)
+	suite.Require().NoError(err)
+	suite.Equal(2, mockAPI.callCount)
+	suite.Require().Len(bars, binanceKlinesLimit+2)
+
+	for i, bar := range bars {
+		suite.Equal("BTCUSDT", bar.Symbol)
+		suite.Equal(time.UnixMilli(startMillis+int64(i)*60000), bar.Time)
+	}
+}
+
+func (suite *BinanceClientTestSuite) TestGetHistoricalKlinesErrors() {
+	client := NewBinanceClientWithAPI(&mockBinanceAPIClient{}, []string{"BTCUSDT"}, "1m")
+
+	_, err := client.GetHistoricalKlines(context.Background(), "BTCUSDT", "7m", time.Now().Add(-time.Hour), time.Now())
+	suite.Error(err)
+
+	client = NewBinanceClientWithAPI(&mockBinanceAPIClient{klinesErr: errors.New("API error")}, []string{"BTCUSDT"}, "1m")
+
+	_, err = client.GetHistoricalKlines(context.Background(), "BTCUSDT", "1m", time.Now().Add(-time.Hour), time.Now())
+	suite.ErrorContains(err, "API error")
+}
@@ -0,0 +1,327 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/polygon-io/client-go/rest/models"
+	"go.uber.org/zap"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
+)
+
+const (
+	// CoinbaseWsURL is the public Coinbase Advanced Trade market data WebSocket endpoint.
+	CoinbaseWsURL = "wss://advanced-trade-ws.coinbase.com"
+	// CoinbaseCandleInterval is the only candle granularity published on the
+	// Coinbase candles channel.
+	CoinbaseCandleInterval = "5m"
+	// DefaultCoinbaseReconnectDelay is the wait before reconnecting after the
+	// WebSocket connection drops.
+	DefaultCoinbaseReconnectDelay = 2 * time.Second
+)
+
+// coinbaseSubscribeMessage subscribes to a channel for a set of products.
+type coinbaseSubscribeMessage struct {
+	Type       string   `json:"type"`
+	ProductIDs []string `json:"product_ids"`
+	Channel    string   `json:"channel"`
+}
+
+// coinbaseMessage is a message received from the Coinbase WebSocket.
+type coinbaseMessage struct {
+	Type    string          `json:"type"`
+	Message string          `json:"message"`
+	Channel string          `json:"channel"`
+	Events  []coinbaseEvent `json:"events"`
+}
+
+// coinbaseEvent is a snapshot or update event of the candles channel.
+type coinbaseEvent struct {
+	Type    string           `json:"type"`
+	Candles []CoinbaseCandle `json:"candles"`
+}
+
+// CoinbaseCandle is a candle from the Coinbase candles channel.
+// Numeric values are sent as strings; Start is the candle start in unix seconds.
+type CoinbaseCandle struct {
+	Start     string `json:"start"`
+	High      string `json:"high"`
+	Low       string `json:"low"`
+	Open      string `json:"open"`
+	Close     string `json:"close"`
+	Volume    string `json:"volume"`
+	ProductID string `json:"product_id"`
+}
+
+// CoinbaseClient streams candles for Coinbase products (e.g. BTC-USD).
+// Coinbase updates the current candle continuously; a candle is emitted once
+// the next candle of the same product starts, so only complete candles are yielded.
+// Dropped connections are reconnected until the context is cancelled.
+type CoinbaseClient struct {
+	wsURL          string
+	reconnectDelay time.Duration
+	writer         writer.MarketDataWriter
+	onStatusChange OnStatusChange
+	symbols        []string
+	interval       string
+}
+
+// NewCoinbaseClient creates a Coinbase market data provider.
+func NewCoinbaseClient(config *CoinbaseStreamConfig) (Provider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required for coinbase provider")
+	}
+
+	return NewCoinbaseClientWithEndpoint(CoinbaseWsURL, DefaultCoinbaseReconnectDelay, config.Symbols, config.Interval), nil
+}
+
+// NewCoinbaseClientWithEndpoint creates a CoinbaseClient connecting to a custom
+// WebSocket URL. This is useful for testing with mock servers.
+func NewCoinbaseClientWithEndpoint(wsURL string, reconnectDelay time.Duration, symbols []string, interval string) *CoinbaseClient {
+	return &CoinbaseClient{
+		wsURL:          wsURL,
+		reconnectDelay: reconnectDelay,
+		writer:         nil,
+		onStatusChange: nil,
+		symbols:        symbols,
+		interval:       interval,
+	}
+}
+
+// GetSymbols returns the list of product IDs configured for streaming.
+func (c *CoinbaseClient) GetSymbols() []string {
+	return c.symbols
+}
+
+// GetInterval returns the candlestick interval configured for streaming.
+func (c *CoinbaseClient) GetInterval() string {
+	return c.interval
+}
+
+func (c *CoinbaseClient) ConfigWriter(w writer.MarketDataWriter) {
+	c.writer = w
+}
+
+// SetOnStatusChange sets a callback that will be called when the WebSocket connection
+// status changes (connected/disconnected).
+func (c *CoinbaseClient) SetOnStatusChange(callback OnStatusChange) {
+	c.onStatusChange = callback
+}
+
+// Download is not supported by the Coinbase provider, which only streams live candles.
+func (c *CoinbaseClient) Download(_ context.Context, _ string, _ time.Time, _ time.Time, _ int, _ models.Timespan, _ OnDownloadProgress) (string, error) {
+	return "", fmt.Errorf("download is not supported by the coinbase provider")
+}
+
+// GetHistoricalKlines is not supported by the Coinbase provider, which only streams live candles.
+func (c *CoinbaseClient) GetHistoricalKlines(_ context.Context, _ string, _ string, _ time.Time, _ time.Time) ([]types.MarketData, error) {
+	return nil, fmt.Errorf("historical klines are not supported by the coinbase provider")
+}
+
+// Stream implements Provider.Stream for real-time candles from the Coinbase WebSocket.
+// It subscribes to the candles channel for all configured products and yields each
+// candle once it is complete. Connection errors are yielded and followed by a
+// reconnect after the reconnect delay. The iterator terminates when the context
+// is cancelled or the consumer stops.
+func (c *CoinbaseClient) Stream(ctx context.Context) iter.Seq2[types.MarketData, error] {
+	return func(yield func(types.MarketData, error) bool) {
+		if len(c.symbols) == 0 {
+			//nolint:exhaustruct // empty struct for error case
+			yield(types.MarketData{}, fmt.Errorf("no symbols provided for streaming"))
+
+			return
+		}
+
+		if c.interval != CoinbaseCandleInterval {
+			//nolint:exhaustruct // empty struct for error case
+			yield(types.MarketData{}, fmt.Errorf("invalid interval: %s (coinbase only streams %s candles)", c.interval, CoinbaseCandleInterval))
+
+			return
+		}
+
+		pending := map[string]types.MarketData{}
+
+		for {
+			stopped, err := c.streamConnection(ctx, pending, yield)
+			if stopped || ctx.Err() != nil {
+				return
+			}
+
+			//nolint:exhaustruct // empty struct for error case
+			if err != nil && !yield(types.MarketData{}, err) {
+				return
+			}
+
+			debugLog.Warn("Coinbase stream: reconnecting", zap.Duration("delay", c.reconnectDelay), zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.reconnectDelay):
+			}
+		}
+	}
+}
+
+// streamConnection runs a single WebSocket connection until it drops, the
+// context is cancelled or the consumer stops. It reports whether streaming
+// should stop, and the error that ended the connection otherwise.
+func (c *CoinbaseClient) streamConnection(ctx context.Context, pending map[string]types.MarketData, yield func(types.MarketData, error) bool) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsURL, nil)
+	if err != nil {
+		c.emitStatus(types.ProviderStatusDisconnected)
+
+		return false, fmt.Errorf("failed to connect to coinbase websocket: %w", err)
+	}
+	defer conn.Close()
+
+	// Heartbeats keep the connection open while candles are not changing
+	for _, channel := range []string{"candles", "heartbeats"} {
+		subscribe := coinbaseSubscribeMessage{Type: "subscribe", ProductIDs: c.symbols, Channel: channel}
+		if err := conn.WriteJSON(subscribe); err != nil {
+			c.emitStatus(types.ProviderStatusDisconnected)
+
+			return false, fmt.Errorf("failed to subscribe to coinbase %s channel: %w", channel, err)
+		}
+	}
+
+	c.emitStatus(types.ProviderStatusConnected)
+	defer c.emitStatus(types.ProviderStatusDisconnected)
+
+	// Unblock the read loop when the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return true, nil
+			}
+
+			return false, fmt.Errorf("coinbase websocket connection lost: %w", err)
+		}
+
+		candles, err := parseCoinbaseMessage(payload)
+		if err != nil {
+			//nolint:exhaustruct // empty struct for error case
+			if !yield(types.MarketData{}, err) {
+				return true, nil
+			}
+
+			continue
+		}
+
+		for _, candle := range candles {
+			completed, ok := trackCoinbaseCandle(pending, candle)
+			if ok && !yield(completed, nil) {
+				return true, nil
+			}
+		}
+	}
+}
+
+// parseCoinbaseMessage returns the candles of a candles channel message.
+// Other channels (subscriptions, heartbeats) yield no candles.
+func parseCoinbaseMessage(payload []byte) ([]types.MarketData, error) {
+	var message coinbaseMessage
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return nil, fmt.Errorf("failed to parse coinbase message: %w", err)
+	}
+
+	if message.Type == "error" {
+		return nil, fmt.Errorf("coinbase websocket error: %s", message.Message)
+	}
+
+	if message.Channel != "candles" {
+		return nil, nil
+	}
+
+	var candles []types.MarketData
+
+	for _, event := range message.Events {
+		for _, candle := range event.Candles {
+			marketData, err := convertCoinbaseCandleToMarketData(candle)
+			if err != nil {
+				return nil, err
+			}
+
+			candles = append(candles, marketData)
+		}
+	}
+
+	return candles, nil
+}
+
+// trackCoinbaseCandle records the latest state of a product's current candle.
+// When a newer candle starts, the previous one is complete and is returned.
+func trackCoinbaseCandle(pending map[string]types.MarketData, candle types.MarketData) (types.MarketData, bool) {
+	previous, ok := pending[candle.Symbol]
+	if ok && candle.Time.Before(previous.Time) {
+		// Stale update for a candle that has already been emitted
+		//nolint:exhaustruct // empty struct when nothing is completed
+		return types.MarketData{}, false
+	}
+
+	pending[candle.Symbol] = candle
+
+	if ok && candle.Time.After(previous.Time) {
+		return previous, true
+	}
+
+	//nolint:exhaustruct // empty struct when nothing is completed
+	return types.MarketData{}, false
+}
+
+// convertCoinbaseCandleToMarketData converts a Coinbase candle to MarketData.
+// The product ID (e.g. BTC-USD) is used as the symbol.
+func convertCoinbaseCandleToMarketData(candle CoinbaseCandle) (types.MarketData, error) {
+	start, err := strconv.ParseInt(candle.Start, 10, 64)
+	if err != nil {
+		return types.MarketData{}, fmt.Errorf("invalid coinbase candle start %q: %w", candle.Start, err)
+	}
+
+	values := make([]float64, 5)
+
+	for i, raw := range []string{candle.Open, candle.High, candle.Low, candle.Close, candle.Volume} {
+		values[i], err = strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return types.MarketData{}, fmt.Errorf("invalid coinbase candle value %q for %s: %w", raw, candle.ProductID, err)
+		}
+	}
+
+	return types.MarketData{
+		Id:     "",
+		Symbol: candle.ProductID,
+		Time:   time.Unix(start, 0).UTC(),
+		Open:   values[0],
+		High:   values[1],
+		Low:    values[2],
+		Close:  values[3],
+		Volume: values[4],
+		Bid:    0,
+		Ask:    0,
+	}, nil
+}
+
+// emitStatus emits a status change if a callback is registered.
+func (c *CoinbaseClient) emitStatus(status types.ProviderConnectionStatus) {
+	if c.onStatusChange != nil {
+		c.onStatusChange(status)
+	}
+}
@@ -0,0 +1,289 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type CoinbaseStreamTestSuite struct {
+	suite.Suite
+}
+
+func TestCoinbaseStreamSuite(t *testing.T) {
+	suite.Run(t, new(CoinbaseStreamTestSuite))
+}
+
+// coinbaseCandleMessage builds a candles channel message for a single candle.
+func coinbaseCandleMessage(productID string, start int64, open, high, low, closePrice, volume string) string {
+	return fmt.Sprintf(`{"channel":"candles","timestamp":"2024-01-01T00:00:00Z","sequence_num":0,"events":[{"type":"update","candles":[{"start":"%d","high":"%s","low":"%s","open":"%s","close":"%s","volume":"%s","product_id":"%s"}]}]}`,
+		start, high, low, open, closePrice, volume, productID)
+}
+
+// mockCoinbaseServer is a WebSocket server that replays one list of messages per connection.
+type mockCoinbaseServer struct {
+	server        *httptest.Server
+	mu            sync.Mutex
+	connections   int
+	subscriptions []coinbaseSubscribeMessage
+}
+
+// newMockCoinbaseServer starts a server. Each connection reads the two
+// subscribe messages and then writes the messages of its session. After the
+// last session the connection is kept open until the client disconnects.
+func newMockCoinbaseServer(sessions ...[]string) *mockCoinbaseServer {
+	mock := &mockCoinbaseServer{}
+	upgrader := websocket.Upgrader{CheckOrigin: func(_ *http.Request) bool { return true }}
+
+	mock.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mock.mu.Lock()
+		session := mock.connections
+		mock.connections++
+		mock.mu.Unlock()
+
+		for range 2 {
+			var subscribe coinbaseSubscribeMessage
+			if err := conn.ReadJSON(&subscribe); err != nil {
+				return
+			}
+
+			mock.mu.Lock()
+			mock.subscriptions = append(mock.subscriptions, subscribe)
+			mock.mu.Unlock()
+		}
+
+		if session < len(sessions) {
+			for _, message := range sessions[session] {
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+					return
+				}
+			}
+
+			if session < len(sessions)-1 {
+				// Drop the connection to force a reconnect
+				return
+			}
+		}
+
+		// Keep the connection open until the client closes it
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return mock
+}
+
+func (m *mockCoinbaseServer) url() string {
+	return "ws" + strings.TrimPrefix(m.server.URL, "http")
+}
+
+func (suite *CoinbaseStreamTestSuite) TestStreamTwoCandles() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	server := newMockCoinbaseServer([]string{
+		`{"channel":"subscriptions","events":[{"subscriptions":{"candles":["BTC-USD"]}}]}`,
+		`{"channel":"heartbeats","events":[{"current_time":"2024-01-01T00:00:00Z","heartbeat_counter":1}]}`,
+		// An in-progress update followed by the final state of the first candle
+		coinbaseCandleMessage("BTC-USD", start, "42000", "42050", "41990", "42010", "1.5"),
+		coinbaseCandleMessage("BTC-USD", start, "42000", "42100", "41950", "42080", "3.25"),
+		coinbaseCandleMessage("BTC-USD", start+300, "42080", "42200", "42070", "42150", "2"),
+		// The start of the third candle completes the second
+		coinbaseCandleMessage("BTC-USD", start+600, "42150", "42150", "42150", "42150", "0.1"),
+	})
+	defer server.server.Close()
+
+	client := NewCoinbaseClientWithEndpoint(server.url(), 10*time.Millisecond, []string{"BTC-USD"}, "5m")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var received []types.MarketData
+
+	for data, err := range client.Stream(ctx) {
+		suite.Require().NoError(err)
+
+		received = append(received, data)
+		if len(received) == 2 {
+			break
+		}
+	}
+
+	suite.Require().Len(received, 2)
+	suite.Equal(types.MarketData{
+		Id:     "",
+		Symbol: "BTC-USD",
+		Time:   time.Unix(start, 0).UTC(),
+		Open:   42000,
+		High:   42100,
+		Low:    41950,
+		Close:  42080,
+		Volume: 3.25,
+	}, received[0])
+	suite.Equal(types.MarketData{
+		Id:     "",
+		Symbol: "BTC-USD",
+		Time:   time.Unix(start+300, 0).UTC(),
+		Open:   42080,
+		High:   42200,
+		Low:    42070,
+		Close:  42150,
+		Volume: 2,
+	}, received[1])
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	suite.Equal([]coinbaseSubscribeMessage{
+		{Type: "subscribe", ProductIDs: []string{"BTC-USD"}, Channel: "candles"},
+		{Type: "subscribe", ProductIDs: []string{"BTC-USD"}, Channel: "heartbeats"},
+	}, server.subscriptions)
+}
+
+func (suite *CoinbaseStreamTestSuite) TestStreamReconnectsAfterDrop() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	server := newMockCoinbaseServer(
+		[]string{
+			coinbaseCandleMessage("ETH-USD", start, "2200", "2210", "2190", "2205", "10"),
+		},
+		[]string{
+			coinbaseCandleMessage("ETH-USD", start+300, "2205", "2220", "2200", "2215", "12"),
+		},
+	)
+	defer server.server.Close()
+
+	client := NewCoinbaseClientWithEndpoint(server.url(), 10*time.Millisecond, []string{"ETH-USD"}, "5m")
+
+	var mu sync.Mutex
+
+	var statuses []types.ProviderConnectionStatus
+
+	client.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		statuses = append(statuses, status)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var received []types.MarketData
+
+	var streamErrors []error
+
+	for data, err := range client.Stream(ctx) {
+		if err != nil {
+			streamErrors = append(streamErrors, err)
+
+			continue
+		}
+
+		received = append(received, data)
+
+		break
+	}
+
+	// The candle that was pending when the connection dropped completes after the reconnect
+	suite.Require().Len(received, 1)
+	suite.Equal(time.Unix(start, 0).UTC(), received[0].Time)
+	suite.Equal(2205.0, received[0].Close)
+
+	suite.Require().Len(streamErrors, 1)
+	suite.Contains(streamErrors[0].Error(), "coinbase websocket connection lost")
+
+	mu.Lock()
+	defer mu.Unlock()
+	suite.Equal([]types.ProviderConnectionStatus{
+		types.ProviderStatusConnected,
+		types.ProviderStatusDisconnected,
+		types.ProviderStatusConnected,
+		types.ProviderStatusDisconnected,
+	}, statuses)
+}
+
+func (suite *CoinbaseStreamTestSuite) TestStreamConnectionError() {
+	client := NewCoinbaseClientWithEndpoint("ws://127.0.0.1:1", time.Hour, []string{"BTC-USD"}, "5m")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, err := range client.Stream(ctx) {
+		suite.Error(err)
+		suite.Contains(err.Error(), "failed to connect to coinbase websocket")
+
+		break
+	}
+}
+
+func (suite *CoinbaseStreamTestSuite) TestStreamInvalidConfig() {
+	tests := []struct {
+		name     string
+		symbols  []string
+		interval string
+		errMsg   string
+	}{
+		{"empty symbols", nil, "5m", "no symbols provided"},
+		{"unsupported interval", []string{"BTC-USD"}, "1m", "invalid interval"},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			client := NewCoinbaseClientWithEndpoint("ws://127.0.0.1:1", time.Millisecond, tc.symbols, tc.interval)
+
+			var errs []error
+
+			for _, err := range client.Stream(context.Background()) {
+				errs = append(errs, err)
+			}
+
+			suite.Require().Len(errs, 1)
+			suite.Contains(errs[0].Error(), tc.errMsg)
+		})
+	}
+}
+
+func (suite *CoinbaseStreamTestSuite) TestParseCoinbaseMessage() {
+	candles, err := parseCoinbaseMessage([]byte(coinbaseCandleMessage("BTC-USD", 1704067200, "1", "2", "0.5", "1.5", "100")))
+	suite.Require().NoError(err)
+	suite.Require().Len(candles, 1)
+	suite.Equal("BTC-USD", candles[0].Symbol)
+	suite.Equal(1.5, candles[0].Close)
+
+	candles, err = parseCoinbaseMessage([]byte(`{"channel":"heartbeats","events":[]}`))
+	suite.NoError(err)
+	suite.Empty(candles)
+
+	_, err = parseCoinbaseMessage([]byte(`{"type":"error","message":"failure to subscribe"}`))
+	suite.ErrorContains(err, "failure to subscribe")
+
+	_, err = parseCoinbaseMessage([]byte(coinbaseCandleMessage("BTC-USD", 1704067200, "bad", "2", "0.5", "1.5", "100")))
+	suite.ErrorContains(err, "invalid coinbase candle value")
+}
+
+func (suite *CoinbaseStreamTestSuite) TestNewMarketDataProvider() {
+	p, err := NewMarketDataProvider(ProviderCoinbase, &CoinbaseStreamConfig{
+		BaseStreamConfig: BaseStreamConfig{Symbols: []string{"BTC-USD"}, Interval: "5m"},
+	})
+	suite.Require().NoError(err)
+	suite.Equal([]string{"BTC-USD"}, p.GetSymbols())
+	suite.Equal("5m", p.GetInterval())
+
+	_, err = NewMarketDataProvider(ProviderCoinbase, &BinanceStreamConfig{})
+	suite.Error(err)
+}
@@ -0,0 +1,532 @@
+package provider
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/polygon-io/client-go/rest/models"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
+)
+
+const (
+	// CSVTimestampUnix parses the time column as unix seconds.
+	CSVTimestampUnix = "unix"
+	// CSVTimestampUnixMilli parses the time column as unix milliseconds.
+	CSVTimestampUnixMilli = "unix_ms"
+)
+
+// CSVHeaderMode defines how the first row of a CSV file is treated.
+type CSVHeaderMode string
+
+const (
+	// CSVHeaderAuto treats the first row as a header when none of its fields is numeric.
+	CSVHeaderAuto CSVHeaderMode = "auto"
+	// CSVHeaderPresent always treats the first row as a header.
+	CSVHeaderPresent CSVHeaderMode = "present"
+	// CSVHeaderAbsent treats every row as data.
+	CSVHeaderAbsent CSVHeaderMode = "absent"
+)
+
+// CSVColumns maps the OHLCV fields to CSV columns. Each entry is either a header
+// name (matched case-insensitively) or a zero-based column index.
+// Empty entries fall back to the field name (e.g. "open") when the file has a header,
+// and to the time, open, high, low, close, volume column order when it does not.
+// Symbol is optional; rows without a symbol column use the requested ticker.
+type CSVColumns struct {
+	Time   string `json:"time" yaml:"time"`
+	Symbol string `json:"symbol" yaml:"symbol"`
+	Open   string `json:"open" yaml:"open"`
+	High   string `json:"high" yaml:"high"`
+	Low    string `json:"low" yaml:"low"`
+	Close  string `json:"close" yaml:"close"`
+	Volume string `json:"volume" yaml:"volume"`
+}
+
+// CSVConfig contains configuration for reading OHLCV rows from a local CSV file.
+type CSVConfig struct {
+	// FilePath is the path of the CSV file.
+	FilePath string `json:"filePath" yaml:"file_path" validate:"required"`
+	// Delimiter is the field separator. Empty detects comma or tab from the first line.
+	Delimiter string `json:"delimiter" yaml:"delimiter" validate:"omitempty,len=1"`
+	// TimestampLayout is a Go time layout, "unix" or "unix_ms". Defaults to RFC3339.
+	TimestampLayout string `json:"timestampLayout" yaml:"timestamp_layout"`
+	// Header controls header-row detection. Defaults to auto.
+	Header CSVHeaderMode `json:"header" yaml:"header" validate:"omitempty,oneof=auto present absent"`
+	// Columns remaps non-standard column names or positions.
+	Columns CSVColumns `json:"columns" yaml:"columns"`
+	// Symbol is used for rows without a symbol column when streaming.
+	Symbol string `json:"symbol" yaml:"symbol"`
+	// Interval is reported by GetInterval; the file is replayed as-is.
+	Interval string `json:"interval" yaml:"interval"`
+}
+
+// Validate validates the CSVConfig.
+func (c *CSVConfig) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(c); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	return nil
+}
+
+// csvColumnIndexes holds the resolved column index of each field; -1 means absent.
+type csvColumnIndexes struct {
+	time, symbol, open, high, low, close, volume int
+}
+
+// CSVClient reads OHLCV rows from a local CSV file. Download performs no network
+// access: it writes the rows of the requested ticker and date range through the
+// configured writer, so the backtest engine reads them like any downloaded dataset.
+type CSVClient struct {
+	config         CSVConfig
+	writer         writer.MarketDataWriter
+	onStatusChange OnStatusChange
+}
+
+// NewCSVClient creates a CSV market data provider.
+func NewCSVClient(config *CSVConfig) (*CSVClient, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required for csv provider")
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &CSVClient{
+		config:         *config,
+		writer:         nil,
+		onStatusChange: nil,
+	}, nil
+}
+
+func (c *CSVClient) ConfigWriter(w writer.MarketDataWriter) {
+	c.writer = w
+}
+
+// GetSymbols returns the configured symbol, if any.
+func (c *CSVClient) GetSymbols() []string {
+	if c.config.Symbol == "" {
+		return nil
+	}
+
+	return []string{c.config.Symbol}
+}
+
+// GetInterval returns the configured interval.
+func (c *CSVClient) GetInterval() string {
+	return c.config.Interval
+}
+
+// SetOnStatusChange sets a callback that will be called when Stream starts and ends.
+func (c *CSVClient) SetOnStatusChange(callback OnStatusChange) {
+	c.onStatusChange = callback
+}
+
+// IsReplay implements Replayer: Stream replays the rows of the file.
+func (c *CSVClient) IsReplay() bool {
+	return true
+}
+
+// Download writes the rows of the ticker between startDate and endDate (inclusive)
+// through the configured writer. The multiplier and timespan are ignored because
+// the file already holds bars at its own interval. Rows from a symbol column that
+// do not match the ticker are skipped.
+func (c *CSVClient) Download(ctx context.Context, ticker string, startDate time.Time, endDate time.Time, _ int, _ models.Timespan, onProgress OnDownloadProgress) (string, error) {
+	if c.writer == nil {
+		return "", fmt.Errorf("writer is not configured")
+	}
+
+	rows, err := c.ReadRows(ticker)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.writer.Initialize(); err != nil {
+		return "", fmt.Errorf("failed to initialize writer: %w", err)
+	}
+
+	batch := make([]types.MarketData, 0, len(rows))
+
+	for _, row := range rows {
+		if row.Symbol != ticker || row.Time.Before(startDate) || row.Time.After(endDate) {
+			continue
+		}
+
+		batch = append(batch, row)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if err := writeCSVRows(c.writer, batch); err != nil {
+		return "", err
+	}
+
+	if onProgress != nil {
+		total := float64(len(batch))
+		onProgress(total, total, fmt.Sprintf("Imported %d %s rows from %s", len(batch), ticker, c.config.FilePath))
+	}
+
+	outputPath, err := c.writer.Finalize()
+	if err != nil {
+		return "", fmt.Errorf("failed to finalize writer: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// GetHistoricalKlines returns the rows of symbol between start and end
+// (inclusive). The interval is ignored like in Download.
+func (c *CSVClient) GetHistoricalKlines(ctx context.Context, symbol string, _ string, start time.Time, end time.Time) ([]types.MarketData, error) {
+	rows, err := c.ReadRows(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var bars []types.MarketData
+
+	for _, row := range rows {
+		if row.Symbol != symbol || row.Time.Before(start) || row.Time.After(end) {
+			continue
+		}
+
+		bars = append(bars, row)
+	}
+
+	return bars, nil
+}
+
+// Stream replays every row of the file in file order, then ends.
+func (c *CSVClient) Stream(ctx context.Context) iter.Seq2[types.MarketData, error] {
+	return func(yield func(types.MarketData, error) bool) {
+		rows, err := c.ReadRows(c.config.Symbol)
+		if err != nil {
+			//nolint:exhaustruct // empty struct for error case
+			yield(types.MarketData{}, err)
+
+			return
+		}
+
+		c.emitStatus(types.ProviderStatusConnected)
+		defer c.emitStatus(types.ProviderStatusDisconnected)
+
+		for _, row := range rows {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ReadRows parses all rows of the CSV file. defaultSymbol is used when the file
+// has no symbol column.
+func (c *CSVClient) ReadRows(defaultSymbol string) ([]types.MarketData, error) {
+	file, err := os.Open(c.config.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open csv file: %w", err)
+	}
+	defer file.Close()
+
+	records, err := c.readRecords(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	hasHeader := c.hasHeader(records[0])
+
+	var header []string
+	if hasHeader {
+		header = records[0]
+		records = records[1:]
+	}
+
+	columns, err := resolveCSVColumns(c.config.Columns, header)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]types.MarketData, 0, len(records))
+
+	for i, record := range records {
+		line := i + 1
+		if hasHeader {
+			line++
+		}
+
+		row, err := c.parseRecord(record, columns, defaultSymbol)
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: %w", line, err)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// readRecords reads all non-empty records, detecting the delimiter when it is not configured.
+func (c *CSVClient) readRecords(r io.Reader) ([][]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv file: %w", err)
+	}
+
+	delimiter := c.config.Delimiter
+	if delimiter == "" {
+		delimiter = detectCSVDelimiter(string(content))
+	}
+
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	reader.Comma = []rune(delimiter)[0]
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv file: %w", err)
+	}
+
+	return records, nil
+}
+
+// detectCSVDelimiter returns a tab when the first line contains one, otherwise a comma.
+func detectCSVDelimiter(content string) string {
+	firstLine, _, _ := strings.Cut(content, "\n")
+	if strings.Contains(firstLine, "\t") {
+		return "\t"
+	}
+
+	return ","
+}
+
+// hasHeader reports whether the first record is a header row.
+func (c *CSVClient) hasHeader(first []string) bool {
+	switch c.config.Header {
+	case CSVHeaderPresent:
+		return true
+	case CSVHeaderAbsent:
+		return false
+	default:
+		for _, field := range first {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(field), 64); err == nil {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// resolveCSVColumns resolves the column mapping against the header, which is nil
+// when the file has no header row.
+func resolveCSVColumns(columns CSVColumns, header []string) (csvColumnIndexes, error) {
+	fields := []struct {
+		name     string
+		spec     string
+		position int
+		optional bool
+	}{
+		{"time", columns.Time, 0, false},
+		{"symbol", columns.Symbol, -1, true},
+		{"open", columns.Open, 1, false},
+		{"high", columns.High, 2, false},
+		{"low", columns.Low, 3, false},
+		{"close", columns.Close, 4, false},
+		{"volume", columns.Volume, 5, false},
+	}
+
+	indexes := make([]int, len(fields))
+
+	for i, field := range fields {
+		index, err := resolveCSVColumn(field.name, field.spec, field.position, header)
+		if err != nil && !(field.optional && field.spec == "") {
+			return csvColumnIndexes{}, err
+		}
+
+		if err != nil {
+			index = -1
+		}
+
+		indexes[i] = index
+	}
+
+	return csvColumnIndexes{
+		time:   indexes[0],
+		symbol: indexes[1],
+		open:   indexes[2],
+		high:   indexes[3],
+		low:    indexes[4],
+		close:  indexes[5],
+		volume: indexes[6],
+	}, nil
+}
+
+// resolveCSVColumn returns the index of a single field. A numeric spec is used as
+// the index directly; otherwise the spec (or the field name) is looked up in the header.
+func resolveCSVColumn(name string, spec string, position int, header []string) (int, error) {
+	if index, err := strconv.Atoi(spec); err == nil {
+		if index < 0 {
+			return 0, fmt.Errorf("invalid csv column index %d for %s", index, name)
+		}
+
+		return index, nil
+	}
+
+	if header == nil {
+		if spec != "" {
+			return 0, fmt.Errorf("csv column %q for %s requires a header row", spec, name)
+		}
+
+		if position < 0 {
+			return 0, fmt.Errorf("csv column for %s is not configured", name)
+		}
+
+		return position, nil
+	}
+
+	lookup := spec
+	if lookup == "" {
+		lookup = name
+	}
+
+	for i, column := range header {
+		if strings.EqualFold(strings.TrimSpace(column), lookup) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("csv column %q for %s not found in header", lookup, name)
+}
+
+// parseRecord converts a CSV record to MarketData.
+func (c *CSVClient) parseRecord(record []string, columns csvColumnIndexes, defaultSymbol string) (types.MarketData, error) {
+	field := func(index int) (string, error) {
+		if index >= len(record) {
+			return "", fmt.Errorf("missing column %d", index)
+		}
+
+		return strings.TrimSpace(record[index]), nil
+	}
+
+	rawTime, err := field(columns.time)
+	if err != nil {
+		return types.MarketData{}, err
+	}
+
+	timestamp, err := parseCSVTimestamp(rawTime, c.config.TimestampLayout)
+	if err != nil {
+		return types.MarketData{}, err
+	}
+
+	symbol := defaultSymbol
+	if columns.symbol >= 0 {
+		if symbol, err = field(columns.symbol); err != nil {
+			return types.MarketData{}, err
+		}
+	}
+
+	values := make([]float64, 5)
+
+	for i, index := range []int{columns.open, columns.high, columns.low, columns.close, columns.volume} {
+		raw, err := field(index)
+		if err != nil {
+			return types.MarketData{}, err
+		}
+
+		values[i], err = strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return types.MarketData{}, fmt.Errorf("invalid number %q: %w", raw, err)
+		}
+	}
+
+	return types.MarketData{
+		Id:     "",
+		Symbol: symbol,
+		Time:   timestamp,
+		Open:   values[0],
+		High:   values[1],
+		Low:    values[2],
+		Close:  values[3],
+		Volume: values[4],
+		Bid:    0,
+		Ask:    0,
+	}, nil
+}
+
+// parseCSVTimestamp parses a timestamp with the given layout. Times without a
+// zone are interpreted as UTC.
+func parseCSVTimestamp(raw string, layout string) (time.Time, error) {
+	switch layout {
+	case CSVTimestampUnix, CSVTimestampUnixMilli:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid unix timestamp %q: %w", raw, err)
+		}
+
+		if layout == CSVTimestampUnixMilli {
+			return time.UnixMilli(value).UTC(), nil
+		}
+
+		return time.Unix(value, 0).UTC(), nil
+	case "":
+		layout = time.RFC3339
+	}
+
+	timestamp, err := time.Parse(layout, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", raw, err)
+	}
+
+	return timestamp, nil
+}
+
+// writeCSVRows writes the rows, in a single batch when the writer supports it.
+func writeCSVRows(w writer.MarketDataWriter, rows []types.MarketData) error {
+	if bw, ok := w.(writer.BatchWriter); ok {
+		if err := bw.WriteBatch(rows); err != nil {
+			return fmt.Errorf("failed to write market data batch: %w", err)
+		}
+
+		return nil
+	}
+
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("failed to write market data: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// emitStatus emits a status change if a callback is registered.
+func (c *CSVClient) emitStatus(status types.ProviderConnectionStatus) {
+	if c.onStatusChange != nil {
+		c.onStatusChange(status)
+	}
+}
@@ -0,0 +1,290 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/client-go/rest/models"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type CSVProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestCSVProviderSuite(t *testing.T) {
+	suite.Run(t, new(CSVProviderTestSuite))
+}
+
+// recordingWriter is a MarketDataWriter that keeps written rows in memory.
+type recordingWriter struct {
+	rows        []types.MarketData
+	initialized bool
+	finalized   bool
+}
+
+func (w *recordingWriter) Initialize() error {
+	w.initialized = true
+
+	return nil
+}
+
+func (w *recordingWriter) Write(data types.MarketData) error {
+	w.rows = append(w.rows, data)
+
+	return nil
+}
+
+func (w *recordingWriter) Finalize() (string, error) {
+	w.finalized = true
+
+	return "memory.parquet", nil
+}
+
+func (w *recordingWriter) Close() error {
+	return nil
+}
+
+func (w *recordingWriter) GetOutputPath() string {
+	return "memory.parquet"
+}
+
+func (suite *CSVProviderTestSuite) TestReadRows() {
+	tests := []struct {
+		name          string
+		config        CSVConfig
+		defaultSymbol string
+		expectedRows  int
+		expectedFirst types.MarketData
+	}{
+		{
+			name: "comma delimited with header and symbol column",
+			//nolint:exhaustruct // defaults are under test
+			config:        CSVConfig{FilePath: "testdata/ohlcv.csv"},
+			defaultSymbol: "IGNORED",
+			expectedRows:  4,
+			expectedFirst: types.MarketData{
+				Id:     "",
+				Symbol: "AAPL",
+				Time:   time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC),
+				Open:   185,
+				High:   186.5,
+				Low:    184.2,
+				Close:  186,
+				Volume: 1200,
+			},
+		},
+		{
+			name: "tab delimited with remapped header columns",
+			//nolint:exhaustruct // symbol column is absent
+			config: CSVConfig{
+				FilePath:        "testdata/ohlcv_tab.tsv",
+				TimestampLayout: "2006-01-02 15:04",
+				Columns:         CSVColumns{Time: "date", Open: "O", High: "H", Low: "L", Close: "C", Volume: "vol"},
+			},
+			defaultSymbol: "BTCUSDT",
+			expectedRows:  2,
+			expectedFirst: types.MarketData{
+				Id:     "",
+				Symbol: "BTCUSDT",
+				Time:   time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC),
+				Open:   42000,
+				High:   42100.5,
+				Low:    41950,
+				Close:  42050,
+				Volume: 12.5,
+			},
+		},
+		{
+			name: "tab delimited without header uses column order",
+			//nolint:exhaustruct // default column positions
+			config: CSVConfig{
+				FilePath:        "testdata/ohlcv_no_header.tsv",
+				Delimiter:       "\t",
+				TimestampLayout: CSVTimestampUnixMilli,
+				Header:          CSVHeaderAbsent,
+			},
+			defaultSymbol: "BTCUSDT",
+			expectedRows:  2,
+			expectedFirst: types.MarketData{
+				Id:     "",
+				Symbol: "BTCUSDT",
+				Time:   time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC),
+				Open:   42000,
+				High:   42100.5,
+				Low:    41950,
+				Close:  42050,
+				Volume: 12.5,
+			},
+		},
+		{
+			name: "auto header detection skips nothing for data-only files",
+			//nolint:exhaustruct // header is detected
+			config: CSVConfig{
+				FilePath:        "testdata/ohlcv_no_header.tsv",
+				TimestampLayout: CSVTimestampUnixMilli,
+			},
+			defaultSymbol: "BTCUSDT",
+			expectedRows:  2,
+			expectedFirst: types.MarketData{
+				Id:     "",
+				Symbol: "BTCUSDT",
+				Time:   time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC),
+				Open:   42000,
+				High:   42100.5,
+				Low:    41950,
+				Close:  42050,
+				Volume: 12.5,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			client, err := NewCSVClient(&tc.config)
+			suite.Require().NoError(err)
+
+			rows, err := client.ReadRows(tc.defaultSymbol)
+			suite.Require().NoError(err)
+			suite.Len(rows, tc.expectedRows)
+			suite.Equal(tc.expectedFirst, rows[0])
+		})
+	}
+}
+
+func (suite *CSVProviderTestSuite) TestReadRowsErrors() {
+	tests := []struct {
+		name          string
+		config        CSVConfig
+		content       string
+		expectedError string
+	}{
+		{
+			name:          "missing column in header",
+			config:        CSVConfig{Columns: CSVColumns{Close: "last"}}, //nolint:exhaustruct // only columns matter
+			content:       "time,open,high,low,close,volume\n2024-01-02T09:30:00Z,1,2,0.5,1.5,10\n",
+			expectedError: `csv column "last" for close not found in header`,
+		},
+		{
+			name:          "named column without header",
+			config:        CSVConfig{Header: CSVHeaderAbsent, Columns: CSVColumns{Time: "ts"}}, //nolint:exhaustruct // only header matters
+			content:       "2024-01-02T09:30:00Z,1,2,0.5,1.5,10\n",
+			expectedError: "requires a header row",
+		},
+		{
+			name:          "invalid timestamp",
+			config:        CSVConfig{}, //nolint:exhaustruct // defaults
+			content:       "time,open,high,low,close,volume\n01/02/2024,1,2,0.5,1.5,10\n",
+			expectedError: "csv line 2: invalid timestamp",
+		},
+		{
+			name:          "invalid number",
+			config:        CSVConfig{}, //nolint:exhaustruct // defaults
+			content:       "time,open,high,low,close,volume\n2024-01-02T09:30:00Z,1,2,n/a,1.5,10\n",
+			expectedError: `invalid number "n/a"`,
+		},
+		{
+			name:          "missing column in row",
+			config:        CSVConfig{}, //nolint:exhaustruct // defaults
+			content:       "time,open,high,low,close,volume\n2024-01-02T09:30:00Z,1,2,0.5,1.5\n",
+			expectedError: "missing column 5",
+		},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			path := filepath.Join(suite.T().TempDir(), "data.csv")
+			suite.Require().NoError(os.WriteFile(path, []byte(tc.content), 0o600))
+
+			tc.config.FilePath = path
+
+			client, err := NewCSVClient(&tc.config)
+			suite.Require().NoError(err)
+
+			_, err = client.ReadRows("AAPL")
+			suite.Require().Error(err)
+			suite.Contains(err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func (suite *CSVProviderTestSuite) TestNewCSVClientValidation() {
+	_, err := NewCSVClient(nil)
+	suite.Error(err)
+
+	_, err = NewCSVClient(&CSVConfig{}) //nolint:exhaustruct // missing file path
+	suite.ErrorContains(err, "invalid config")
+
+	_, err = NewCSVClient(&CSVConfig{FilePath: "a.csv", Header: "sometimes"}) //nolint:exhaustruct // invalid header mode
+	suite.ErrorContains(err, "invalid config")
+
+	_, err = NewCSVClient(&CSVConfig{FilePath: "a.csv", Delimiter: ";;"}) //nolint:exhaustruct // invalid delimiter
+	suite.ErrorContains(err, "invalid config")
+}
+
+func (suite *CSVProviderTestSuite) TestDownloadFiltersTickerAndDateRange() {
+	client, err := NewCSVClient(&CSVConfig{FilePath: "testdata/ohlcv.csv"}) //nolint:exhaustruct // defaults
+	suite.Require().NoError(err)
+
+	w := &recordingWriter{}
+	client.ConfigWriter(w)
+
+	var progress []float64
+
+	path, err := client.Download(context.Background(), "AAPL",
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 23, 59, 59, 0, time.UTC),
+		1, models.Minute,
+		func(current float64, _ float64, _ string) { progress = append(progress, current) })
+	suite.Require().NoError(err)
+
+	suite.Equal("memory.parquet", path)
+	suite.True(w.initialized)
+	suite.True(w.finalized)
+	suite.Require().Len(w.rows, 2)
+	suite.Equal(186.0, w.rows[0].Close)
+	suite.Equal(186.4, w.rows[1].Close)
+	suite.Equal([]float64{2}, progress)
+}
+
+func (suite *CSVProviderTestSuite) TestDownloadRequiresWriter() {
+	client, err := NewCSVClient(&CSVConfig{FilePath: "testdata/ohlcv.csv"}) //nolint:exhaustruct // defaults
+	suite.Require().NoError(err)
+
+	_, err = client.Download(context.Background(), "AAPL", time.Time{}, time.Now(), 1, models.Minute, nil)
+	suite.ErrorContains(err, "writer is not configured")
+}
+
+func (suite *CSVProviderTestSuite) TestStreamReplaysRows() {
+	//nolint:exhaustruct // defaults
+	client, err := NewCSVClient(&CSVConfig{
+		FilePath:        "testdata/ohlcv_tab.tsv",
+		TimestampLayout: "2006-01-02 15:04",
+		Columns:         CSVColumns{Time: "Date", Open: "O", High: "H", Low: "L", Close: "C", Volume: "Vol"},
+		Symbol:          "BTCUSDT",
+		Interval:        "5m",
+	})
+	suite.Require().NoError(err)
+
+	var statuses []types.ProviderConnectionStatus
+	client.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		statuses = append(statuses, status)
+	})
+
+	var closes []float64
+
+	for data, err := range client.Stream(context.Background()) {
+		suite.Require().NoError(err)
+		suite.Equal("BTCUSDT", data.Symbol)
+		closes = append(closes, data.Close)
+	}
+
+	suite.Equal([]float64{42050, 42150}, closes)
+	suite.Equal([]string{"BTCUSDT"}, client.GetSymbols())
+	suite.Equal("5m", client.GetInterval())
+	suite.Equal([]types.ProviderConnectionStatus{types.ProviderStatusConnected, types.ProviderStatusDisconnected}, statuses)
+}
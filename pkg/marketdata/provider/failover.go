@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/polygon-io/client-go/rest/models"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
+)
+
+// DefaultFailoverMaxConsecutiveErrors is the number of consecutive stream errors
+// tolerated from the primary provider before switching to the backup.
+const DefaultFailoverMaxConsecutiveErrors = 3
+
+// FailoverConfig configures when a FailoverProvider switches from its primary
+// provider to its backup.
+type FailoverConfig struct {
+	// MaxConsecutiveErrors is the number of consecutive stream errors from the
+	// primary provider that triggers the failover. Values <= 0 use
+	// DefaultFailoverMaxConsecutiveErrors.
+	MaxConsecutiveErrors int `json:"max_consecutive_errors" yaml:"max_consecutive_errors" jsonschema:"description=Consecutive primary stream errors before switching to the backup provider,default=3"`
+}
+
+// FailoverProvider composes a primary and a backup market data provider.
+// It streams from the primary until the primary fails repeatedly (or its stream
+// ends before the context is cancelled), then transparently continues streaming
+// from the backup. Stream errors are still yielded to the caller, and the switch
+// is reported through the status change callback as a disconnect followed by a
+// reconnect once the backup delivers data. The failover is one-way.
+type FailoverProvider struct {
+	primary Provider
+	backup  Provider
+	config  FailoverConfig
+
+	mu             sync.Mutex
+	active         Provider
+	failedOver     bool
+	status         types.ProviderConnectionStatus
+	onStatusChange OnStatusChange
+}
+
+// NewFailoverProvider creates a provider that fails over from primary to backup.
+func NewFailoverProvider(primary Provider, backup Provider, config FailoverConfig) *FailoverProvider {
+	if config.MaxConsecutiveErrors <= 0 {
+		config.MaxConsecutiveErrors = DefaultFailoverMaxConsecutiveErrors
+	}
+
+	return &FailoverProvider{
+		primary:        primary,
+		backup:         backup,
+		config:         config,
+		mu:             sync.Mutex{},
+		active:         primary,
+		failedOver:     false,
+		status:         types.ProviderStatusDisconnected,
+		onStatusChange: nil,
+	}
+}
+
+// ActiveProvider returns the provider currently used for streaming.
+func (f *FailoverProvider) ActiveProvider() Provider {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.active
+}
+
+// FailedOver reports whether the provider has switched to the backup.
+func (f *FailoverProvider) FailedOver() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.failedOver
+}
+
+// ConfigWriter configures the writer on the active provider.
+func (f *FailoverProvider) ConfigWriter(w writer.MarketDataWriter) {
+	f.ActiveProvider().ConfigWriter(w)
+}
+
+// Download downloads data using the active provider.
+func (f *FailoverProvider) Download(ctx context.Context, ticker string, startDate time.Time, endDate time.Time, multiplier int, timespan models.Timespan, onProgress OnDownloadProgress) (string, error) {
+	return f.ActiveProvider().Download(ctx, ticker, startDate, endDate, multiplier, timespan, onProgress)
+}
+
+// GetHistoricalKlines returns historical bars from the active provider.
+func (f *FailoverProvider) GetHistoricalKlines(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]types.MarketData, error) {
+	return f.ActiveProvider().GetHistoricalKlines(ctx, symbol, interval, start, end)
+}
+
+// GetSymbols returns the symbols configured on the active provider.
+func (f *FailoverProvider) GetSymbols() []string {
+	return f.ActiveProvider().GetSymbols()
+}
+
+// GetInterval returns the interval configured on the active provider.
+func (f *FailoverProvider) GetInterval() string {
+	return f.ActiveProvider().GetInterval()
+}
+
+// SetOnStatusChange sets the status callback. Status changes reported by the
+// underlying providers are forwarded only while that provider is active.
+func (f *FailoverProvider) SetOnStatusChange(callback OnStatusChange) {
+	f.mu.Lock()
+	f.onStatusChange = callback
+	f.mu.Unlock()
+
+	f.primary.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		f.forwardStatus(f.primary, status)
+	})
+	f.backup.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		f.forwardStatus(f.backup, status)
+	})
+}
+
+// Stream yields market data from the primary provider and switches to the
+// backup after MaxConsecutiveErrors consecutive errors.
+func (f *FailoverProvider) Stream(ctx context.Context) iter.Seq2[types.MarketData, error] {
+	return func(yield func(types.MarketData, error) bool) {
+		if !f.FailedOver() {
+			consecutiveErrors := 0
+			stopped := false
+
+			for data, err := range f.primary.Stream(ctx) {
+				if err != nil {
+					consecutiveErrors++
+				} else {
+					consecutiveErrors = 0
+					f.setStatus(types.ProviderStatusConnected)
+				}
+
+				if !yield(data, err) {
+					stopped = true
+
+					break
+				}
+
+				if consecutiveErrors >= f.config.MaxConsecutiveErrors {
+					break
+				}
+			}
+
+			if stopped || ctx.Err() != nil {
+				return
+			}
+
+			f.failover()
+		}
+
+		for data, err := range f.backup.Stream(ctx) {
+			if err == nil {
+				f.setStatus(types.ProviderStatusConnected)
+			}
+
+			if !yield(data, err) {
+				return
+			}
+		}
+	}
+}
+
+// failover switches the active provider to the backup and reports the
+// primary's disconnection.
+func (f *FailoverProvider) failover() {
+	f.mu.Lock()
+	f.active = f.backup
+	f.failedOver = true
+	f.mu.Unlock()
+
+	f.setStatus(types.ProviderStatusDisconnected)
+}
+
+// forwardStatus forwards a status change from source if it is the active provider.
+func (f *FailoverProvider) forwardStatus(source Provider, status types.ProviderConnectionStatus) {
+	if f.ActiveProvider() != source {
+		return
+	}
+
+	f.setStatus(status)
+}
+
+// setStatus records the status and notifies the callback when it changes.
+func (f *FailoverProvider) setStatus(status types.ProviderConnectionStatus) {
+	f.mu.Lock()
+	if f.status == status {
+		f.mu.Unlock()
+
+		return
+	}
+
+	f.status = status
+	callback := f.onStatusChange
+	f.mu.Unlock()
+
+	if callback != nil {
+		callback(status)
+	}
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/client-go/rest/models"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
+	"github.com/stretchr/testify/suite"
+)
+
+type FailoverProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestFailoverProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(FailoverProviderTestSuite))
+}
+
+// streamEvent is a single data point or error yielded by fakeStreamProvider.
+type streamEvent struct {
+	data types.MarketData
+	err  error
+}
+
+// fakeStreamProvider is a Provider that replays a fixed sequence of stream events.
+type fakeStreamProvider struct {
+	symbols        []string
+	interval       string
+	events         []streamEvent
+	streamCalls    int
+	onStatusChange OnStatusChange
+}
+
+func (p *fakeStreamProvider) ConfigWriter(_ writer.MarketDataWriter) {}
+
+func (p *fakeStreamProvider) Download(_ context.Context, _ string, _ time.Time, _ time.Time, _ int, _ models.Timespan, _ OnDownloadProgress) (string, error) {
+	return "", nil
+}
+
+func (p *fakeStreamProvider) GetHistoricalKlines(_ context.Context, _ string, _ string, _ time.Time, _ time.Time) ([]types.MarketData, error) {
+	return nil, nil
+}
+
+func (p *fakeStreamProvider) Stream(_ context.Context) iter.Seq2[types.MarketData, error] {
+	p.streamCalls++
+
+	return func(yield func(types.MarketData, error) bool) {
+		for _, event := range p.events {
+			if !yield(event.data, event.err) {
+				return
+			}
+		}
+	}
+}
+
+func (p *fakeStreamProvider) GetSymbols() []string {
+	return p.symbols
+}
+
+func (p *fakeStreamProvider) GetInterval() string {
+	return p.interval
+}
+
+func (p *fakeStreamProvider) SetOnStatusChange(callback OnStatusChange) {
+	p.onStatusChange = callback
+}
+
+func newFakeStreamProvider(symbol string, events ...streamEvent) *fakeStreamProvider {
+	return &fakeStreamProvider{
+		symbols:        []string{symbol},
+		interval:       "1m",
+		events:         events,
+		streamCalls:    0,
+		onStatusChange: nil,
+	}
+}
+
+func bar(symbol string, price float64) streamEvent {
+	return streamEvent{
+		data: types.MarketData{
+			Id:     "",
+			Symbol: symbol,
+			Time:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Open:   price,
+			High:   price,
+			Low:    price,
+			Close:  price,
+			Volume: 1,
+		},
+		err: nil,
+	}
+}
+
+func streamErr(msg string) streamEvent {
+	return streamEvent{data: types.MarketData{}, err: errors.New(msg)} //nolint:exhaustruct // error events carry no data
+}
+
+// collect drains the failover stream and returns the yielded prices and errors.
+func collect(stream iter.Seq2[types.MarketData, error]) ([]float64, []error) {
+	var prices []float64
+
+	var errs []error
+
+	for data, err := range stream {
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		prices = append(prices, data.Close)
+	}
+
+	return prices, errs
+}
+
+func (suite *FailoverProviderTestSuite) TestStream_SwitchesToBackupAfterConsecutiveErrors() {
+	primary := newFakeStreamProvider("BTCUSDT",
+		bar("BTCUSDT", 100),
+		streamErr("primary error 1"),
+		streamErr("primary error 2"),
+		bar("BTCUSDT", 999), // never reached
+	)
+	backup := newFakeStreamProvider("BTCUSDT", bar("BTCUSDT", 101), bar("BTCUSDT", 102))
+
+	failover := NewFailoverProvider(primary, backup, FailoverConfig{MaxConsecutiveErrors: 2})
+
+	var statuses []types.ProviderConnectionStatus
+	failover.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		statuses = append(statuses, status)
+	})
+
+	prices, errs := collect(failover.Stream(context.Background()))
+
+	suite.Equal([]float64{100, 101, 102}, prices)
+	suite.Len(errs, 2, "primary errors are still surfaced to the caller")
+	suite.True(failover.FailedOver())
+	suite.Same(backup, failover.ActiveProvider())
+	suite.Equal(1, backup.streamCalls)
+	suite.Equal([]types.ProviderConnectionStatus{
+		types.ProviderStatusConnected,
+		types.ProviderStatusDisconnected,
+		types.ProviderStatusConnected,
+	}, statuses)
+}
+
+func (suite *FailoverProviderTestSuite) TestStream_NonConsecutiveErrorsDoNotFailover() {
+	primary := newFakeStreamProvider("BTCUSDT",
+		bar("BTCUSDT", 100),
+		streamErr("transient"),
+		bar("BTCUSDT", 101),
+		streamErr("transient"),
+		bar("BTCUSDT", 102),
+	)
+	backup := newFakeStreamProvider("BTCUSDT", bar("BTCUSDT", 200))
+
+	failover := NewFailoverProvider(primary, backup, FailoverConfig{MaxConsecutiveErrors: 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var prices []float64
+
+	for data, err := range failover.Stream(ctx) {
+		if err != nil {
+			continue
+		}
+
+		prices = append(prices, data.Close)
+		if data.Close == 102 {
+			cancel()
+		}
+	}
+
+	suite.Equal([]float64{100, 101, 102}, prices)
+	suite.False(failover.FailedOver())
+	suite.Equal(0, backup.streamCalls)
+}
+
+func (suite *FailoverProviderTestSuite) TestStream_PrimaryStreamEndsFailsOver() {
+	primary := newFakeStreamProvider("BTCUSDT", bar("BTCUSDT", 100))
+	backup := newFakeStreamProvider("BTCUSDT", bar("BTCUSDT", 101))
+
+	failover := NewFailoverProvider(primary, backup, FailoverConfig{MaxConsecutiveErrors: 0})
+
+	prices, errs := collect(failover.Stream(context.Background()))
+
+	suite.Equal([]float64{100, 101}, prices)
+	suite.Empty(errs)
+	suite.True(failover.FailedOver())
+}
+
+func (suite *FailoverProviderTestSuite) TestStream_ConsumerStopDoesNotFailover() {
+	primary := newFakeStreamProvider("BTCUSDT", bar("BTCUSDT", 100), bar("BTCUSDT", 101))
+	backup := newFakeStreamProvider("BTCUSDT", bar("BTCUSDT", 200))
+
+	failover := NewFailoverProvider(primary, backup, FailoverConfig{MaxConsecutiveErrors: 1})
+
+	for range failover.Stream(context.Background()) {
+		break
+	}
+
+	suite.False(failover.FailedOver())
+	suite.Equal(0, backup.streamCalls)
+}
+
+func (suite *FailoverProviderTestSuite) TestNewFailoverProvider_DefaultThreshold() {
+	failover := NewFailoverProvider(newFakeStreamProvider("A"), newFakeStreamProvider("B"), FailoverConfig{MaxConsecutiveErrors: -1})
+
+	suite.Equal(DefaultFailoverMaxConsecutiveErrors, failover.config.MaxConsecutiveErrors)
+}
+
+func (suite *FailoverProviderTestSuite) TestStatusForwardedOnlyFromActiveProvider() {
+	primary := newFakeStreamProvider("BTCUSDT", streamErr("down"))
+	backup := newFakeStreamProvider("ETHUSDT")
+
+	failover := NewFailoverProvider(primary, backup, FailoverConfig{MaxConsecutiveErrors: 1})
+
+	var statuses []types.ProviderConnectionStatus
+	failover.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		statuses = append(statuses, status)
+	})
+
+	// Backup status is ignored while the primary is active
+	backup.onStatusChange(types.ProviderStatusConnected)
+	suite.Empty(statuses)
+
+	primary.onStatusChange(types.ProviderStatusConnected)
+	suite.Equal([]string{"BTCUSDT"}, failover.GetSymbols())
+
+	collect(failover.Stream(context.Background()))
+
+	// After failover the primary is ignored and the backup is forwarded
+	primary.onStatusChange(types.ProviderStatusConnected)
+	backup.onStatusChange(types.ProviderStatusConnected)
+
+	suite.Equal([]types.ProviderConnectionStatus{
+		types.ProviderStatusConnected,
+		types.ProviderStatusDisconnected,
+		types.ProviderStatusConnected,
+	}, statuses)
+	suite.Equal([]string{"ETHUSDT"}, failover.GetSymbols())
+}
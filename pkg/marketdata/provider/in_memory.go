@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/polygon-io/client-go/rest/models"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
+)
+
+// InMemoryStatusChange is a connection status the InMemoryProvider reports
+// while streaming, once AfterItems items have been yielded.
+type InMemoryStatusChange struct {
+	AfterItems int
+	Status     types.ProviderConnectionStatus
+}
+
+// InMemoryProvider replays a fixed slice of market data. It needs no network
+// access, so the live engine can be driven deterministically in tests, such as
+// integration tests of a WASM strategy against the real engine.
+type InMemoryProvider struct {
+	data           []types.MarketData
+	errs           []error
+	symbols        []string
+	interval       string
+	schedule       []InMemoryStatusChange
+	writer         writer.MarketDataWriter
+	onStatusChange OnStatusChange
+}
+
+// NewInMemoryProvider creates a provider streaming data in order. errs is
+// optional: errs[i] is yielded together with data[i]. The symbols default to
+// the distinct symbols of data in order of appearance, and the interval to "1m".
+func NewInMemoryProvider(data []types.MarketData, errs []error) *InMemoryProvider {
+	symbols := make([]string, 0)
+
+	for _, item := range data {
+		if item.Symbol != "" && !slices.Contains(symbols, item.Symbol) {
+			symbols = append(symbols, item.Symbol)
+		}
+	}
+
+	return &InMemoryProvider{
+		data:           slices.Clone(data),
+		errs:           slices.Clone(errs),
+		symbols:        symbols,
+		interval:       "1m",
+		schedule:       nil,
+		writer:         nil,
+		onStatusChange: nil,
+	}
+}
+
+// SetSymbols sets the symbols reported by GetSymbols.
+func (p *InMemoryProvider) SetSymbols(symbols []string) {
+	p.symbols = slices.Clone(symbols)
+}
+
+// SetInterval sets the interval reported by GetInterval.
+func (p *InMemoryProvider) SetInterval(interval string) {
+	p.interval = interval
+}
+
+// SetStatusSchedule sets the status changes reported while streaming. Without
+// a schedule, Stream reports connected before the first item and disconnected
+// after the last one. Changes scheduled after the last item are reported when
+// the stream ends.
+func (p *InMemoryProvider) SetStatusSchedule(schedule []InMemoryStatusChange) {
+	p.schedule = slices.Clone(schedule)
+	slices.SortStableFunc(p.schedule, func(a, b InMemoryStatusChange) int {
+		return a.AfterItems - b.AfterItems
+	})
+}
+
+func (p *InMemoryProvider) ConfigWriter(w writer.MarketDataWriter) {
+	p.writer = w
+}
+
+// GetSymbols returns the configured symbols.
+func (p *InMemoryProvider) GetSymbols() []string {
+	return p.symbols
+}
+
+// GetInterval returns the configured interval.
+func (p *InMemoryProvider) GetInterval() string {
+	return p.interval
+}
+
+// SetOnStatusChange sets a callback that will be called on every status change of Stream.
+func (p *InMemoryProvider) SetOnStatusChange(callback OnStatusChange) {
+	p.onStatusChange = callback
+}
+
+// IsReplay implements Replayer: Stream replays the configured data.
+func (p *InMemoryProvider) IsReplay() bool {
+	return true
+}
+
+// Download writes the items of the ticker between startDate and endDate
+// (inclusive) through the configured writer. The multiplier and timespan are
+// ignored.
+func (p *InMemoryProvider) Download(ctx context.Context, ticker string, startDate time.Time, endDate time.Time, _ int, _ models.Timespan, onProgress OnDownloadProgress) (string, error) {
+	if p.writer == nil {
+		return "", fmt.Errorf("writer is not configured")
+	}
+
+	bars, err := p.GetHistoricalKlines(ctx, ticker, "", startDate, endDate)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.writer.Initialize(); err != nil {
+		return "", fmt.Errorf("failed to initialize writer: %w", err)
+	}
+
+	if err := writeCSVRows(p.writer, bars); err != nil {
+		return "", err
+	}
+
+	if onProgress != nil {
+		total := float64(len(bars))
+		onProgress(total, total, fmt.Sprintf("Imported %d %s rows from memory", len(bars), ticker))
+	}
+
+	outputPath, err := p.writer.Finalize()
+	if err != nil {
+		return "", fmt.Errorf("failed to finalize writer: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// GetHistoricalKlines returns the items of symbol between start and end
+// (inclusive), skipping items paired with an error. The interval is ignored.
+func (p *InMemoryProvider) GetHistoricalKlines(ctx context.Context, symbol string, _ string, start time.Time, end time.Time) ([]types.MarketData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var bars []types.MarketData
+
+	for i, item := range p.data {
+		if p.itemErr(i) != nil || item.Symbol != symbol || item.Time.Before(start) || item.Time.After(end) {
+			continue
+		}
+
+		bars = append(bars, item)
+	}
+
+	return bars, nil
+}
+
+// Stream yields every item with its error in order, then ends. Each call
+// replays the data from the start.
+func (p *InMemoryProvider) Stream(ctx context.Context) iter.Seq2[types.MarketData, error] {
+	return func(yield func(types.MarketData, error) bool) {
+		schedule := p.schedule
+		if schedule == nil {
+			schedule = []InMemoryStatusChange{
+				{AfterItems: 0, Status: types.ProviderStatusConnected},
+				{AfterItems: len(p.data), Status: types.ProviderStatusDisconnected},
+			}
+		}
+
+		next := 0
+		emitDue := func(yielded int) {
+			for next < len(schedule) && schedule[next].AfterItems <= yielded {
+				p.emitStatus(schedule[next].Status)
+				next++
+			}
+		}
+
+		defer func() {
+			for ; next < len(schedule); next++ {
+				p.emitStatus(schedule[next].Status)
+			}
+		}()
+
+		for i, item := range p.data {
+			emitDue(i)
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !yield(item, p.itemErr(i)) {
+				return
+			}
+		}
+
+		emitDue(len(p.data))
+	}
+}
+
+// itemErr returns the error paired with the item at index i, if any.
+func (p *InMemoryProvider) itemErr(i int) error {
+	if i < len(p.errs) {
+		return p.errs[i]
+	}
+
+	return nil
+}
+
+// emitStatus emits a status change if a callback is registered.
+func (p *InMemoryProvider) emitStatus(status types.ProviderConnectionStatus) {
+	if p.onStatusChange != nil {
+		p.onStatusChange(status)
+	}
+}
@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polygon-io/client-go/rest/models"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type InMemoryProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestInMemoryProviderSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryProviderTestSuite))
+}
+
+func inMemoryBar(symbol string, minute int, price float64) types.MarketData {
+	return types.MarketData{
+		Id:     "",
+		Symbol: symbol,
+		Time:   time.Date(2024, 1, 1, 0, minute, 0, 0, time.UTC),
+		Open:   price,
+		High:   price + 1,
+		Low:    price - 1,
+		Close:  price,
+		Volume: 100,
+	}
+}
+
+func (suite *InMemoryProviderTestSuite) TestDefaults() {
+	p := NewInMemoryProvider([]types.MarketData{
+		inMemoryBar("BTCUSDT", 0, 100),
+		inMemoryBar("ETHUSDT", 0, 10),
+		inMemoryBar("BTCUSDT", 1, 101),
+	}, nil)
+
+	suite.Equal([]string{"BTCUSDT", "ETHUSDT"}, p.GetSymbols())
+	suite.Equal("1m", p.GetInterval())
+
+	p.SetSymbols([]string{"SOLUSDT"})
+	p.SetInterval("5m")
+	suite.Equal([]string{"SOLUSDT"}, p.GetSymbols())
+	suite.Equal("5m", p.GetInterval())
+}
+
+func (suite *InMemoryProviderTestSuite) TestStreamYieldsDataAndErrors() {
+	streamErr := errors.New("stream error")
+	p := NewInMemoryProvider([]types.MarketData{
+		inMemoryBar("BTCUSDT", 0, 100),
+		{},
+		inMemoryBar("BTCUSDT", 2, 102),
+	}, []error{nil, streamErr})
+
+	var statuses []types.ProviderConnectionStatus
+	p.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		statuses = append(statuses, status)
+	})
+
+	// Each call replays the data from the start.
+	for range 2 {
+		var closes []float64
+		var errs []error
+
+		for data, err := range p.Stream(context.Background()) {
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			closes = append(closes, data.Close)
+		}
+
+		suite.Equal([]float64{100, 102}, closes)
+		suite.Equal([]error{streamErr}, errs)
+	}
+
+	suite.Equal([]types.ProviderConnectionStatus{
+		types.ProviderStatusConnected, types.ProviderStatusDisconnected,
+		types.ProviderStatusConnected, types.ProviderStatusDisconnected,
+	}, statuses)
+}
+
+func (suite *InMemoryProviderTestSuite) TestStreamStatusSchedule() {
+	p := NewInMemoryProvider([]types.MarketData{
+		inMemoryBar("BTCUSDT", 0, 100),
+		inMemoryBar("BTCUSDT", 1, 101),
+		inMemoryBar("BTCUSDT", 2, 102),
+	}, nil)
+	p.SetStatusSchedule([]InMemoryStatusChange{
+		{AfterItems: 5, Status: types.ProviderStatusDisconnected},
+		{AfterItems: 0, Status: types.ProviderStatusConnected},
+		{AfterItems: 1, Status: types.ProviderStatusDisconnected},
+		{AfterItems: 2, Status: types.ProviderStatusConnected},
+	})
+
+	var events []string
+	p.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		events = append(events, string(status))
+	})
+
+	for data := range p.Stream(context.Background()) {
+		events = append(events, data.Time.Format("15:04"))
+	}
+
+	suite.Equal([]string{"connected", "00:00", "disconnected", "00:01", "connected", "00:02", "disconnected"}, events)
+}
+
+func (suite *InMemoryProviderTestSuite) TestStreamStopsEarly() {
+	p := NewInMemoryProvider([]types.MarketData{
+		inMemoryBar("BTCUSDT", 0, 100),
+		inMemoryBar("BTCUSDT", 1, 101),
+	}, nil)
+
+	var statuses []types.ProviderConnectionStatus
+	p.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		statuses = append(statuses, status)
+	})
+
+	count := 0
+	for range p.Stream(context.Background()) {
+		count++
+
+		break
+	}
+
+	suite.Equal(1, count)
+	suite.Equal([]types.ProviderConnectionStatus{types.ProviderStatusConnected, types.ProviderStatusDisconnected}, statuses)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for range p.Stream(ctx) {
+		suite.Fail("a cancelled stream must not yield")
+	}
+}
+
+func (suite *InMemoryProviderTestSuite) TestGetHistoricalKlinesAndDownload() {
+	p := NewInMemoryProvider([]types.MarketData{
+		inMemoryBar("BTCUSDT", 0, 100),
+		inMemoryBar("ETHUSDT", 1, 10),
+		inMemoryBar("BTCUSDT", 1, 101),
+		{},
+		inMemoryBar("BTCUSDT", 3, 103),
+	}, []error{nil, nil, nil, errors.New("gap")})
+
+	start := time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 0, 3, 0, 0, time.UTC)
+
+	bars, err := p.GetHistoricalKlines(context.Background(), "BTCUSDT", "1m", start, end)
+	suite.Require().NoError(err)
+	suite.Require().Len(bars, 2)
+	suite.Equal(101.0, bars[0].Close)
+	suite.Equal(103.0, bars[1].Close)
+
+	_, err = p.Download(context.Background(), "BTCUSDT", start, end, 1, models.Minute, nil)
+	suite.Error(err)
+
+	w := &recordingWriter{}
+	p.ConfigWriter(w)
+
+	path, err := p.Download(context.Background(), "BTCUSDT", start, end, 1, models.Minute, nil)
+	suite.Require().NoError(err)
+	suite.Equal("memory.parquet", path)
+	suite.True(w.initialized)
+	suite.True(w.finalized)
+	suite.Equal(bars, w.rows)
+}
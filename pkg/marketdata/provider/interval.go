@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/polygon-io/client-go/rest/models"
+)
+
+// ParseInterval splits a candle interval such as "15m" into its multiplier and
+// timespan. The units are s, m, h, d and w, plus M for months. It is the one
+// parser of interval strings; IntervalDuration builds on it.
+func ParseInterval(interval string) (int, models.Timespan, error) {
+	if len(interval) < 2 {
+		return 0, "", fmt.Errorf("invalid interval: %q", interval)
+	}
+
+	multiplier, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || multiplier <= 0 {
+		return 0, "", fmt.Errorf("invalid interval: %q", interval)
+	}
+
+	switch interval[len(interval)-1] {
+	case 's':
+		return multiplier, models.Second, nil
+	case 'm':
+		return multiplier, models.Minute, nil
+	case 'h':
+		return multiplier, models.Hour, nil
+	case 'd':
+		return multiplier, models.Day, nil
+	case 'w':
+		return multiplier, models.Week, nil
+	case 'M':
+		return multiplier, models.Month, nil
+	default:
+		return 0, "", fmt.Errorf("invalid interval: %q", interval)
+	}
+}
+
+// IntervalDuration returns the length of one bar of a candle interval such as
+// "15m". Monthly intervals have no fixed length and are rejected.
+func IntervalDuration(interval string) (time.Duration, error) {
+	multiplier, timespan, err := ParseInterval(interval)
+	if err != nil {
+		return 0, err
+	}
+
+	var unit time.Duration
+
+	switch timespan {
+	case models.Second:
+		unit = time.Second
+	case models.Minute:
+		unit = time.Minute
+	case models.Hour:
+		unit = time.Hour
+	case models.Day:
+		unit = 24 * time.Hour
+	case models.Week:
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("interval has no fixed duration: %q", interval)
+	}
+
+	return time.Duration(multiplier) * unit, nil
+}
+
+// FormatInterval returns the candle interval of bars d apart in the format
+// IntervalDuration reads, using the largest unit that divides d, e.g. "4h" for
+// four hours and "90m" for an hour and a half. It returns "" for durations
+// that are not a positive whole number of seconds.
+func FormatInterval(d time.Duration) string {
+	if d <= 0 || d%time.Second != 0 {
+		return ""
+	}
+
+	units := []struct {
+		length time.Duration
+		suffix string
+	}{
+		{7 * 24 * time.Hour, "w"},
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+
+	for _, unit := range units {
+		if d%unit.length == 0 {
+			return strconv.FormatInt(int64(d/unit.length), 10) + unit.suffix
+		}
+	}
+
+	return ""
+}
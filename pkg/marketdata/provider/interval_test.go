@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type IntervalTestSuite struct {
+	suite.Suite
+}
+
+func TestIntervalTestSuite(t *testing.T) {
+	suite.Run(t, new(IntervalTestSuite))
+}
+
+func (suite *IntervalTestSuite) TestIntervalDuration() {
+	tests := []struct {
+		interval string
+		expected time.Duration
+	}{
+		{"1s", time.Second},
+		{"15m", 15 * time.Minute},
+		{"4h", 4 * time.Hour},
+		{"3d", 72 * time.Hour},
+		{"1w", 168 * time.Hour},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.interval, func() {
+			duration, err := IntervalDuration(tc.interval)
+			suite.Require().NoError(err)
+			suite.Equal(tc.expected, duration)
+		})
+	}
+}
+
+func (suite *IntervalTestSuite) TestIntervalDuration_Invalid() {
+	for _, interval := range []string{"", "m", "0m", "-1h", "5x", "1M"} {
+		_, err := IntervalDuration(interval)
+		suite.Error(err, interval)
+	}
+}
+
+func (suite *IntervalTestSuite) TestFormatInterval() {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{time.Second, "1s"},
+		{15 * time.Minute, "15m"},
+		{90 * time.Minute, "90m"},
+		{4 * time.Hour, "4h"},
+		{24 * time.Hour, "1d"},
+		{168 * time.Hour, "1w"},
+		{0, ""},
+		{-time.Minute, ""},
+		{1500 * time.Millisecond, ""},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.duration.String(), func() {
+			interval := FormatInterval(tc.duration)
+			suite.Equal(tc.expected, interval)
+
+			if interval != "" {
+				duration, err := IntervalDuration(interval)
+				suite.Require().NoError(err)
+				suite.Equal(tc.duration, duration)
+			}
+		})
+	}
+}
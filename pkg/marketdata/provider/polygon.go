@@ -117,6 +117,7 @@ type PolygonClient struct {
 	onStatusChange      OnStatusChange
 	symbols             []string
 	interval            string
+	reconnect           reconnectPolicy
 }
 
 func NewPolygonClient(config *PolygonStreamConfig) (Provider, error) {
@@ -138,6 +139,7 @@ func NewPolygonClient(config *PolygonStreamConfig) (Provider, error) {
 		onStatusChange:      nil,
 		symbols:             config.Symbols,
 		interval:            config.Interval,
+		reconnect:           defaultReconnectPolicy(),
 	}, nil
 }
 
@@ -151,6 +153,7 @@ func NewPolygonClientWithAPI(apiClient PolygonAPIClient, symbols []string, inter
 		onStatusChange:      nil,
 		symbols:             symbols,
 		interval:            interval,
+		reconnect:           defaultReconnectPolicy(),
 	}
 }
 
@@ -164,6 +167,7 @@ func NewPolygonClientWithWebSocket(apiKey string, wsService PolygonWebSocketServ
 		onStatusChange:      nil,
 		symbols:             symbols,
 		interval:            interval,
+		reconnect:           defaultReconnectPolicy(),
 	}
 }
 
@@ -261,6 +265,8 @@ func (c *PolygonClient) Download(ctx context.Context, ticker string, startDate t
 			Low:    agg.Low,
 			Close:  agg.Close,
 			Volume: agg.Volume,
+			Bid:    0,
+			Ask:    0,
 		}
 
 		err = c.writer.Write(marketData)
@@ -301,6 +307,51 @@ func (c *PolygonClient) Download(ctx context.Context, ticker string, startDate t
 	return outputPath, nil
 }
 
+// GetHistoricalKlines implements Provider.GetHistoricalKlines with the aggregates
+// REST endpoint. The client follows the pagination of the results itself.
+func (c *PolygonClient) GetHistoricalKlines(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]types.MarketData, error) {
+	multiplier, timespan, err := ParseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:exhaustruct // third-party struct with many optional fields
+	params := models.ListAggsParams{
+		Ticker:     symbol,
+		Multiplier: multiplier,
+		Timespan:   timespan,
+		From:       models.Millis(start),
+		To:         models.Millis(end),
+	}.WithLimit(50000).WithOrder(models.Asc)
+
+	aggsIter := c.apiClient.ListAggs(ctx, params)
+
+	var bars []types.MarketData
+
+	for aggsIter.Next() {
+		agg := aggsIter.Item()
+
+		bars = append(bars, types.MarketData{
+			Id:     "",
+			Symbol: symbol,
+			Time:   time.Time(agg.Timestamp),
+			Open:   agg.Open,
+			High:   agg.High,
+			Low:    agg.Low,
+			Close:  agg.Close,
+			Volume: agg.Volume,
+			Bid:    0,
+			Ask:    0,
+		})
+	}
+
+	if err := aggsIter.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating polygon aggregates: %w", err)
+	}
+
+	return bars, nil
+}
+
 // Stream implements Provider.Stream for real-time WebSocket market data from Polygon.
 // It subscribes to aggregate streams for all specified symbols and yields data as it arrives.
 // The iterator terminates when the context is cancelled or an unrecoverable error occurs.
@@ -325,74 +376,117 @@ func (c *PolygonClient) Stream(ctx context.Context) goiter.Seq2[types.MarketData
 			return
 		}
 
-		// Create or use WebSocket service
-		var wsService PolygonWebSocketService
-		if c.wsServiceForTesting != nil {
-			// Use injected service for testing
-			wsService = c.wsServiceForTesting
-		} else {
-			// Create production WebSocket service
-			wsService, err = newPolygonWebSocketService(c.apiKey, polygonws.RealTime)
-			if err != nil {
-				//nolint:exhaustruct // empty struct for error case
-				yield(types.MarketData{}, fmt.Errorf("failed to create websocket service: %w", err))
+		// Bars replayed by a reconnected stream were already yielded
+		yield = dedupBars(yield)
 
+		// connected is set once a connection was established. Only a dropped
+		// connection is reconnected, a stream that cannot connect at all ends.
+		connected := false
+		failures := 0
+
+		for {
+			established, stopped, err := c.streamConnection(ctx, topic, symbols, yield)
+			if stopped || ctx.Err() != nil {
 				return
 			}
-			defer wsService.Close()
-		}
 
-		// Connect to WebSocket
-		if err := wsService.Connect(); err != nil {
+			if established {
+				connected = true
+				failures = 0
+			} else {
+				failures++
+			}
+
 			//nolint:exhaustruct // empty struct for error case
-			yield(types.MarketData{}, fmt.Errorf("failed to connect to polygon websocket: %w", err))
+			if err != nil && !yield(types.MarketData{}, err) {
+				return
+			}
 
-			// Emit disconnected status on connection failure
-			c.emitStatus(types.ProviderStatusDisconnected)
+			if !connected || failures >= c.reconnect.maxAttempts {
+				return
+			}
 
-			return
+			// The connection dropped, resubscribe the symbols after a backoff
+			c.emitStatus(types.ProviderStatusReconnecting)
+
+			if !c.reconnect.wait(ctx, failures) {
+				return
+			}
 		}
+	}
+}
 
-		// Emit connected status when WebSocket connection is established
-		c.emitStatus(types.ProviderStatusConnected)
+// streamConnection connects to the Polygon WebSocket, subscribes the symbols
+// to topic and yields their aggregates until the connection drops. It reports
+// whether the connection was established, whether the stream stopped because
+// the context was cancelled or the consumer stopped iterating, and the error
+// that ended the connection.
+func (c *PolygonClient) streamConnection(ctx context.Context, topic polygonws.Topic, symbols []string, yield func(types.MarketData, error) bool) (bool, bool, error) {
+	// Create or use WebSocket service
+	var wsService PolygonWebSocketService
+	if c.wsServiceForTesting != nil {
+		// Use injected service for testing
+		wsService = c.wsServiceForTesting
+	} else {
+		// Create production WebSocket service
+		var err error
+
+		wsService, err = newPolygonWebSocketService(c.apiKey, polygonws.RealTime)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to create websocket service: %w", err)
+		}
+		defer wsService.Close()
+	}
 
-		// Subscribe to aggregate topic for all symbols
-		if err := wsService.Subscribe(topic, symbols...); err != nil {
-			//nolint:exhaustruct // empty struct for error case
-			yield(types.MarketData{}, fmt.Errorf("failed to subscribe to symbols: %w", err))
+	// Connect to WebSocket
+	if err := wsService.Connect(); err != nil {
+		// Emit disconnected status on connection failure
+		c.emitStatus(types.ProviderStatusDisconnected)
 
-			// Emit disconnected status on subscription failure
-			c.emitStatus(types.ProviderStatusDisconnected)
+		return false, false, fmt.Errorf("failed to connect to polygon websocket: %w", err)
+	}
 
-			return
-		}
+	// Emit connected status when WebSocket connection is established
+	c.emitStatus(types.ProviderStatusConnected)
 
-		// Ensure disconnected status is emitted when the stream ends
-		defer c.emitStatus(types.ProviderStatusDisconnected)
+	// Subscribe to aggregate topic for all symbols
+	if err := wsService.Subscribe(topic, symbols...); err != nil {
+		// Emit disconnected status on subscription failure
+		c.emitStatus(types.ProviderStatusDisconnected)
 
-		// Main message loop
-		for {
-			select {
-			case <-ctx.Done():
-				return
+		return false, false, fmt.Errorf("failed to subscribe to symbols: %w", err)
+	}
 
-			case err := <-wsService.Error():
-				//nolint:exhaustruct // empty struct for error case
-				if !yield(types.MarketData{}, fmt.Errorf("websocket error: %w", err)) {
-					return
-				}
+	// Ensure disconnected status is emitted when the connection ends
+	defer c.emitStatus(types.ProviderStatusDisconnected)
+
+	// Main message loop
+	for {
+		select {
+		case <-ctx.Done():
+			return true, true, nil
+
+		case err, ok := <-wsService.Error():
+			if !ok {
+				return true, false, fmt.Errorf("websocket error: connection closed")
+			}
+
+			return true, false, fmt.Errorf("websocket error: %w", err)
+
+		case msg, ok := <-wsService.Output():
+			if !ok {
+				return true, false, fmt.Errorf("websocket error: connection closed")
+			}
 
-			case msg := <-wsService.Output():
-				switch agg := msg.(type) {
-				case polygonmodels.EquityAgg:
-					marketData := convertEquityAggToMarketData(&agg)
-					if !yield(marketData, nil) {
-						return
-					}
-				// Ignore other message types (trades, quotes, control messages)
-				default:
-					// Skip non-aggregate messages
+			switch agg := msg.(type) {
+			case polygonmodels.EquityAgg:
+				marketData := convertEquityAggToMarketData(&agg)
+				if !yield(marketData, nil) {
+					return true, true, nil
 				}
+			// Ignore other message types (trades, quotes, control messages)
+			default:
+				// Skip non-aggregate messages
 			}
 		}
 	}
@@ -431,6 +525,8 @@ func convertEquityAggToMarketData(agg *polygonmodels.EquityAgg) types.MarketData
 		Low:    agg.Low,
 		Close:  agg.Close,
 		Volume: agg.Volume,
+		Bid:    0,
+		Ask:    0,
 	}
 }
 
@@ -363,3 +363,121 @@ func (suite *PolygonStreamTestSuite) TestStreamEmitsDisconnectedOnError() {
 	// Should have received disconnected status on failure
 	suite.Contains(statusChanges, types.ProviderStatusDisconnected, "Should have received disconnected status on connection error")
 }
+
+// reconnectingPolygonWebSocketService serves one session of events per Connect
+// call. Every session but the last reports an error after its events, as the
+// client does when its connection drops.
+type reconnectingPolygonWebSocketService struct {
+	sessions   [][]any
+	connects   int
+	outputChan chan any
+	errorChan  chan error
+}
+
+func (m *reconnectingPolygonWebSocketService) Connect() error {
+	session := min(m.connects, len(m.sessions)-1)
+	m.connects++
+
+	go func() {
+		// Unbuffered, so every event is read before the error is reported
+		for _, event := range m.sessions[session] {
+			m.outputChan <- event
+		}
+
+		if session < len(m.sessions)-1 {
+			m.errorChan <- errors.New("connection reset by peer")
+		}
+	}()
+
+	return nil
+}
+
+func (m *reconnectingPolygonWebSocketService) Subscribe(topic polygonws.Topic, tickers ...string) error {
+	return nil
+}
+
+func (m *reconnectingPolygonWebSocketService) Unsubscribe(topic polygonws.Topic, tickers ...string) error {
+	return nil
+}
+
+func (m *reconnectingPolygonWebSocketService) Output() <-chan any {
+	return m.outputChan
+}
+
+func (m *reconnectingPolygonWebSocketService) Error() <-chan error {
+	return m.errorChan
+}
+
+func (m *reconnectingPolygonWebSocketService) Close() {}
+
+func equityAgg(symbol string, startTimestamp int64, closePrice float64) models.EquityAgg {
+	return models.EquityAgg{
+		Symbol:         symbol,
+		Open:           closePrice,
+		High:           closePrice,
+		Low:            closePrice,
+		Close:          closePrice,
+		Volume:         1000,
+		StartTimestamp: startTimestamp,
+	}
+}
+
+func (suite *PolygonStreamTestSuite) TestStreamReconnectsAfterDrop() {
+	mockWs := &reconnectingPolygonWebSocketService{
+		sessions: [][]any{
+			{equityAgg("AAPL", 1704067200000, 100), equityAgg("AAPL", 1704067260000, 101)},
+			// The resubscribed stream replays the last bar before the connection dropped
+			{equityAgg("AAPL", 1704067260000, 101), equityAgg("AAPL", 1704067320000, 102), equityAgg("AAPL", 1704067380000, 103)},
+		},
+		outputChan: make(chan any),
+		errorChan:  make(chan error),
+	}
+
+	client := NewPolygonClientWithWebSocket("test-api-key", mockWs, []string{"AAPL"}, "1m")
+	client.reconnect = reconnectPolicy{maxAttempts: 3, baseDelay: 10 * time.Millisecond, maxDelay: 50 * time.Millisecond}
+
+	var statusChanges []types.ProviderConnectionStatus
+	client.SetOnStatusChange(func(status types.ProviderConnectionStatus) {
+		statusChanges = append(statusChanges, status)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var closes []float64
+
+	var streamErrors []error
+
+	for data, err := range client.Stream(ctx) {
+		if err != nil {
+			streamErrors = append(streamErrors, err)
+
+			continue
+		}
+
+		closes = append(closes, data.Close)
+		if len(closes) == 4 {
+			break
+		}
+	}
+
+	suite.Equal([]float64{100, 101, 102, 103}, closes, "bars continue across the reconnect without duplicates")
+	suite.Equal(2, mockWs.connects, "the symbols are resubscribed once")
+	suite.Require().Len(streamErrors, 1)
+	suite.Contains(streamErrors[0].Error(), "connection reset by peer")
+	suite.Equal([]types.ProviderConnectionStatus{
+		types.ProviderStatusConnected,
+		types.ProviderStatusDisconnected,
+		types.ProviderStatusReconnecting,
+		types.ProviderStatusConnected,
+		types.ProviderStatusDisconnected,
+	}, statusChanges)
+}
+
+func (suite *PolygonStreamTestSuite) TestReconnectPolicyDelay() {
+	policy := reconnectPolicy{maxAttempts: 5, baseDelay: time.Second, maxDelay: 5 * time.Second}
+
+	for failures, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		suite.Equal(expected, policy.delay(failures), "delay after %d failures", failures)
+	}
+}
@@ -707,3 +707,36 @@ func (suite *PolygonClientTestSuite) TestDownloadNilProgressCallback() {
 		suite.Len(mockW.writtenData, 2)
 	})
 }
+
+func (suite *PolygonClientTestSuite) TestGetHistoricalKlines() {
+	start := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	mockAPI := &mockPolygonAPIClient{
+		iterator: &mockPolygonIterator{
+			aggs: []models.Agg{
+				{Timestamp: models.Millis(start), Open: 470, High: 471, Low: 469, Close: 470.5, Volume: 1000},
+				{Timestamp: models.Millis(start.Add(5 * time.Minute)), Open: 470.5, High: 472, Low: 470, Close: 471, Volume: 800},
+			},
+		},
+	}
+	client := NewPolygonClientWithAPI(mockAPI, []string{"SPY"}, "5m")
+
+	bars, err := client.GetHistoricalKlines(context.Background(), "SPY", "5m", start, start.Add(10*time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().Len(bars, 2)
+	suite.Equal("SPY", bars[0].Symbol)
+	suite.True(start.Equal(bars[0].Time))
+	suite.Equal(471.0, bars[1].Close)
+
+	_, err = client.GetHistoricalKlines(context.Background(), "SPY", "5x", start, start.Add(10*time.Minute))
+	suite.Error(err)
+}
+
+func (suite *PolygonClientTestSuite) TestGetHistoricalKlinesIteratorError() {
+	mockAPI := &mockPolygonAPIClient{
+		iterator: &mockPolygonIterator{err: errors.New("rate limited")},
+	}
+	client := NewPolygonClientWithAPI(mockAPI, []string{"SPY"}, "1m")
+
+	_, err := client.GetHistoricalKlines(context.Background(), "SPY", "1m", time.Now().Add(-time.Hour), time.Now())
+	suite.ErrorContains(err, "rate limited")
+}
@@ -17,6 +17,10 @@ type ProviderType string
 const (
 	ProviderPolygon ProviderType = "polygon"
 	ProviderBinance ProviderType = "binance"
+	// ProviderCoinbase streams candles from the Coinbase Advanced Trade WebSocket.
+	ProviderCoinbase ProviderType = "coinbase"
+	// ProviderAlpaca streams US equity bars from the Alpaca IEX or SIP feed.
+	ProviderAlpaca ProviderType = "alpaca"
 )
 
 type OnDownloadProgress = func(current float64, total float64, message string)
@@ -34,6 +38,11 @@ type Provider interface {
 	// example:
 	// Download(ctx, "AAPL", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC), 1, models.TimespanMinute, onProgress)
 	Download(ctx context.Context, ticker string, startDate time.Time, endDate time.Time, multiplier int, timespan models.Timespan, onProgress OnDownloadProgress) (path string, err error)
+	// GetHistoricalKlines returns the bars of symbol at interval (such as "1m")
+	// that open between start and end, oldest first. Unlike Download it returns
+	// the bars instead of writing them, which suits short backfills such as the
+	// warm-up of a live strategy.
+	GetHistoricalKlines(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]types.MarketData, error)
 	// Stream returns an iterator that yields realtime market data via WebSocket.
 	// Uses Go 1.23+ iter.Seq2 pattern for streaming data.
 	// The iterator yields MarketData and error pairs. Cancel the context to stop streaming.
@@ -48,6 +57,23 @@ type Provider interface {
 	SetOnStatusChange(callback OnStatusChange)
 }
 
+// Replayer is implemented by providers whose Stream replays recorded market
+// data instead of streaming it live. The live engine paces the bars of a
+// replaying provider by its configured replay speed.
+type Replayer interface {
+	// IsReplay reports whether Stream replays recorded data.
+	IsReplay() bool
+}
+
+// TickStreamer is implemented by providers that stream individual trades
+// instead of completed bars. The live engine aggregates the ticks into bars of
+// the provider's configured interval with AggregateTicks and ignores Stream.
+type TickStreamer interface {
+	// StreamTicks returns an iterator that yields trades as they happen.
+	// Cancel the context to stop streaming.
+	StreamTicks(ctx context.Context) iter.Seq2[types.Tick, error]
+}
+
 // NewMarketDataProvider creates a new market data provider based on the provider type.
 func NewMarketDataProvider(providerType ProviderType, config any) (Provider, error) {
 	switch providerType {
@@ -65,6 +91,20 @@ func NewMarketDataProvider(providerType ProviderType, config any) (Provider, err
 		}
 
 		return NewPolygonClient(cfg)
+	case ProviderCoinbase:
+		cfg, ok := config.(*CoinbaseStreamConfig)
+		if !ok || cfg == nil {
+			return nil, fmt.Errorf("invalid config type for coinbase provider, expected non-nil *CoinbaseStreamConfig")
+		}
+
+		return NewCoinbaseClient(cfg)
+	case ProviderAlpaca:
+		cfg, ok := config.(*AlpacaStreamConfig)
+		if !ok || cfg == nil {
+			return nil, fmt.Errorf("invalid config type for alpaca provider, expected non-nil *AlpacaStreamConfig")
+		}
+
+		return NewAlpacaClient(cfg)
 	default:
 		return nil, fmt.Errorf("unsupported market data provider: %s", providerType)
 	}
@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// reconnectPolicy controls how a streaming provider reconnects after its
+// connection drops mid-stream.
+type reconnectPolicy struct {
+	// maxAttempts is the number of consecutive failed reconnects after which
+	// the stream gives up.
+	maxAttempts int
+	// baseDelay is the wait before the first reconnect. It doubles after every
+	// failed attempt.
+	baseDelay time.Duration
+	// maxDelay caps the wait between two reconnects.
+	maxDelay time.Duration
+}
+
+// defaultReconnectPolicy returns the reconnect policy of the streaming providers.
+func defaultReconnectPolicy() reconnectPolicy {
+	return reconnectPolicy{
+		maxAttempts: 10,
+		baseDelay:   time.Second,
+		maxDelay:    time.Minute,
+	}
+}
+
+// delay returns the wait before the reconnect that follows failures failed attempts.
+func (p reconnectPolicy) delay(failures int) time.Duration {
+	delay := p.baseDelay
+
+	for range failures {
+		delay *= 2
+		if delay >= p.maxDelay {
+			return p.maxDelay
+		}
+	}
+
+	return min(delay, p.maxDelay)
+}
+
+// wait blocks for the delay before the next reconnect. It reports false when
+// the context is cancelled first.
+func (p reconnectPolicy) wait(ctx context.Context, failures int) bool {
+	timer := time.NewTimer(p.delay(failures))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// dedupBars wraps yield so that bars a provider replays after reconnecting,
+// i.e. bars not newer than the last bar yielded for their symbol, are dropped.
+// Errors are passed through.
+func dedupBars(yield func(types.MarketData, error) bool) func(types.MarketData, error) bool {
+	last := map[string]time.Time{}
+
+	return func(data types.MarketData, err error) bool {
+		if err != nil {
+			return yield(data, err)
+		}
+
+		if previous, ok := last[data.Symbol]; ok && !data.Time.After(previous) {
+			return true
+		}
+
+		last[data.Symbol] = data.Time
+
+		return yield(data, nil)
+	}
+}
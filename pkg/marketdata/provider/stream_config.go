@@ -25,6 +25,24 @@ type BinanceStreamConfig struct {
 	BaseStreamConfig
 }
 
+// CoinbaseStreamConfig contains configuration for Coinbase streaming market data.
+// Symbols are Coinbase product IDs (e.g. BTC-USD) and the interval must be 5m,
+// the only candle granularity of the Coinbase WebSocket.
+type CoinbaseStreamConfig struct {
+	BaseStreamConfig
+}
+
+// AlpacaStreamConfig contains configuration for Alpaca streaming market data.
+// Symbols are US equity tickers (e.g. AAPL) and the interval must be 1m, the
+// only bar interval of the Alpaca WebSocket.
+type AlpacaStreamConfig struct {
+	BaseStreamConfig
+
+	ApiKey    string `json:"apiKey" jsonschema:"title=API Key,description=Alpaca API key ID,required" keychain:"true" validate:"required"`
+	SecretKey string `json:"secretKey" jsonschema:"title=Secret Key,description=Alpaca API secret key,required" keychain:"true" validate:"required"`
+	Feed      string `json:"feed,omitempty" jsonschema:"title=Feed,description=Market data feed: iex for the free IEX feed or sip for all US exchanges (optional). Defaults to iex.,enum=iex,enum=sip" validate:"omitempty,oneof=iex sip"`
+}
+
 // Validate validates the BaseStreamConfig fields.
 func (c *BaseStreamConfig) Validate() error {
 	validate := validator.New()
@@ -50,6 +68,33 @@ func (c *BinanceStreamConfig) Validate() error {
 	return c.BaseStreamConfig.Validate()
 }
 
+// Validate validates the CoinbaseStreamConfig.
+func (c *CoinbaseStreamConfig) Validate() error {
+	if err := c.BaseStreamConfig.Validate(); err != nil {
+		return err
+	}
+
+	if c.Interval != CoinbaseCandleInterval {
+		return fmt.Errorf("invalid config: coinbase only supports the %s interval, got %s", CoinbaseCandleInterval, c.Interval)
+	}
+
+	return nil
+}
+
+// Validate validates the AlpacaStreamConfig.
+func (c *AlpacaStreamConfig) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(c); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	if c.Interval != AlpacaStreamInterval {
+		return fmt.Errorf("invalid config: alpaca only supports the %s interval, got %s", AlpacaStreamInterval, c.Interval)
+	}
+
+	return nil
+}
+
 // ParsePolygonStreamConfig parses JSON into a PolygonStreamConfig.
 func ParsePolygonStreamConfig(jsonConfig string) (*PolygonStreamConfig, error) {
 	var config PolygonStreamConfig
@@ -77,3 +122,31 @@ func ParseBinanceStreamConfig(jsonConfig string) (*BinanceStreamConfig, error) {
 
 	return &config, nil
 }
+
+// ParseCoinbaseStreamConfig parses JSON into a CoinbaseStreamConfig.
+func ParseCoinbaseStreamConfig(jsonConfig string) (*CoinbaseStreamConfig, error) {
+	var config CoinbaseStreamConfig
+	if err := json.Unmarshal([]byte(jsonConfig), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// ParseAlpacaStreamConfig parses JSON into an AlpacaStreamConfig.
+func ParseAlpacaStreamConfig(jsonConfig string) (*AlpacaStreamConfig, error) {
+	var config AlpacaStreamConfig
+	if err := json.Unmarshal([]byte(jsonConfig), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
@@ -151,6 +151,31 @@ func (suite *StreamConfigTestSuite) TestParseBinanceStreamConfig_InvalidJSON() {
 	suite.Contains(err.Error(), "failed to parse JSON config")
 }
 
+func (suite *StreamConfigTestSuite) TestParseCoinbaseStreamConfig_Valid() {
+	jsonConfig := `{
+		"symbols": ["BTC-USD", "ETH-USD"],
+		"interval": "5m"
+	}`
+
+	config, err := ParseCoinbaseStreamConfig(jsonConfig)
+
+	suite.NoError(err)
+	suite.Equal([]string{"BTC-USD", "ETH-USD"}, config.Symbols)
+	suite.Equal("5m", config.Interval)
+}
+
+func (suite *StreamConfigTestSuite) TestParseCoinbaseStreamConfig_UnsupportedInterval() {
+	jsonConfig := `{
+		"symbols": ["BTC-USD"],
+		"interval": "1m"
+	}`
+
+	_, err := ParseCoinbaseStreamConfig(jsonConfig)
+
+	suite.Error(err)
+	suite.Contains(err.Error(), "coinbase only supports the 5m interval")
+}
+
 func (suite *StreamConfigTestSuite) TestPolygonStreamConfig_KeychainFields() {
 	//nolint:exhaustruct // Empty struct for field introspection
 	fields := strategy.GetKeychainFields(PolygonStreamConfig{})
@@ -15,6 +15,12 @@ func GetStreamConfigSchema(providerName string) (string, error) {
 	case ProviderBinance:
 		//nolint:exhaustruct // Empty struct is intentional for schema generation
 		return strategy.ToJSONSchema(BinanceStreamConfig{})
+	case ProviderCoinbase:
+		//nolint:exhaustruct // Empty struct is intentional for schema generation
+		return strategy.ToJSONSchema(CoinbaseStreamConfig{})
+	case ProviderAlpaca:
+		//nolint:exhaustruct // Empty struct is intentional for schema generation
+		return strategy.ToJSONSchema(AlpacaStreamConfig{})
 	default:
 		return "", fmt.Errorf("unsupported market data provider: %s", providerName)
 	}
@@ -29,6 +35,12 @@ func GetStreamKeychainFields(providerName string) ([]string, error) {
 	case ProviderBinance:
 		//nolint:exhaustruct // Empty struct is intentional for field introspection
 		return strategy.GetKeychainFields(BinanceStreamConfig{}), nil
+	case ProviderCoinbase:
+		//nolint:exhaustruct // Empty struct is intentional for field introspection
+		return strategy.GetKeychainFields(CoinbaseStreamConfig{}), nil
+	case ProviderAlpaca:
+		//nolint:exhaustruct // Empty struct is intentional for field introspection
+		return strategy.GetKeychainFields(AlpacaStreamConfig{}), nil
 	default:
 		return nil, fmt.Errorf("unsupported market data provider: %s", providerName)
 	}
@@ -41,6 +53,10 @@ func ParseStreamConfig(providerName string, jsonConfig string) (any, error) {
 		return ParsePolygonStreamConfig(jsonConfig)
 	case ProviderBinance:
 		return ParseBinanceStreamConfig(jsonConfig)
+	case ProviderCoinbase:
+		return ParseCoinbaseStreamConfig(jsonConfig)
+	case ProviderAlpaca:
+		return ParseAlpacaStreamConfig(jsonConfig)
 	default:
 		return nil, fmt.Errorf("unsupported market data provider: %s", providerName)
 	}
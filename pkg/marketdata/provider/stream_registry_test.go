@@ -128,6 +128,43 @@ func (suite *StreamRegistryTestSuite) TestParseStreamConfig_Binance() {
 	suite.Equal("1h", binanceConfig.Interval)
 }
 
+func (suite *StreamRegistryTestSuite) TestParseStreamConfig_Coinbase() {
+	config, err := ParseStreamConfig("coinbase", `{"symbols": ["BTC-USD"], "interval": "5m"}`)
+
+	suite.NoError(err)
+
+	coinbaseConfig, ok := config.(*CoinbaseStreamConfig)
+	suite.True(ok)
+	suite.Equal([]string{"BTC-USD"}, coinbaseConfig.Symbols)
+
+	schema, err := GetStreamConfigSchema("coinbase")
+	suite.NoError(err)
+	suite.Contains(schema, "symbols")
+
+	fields, err := GetStreamKeychainFields("coinbase")
+	suite.NoError(err)
+	suite.Empty(fields)
+}
+
+func (suite *StreamRegistryTestSuite) TestParseStreamConfig_Alpaca() {
+	config, err := ParseStreamConfig("alpaca", `{"symbols": ["AAPL"], "interval": "1m", "apiKey": "key", "secretKey": "secret", "feed": "sip"}`)
+
+	suite.NoError(err)
+
+	alpacaConfig, ok := config.(*AlpacaStreamConfig)
+	suite.True(ok)
+	suite.Equal([]string{"AAPL"}, alpacaConfig.Symbols)
+	suite.Equal("sip", alpacaConfig.Feed)
+
+	schema, err := GetStreamConfigSchema("alpaca")
+	suite.NoError(err)
+	suite.Contains(schema, "feed")
+
+	fields, err := GetStreamKeychainFields("alpaca")
+	suite.NoError(err)
+	suite.ElementsMatch([]string{"apiKey", "secretKey"}, fields)
+}
+
 func (suite *StreamRegistryTestSuite) TestParseStreamConfig_InvalidProvider() {
 	_, err := ParseStreamConfig("invalid", `{}`)
 
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"iter"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+)
+
+// TickAggregator groups ticks into OHLCV bars of a fixed interval. Each symbol
+// has its own bar, which is completed by the first tick of that symbol in a
+// later interval. Bars are timestamped with the start of their interval.
+type TickAggregator struct {
+	interval time.Duration
+	// bars holds the bar being built of each symbol.
+	bars map[string]*types.MarketData
+}
+
+// NewTickAggregator returns an aggregator building bars of interval.
+func NewTickAggregator(interval time.Duration) *TickAggregator {
+	return &TickAggregator{
+		interval: interval,
+		bars:     map[string]*types.MarketData{},
+	}
+}
+
+// Add folds a tick into the bar of its symbol. It returns the symbol's
+// previous bar when the tick starts a new interval. Ticks older than the bar
+// being built belong to a bar that was already returned and are dropped.
+func (a *TickAggregator) Add(tick types.Tick) (types.MarketData, bool) {
+	start := tick.Time.Truncate(a.interval)
+
+	bar, ok := a.bars[tick.Symbol]
+	if ok && start.Before(bar.Time) {
+		return types.MarketData{}, false
+	}
+
+	if ok && start.Equal(bar.Time) {
+		bar.High = max(bar.High, tick.Price)
+		bar.Low = min(bar.Low, tick.Price)
+		bar.Close = tick.Price
+		bar.Volume += tick.Size
+
+		return types.MarketData{}, false
+	}
+
+	a.bars[tick.Symbol] = &types.MarketData{
+		Id:     "",
+		Symbol: tick.Symbol,
+		Time:   start,
+		Open:   tick.Price,
+		High:   tick.Price,
+		Low:    tick.Price,
+		Close:  tick.Price,
+		Volume: tick.Size,
+		Bid:    0,
+		Ask:    0,
+	}
+
+	if !ok {
+		return types.MarketData{}, false
+	}
+
+	return *bar, true
+}
+
+// AggregateTicks turns a tick stream into a stream of completed bars of
+// interval. Errors are passed through, and the incomplete bars left when the
+// tick stream ends are discarded.
+func AggregateTicks(ticks iter.Seq2[types.Tick, error], interval time.Duration) iter.Seq2[types.MarketData, error] {
+	return func(yield func(types.MarketData, error) bool) {
+		aggregator := NewTickAggregator(interval)
+
+		for tick, err := range ticks {
+			if err != nil {
+				if !yield(types.MarketData{}, err) {
+					return
+				}
+
+				continue
+			}
+
+			if bar, completed := aggregator.Add(tick); completed {
+				if !yield(bar, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type TickAggregatorTestSuite struct {
+	suite.Suite
+}
+
+func TestTickAggregatorTestSuite(t *testing.T) {
+	suite.Run(t, new(TickAggregatorTestSuite))
+}
+
+// tickStream yields the ticks, then err if it is not nil.
+func tickStream(ticks []types.Tick, err error) iter.Seq2[types.Tick, error] {
+	return func(yield func(types.Tick, error) bool) {
+		for _, tick := range ticks {
+			if !yield(tick, nil) {
+				return
+			}
+		}
+
+		if err != nil {
+			yield(types.Tick{}, err)
+		}
+	}
+}
+
+// collectBars drains a bar stream.
+func collectBars(stream iter.Seq2[types.MarketData, error]) ([]types.MarketData, []error) {
+	var bars []types.MarketData
+
+	var errs []error
+
+	for bar, err := range stream {
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		bars = append(bars, bar)
+	}
+
+	return bars, errs
+}
+
+func (suite *TickAggregatorTestSuite) TestAggregatesTicksAcrossIntervalBoundaries() {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	tick := func(offset time.Duration, price, size float64) types.Tick {
+		return types.Tick{Symbol: "BTCUSDT", Price: price, Size: size, Time: start.Add(offset)}
+	}
+
+	ticks := []types.Tick{
+		// 10:00 bar
+		tick(5*time.Second, 100, 1),
+		tick(20*time.Second, 104, 2),
+		tick(40*time.Second, 98, 0.5),
+		tick(59*time.Second, 101, 1.5),
+		// 10:01 bar, completed by the first 10:02 tick
+		tick(time.Minute, 101, 3),
+		tick(time.Minute+30*time.Second, 99, 1),
+		// 10:02 bar, incomplete when the stream ends
+		tick(2*time.Minute+time.Second, 105, 1),
+	}
+
+	bars, errs := collectBars(AggregateTicks(tickStream(ticks, nil), time.Minute))
+	suite.Empty(errs)
+	suite.Equal([]types.MarketData{
+		{Symbol: "BTCUSDT", Time: start, Open: 100, High: 104, Low: 98, Close: 101, Volume: 5},
+		{Symbol: "BTCUSDT", Time: start.Add(time.Minute), Open: 101, High: 101, Low: 99, Close: 99, Volume: 4},
+	}, bars)
+}
+
+func (suite *TickAggregatorTestSuite) TestAggregatesSymbolsSeparately() {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	aggregator := NewTickAggregator(time.Minute)
+
+	_, completed := aggregator.Add(types.Tick{Symbol: "BTCUSDT", Price: 100, Size: 1, Time: start})
+	suite.False(completed)
+	_, completed = aggregator.Add(types.Tick{Symbol: "ETHUSDT", Price: 10, Size: 1, Time: start.Add(time.Second)})
+	suite.False(completed)
+
+	// A new interval of one symbol completes only that symbol's bar
+	bar, completed := aggregator.Add(types.Tick{Symbol: "ETHUSDT", Price: 11, Size: 1, Time: start.Add(time.Minute)})
+	suite.True(completed)
+	suite.Equal("ETHUSDT", bar.Symbol)
+	suite.Equal(10.0, bar.Close)
+
+	// Late ticks of a completed bar are dropped
+	_, completed = aggregator.Add(types.Tick{Symbol: "ETHUSDT", Price: 50, Size: 1, Time: start.Add(2 * time.Second)})
+	suite.False(completed)
+
+	bar, completed = aggregator.Add(types.Tick{Symbol: "ETHUSDT", Price: 12, Size: 1, Time: start.Add(2 * time.Minute)})
+	suite.True(completed)
+	suite.Equal(11.0, bar.High)
+	suite.Equal(1.0, bar.Volume)
+
+	bar, completed = aggregator.Add(types.Tick{Symbol: "BTCUSDT", Price: 101, Size: 1, Time: start.Add(2 * time.Minute)})
+	suite.True(completed)
+	suite.Equal("BTCUSDT", bar.Symbol)
+	suite.Equal(start, bar.Time)
+}
+
+func (suite *TickAggregatorTestSuite) TestPassesErrorsThrough() {
+	streamErr := errors.New("connection lost")
+
+	bars, errs := collectBars(AggregateTicks(tickStream(nil, streamErr), time.Minute))
+	suite.Empty(bars)
+	suite.Equal([]error{streamErr}, errs)
+}
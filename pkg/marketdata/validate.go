@@ -0,0 +1,234 @@
+package marketdata
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/polygon-io/client-go/rest/models"
+)
+
+// DataGap is a run of bars missing between two consecutive bars of a symbol.
+type DataGap struct {
+	Symbol string
+	// From and To are the times of the bars before and after the gap.
+	From time.Time
+	To   time.Time
+	// MissingBars is the number of bars expected between From and To.
+	MissingBars int
+}
+
+// DuplicateBar is a (symbol, time) pair that appears in more than one row.
+type DuplicateBar struct {
+	Symbol string
+	Time   time.Time
+	Count  int
+}
+
+// InvalidBar is a row whose prices or volume are impossible, e.g. a high below its low.
+type InvalidBar struct {
+	Symbol string
+	Time   time.Time
+	Reason string
+}
+
+// ValidationReport is the result of checking a downloaded data file.
+type ValidationReport struct {
+	Path string
+	Bars int
+	// Interval is the expected time between bars. Gaps are not checked when it
+	// is 0, which is the case for calendar timespans such as months.
+	Interval      time.Duration
+	Gaps          []DataGap
+	Duplicates    []DuplicateBar
+	InvalidPrices []InvalidBar
+}
+
+// Valid reports whether the data has no gaps, duplicates or invalid prices.
+func (r ValidationReport) Valid() bool {
+	return len(r.Gaps) == 0 && len(r.Duplicates) == 0 && len(r.InvalidPrices) == 0
+}
+
+// Validate checks the file written by a download with params for missing
+// bars, duplicate (symbol, time) rows and out-of-range prices. Periods where
+// the market is closed, such as nights and weekends of stock data, are
+// reported as gaps as well.
+func (c *Client) Validate(params DownloadParams) (ValidationReport, error) {
+	interval, _ := barDuration(params.Multiplier, params.Timespan)
+
+	return ValidateFile(c.outputPath(params), interval)
+}
+
+// ValidateFile checks a parquet file of market data for the problems described
+// at Client.Validate. interval is the expected time between bars; 0 skips the
+// gap check.
+func ValidateFile(path string, interval time.Duration) (ValidationReport, error) {
+	report := ValidationReport{
+		Path:          path,
+		Bars:          0,
+		Interval:      interval,
+		Gaps:          nil,
+		Duplicates:    nil,
+		InvalidPrices: nil,
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return report, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	db, err := sql.Open("duckdb", ":memory:")
+	if err != nil {
+		return report, fmt.Errorf("failed to open DuckDB connection: %w", err)
+	}
+	defer db.Close()
+
+	source := fmt.Sprintf("read_parquet('%s')", path)
+
+	if err := db.QueryRow(fmt.Sprintf(`SELECT count(*) FROM %s`, source)).Scan(&report.Bars); err != nil {
+		return report, fmt.Errorf("failed to count bars: %w", err)
+	}
+
+	if report.Duplicates, err = findDuplicateBars(db, source); err != nil {
+		return report, err
+	}
+
+	if report.InvalidPrices, err = findInvalidBars(db, source); err != nil {
+		return report, err
+	}
+
+	if interval > 0 {
+		if report.Gaps, err = findGaps(db, source, interval); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// findDuplicateBars returns the (symbol, time) pairs stored more than once.
+func findDuplicateBars(db *sql.DB, source string) ([]DuplicateBar, error) {
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT symbol, time, count(*)
+		FROM %s
+		GROUP BY symbol, time
+		HAVING count(*) > 1
+		ORDER BY symbol, time
+	`, source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query duplicate bars: %w", err)
+	}
+	defer rows.Close()
+
+	var duplicates []DuplicateBar
+
+	for rows.Next() {
+		var duplicate DuplicateBar
+		if err := rows.Scan(&duplicate.Symbol, &duplicate.Time, &duplicate.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate bar: %w", err)
+		}
+
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return duplicates, rows.Err()
+}
+
+// findInvalidBars returns the rows with non-positive prices, a high below the
+// low, an open or close outside the high-low range, or a negative volume.
+func findInvalidBars(db *sql.DB, source string) ([]InvalidBar, error) {
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT symbol, time, CASE
+			WHEN open <= 0 OR high <= 0 OR low <= 0 OR close <= 0 THEN 'non-positive price'
+			WHEN high < low THEN 'high below low'
+			WHEN open < low OR open > high THEN 'open outside high-low range'
+			WHEN close < low OR close > high THEN 'close outside high-low range'
+			ELSE 'negative volume'
+		END
+		FROM %s
+		WHERE open <= 0 OR high <= 0 OR low <= 0 OR close <= 0
+			OR high < low
+			OR open < low OR open > high
+			OR close < low OR close > high
+			OR volume < 0
+		ORDER BY symbol, time
+	`, source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invalid bars: %w", err)
+	}
+	defer rows.Close()
+
+	var invalid []InvalidBar
+
+	for rows.Next() {
+		var bar InvalidBar
+		if err := rows.Scan(&bar.Symbol, &bar.Time, &bar.Reason); err != nil {
+			return nil, fmt.Errorf("failed to scan invalid bar: %w", err)
+		}
+
+		invalid = append(invalid, bar)
+	}
+
+	return invalid, rows.Err()
+}
+
+// findGaps returns the gaps between consecutive bars of each symbol that are
+// longer than interval.
+func findGaps(db *sql.DB, source string, interval time.Duration) ([]DataGap, error) {
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT symbol, previous, time, epoch_ms(time) - epoch_ms(previous)
+		FROM (
+			SELECT symbol, time, lag(time) OVER (PARTITION BY symbol ORDER BY time) AS previous
+			FROM (SELECT DISTINCT symbol, time FROM %s)
+		)
+		WHERE previous IS NOT NULL AND epoch_ms(time) - epoch_ms(previous) > ?
+		ORDER BY symbol, time
+	`, source), interval.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gaps: %w", err)
+	}
+	defer rows.Close()
+
+	var gaps []DataGap
+
+	for rows.Next() {
+		var gap DataGap
+
+		var elapsedMillis int64
+		if err := rows.Scan(&gap.Symbol, &gap.From, &gap.To, &elapsedMillis); err != nil {
+			return nil, fmt.Errorf("failed to scan gap: %w", err)
+		}
+
+		gap.MissingBars = int(time.Duration(elapsedMillis)*time.Millisecond/interval) - 1
+		if time.Duration(elapsedMillis)*time.Millisecond%interval != 0 {
+			gap.MissingBars++
+		}
+
+		gaps = append(gaps, gap)
+	}
+
+	return gaps, rows.Err()
+}
+
+// barDuration returns the length of a bar of multiplier timespans. It reports
+// false for calendar timespans, whose length varies.
+func barDuration(multiplier int, timespan models.Timespan) (time.Duration, bool) {
+	var unit time.Duration
+
+	switch timespan {
+	case models.Second:
+		unit = time.Second
+	case models.Minute:
+		unit = time.Minute
+	case models.Hour:
+		unit = time.Hour
+	case models.Day:
+		unit = 24 * time.Hour
+	case models.Week:
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, false
+	}
+
+	return time.Duration(multiplier) * unit, true
+}
@@ -0,0 +1,98 @@
+package marketdata
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/polygon-io/client-go/rest/models"
+	"github.com/rxtech-lab/argo-trading/internal/types"
+	"github.com/rxtech-lab/argo-trading/pkg/marketdata/writer"
+)
+
+// writeBars writes bars to a parquet file in dir named like the output of a
+// one minute AAPL download from 2024-01-01 to 2024-01-02.
+func (suite *ClientTestSuite) writeBars(dir string, bars []types.MarketData) {
+	w := writer.NewDuckDBWriter(filepath.Join(dir, "AAPL_2024-01-01_2024-01-02_1_minute.parquet"))
+	suite.Require().NoError(w.Initialize())
+
+	defer w.Close()
+
+	for _, bar := range bars {
+		suite.Require().NoError(w.Write(bar))
+	}
+
+	_, err := w.Finalize()
+	suite.Require().NoError(err)
+}
+
+// minuteBar returns a valid AAPL bar at the given minute after 2024-01-01 14:30 UTC.
+func minuteBar(minute int) types.MarketData {
+	return types.MarketData{
+		Id:     "",
+		Symbol: "AAPL",
+		Time:   time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC).Add(time.Duration(minute) * time.Minute),
+		Open:   100,
+		High:   101,
+		Low:    99,
+		Close:  100.5,
+		Volume: 1000,
+		Bid:    0,
+		Ask:    0,
+	}
+}
+
+func (suite *ClientTestSuite) TestValidateFlagsGapsDuplicatesAndInvalidPrices() {
+	dir := suite.T().TempDir()
+
+	invalid := minuteBar(5)
+	invalid.High = 98
+
+	// Minutes 2 and 3 are missing and minute 1 is stored twice
+	suite.writeBars(dir, []types.MarketData{
+		minuteBar(0), minuteBar(1), minuteBar(1), minuteBar(4), invalid, minuteBar(6),
+	})
+
+	client := &Client{config: ClientConfig{WriterType: WriterDuckDB, DataPath: dir}}
+
+	report, err := client.Validate(DownloadParams{
+		Ticker:     "AAPL",
+		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Multiplier: 1,
+		Timespan:   models.Minute,
+	})
+	suite.Require().NoError(err)
+
+	suite.False(report.Valid())
+	suite.Equal(6, report.Bars)
+	suite.Equal(time.Minute, report.Interval)
+
+	suite.Require().Len(report.Gaps, 1)
+	suite.Equal("AAPL", report.Gaps[0].Symbol)
+	suite.True(minuteBar(1).Time.Equal(report.Gaps[0].From))
+	suite.True(minuteBar(4).Time.Equal(report.Gaps[0].To))
+	suite.Equal(2, report.Gaps[0].MissingBars)
+
+	suite.Require().Len(report.Duplicates, 1)
+	suite.True(minuteBar(1).Time.Equal(report.Duplicates[0].Time))
+	suite.Equal(2, report.Duplicates[0].Count)
+
+	suite.Require().Len(report.InvalidPrices, 1)
+	suite.True(invalid.Time.Equal(report.InvalidPrices[0].Time))
+	suite.Equal("high below low", report.InvalidPrices[0].Reason)
+}
+
+func (suite *ClientTestSuite) TestValidateCleanData() {
+	dir := suite.T().TempDir()
+	suite.writeBars(dir, []types.MarketData{minuteBar(0), minuteBar(1), minuteBar(2)})
+
+	report, err := ValidateFile(filepath.Join(dir, "AAPL_2024-01-01_2024-01-02_1_minute.parquet"), time.Minute)
+	suite.Require().NoError(err)
+	suite.True(report.Valid())
+	suite.Equal(3, report.Bars)
+}
+
+func (suite *ClientTestSuite) TestValidateMissingFile() {
+	_, err := ValidateFile(filepath.Join(suite.T().TempDir(), "missing.parquet"), time.Minute)
+	suite.Error(err)
+}
@@ -6,19 +6,42 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	_ "github.com/marcboeker/go-duckdb"
 	"github.com/rxtech-lab/argo-trading/internal/types"
 )
 
+// StreamingFlushConfig controls when the streaming writer moves buffered rows
+// into its parquet file.
+type StreamingFlushConfig struct {
+	// FlushRows flushes once this many rows are buffered. Values <= 1 flush on
+	// every write, so the parquet file always holds the latest bar.
+	FlushRows int `json:"flush_rows" yaml:"flush_rows" jsonschema:"description=Number of buffered rows that triggers a flush to the parquet file (values up to 1 flush every row),minimum=0,default=1"`
+
+	// FlushIntervalSeconds flushes buffered rows at least this often, even when
+	// fewer than FlushRows are buffered. Zero only flushes by row count.
+	FlushIntervalSeconds int `json:"flush_interval_seconds" yaml:"flush_interval_seconds" jsonschema:"description=Maximum number of seconds rows stay buffered before they are flushed (0 only flushes by row count),minimum=0,default=0"`
+}
+
 // StreamingDuckDBWriter implements MarketDataWriter for streaming data with append support.
 // It writes finalized candles to a parquet file that persists across restarts.
 // The file is named: stream_data_{provider}_{interval}.parquet.
+//
+// Rows are buffered in memory until a flush merges them into the parquet file.
+// A flush writes a temporary file and renames it over the parquet file, so the
+// file stays readable at all times, also after a crash, which loses only the
+// rows buffered since the last flush.
 type StreamingDuckDBWriter struct {
-	db         *sql.DB
-	outputPath string // Full path: {dataDir}/stream_data_{provider}_{interval}.parquet
-	mu         sync.Mutex
+	db          *sql.DB
+	outputPath  string // Full path: {dataDir}/stream_data_{provider}_{interval}.parquet
+	mu          sync.Mutex
+	flushConfig StreamingFlushConfig
+	pending     int   // Rows buffered since the last flush
+	flushErr    error // Error of the last interval flush, returned by the next call
+	stop        chan struct{}
+	done        chan struct{}
 }
 
 // NewStreamingDuckDBWriter creates a new StreamingDuckDBWriter.
@@ -30,14 +53,29 @@ func NewStreamingDuckDBWriter(dataDir, providerName, interval string) *Streaming
 	outputPath := filepath.Join(dataDir, filename)
 
 	return &StreamingDuckDBWriter{
-		db:         nil,
-		outputPath: outputPath,
-		mu:         sync.Mutex{},
+		db:          nil,
+		outputPath:  outputPath,
+		mu:          sync.Mutex{},
+		flushConfig: StreamingFlushConfig{FlushRows: 1, FlushIntervalSeconds: 0},
+		pending:     0,
+		flushErr:    nil,
+		stop:        nil,
+		done:        nil,
 	}
 }
 
+// SetFlushConfig sets when buffered rows are flushed. It must be called before Initialize.
+func (w *StreamingDuckDBWriter) SetFlushConfig(config StreamingFlushConfig) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.flushConfig = config
+}
+
 // Initialize sets up the streaming writer.
-// It opens a DuckDB connection and loads existing data from the parquet file if it exists.
+// It opens a DuckDB connection for the row buffer. New rows are merged into an
+// existing parquet file; a file that cannot be read is moved aside to
+// {outputPath}.corrupt and replaced.
 func (w *StreamingDuckDBWriter) Initialize() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -76,24 +114,35 @@ func (w *StreamingDuckDBWriter) Initialize() error {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
-	// Load existing data from parquet file if it exists
+	// A crash during a flush can leave the temporary file behind
+	_ = os.Remove(w.tempPath())
+
 	if _, err := os.Stat(w.outputPath); err == nil {
-		_, err = w.db.Exec(fmt.Sprintf(`
-			INSERT INTO market_data
-			SELECT * FROM read_parquet('%s')
-			ON CONFLICT (symbol, time) DO NOTHING
-		`, w.outputPath))
-		if err != nil {
-			// If loading fails, log but continue (file might be corrupted or empty)
-			// We'll overwrite it with new data
-			_ = err // Ignore error, start fresh
+		var count int
+		if err := w.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM read_parquet('%s')", w.outputPath)).Scan(&count); err != nil {
+			if err := os.Rename(w.outputPath, w.outputPath+".corrupt"); err != nil {
+				w.db.Close()
+
+				return fmt.Errorf("failed to move unreadable parquet file aside: %w", err)
+			}
 		}
 	}
 
+	w.pending = 0
+	w.flushErr = nil
+
+	if w.flushConfig.FlushIntervalSeconds > 0 {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+
+		go w.flushPeriodically(time.Duration(w.flushConfig.FlushIntervalSeconds)*time.Second, w.stop, w.done)
+	}
+
 	return nil
 }
 
-// Write persists a single market data point and exports to parquet.
+// Write buffers a single market data point and flushes the buffer to parquet
+// once it holds FlushRows rows.
 // This should only be called for finalized candles (IsFinal=true).
 func (w *StreamingDuckDBWriter) Write(data types.MarketData) error {
 	w.mu.Lock()
@@ -103,11 +152,20 @@ func (w *StreamingDuckDBWriter) Write(data types.MarketData) error {
 		return fmt.Errorf("writer not initialized")
 	}
 
+	if err := w.takeFlushErr(); err != nil {
+		return err
+	}
+
 	if err := w.insertRow(data); err != nil {
 		return err
 	}
 
-	// Export to parquet after each write
+	w.pending++
+
+	if w.pending < w.flushConfig.FlushRows {
+		return nil
+	}
+
 	if err := w.exportToParquet(); err != nil {
 		return fmt.Errorf("failed to export to parquet: %w", err)
 	}
@@ -171,6 +229,8 @@ func (w *StreamingDuckDBWriter) WriteBatch(data []types.MarketData) error {
 		return fmt.Errorf("failed to commit batch: %w", err)
 	}
 
+	w.pending += len(data)
+
 	if err := w.exportToParquet(); err != nil {
 		return fmt.Errorf("failed to export to parquet: %w", err)
 	}
@@ -178,7 +238,7 @@ func (w *StreamingDuckDBWriter) WriteBatch(data []types.MarketData) error {
 	return nil
 }
 
-// Flush forces an export to parquet.
+// Flush forces an export of the buffered rows to parquet.
 func (w *StreamingDuckDBWriter) Flush() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -187,6 +247,10 @@ func (w *StreamingDuckDBWriter) Flush() error {
 		return fmt.Errorf("writer not initialized")
 	}
 
+	if err := w.takeFlushErr(); err != nil {
+		return err
+	}
+
 	return w.exportToParquet()
 }
 
@@ -212,22 +276,79 @@ func (w *StreamingDuckDBWriter) GetOutputPath() string {
 	return w.outputPath
 }
 
-// Close releases database resources.
+// Close flushes the buffered rows and releases database resources.
 func (w *StreamingDuckDBWriter) Close() error {
+	// The interval flush takes the lock, so it is stopped before locking
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+
+		w.stop = nil
+		w.done = nil
+	}
+
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	if w.db != nil {
+		flushErr := w.exportToParquet()
+
 		if err := w.db.Close(); err != nil {
 			return fmt.Errorf("failed to close database: %w", err)
 		}
 
 		w.db = nil
+
+		if flushErr != nil {
+			return flushErr
+		}
 	}
 
 	return nil
 }
 
+// flushPeriodically flushes the buffered rows every interval until stop is closed.
+func (w *StreamingDuckDBWriter) flushPeriodically(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+
+			if w.db != nil && w.pending > 0 {
+				if err := w.exportToParquet(); err != nil && w.flushErr == nil {
+					w.flushErr = err
+				}
+			}
+
+			w.mu.Unlock()
+		}
+	}
+}
+
+// takeFlushErr returns and clears the error of the last interval flush. Caller must hold w.mu.
+func (w *StreamingDuckDBWriter) takeFlushErr() error {
+	err := w.flushErr
+	w.flushErr = nil
+
+	if err != nil {
+		return fmt.Errorf("interval flush failed: %w", err)
+	}
+
+	return nil
+}
+
+// tempPath is the file a flush writes before renaming it over the output file.
+func (w *StreamingDuckDBWriter) tempPath() string {
+	return w.outputPath + ".tmp"
+}
+
 // insertRow upserts a single row into the in-memory table. Caller must hold w.mu.
 func (w *StreamingDuckDBWriter) insertRow(data types.MarketData) error {
 	_, err := w.db.Exec(`
@@ -248,16 +369,43 @@ func (w *StreamingDuckDBWriter) insertRow(data types.MarketData) error {
 	return nil
 }
 
-// exportToParquet exports the current data to the parquet file.
+// exportToParquet merges the buffered rows into the parquet file and empties
+// the buffer. Buffered rows replace file rows of the same symbol and time.
+// Caller must hold w.mu.
 func (w *StreamingDuckDBWriter) exportToParquet() error {
+	_, statErr := os.Stat(w.outputPath)
+	if statErr == nil && w.pending == 0 {
+		return nil
+	}
+
+	source := "SELECT id, time, symbol, open, high, low, close, volume FROM market_data"
+	if statErr == nil {
+		source = fmt.Sprintf(`
+			SELECT id, time, symbol, open, high, low, close, volume FROM read_parquet('%s') f
+			WHERE NOT EXISTS (SELECT 1 FROM market_data m WHERE m.symbol = f.symbol AND m.time = f.time)
+			UNION ALL
+			%s
+		`, w.outputPath, source)
+	}
+
 	_, err := w.db.Exec(fmt.Sprintf(`
-		COPY (SELECT * FROM market_data ORDER BY time ASC)
+		COPY (%s ORDER BY time ASC)
 		TO '%s' (FORMAT PARQUET)
-	`, w.outputPath))
+	`, source, w.tempPath()))
 	if err != nil {
 		return fmt.Errorf("failed to export to parquet: %w", err)
 	}
 
+	if err := os.Rename(w.tempPath(), w.outputPath); err != nil {
+		return fmt.Errorf("failed to replace parquet file: %w", err)
+	}
+
+	if _, err := w.db.Exec("DELETE FROM market_data"); err != nil {
+		return fmt.Errorf("failed to clear flushed rows: %w", err)
+	}
+
+	w.pending = 0
+
 	return nil
 }
 
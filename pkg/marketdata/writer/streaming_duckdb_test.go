@@ -545,3 +545,128 @@ func (suite *StreamingDuckDBWriterTestSuite) TestLargeDataset() {
 	suite.NoError(err)
 	suite.Equal(1000, count)
 }
+
+func (suite *StreamingDuckDBWriterTestSuite) countRows(path string) int {
+	db, err := sql.Open("duckdb", ":memory:")
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM read_parquet('" + path + "')").Scan(&count)
+	suite.Require().NoError(err)
+
+	return count
+}
+
+func (suite *StreamingDuckDBWriterTestSuite) TestFlushByRowCount() {
+	writer := NewStreamingDuckDBWriter(suite.tempDir, "binance", "flush_rows")
+	writer.SetFlushConfig(StreamingFlushConfig{FlushRows: 1000, FlushIntervalSeconds: 0})
+	suite.Require().NoError(writer.Initialize())
+	defer writer.Close()
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 10000; i++ {
+		err := writer.Write(types.MarketData{
+			Symbol: "BTCUSDT",
+			Time:   baseTime.Add(time.Duration(i) * time.Minute),
+			Open:   42000.0,
+			High:   42500.0,
+			Low:    41800.0,
+			Close:  42200.0,
+			Volume: 1000.0,
+		})
+		suite.Require().NoError(err)
+
+		// The file is readable mid-stream and holds every flushed row
+		switch written := i + 1; {
+		case written == 999:
+			_, statErr := os.Stat(writer.GetOutputPath())
+			suite.True(os.IsNotExist(statErr), "nothing is flushed before the threshold")
+		case written%1000 == 0:
+			suite.Equal(written, suite.countRows(writer.GetOutputPath()))
+		case written%1000 == 500 && written > 1000:
+			suite.Equal(written-500, suite.countRows(writer.GetOutputPath()))
+		}
+	}
+
+	suite.Require().NoError(writer.Flush())
+	suite.Equal(10000, suite.countRows(writer.GetOutputPath()))
+}
+
+func (suite *StreamingDuckDBWriterTestSuite) TestFlushByInterval() {
+	writer := NewStreamingDuckDBWriter(suite.tempDir, "binance", "flush_interval")
+	writer.SetFlushConfig(StreamingFlushConfig{FlushRows: 1000, FlushIntervalSeconds: 1})
+	suite.Require().NoError(writer.Initialize())
+	defer writer.Close()
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		suite.Require().NoError(writer.Write(types.MarketData{
+			Symbol: "BTCUSDT",
+			Time:   baseTime.Add(time.Duration(i) * time.Minute),
+			Close:  42200.0,
+		}))
+	}
+
+	suite.Eventually(func() bool {
+		_, err := os.Stat(writer.GetOutputPath())
+
+		return err == nil
+	}, 3*time.Second, 50*time.Millisecond)
+	suite.Equal(5, suite.countRows(writer.GetOutputPath()))
+}
+
+func (suite *StreamingDuckDBWriterTestSuite) TestRestartAfterCrash() {
+	outputPath := filepath.Join(suite.tempDir, "stream_data_binance_crash.parquet")
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	bar := func(i int, close float64) types.MarketData {
+		return types.MarketData{
+			Symbol: "BTCUSDT",
+			Time:   baseTime.Add(time.Duration(i) * time.Minute),
+			Open:   close,
+			High:   close,
+			Low:    close,
+			Close:  close,
+			Volume: 1000.0,
+		}
+	}
+
+	// The first session dies with 500 rows buffered and a half-written flush
+	crashed := NewStreamingDuckDBWriter(suite.tempDir, "binance", "crash")
+	crashed.SetFlushConfig(StreamingFlushConfig{FlushRows: 1000, FlushIntervalSeconds: 0})
+	suite.Require().NoError(crashed.Initialize())
+
+	for i := 0; i < 2500; i++ {
+		suite.Require().NoError(crashed.Write(bar(i, 100)))
+	}
+
+	suite.Require().NoError(os.WriteFile(outputPath+".tmp", []byte("partial"), 0644))
+	suite.Equal(2000, suite.countRows(outputPath), "the parquet file of the crashed session is readable")
+
+	// The next session appends to the file and replaces bars it writes again
+	writer := NewStreamingDuckDBWriter(suite.tempDir, "binance", "crash")
+	writer.SetFlushConfig(StreamingFlushConfig{FlushRows: 1000, FlushIntervalSeconds: 0})
+	suite.Require().NoError(writer.Initialize())
+
+	_, statErr := os.Stat(outputPath + ".tmp")
+	suite.True(os.IsNotExist(statErr), "the leftover temporary file is removed")
+
+	for i := 1999; i < 2500; i++ {
+		suite.Require().NoError(writer.Write(bar(i, 200)))
+	}
+
+	suite.Require().NoError(writer.Close())
+	suite.Equal(2500, suite.countRows(outputPath))
+
+	db, err := sql.Open("duckdb", ":memory:")
+	suite.Require().NoError(err)
+	defer db.Close()
+
+	var close float64
+	err = db.QueryRow("SELECT close FROM read_parquet('"+outputPath+"') WHERE time = ?", baseTime.Add(1999*time.Minute)).Scan(&close)
+	suite.Require().NoError(err)
+	suite.Equal(200.0, close)
+}
@@ -207,6 +207,11 @@ const (
 	// Defaults: period=12, upperThreshold=75, lowerThreshold=25
 	// RawValue: {"psy": float64} - PSY value (0-100 scale, percent of up days)
 	IndicatorType_INDICATOR_PSY IndicatorType = 14
+	// VWAP (session Volume Weighted Average Price)
+	// Config: [timezone] - e.g., "[\"America/New_York\"]"
+	// Defaults: timezone="UTC" (sessions start at midnight in this time zone)
+	// RawValue: {"vwap": float64} - volume weighted average typical price of the session
+	IndicatorType_INDICATOR_VWAP IndicatorType = 15
 )
 
 // Enum value maps for IndicatorType.
@@ -227,6 +232,7 @@ var (
 		12: "INDICATOR_ATR",
 		13: "INDICATOR_MA",
 		14: "INDICATOR_PSY",
+		15: "INDICATOR_VWAP",
 	}
 	IndicatorType_value = map[string]int32{
 		"INDICATOR_RSI":                   0,
@@ -244,6 +250,7 @@ var (
 		"INDICATOR_ATR":                   12,
 		"INDICATOR_MA":                    13,
 		"INDICATOR_PSY":                   14,
+		"INDICATOR_VWAP":                  15,
 	}
 )
 
@@ -1439,16 +1446,18 @@ type Position struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Symbol           string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Quantity         float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	TotalInQuantity  float64                `protobuf:"fixed64,3,opt,name=total_in_quantity,json=totalInQuantity,proto3" json:"total_in_quantity,omitempty"`
-	TotalOutQuantity float64                `protobuf:"fixed64,4,opt,name=total_out_quantity,json=totalOutQuantity,proto3" json:"total_out_quantity,omitempty"`
-	TotalInAmount    float64                `protobuf:"fixed64,5,opt,name=total_in_amount,json=totalInAmount,proto3" json:"total_in_amount,omitempty"`
-	TotalOutAmount   float64                `protobuf:"fixed64,6,opt,name=total_out_amount,json=totalOutAmount,proto3" json:"total_out_amount,omitempty"`
-	TotalInFee       float64                `protobuf:"fixed64,7,opt,name=total_in_fee,json=totalInFee,proto3" json:"total_in_fee,omitempty"`
-	TotalOutFee      float64                `protobuf:"fixed64,8,opt,name=total_out_fee,json=totalOutFee,proto3" json:"total_out_fee,omitempty"`
-	OpenTimestamp    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=open_timestamp,json=openTimestamp,proto3" json:"open_timestamp,omitempty"`
-	StrategyName     string                 `protobuf:"bytes,10,opt,name=strategy_name,json=strategyName,proto3" json:"strategy_name,omitempty"`
+	Symbol            string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Quantity          float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	TotalInQuantity   float64                `protobuf:"fixed64,3,opt,name=total_in_quantity,json=totalInQuantity,proto3" json:"total_in_quantity,omitempty"`
+	TotalOutQuantity  float64                `protobuf:"fixed64,4,opt,name=total_out_quantity,json=totalOutQuantity,proto3" json:"total_out_quantity,omitempty"`
+	TotalInAmount     float64                `protobuf:"fixed64,5,opt,name=total_in_amount,json=totalInAmount,proto3" json:"total_in_amount,omitempty"`
+	TotalOutAmount    float64                `protobuf:"fixed64,6,opt,name=total_out_amount,json=totalOutAmount,proto3" json:"total_out_amount,omitempty"`
+	TotalInFee        float64                `protobuf:"fixed64,7,opt,name=total_in_fee,json=totalInFee,proto3" json:"total_in_fee,omitempty"`
+	TotalOutFee       float64                `protobuf:"fixed64,8,opt,name=total_out_fee,json=totalOutFee,proto3" json:"total_out_fee,omitempty"`
+	OpenTimestamp     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=open_timestamp,json=openTimestamp,proto3" json:"open_timestamp,omitempty"`
+	StrategyName      string                 `protobuf:"bytes,10,opt,name=strategy_name,json=strategyName,proto3" json:"strategy_name,omitempty"`
+	AverageEntryPrice float64                `protobuf:"fixed64,11,opt,name=average_entry_price,json=averageEntryPrice,proto3" json:"average_entry_price,omitempty"` // Average price of the open lots weighted by quantity
+	TotalCost         float64                `protobuf:"fixed64,12,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`                           // Entry cost of the open lots
 }
 
 func (x *Position) ProtoReflect() protoreflect.Message {
@@ -1525,6 +1534,20 @@ func (x *Position) GetStrategyName() string {
 	return ""
 }
 
+func (x *Position) GetAverageEntryPrice() float64 {
+	if x != nil {
+		return x.AverageEntryPrice
+	}
+	return 0
+}
+
+func (x *Position) GetTotalCost() float64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
 type MarkRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1896,6 +1919,8 @@ type TradeRecord struct {
 	StrategyName  string                 `protobuf:"bytes,10,opt,name=strategy_name,json=strategyName,proto3" json:"strategy_name,omitempty"`
 	Reason        *Reason                `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
 	CumulativePnl float64                `protobuf:"fixed64,12,opt,name=cumulative_pnl,json=cumulativePnl,proto3" json:"cumulative_pnl,omitempty"`
+	RealizedPnl   float64                `protobuf:"fixed64,13,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	CostBasis     float64                `protobuf:"fixed64,14,opt,name=cost_basis,json=costBasis,proto3" json:"cost_basis,omitempty"`
 }
 
 func (x *TradeRecord) ProtoReflect() protoreflect.Message {
@@ -1986,6 +2011,20 @@ func (x *TradeRecord) GetCumulativePnl() float64 {
 	return 0
 }
 
+func (x *TradeRecord) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *TradeRecord) GetCostBasis() float64 {
+	if x != nil {
+		return x.CostBasis
+	}
+	return 0
+}
+
 // LogRequest contains a log message from the strategy
 type LogRequest struct {
 	state         protoimpl.MessageState
@@ -2022,6 +2061,151 @@ func (x *LogRequest) GetFields() map[string]string {
 	return nil
 }
 
+// GetCurrentTimeResponse contains the time of the market data being processed
+type GetCurrentTimeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *GetCurrentTimeResponse) ProtoReflect() protoreflect.Message {
+	panic(`not implemented`)
+}
+
+func (x *GetCurrentTimeResponse) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// GetIntervalResponse contains the interval of the market data, such as "1m"
+type GetIntervalResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Interval string `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+}
+
+func (x *GetIntervalResponse) ProtoReflect() protoreflect.Message {
+	panic(`not implemented`)
+}
+
+func (x *GetIntervalResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+// GetRecentCandlesRequest asks for the last n candles of a symbol
+type GetRecentCandlesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	N      int32  `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
+}
+
+func (x *GetRecentCandlesRequest) ProtoReflect() protoreflect.Message {
+	panic(`not implemented`)
+}
+
+func (x *GetRecentCandlesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetRecentCandlesRequest) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+// GetRecentCandlesResponse contains the candles in chronological order, ending with the current one
+type GetRecentCandlesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []*MarketData `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *GetRecentCandlesResponse) ProtoReflect() protoreflect.Message {
+	panic(`not implemented`)
+}
+
+func (x *GetRecentCandlesResponse) GetData() []*MarketData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// GetCandlesForIntervalRequest asks for the last n complete candles of a symbol
+// aggregated to a higher timeframe interval, such as "1h"
+type GetCandlesForIntervalRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol   string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval string `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	N        int32  `protobuf:"varint,3,opt,name=n,proto3" json:"n,omitempty"`
+}
+
+func (x *GetCandlesForIntervalRequest) ProtoReflect() protoreflect.Message {
+	panic(`not implemented`)
+}
+
+func (x *GetCandlesForIntervalRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetCandlesForIntervalRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetCandlesForIntervalRequest) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+// GetCandlesForIntervalResponse contains the aggregated candles in chronological order
+type GetCandlesForIntervalResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []*MarketData `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *GetCandlesForIntervalResponse) ProtoReflect() protoreflect.Message {
+	panic(`not implemented`)
+}
+
+func (x *GetCandlesForIntervalResponse) GetData() []*MarketData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 // TradingStrategy defines the interface for trading strategies
 // go:plugin type=plugin version=1
 type TradingStrategy interface {
@@ -2047,6 +2231,8 @@ type StrategyApi interface {
 	ReadLastData(context.Context, *ReadLastDataRequest) (*MarketData, error)
 	ExecuteSQL(context.Context, *ExecuteSQLRequest) (*ExecuteSQLResponse, error)
 	Count(context.Context, *CountRequest) (*CountResponse, error)
+	GetRecentCandles(context.Context, *GetRecentCandlesRequest) (*GetRecentCandlesResponse, error)
+	GetCandlesForInterval(context.Context, *GetCandlesForIntervalRequest) (*GetCandlesForIntervalResponse, error)
 	// Indicator methods
 	ConfigureIndicator(context.Context, *ConfigureRequest) (*emptypb.Empty, error)
 	GetSignal(context.Context, *GetSignalRequest) (*GetSignalResponse, error)
@@ -2069,4 +2255,7 @@ type StrategyApi interface {
 	GetMarkers(context.Context, *emptypb.Empty) (*GetMarkersResponse, error)
 	// Logging methods
 	Log(context.Context, *LogRequest) (*emptypb.Empty, error)
+	// Engine methods
+	GetCurrentTime(context.Context, *emptypb.Empty) (*GetCurrentTimeResponse, error)
+	GetInterval(context.Context, *emptypb.Empty) (*GetIntervalResponse, error)
 }
@@ -270,6 +270,52 @@ func (h strategyApi) Count(ctx context.Context, request *CountRequest) (*CountRe
 	return response, nil
 }
 
+//go:wasmimport env get_recent_candles
+func _get_recent_candles(ptr uint32, size uint32) uint64
+
+func (h strategyApi) GetRecentCandles(ctx context.Context, request *GetRecentCandlesRequest) (*GetRecentCandlesResponse, error) {
+	buf, err := request.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+	ptr, size := wasm.ByteToPtr(buf)
+	ptrSize := _get_recent_candles(ptr, size)
+	wasm.Free(ptr)
+
+	ptr = uint32(ptrSize >> 32)
+	size = uint32(ptrSize)
+	buf = wasm.PtrToByte(ptr, size)
+
+	response := new(GetRecentCandlesResponse)
+	if err = response.UnmarshalVT(buf); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+//go:wasmimport env get_candles_for_interval
+func _get_candles_for_interval(ptr uint32, size uint32) uint64
+
+func (h strategyApi) GetCandlesForInterval(ctx context.Context, request *GetCandlesForIntervalRequest) (*GetCandlesForIntervalResponse, error) {
+	buf, err := request.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+	ptr, size := wasm.ByteToPtr(buf)
+	ptrSize := _get_candles_for_interval(ptr, size)
+	wasm.Free(ptr)
+
+	ptr = uint32(ptrSize >> 32)
+	size = uint32(ptrSize)
+	buf = wasm.PtrToByte(ptr, size)
+
+	response := new(GetCandlesForIntervalResponse)
+	if err = response.UnmarshalVT(buf); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 //go:wasmimport env configure_indicator
 func _configure_indicator(ptr uint32, size uint32) uint64
 
@@ -660,3 +706,49 @@ func (h strategyApi) Log(ctx context.Context, request *LogRequest) (*emptypb.Emp
 	}
 	return response, nil
 }
+
+//go:wasmimport env get_current_time
+func _get_current_time(ptr uint32, size uint32) uint64
+
+func (h strategyApi) GetCurrentTime(ctx context.Context, request *emptypb.Empty) (*GetCurrentTimeResponse, error) {
+	buf, err := request.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+	ptr, size := wasm.ByteToPtr(buf)
+	ptrSize := _get_current_time(ptr, size)
+	wasm.Free(ptr)
+
+	ptr = uint32(ptrSize >> 32)
+	size = uint32(ptrSize)
+	buf = wasm.PtrToByte(ptr, size)
+
+	response := new(GetCurrentTimeResponse)
+	if err = response.UnmarshalVT(buf); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+//go:wasmimport env get_interval
+func _get_interval(ptr uint32, size uint32) uint64
+
+func (h strategyApi) GetInterval(ctx context.Context, request *emptypb.Empty) (*GetIntervalResponse, error) {
+	buf, err := request.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+	ptr, size := wasm.ByteToPtr(buf)
+	ptrSize := _get_interval(ptr, size)
+	wasm.Free(ptr)
+
+	ptr = uint32(ptrSize >> 32)
+	size = uint32(ptrSize)
+	buf = wasm.PtrToByte(ptr, size)
+
+	response := new(GetIntervalResponse)
+	if err = response.UnmarshalVT(buf); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
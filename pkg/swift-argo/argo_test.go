@@ -63,11 +63,17 @@ func TestGetBacktestEngineConfigSchema_EnumFields(t *testing.T) {
 	require.True(t, ok, "schema should have broker property")
 	brokerEnum, ok := broker["enum"].([]interface{})
 	require.True(t, ok, "broker should have enum")
-	assert.Len(t, brokerEnum, 3)
-	brokerValues := []string{brokerEnum[0].(string), brokerEnum[1].(string), brokerEnum[2].(string)}
+	assert.Len(t, brokerEnum, 6)
+	brokerValues := make([]string, 0, len(brokerEnum))
+	for _, value := range brokerEnum {
+		brokerValues = append(brokerValues, value.(string))
+	}
 	assert.Contains(t, brokerValues, "interactive_broker")
 	assert.Contains(t, brokerValues, "zero_commission")
 	assert.Contains(t, brokerValues, "binance")
+	assert.Contains(t, brokerValues, "percentage")
+	assert.Contains(t, brokerValues, "tiered")
+	assert.Contains(t, brokerValues, "maker_taker")
 
 	// Check portfolio_calculation field has enum
 	portfolioCalc, ok := properties["portfolio_calculation"].(map[string]interface{})
@@ -37,9 +37,13 @@ type TradingEngineHelper interface {
 	// orderJSON is the JSON representation of the ExecuteOrder.
 	OnOrderPlaced(orderJSON string) error
 
-	// OnOrderFilled is called when an order is filled.
-	// orderJSON is the JSON representation of the filled Order.
-	OnOrderFilled(orderJSON string) error
+	// OnOrderFilled is called when an order placed by the strategy is filled.
+	// tradeJSON is the JSON representation of the Trade that filled it.
+	OnOrderFilled(tradeJSON string) error
+
+	// OnOrderRejected is called when the trading provider rejects an order placed by the strategy.
+	// orderJSON is the JSON representation of the rejected ExecuteOrder and reason is the provider's explanation.
+	OnOrderRejected(orderJSON string, reason string) error
 
 	// OnError is called when a non-fatal error occurs.
 	OnError(err error)
@@ -48,7 +52,9 @@ type TradingEngineHelper interface {
 	OnStrategyError(symbol string, timestamp int64, err error)
 
 	// OnStatusUpdate is called when the engine status changes.
-	// status is one of "prefetching", "gap_filling", "running", "stopped".
+	// status is one of "prefetching", "gap_filling", "warming_up", "running", "paused", "halted", "stopped",
+	// or, in a validate-only run, "config_validated", "strategy_validated",
+	// "connection_validated" and "symbols_validated".
 	OnStatusUpdate(status string) error
 
 	// OnPrefetchProgress is called during historical data prefetch and gap-fill downloads.
@@ -175,6 +181,8 @@ func GetSupportedMarketDataProviders() StringCollection {
 	return &StringArray{items: []string{
 		string(provider.ProviderBinance),
 		string(provider.ProviderPolygon),
+		string(provider.ProviderCoinbase),
+		string(provider.ProviderAlpaca),
 	}}
 }
 
@@ -355,6 +363,18 @@ func (t *TradingEngine) Cancel() bool {
 	return false
 }
 
+// Pause stops the strategy from trading without cancelling the run; market
+// data keeps streaming. This method is safe to call from any goroutine.
+func (t *TradingEngine) Pause() {
+	t.engine.Pause()
+}
+
+// Resume lets the strategy trade again after Pause.
+// This method is safe to call from any goroutine.
+func (t *TradingEngine) Resume() {
+	t.engine.Resume()
+}
+
 // createCallbacks creates engine.LiveTradingCallbacks from the helper interface.
 func (t *TradingEngine) createCallbacks() engine.LiveTradingCallbacks {
 	var callbacks engine.LiveTradingCallbacks
@@ -404,15 +424,26 @@ func (t *TradingEngine) createCallbacks() engine.LiveTradingCallbacks {
 	callbacks.OnOrderPlaced = &onOrderPlaced
 
 	// OnOrderFilled callback
-	onOrderFilled := engine.OnOrderFilledCallback(func(order types.Order) error {
+	onOrderFilled := engine.OnOrderFilledCallback(func(trade types.Trade) error {
+		tradeJSON, err := json.Marshal(trade)
+		if err != nil {
+			return fmt.Errorf("failed to marshal trade: %w", err)
+		}
+
+		return t.helper.OnOrderFilled(string(tradeJSON))
+	})
+	callbacks.OnOrderFilled = &onOrderFilled
+
+	// OnOrderRejected callback
+	onOrderRejected := engine.OnOrderRejectedCallback(func(order types.ExecuteOrder, reason string) error {
 		orderJSON, err := json.Marshal(order)
 		if err != nil {
 			return fmt.Errorf("failed to marshal order: %w", err)
 		}
 
-		return t.helper.OnOrderFilled(string(orderJSON))
+		return t.helper.OnOrderRejected(string(orderJSON), reason)
 	})
-	callbacks.OnOrderFilled = &onOrderFilled
+	callbacks.OnOrderRejected = &onOrderRejected
 
 	// OnError callback
 	onError := engine.OnErrorCallback(func(err error) {
@@ -24,6 +24,7 @@ type mockTradingHelper struct {
 	marketDataCalls       int
 	orderPlacedCalls      int
 	orderFilledCalls      int
+	orderRejectedCalls    int
 	errorCalls            int
 	strategyErrors        int
 	statusUpdates         []string
@@ -88,6 +89,13 @@ func (m *mockTradingHelper) OnOrderFilled(orderJSON string) error {
 	return nil
 }
 
+func (m *mockTradingHelper) OnOrderRejected(orderJSON string, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orderRejectedCalls++
+	return nil
+}
+
 func (m *mockTradingHelper) OnError(err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -518,6 +526,7 @@ func (suite *TradingTestSuite) TestCreateCallbacks_NilHelper() {
 	suite.Nil(callbacks.OnMarketData)
 	suite.Nil(callbacks.OnOrderPlaced)
 	suite.Nil(callbacks.OnOrderFilled)
+	suite.Nil(callbacks.OnOrderRejected)
 	suite.Nil(callbacks.OnError)
 	suite.Nil(callbacks.OnStrategyError)
 }
@@ -536,6 +545,7 @@ func (suite *TradingTestSuite) TestCreateCallbacks_ValidHelper() {
 	suite.NotNil(callbacks.OnMarketData)
 	suite.NotNil(callbacks.OnOrderPlaced)
 	suite.NotNil(callbacks.OnOrderFilled)
+	suite.NotNil(callbacks.OnOrderRejected)
 	suite.NotNil(callbacks.OnError)
 	suite.NotNil(callbacks.OnStrategyError)
 }